@@ -40,6 +40,7 @@ import (
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/messaging/kafka"
 	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/service"
 	"github.com/uber/cadence/service/frontend"
 	"github.com/uber/cadence/service/history"
@@ -209,6 +210,9 @@ func (s *server) startService() common.Daemon {
 	params.ArchiverProvider = provider.NewArchiverProvider(s.cfg.Archival.History.Provider, s.cfg.Archival.Visibility.Provider)
 	params.PersistenceConfig.TransactionSizeLimit = dc.GetIntProperty(dynamicconfig.TransactionSizeLimit, common.DefaultTransactionSizeLimit)
 	params.PersistenceConfig.ErrorInjectionRate = dc.GetFloat64Property(dynamicconfig.PersistenceErrorInjectionRate, 0)
+	params.PersistenceConfig.ShardThrashingThreshold = dc.GetIntProperty(dynamicconfig.ShardThrashingThreshold, 0)
+	params.PersistenceConfig.MinDomainRetentionDays = dc.GetIntProperty(dynamicconfig.MinRetentionDays, persistence.DefaultMinDomainRetentionDays)
+	params.PersistenceConfig.MaxDomainRetentionDays = dc.GetIntProperty(dynamicconfig.MaxRetentionDays, persistence.DefaultMaxDomainRetentionDays)
 	params.Authorizer = authorization.NewNopAuthorizer()
 	params.BlobstoreClient, err = filestore.NewFilestoreClient(s.cfg.Blobstore.Filestore)
 	if err != nil {
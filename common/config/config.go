@@ -144,6 +144,22 @@ type (
 		TransactionSizeLimit dynamicconfig.IntPropertyFn `yaml:"-" json:"-"`
 		// ErrorInjectionRate is the the rate for injecting random error
 		ErrorInjectionRate dynamicconfig.FloatPropertyFn `yaml:"-" json:"-"`
+		// ShardThrashingThreshold is the shard steal count from which the shard manager logs a shard as thrashing
+		ShardThrashingThreshold dynamicconfig.IntPropertyFn `yaml:"-" json:"-"`
+		// MinDomainRetentionDays and MaxDomainRetentionDays bound the retention of the domains written
+		// by the metadata manager, the retention is not validated if they are not set
+		MinDomainRetentionDays dynamicconfig.IntPropertyFn `yaml:"-" json:"-"`
+		MaxDomainRetentionDays dynamicconfig.IntPropertyFn `yaml:"-" json:"-"`
+		// CircuitBreaker, if set, makes the calls to a datastore fail fast while it keeps failing
+		CircuitBreaker *PersistenceCircuitBreaker `yaml:"circuitBreaker"`
+	}
+
+	// PersistenceCircuitBreaker is the config of the circuit breaker of each datastore
+	PersistenceCircuitBreaker struct {
+		// FailureThreshold is the number of consecutive backend failures, e.g. timeouts, which opens the circuit
+		FailureThreshold int `yaml:"failureThreshold" validate:"nonzero"`
+		// OpenDuration is how long the circuit stays open before a probe call is let through
+		OpenDuration time.Duration `yaml:"openDuration" validate:"nonzero"`
 	}
 
 	// DataStore is the configuration for a single datastore
@@ -188,6 +204,8 @@ type (
 		Password string `yaml:"password"`
 		// Keyspace is the cassandra keyspace
 		Keyspace string `yaml:"keyspace" validate:"nonzero"`
+		// KeyspaceOverrides allows individual stores to use a keyspace other than Keyspace
+		KeyspaceOverrides CassandraKeyspaceOverrides `yaml:"keyspaceOverrides"`
 		// Region is the region filter arg for cassandra
 		Region string `yaml:"region"`
 		// Datacenter is the data center filter arg for cassandra
@@ -196,10 +214,52 @@ type (
 		MaxConns int `yaml:"maxConns"`
 		// TLS configuration
 		TLS *auth.TLS `yaml:"tls"`
+		// Consistency is the default consistency level for queries, e.g. LOCAL_QUORUM or EACH_QUORUM
+		// LOCAL_QUORUM is used if not specified
+		Consistency string `yaml:"consistency"`
+		// SerialConsistency is the serial consistency level for conditional updates, e.g. LOCAL_SERIAL or SERIAL
+		// LOCAL_SERIAL is used if not specified
+		SerialConsistency string `yaml:"serialConsistency"`
+		// HostSelectionPolicy is the policy used to route queries to hosts, e.g. TokenAwareDCAware or RoundRobin
+		// TokenAwareDCAware is used if not specified, preferring hosts in the configured Datacenter
+		HostSelectionPolicy string `yaml:"hostSelectionPolicy"`
+		// EnablePoolMetrics enables periodic emission of the connection pool metrics of the cassandra session,
+		// including the failures to reload the TLS client certificate
+		EnablePoolMetrics bool `yaml:"enablePoolMetrics"`
+		// ScanQueryTimeout is the timeout of each page fetched by iterator-style queries, e.g. when scanning all history branches
+		// Point operations always use the default timeout of 10s, which is also used for scans if not specified
+		ScanQueryTimeout time.Duration `yaml:"scanQueryTimeout"`
+		// RetryPolicy is the policy used to retry queries failing with unavailable replicas or a coordinator timeout,
+		// write timeouts are only retried for idempotent queries.
+		// Queries are retried once on another host with a backoff of 100ms if not specified
+		RetryPolicy *CassandraRetryPolicy `yaml:"retryPolicy"`
+		// DisableIdempotentQueries stops marking any query as idempotent, so no write is retried after a write timeout,
+		// e.g. to rule it out while investigating duplicated writes. Only unconditional writes which are safe to apply
+		// twice are marked in the first place
+		DisableIdempotentQueries bool `yaml:"disableIdempotentQueries"`
 		// CQLClient specifies a custom CQL client implementation, can not be specified through yaml
 		CQLClient gocql.Client `yaml:"-" json:"-"`
 	}
 
+	// CassandraRetryPolicy is the config of the retry policy of cassandra queries
+	CassandraRetryPolicy struct {
+		// NumRetries is the max number of retries of a query, zero disables retries
+		NumRetries int `yaml:"numRetries"`
+		// MinBackoff is the backoff before the first retry, it is doubled for each further retry
+		MinBackoff time.Duration `yaml:"minBackoff"`
+		// MaxBackoff caps the backoff between retries
+		MaxBackoff time.Duration `yaml:"maxBackoff"`
+	}
+
+	// CassandraKeyspaceOverrides is the config of the keyspaces used by individual stores,
+	// the Keyspace of the datastore is used by stores without an override
+	CassandraKeyspaceOverrides struct {
+		// History is the keyspace of the history tree and node tables used by the HistoryManager
+		History string `yaml:"history"`
+		// Execution is the keyspace of the executions table used by the ExecutionManager and ShardManager
+		Execution string `yaml:"execution"`
+	}
+
 	// SQL is the configuration for connecting to a SQL backed datastore
 	SQL struct {
 		// User is the username to be used for the conn
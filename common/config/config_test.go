@@ -62,3 +62,14 @@ func TestFillingDefaultRpcName(t *testing.T) {
 	cfg.fillDefaults()
 	assert.Equal(t, "cadence-frontend", cfg.ClusterMetadata.ClusterInformation["clusterA"].RPCName)
 }
+
+func TestCassandraKeyspaceOverrides(t *testing.T) {
+	cfg := &Cassandra{Keyspace: "cadence"}
+	assert.Equal(t, "cadence", cfg.HistoryStoreConfig().Keyspace)
+	assert.Equal(t, "cadence", cfg.ExecutionStoreConfig().Keyspace)
+
+	cfg.KeyspaceOverrides.History = "cadence_history"
+	assert.Equal(t, "cadence_history", cfg.HistoryStoreConfig().Keyspace)
+	assert.Equal(t, "cadence", cfg.ExecutionStoreConfig().Keyspace)
+	assert.Equal(t, "cadence", cfg.Keyspace)
+}
@@ -62,3 +62,21 @@ func (c *Persistence) Validate() error {
 func (c *Persistence) IsAdvancedVisibilityConfigExist() bool {
 	return len(c.AdvancedVisibilityStore) != 0
 }
+
+// HistoryStoreConfig returns the config used by the history store, with Keyspace set to its override if any
+func (c *Cassandra) HistoryStoreConfig() Cassandra {
+	return c.withKeyspaceOverride(c.KeyspaceOverrides.History)
+}
+
+// ExecutionStoreConfig returns the config used by the execution and shard stores, with Keyspace set to its override if any
+func (c *Cassandra) ExecutionStoreConfig() Cassandra {
+	return c.withKeyspaceOverride(c.KeyspaceOverrides.Execution)
+}
+
+func (c *Cassandra) withKeyspaceOverride(keyspace string) Cassandra {
+	cfg := *c
+	if keyspace != "" {
+		cfg.Keyspace = keyspace
+	}
+	return cfg
+}
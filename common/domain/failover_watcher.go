@@ -205,6 +205,7 @@ func CleanPendingActiveState(
 			FailoverNotificationVersion: getResponse.FailoverNotificationVersion,
 			FailoverEndTime:             nil,
 			NotificationVersion:         notificationVersion,
+			DeletionTime:                getResponse.DeletionTime,
 		}
 		op := func() error {
 			return metadataMgr.UpdateDomain(context.Background(), updateReq)
@@ -532,6 +532,7 @@ func (d *handlerImpl) UpdateDomain(
 			PreviousFailoverVersion:     previousFailoverVersion,
 			LastUpdatedTime:             lastUpdatedTime.UnixNano(),
 			NotificationVersion:         notificationVersion,
+			DeletionTime:                getResponse.DeletionTime,
 		}
 		err = d.metadataMgr.UpdateDomain(ctx, updateReq)
 		if err != nil {
@@ -606,6 +607,7 @@ func (d *handlerImpl) DeprecateDomain(
 		PreviousFailoverVersion:     getResponse.PreviousFailoverVersion,
 		LastUpdatedTime:             d.timeSource.Now().UnixNano(),
 		NotificationVersion:         notificationVersion,
+		DeletionTime:                getResponse.DeletionTime,
 	}
 	err = d.metadataMgr.UpdateDomain(ctx, updateReq)
 	if err != nil {
@@ -136,6 +136,8 @@ func (h *domainReplicationTaskExecutorImpl) handleDomainCreationReplicationTask(
 		ConfigVersion:   task.GetConfigVersion(),
 		FailoverVersion: task.GetFailoverVersion(),
 		LastUpdatedTime: h.timeSource.Now().UnixNano(),
+		// retention has already been validated by the source cluster
+		SkipRetentionValidation: true,
 	}
 
 	_, err = h.metadataManager.CreateDomain(ctx, request)
@@ -227,6 +229,7 @@ func (h *domainReplicationTaskExecutorImpl) handleDomainUpdateReplicationTask(ct
 		PreviousFailoverVersion:     resp.PreviousFailoverVersion,
 		NotificationVersion:         notificationVersion,
 		LastUpdatedTime:             h.timeSource.Now().UnixNano(),
+		DeletionTime:                resp.DeletionTime,
 	}
 
 	if resp.ConfigVersion < task.GetConfigVersion() {
@@ -265,6 +268,8 @@ func (h *domainReplicationTaskExecutorImpl) handleDomainUpdateReplicationTask(ct
 		return nil
 	}
 
+	// retention has already been validated by the source cluster
+	request.SkipRetentionValidation = true
 	return h.metadataManager.UpdateDomain(ctx, request)
 }
 
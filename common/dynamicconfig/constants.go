@@ -74,6 +74,7 @@ var keys = map[Key]string{
 	EnableGracefulFailover:              "system.enableGracefulFailover",
 	TransactionSizeLimit:                "system.transactionSizeLimit",
 	PersistenceErrorInjectionRate:       "system.persistenceErrorInjectionRate",
+	ShardThrashingThreshold:             "system.shardThrashingThreshold",
 	MaxRetentionDays:                    "system.maxRetentionDays",
 	MinRetentionDays:                    "system.minRetentionDays",
 	MaxDecisionStartToCloseSeconds:      "system.maxDecisionStartToCloseSeconds",
@@ -414,6 +415,9 @@ const (
 	TransactionSizeLimit
 	// PersistenceErrorInjectionRate is the rate for injecting random error in persistence
 	PersistenceErrorInjectionRate
+	// ShardThrashingThreshold is the number of times a shard can be stolen since its last renewal
+	// before the shard manager logs it as thrashing, 0 disables the check
+	ShardThrashingThreshold
 	// MaxRetentionDays is the maximum retention allowed when registering a domain
 	// !!! Do NOT simply decrease this number, because it is being used by history scavenger to avoid race condition against history archival.
 	//	Check more details in history scanner(scavenger)
@@ -175,34 +175,51 @@ var (
 var (
 	StoreOperationCreateShard = storeOperation("create-shard")
 	StoreOperationGetShard    = storeOperation("get-shard")
+	StoreOperationGetShards   = storeOperation("get-shards")
+	StoreOperationListShards  = storeOperation("list-shards")
 	StoreOperationUpdateShard = storeOperation("update-shard")
 
 	StoreOperationCreateWorkflowExecution           = storeOperation("create-wf-execution")
 	StoreOperationGetWorkflowExecution              = storeOperation("get-wf-execution")
+	StoreOperationGetWorkflowExecutionForUpdate     = storeOperation("get-wf-execution-for-update")
 	StoreOperationUpdateWorkflowExecution           = storeOperation("update-wf-execution")
+	StoreOperationFlushBufferedEvents               = storeOperation("flush-buffered-events")
 	StoreOperationConflictResolveWorkflowExecution  = storeOperation("conflict-resolve-wf-execution")
 	StoreOperationResetWorkflowExecution            = storeOperation("reset-wf-execution")
 	StoreOperationDeleteWorkflowExecution           = storeOperation("delete-wf-execution")
 	StoreOperationDeleteCurrentWorkflowExecution    = storeOperation("delete-current-wf-execution")
+	StoreOperationDeleteWorkflowExecutions          = storeOperation("delete-wf-executions")
 	StoreOperationGetCurrentExecution               = storeOperation("get-current-execution")
+	StoreOperationGetCurrentRunID                   = storeOperation("get-current-run-id")
 	StoreOperationListCurrentExecution              = storeOperation("list-current-execution")
 	StoreOperationIsWorkflowExecutionExists         = storeOperation("is-wf-execution-exists")
+	StoreOperationWorkflowExists                    = storeOperation("wf-exists")
+	StoreOperationCheckCurrentExecution             = storeOperation("check-current-execution")
 	StoreOperationListConcreteExecution             = storeOperation("list-concrete-execution")
+	StoreOperationCountExecutions                   = storeOperation("count-executions")
 	StoreOperationGetTransferTasks                  = storeOperation("get-transfer-tasks")
 	StoreOperationGetReplicationTasks               = storeOperation("get-replication-tasks")
+	StoreOperationGetFailoverMarkerTasks            = storeOperation("get-failover-marker-tasks")
 	StoreOperationCompleteTransferTask              = storeOperation("complete-transfer-task")
 	StoreOperationRangeCompleteTransferTask         = storeOperation("range-complete-transfer-task")
+	StoreOperationCompleteTransferTasks             = storeOperation("complete-transfer-tasks")
+	StoreOperationGetCrossClusterTasks              = storeOperation("get-cross-cluster-tasks")
+	StoreOperationCompleteCrossClusterTask          = storeOperation("complete-cross-cluster-task")
+	StoreOperationRangeCompleteCrossClusterTask     = storeOperation("range-complete-cross-cluster-task")
 	StoreOperationCompleteReplicationTask           = storeOperation("complete-replication-task")
 	StoreOperationRangeCompleteReplicationTask      = storeOperation("range-complete-replication-task")
 	StoreOperationPutReplicationTaskToDLQ           = storeOperation("put-replication-task-to-dlq")
+	StoreOperationPutReplicationTasksToDLQ          = storeOperation("put-replication-tasks-to-dlq")
 	StoreOperationGetReplicationTasksFromDLQ        = storeOperation("get-replication-tasks-from-dlq")
 	StoreOperationGetReplicationDLQSize             = storeOperation("get-replication-dlq-size")
 	StoreOperationDeleteReplicationTaskFromDLQ      = storeOperation("delete-replication-task-from-dlq")
 	StoreOperationRangeDeleteReplicationTaskFromDLQ = storeOperation("range-delete-replication-task-from-dlq")
+	StoreOperationDeleteReplicationTasksFromDLQ     = storeOperation("delete-replication-tasks-from-dlq")
 	StoreOperationCreateFailoverMarkerTasks         = storeOperation("createFailoverMarkerTasks")
 	StoreOperationGetTimerIndexTasks                = storeOperation("get-timer-index-tasks")
 	StoreOperationCompleteTimerTask                 = storeOperation("complete-timer-task")
 	StoreOperationRangeCompleteTimerTask            = storeOperation("range-complete-timer-task")
+	StoreOperationCompleteTimerTasksForDomain       = storeOperation("complete-timer-tasks-for-domain")
 
 	StoreOperationCreateTasks           = storeOperation("create-tasks")
 	StoreOperationGetTasks              = storeOperation("get-tasks")
@@ -211,16 +228,19 @@ var (
 	StoreOperationLeaseTaskList         = storeOperation("lease-task-list")
 	StoreOperationUpdateTaskList        = storeOperation("update-task-list")
 	StoreOperationListTaskList          = storeOperation("list-task-list")
+	StoreOperationListTaskListByDomain  = storeOperation("list-task-list-by-domain")
 	StoreOperationDeleteTaskList        = storeOperation("delete-task-list")
 	StoreOperationStopTaskList          = storeOperation("stop-task-list")
 
-	StoreOperationCreateDomain       = storeOperation("create-domain")
-	StoreOperationGetDomain          = storeOperation("get-domain")
-	StoreOperationUpdateDomain       = storeOperation("update-domain")
-	StoreOperationDeleteDomain       = storeOperation("delete-domain")
-	StoreOperationDeleteDomainByName = storeOperation("delete-domain-by-name")
-	StoreOperationListDomains        = storeOperation("list-domains")
-	StoreOperationGetMetadata        = storeOperation("get-metadata")
+	StoreOperationCreateDomain           = storeOperation("create-domain")
+	StoreOperationGetDomain              = storeOperation("get-domain")
+	StoreOperationGetDomainConfigVersion = storeOperation("get-domain-config-version")
+	StoreOperationUpdateDomain           = storeOperation("update-domain")
+	StoreOperationMarkDomainForDeletion  = storeOperation("mark-domain-for-deletion")
+	StoreOperationDeleteDomain           = storeOperation("delete-domain")
+	StoreOperationDeleteDomainByName     = storeOperation("delete-domain-by-name")
+	StoreOperationListDomains            = storeOperation("list-domains")
+	StoreOperationGetMetadata            = storeOperation("get-metadata")
 
 	StoreOperationRecordWorkflowExecutionStarted           = storeOperation("record-wf-execution-started")
 	StoreOperationRecordWorkflowExecutionClosed            = storeOperation("record-wf-execution-closed")
@@ -238,16 +258,20 @@ var (
 	StoreOperationScanWorkflowExecutions                   = storeOperation("scan-wf-executions")
 	StoreOperationCountWorkflowExecutions                  = storeOperation("count-wf-executions")
 
-	StoreOperationAppendHistoryNodes        = storeOperation("append-history-nodes")
-	StoreOperationReadHistoryBranch         = storeOperation("read-history-branch")
-	StoreOperationReadHistoryBranchByBatch  = storeOperation("read-history-branch-by-batch")
-	StoreOperationReadRawHistoryBranch      = storeOperation("read-raw-history-branch")
-	StoreOperationForkHistoryBranch         = storeOperation("fork-history-branch")
-	StoreOperationDeleteHistoryBranch       = storeOperation("delete-history-branch")
-	StoreOperationGetHistoryTree            = storeOperation("get-history-tree")
-	StoreOperationGetAllHistoryTreeBranches = storeOperation("get-all-history-tree-branches")
+	StoreOperationAppendHistoryNodes         = storeOperation("append-history-nodes")
+	StoreOperationReadHistoryBranch          = storeOperation("read-history-branch")
+	StoreOperationReadHistoryBranchByBatch   = storeOperation("read-history-branch-by-batch")
+	StoreOperationReadRawHistoryBranch       = storeOperation("read-raw-history-branch")
+	StoreOperationReadHistoryBranchWithBlobs = storeOperation("read-history-branch-with-blobs")
+	StoreOperationReadHistoryNode            = storeOperation("read-history-node")
+	StoreOperationForkHistoryBranch          = storeOperation("fork-history-branch")
+	StoreOperationDeleteHistoryBranch        = storeOperation("delete-history-branch")
+	StoreOperationGetHistoryTree             = storeOperation("get-history-tree")
+	StoreOperationGetAllHistoryTreeBranches  = storeOperation("get-all-history-tree-branches")
 
 	StoreOperationEnqueueMessage             = storeOperation("enqueue-message")
+	StoreOperationEnqueueMessageWithID       = storeOperation("enqueue-message-with-id")
+	StoreOperationEnqueueMessages            = storeOperation("enqueue-messages")
 	StoreOperationReadMessages               = storeOperation("read-messages")
 	StoreOperationUpdateAckLevel             = storeOperation("update-ack-level")
 	StoreOperationGetAckLevels               = storeOperation("get-ack-levels")
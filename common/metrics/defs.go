@@ -136,14 +136,22 @@ const (
 	PersistenceCreateShardScope = iota
 	// PersistenceGetShardScope tracks GetShard calls made by service to persistence layer
 	PersistenceGetShardScope
+	// PersistenceGetShardsScope tracks GetShards calls made by service to persistence layer
+	PersistenceGetShardsScope
+	// PersistenceListShardsScope tracks ListShards calls made by service to persistence layer
+	PersistenceListShardsScope
 	// PersistenceUpdateShardScope tracks UpdateShard calls made by service to persistence layer
 	PersistenceUpdateShardScope
 	// PersistenceCreateWorkflowExecutionScope tracks CreateWorkflowExecution calls made by service to persistence layer
 	PersistenceCreateWorkflowExecutionScope
 	// PersistenceGetWorkflowExecutionScope tracks GetWorkflowExecution calls made by service to persistence layer
 	PersistenceGetWorkflowExecutionScope
+	// PersistenceGetWorkflowExecutionForUpdateScope tracks GetWorkflowExecutionForUpdate calls made by service to persistence layer
+	PersistenceGetWorkflowExecutionForUpdateScope
 	// PersistenceUpdateWorkflowExecutionScope tracks UpdateWorkflowExecution calls made by service to persistence layer
 	PersistenceUpdateWorkflowExecutionScope
+	// PersistenceFlushBufferedEventsScope tracks FlushBufferedEvents calls made by service to persistence layer
+	PersistenceFlushBufferedEventsScope
 	// PersistenceConflictResolveWorkflowExecutionScope tracks ConflictResolveWorkflowExecution calls made by service to persistence layer
 	PersistenceConflictResolveWorkflowExecutionScope
 	// PersistenceResetWorkflowExecutionScope tracks ResetWorkflowExecution calls made by service to persistence layer
@@ -152,28 +160,50 @@ const (
 	PersistenceDeleteWorkflowExecutionScope
 	// PersistenceDeleteCurrentWorkflowExecutionScope tracks DeleteCurrentWorkflowExecution calls made by service to persistence layer
 	PersistenceDeleteCurrentWorkflowExecutionScope
+	// PersistenceDeleteWorkflowExecutionsScope tracks DeleteWorkflowExecutions calls made by service to persistence layer
+	PersistenceDeleteWorkflowExecutionsScope
 	// PersistenceGetCurrentExecutionScope tracks GetCurrentExecution calls made by service to persistence layer
 	PersistenceGetCurrentExecutionScope
+	// PersistenceGetCurrentRunIDScope tracks GetCurrentRunID calls made by service to persistence layer
+	PersistenceGetCurrentRunIDScope
 	// PersistenceIsWorkflowExecutionExistsScope tracks IsWorkflowExecutionExists calls made by service to persistence layer
 	PersistenceIsWorkflowExecutionExistsScope
+	// PersistenceWorkflowExistsScope tracks WorkflowExists calls made by service to persistence layer
+	PersistenceWorkflowExistsScope
+	// PersistenceCheckCurrentExecutionScope tracks CheckCurrentExecution calls made by service to persistence layer
+	PersistenceCheckCurrentExecutionScope
 	// PersistenceListCurrentExecutionsScope tracks ListCurrentExecutions calls made by service to persistence layer
 	PersistenceListCurrentExecutionsScope
 	// PersistenceListConcreteExecutionsScope tracks ListConcreteExecutions calls made by service to persistence layer
 	PersistenceListConcreteExecutionsScope
+	// PersistenceCountExecutionsScope tracks CountWorkflowExecutions calls on the execution manager made by service to persistence layer
+	PersistenceCountExecutionsScope
 	// PersistenceGetTransferTasksScope tracks GetTransferTasks calls made by service to persistence layer
 	PersistenceGetTransferTasksScope
 	// PersistenceCompleteTransferTaskScope tracks CompleteTransferTasks calls made by service to persistence layer
 	PersistenceCompleteTransferTaskScope
 	// PersistenceRangeCompleteTransferTaskScope tracks CompleteTransferTasks calls made by service to persistence layer
 	PersistenceRangeCompleteTransferTaskScope
+	// PersistenceCompleteTransferTasksScope tracks CompleteTransferTasks calls made by service to persistence layer
+	PersistenceCompleteTransferTasksScope
+	// PersistenceGetCrossClusterTasksScope tracks GetCrossClusterTasks calls made by service to persistence layer
+	PersistenceGetCrossClusterTasksScope
+	// PersistenceCompleteCrossClusterTaskScope tracks CompleteCrossClusterTask calls made by service to persistence layer
+	PersistenceCompleteCrossClusterTaskScope
+	// PersistenceRangeCompleteCrossClusterTaskScope tracks RangeCompleteCrossClusterTask calls made by service to persistence layer
+	PersistenceRangeCompleteCrossClusterTaskScope
 	// PersistenceGetReplicationTasksScope tracks GetReplicationTasks calls made by service to persistence layer
 	PersistenceGetReplicationTasksScope
+	// PersistenceGetFailoverMarkerTasksScope tracks GetFailoverMarkerTasks calls made by service to persistence layer
+	PersistenceGetFailoverMarkerTasksScope
 	// PersistenceCompleteReplicationTaskScope tracks CompleteReplicationTasks calls made by service to persistence layer
 	PersistenceCompleteReplicationTaskScope
 	// PersistenceRangeCompleteReplicationTaskScope tracks RangeCompleteReplicationTasks calls made by service to persistence layer
 	PersistenceRangeCompleteReplicationTaskScope
 	// PersistencePutReplicationTaskToDLQScope tracks PersistencePutReplicationTaskToDLQScope calls made by service to persistence layer
 	PersistencePutReplicationTaskToDLQScope
+	// PersistencePutReplicationTasksToDLQScope tracks PersistencePutReplicationTasksToDLQScope calls made by service to persistence layer
+	PersistencePutReplicationTasksToDLQScope
 	// PersistenceGetReplicationTasksFromDLQScope tracks PersistenceGetReplicationTasksFromDLQScope calls made by service to persistence layer
 	PersistenceGetReplicationTasksFromDLQScope
 	// PersistenceGetReplicationDLQSizeScope tracks PersistenceGetReplicationDLQSizeScope calls made by service to persistence layer
@@ -182,6 +212,8 @@ const (
 	PersistenceDeleteReplicationTaskFromDLQScope
 	// PersistenceRangeDeleteReplicationTaskFromDLQScope tracks PersistenceRangeDeleteReplicationTaskFromDLQScope calls made by service to persistence layer
 	PersistenceRangeDeleteReplicationTaskFromDLQScope
+	// PersistenceDeleteReplicationTasksFromDLQScope tracks PersistenceDeleteReplicationTasksFromDLQScope calls made by service to persistence layer
+	PersistenceDeleteReplicationTasksFromDLQScope
 	// PersistenceCreateFailoverMarkerTasksScope tracks CreateFailoverMarkerTasks calls made by service to persistence layer
 	PersistenceCreateFailoverMarkerTasksScope
 	// PersistenceGetTimerIndexTasksScope tracks GetTimerIndexTasks calls made by service to persistence layer
@@ -190,6 +222,8 @@ const (
 	PersistenceCompleteTimerTaskScope
 	// PersistenceRangeCompleteTimerTaskScope tracks CompleteTimerTasks calls made by service to persistence layer
 	PersistenceRangeCompleteTimerTaskScope
+	// PersistenceCompleteTimerTasksForDomainScope tracks CompleteTimerTasksForDomain calls made by service to persistence layer
+	PersistenceCompleteTimerTasksForDomainScope
 	// PersistenceCreateTaskScope tracks CreateTask calls made by service to persistence layer
 	PersistenceCreateTaskScope
 	// PersistenceGetTasksScope tracks GetTasks calls made by service to persistence layer
@@ -206,6 +240,8 @@ const (
 	PersistenceUpdateTaskListScope
 	// PersistenceListTaskListScope is the metric scope for persistence.TaskManager.ListTaskList API
 	PersistenceListTaskListScope
+	// PersistenceListTaskListByDomainScope is the metric scope for persistence.TaskManager.ListTaskListByDomain API
+	PersistenceListTaskListByDomainScope
 	// PersistenceDeleteTaskListScope is the metric scope for persistence.TaskManager.DeleteTaskList API
 	PersistenceDeleteTaskListScope
 	// PersistenceAppendHistoryEventsScope tracks AppendHistoryEvents calls made by service to persistence layer
@@ -218,8 +254,12 @@ const (
 	PersistenceCreateDomainScope
 	// PersistenceGetDomainScope tracks GetDomain calls made by service to persistence layer
 	PersistenceGetDomainScope
+	// PersistenceGetDomainConfigVersionScope tracks GetDomainConfigVersion calls made by service to persistence layer
+	PersistenceGetDomainConfigVersionScope
 	// PersistenceUpdateDomainScope tracks UpdateDomain calls made by service to persistence layer
 	PersistenceUpdateDomainScope
+	// PersistenceMarkDomainForDeletionScope tracks MarkDomainForDeletion calls made by service to persistence layer
+	PersistenceMarkDomainForDeletionScope
 	// PersistenceDeleteDomainScope tracks DeleteDomain calls made by service to persistence layer
 	PersistenceDeleteDomainScope
 	// PersistenceDeleteDomainByNameScope tracks DeleteDomainByName calls made by service to persistence layer
@@ -260,6 +300,10 @@ const (
 	PersistenceCountWorkflowExecutionsScope
 	// PersistenceEnqueueMessageScope tracks Enqueue calls made by service to persistence layer
 	PersistenceEnqueueMessageScope
+	// PersistenceEnqueueMessageWithIDScope tracks EnqueueMessageWithID calls made by service to persistence layer
+	PersistenceEnqueueMessageWithIDScope
+	// PersistenceEnqueueMessagesScope tracks EnqueueMessages calls made by service to persistence layer
+	PersistenceEnqueueMessagesScope
 	// PersistenceEnqueueMessageToDLQScope tracks Enqueue DLQ calls made by service to persistence layer
 	PersistenceEnqueueMessageToDLQScope
 	// PersistenceReadQueueMessagesScope tracks ReadMessages calls made by service to persistence layer
@@ -597,6 +641,10 @@ const (
 	PersistenceAppendHistoryNodesScope
 	// PersistenceReadHistoryBranchScope tracks ReadHistoryBranch calls made by service to persistence layer
 	PersistenceReadHistoryBranchScope
+	// PersistenceReadHistoryBranchWithBlobsScope tracks ReadHistoryBranchWithBlobs calls made by service to persistence layer
+	PersistenceReadHistoryBranchWithBlobsScope
+	// PersistenceReadHistoryNodeScope tracks ReadHistoryNode calls made by service to persistence layer
+	PersistenceReadHistoryNodeScope
 	// PersistenceForkHistoryBranchScope tracks ForkHistoryBranch calls made by service to persistence layer
 	PersistenceForkHistoryBranchScope
 	// PersistenceDeleteHistoryBranchScope tracks DeleteHistoryBranch calls made by service to persistence layer
@@ -674,6 +722,8 @@ const (
 	DomainFailoverScope
 	// DomainReplicationQueueScope is used in domainreplication queue
 	DomainReplicationQueueScope
+	// CassandraSessionScope is used by the cassandra session to emit connection pool metrics
+	CassandraSessionScope
 
 	NumCommonScopes
 )
@@ -1094,33 +1144,50 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 	Common: {
 		PersistenceCreateShardScope:                              {operation: "CreateShard"},
 		PersistenceGetShardScope:                                 {operation: "GetShard"},
+		PersistenceGetShardsScope:                                {operation: "GetShards"},
+		PersistenceListShardsScope:                               {operation: "ListShards"},
 		PersistenceUpdateShardScope:                              {operation: "UpdateShard"},
 		PersistenceCreateWorkflowExecutionScope:                  {operation: "CreateWorkflowExecution"},
 		PersistenceGetWorkflowExecutionScope:                     {operation: "GetWorkflowExecution"},
+		PersistenceGetWorkflowExecutionForUpdateScope:            {operation: "GetWorkflowExecutionForUpdate"},
 		PersistenceUpdateWorkflowExecutionScope:                  {operation: "UpdateWorkflowExecution"},
+		PersistenceFlushBufferedEventsScope:                      {operation: "FlushBufferedEvents"},
 		PersistenceConflictResolveWorkflowExecutionScope:         {operation: "ConflictResolveWorkflowExecution"},
 		PersistenceResetWorkflowExecutionScope:                   {operation: "ResetWorkflowExecution"},
 		PersistenceDeleteWorkflowExecutionScope:                  {operation: "DeleteWorkflowExecution"},
 		PersistenceDeleteCurrentWorkflowExecutionScope:           {operation: "DeleteCurrentWorkflowExecution"},
+		PersistenceDeleteWorkflowExecutionsScope:                 {operation: "DeleteWorkflowExecutions"},
 		PersistenceGetCurrentExecutionScope:                      {operation: "GetCurrentExecution"},
+		PersistenceGetCurrentRunIDScope:                          {operation: "GetCurrentRunID"},
 		PersistenceIsWorkflowExecutionExistsScope:                {operation: "IsWorkflowExecutionExists"},
+		PersistenceWorkflowExistsScope:                           {operation: "WorkflowExists"},
+		PersistenceCheckCurrentExecutionScope:                    {operation: "CheckCurrentExecution"},
 		PersistenceListCurrentExecutionsScope:                    {operation: "ListCurrentExecutions"},
 		PersistenceListConcreteExecutionsScope:                   {operation: "ListConcreteExecutions"},
+		PersistenceCountExecutionsScope:                          {operation: "CountExecutions"},
 		PersistenceGetTransferTasksScope:                         {operation: "GetTransferTasks"},
 		PersistenceCompleteTransferTaskScope:                     {operation: "CompleteTransferTask"},
 		PersistenceRangeCompleteTransferTaskScope:                {operation: "RangeCompleteTransferTask"},
+		PersistenceCompleteTransferTasksScope:                    {operation: "CompleteTransferTasks"},
+		PersistenceGetCrossClusterTasksScope:                     {operation: "GetCrossClusterTasks"},
+		PersistenceCompleteCrossClusterTaskScope:                 {operation: "CompleteCrossClusterTask"},
+		PersistenceRangeCompleteCrossClusterTaskScope:            {operation: "RangeCompleteCrossClusterTask"},
 		PersistenceGetReplicationTasksScope:                      {operation: "GetReplicationTasks"},
+		PersistenceGetFailoverMarkerTasksScope:                   {operation: "GetFailoverMarkerTasks"},
 		PersistenceCompleteReplicationTaskScope:                  {operation: "CompleteReplicationTask"},
 		PersistenceRangeCompleteReplicationTaskScope:             {operation: "RangeCompleteReplicationTask"},
 		PersistencePutReplicationTaskToDLQScope:                  {operation: "PutReplicationTaskToDLQ"},
+		PersistencePutReplicationTasksToDLQScope:                 {operation: "PutReplicationTasksToDLQ"},
 		PersistenceGetReplicationTasksFromDLQScope:               {operation: "GetReplicationTasksFromDLQ"},
 		PersistenceGetReplicationDLQSizeScope:                    {operation: "GetReplicationDLQSize"},
 		PersistenceDeleteReplicationTaskFromDLQScope:             {operation: "DeleteReplicationTaskFromDLQ"},
 		PersistenceRangeDeleteReplicationTaskFromDLQScope:        {operation: "RangeDeleteReplicationTaskFromDLQ"},
+		PersistenceDeleteReplicationTasksFromDLQScope:            {operation: "DeleteReplicationTasksFromDLQ"},
 		PersistenceCreateFailoverMarkerTasksScope:                {operation: "CreateFailoverMarkerTasks"},
 		PersistenceGetTimerIndexTasksScope:                       {operation: "GetTimerIndexTasks"},
 		PersistenceCompleteTimerTaskScope:                        {operation: "CompleteTimerTask"},
 		PersistenceRangeCompleteTimerTaskScope:                   {operation: "RangeCompleteTimerTask"},
+		PersistenceCompleteTimerTasksForDomainScope:              {operation: "CompleteTimerTasksForDomain"},
 		PersistenceCreateTaskScope:                               {operation: "CreateTask"},
 		PersistenceGetTasksScope:                                 {operation: "GetTasks"},
 		PersistenceCompleteTaskScope:                             {operation: "CompleteTask"},
@@ -1129,13 +1196,16 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		PersistenceLeaseTaskListScope:                            {operation: "LeaseTaskList"},
 		PersistenceUpdateTaskListScope:                           {operation: "UpdateTaskList"},
 		PersistenceListTaskListScope:                             {operation: "ListTaskList"},
+		PersistenceListTaskListByDomainScope:                     {operation: "ListTaskListByDomain"},
 		PersistenceDeleteTaskListScope:                           {operation: "DeleteTaskList"},
 		PersistenceAppendHistoryEventsScope:                      {operation: "AppendHistoryEvents"},
 		PersistenceGetWorkflowExecutionHistoryScope:              {operation: "GetWorkflowExecutionHistory"},
 		PersistenceDeleteWorkflowExecutionHistoryScope:           {operation: "DeleteWorkflowExecutionHistory"},
 		PersistenceCreateDomainScope:                             {operation: "CreateDomain"},
 		PersistenceGetDomainScope:                                {operation: "GetDomain"},
+		PersistenceGetDomainConfigVersionScope:                   {operation: "GetDomainConfigVersion"},
 		PersistenceUpdateDomainScope:                             {operation: "UpdateDomain"},
+		PersistenceMarkDomainForDeletionScope:                    {operation: "MarkDomainForDeletion"},
 		PersistenceDeleteDomainScope:                             {operation: "DeleteDomain"},
 		PersistenceDeleteDomainByNameScope:                       {operation: "DeleteDomainByName"},
 		PersistenceListDomainScope:                               {operation: "ListDomain"},
@@ -1157,12 +1227,16 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		PersistenceCountWorkflowExecutionsScope:                  {operation: "CountWorkflowExecutions"},
 		PersistenceAppendHistoryNodesScope:                       {operation: "AppendHistoryNodes"},
 		PersistenceReadHistoryBranchScope:                        {operation: "ReadHistoryBranch"},
+		PersistenceReadHistoryBranchWithBlobsScope:               {operation: "ReadHistoryBranchWithBlobs"},
+		PersistenceReadHistoryNodeScope:                          {operation: "ReadHistoryNode"},
 		PersistenceForkHistoryBranchScope:                        {operation: "ForkHistoryBranch"},
 		PersistenceDeleteHistoryBranchScope:                      {operation: "DeleteHistoryBranch"},
 		PersistenceCompleteForkBranchScope:                       {operation: "CompleteForkBranch"},
 		PersistenceGetHistoryTreeScope:                           {operation: "GetHistoryTree"},
 		PersistenceGetAllHistoryTreeBranchesScope:                {operation: "GetAllHistoryTreeBranches"},
 		PersistenceEnqueueMessageScope:                           {operation: "EnqueueMessage"},
+		PersistenceEnqueueMessageWithIDScope:                     {operation: "EnqueueMessageWithID"},
+		PersistenceEnqueueMessagesScope:                          {operation: "EnqueueMessages"},
 		PersistenceEnqueueMessageToDLQScope:                      {operation: "EnqueueMessageToDLQ"},
 		PersistenceReadQueueMessagesScope:                        {operation: "ReadQueueMessages"},
 		PersistenceReadQueueMessagesFromDLQScope:                 {operation: "ReadQueueMessagesFromDLQ"},
@@ -1365,6 +1439,7 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 
 		DomainFailoverScope:         {operation: "DomainFailover"},
 		DomainReplicationQueueScope: {operation: "DomainReplicationQueue"},
+		CassandraSessionScope:       {operation: "CassandraSession"},
 	},
 	// Frontend Scope Names
 	Frontend: {
@@ -1602,6 +1677,7 @@ const (
 	PersistenceLatency
 	PersistenceErrShardExistsCounter
 	PersistenceErrShardOwnershipLostCounter
+	PersistenceErrShardRangeIDMismatchCounter
 	PersistenceErrConditionFailedCounter
 	PersistenceErrCurrentWorkflowConditionFailedCounter
 	PersistenceErrTimeoutCounter
@@ -1611,6 +1687,12 @@ const (
 	PersistenceErrDomainAlreadyExistsCounter
 	PersistenceErrBadRequestCounter
 	PersistenceSampledCounter
+	PersistenceShardThrashingCounter
+
+	CassandraPoolConnectsCounter
+	CassandraPoolInFlightQueriesGauge
+	CassandraPoolErrorsCounter
+	CassandraClientCertificateReloadErrorsCounter
 
 	CadenceClientRequests
 	CadenceClientFailures
@@ -2072,6 +2154,7 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		PersistenceLatency:                                  {metricName: "persistence_latency", metricType: Timer},
 		PersistenceErrShardExistsCounter:                    {metricName: "persistence_errors_shard_exists", metricType: Counter},
 		PersistenceErrShardOwnershipLostCounter:             {metricName: "persistence_errors_shard_ownership_lost", metricType: Counter},
+		PersistenceErrShardRangeIDMismatchCounter:           {metricName: "persistence_errors_shard_range_id_mismatch", metricType: Counter},
 		PersistenceErrConditionFailedCounter:                {metricName: "persistence_errors_condition_failed", metricType: Counter},
 		PersistenceErrCurrentWorkflowConditionFailedCounter: {metricName: "persistence_errors_current_workflow_condition_failed", metricType: Counter},
 		PersistenceErrTimeoutCounter:                        {metricName: "persistence_errors_timeout", metricType: Counter},
@@ -2081,6 +2164,11 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		PersistenceErrDomainAlreadyExistsCounter:            {metricName: "persistence_errors_domain_already_exists", metricType: Counter},
 		PersistenceErrBadRequestCounter:                     {metricName: "persistence_errors_bad_request", metricType: Counter},
 		PersistenceSampledCounter:                           {metricName: "persistence_sampled", metricType: Counter},
+		PersistenceShardThrashingCounter:                    {metricName: "persistence_shard_thrashing", metricType: Counter},
+		CassandraPoolConnectsCounter:                        {metricName: "cassandra_pool_connects", metricType: Counter},
+		CassandraPoolInFlightQueriesGauge:                   {metricName: "cassandra_pool_inflight_queries", metricType: Gauge},
+		CassandraPoolErrorsCounter:                          {metricName: "cassandra_pool_errors", metricType: Counter},
+		CassandraClientCertificateReloadErrorsCounter:       {metricName: "cassandra_client_certificate_reload_errors", metricType: Counter},
 		CadenceClientRequests:                               {metricName: "cadence_client_requests", metricType: Counter},
 		CadenceClientFailures:                               {metricName: "cadence_client_errors", metricType: Counter},
 		CadenceClientLatency:                                {metricName: "cadence_client_latency", metricType: Timer},
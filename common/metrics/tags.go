@@ -43,6 +43,7 @@ const (
 	invariantType  = "invariantType"
 	kafkaPartition = "kafkaPartition"
 	transport      = "transport"
+	cassandraHost  = "cassandra_host"
 
 	domainAllValue = "all"
 	unknownValue   = "_unknown_"
@@ -144,6 +145,11 @@ func KafkaPartitionTag(value int32) Tag {
 	return simpleMetric{key: kafkaPartition, value: strconv.Itoa(int(value))}
 }
 
+// CassandraHostTag returns a new cassandra host tag.
+func CassandraHostTag(value string) Tag {
+	return metricWithUnknown(cassandraHost, value)
+}
+
 // ThriftTransportTag returns a new Thrift transport type tag.
 func ThriftTransportTag() Tag {
 	return simpleMetric{key: transport, value: transportThrift}
@@ -35,11 +35,62 @@ type ExecutionManager struct {
 	mock.Mock
 }
 
+// CheckCurrentExecution provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) CheckCurrentExecution(ctx context.Context, request *persistence.CheckCurrentExecutionRequest) (*persistence.CheckCurrentExecutionResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.CheckCurrentExecutionResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.CheckCurrentExecutionRequest) *persistence.CheckCurrentExecutionResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.CheckCurrentExecutionResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.CheckCurrentExecutionRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Close provides a mock function with given fields:
 func (_m *ExecutionManager) Close() {
 	_m.Called()
 }
 
+// CloseWithContext provides a mock function with given fields: ctx
+func (_m *ExecutionManager) CloseWithContext(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CompleteCrossClusterTask provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) CompleteCrossClusterTask(ctx context.Context, request *persistence.CompleteCrossClusterTaskRequest) error {
+	ret := _m.Called(ctx, request)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.CompleteCrossClusterTaskRequest) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CompleteReplicationTask provides a mock function with given fields: ctx, request
 func (_m *ExecutionManager) CompleteReplicationTask(ctx context.Context, request *persistence.CompleteReplicationTaskRequest) error {
 	ret := _m.Called(ctx, request)
@@ -68,6 +119,27 @@ func (_m *ExecutionManager) CompleteTimerTask(ctx context.Context, request *pers
 	return r0
 }
 
+// CompleteTimerTasksForDomain provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) CompleteTimerTasksForDomain(ctx context.Context, request *persistence.CompleteTimerTasksForDomainRequest) (int, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.CompleteTimerTasksForDomainRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.CompleteTimerTasksForDomainRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CompleteTransferTask provides a mock function with given fields: ctx, request
 func (_m *ExecutionManager) CompleteTransferTask(ctx context.Context, request *persistence.CompleteTransferTaskRequest) error {
 	ret := _m.Called(ctx, request)
@@ -82,6 +154,20 @@ func (_m *ExecutionManager) CompleteTransferTask(ctx context.Context, request *p
 	return r0
 }
 
+// CompleteTransferTasks provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) CompleteTransferTasks(ctx context.Context, request *persistence.CompleteTransferTasksRequest) error {
+	ret := _m.Called(ctx, request)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.CompleteTransferTasksRequest) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ConflictResolveWorkflowExecution provides a mock function with given fields: ctx, request
 func (_m *ExecutionManager) ConflictResolveWorkflowExecution(ctx context.Context, request *persistence.ConflictResolveWorkflowExecutionRequest) error {
 	ret := _m.Called(ctx, request)
@@ -96,6 +182,29 @@ func (_m *ExecutionManager) ConflictResolveWorkflowExecution(ctx context.Context
 	return r0
 }
 
+// CountWorkflowExecutions provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) CountWorkflowExecutions(ctx context.Context, request *persistence.CountExecutionsRequest) (*persistence.CountExecutionsResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.CountExecutionsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.CountExecutionsRequest) *persistence.CountExecutionsResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.CountExecutionsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.CountExecutionsRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateFailoverMarkerTasks provides a mock function with given fields: ctx, request
 func (_m *ExecutionManager) CreateFailoverMarkerTasks(ctx context.Context, request *persistence.CreateFailoverMarkersRequest) error {
 	ret := _m.Called(ctx, request)
@@ -161,6 +270,20 @@ func (_m *ExecutionManager) DeleteReplicationTaskFromDLQ(ctx context.Context, re
 	return r0
 }
 
+// DeleteReplicationTasksFromDLQ provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) DeleteReplicationTasksFromDLQ(ctx context.Context, request *persistence.DeleteReplicationTasksFromDLQRequest) error {
+	ret := _m.Called(ctx, request)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.DeleteReplicationTasksFromDLQRequest) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteWorkflowExecution provides a mock function with given fields: ctx, request
 func (_m *ExecutionManager) DeleteWorkflowExecution(ctx context.Context, request *persistence.DeleteWorkflowExecutionRequest) error {
 	ret := _m.Called(ctx, request)
@@ -175,6 +298,66 @@ func (_m *ExecutionManager) DeleteWorkflowExecution(ctx context.Context, request
 	return r0
 }
 
+// DeleteWorkflowExecutions provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) DeleteWorkflowExecutions(ctx context.Context, request *persistence.DeleteWorkflowExecutionsRequest) (*persistence.DeleteWorkflowExecutionsResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.DeleteWorkflowExecutionsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.DeleteWorkflowExecutionsRequest) *persistence.DeleteWorkflowExecutionsResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.DeleteWorkflowExecutionsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.DeleteWorkflowExecutionsRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FlushBufferedEvents provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) FlushBufferedEvents(ctx context.Context, request *persistence.FlushBufferedEventsRequest) error {
+	ret := _m.Called(ctx, request)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.FlushBufferedEventsRequest) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetCrossClusterTasks provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) GetCrossClusterTasks(ctx context.Context, request *persistence.GetCrossClusterTasksRequest) (*persistence.GetCrossClusterTasksResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.GetCrossClusterTasksResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.GetCrossClusterTasksRequest) *persistence.GetCrossClusterTasksResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.GetCrossClusterTasksResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.GetCrossClusterTasksRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCurrentExecution provides a mock function with given fields: ctx, request
 func (_m *ExecutionManager) GetCurrentExecution(ctx context.Context, request *persistence.GetCurrentExecutionRequest) (*persistence.GetCurrentExecutionResponse, error) {
 	ret := _m.Called(ctx, request)
@@ -198,6 +381,52 @@ func (_m *ExecutionManager) GetCurrentExecution(ctx context.Context, request *pe
 	return r0, r1
 }
 
+// GetCurrentRunID provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) GetCurrentRunID(ctx context.Context, request *persistence.GetCurrentRunIDRequest) (*persistence.GetCurrentRunIDResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.GetCurrentRunIDResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.GetCurrentRunIDRequest) *persistence.GetCurrentRunIDResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.GetCurrentRunIDResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.GetCurrentRunIDRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFailoverMarkerTasks provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) GetFailoverMarkerTasks(ctx context.Context, request *persistence.GetFailoverMarkerTasksRequest) (*persistence.GetFailoverMarkerTasksResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.GetFailoverMarkerTasksResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.GetFailoverMarkerTasksRequest) *persistence.GetFailoverMarkerTasksResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.GetFailoverMarkerTasksResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.GetFailoverMarkerTasksRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetName provides a mock function with given fields:
 func (_m *ExecutionManager) GetName() string {
 	ret := _m.Called()
@@ -258,6 +487,29 @@ func (_m *ExecutionManager) GetReplicationTasks(ctx context.Context, request *pe
 	return r0, r1
 }
 
+// GetReplicationTasksFromAllDLQs provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) GetReplicationTasksFromAllDLQs(ctx context.Context, request *persistence.GetReplicationTasksFromAllDLQsRequest) (*persistence.GetReplicationTasksFromAllDLQsResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.GetReplicationTasksFromAllDLQsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.GetReplicationTasksFromAllDLQsRequest) *persistence.GetReplicationTasksFromAllDLQsResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.GetReplicationTasksFromAllDLQsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.GetReplicationTasksFromAllDLQsRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetReplicationTasksFromDLQ provides a mock function with given fields: ctx, request
 func (_m *ExecutionManager) GetReplicationTasksFromDLQ(ctx context.Context, request *persistence.GetReplicationTasksFromDLQRequest) (*persistence.GetReplicationTasksResponse, error) {
 	ret := _m.Called(ctx, request)
@@ -318,6 +570,22 @@ func (_m *ExecutionManager) GetTimerIndexTasks(ctx context.Context, request *per
 	return r0, r1
 }
 
+// GetTimerIndexTasksIterator provides a mock function with given fields: request
+func (_m *ExecutionManager) GetTimerIndexTasksIterator(request *persistence.GetTimerIndexTasksRequest) persistence.TimerTaskIterator {
+	ret := _m.Called(request)
+
+	var r0 persistence.TimerTaskIterator
+	if rf, ok := ret.Get(0).(func(*persistence.GetTimerIndexTasksRequest) persistence.TimerTaskIterator); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(persistence.TimerTaskIterator)
+		}
+	}
+
+	return r0
+}
+
 // GetTransferTasks provides a mock function with given fields: ctx, request
 func (_m *ExecutionManager) GetTransferTasks(ctx context.Context, request *persistence.GetTransferTasksRequest) (*persistence.GetTransferTasksResponse, error) {
 	ret := _m.Called(ctx, request)
@@ -364,6 +632,29 @@ func (_m *ExecutionManager) GetWorkflowExecution(ctx context.Context, request *p
 	return r0, r1
 }
 
+// GetWorkflowExecutionForUpdate provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) GetWorkflowExecutionForUpdate(ctx context.Context, request *persistence.GetWorkflowExecutionForUpdateRequest) (*persistence.GetWorkflowExecutionForUpdateResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.GetWorkflowExecutionForUpdateResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.GetWorkflowExecutionForUpdateRequest) *persistence.GetWorkflowExecutionForUpdateResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.GetWorkflowExecutionForUpdateResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.GetWorkflowExecutionForUpdateRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsWorkflowExecutionExists provides a mock function with given fields: ctx, request
 func (_m *ExecutionManager) IsWorkflowExecutionExists(ctx context.Context, request *persistence.IsWorkflowExecutionExistsRequest) (*persistence.IsWorkflowExecutionExistsResponse, error) {
 	ret := _m.Called(ctx, request)
@@ -447,6 +738,34 @@ func (_m *ExecutionManager) PutReplicationTaskToDLQ(ctx context.Context, request
 	return r0
 }
 
+// PutReplicationTasksToDLQ provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) PutReplicationTasksToDLQ(ctx context.Context, request *persistence.PutReplicationTasksToDLQRequest) error {
+	ret := _m.Called(ctx, request)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.PutReplicationTasksToDLQRequest) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RangeCompleteCrossClusterTask provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) RangeCompleteCrossClusterTask(ctx context.Context, request *persistence.RangeCompleteCrossClusterTaskRequest) error {
+	ret := _m.Called(ctx, request)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.RangeCompleteCrossClusterTaskRequest) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RangeCompleteReplicationTask provides a mock function with given fields: ctx, request
 func (_m *ExecutionManager) RangeCompleteReplicationTask(ctx context.Context, request *persistence.RangeCompleteReplicationTaskRequest) error {
 	ret := _m.Called(ctx, request)
@@ -539,3 +858,26 @@ func (_m *ExecutionManager) UpdateWorkflowExecution(ctx context.Context, request
 
 	return r0, r1
 }
+
+// WorkflowExists provides a mock function with given fields: ctx, request
+func (_m *ExecutionManager) WorkflowExists(ctx context.Context, request *persistence.WorkflowExistsRequest) (*persistence.WorkflowExistsResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.WorkflowExistsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.WorkflowExistsRequest) *persistence.WorkflowExistsResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.WorkflowExistsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.WorkflowExistsRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
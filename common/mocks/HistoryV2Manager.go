@@ -28,6 +28,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	persistence "github.com/uber/cadence/common/persistence"
+
+	types "github.com/uber/cadence/common/types"
 )
 
 // HistoryV2Manager is an autogenerated mock type for the HistoryV2Manager type
@@ -64,6 +66,20 @@ func (_m *HistoryV2Manager) Close() {
 	_m.Called()
 }
 
+// CloseWithContext provides a mock function with given fields: ctx
+func (_m *HistoryV2Manager) CloseWithContext(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteHistoryBranch provides a mock function with given fields: ctx, request
 func (_m *HistoryV2Manager) DeleteHistoryBranch(ctx context.Context, request *persistence.DeleteHistoryBranchRequest) error {
 	ret := _m.Called(ctx, request)
@@ -207,6 +223,66 @@ func (_m *HistoryV2Manager) ReadHistoryBranchByBatch(ctx context.Context, reques
 	return r0, r1
 }
 
+// ReadHistoryBranchIterator provides a mock function with given fields: ctx, request, callback
+func (_m *HistoryV2Manager) ReadHistoryBranchIterator(ctx context.Context, request *persistence.ReadHistoryBranchRequest, callback func(*types.HistoryEvent) error) error {
+	ret := _m.Called(ctx, request, callback)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.ReadHistoryBranchRequest, func(*types.HistoryEvent) error) error); ok {
+		r0 = rf(ctx, request, callback)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReadHistoryBranchWithBlobs provides a mock function with given fields: ctx, request
+func (_m *HistoryV2Manager) ReadHistoryBranchWithBlobs(ctx context.Context, request *persistence.ReadHistoryBranchRequest) (*persistence.ReadHistoryBranchWithBlobsResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.ReadHistoryBranchWithBlobsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.ReadHistoryBranchRequest) *persistence.ReadHistoryBranchWithBlobsResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.ReadHistoryBranchWithBlobsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.ReadHistoryBranchRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadHistoryNode provides a mock function with given fields: ctx, request
+func (_m *HistoryV2Manager) ReadHistoryNode(ctx context.Context, request *persistence.ReadHistoryNodeRequest) (*persistence.ReadHistoryNodeResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.ReadHistoryNodeResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.ReadHistoryNodeRequest) *persistence.ReadHistoryNodeResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.ReadHistoryNodeResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.ReadHistoryNodeRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReadRawHistoryBranch provides a mock function with given fields: ctx, request
 func (_m *HistoryV2Manager) ReadRawHistoryBranch(ctx context.Context, request *persistence.ReadHistoryBranchRequest) (*persistence.ReadRawHistoryBranchResponse, error) {
 	ret := _m.Called(ctx, request)
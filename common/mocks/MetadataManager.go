@@ -35,11 +35,48 @@ type MetadataManager struct {
 	mock.Mock
 }
 
+// BatchGetDomains provides a mock function with given fields: ctx, request
+func (_m *MetadataManager) BatchGetDomains(ctx context.Context, request *persistence.BatchGetDomainsRequest) (*persistence.BatchGetDomainsResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.BatchGetDomainsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.BatchGetDomainsRequest) *persistence.BatchGetDomainsResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.BatchGetDomainsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.BatchGetDomainsRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Close provides a mock function with given fields:
 func (_m *MetadataManager) Close() {
 	_m.Called()
 }
 
+// CloseWithContext provides a mock function with given fields: ctx
+func (_m *MetadataManager) CloseWithContext(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateDomain provides a mock function with given fields: ctx, request
 func (_m *MetadataManager) CreateDomain(ctx context.Context, request *persistence.CreateDomainRequest) (*persistence.CreateDomainResponse, error) {
 	ret := _m.Called(ctx, request)
@@ -114,6 +151,29 @@ func (_m *MetadataManager) GetDomain(ctx context.Context, request *persistence.G
 	return r0, r1
 }
 
+// GetDomainConfigVersion provides a mock function with given fields: ctx, request
+func (_m *MetadataManager) GetDomainConfigVersion(ctx context.Context, request *persistence.GetDomainConfigVersionRequest) (*persistence.GetDomainConfigVersionResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.GetDomainConfigVersionResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.GetDomainConfigVersionRequest) *persistence.GetDomainConfigVersionResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.GetDomainConfigVersionResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.GetDomainConfigVersionRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMetadata provides a mock function with given fields: ctx
 func (_m *MetadataManager) GetMetadata(ctx context.Context) (*persistence.GetMetadataResponse, error) {
 	ret := _m.Called(ctx)
@@ -174,6 +234,20 @@ func (_m *MetadataManager) ListDomains(ctx context.Context, request *persistence
 	return r0, r1
 }
 
+// MarkDomainForDeletion provides a mock function with given fields: ctx, request
+func (_m *MetadataManager) MarkDomainForDeletion(ctx context.Context, request *persistence.MarkDomainForDeletionRequest) error {
+	ret := _m.Called(ctx, request)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.MarkDomainForDeletionRequest) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateDomain provides a mock function with given fields: ctx, request
 func (_m *MetadataManager) UpdateDomain(ctx context.Context, request *persistence.UpdateDomainRequest) error {
 	ret := _m.Called(ctx, request)
@@ -40,6 +40,20 @@ func (_m *ShardManager) Close() {
 	_m.Called()
 }
 
+// CloseWithContext provides a mock function with given fields: ctx
+func (_m *ShardManager) CloseWithContext(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateShard provides a mock function with given fields: ctx, request
 func (_m *ShardManager) CreateShard(ctx context.Context, request *persistence.CreateShardRequest) error {
 	ret := _m.Called(ctx, request)
@@ -91,6 +105,52 @@ func (_m *ShardManager) GetShard(ctx context.Context, request *persistence.GetSh
 	return r0, r1
 }
 
+// GetShards provides a mock function with given fields: ctx, request
+func (_m *ShardManager) GetShards(ctx context.Context, request *persistence.GetShardsRequest) (*persistence.GetShardsResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.GetShardsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.GetShardsRequest) *persistence.GetShardsResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.GetShardsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.GetShardsRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListShards provides a mock function with given fields: ctx, request
+func (_m *ShardManager) ListShards(ctx context.Context, request *persistence.ListShardsRequest) (*persistence.ListShardsResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.ListShardsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.ListShardsRequest) *persistence.ListShardsResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.ListShardsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.ListShardsRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateShard provides a mock function with given fields: ctx, request
 func (_m *ShardManager) UpdateShard(ctx context.Context, request *persistence.UpdateShardRequest) error {
 	ret := _m.Called(ctx, request)
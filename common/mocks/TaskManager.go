@@ -40,6 +40,20 @@ func (_m *TaskManager) Close() {
 	_m.Called()
 }
 
+// CloseWithContext provides a mock function with given fields: ctx
+func (_m *TaskManager) CloseWithContext(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CompleteTask provides a mock function with given fields: ctx, request
 func (_m *TaskManager) CompleteTask(ctx context.Context, request *persistence.CompleteTaskRequest) error {
 	ret := _m.Called(ctx, request)
@@ -172,6 +186,22 @@ func (_m *TaskManager) GetTasks(ctx context.Context, request *persistence.GetTas
 	return r0, r1
 }
 
+// GetTasksIterator provides a mock function with given fields: request
+func (_m *TaskManager) GetTasksIterator(request *persistence.GetTasksRequest) persistence.TaskIterator {
+	ret := _m.Called(request)
+
+	var r0 persistence.TaskIterator
+	if rf, ok := ret.Get(0).(func(*persistence.GetTasksRequest) persistence.TaskIterator); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(persistence.TaskIterator)
+		}
+	}
+
+	return r0
+}
+
 // LeaseTaskList provides a mock function with given fields: ctx, request
 func (_m *TaskManager) LeaseTaskList(ctx context.Context, request *persistence.LeaseTaskListRequest) (*persistence.LeaseTaskListResponse, error) {
 	ret := _m.Called(ctx, request)
@@ -218,6 +248,29 @@ func (_m *TaskManager) ListTaskList(ctx context.Context, request *persistence.Li
 	return r0, r1
 }
 
+// ListTaskListByDomain provides a mock function with given fields: ctx, request
+func (_m *TaskManager) ListTaskListByDomain(ctx context.Context, request *persistence.ListTaskListByDomainRequest) (*persistence.ListTaskListResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *persistence.ListTaskListResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *persistence.ListTaskListByDomainRequest) *persistence.ListTaskListResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.ListTaskListResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *persistence.ListTaskListByDomainRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateTaskList provides a mock function with given fields: ctx, request
 func (_m *TaskManager) UpdateTaskList(ctx context.Context, request *persistence.UpdateTaskListRequest) (*persistence.UpdateTaskListResponse, error) {
 	ret := _m.Called(ctx, request)
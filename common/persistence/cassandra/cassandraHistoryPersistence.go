@@ -28,6 +28,7 @@ import (
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
 	p "github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra"
@@ -58,10 +59,11 @@ func NewHistoryV2PersistenceFromSession(
 func newHistoryV2Persistence(
 	cfg config.Cassandra,
 	logger log.Logger,
+	metricsClient metrics.Client,
 ) (p.HistoryStore, error) {
 
 	// TODO hardcoding to Cassandra for now, will switch to dynamically loading later
-	db, err := cassandra.NewCassandraDB(cfg, logger)
+	db, err := cassandra.NewCassandraDB(cfg, logger, metricsClient)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +80,11 @@ func (h *nosqlHistoryManager) Close() {
 	h.db.Close()
 }
 
+// CloseWithContext releases the underlying resources like Close, but stops waiting once ctx is done
+func (h *nosqlHistoryManager) CloseWithContext(ctx context.Context) error {
+	return h.db.CloseWithContext(ctx)
+}
+
 // AppendHistoryNodes upsert a batch of events as a single node to a history branch
 // Note that it's not allowed to append above the branch's ancestors' nodes, which means nodeID >= ForkNodeID
 func (h *nosqlHistoryManager) AppendHistoryNodes(
@@ -116,12 +123,23 @@ func (h *nosqlHistoryManager) AppendHistoryNodes(
 		NodeID:       request.NodeID,
 		TxnID:        &request.TransactionID,
 		Data:         request.Events.Data,
-		DataEncoding: string(request.Events.Encoding),
+		DataEncoding: request.Events.GetStoredEncoding(),
 		ShardID:      request.ShardID,
+		TTLSeconds:   request.TTLSeconds,
+		FailIfExists: request.FailIfExists,
 	}
 	err = h.db.InsertIntoHistoryTreeAndNode(ctx, treeRow, nodeRow)
 
 	if err != nil {
+		if h.db.IsConditionFailedError(err) {
+			return &p.HistoryNodeConflictError{
+				TreeID:   branchInfo.GetTreeID(),
+				BranchID: branchInfo.GetBranchID(),
+				NodeID:   request.NodeID,
+				Msg: fmt.Sprintf("history node %v already exists in branch %v of tree %v",
+					request.NodeID, branchInfo.GetBranchID(), branchInfo.GetTreeID()),
+			}
+		}
 		return convertCommonErrors(h.db, "AppendHistoryNodes", err)
 	}
 	return nil
@@ -159,7 +177,7 @@ func (h *nosqlHistoryManager) ReadHistoryBranch(
 		nodeID = row.NodeID
 		txnID = *row.TxnID
 		eventBlob.Data = row.Data
-		eventBlob.Encoding = common.EncodingType(row.DataEncoding)
+		eventBlob.Encoding, eventBlob.Compression = p.ParseStoredEncoding(row.DataEncoding)
 		if txnID < lastTxnID {
 			// assuming that business logic layer is correct and transaction ID only increase
 			// thus, valid event batch will come with increasing transaction ID
@@ -200,6 +218,29 @@ func (h *nosqlHistoryManager) ReadHistoryBranch(
 	}, nil
 }
 
+// ReadHistoryNode returns the latest transaction of a single history node
+func (h *nosqlHistoryManager) ReadHistoryNode(
+	ctx context.Context,
+	request *p.InternalReadHistoryNodeRequest,
+) (*p.InternalReadHistoryNodeResponse, error) {
+	row, err := h.db.SelectOneFromHistoryNode(ctx, request.TreeID, request.BranchID, request.NodeID)
+	if err != nil {
+		if h.db.IsNotFoundError(err) {
+			return nil, &types.EntityNotExistsError{
+				Message: fmt.Sprintf("History node not found. TreeID: %v, BranchID: %v, NodeID: %v", request.TreeID, request.BranchID, request.NodeID),
+			}
+		}
+		return nil, convertCommonErrors(h.db, "SelectOneFromHistoryNode", err)
+	}
+
+	history := &p.DataBlob{Data: row.Data}
+	history.Encoding, history.Compression = p.ParseStoredEncoding(row.DataEncoding)
+	return &p.InternalReadHistoryNodeResponse{
+		History:       history,
+		TransactionID: *row.TxnID,
+	}, nil
+}
+
 // ForkHistoryBranch forks a new branch from an existing branch
 // Note that application must provide a void forking nodeID, it must be a valid nodeID in that branch.
 // A valid forking nodeID can be an ancestor from the existing branch.
@@ -317,12 +358,6 @@ func (h *nosqlHistoryManager) DeleteHistoryBranch(
 
 	branch := request.BranchInfo
 	treeID := *branch.TreeID
-	brsToDelete := branch.Ancestors
-	beginNodeID := p.GetBeginNodeID(branch)
-	brsToDelete = append(brsToDelete, &types.HistoryBranchRange{
-		BranchID:    branch.BranchID,
-		BeginNodeID: common.Int64Ptr(beginNodeID),
-	})
 
 	rsp, err := h.GetHistoryTree(ctx, &p.InternalGetHistoryTreeRequest{
 		TreeID:  treeID,
@@ -338,42 +373,13 @@ func (h *nosqlHistoryManager) DeleteHistoryBranch(
 		BranchID: branch.BranchID,
 	}
 	var nodeFilters []*nosqlplugin.HistoryNodeFilter
-
-	// validBRsMaxEndNode is to know each branch range that is being used, we want to know what is the max nodeID referred by other valid branch
-	validBRsMaxEndNode := map[string]int64{}
-	for _, b := range rsp.Branches {
-		for _, br := range b.Ancestors {
-			curr, ok := validBRsMaxEndNode[*br.BranchID]
-			if !ok || curr < *br.EndNodeID {
-				validBRsMaxEndNode[*br.BranchID] = *br.EndNodeID
-			}
-		}
-	}
-
-	// for each branch range to delete, we iterate from bottom to up, and delete up to the point according to validBRsEndNode
-	for i := len(brsToDelete) - 1; i >= 0; i-- {
-		br := brsToDelete[i]
-		maxReferredEndNodeID, ok := validBRsMaxEndNode[*br.BranchID]
-		if ok {
-			// we can only delete from the maxEndNode and stop here
-			nodeFilter := &nosqlplugin.HistoryNodeFilter{
-				ShardID:   request.ShardID,
-				TreeID:    treeID,
-				BranchID:  *br.BranchID,
-				MinNodeID: maxReferredEndNodeID,
-			}
-			nodeFilters = append(nodeFilters, nodeFilter)
-			break
-		} else {
-			// No any branch is using this range, we can delete all of it
-			nodeFilter := &nosqlplugin.HistoryNodeFilter{
-				ShardID:   request.ShardID,
-				TreeID:    treeID,
-				BranchID:  *br.BranchID,
-				MinNodeID: *br.BeginNodeID,
-			}
-			nodeFilters = append(nodeFilters, nodeFilter)
-		}
+	for _, br := range p.GetHistoryBranchRangesToDelete(branch, rsp.Branches, request.PreserveAncestors) {
+		nodeFilters = append(nodeFilters, &nosqlplugin.HistoryNodeFilter{
+			ShardID:   request.ShardID,
+			TreeID:    treeID,
+			BranchID:  *br.BranchID,
+			MinNodeID: *br.BeginNodeID,
+		})
 	}
 
 	err = h.db.DeleteFromHistoryTreeAndNode(ctx, treeFilter, nodeFilters)
@@ -420,12 +426,19 @@ func (h *nosqlHistoryManager) GetHistoryTree(
 ) (*p.InternalGetHistoryTreeResponse, error) {
 
 	treeID := request.TreeID
+	filter := &nosqlplugin.HistoryTreeFilter{
+		ShardID: *request.ShardID,
+		TreeID:  treeID,
+	}
 
-	dbBranches, err := h.db.SelectFromHistoryTree(ctx,
-		&nosqlplugin.HistoryTreeFilter{
-			ShardID: *request.ShardID,
-			TreeID:  treeID,
-		})
+	var dbBranches []*nosqlplugin.HistoryTreeRow
+	var nextPageToken []byte
+	var err error
+	if request.PageSize > 0 {
+		dbBranches, nextPageToken, err = h.db.SelectFromHistoryTreeWithPagination(ctx, filter, request.NextPageToken, request.PageSize)
+	} else {
+		dbBranches, err = h.db.SelectFromHistoryTree(ctx, filter)
+	}
 	if err != nil {
 		return nil, convertCommonErrors(h.db, "SelectFromHistoryTree", err)
 	}
@@ -440,6 +453,7 @@ func (h *nosqlHistoryManager) GetHistoryTree(
 		branches = append(branches, br)
 	}
 	return &p.InternalGetHistoryTreeResponse{
-		Branches: branches,
+		Branches:      branches,
+		NextPageToken: nextPageToken,
 	}, nil
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
 	p "github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra"
@@ -45,9 +46,10 @@ func newMetadataPersistenceV2(
 	cfg config.Cassandra,
 	currentClusterName string,
 	logger log.Logger,
+	metricsClient metrics.Client,
 ) (p.MetadataStore, error) {
 	// TODO hardcoding to Cassandra for now, will switch to dynamically loading later
-	db, err := cassandra.NewCassandraDB(cfg, logger)
+	db, err := cassandra.NewCassandraDB(cfg, logger, metricsClient)
 	if err != nil {
 		return nil, err
 	}
@@ -118,16 +120,38 @@ func (m *nosqlDomainManager) UpdateDomain(
 		PreviousFailoverVersion:     request.PreviousFailoverVersion,
 		FailoverEndTime:             request.FailoverEndTime,
 		NotificationVersion:         request.NotificationVersion,
+		DeletionTime:                request.DeletionTime,
 	}
 
 	err = m.db.UpdateDomain(ctx, row)
 	if err != nil {
+		if m.db.IsConditionFailedError(err) {
+			return m.domainVersionConflictError(ctx, request.NotificationVersion)
+		}
 		return convertCommonErrors(m.db, "UpdateDomain", err)
 	}
 
 	return nil
 }
 
+// domainVersionConflictError reads back the current notification version after a conditional domain
+// write failed, the version may already be newer than the one which caused the conflict
+func (m *nosqlDomainManager) domainVersionConflictError(
+	ctx context.Context,
+	expectedVersion int64,
+) error {
+	actualVersion, err := m.db.SelectDomainMetadata(ctx)
+	if err != nil {
+		return convertCommonErrors(m.db, "UpdateDomain", err)
+	}
+	return &p.DomainVersionConflictError{
+		ExpectedVersion: expectedVersion,
+		ActualVersion:   actualVersion,
+		Msg: fmt.Sprintf("UpdateDomain operation failed because of a version conflict. Expected notification version: %v, actual: %v",
+			expectedVersion, actualVersion),
+	}
+}
+
 func (m *nosqlDomainManager) GetDomain(
 	ctx context.Context,
 	request *p.GetDomainRequest,
@@ -193,6 +217,45 @@ func (m *nosqlDomainManager) GetDomain(
 		FailoverEndTime:             row.FailoverEndTime,
 		NotificationVersion:         row.NotificationVersion,
 		LastUpdatedTime:             row.LastUpdatedTime,
+		DeletionTime:                row.DeletionTime,
+	}, nil
+}
+
+func (m *nosqlDomainManager) GetDomainConfigVersion(
+	ctx context.Context,
+	request *p.GetDomainConfigVersionRequest,
+) (*p.GetDomainConfigVersionResponse, error) {
+	if len(request.ID) > 0 && len(request.Name) > 0 {
+		return nil, &types.BadRequestError{
+			Message: "GetDomainConfigVersion operation failed.  Both ID and Name specified in request.",
+		}
+	} else if len(request.ID) == 0 && len(request.Name) == 0 {
+		return nil, &types.BadRequestError{
+			Message: "GetDomainConfigVersion operation failed.  Both ID and Name are empty.",
+		}
+	}
+	var domainName *string
+	var domainID *string
+	identity := request.Name
+	if len(request.ID) > 0 {
+		domainID = common.StringPtr(request.ID)
+		identity = request.ID
+	} else {
+		domainName = common.StringPtr(request.Name)
+	}
+
+	configVersion, failoverVersion, err := m.db.SelectDomainConfigVersion(ctx, domainID, domainName)
+	if err != nil {
+		if m.db.IsNotFoundError(err) {
+			return nil, &types.EntityNotExistsError{
+				Message: fmt.Sprintf("Domain %s does not exist.", identity),
+			}
+		}
+		return nil, convertCommonErrors(m.db, "GetDomainConfigVersion", err)
+	}
+	return &p.GetDomainConfigVersionResponse{
+		ConfigVersion:   configVersion,
+		FailoverVersion: failoverVersion,
 	}, nil
 }
 
@@ -231,6 +294,7 @@ func (m *nosqlDomainManager) ListDomains(
 			FailoverEndTime:             row.FailoverEndTime,
 			NotificationVersion:         row.NotificationVersion,
 			LastUpdatedTime:             row.LastUpdatedTime,
+			DeletionTime:                row.DeletionTime,
 		})
 	}
 
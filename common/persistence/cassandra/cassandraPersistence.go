@@ -56,7 +56,7 @@ var _ p.ExecutionStore = (*cassandraPersistence)(nil)
 // Where x is any hexadecimal value, E represents the entity type valid values are:
 // E = {DomainID = 1, WorkflowID = 2, RunID = 3}
 // R represents row type in executions table, valid values are:
-// R = {Shard = 1, Execution = 2, Transfer = 3, Timer = 4, Replication = 5}
+// R = {Shard = 1, Execution = 2, Transfer = 3, Timer = 4, Replication = 5, ReplicationDLQ = 6, CrossCluster = 7}
 const (
 	// Special Domains related constants
 	emptyDomainID = "10000000-0000-f000-f000-000000000000"
@@ -82,12 +82,20 @@ const (
 	// Row Constants for Replication Task DLQ Row. Source cluster name will be used as WorkflowID.
 	rowTypeDLQDomainID = "10000000-6000-f000-f000-000000000000"
 	rowTypeDLQRunID    = "30000000-6000-f000-f000-000000000000"
+	// Row Constants for Cross Cluster Task Row. Target cluster name will be used as WorkflowID.
+	rowTypeCrossClusterDomainID = "10000000-7000-f000-f000-000000000000"
+	rowTypeCrossClusterRunID    = "30000000-7000-f000-f000-000000000000"
 	// Special TaskId constants
 	rowTypeExecutionTaskID = int64(-10)
 	rowTypeShardTaskID     = int64(-11)
 	emptyInitiatedID       = int64(-7)
 
 	stickyTaskListTTL = int32(24 * time.Hour / time.Second) // if sticky task_list stopped being updated, remove it in one day
+
+	completeTimerTasksForDomainPageSize = 1000 // page size used when scanning timer tasks to delete for a domain
+	deleteReplicationDLQTasksBatchSize  = 100  // max number of deletes sent in one batch when deleting a set of DLQ tasks
+	completeTransferTasksBatchSize      = 100  // max number of deletes sent in one batch when completing a set of transfer tasks
+	putReplicationDLQTasksBatchSize     = 100  // max number of inserts sent in one batch when putting a set of tasks to the DLQ
 )
 
 const (
@@ -98,6 +106,7 @@ const (
 	rowTypeTimerTask
 	rowTypeReplicationTask
 	rowTypeDLQ
+	rowTypeCrossClusterTask
 )
 
 const (
@@ -294,7 +303,7 @@ const (
 
 	templateUpdateCurrentWorkflowExecutionQuery = `UPDATE executions USING TTL 0 ` +
 		`SET current_run_id = ?,
-execution = {run_id: ?, create_request_id: ?, state: ?, close_status: ?},
+execution = {run_id: ?, create_request_id: ?, state: ?, close_status: ?, history_size: ?},
 workflow_last_write_version = ?,
 workflow_state = ? ` +
 		`WHERE shard_id = ? ` +
@@ -312,7 +321,7 @@ workflow_state = ? ` +
 
 	templateCreateCurrentWorkflowExecutionQuery = `INSERT INTO executions (` +
 		`shard_id, type, domain_id, workflow_id, run_id, visibility_ts, task_id, current_run_id, execution, workflow_last_write_version, workflow_state) ` +
-		`VALUES(?, ?, ?, ?, ?, ?, ?, ?, {run_id: ?, create_request_id: ?, state: ?, close_status: ?}, ?, ?) IF NOT EXISTS USING TTL 0 `
+		`VALUES(?, ?, ?, ?, ?, ?, ?, ?, {run_id: ?, create_request_id: ?, state: ?, close_status: ?, history_size: ?}, ?, ?) IF NOT EXISTS USING TTL 0 `
 
 	templateCreateWorkflowExecutionWithVersionHistoriesQuery = `INSERT INTO executions (` +
 		`shard_id, domain_id, workflow_id, run_id, type, execution, next_event_id, visibility_ts, task_id, version_histories, version_histories_encoding, checksum, workflow_last_write_version, workflow_state) ` +
@@ -341,10 +350,8 @@ workflow_state = ? ` +
 		`and task_id = ? ` +
 		`IF range_id = ?`
 
-	// TODO: remove replication_state after all 2DC workflows complete
-	templateGetWorkflowExecutionQuery = `SELECT execution, replication_state, activity_map, timer_map, ` +
-		`child_executions_map, request_cancel_map, signal_map, signal_requested, buffered_events_list, ` +
-		`buffered_replication_tasks_map, version_histories, version_histories_encoding, checksum ` +
+	// the selected columns are filled in by getWorkflowExecutionQuery
+	templateGetWorkflowExecutionQuery = `SELECT %v ` +
 		`FROM executions ` +
 		`WHERE shard_id = ? ` +
 		`and type = ? ` +
@@ -364,11 +371,28 @@ workflow_state = ? ` +
 		`and visibility_ts = ? ` +
 		`and task_id = ?`
 
+	templateGetCurrentRunIDQuery = `SELECT current_run_id ` +
+		`FROM executions ` +
+		`WHERE shard_id = ? ` +
+		`and type = ? ` +
+		`and domain_id = ? ` +
+		`and workflow_id = ? ` +
+		`and run_id = ? ` +
+		`and visibility_ts = ? ` +
+		`and task_id = ?`
+
 	templateListCurrentExecutionsQuery = `SELECT domain_id, workflow_id, run_id, current_run_id, workflow_state ` +
 		`FROM executions ` +
 		`WHERE shard_id = ? ` +
 		`and type = ?`
 
+	templateCountWorkflowExecutionsQuery = `SELECT count(1) as count ` +
+		`FROM executions ` +
+		`WHERE shard_id = ? ` +
+		`and type = ? ` +
+		`and next_event_id > 0 ` +
+		`ALLOW FILTERING`
+
 	templateIsWorkflowExecutionExistsQuery = `SELECT shard_id, type, domain_id, workflow_id, run_id, visibility_ts, task_id ` +
 		`FROM executions ` +
 		`WHERE shard_id = ? ` +
@@ -395,6 +419,26 @@ workflow_state = ? ` +
 		`and task_id = ? ` +
 		`IF next_event_id = ?`
 
+	templateGetWorkflowExecutionNextEventIDQuery = `SELECT next_event_id ` +
+		`FROM executions ` +
+		`WHERE shard_id = ? ` +
+		`and type = ? ` +
+		`and domain_id = ? ` +
+		`and workflow_id = ? ` +
+		`and run_id = ? ` +
+		`and visibility_ts = ? ` +
+		`and task_id = ?`
+
+	templateGetLeaseQuery = `SELECT range_id ` +
+		`FROM executions ` +
+		`WHERE shard_id = ? ` +
+		`and type = ? ` +
+		`and domain_id = ? ` +
+		`and workflow_id = ? ` +
+		`and run_id = ? ` +
+		`and visibility_ts = ? ` +
+		`and task_id = ?`
+
 	templateUpdateWorkflowExecutionWithVersionHistoriesQuery = `UPDATE executions ` +
 		`SET execution = ` + templateWorkflowExecutionType +
 		`, next_event_id = ? ` +
@@ -634,6 +678,17 @@ workflow_state = ? ` +
 		`and task_id > ? ` +
 		`and task_id <= ?`
 
+	templateGetCrossClusterTasksQuery = `SELECT cross_cluster ` +
+		`FROM executions ` +
+		`WHERE shard_id = ? ` +
+		`and type = ? ` +
+		`and domain_id = ? ` +
+		`and workflow_id = ? ` +
+		`and run_id = ? ` +
+		`and visibility_ts = ? ` +
+		`and task_id > ? ` +
+		`and task_id <= ?`
+
 	templateGetReplicationTasksQuery = `SELECT replication ` +
 		`FROM executions ` +
 		`WHERE shard_id = ? ` +
@@ -683,6 +738,10 @@ workflow_state = ? ` +
 
 	templateCompleteReplicationTaskQuery = templateCompleteTransferTaskQuery
 
+	templateCompleteCrossClusterTaskQuery = templateCompleteTransferTaskQuery
+
+	templateRangeCompleteCrossClusterTaskQuery = templateRangeCompleteTransferTaskQuery
+
 	templateRangeCompleteReplicationTaskQuery = templateRangeCompleteTransferTaskQuery
 
 	templateGetTimerTasksQuery = `SELECT timer ` +
@@ -704,6 +763,16 @@ workflow_state = ? ` +
 		`and visibility_ts = ? ` +
 		`and task_id = ?`
 
+	templateGetTimerTasksInclusiveQuery = `SELECT timer ` +
+		`FROM executions ` +
+		`WHERE shard_id = ? ` +
+		`and type = ?` +
+		`and domain_id = ? ` +
+		`and workflow_id = ?` +
+		`and run_id = ?` +
+		`and visibility_ts >= ? ` +
+		`and visibility_ts <= ?`
+
 	templateRangeCompleteTimerTaskQuery = `DELETE FROM executions ` +
 		`WHERE shard_id = ? ` +
 		`and type = ? ` +
@@ -730,6 +799,14 @@ func (d *cassandraStore) Close() {
 	}
 }
 
+// CloseWithContext releases the underlying resources like Close, but stops waiting once ctx is done
+func (d *cassandraStore) CloseWithContext(ctx context.Context) error {
+	if d.session != nil {
+		return d.session.CloseWithContext(ctx)
+	}
+	return nil
+}
+
 // NewWorkflowExecutionPersistence is used to create an instance of workflowExecutionManager implementation
 func NewWorkflowExecutionPersistence(
 	shardID int,
@@ -787,6 +864,7 @@ func (d *cassandraPersistence) CreateWorkflowExecution(
 			runID,
 			executionInfo.State,
 			executionInfo.CloseStatus,
+			executionInfo.HistorySize,
 			executionInfo.CreateRequestID,
 			startVersion,
 			lastWriteVersion,
@@ -844,8 +922,10 @@ func (d *cassandraPersistence) CreateWorkflowExecution(
 			if rowType == rowTypeShard {
 				if rangeID, ok := previous["range_id"].(int64); ok && rangeID != request.RangeID {
 					// CreateWorkflowExecution failed because rangeID was modified
-					return nil, &p.ShardOwnershipLostError{
-						ShardID: d.shardID,
+					return nil, &p.ShardRangeIDMismatchError{
+						ShardID:         d.shardID,
+						ExpectedRangeID: request.RangeID,
+						ActualRangeID:   rangeID,
 						Msg: fmt.Sprintf("Failed to create workflow execution.  Request RangeID: %v, Actual RangeID: %v",
 							request.RangeID, rangeID),
 					}
@@ -877,6 +957,9 @@ func (d *cassandraPersistence) CreateWorkflowExecution(
 							LastWriteVersion: lastWriteVersion,
 						}
 					}
+					if err := newCurrentWorkflowStillRunningError(request, msg, executionInfo.RunID, executionInfo.State, lastWriteVersion); err != nil {
+						return nil, err
+					}
 					return nil, &p.CurrentWorkflowConditionFailedError{Msg: msg}
 
 				}
@@ -890,6 +973,12 @@ func (d *cassandraPersistence) CreateWorkflowExecution(
 
 				msg := fmt.Sprintf("Workflow execution creation condition failed. WorkflowId: %v, CurrentRunID: %v, columns: (%v)",
 					executionInfo.WorkflowID, executionInfo.RunID, strings.Join(columns, ","))
+				if state, ok := previous["workflow_state"].(int); ok {
+					lastWriteVersion, _ := previous["workflow_last_write_version"].(int64)
+					if err := newCurrentWorkflowStillRunningError(request, msg, request.PreviousRunID, state, lastWriteVersion); err != nil {
+						return nil, err
+					}
+				}
 				return nil, &p.CurrentWorkflowConditionFailedError{Msg: msg}
 			} else if rowType == rowTypeExecution && runID == executionInfo.RunID {
 				msg := fmt.Sprintf("Workflow execution already running. WorkflowId: %v, RunId: %v, rangeID: %v",
@@ -922,13 +1011,35 @@ func (d *cassandraPersistence) CreateWorkflowExecution(
 	return &p.CreateWorkflowExecutionResponse{}, nil
 }
 
+// newCurrentWorkflowStillRunningError returns an error if reusing the workflow ID failed only because
+// the current run, which matches the expected run and last write version, is not closed, otherwise nil
+func newCurrentWorkflowStillRunningError(
+	request *p.InternalCreateWorkflowExecutionRequest,
+	msg string,
+	currentRunID string,
+	currentState int,
+	currentLastWriteVersion int64,
+) *p.CurrentWorkflowStillRunningError {
+	if request.Mode != p.CreateWorkflowModeWorkflowIDReuse ||
+		currentRunID != request.PreviousRunID ||
+		currentLastWriteVersion != request.PreviousLastWriteVersion ||
+		currentState == p.WorkflowStateCompleted {
+		return nil
+	}
+	return &p.CurrentWorkflowStillRunningError{
+		Msg:   msg,
+		RunID: currentRunID,
+		State: currentState,
+	}
+}
+
 func (d *cassandraPersistence) GetWorkflowExecution(
 	ctx context.Context,
 	request *p.InternalGetWorkflowExecutionRequest,
 ) (*p.InternalGetWorkflowExecutionResponse, error) {
 
 	execution := request.Execution
-	query := d.session.Query(templateGetWorkflowExecutionQuery,
+	query := d.session.Query(getWorkflowExecutionQuery(request),
 		d.shardID,
 		rowTypeExecution,
 		request.DomainID,
@@ -941,8 +1052,11 @@ func (d *cassandraPersistence) GetWorkflowExecution(
 	result := make(map[string]interface{})
 	if err := query.MapScan(result); err != nil {
 		if d.client.IsNotFoundError(err) {
-			return nil, &types.EntityNotExistsError{
-				Message: fmt.Sprintf("Workflow execution not found.  WorkflowId: %v, RunId: %v",
+			return nil, &p.WorkflowExecutionNotExistsError{
+				DomainID:   request.DomainID,
+				WorkflowID: execution.WorkflowID,
+				RunID:      execution.RunID,
+				Msg: fmt.Sprintf("Workflow execution not found.  WorkflowId: %v, RunId: %v",
 					execution.WorkflowID, execution.RunID),
 			}
 		}
@@ -959,63 +1073,111 @@ func (d *cassandraPersistence) GetWorkflowExecution(
 	state.ReplicationState = replicationState
 
 	activityInfos := make(map[int64]*p.InternalActivityInfo)
-	aMap := result["activity_map"].(map[int64]map[string]interface{})
-	for key, value := range aMap {
-		info := createActivityInfo(request.DomainID, value)
-		activityInfos[key] = info
+	if request.LoadsField(p.MutableStateFieldActivityInfos) {
+		aMap := result["activity_map"].(map[int64]map[string]interface{})
+		for key, value := range aMap {
+			info := createActivityInfo(request.DomainID, value)
+			activityInfos[key] = info
+		}
 	}
 	state.ActivityInfos = activityInfos
 
 	timerInfos := make(map[string]*p.TimerInfo)
-	tMap := result["timer_map"].(map[string]map[string]interface{})
-	for key, value := range tMap {
-		info := createTimerInfo(value)
-		timerInfos[key] = info
+	if request.LoadsField(p.MutableStateFieldTimerInfos) {
+		tMap := result["timer_map"].(map[string]map[string]interface{})
+		for key, value := range tMap {
+			info := createTimerInfo(value)
+			timerInfos[key] = info
+		}
 	}
 	state.TimerInfos = timerInfos
 
 	childExecutionInfos := make(map[int64]*p.InternalChildExecutionInfo)
-	cMap := result["child_executions_map"].(map[int64]map[string]interface{})
-	for key, value := range cMap {
-		info := createChildExecutionInfo(value)
-		childExecutionInfos[key] = info
+	if request.LoadsField(p.MutableStateFieldChildExecutionInfos) {
+		cMap := result["child_executions_map"].(map[int64]map[string]interface{})
+		for key, value := range cMap {
+			info := createChildExecutionInfo(value)
+			childExecutionInfos[key] = info
+		}
 	}
 	state.ChildExecutionInfos = childExecutionInfos
 
 	requestCancelInfos := make(map[int64]*p.RequestCancelInfo)
-	rMap := result["request_cancel_map"].(map[int64]map[string]interface{})
-	for key, value := range rMap {
-		info := createRequestCancelInfo(value)
-		requestCancelInfos[key] = info
+	if request.LoadsField(p.MutableStateFieldRequestCancelInfos) {
+		rMap := result["request_cancel_map"].(map[int64]map[string]interface{})
+		for key, value := range rMap {
+			info := createRequestCancelInfo(value)
+			requestCancelInfos[key] = info
+		}
 	}
 	state.RequestCancelInfos = requestCancelInfos
 
 	signalInfos := make(map[int64]*p.SignalInfo)
-	sMap := result["signal_map"].(map[int64]map[string]interface{})
-	for key, value := range sMap {
-		info := createSignalInfo(value)
-		signalInfos[key] = info
+	if request.LoadsField(p.MutableStateFieldSignalInfos) {
+		sMap := result["signal_map"].(map[int64]map[string]interface{})
+		for key, value := range sMap {
+			info := createSignalInfo(value)
+			signalInfos[key] = info
+		}
 	}
 	state.SignalInfos = signalInfos
 
 	signalRequestedIDs := make(map[string]struct{})
-	sList := mustConvertToSlice(result["signal_requested"])
-	for _, v := range sList {
-		signalRequestedIDs[v.(gocql.UUID).String()] = struct{}{}
+	if request.LoadsField(p.MutableStateFieldSignalRequestedIDs) {
+		sList := mustConvertToSlice(result["signal_requested"])
+		for _, v := range sList {
+			signalRequestedIDs[v.(gocql.UUID).String()] = struct{}{}
+		}
 	}
 	state.SignalRequestedIDs = signalRequestedIDs
 
-	eList := result["buffered_events_list"].([]map[string]interface{})
-	bufferedEventsBlobs := make([]*p.DataBlob, 0, len(eList))
-	for _, v := range eList {
-		blob := createHistoryEventBatchBlob(v)
-		bufferedEventsBlobs = append(bufferedEventsBlobs, blob)
+	var bufferedEventsBlobs []*p.DataBlob
+	if request.LoadsField(p.MutableStateFieldBufferedEvents) {
+		eList := result["buffered_events_list"].([]map[string]interface{})
+		bufferedEventsBlobs = make([]*p.DataBlob, 0, len(eList))
+		for _, v := range eList {
+			blob := createHistoryEventBatchBlob(v)
+			bufferedEventsBlobs = append(bufferedEventsBlobs, blob)
+		}
 	}
 	state.BufferedEvents = bufferedEventsBlobs
 
 	state.Checksum = createChecksum(result["checksum"].(map[string]interface{}))
 
-	return &p.InternalGetWorkflowExecutionResponse{State: state}, nil
+	return &p.InternalGetWorkflowExecutionResponse{
+		State:           state,
+		DBRecordVersion: result["db_record_version"].(int64),
+	}, nil
+}
+
+// getWorkflowExecutionQuery returns the query loading an execution row, only the columns which are not
+// excluded by the request are selected, so large collections are not read from disk when they are not needed
+func getWorkflowExecutionQuery(
+	request *p.InternalGetWorkflowExecutionRequest,
+) string {
+	// TODO: remove replication_state after all 2DC workflows complete
+	columns := []string{"execution", "replication_state", "buffered_replication_tasks_map", "version_histories",
+		"version_histories_encoding", "checksum",
+		// every write of the execution row rewrites the execution column, so its write time
+		// in microseconds serves as the version of the record
+		"writetime(execution) as db_record_version"}
+	for _, field := range []struct {
+		field  p.MutableStateField
+		column string
+	}{
+		{p.MutableStateFieldActivityInfos, "activity_map"},
+		{p.MutableStateFieldTimerInfos, "timer_map"},
+		{p.MutableStateFieldChildExecutionInfos, "child_executions_map"},
+		{p.MutableStateFieldRequestCancelInfos, "request_cancel_map"},
+		{p.MutableStateFieldSignalInfos, "signal_map"},
+		{p.MutableStateFieldSignalRequestedIDs, "signal_requested"},
+		{p.MutableStateFieldBufferedEvents, "buffered_events_list"},
+	} {
+		if request.LoadsField(field.field) {
+			columns = append(columns, field.column)
+		}
+	}
+	return fmt.Sprintf(templateGetWorkflowExecutionQuery, strings.Join(columns, ", "))
 }
 
 func (d *cassandraPersistence) UpdateWorkflowExecution(
@@ -1075,6 +1237,7 @@ func (d *cassandraPersistence) UpdateWorkflowExecution(
 				newRunID,
 				newExecutionInfo.State,
 				newExecutionInfo.CloseStatus,
+				newExecutionInfo.HistorySize,
 				newExecutionInfo.CreateRequestID,
 				newStartVersion,
 				newLastWriteVersion,
@@ -1092,6 +1255,7 @@ func (d *cassandraPersistence) UpdateWorkflowExecution(
 				executionInfo.CreateRequestID,
 				executionInfo.State,
 				executionInfo.CloseStatus,
+				executionInfo.HistorySize,
 				lastWriteVersion,
 				executionInfo.State,
 				d.shardID,
@@ -1184,6 +1348,7 @@ func (d *cassandraPersistence) ResetWorkflowExecution(
 		newExecutionInfo.CreateRequestID,
 		newExecutionInfo.State,
 		newExecutionInfo.CloseStatus,
+		newExecutionInfo.HistorySize,
 		lastWriteVersion,
 		newExecutionInfo.State,
 		d.shardID,
@@ -1315,6 +1480,7 @@ func (d *cassandraPersistence) ConflictResolveWorkflowExecution(
 		createRequestID := executionInfo.CreateRequestID
 		state := executionInfo.State
 		closeStatus := executionInfo.CloseStatus
+		historySize := executionInfo.HistorySize
 
 		if currentWorkflow != nil {
 			prevRunID = currentWorkflow.ExecutionInfo.RunID
@@ -1325,6 +1491,7 @@ func (d *cassandraPersistence) ConflictResolveWorkflowExecution(
 				createRequestID,
 				state,
 				closeStatus,
+				historySize,
 				lastWriteVersion,
 				state,
 				shardID,
@@ -1346,6 +1513,7 @@ func (d *cassandraPersistence) ConflictResolveWorkflowExecution(
 				createRequestID,
 				state,
 				closeStatus,
+				historySize,
 				lastWriteVersion,
 				state,
 				shardID,
@@ -1365,6 +1533,11 @@ func (d *cassandraPersistence) ConflictResolveWorkflowExecution(
 		}
 	}
 
+	if request.DryRun {
+		// the live path checks its conditions as part of the conditional batch, read them instead
+		return d.assertConflictResolveConditions(ctx, request, prevRunID)
+	}
+
 	if err := applyWorkflowSnapshotBatchAsReset(batch,
 		shardID,
 		&resetWorkflow); err != nil {
@@ -1413,6 +1586,113 @@ func (d *cassandraPersistence) ConflictResolveWorkflowExecution(
 	return nil
 }
 
+// assertConflictResolveConditions reads the rows the conditional batch of ConflictResolveWorkflowExecution
+// is applied to, and returns the error the batch would fail with
+func (d *cassandraPersistence) assertConflictResolveConditions(
+	ctx context.Context,
+	request *p.InternalConflictResolveWorkflowExecutionRequest,
+	prevRunID string,
+) error {
+
+	if err := d.assertRangeID(ctx, request.RangeID); err != nil {
+		return err
+	}
+	resetWorkflow := request.ResetWorkflowSnapshot
+	if err := d.assertNextEventID(ctx, resetWorkflow.ExecutionInfo, resetWorkflow.Condition); err != nil {
+		return err
+	}
+	if currentWorkflow := request.CurrentWorkflowMutation; currentWorkflow != nil {
+		if err := d.assertNextEventID(ctx, currentWorkflow.ExecutionInfo, currentWorkflow.Condition); err != nil {
+			return err
+		}
+	}
+	if newWorkflow := request.NewWorkflowSnapshot; newWorkflow != nil {
+		executionInfo := newWorkflow.ExecutionInfo
+		resp, err := d.IsWorkflowExecutionExists(ctx, &p.IsWorkflowExecutionExistsRequest{
+			DomainID:   executionInfo.DomainID,
+			WorkflowID: executionInfo.WorkflowID,
+			RunID:      executionInfo.RunID,
+		})
+		if err != nil {
+			return err
+		}
+		if resp.Exists {
+			return &p.ConditionFailedError{
+				Msg: fmt.Sprintf("Assertion on new workflow failed. Run ID already exists: %v", executionInfo.RunID),
+			}
+		}
+	}
+	if request.Mode == p.ConflictResolveWorkflowModeUpdateCurrent {
+		return d.assertCurrentExecution(ctx, resetWorkflow.ExecutionInfo.DomainID, resetWorkflow.ExecutionInfo.WorkflowID, prevRunID)
+	}
+	return nil
+}
+
+func (d *cassandraPersistence) assertRangeID(
+	ctx context.Context,
+	rangeID int64,
+) error {
+
+	query := d.session.Query(templateGetLeaseQuery,
+		d.shardID,
+		rowTypeShard,
+		rowTypeShardDomainID,
+		rowTypeShardWorkflowID,
+		rowTypeShardRunID,
+		defaultVisibilityTimestamp,
+		rowTypeShardTaskID,
+	).WithContext(ctx)
+
+	var actualRangeID int64
+	if err := query.Scan(&actualRangeID); err != nil {
+		return convertCommonErrors(d.client, "AssertRangeID", err)
+	}
+	if actualRangeID != rangeID {
+		return &p.ShardRangeIDMismatchError{
+			ShardID:         d.shardID,
+			ExpectedRangeID: rangeID,
+			ActualRangeID:   actualRangeID,
+			Msg: fmt.Sprintf("Assertion on shard range ID failed.  Request RangeID: %v, Actual RangeID: %v",
+				rangeID, actualRangeID),
+		}
+	}
+	return nil
+}
+
+func (d *cassandraPersistence) assertNextEventID(
+	ctx context.Context,
+	executionInfo *p.InternalWorkflowExecutionInfo,
+	condition int64,
+) error {
+
+	query := d.session.Query(templateGetWorkflowExecutionNextEventIDQuery,
+		d.shardID,
+		rowTypeExecution,
+		executionInfo.DomainID,
+		executionInfo.WorkflowID,
+		executionInfo.RunID,
+		defaultVisibilityTimestamp,
+		rowTypeExecutionTaskID,
+	).WithContext(ctx)
+
+	var nextEventID int64
+	if err := query.Scan(&nextEventID); err != nil {
+		if d.client.IsNotFoundError(err) {
+			return &p.ConditionFailedError{
+				Msg: fmt.Sprintf("Assertion on next event ID failed. Workflow execution not found: %v", executionInfo.RunID),
+			}
+		}
+		return convertCommonErrors(d.client, "AssertNextEventID", err)
+	}
+	if nextEventID != condition {
+		return &p.ConditionFailedError{
+			Msg: fmt.Sprintf("Assertion on next event ID failed.  Request Condition: %v, Actual Value: %v",
+				condition, nextEventID),
+		}
+	}
+	return nil
+}
+
 func (d *cassandraPersistence) getExecutionConditionalUpdateFailure(
 	previous map[string]interface{},
 	iter gocql.Iter,
@@ -1469,8 +1749,10 @@ GetFailureReasonLoop:
 	}
 
 	if rangeIDUnmatch {
-		return &p.ShardOwnershipLostError{
-			ShardID: d.shardID,
+		return &p.ShardRangeIDMismatchError{
+			ShardID:         d.shardID,
+			ExpectedRangeID: requestRangeID,
+			ActualRangeID:   actualRangeID,
 			Msg: fmt.Sprintf("Failed to update mutable state.  Request RangeID: %v, Actual RangeID: %v",
 				requestRangeID, actualRangeID),
 		}
@@ -1516,7 +1798,7 @@ func (d *cassandraPersistence) assertNotCurrentExecution(
 		DomainID:   domainID,
 		WorkflowID: workflowID,
 	}); err != nil {
-		if _, ok := err.(*types.EntityNotExistsError); ok {
+		if p.IsNotExistsError(err) {
 			// allow bypassing no current record
 			return nil
 		}
@@ -1530,6 +1812,29 @@ func (d *cassandraPersistence) assertNotCurrentExecution(
 	return nil
 }
 
+func (d *cassandraPersistence) assertCurrentExecution(
+	ctx context.Context,
+	domainID string,
+	workflowID string,
+	runID string,
+) error {
+
+	resp, err := d.GetCurrentExecution(ctx, &p.GetCurrentExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowID,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.RunID != runID {
+		return &p.CurrentWorkflowConditionFailedError{
+			Msg: fmt.Sprintf("Assertion on current record failed. Current run ID: %v, expected: %v", resp.RunID, runID),
+		}
+	}
+
+	return nil
+}
+
 func (d *cassandraPersistence) DeleteWorkflowExecution(
 	ctx context.Context,
 	request *p.DeleteWorkflowExecutionRequest,
@@ -1542,7 +1847,7 @@ func (d *cassandraPersistence) DeleteWorkflowExecution(
 		request.RunID,
 		defaultVisibilityTimestamp,
 		rowTypeExecutionTaskID,
-	).WithContext(ctx)
+	).Idempotent(true).WithContext(ctx)
 
 	err := query.Exec()
 	if err != nil {
@@ -1556,6 +1861,10 @@ func (d *cassandraPersistence) DeleteCurrentWorkflowExecution(
 	ctx context.Context,
 	request *p.DeleteCurrentWorkflowExecutionRequest,
 ) error {
+	if request.RangeID != 0 {
+		return d.deleteCurrentWorkflowExecutionWithRangeID(ctx, request)
+	}
+
 	query := d.session.Query(templateDeleteWorkflowExecutionCurrentRowQuery,
 		d.shardID,
 		rowTypeExecution,
@@ -1575,6 +1884,75 @@ func (d *cassandraPersistence) DeleteCurrentWorkflowExecution(
 	return nil
 }
 
+func (d *cassandraPersistence) deleteCurrentWorkflowExecutionWithRangeID(
+	ctx context.Context,
+	request *p.DeleteCurrentWorkflowExecutionRequest,
+) error {
+	batch := d.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(templateDeleteWorkflowExecutionCurrentRowQuery,
+		d.shardID,
+		rowTypeExecution,
+		request.DomainID,
+		request.WorkflowID,
+		permanentRunID,
+		defaultVisibilityTimestamp,
+		rowTypeExecutionTaskID,
+		request.RunID,
+	)
+
+	// Verifies that the RangeID has not changed
+	batch.Query(templateUpdateLeaseQuery,
+		request.RangeID,
+		d.shardID,
+		rowTypeShard,
+		rowTypeShardDomainID,
+		rowTypeShardWorkflowID,
+		rowTypeShardRunID,
+		defaultVisibilityTimestamp,
+		rowTypeShardTaskID,
+		request.RangeID,
+	)
+
+	previous := make(map[string]interface{})
+	applied, iter, err := d.session.MapExecuteBatchCAS(batch, previous)
+	defer func() {
+		if iter != nil {
+			iter.Close()
+		}
+	}()
+	if err != nil {
+		return convertCommonErrors(d.client, "DeleteWorkflowCurrentRow", err)
+	}
+
+	if !applied {
+		for {
+			rowType, ok := previous["type"].(int)
+			if !ok {
+				// This should never happen, as all our rows have the type field.
+				break
+			}
+			if rowType == rowTypeShard {
+				if rangeID, ok := previous["range_id"].(int64); ok && rangeID != request.RangeID {
+					return &p.ShardOwnershipLostError{
+						ShardID: d.shardID,
+						Msg: fmt.Sprintf("Failed to delete current workflow execution.  Request RangeID: %v, Actual RangeID: %v",
+							request.RangeID, rangeID),
+					}
+				}
+			}
+			previous = make(map[string]interface{})
+			if !iter.MapScan(previous) {
+				break
+			}
+		}
+		// the shard is still owned, so the current record points to another run by now
+		return &p.ConditionFailedError{
+			Msg: fmt.Sprintf("Failed to delete current workflow execution.  Request RunID: %v", request.RunID),
+		}
+	}
+	return nil
+}
+
 func (d *cassandraPersistence) GetCurrentExecution(
 	ctx context.Context,
 	request *p.GetCurrentExecutionRequest,
@@ -1589,12 +1967,17 @@ func (d *cassandraPersistence) GetCurrentExecution(
 		defaultVisibilityTimestamp,
 		rowTypeExecutionTaskID,
 	).WithContext(ctx)
+	if consistency, ok := toGocqlConsistency(request.Consistency); ok {
+		query = query.Consistency(consistency)
+	}
 
 	result := make(map[string]interface{})
 	if err := query.MapScan(result); err != nil {
 		if d.client.IsNotFoundError(err) {
-			return nil, &types.EntityNotExistsError{
-				Message: fmt.Sprintf("Workflow execution not found.  WorkflowId: %v",
+			return nil, &p.WorkflowExecutionNotExistsError{
+				DomainID:   request.DomainID,
+				WorkflowID: request.WorkflowID,
+				Msg: fmt.Sprintf("Workflow execution not found.  WorkflowId: %v",
 					request.WorkflowID),
 			}
 		}
@@ -1608,13 +1991,94 @@ func (d *cassandraPersistence) GetCurrentExecution(
 	if result["workflow_last_write_version"] != nil {
 		lastWriteVersion = result["workflow_last_write_version"].(int64)
 	}
-	return &p.GetCurrentExecutionResponse{
+	response := &p.GetCurrentExecutionResponse{
 		RunID:            currentRunID,
 		StartRequestID:   executionInfo.CreateRequestID,
 		State:            executionInfo.State,
 		CloseStatus:      executionInfo.CloseStatus,
 		LastWriteVersion: lastWriteVersion,
-	}, nil
+	}
+	if request.IncludeStats {
+		response.Stats = &p.ExecutionStats{HistorySize: executionInfo.HistorySize}
+		// current rows written before the history size was kept on them have no history size,
+		// a run always has history once it is created so only those rows read the run
+		if executionInfo.HistorySize == 0 {
+			stats, err := d.getExecutionStats(ctx, request.DomainID, request.WorkflowID, currentRunID)
+			if err != nil {
+				return nil, err
+			}
+			response.Stats = stats
+		}
+	}
+	return response, nil
+}
+
+// GetCurrentRunID only reads the current_run_id column of the current execution row
+func (d *cassandraPersistence) GetCurrentRunID(
+	ctx context.Context,
+	request *p.GetCurrentRunIDRequest,
+) (*p.GetCurrentRunIDResponse, error) {
+	query := d.session.Query(templateGetCurrentRunIDQuery,
+		d.shardID,
+		rowTypeExecution,
+		request.DomainID,
+		request.WorkflowID,
+		permanentRunID,
+		defaultVisibilityTimestamp,
+		rowTypeExecutionTaskID,
+	).WithContext(ctx)
+
+	result := make(map[string]interface{})
+	if err := query.MapScan(result); err != nil {
+		if d.client.IsNotFoundError(err) {
+			return nil, &p.WorkflowExecutionNotExistsError{
+				DomainID:   request.DomainID,
+				WorkflowID: request.WorkflowID,
+				Msg: fmt.Sprintf("Workflow execution not found.  WorkflowId: %v",
+					request.WorkflowID),
+			}
+		}
+
+		return nil, convertCommonErrors(d.client, "GetCurrentRunID", err)
+	}
+	return &p.GetCurrentRunIDResponse{RunID: result["current_run_id"].(gocql.UUID).String()}, nil
+}
+
+// getExecutionStats reads the stats of a run from the execution column of the run,
+// to avoid loading the rest of the mutable state
+func (d *cassandraPersistence) getExecutionStats(
+	ctx context.Context,
+	domainID string,
+	workflowID string,
+	runID string,
+) (*p.ExecutionStats, error) {
+	query := d.session.Query(fmt.Sprintf(templateGetWorkflowExecutionQuery, "execution"),
+		d.shardID,
+		rowTypeExecution,
+		domainID,
+		workflowID,
+		runID,
+		defaultVisibilityTimestamp,
+		rowTypeExecutionTaskID,
+	).WithContext(ctx)
+
+	result := make(map[string]interface{})
+	if err := query.MapScan(result); err != nil {
+		if d.client.IsNotFoundError(err) {
+			return nil, &p.WorkflowExecutionNotExistsError{
+				DomainID:   domainID,
+				WorkflowID: workflowID,
+				RunID:      runID,
+				Msg: fmt.Sprintf("Workflow execution not found.  WorkflowId: %v, RunId: %v",
+					workflowID, runID),
+			}
+		}
+
+		return nil, convertCommonErrors(d.client, "GetCurrentExecution", err)
+	}
+
+	info := createWorkflowExecutionInfo(result["execution"].(map[string]interface{}))
+	return &p.ExecutionStats{HistorySize: info.HistorySize}, nil
 }
 
 func (d *cassandraPersistence) ListCurrentExecutions(
@@ -1685,6 +2149,9 @@ func (d *cassandraPersistence) IsWorkflowExecutionExists(
 	return &p.IsWorkflowExecutionExistsResponse{Exists: true}, nil
 }
 
+// ListConcreteExecutions pages through the execution rows of the shard partition. The page state is a cursor
+// on the clustering key, so a row is never returned twice within a scan, no matter what is written to the
+// partition while it is in progress.
 func (d *cassandraPersistence) ListConcreteExecutions(
 	ctx context.Context,
 	request *p.ListConcreteExecutionsRequest,
@@ -1727,6 +2194,37 @@ func (d *cassandraPersistence) ListConcreteExecutions(
 	return response, nil
 }
 
+// CountWorkflowExecutions counts the concrete executions in the shard with a single aggregate query
+// over the shard partition, rows are counted server side and never returned to the client.
+// Current execution records share the execution row type but never set next_event_id, so they are
+// filtered out within the partition. The aggregate is not isolated from concurrent writes to the
+// partition, so the count is reported as approximate.
+func (d *cassandraPersistence) CountWorkflowExecutions(
+	ctx context.Context,
+	_ *p.CountExecutionsRequest,
+) (*p.CountExecutionsResponse, error) {
+	query := d.session.Query(templateCountWorkflowExecutionsQuery,
+		d.shardID,
+		rowTypeExecution,
+	).WithContext(ctx)
+
+	result := make(map[string]interface{})
+	if err := query.MapScan(result); err != nil {
+		return nil, convertCommonErrors(d.client, "CountWorkflowExecutions", err)
+	}
+
+	count, ok := result["count"].(int64)
+	if !ok {
+		return nil, &types.InternalServiceError{
+			Message: fmt.Sprintf("CountWorkflowExecutions operation failed. Unexpected count: %v", result["count"]),
+		}
+	}
+	return &p.CountExecutionsResponse{
+		Count:           count,
+		ApproximateOnly: true,
+	}, nil
+}
+
 func (d *cassandraPersistence) GetTransferTasks(
 	ctx context.Context,
 	request *p.GetTransferTasksRequest,
@@ -1833,7 +2331,7 @@ func (d *cassandraPersistence) CompleteTransferTask(
 		rowTypeTransferRunID,
 		defaultVisibilityTimestamp,
 		request.TaskID,
-	).WithContext(ctx)
+	).Idempotent(true).WithContext(ctx)
 
 	err := query.Exec()
 	if err != nil {
@@ -1856,7 +2354,7 @@ func (d *cassandraPersistence) RangeCompleteTransferTask(
 		defaultVisibilityTimestamp,
 		request.ExclusiveBeginTaskID,
 		request.InclusiveEndTaskID,
-	).WithContext(ctx)
+	).Idempotent(true).WithContext(ctx)
 
 	err := query.Exec()
 	if err != nil {
@@ -1866,6 +2364,129 @@ func (d *cassandraPersistence) RangeCompleteTransferTask(
 	return nil
 }
 
+func (d *cassandraPersistence) CompleteTransferTasks(
+	ctx context.Context,
+	request *p.CompleteTransferTasksRequest,
+) error {
+
+	// all transfer tasks of a shard live in the same partition, so unlogged batches are cheap,
+	// they are only chunked to stay below the cassandra batch size limit
+	for start := 0; start < len(request.TaskIDs); start += completeTransferTasksBatchSize {
+		end := start + completeTransferTasksBatchSize
+		if end > len(request.TaskIDs) {
+			end = len(request.TaskIDs)
+		}
+
+		batch := d.session.NewBatch(gocql.UnloggedBatch).WithContext(ctx)
+		for _, taskID := range request.TaskIDs[start:end] {
+			batch.Query(templateCompleteTransferTaskQuery,
+				d.shardID,
+				rowTypeTransferTask,
+				rowTypeTransferDomainID,
+				rowTypeTransferWorkflowID,
+				rowTypeTransferRunID,
+				defaultVisibilityTimestamp,
+				taskID,
+			)
+		}
+
+		if err := d.session.ExecuteBatch(batch); err != nil {
+			return convertCommonErrors(d.client, "CompleteTransferTasks", err)
+		}
+	}
+
+	return nil
+}
+
+func (d *cassandraPersistence) GetCrossClusterTasks(
+	ctx context.Context,
+	request *p.GetCrossClusterTasksRequest,
+) (*p.GetCrossClusterTasksResponse, error) {
+
+	// Reading cross-cluster tasks need to be quorum level consistent, otherwise we could loose task
+	query := d.session.Query(templateGetCrossClusterTasksQuery,
+		d.shardID,
+		rowTypeCrossClusterTask,
+		rowTypeCrossClusterDomainID,
+		request.TargetCluster,
+		rowTypeCrossClusterRunID,
+		defaultVisibilityTimestamp,
+		request.ReadLevel,
+		request.MaxReadLevel,
+	).PageSize(request.BatchSize).PageState(request.NextPageToken).WithContext(ctx)
+
+	iter := query.Iter()
+	if iter == nil {
+		return nil, &types.InternalServiceError{
+			Message: "GetCrossClusterTasks operation failed.  Not able to create query iterator.",
+		}
+	}
+
+	response := &p.GetCrossClusterTasksResponse{}
+	task := make(map[string]interface{})
+	for iter.MapScan(task) {
+		t := createCrossClusterTaskInfo(task["cross_cluster"].(map[string]interface{}))
+		// Reset task map to get it ready for next scan
+		task = make(map[string]interface{})
+
+		response.Tasks = append(response.Tasks, t)
+	}
+	nextPageToken := iter.PageState()
+	response.NextPageToken = make([]byte, len(nextPageToken))
+	copy(response.NextPageToken, nextPageToken)
+
+	if err := iter.Close(); err != nil {
+		return nil, convertCommonErrors(d.client, "GetCrossClusterTasks", err)
+	}
+
+	return response, nil
+}
+
+func (d *cassandraPersistence) CompleteCrossClusterTask(
+	ctx context.Context,
+	request *p.CompleteCrossClusterTaskRequest,
+) error {
+	query := d.session.Query(templateCompleteCrossClusterTaskQuery,
+		d.shardID,
+		rowTypeCrossClusterTask,
+		rowTypeCrossClusterDomainID,
+		request.TargetCluster,
+		rowTypeCrossClusterRunID,
+		defaultVisibilityTimestamp,
+		request.TaskID,
+	).Idempotent(true).WithContext(ctx)
+
+	err := query.Exec()
+	if err != nil {
+		return convertCommonErrors(d.client, "CompleteCrossClusterTask", err)
+	}
+
+	return nil
+}
+
+func (d *cassandraPersistence) RangeCompleteCrossClusterTask(
+	ctx context.Context,
+	request *p.RangeCompleteCrossClusterTaskRequest,
+) error {
+	query := d.session.Query(templateRangeCompleteCrossClusterTaskQuery,
+		d.shardID,
+		rowTypeCrossClusterTask,
+		rowTypeCrossClusterDomainID,
+		request.TargetCluster,
+		rowTypeCrossClusterRunID,
+		defaultVisibilityTimestamp,
+		request.ExclusiveBeginTaskID,
+		request.InclusiveEndTaskID,
+	).Idempotent(true).WithContext(ctx)
+
+	err := query.Exec()
+	if err != nil {
+		return convertCommonErrors(d.client, "RangeCompleteCrossClusterTask", err)
+	}
+
+	return nil
+}
+
 func (d *cassandraPersistence) CompleteReplicationTask(
 	ctx context.Context,
 	request *p.CompleteReplicationTaskRequest,
@@ -1878,7 +2499,7 @@ func (d *cassandraPersistence) CompleteReplicationTask(
 		rowTypeReplicationRunID,
 		defaultVisibilityTimestamp,
 		request.TaskID,
-	).WithContext(ctx)
+	).Idempotent(true).WithContext(ctx)
 
 	err := query.Exec()
 	if err != nil {
@@ -1901,7 +2522,7 @@ func (d *cassandraPersistence) RangeCompleteReplicationTask(
 		rowTypeReplicationRunID,
 		defaultVisibilityTimestamp,
 		request.InclusiveEndTaskID,
-	).WithContext(ctx)
+	).Idempotent(true).WithContext(ctx)
 
 	err := query.Exec()
 	if err != nil {
@@ -1924,7 +2545,7 @@ func (d *cassandraPersistence) CompleteTimerTask(
 		rowTypeTimerRunID,
 		ts,
 		request.TaskID,
-	).WithContext(ctx)
+	).Idempotent(true).WithContext(ctx)
 
 	err := query.Exec()
 	if err != nil {
@@ -1948,7 +2569,7 @@ func (d *cassandraPersistence) RangeCompleteTimerTask(
 		rowTypeTimerRunID,
 		start,
 		end,
-	).WithContext(ctx)
+	).Idempotent(true).WithContext(ctx)
 
 	err := query.Exec()
 	if err != nil {
@@ -1958,6 +2579,56 @@ func (d *cassandraPersistence) RangeCompleteTimerTask(
 	return nil
 }
 
+// CompleteTimerTasksForDomain deletes the timer tasks of a single domain. Timer rows are not partitioned
+// by domain, so this scans the timer index within the requested range and deletes the matching rows one by one.
+func (d *cassandraPersistence) CompleteTimerTasksForDomain(
+	ctx context.Context,
+	request *p.CompleteTimerTasksForDomainRequest,
+) (int, error) {
+	start := p.UnixNanoToDBTimestamp(request.InclusiveBeginTimestamp.UnixNano())
+	end := p.UnixNanoToDBTimestamp(request.InclusiveEndTimestamp.UnixNano())
+	iter := d.session.Query(templateGetTimerTasksInclusiveQuery,
+		d.shardID,
+		rowTypeTimerTask,
+		rowTypeTimerDomainID,
+		rowTypeTimerWorkflowID,
+		rowTypeTimerRunID,
+		start,
+		end,
+	).PageSize(completeTimerTasksForDomainPageSize).WithContext(ctx).Iter()
+	if iter == nil {
+		return 0, &types.InternalServiceError{
+			Message: "CompleteTimerTasksForDomain operation failed.  Not able to create query iterator.",
+		}
+	}
+
+	deleted := 0
+	task := make(map[string]interface{})
+	for iter.MapScan(task) {
+		t := createTimerTaskInfo(task["timer"].(map[string]interface{}))
+		// Reset task map to get it ready for next scan
+		task = make(map[string]interface{})
+
+		if t.DomainID != request.DomainID {
+			continue
+		}
+		if err := d.CompleteTimerTask(ctx, &p.CompleteTimerTaskRequest{
+			VisibilityTimestamp: t.VisibilityTimestamp,
+			TaskID:              t.TaskID,
+		}); err != nil {
+			iter.Close()
+			return deleted, err
+		}
+		deleted++
+	}
+
+	if err := iter.Close(); err != nil {
+		return deleted, convertCommonErrors(d.client, "CompleteTimerTasksForDomain", err)
+	}
+
+	return deleted, nil
+}
+
 func (d *cassandraPersistence) GetTimerIndexTasks(
 	ctx context.Context,
 	request *p.GetTimerIndexTasksRequest,
@@ -2006,14 +2677,58 @@ func (d *cassandraPersistence) PutReplicationTaskToDLQ(
 	ctx context.Context,
 	request *p.InternalPutReplicationTaskToDLQRequest,
 ) error {
-	task := request.TaskInfo
+	query := d.session.Query(templateCreateReplicationTaskQuery,
+		d.replicationDLQTaskQueryArgs(request.SourceClusterName, request.TaskInfo)...,
+	).Idempotent(true).WithContext(ctx)
+
+	err := query.Exec()
+	if err != nil {
+		return convertCommonErrors(d.client, "PutReplicationTaskToDLQ", err)
+	}
+
+	return nil
+}
+
+func (d *cassandraPersistence) PutReplicationTasksToDLQ(
+	ctx context.Context,
+	request *p.InternalPutReplicationTasksToDLQRequest,
+) error {
+
+	// all DLQ tasks of a shard live in the same partition, so unlogged batches are cheap,
+	// they are only chunked to stay below the cassandra batch size limit
+	for start := 0; start < len(request.TaskInfos); start += putReplicationDLQTasksBatchSize {
+		end := start + putReplicationDLQTasksBatchSize
+		if end > len(request.TaskInfos) {
+			end = len(request.TaskInfos)
+		}
+
+		batch := d.session.NewBatch(gocql.UnloggedBatch).WithContext(ctx)
+		for _, task := range request.TaskInfos[start:end] {
+			batch.Query(templateCreateReplicationTaskQuery,
+				d.replicationDLQTaskQueryArgs(request.SourceClusterName, task)...,
+			)
+		}
+
+		if err := d.session.ExecuteBatch(batch); err != nil {
+			return convertCommonErrors(d.client, "PutReplicationTasksToDLQ", err)
+		}
+	}
+
+	return nil
+}
 
+// replicationDLQTaskQueryArgs returns the arguments of templateCreateReplicationTaskQuery
+// for writing the given task to the DLQ of the source cluster
+func (d *cassandraPersistence) replicationDLQTaskQueryArgs(
+	sourceClusterName string,
+	task *p.InternalReplicationTaskInfo,
+) []interface{} {
 	// Use source cluster name as the workflow id for replication dlq
-	query := d.session.Query(templateCreateReplicationTaskQuery,
+	return []interface{}{
 		d.shardID,
 		rowTypeDLQ,
 		rowTypeDLQDomainID,
-		request.SourceClusterName,
+		sourceClusterName,
 		rowTypeDLQRunID,
 		task.DomainID,
 		task.WorkflowID,
@@ -2031,14 +2746,7 @@ func (d *cassandraPersistence) PutReplicationTaskToDLQ(
 		defaultVisibilityTimestamp,
 		defaultVisibilityTimestamp,
 		task.TaskID,
-	).WithContext(ctx)
-
-	err := query.Exec()
-	if err != nil {
-		return convertCommonErrors(d.client, "PutReplicationTaskToDLQ", err)
 	}
-
-	return nil
 }
 
 func (d *cassandraPersistence) GetReplicationTasksFromDLQ(
@@ -2098,7 +2806,7 @@ func (d *cassandraPersistence) DeleteReplicationTaskFromDLQ(
 		rowTypeDLQRunID,
 		defaultVisibilityTimestamp,
 		request.TaskID,
-	).WithContext(ctx)
+	).Idempotent(true).WithContext(ctx)
 
 	err := query.Exec()
 	if err != nil {
@@ -2122,7 +2830,7 @@ func (d *cassandraPersistence) RangeDeleteReplicationTaskFromDLQ(
 		defaultVisibilityTimestamp,
 		request.ExclusiveBeginTaskID,
 		request.InclusiveEndTaskID,
-	).WithContext(ctx)
+	).Idempotent(true).WithContext(ctx)
 
 	err := query.Exec()
 	if err != nil {
@@ -2132,6 +2840,40 @@ func (d *cassandraPersistence) RangeDeleteReplicationTaskFromDLQ(
 	return nil
 }
 
+func (d *cassandraPersistence) DeleteReplicationTasksFromDLQ(
+	ctx context.Context,
+	request *p.DeleteReplicationTasksFromDLQRequest,
+) error {
+
+	// all DLQ tasks of a source cluster live in the same partition, so unlogged batches are cheap,
+	// they are only chunked to stay below the cassandra batch size limit
+	for start := 0; start < len(request.TaskIDs); start += deleteReplicationDLQTasksBatchSize {
+		end := start + deleteReplicationDLQTasksBatchSize
+		if end > len(request.TaskIDs) {
+			end = len(request.TaskIDs)
+		}
+
+		batch := d.session.NewBatch(gocql.UnloggedBatch).WithContext(ctx)
+		for _, taskID := range request.TaskIDs[start:end] {
+			batch.Query(templateCompleteReplicationTaskQuery,
+				d.shardID,
+				rowTypeDLQ,
+				rowTypeDLQDomainID,
+				request.SourceClusterName,
+				rowTypeDLQRunID,
+				defaultVisibilityTimestamp,
+				taskID,
+			)
+		}
+
+		if err := d.session.ExecuteBatch(batch); err != nil {
+			return convertCommonErrors(d.client, "DeleteReplicationTasksFromDLQ", err)
+		}
+	}
+
+	return nil
+}
+
 func (d *cassandraPersistence) CreateFailoverMarkerTasks(
 	ctx context.Context,
 	request *p.CreateFailoverMarkersRequest,
@@ -2185,9 +2927,11 @@ func (d *cassandraPersistence) CreateFailoverMarkerTasks(
 		if rowType == rowTypeShard {
 			if rangeID, ok := previous["range_id"].(int64); ok && rangeID != request.RangeID {
 				// CreateWorkflowExecution failed because rangeID was modified
-				return &p.ShardOwnershipLostError{
-					ShardID: d.shardID,
-					Msg: fmt.Sprintf("Failed to create workflow execution.  Request RangeID: %v, Actual RangeID: %v",
+				return &p.ShardRangeIDMismatchError{
+					ShardID:         d.shardID,
+					ExpectedRangeID: request.RangeID,
+					ActualRangeID:   rangeID,
+					Msg: fmt.Sprintf("Failed to create failover marker tasks.  Request RangeID: %v, Actual RangeID: %v",
 						request.RangeID, rangeID),
 				}
 			}
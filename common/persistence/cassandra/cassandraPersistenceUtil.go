@@ -881,6 +881,7 @@ func createOrUpdateCurrentExecution(
 	runID string,
 	state int,
 	closeStatus int,
+	historySize int64,
 	createRequestID string,
 	startVersion int64,
 	lastWriteVersion int64,
@@ -896,6 +897,7 @@ func createOrUpdateCurrentExecution(
 			createRequestID,
 			state,
 			closeStatus,
+			historySize,
 			lastWriteVersion,
 			state,
 			shardID,
@@ -914,6 +916,7 @@ func createOrUpdateCurrentExecution(
 			createRequestID,
 			state,
 			closeStatus,
+			historySize,
 			lastWriteVersion,
 			state,
 			shardID,
@@ -941,6 +944,7 @@ func createOrUpdateCurrentExecution(
 			createRequestID,
 			state,
 			closeStatus,
+			historySize,
 			lastWriteVersion,
 			state,
 		)
@@ -1704,6 +1708,52 @@ func createTransferTaskInfo(
 	return info
 }
 
+func createCrossClusterTaskInfo(
+	result map[string]interface{},
+) *p.CrossClusterTaskInfo {
+
+	info := &p.CrossClusterTaskInfo{}
+	for k, v := range result {
+		switch k {
+		case "domain_id":
+			info.DomainID = v.(gocql.UUID).String()
+		case "workflow_id":
+			info.WorkflowID = v.(string)
+		case "run_id":
+			info.RunID = v.(gocql.UUID).String()
+		case "visibility_ts":
+			info.VisibilityTimestamp = v.(time.Time)
+		case "task_id":
+			info.TaskID = v.(int64)
+		case "target_cluster":
+			info.TargetCluster = v.(string)
+		case "target_domain_id":
+			info.TargetDomainID = v.(gocql.UUID).String()
+		case "target_workflow_id":
+			info.TargetWorkflowID = v.(string)
+		case "target_run_id":
+			info.TargetRunID = v.(gocql.UUID).String()
+			if info.TargetRunID == p.TransferTaskTransferTargetRunID {
+				info.TargetRunID = ""
+			}
+		case "target_child_workflow_only":
+			info.TargetChildWorkflowOnly = v.(bool)
+		case "task_list":
+			info.TaskList = v.(string)
+		case "type":
+			info.TaskType = v.(int)
+		case "schedule_id":
+			info.ScheduleID = v.(int64)
+		case "record_visibility":
+			info.RecordVisibility = v.(bool)
+		case "version":
+			info.Version = v.(int64)
+		}
+	}
+
+	return info
+}
+
 func createReplicationTaskInfo(
 	result map[string]interface{},
 ) *p.InternalReplicationTaskInfo {
@@ -2199,6 +2249,33 @@ func createChecksum(result map[string]interface{}) checksum.Checksum {
 	return csum
 }
 
+// toGocqlConsistency returns the gocql consistency level of the read consistency,
+// false is returned if the session default should be used
+func toGocqlConsistency(consistency p.ReadConsistency) (gocql.Consistency, bool) {
+	switch consistency {
+	case p.ReadConsistencyLocalQuorum:
+		return gocql.LocalQuorum, true
+	case p.ReadConsistencyQuorum:
+		return gocql.Quorum, true
+	case p.ReadConsistencyLocalSerial:
+		return gocql.LocalSerialRead, true
+	case p.ReadConsistencySerial:
+		return gocql.SerialRead, true
+	default:
+		return gocql.LocalQuorum, false
+	}
+}
+
+// isIdempotentWriteType returns whether a write which timed out with the given write type can be safely retried.
+// Lightweight transactions and counter updates are not, as the mutation may already have been applied.
+func isIdempotentWriteType(writeType string) bool {
+	switch writeType {
+	case gocql.WriteTypeCAS, gocql.WriteTypeCounter:
+		return false
+	}
+	return true
+}
+
 func convertCommonErrors(
 	errChecker gocql.ErrorChecker,
 	operation string,
@@ -2211,7 +2288,12 @@ func convertCommonErrors(
 	}
 
 	if errChecker.IsTimeoutError(err) {
-		return &p.TimeoutError{Msg: fmt.Sprintf("%v timed out. Error: %v", operation, err)}
+		timeoutErr := &p.TimeoutError{Msg: fmt.Sprintf("%v timed out. Error: %v", operation, err)}
+		if writeType, ok := errChecker.WriteTimeoutType(err); ok {
+			timeoutErr.WriteType = writeType
+			timeoutErr.Idempotent = isIdempotentWriteType(writeType)
+		}
+		return timeoutErr
 	}
 
 	if errChecker.IsThrottlingError(err) {
@@ -0,0 +1,83 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cassandra
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	p "github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra/gocql"
+)
+
+func TestConvertCommonErrorsWriteTimeout(t *testing.T) {
+	testCases := []struct {
+		writeType  string
+		idempotent bool
+	}{
+		{writeType: gocql.WriteTypeSimple, idempotent: true},
+		{writeType: gocql.WriteTypeBatch, idempotent: true},
+		{writeType: gocql.WriteTypeUnloggedBatch, idempotent: true},
+		{writeType: gocql.WriteTypeBatchLog, idempotent: true},
+		{writeType: gocql.WriteTypeView, idempotent: true},
+		{writeType: gocql.WriteTypeCDC, idempotent: true},
+		{writeType: gocql.WriteTypeCAS, idempotent: false},
+		{writeType: gocql.WriteTypeCounter, idempotent: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.writeType, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+			errChecker := gocql.NewMockErrorChecker(controller)
+			timeout := errors.New("write timeout")
+			errChecker.EXPECT().IsNotFoundError(timeout).Return(false)
+			errChecker.EXPECT().IsTimeoutError(timeout).Return(true)
+			errChecker.EXPECT().WriteTimeoutType(timeout).Return(tc.writeType, true)
+
+			err := convertCommonErrors(errChecker, "test", timeout)
+			timeoutErr, ok := err.(*p.TimeoutError)
+			assert.True(t, ok)
+			assert.Equal(t, tc.writeType, timeoutErr.WriteType)
+			assert.Equal(t, tc.idempotent, timeoutErr.Idempotent)
+			assert.Equal(t, tc.idempotent, p.IsTransientError(err))
+		})
+	}
+}
+
+func TestConvertCommonErrorsTimeoutWithoutWriteType(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	errChecker := gocql.NewMockErrorChecker(controller)
+	timeout := errors.New("read timeout")
+	errChecker.EXPECT().IsNotFoundError(timeout).Return(false)
+	errChecker.EXPECT().IsTimeoutError(timeout).Return(true)
+	errChecker.EXPECT().WriteTimeoutType(timeout).Return("", false)
+
+	err := convertCommonErrors(errChecker, "test", timeout)
+	timeoutErr, ok := err.(*p.TimeoutError)
+	assert.True(t, ok)
+	assert.Empty(t, timeoutErr.WriteType)
+	assert.True(t, p.IsTransientError(err))
+}
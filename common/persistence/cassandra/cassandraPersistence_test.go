@@ -0,0 +1,104 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cassandra
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	p "github.com/uber/cadence/common/persistence"
+)
+
+func TestGetWorkflowExecutionQuery(t *testing.T) {
+	selectedColumns := func(query string) []string {
+		start := strings.Index(query, "SELECT ")
+		end := strings.Index(query, " FROM ")
+		require.True(t, start >= 0 && end > start, query)
+		return strings.Split(query[start+len("SELECT "):end], ", ")
+	}
+	mapColumns := []string{"activity_map", "timer_map", "child_executions_map", "request_cancel_map",
+		"signal_map", "signal_requested", "buffered_events_list"}
+
+	testCases := []struct {
+		name     string
+		request  *p.InternalGetWorkflowExecutionRequest
+		excluded []string
+	}{
+		{
+			name:    "whole mutable state",
+			request: &p.InternalGetWorkflowExecutionRequest{},
+		},
+		{
+			name: "exclude buffered events and activity infos",
+			request: &p.InternalGetWorkflowExecutionRequest{
+				ExcludeBufferedEvents: true,
+				ExcludeActivityInfos:  true,
+			},
+			excluded: []string{"activity_map", "buffered_events_list"},
+		},
+		{
+			name: "exclude buffered events",
+			request: &p.InternalGetWorkflowExecutionRequest{
+				ExcludeBufferedEvents: true,
+			},
+			excluded: []string{"buffered_events_list"},
+		},
+		{
+			name: "selected fields",
+			request: &p.InternalGetWorkflowExecutionRequest{
+				Fields: []p.MutableStateField{p.MutableStateFieldTimerInfos, p.MutableStateFieldActivityInfos},
+			},
+			excluded: []string{"child_executions_map", "request_cancel_map", "signal_map", "signal_requested",
+				"buffered_events_list"},
+		},
+		{
+			name: "excluded field wins over selected fields",
+			request: &p.InternalGetWorkflowExecutionRequest{
+				Fields:               []p.MutableStateField{p.MutableStateFieldActivityInfos},
+				ExcludeActivityInfos: true,
+			},
+			excluded: mapColumns,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			columns := selectedColumns(getWorkflowExecutionQuery(tc.request))
+			// the execution row itself is always loaded
+			assert.Contains(t, columns, "execution")
+			assert.Contains(t, columns, "checksum")
+			for _, column := range mapColumns {
+				excluded := false
+				for _, e := range tc.excluded {
+					excluded = excluded || e == column
+				}
+				if excluded {
+					assert.NotContains(t, columns, column)
+				} else {
+					assert.Contains(t, columns, column)
+				}
+			}
+		})
+	}
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra"
@@ -48,13 +49,19 @@ func (q *nosqlQueue) Close() {
 	q.db.Close()
 }
 
+// CloseWithContext closes the queue like Close, but stops waiting once ctx is done
+func (q *nosqlQueue) CloseWithContext(ctx context.Context) error {
+	return q.db.CloseWithContext(ctx)
+}
+
 func newQueue(
 	cfg config.Cassandra,
 	logger log.Logger,
+	metricsClient metrics.Client,
 	queueType persistence.QueueType,
 ) (persistence.Queue, error) {
 	// TODO hardcoding to Cassandra for now, will switch to dynamically loading later
-	db, err := cassandra.NewCassandraDB(cfg, logger)
+	db, err := cassandra.NewCassandraDB(cfg, logger, metricsClient)
 	if err != nil {
 		return nil, err
 	}
@@ -108,6 +115,55 @@ func (q *nosqlQueue) EnqueueMessage(
 	return err
 }
 
+func (q *nosqlQueue) EnqueueMessageWithID(
+	ctx context.Context,
+	messagePayload []byte,
+	messageID int64,
+) error {
+	_, err := q.tryEnqueue(ctx, q.queueType, messageID, messagePayload)
+	if _, ok := err.(*persistence.ConditionFailedError); ok {
+		return &persistence.QueueMessageIDConflictError{
+			QueueType: q.queueType,
+			MessageID: messageID,
+			Msg:       fmt.Sprintf("message ID %v exists in queue %v", messageID, q.queueType),
+		}
+	}
+	return err
+}
+
+func (q *nosqlQueue) EnqueueMessages(
+	ctx context.Context,
+	messagePayloads [][]byte,
+) ([]int64, error) {
+	lastMessageID, err := q.getLastMessageID(ctx, q.queueType)
+	if err != nil {
+		return nil, err
+	}
+
+	messageIDs := make([]int64, 0, len(messagePayloads))
+	rows := make([]*nosqlplugin.QueueMessageRow, 0, len(messagePayloads))
+	for i, payload := range messagePayloads {
+		messageID := lastMessageID + 1 + int64(i)
+		messageIDs = append(messageIDs, messageID)
+		rows = append(rows, &nosqlplugin.QueueMessageRow{
+			QueueType: q.queueType,
+			ID:        messageID,
+			Payload:   payload,
+		})
+	}
+
+	if err := q.db.InsertIntoQueueInBatch(ctx, rows); err != nil {
+		if q.db.IsConditionFailedError(err) {
+			return nil, &persistence.ConditionFailedError{
+				Msg: fmt.Sprintf("message IDs %v to %v overlap existing messages in queue, none of the %v messages were enqueued",
+					messageIDs[0], messageIDs[len(messageIDs)-1], len(messageIDs)),
+			}
+		}
+		return nil, convertCommonErrors(q.db, fmt.Sprintf("EnqueueMessages, Type: %v", q.queueType), err)
+	}
+	return messageIDs, nil
+}
+
 func (q *nosqlQueue) EnqueueMessageToDLQ(
 	ctx context.Context,
 	messagePayload []byte,
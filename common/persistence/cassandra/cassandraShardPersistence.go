@@ -29,6 +29,7 @@ import (
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
 	p "github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra/gocql"
@@ -59,8 +60,8 @@ const (
 		`}`
 
 	templateCreateShardQuery = `INSERT INTO executions (` +
-		`shard_id, type, domain_id, workflow_id, run_id, visibility_ts, task_id, shard, range_id)` +
-		`VALUES(?, ?, ?, ?, ?, ?, ?, ` + templateShardType + `, ?) IF NOT EXISTS`
+		`shard_id, type, domain_id, workflow_id, run_id, visibility_ts, task_id, shard, range_id, shard_owner)` +
+		`VALUES(?, ?, ?, ?, ?, ?, ?, ` + templateShardType + `, ?, ?) IF NOT EXISTS`
 
 	templateGetShardQuery = `SELECT shard, range_id ` +
 		`FROM executions ` +
@@ -72,8 +73,18 @@ const (
 		`and visibility_ts = ? ` +
 		`and task_id = ?`
 
+	templateListShardsQuery = `SELECT shard_id, shard, range_id ` +
+		`FROM executions ` +
+		`WHERE type = ? ` +
+		`and domain_id = ? ` +
+		`and workflow_id = ? ` +
+		`and run_id = ? ` +
+		`and visibility_ts = ? ` +
+		`and task_id = ? ` +
+		`ALLOW FILTERING`
+
 	templateUpdateShardQuery = `UPDATE executions ` +
-		`SET shard = ` + templateShardType + `, range_id = ? ` +
+		`SET shard = ` + templateShardType + `, range_id = ?, shard_owner = ? ` +
 		`WHERE shard_id = ? ` +
 		`and type = ? ` +
 		`and domain_id = ? ` +
@@ -83,6 +94,10 @@ const (
 		`and task_id = ? ` +
 		`IF range_id = ?`
 
+	// the owner field of the frozen shard type can't be used in a condition, so the owner is also
+	// written to the shard_owner column, which is only set by schema version 0.34 and later
+	templateUpdateShardWithOwnerQuery = templateUpdateShardQuery + ` and shard_owner = ?`
+
 	templateUpdateRangeIDQuery = `UPDATE executions ` +
 		`SET range_id = ? ` +
 		`WHERE shard_id = ? ` +
@@ -111,8 +126,9 @@ func newShardPersistence(
 	cfg config.Cassandra,
 	clusterName string,
 	logger log.Logger,
+	metricsClient metrics.Client,
 ) (p.ShardStore, error) {
-	session, err := cassandra.CreateSession(cfg)
+	session, err := cassandra.CreateSession(cfg, logger, metricsClient)
 	if err != nil {
 		return nil, err
 	}
@@ -184,6 +200,7 @@ func (d *cassandraShardPersistence) CreateShard(
 		markerData,
 		markerEncoding,
 		shardInfo.RangeID,
+		shardInfo.Owner,
 	).WithContext(ctx)
 
 	previous := make(map[string]interface{})
@@ -263,6 +280,62 @@ func (d *cassandraShardPersistence) GetShard(
 	return &p.InternalGetShardResponse{ShardInfo: info}, nil
 }
 
+// ListShards reads the shard rows of the executions table, the shard rows live one per partition
+// so a page may hold fewer than PageSize shards while there are still more pages to read.
+// Unlike GetShard, a rangeID column behind the shard value is not repaired here.
+func (d *cassandraShardPersistence) ListShards(
+	ctx context.Context,
+	request *p.ListShardsRequest,
+) (*p.InternalListShardsResponse, error) {
+	query := d.session.Query(templateListShardsQuery,
+		rowTypeShard,
+		rowTypeShardDomainID,
+		rowTypeShardWorkflowID,
+		rowTypeShardRunID,
+		defaultVisibilityTimestamp,
+		rowTypeShardTaskID,
+	).PageSize(request.PageSize).PageState(request.PageToken).WithContext(ctx)
+
+	iter := query.Iter()
+	if iter == nil {
+		return nil, &types.InternalServiceError{
+			Message: "ListShards operation failed. Not able to create query iterator.",
+		}
+	}
+
+	response := &p.InternalListShardsResponse{}
+	result := make(map[string]interface{})
+	for iter.MapScan(result) {
+		rangeID, ok := result["range_id"].(int64)
+		if !ok {
+			iter.Close()
+			return nil, &types.InternalServiceError{
+				Message: fmt.Sprintf("ListShards operation failed. Unexpected range_id: %v", result["range_id"]),
+			}
+		}
+		shard, ok := result["shard"].(map[string]interface{})
+		if !ok {
+			iter.Close()
+			return nil, &types.InternalServiceError{
+				Message: fmt.Sprintf("ListShards operation failed. Unexpected shard value for ShardId: %v", result["shard_id"]),
+			}
+		}
+		if shardInfoRangeID, ok := shard["range_id"].(int64); ok && shardInfoRangeID > rangeID {
+			rangeID = shardInfoRangeID
+		}
+		response.ShardInfos = append(response.ShardInfos, createShardInfo(d.currentClusterName, rangeID, shard))
+		result = make(map[string]interface{})
+	}
+	nextPageToken := iter.PageState()
+	response.NextPageToken = make([]byte, len(nextPageToken))
+	copy(response.NextPageToken, nextPageToken)
+
+	if err := iter.Close(); err != nil {
+		return nil, convertCommonErrors(d.client, "ListShards", err)
+	}
+	return response, nil
+}
+
 func (d *cassandraShardPersistence) updateRangeID(
 	ctx context.Context,
 	shardID int,
@@ -313,7 +386,7 @@ func (d *cassandraShardPersistence) UpdateShard(
 	transferPQS, transferPQSEncoding := p.FromDataBlob(shardInfo.TransferProcessingQueueStates)
 	timerPQS, timerPQSEncoding := p.FromDataBlob(shardInfo.TimerProcessingQueueStates)
 
-	query := d.session.Query(templateUpdateShardQuery,
+	values := []interface{}{
 		shardInfo.ShardID,
 		shardInfo.Owner,
 		shardInfo.RangeID,
@@ -334,6 +407,7 @@ func (d *cassandraShardPersistence) UpdateShard(
 		markerData,
 		markerEncoding,
 		shardInfo.RangeID,
+		shardInfo.Owner,
 		shardInfo.ShardID,
 		rowTypeShard,
 		rowTypeShardDomainID,
@@ -342,7 +416,13 @@ func (d *cassandraShardPersistence) UpdateShard(
 		defaultVisibilityTimestamp,
 		rowTypeShardTaskID,
 		request.PreviousRangeID,
-	).WithContext(ctx)
+	}
+	queryTemplate := templateUpdateShardQuery
+	if request.PreviousOwner != "" {
+		queryTemplate = templateUpdateShardWithOwnerQuery
+		values = append(values, request.PreviousOwner)
+	}
+	query := d.session.Query(queryTemplate, values...).WithContext(ctx)
 
 	previous := make(map[string]interface{})
 	applied, err := query.MapScanCAS(previous)
@@ -23,12 +23,15 @@ package cassandra
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
 	p "github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra/gocql"
@@ -53,6 +56,8 @@ const (
 const (
 	taskListTaskID = -12345
 	initialRangeID = 1 // Id of the first range of a new task list
+
+	getOrphanTasksMaxPartitions = 100 // Max number of task list partitions scanned per GetOrphanTasks call
 )
 
 const (
@@ -97,6 +102,20 @@ const (
 		`and type = ? ` +
 		`and task_id = ?`
 
+	templateGetTaskIDsQuery = `SELECT task_id ` +
+		`FROM tasks ` +
+		`WHERE domain_id = ? ` +
+		`and task_list_name = ? ` +
+		`and task_list_type = ? ` +
+		`and type = ? ` +
+		`and task_id > ?`
+
+	templateGetTaskListPartitionsQuery = `SELECT DISTINCT domain_id, task_list_name, task_list_type ` +
+		`FROM tasks`
+
+	templateGetTaskListPartitionsAfterQuery = templateGetTaskListPartitionsQuery + ` ` +
+		`WHERE token(domain_id, task_list_name, task_list_type) > token(?, ?, ?)`
+
 	templateCompleteTasksLessThanQuery = `DELETE FROM tasks ` +
 		`WHERE domain_id = ? ` +
 		`AND task_list_name = ? ` +
@@ -114,6 +133,16 @@ const (
 		`and type = ? ` +
 		`and task_id = ?`
 
+	templateGetTaskListIdempotencyKeyQuery = `SELECT ` +
+		`range_id, ` +
+		`last_idempotency_key ` +
+		`FROM tasks ` +
+		`WHERE domain_id = ? ` +
+		`and task_list_name = ? ` +
+		`and task_list_type = ? ` +
+		`and type = ? ` +
+		`and task_id = ?`
+
 	templateInsertTaskListQuery = `INSERT INTO tasks (` +
 		`domain_id, ` +
 		`task_list_name, ` +
@@ -134,6 +163,18 @@ const (
 		`and task_id = ? ` +
 		`IF range_id = ?`
 
+	// TTL 0 keeps the cells forever, sticky task lists pass their TTL so the key expires with the lease
+	templateUpdateTaskListWithIdempotencyKeyQuery = `UPDATE tasks USING TTL ? SET ` +
+		`range_id = ?, ` +
+		`task_list = ` + templateTaskListType + ", " +
+		`last_idempotency_key = ? ` +
+		`WHERE domain_id = ? ` +
+		`and task_list_name = ? ` +
+		`and task_list_type = ? ` +
+		`and type = ? ` +
+		`and task_id = ? ` +
+		`IF range_id = ?`
+
 	templateUpdateTaskListQueryWithTTLPart1 = ` INSERT INTO tasks (` +
 		`domain_id, ` +
 		`task_list_name, ` +
@@ -167,8 +208,9 @@ var _ p.TaskStore = (*cassandraTaskPersistence)(nil)
 func newTaskPersistence(
 	cfg config.Cassandra,
 	logger log.Logger,
+	metricsClient metrics.Client,
 ) (p.TaskStore, error) {
-	session, err := cassandra.CreateSession(cfg)
+	session, err := cassandra.CreateSession(cfg, logger, metricsClient)
 	if err != nil {
 		return nil, err
 	}
@@ -183,11 +225,146 @@ func newTaskPersistence(
 	}, nil
 }
 
+// GetOrphanTasks scans the task list partitions in token order and returns the tasks of
+// partitions without a task list row. At most getOrphanTasksMaxPartitions partitions are scanned
+// per call, so a page may hold fewer than Limit tasks while the next page token is still set.
+// The page token is the key of the last returned task, or of the last scanned partition with
+// a task ID of math.MaxInt64 if the partition is done.
 func (d *cassandraTaskPersistence) GetOrphanTasks(ctx context.Context, request *p.GetOrphanTasksRequest) (*p.GetOrphanTasksResponse, error) {
-	// TODO: It's unclear if this's necessary or useful for Cassandra
-	return nil, &types.InternalServiceError{
-		Message: "Unimplemented call to GetOrphanTasks for Cassandra",
+	if request.Limit <= 0 {
+		return nil, &p.InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("GetOrphanTasks requires a positive limit, got %v", request.Limit),
+		}
+	}
+
+	var tasks []*p.TaskKey
+	var partitions gocql.Iter
+	if len(request.NextPageToken) == 0 {
+		partitions = d.session.Query(templateGetTaskListPartitionsQuery).
+			PageSize(getOrphanTasksMaxPartitions).WithContext(ctx).Iter()
+	} else {
+		var lastTask p.TaskKey
+		if err := json.Unmarshal(request.NextPageToken, &lastTask); err != nil {
+			return nil, &types.BadRequestError{
+				Message: fmt.Sprintf("GetOrphanTasks: invalid next page token: %v", err),
+			}
+		}
+
+		// finish the partition the previous page stopped in first
+		if lastTask.TaskID != math.MaxInt64 {
+			var err error
+			tasks, err = d.appendOrphanTasks(ctx, tasks, request.Limit, lastTask.DomainID, lastTask.TaskListName, lastTask.TaskType, lastTask.TaskID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		partitions = d.session.Query(templateGetTaskListPartitionsAfterQuery,
+			lastTask.DomainID,
+			lastTask.TaskListName,
+			lastTask.TaskType,
+		).PageSize(getOrphanTasksMaxPartitions).WithContext(ctx).Iter()
+	}
+	if partitions == nil {
+		return nil, &types.InternalServiceError{
+			Message: "GetOrphanTasks operation failed.  Not able to create query iterator.",
+		}
+	}
+
+	var domainID, taskListName string
+	var taskListType int
+	var lastPartition *p.TaskKey
+	scanned := 0
+	for len(tasks) < request.Limit && scanned < getOrphanTasksMaxPartitions && partitions.Scan(&domainID, &taskListName, &taskListType) {
+		var err error
+		tasks, err = d.appendOrphanTasks(ctx, tasks, request.Limit, domainID, taskListName, taskListType, math.MinInt64)
+		if err != nil {
+			_ = partitions.Close()
+			return nil, err
+		}
+		scanned++
+		lastPartition = &p.TaskKey{
+			DomainID:     domainID,
+			TaskListName: taskListName,
+			TaskType:     taskListType,
+			TaskID:       math.MaxInt64,
+		}
+	}
+	if err := partitions.Close(); err != nil {
+		return nil, convertCommonErrors(d.client, "GetOrphanTasks", err)
+	}
+
+	response := &p.GetOrphanTasksResponse{Tasks: tasks}
+	var lastKey *p.TaskKey
+	if len(tasks) == request.Limit {
+		lastKey = tasks[len(tasks)-1]
+	} else if scanned == getOrphanTasksMaxPartitions {
+		lastKey = lastPartition
+	}
+	if lastKey != nil {
+		token, err := json.Marshal(lastKey)
+		if err != nil {
+			return nil, &types.InternalServiceError{
+				Message: fmt.Sprintf("GetOrphanTasks: failed to serialize next page token: %v", err),
+			}
+		}
+		response.NextPageToken = token
+	}
+	return response, nil
+}
+
+// appendOrphanTasks appends the tasks after exclusiveMinTaskID of the given task list partition,
+// up to limit tasks in total, if the partition has no task list row
+func (d *cassandraTaskPersistence) appendOrphanTasks(
+	ctx context.Context,
+	tasks []*p.TaskKey,
+	limit int,
+	domainID string,
+	taskListName string,
+	taskListType int,
+	exclusiveMinTaskID int64,
+) ([]*p.TaskKey, error) {
+	var rangeID int64
+	var tlDB map[string]interface{}
+	err := d.session.Query(templateGetTaskList,
+		domainID,
+		taskListName,
+		taskListType,
+		rowTypeTaskList,
+		taskListTaskID,
+	).WithContext(ctx).Scan(&rangeID, &tlDB)
+	if err == nil {
+		return tasks, nil
+	}
+	if !d.client.IsNotFoundError(err) {
+		return nil, convertCommonErrors(d.client, "GetOrphanTasks", err)
+	}
+
+	iter := d.session.Query(templateGetTaskIDsQuery,
+		domainID,
+		taskListName,
+		taskListType,
+		rowTypeTask,
+		exclusiveMinTaskID,
+	).PageSize(limit - len(tasks)).WithContext(ctx).Iter()
+	if iter == nil {
+		return nil, &types.InternalServiceError{
+			Message: "GetOrphanTasks operation failed.  Not able to create query iterator.",
+		}
+	}
+
+	var taskID int64
+	for len(tasks) < limit && iter.Scan(&taskID) {
+		tasks = append(tasks, &p.TaskKey{
+			DomainID:     domainID,
+			TaskListName: taskListName,
+			TaskType:     taskListType,
+			TaskID:       taskID,
+		})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, convertCommonErrors(d.client, "GetOrphanTasks", err)
 	}
+	return tasks, nil
 }
 
 // From TaskManager interface
@@ -211,6 +388,9 @@ func (d *cassandraTaskPersistence) LeaseTaskList(
 	var rangeID, ackLevel int64
 	var tlDB map[string]interface{}
 	err := query.Scan(&rangeID, &tlDB)
+	if request.ReadOnly {
+		return d.readTaskList(request, rangeID, tlDB, err)
+	}
 	if err != nil {
 		if d.client.IsNotFoundError(err) { // First time task list is used
 			query = d.session.Query(templateInsertTaskListQuery,
@@ -282,6 +462,47 @@ func (d *cassandraTaskPersistence) LeaseTaskList(
 	return &p.LeaseTaskListResponse{TaskListInfo: tli}, nil
 }
 
+func (d *cassandraTaskPersistence) readTaskList(
+	request *p.LeaseTaskListRequest,
+	rangeID int64,
+	tlDB map[string]interface{},
+	err error,
+) (*p.LeaseTaskListResponse, error) {
+	if err != nil {
+		if d.client.IsNotFoundError(err) {
+			return nil, &types.EntityNotExistsError{
+				Message: fmt.Sprintf("LeaseTaskList: task list %v of type %v does not exist", request.TaskList, request.TaskType),
+			}
+		}
+		return nil, convertCommonErrors(d.client, "LeaseTaskList", err)
+	}
+
+	return &p.LeaseTaskListResponse{
+		TaskListInfo: createTaskListInfo(request.DomainID, request.TaskList, request.TaskType, rangeID, tlDB),
+	}, nil
+}
+
+func createTaskListInfo(
+	domainID string,
+	name string,
+	taskType int,
+	rangeID int64,
+	tlDB map[string]interface{},
+) *p.TaskListInfo {
+	tli := &p.TaskListInfo{
+		DomainID: domainID,
+		Name:     name,
+		TaskType: taskType,
+		RangeID:  rangeID,
+		AckLevel: tlDB["ack_level"].(int64),
+		Kind:     tlDB["kind"].(int),
+	}
+	if lastUpdated, ok := tlDB["last_updated"].(time.Time); ok {
+		tli.LastUpdated = lastUpdated
+	}
+	return tli
+}
+
 // From TaskManager interface
 func (d *cassandraTaskPersistence) UpdateTaskList(
 	ctx context.Context,
@@ -368,6 +589,65 @@ func (d *cassandraTaskPersistence) ListTaskList(
 	}
 }
 
+// ListTaskListByDomain pages through the task list partitions and keeps the task lists of the requested domain.
+// The tasks table is not partitioned by domain, so the partitions are filtered in memory and a page may
+// contain fewer than PageSize items, the page token is the cassandra paging state of the partition scan.
+func (d *cassandraTaskPersistence) ListTaskListByDomain(
+	ctx context.Context,
+	request *p.ListTaskListByDomainRequest,
+) (*p.ListTaskListResponse, error) {
+	if request.PageSize <= 0 {
+		return nil, &p.InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("ListTaskListByDomain requires a positive page size, got %v", request.PageSize),
+		}
+	}
+
+	iter := d.session.Query(templateGetTaskListPartitionsQuery).
+		PageSize(request.PageSize).PageState(request.PageToken).WithContext(ctx).Iter()
+	if iter == nil {
+		return nil, &types.InternalServiceError{
+			Message: "ListTaskListByDomain operation failed.  Not able to create query iterator.",
+		}
+	}
+
+	response := &p.ListTaskListResponse{}
+	var domainID, taskListName string
+	var taskListType int
+	for iter.Scan(&domainID, &taskListName, &taskListType) {
+		if domainID != request.DomainID {
+			continue
+		}
+
+		var rangeID int64
+		var tlDB map[string]interface{}
+		err := d.session.Query(templateGetTaskList,
+			domainID,
+			taskListName,
+			taskListType,
+			rowTypeTaskList,
+			taskListTaskID,
+		).WithContext(ctx).Scan(&rangeID, &tlDB)
+		if err != nil {
+			if d.client.IsNotFoundError(err) {
+				// orphan tasks without a task list row
+				continue
+			}
+			_ = iter.Close()
+			return nil, convertCommonErrors(d.client, "ListTaskListByDomain", err)
+		}
+		response.Items = append(response.Items, *createTaskListInfo(domainID, taskListName, taskListType, rangeID, tlDB))
+	}
+	if nextPageToken := iter.PageState(); len(nextPageToken) > 0 {
+		response.NextPageToken = make([]byte, len(nextPageToken))
+		copy(response.NextPageToken, nextPageToken)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, convertCommonErrors(d.client, "ListTaskListByDomain", err)
+	}
+	return response, nil
+}
+
 func (d *cassandraTaskPersistence) DeleteTaskList(
 	ctx context.Context,
 	request *p.DeleteTaskListRequest,
@@ -398,6 +678,16 @@ func (d *cassandraTaskPersistence) CreateTasks(
 	ctx context.Context,
 	request *p.InternalCreateTasksRequest,
 ) (*p.CreateTasksResponse, error) {
+	if request.IdempotencyKey != "" {
+		applied, err := d.isTaskBatchApplied(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		if applied {
+			return &p.CreateTasksResponse{}, nil
+		}
+	}
+
 	batch := d.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
 	domainID := request.TaskListInfo.DomainID
 	taskList := request.TaskListInfo.Name
@@ -441,21 +731,45 @@ func (d *cassandraTaskPersistence) CreateTasks(
 	}
 
 	// The following query is used to ensure that range_id didn't change
-	batch.Query(templateUpdateTaskListQuery,
-		request.TaskListInfo.RangeID,
-		domainID,
-		taskList,
-		taskListType,
-		ackLevel,
-		taskListKind,
-		time.Now(),
-		domainID,
-		taskList,
-		taskListType,
-		rowTypeTaskList,
-		taskListTaskID,
-		request.TaskListInfo.RangeID,
-	)
+	if request.IdempotencyKey == "" {
+		batch.Query(templateUpdateTaskListQuery,
+			request.TaskListInfo.RangeID,
+			domainID,
+			taskList,
+			taskListType,
+			ackLevel,
+			taskListKind,
+			time.Now(),
+			domainID,
+			taskList,
+			taskListType,
+			rowTypeTaskList,
+			taskListTaskID,
+			request.TaskListInfo.RangeID,
+		)
+	} else {
+		ttl := int32(0)
+		if taskListKind == p.TaskListKindSticky {
+			ttl = stickyTaskListTTL
+		}
+		batch.Query(templateUpdateTaskListWithIdempotencyKeyQuery,
+			ttl,
+			request.TaskListInfo.RangeID,
+			domainID,
+			taskList,
+			taskListType,
+			ackLevel,
+			taskListKind,
+			time.Now(),
+			request.IdempotencyKey,
+			domainID,
+			taskList,
+			taskListType,
+			rowTypeTaskList,
+			taskListTaskID,
+			request.TaskListInfo.RangeID,
+		)
+	}
 
 	previous := make(map[string]interface{})
 	applied, _, err := d.session.MapExecuteBatchCAS(batch, previous)
@@ -463,8 +777,13 @@ func (d *cassandraTaskPersistence) CreateTasks(
 		return nil, convertCommonErrors(d.client, "CreateTasks", err)
 	}
 	if !applied {
-		rangeID := previous["range_id"]
-		return nil, &p.ConditionFailedError{
+		rangeID, _ := previous["range_id"].(int64)
+		return nil, &p.TaskListNotOwnedError{
+			DomainID:        domainID,
+			TaskListName:    taskList,
+			TaskType:        taskListType,
+			ExpectedRangeID: request.TaskListInfo.RangeID,
+			ActualRangeID:   rangeID,
 			Msg: fmt.Sprintf("Failed to create task. TaskList: %v, taskListType: %v, rangeID: %v, db rangeID: %v",
 				taskList, taskListType, request.TaskListInfo.RangeID, rangeID),
 		}
@@ -473,6 +792,30 @@ func (d *cassandraTaskPersistence) CreateTasks(
 	return &p.CreateTasksResponse{}, nil
 }
 
+// isTaskBatchApplied returns true if the last batch written to the task list under
+// the current range_id carries the same idempotency key as the request
+func (d *cassandraTaskPersistence) isTaskBatchApplied(
+	ctx context.Context,
+	request *p.InternalCreateTasksRequest,
+) (bool, error) {
+	query := d.session.Query(templateGetTaskListIdempotencyKeyQuery,
+		request.TaskListInfo.DomainID,
+		request.TaskListInfo.Name,
+		request.TaskListInfo.TaskType,
+		rowTypeTaskList,
+		taskListTaskID,
+	).WithContext(ctx)
+	var rangeID int64
+	var lastIdempotencyKey string
+	if err := query.Scan(&rangeID, &lastIdempotencyKey); err != nil {
+		if d.client.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, convertCommonErrors(d.client, "CreateTasks", err)
+	}
+	return rangeID == request.TaskListInfo.RangeID && lastIdempotencyKey == request.IdempotencyKey, nil
+}
+
 // From TaskManager interface
 func (d *cassandraTaskPersistence) GetTasks(
 	ctx context.Context,
@@ -534,13 +877,41 @@ func (d *cassandraTaskPersistence) CompleteTask(
 	request *p.CompleteTaskRequest,
 ) error {
 	tli := request.TaskList
+	if tli.RangeID > 0 {
+		// the range check is not atomic with the delete, a conditional update on the task list
+		// row would reset the TTL of sticky task lists
+		var rangeID int64
+		var tlDB map[string]interface{}
+		err := d.session.Query(templateGetTaskList,
+			tli.DomainID,
+			tli.Name,
+			tli.TaskType,
+			rowTypeTaskList,
+			taskListTaskID,
+		).WithContext(ctx).Scan(&rangeID, &tlDB)
+		if err != nil && !d.client.IsNotFoundError(err) {
+			return convertCommonErrors(d.client, "CompleteTask", err)
+		}
+		if rangeID != tli.RangeID {
+			return &p.TaskListNotOwnedError{
+				DomainID:        tli.DomainID,
+				TaskListName:    tli.Name,
+				TaskType:        tli.TaskType,
+				ExpectedRangeID: tli.RangeID,
+				ActualRangeID:   rangeID,
+				Msg: fmt.Sprintf("Failed to complete task. TaskList: %v, taskListType: %v, rangeID: %v, db rangeID: %v",
+					tli.Name, tli.TaskType, tli.RangeID, rangeID),
+			}
+		}
+	}
+
 	query := d.session.Query(templateCompleteTaskQuery,
 		tli.DomainID,
 		tli.Name,
 		tli.TaskType,
 		rowTypeTask,
 		request.TaskID,
-	).WithContext(ctx)
+	).Idempotent(true).WithContext(ctx)
 
 	err := query.Exec()
 	if err != nil {
@@ -563,7 +934,7 @@ func (d *cassandraTaskPersistence) CompleteTasksLessThan(
 		request.TaskType,
 		rowTypeTask,
 		request.TaskID,
-	).WithContext(ctx)
+	).Idempotent(true).WithContext(ctx)
 	err := query.Exec()
 	if err != nil {
 		return 0, convertCommonErrors(d.client, "CompleteTasksLessThan", err)
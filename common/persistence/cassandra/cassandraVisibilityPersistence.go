@@ -29,6 +29,7 @@ import (
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
 	p "github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra/gocql"
@@ -192,8 +193,9 @@ func newVisibilityPersistence(
 	listClosedOrderingByCloseTime bool,
 	cfg config.Cassandra,
 	logger log.Logger,
+	metricsClient metrics.Client,
 ) (p.VisibilityStore, error) {
-	session, err := cassandra.CreateSession(cfg)
+	session, err := cassandra.CreateSession(cfg, logger, metricsClient)
 	if err != nil {
 		return nil, err
 	}
@@ -216,6 +218,14 @@ func (v *cassandraVisibilityPersistence) Close() {
 	}
 }
 
+// CloseWithContext releases the resources like Close, but stops waiting once ctx is done
+func (v *cassandraVisibilityPersistence) CloseWithContext(ctx context.Context) error {
+	if v.session != nil {
+		return v.session.CloseWithContext(ctx)
+	}
+	return nil
+}
+
 func (v *cassandraVisibilityPersistence) RecordWorkflowExecutionStarted(
 	ctx context.Context,
 	request *p.InternalRecordWorkflowExecutionStartedRequest,
@@ -234,7 +244,7 @@ func (v *cassandraVisibilityPersistence) RecordWorkflowExecutionStarted(
 			request.Memo.Data,
 			string(request.Memo.GetEncoding()),
 			request.TaskList,
-		).WithContext(ctx)
+		).Idempotent(true).WithContext(ctx)
 	} else {
 		query = v.session.Query(templateCreateWorkflowExecutionStartedWithTTL,
 			request.DomainUUID,
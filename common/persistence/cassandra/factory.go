@@ -25,6 +25,7 @@ import (
 
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
 	p "github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra/gocql"
@@ -37,6 +38,7 @@ type (
 		cfg              config.Cassandra
 		clusterName      string
 		logger           log.Logger
+		metricsClient    metrics.Client
 		execStoreFactory *executionStoreFactory
 	}
 
@@ -48,36 +50,37 @@ type (
 )
 
 // NewFactory returns an instance of a factory object which can be used to create
-// datastores that are backed by cassandra
-func NewFactory(cfg config.Cassandra, clusterName string, logger log.Logger) *Factory {
+// datastores that are backed by cassandra, metricsClient is optional and only used for session metrics
+func NewFactory(cfg config.Cassandra, clusterName string, logger log.Logger, metricsClient metrics.Client) *Factory {
 	if cfg.CQLClient == nil {
 		cfg.CQLClient = gocql.NewClient()
 	}
 	return &Factory{
-		cfg:         cfg,
-		clusterName: clusterName,
-		logger:      logger,
+		cfg:           cfg,
+		clusterName:   clusterName,
+		logger:        logger,
+		metricsClient: metricsClient,
 	}
 }
 
 // NewTaskStore returns a new task store
 func (f *Factory) NewTaskStore() (p.TaskStore, error) {
-	return newTaskPersistence(f.cfg, f.logger)
+	return newTaskPersistence(f.cfg, f.logger, f.metricsClient)
 }
 
 // NewShardStore returns a new shard store
 func (f *Factory) NewShardStore() (p.ShardStore, error) {
-	return newShardPersistence(f.cfg, f.clusterName, f.logger)
+	return newShardPersistence(f.cfg.ExecutionStoreConfig(), f.clusterName, f.logger, f.metricsClient)
 }
 
 // NewHistoryV2Store returns a new history store
 func (f *Factory) NewHistoryV2Store() (p.HistoryStore, error) {
-	return newHistoryV2Persistence(f.cfg, f.logger)
+	return newHistoryV2Persistence(f.cfg.HistoryStoreConfig(), f.logger, f.metricsClient)
 }
 
 // NewMetadataStore returns a metadata store that understands only v2
 func (f *Factory) NewMetadataStore() (p.MetadataStore, error) {
-	return newMetadataPersistenceV2(f.cfg, f.clusterName, f.logger)
+	return newMetadataPersistenceV2(f.cfg, f.clusterName, f.logger, f.metricsClient)
 }
 
 // NewExecutionStore returns an ExecutionStore for a given shardID
@@ -91,12 +94,12 @@ func (f *Factory) NewExecutionStore(shardID int) (p.ExecutionStore, error) {
 
 // NewVisibilityStore returns a visibility store
 func (f *Factory) NewVisibilityStore(sortByCloseTime bool) (p.VisibilityStore, error) {
-	return newVisibilityPersistence(sortByCloseTime, f.cfg, f.logger)
+	return newVisibilityPersistence(sortByCloseTime, f.cfg, f.logger, f.metricsClient)
 }
 
 // NewQueue returns a new queue backed by cassandra
 func (f *Factory) NewQueue(queueType p.QueueType) (p.Queue, error) {
-	return newQueue(f.cfg, f.logger, queueType)
+	return newQueue(f.cfg, f.logger, f.metricsClient, queueType)
 }
 
 // Close closes the factory
@@ -121,7 +124,7 @@ func (f *Factory) executionStoreFactory() (*executionStoreFactory, error) {
 		return f.execStoreFactory, nil
 	}
 
-	factory, err := newExecutionStoreFactory(f.cfg, f.logger)
+	factory, err := newExecutionStoreFactory(f.cfg.ExecutionStoreConfig(), f.logger, f.metricsClient)
 	if err != nil {
 		return nil, err
 	}
@@ -133,8 +136,9 @@ func (f *Factory) executionStoreFactory() (*executionStoreFactory, error) {
 func newExecutionStoreFactory(
 	cfg config.Cassandra,
 	logger log.Logger,
+	metricsClient metrics.Client,
 ) (*executionStoreFactory, error) {
-	session, err := cassandra.CreateSession(cfg)
+	session, err := cassandra.CreateSession(cfg, logger, metricsClient)
 	if err != nil {
 		return nil, err
 	}
@@ -21,6 +21,8 @@
 package cassandra
 
 import (
+	"context"
+
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin"
 )
@@ -41,3 +43,8 @@ func (nm *nosqlManager) GetName() string {
 func (nm *nosqlManager) Close() {
 	nm.db.Close()
 }
+
+// CloseWithContext releases the underlying resources like Close, but stops waiting once ctx is done
+func (nm *nosqlManager) CloseWithContext(ctx context.Context) error {
+	return nm.db.CloseWithContext(ctx)
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/types"
+)
+
+type (
+	// CircuitBreaker protects a persistence backend from the callers while it is unhealthy.
+	// The circuit opens after a number of consecutive failures of the backend, such as timeouts, and the calls
+	// fail fast while it is open. Once the open duration elapses, a single probe call is let through: the circuit
+	// closes if it succeeds and opens again if it fails. The same CircuitBreaker can be shared by the clients
+	// of all the managers of a backend.
+	CircuitBreaker interface {
+		// Allow returns whether a call can be made to the backend
+		Allow() bool
+		// Record records the result of a call which was allowed
+		Record(err error)
+	}
+
+	circuitBreakerImpl struct {
+		sync.Mutex
+		failureThreshold    int
+		openDuration        time.Duration
+		timeSource          clock.TimeSource
+		state               circuitBreakerState
+		consecutiveFailures int
+		// openedAt is when the circuit was opened, or when the last probe was let through if it is half open
+		openedAt time.Time
+	}
+
+	circuitBreakerState int
+
+	circuitBreakerResult int
+)
+
+const (
+	circuitBreakerClosed circuitBreakerState = iota
+	circuitBreakerOpen
+	// circuitBreakerHalfOpen means a probe call is in flight
+	circuitBreakerHalfOpen
+)
+
+const (
+	// circuitBreakerResultUnknown leaves the circuit as it is, e.g. the caller canceled the call
+	circuitBreakerResultUnknown circuitBreakerResult = iota
+	circuitBreakerResultSuccess
+	circuitBreakerResultFailure
+)
+
+var (
+	// ErrPersistenceCircuitOpen is the error returned by the clients while the circuit breaker is open
+	ErrPersistenceCircuitOpen = &types.ServiceBusyError{Message: "Persistence circuit breaker is open."}
+)
+
+var _ CircuitBreaker = (*circuitBreakerImpl)(nil)
+
+// NewCircuitBreaker returns a CircuitBreaker which opens after failureThreshold consecutive backend failures
+// and lets a probe call through every openDuration while it is open
+func NewCircuitBreaker(
+	failureThreshold int,
+	openDuration time.Duration,
+	timeSource clock.TimeSource,
+) CircuitBreaker {
+	return &circuitBreakerImpl{
+		failureThreshold: failureThreshold,
+		openDuration:     openDuration,
+		timeSource:       timeSource,
+		state:            circuitBreakerClosed,
+	}
+}
+
+func (c *circuitBreakerImpl) Allow() bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.state == circuitBreakerClosed {
+		return true
+	}
+	// a half open circuit lets another probe through if the previous one never reported back
+	now := c.timeSource.Now()
+	if now.Sub(c.openedAt) < c.openDuration {
+		return false
+	}
+	c.state = circuitBreakerHalfOpen
+	c.openedAt = now
+	return true
+}
+
+func (c *circuitBreakerImpl) Record(err error) {
+	c.Lock()
+	defer c.Unlock()
+
+	switch classifyCircuitBreakerResult(err) {
+	case circuitBreakerResultSuccess:
+		c.state = circuitBreakerClosed
+		c.consecutiveFailures = 0
+	case circuitBreakerResultFailure:
+		c.consecutiveFailures++
+		if c.state == circuitBreakerHalfOpen || c.consecutiveFailures >= c.failureThreshold {
+			c.state = circuitBreakerOpen
+			c.openedAt = c.timeSource.Now()
+		}
+	}
+}
+
+// classifyCircuitBreakerResult tells whether the result of a call shows the backend is healthy or not.
+// This is not the same as whether the call can be retried: a non idempotent write which timed out
+// must not be retried, but the timeout still counts as a failure of the backend.
+func classifyCircuitBreakerResult(err error) circuitBreakerResult {
+	if err == nil {
+		return circuitBreakerResultSuccess
+	}
+	if err == context.DeadlineExceeded {
+		return circuitBreakerResultFailure
+	}
+
+	switch err.(type) {
+	case *TimeoutError, *types.InternalServiceError, *types.ServiceBusyError:
+		return circuitBreakerResultFailure
+	case *types.EntityNotExistsError,
+		*types.BadRequestError,
+		*types.DomainAlreadyExistsError,
+		*InvalidPersistenceRequestError,
+		*CurrentWorkflowConditionFailedError,
+		*ConditionFailedError,
+		*ShardAlreadyExistError,
+		*ShardOwnershipLostError,
+		*ShardRangeIDMismatchError,
+		*TaskListNotOwnedError,
+		*DomainVersionConflictError,
+		*WorkflowExecutionAlreadyStartedError,
+		*WorkflowExecutionNotExistsError,
+		*TransactionSizeLimitError,
+		*OperationNotSupportedError:
+		// the backend answered, the request itself was rejected
+		return circuitBreakerResultSuccess
+	}
+	return circuitBreakerResultUnknown
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/types"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	timeSource := clock.NewEventTimeSource().Update(time.Unix(1000, 0))
+	breaker := NewCircuitBreaker(3, time.Second, timeSource)
+	transientErr := &types.InternalServiceError{Message: "error"}
+
+	// only consecutive transient failures open the circuit
+	breaker.Record(transientErr)
+	breaker.Record(transientErr)
+	breaker.Record(nil)
+	breaker.Record(transientErr)
+	breaker.Record(transientErr)
+	breaker.Record(&types.EntityNotExistsError{})
+	breaker.Record(transientErr)
+	breaker.Record(transientErr)
+	assert.True(t, breaker.Allow())
+	breaker.Record(transientErr)
+	assert.False(t, breaker.Allow())
+
+	// a single probe is let through once the circuit was open long enough
+	timeSource.Update(timeSource.Now().Add(time.Second))
+	assert.True(t, breaker.Allow())
+	assert.False(t, breaker.Allow())
+	// a failed probe opens the circuit again
+	breaker.Record(transientErr)
+	assert.False(t, breaker.Allow())
+
+	// a probe which never reports back does not keep the circuit half open forever
+	timeSource.Update(timeSource.Now().Add(time.Second))
+	assert.True(t, breaker.Allow())
+	timeSource.Update(timeSource.Now().Add(time.Second))
+	assert.True(t, breaker.Allow())
+
+	// a successful probe closes the circuit
+	breaker.Record(nil)
+	assert.True(t, breaker.Allow())
+	assert.True(t, breaker.Allow())
+	breaker.Record(transientErr)
+	assert.True(t, breaker.Allow())
+}
+
+func TestCircuitBreakerTimeouts(t *testing.T) {
+	timeSource := clock.NewEventTimeSource().Update(time.Unix(1000, 0))
+	breaker := NewCircuitBreaker(3, time.Second, timeSource)
+
+	// write timeouts are not retried but still count as failures of the backend
+	breaker.Record(&TimeoutError{Msg: "timeout", WriteType: "SIMPLE"})
+	breaker.Record(&TimeoutError{Msg: "timeout", WriteType: "CAS"})
+	// a canceled call tells nothing about the backend
+	breaker.Record(context.Canceled)
+	assert.True(t, breaker.Allow())
+	breaker.Record(context.DeadlineExceeded)
+	assert.False(t, breaker.Allow())
+}
+
+func TestClassifyCircuitBreakerResult(t *testing.T) {
+	testCases := []struct {
+		err    error
+		result circuitBreakerResult
+	}{
+		{nil, circuitBreakerResultSuccess},
+		{&TimeoutError{Msg: "timeout"}, circuitBreakerResultFailure},
+		{&TimeoutError{Msg: "timeout", WriteType: "SIMPLE"}, circuitBreakerResultFailure},
+		{context.DeadlineExceeded, circuitBreakerResultFailure},
+		{&types.InternalServiceError{}, circuitBreakerResultFailure},
+		{&types.ServiceBusyError{}, circuitBreakerResultFailure},
+		{&types.EntityNotExistsError{}, circuitBreakerResultSuccess},
+		{&ConditionFailedError{}, circuitBreakerResultSuccess},
+		{&ShardOwnershipLostError{}, circuitBreakerResultSuccess},
+		{&InvalidPersistenceRequestError{}, circuitBreakerResultSuccess},
+		{context.Canceled, circuitBreakerResultUnknown},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.result, classifyCircuitBreakerResult(tc.err), "%v", tc.err)
+	}
+}
+
+func TestCircuitBreakerClient(t *testing.T) {
+	timeSource := clock.NewEventTimeSource().Update(time.Unix(1000, 0))
+	store, executionManager := newTestExecutionManager(t)
+	manager := NewWorkflowExecutionPersistenceCircuitBreakerClient(
+		executionManager,
+		NewCircuitBreaker(2, time.Second, timeSource),
+		loggerimpl.NewNopLogger(),
+	)
+	request := &GetCurrentRunIDRequest{DomainID: "domain", WorkflowID: "workflow"}
+
+	storeErr := &types.InternalServiceError{Message: "error"}
+	store.EXPECT().GetCurrentRunID(gomock.Any(), request).Return(nil, storeErr).Times(2)
+	for i := 0; i < 2; i++ {
+		_, err := manager.GetCurrentRunID(context.Background(), request)
+		assert.Equal(t, storeErr, err)
+	}
+	// the open circuit does not reach the store
+	_, err := manager.GetCurrentRunID(context.Background(), request)
+	assert.Equal(t, ErrPersistenceCircuitOpen, err)
+
+	store.EXPECT().GetCurrentRunID(gomock.Any(), request).Return(&GetCurrentRunIDResponse{RunID: "run"}, nil).Times(2)
+	timeSource.Update(timeSource.Now().Add(time.Second))
+	response, err := manager.GetCurrentRunID(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, "run", response.RunID)
+	_, err = manager.GetCurrentRunID(context.Background(), request)
+	assert.NoError(t, err)
+}
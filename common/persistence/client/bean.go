@@ -23,8 +23,11 @@
 package client
 
 import (
+	"context"
 	"sync"
 
+	"go.uber.org/multierr"
+
 	"github.com/uber/cadence/common/persistence"
 )
 
@@ -32,6 +35,8 @@ type (
 	// Bean in an collection of persistence manager
 	Bean interface {
 		Close()
+		// CloseWithContext closes all the managers like Close, but stops waiting once ctx is done
+		CloseWithContext(ctx context.Context) error
 
 		GetMetadataManager() persistence.MetadataManager
 		SetMetadataManager(persistence.MetadataManager)
@@ -318,3 +323,30 @@ func (s *BeanImpl) Close() {
 		executionMgr.Close()
 	}
 }
+
+// CloseWithContext closes all the managers in the same order as Close, the managers left once ctx is done
+// are still asked to close so they release their resources in the background
+func (s *BeanImpl) CloseWithContext(ctx context.Context) error {
+
+	s.Lock()
+	defer s.Unlock()
+
+	closeables := []persistence.Closeable{
+		s.metadataManager,
+		s.taskManager,
+		s.visibilityManager,
+		s.domainReplicationQueueManager,
+		s.shardManager,
+		s.historyManager,
+		s.executionManagerFactory,
+	}
+	for _, executionMgr := range s.shardIDToExecutionManager {
+		closeables = append(closeables, executionMgr)
+	}
+
+	var err error
+	for _, closeable := range closeables {
+		err = multierr.Append(err, persistence.CloseWithContext(ctx, closeable))
+	}
+	return err
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/uber/cadence/common/log/tag"
 
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/log"
@@ -83,8 +84,9 @@ type (
 
 	// Datastore represents a datastore
 	Datastore struct {
-		factory   DataStoreFactory
-		ratelimit quotas.Limiter
+		factory        DataStoreFactory
+		ratelimit      quotas.Limiter
+		circuitBreaker p.CircuitBreaker
 	}
 	factoryImpl struct {
 		sync.RWMutex
@@ -139,7 +141,8 @@ func NewFactory(
 		clusterName:   clusterName,
 	}
 	limiters := buildRatelimiters(cfg, persistenceMaxQPS)
-	factory.init(clusterName, limiters)
+	circuitBreakers := buildCircuitBreakers(cfg)
+	factory.init(clusterName, limiters, circuitBreakers)
 	return factory
 }
 
@@ -151,6 +154,9 @@ func (f *factoryImpl) NewTaskManager() (p.TaskManager, error) {
 		return nil, err
 	}
 	result := p.NewTaskManager(store)
+	if ds.circuitBreaker != nil {
+		result = p.NewTaskPersistenceCircuitBreakerClient(result, ds.circuitBreaker, f.logger)
+	}
 	if errorRate := f.config.ErrorInjectionRate(); errorRate != 0 {
 		result = p.NewTaskPersistenceErrorInjectionClient(result, errorRate, f.logger)
 	}
@@ -170,7 +176,10 @@ func (f *factoryImpl) NewShardManager() (p.ShardManager, error) {
 	if err != nil {
 		return nil, err
 	}
-	result := p.NewShardManager(store)
+	result := p.NewShardManager(store, f.logger, f.metricsClient, f.config.ShardThrashingThreshold)
+	if ds.circuitBreaker != nil {
+		result = p.NewShardPersistenceCircuitBreakerClient(result, ds.circuitBreaker, f.logger)
+	}
 	if errorRate := f.config.ErrorInjectionRate(); errorRate != 0 {
 		result = p.NewShardPersistenceErrorInjectionClient(result, errorRate, f.logger)
 	}
@@ -191,6 +200,9 @@ func (f *factoryImpl) NewHistoryManager() (p.HistoryManager, error) {
 		return nil, err
 	}
 	result := p.NewHistoryV2ManagerImpl(store, f.logger, f.config.TransactionSizeLimit)
+	if ds.circuitBreaker != nil {
+		result = p.NewHistoryPersistenceCircuitBreakerClient(result, ds.circuitBreaker, f.logger)
+	}
 	if errorRate := f.config.ErrorInjectionRate(); errorRate != 0 {
 		result = p.NewHistoryPersistenceErrorInjectionClient(result, errorRate, f.logger)
 	}
@@ -212,7 +224,10 @@ func (f *factoryImpl) NewMetadataManager() (p.MetadataManager, error) {
 	if err != nil {
 		return nil, err
 	}
-	result := p.NewMetadataManagerImpl(store, f.logger)
+	result := p.NewMetadataManagerImpl(store, f.logger, f.config.MinDomainRetentionDays, f.config.MaxDomainRetentionDays)
+	if ds.circuitBreaker != nil {
+		result = p.NewMetadataPersistenceCircuitBreakerClient(result, ds.circuitBreaker, f.logger)
+	}
 	if errorRate := f.config.ErrorInjectionRate(); errorRate != 0 {
 		result = p.NewMetadataPersistenceErrorInjectionClient(result, errorRate, f.logger)
 	}
@@ -233,6 +248,9 @@ func (f *factoryImpl) NewExecutionManager(shardID int) (p.ExecutionManager, erro
 		return nil, err
 	}
 	result := p.NewExecutionManagerImpl(store, f.logger)
+	if ds.circuitBreaker != nil {
+		result = p.NewWorkflowExecutionPersistenceCircuitBreakerClient(result, ds.circuitBreaker, f.logger)
+	}
 	if errorRate := f.config.ErrorInjectionRate(); errorRate != 0 {
 		result = p.NewWorkflowExecutionPersistenceErrorInjectionClient(result, errorRate, f.logger)
 	}
@@ -261,6 +279,9 @@ func (f *factoryImpl) NewVisibilityManager() (p.VisibilityManager, error) {
 		return nil, err
 	}
 	result := p.NewVisibilityManagerImpl(store, f.logger)
+	if ds.circuitBreaker != nil {
+		result = p.NewVisibilityPersistenceCircuitBreakerClient(result, ds.circuitBreaker, f.logger)
+	}
 	if errorRate := f.config.ErrorInjectionRate(); errorRate != 0 {
 		result = p.NewVisibilityPersistenceErrorInjectionClient(result, errorRate, f.logger)
 	}
@@ -284,6 +305,9 @@ func (f *factoryImpl) NewDomainReplicationQueueManager() (p.QueueManager, error)
 		return nil, err
 	}
 	result := p.NewQueueManager(store)
+	if ds.circuitBreaker != nil {
+		result = p.NewQueuePersistenceCircuitBreakerClient(result, ds.circuitBreaker, f.logger)
+	}
 	if errorRate := f.config.ErrorInjectionRate(); errorRate != 0 {
 		result = p.NewQueuePersistenceErrorInjectionClient(result, errorRate, f.logger)
 	}
@@ -303,13 +327,20 @@ func (f *factoryImpl) Close() {
 	ds.factory.Close()
 }
 
-func (f *factoryImpl) init(clusterName string, limiters map[string]quotas.Limiter) {
+func (f *factoryImpl) init(
+	clusterName string,
+	limiters map[string]quotas.Limiter,
+	circuitBreakers map[string]p.CircuitBreaker,
+) {
 	f.datastores = make(map[storeType]Datastore, len(storeTypes))
 	defaultCfg := f.config.DataStores[f.config.DefaultStore]
-	defaultDataStore := Datastore{ratelimit: limiters[f.config.DefaultStore]}
+	defaultDataStore := Datastore{
+		ratelimit:      limiters[f.config.DefaultStore],
+		circuitBreaker: circuitBreakers[f.config.DefaultStore],
+	}
 	switch {
 	case defaultCfg.Cassandra != nil:
-		defaultDataStore.factory = cassandra.NewFactory(*defaultCfg.Cassandra, clusterName, f.logger)
+		defaultDataStore.factory = cassandra.NewFactory(*defaultCfg.Cassandra, clusterName, f.logger, f.metricsClient)
 	case defaultCfg.SQL != nil:
 		if defaultCfg.SQL.EncodingType == "" {
 			defaultCfg.SQL.EncodingType = string(common.EncodingTypeThriftRW)
@@ -339,10 +370,13 @@ func (f *factoryImpl) init(clusterName string, limiters map[string]quotas.Limite
 	}
 
 	visibilityCfg := f.config.DataStores[f.config.VisibilityStore]
-	visibilityDataStore := Datastore{ratelimit: limiters[f.config.VisibilityStore]}
+	visibilityDataStore := Datastore{
+		ratelimit:      limiters[f.config.VisibilityStore],
+		circuitBreaker: circuitBreakers[f.config.VisibilityStore],
+	}
 	switch {
 	case visibilityCfg.Cassandra != nil:
-		visibilityDataStore.factory = cassandra.NewFactory(*visibilityCfg.Cassandra, clusterName, f.logger)
+		visibilityDataStore.factory = cassandra.NewFactory(*visibilityCfg.Cassandra, clusterName, f.logger, f.metricsClient)
 	case visibilityCfg.SQL != nil:
 		var decodingTypes []common.EncodingType
 		for _, dt := range visibilityCfg.SQL.DecodingTypes {
@@ -377,3 +411,19 @@ func buildRatelimiters(cfg *config.Persistence, maxQPS dynamicconfig.IntProperty
 	}
 	return result
 }
+
+func buildCircuitBreakers(cfg *config.Persistence) map[string]p.CircuitBreaker {
+	result := make(map[string]p.CircuitBreaker, len(cfg.DataStores))
+	if cfg.CircuitBreaker == nil {
+		return result
+	}
+	for dsName := range cfg.DataStores {
+		// one circuit breaker per datastore, so it trips for all the managers backed by the datastore
+		result[dsName] = p.NewCircuitBreaker(
+			cfg.CircuitBreaker.FailureThreshold,
+			cfg.CircuitBreaker.OpenDuration,
+			clock.NewRealTimeSource(),
+		)
+	}
+	return result
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"context"
+	"sync"
+
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	// getReplicationTasksFromAllDLQsConcurrency is the max number of dlqs read concurrently by GetReplicationTasksFromAllDLQs
+	getReplicationTasksFromAllDLQsConcurrency = 8
+	// batchGetDomainsConcurrency is the max number of domains read concurrently by BatchGetDomains
+	batchGetDomainsConcurrency = 16
+)
+
+type (
+	// executionManagerCompositeOperations implements the ExecutionManager operations made of several reads of
+	// the same manager. The manager is the one embedding it, so each read goes through the metric, rate limiter,
+	// error injection and circuit breaker wrappers of that manager like any other call would.
+	executionManagerCompositeOperations struct {
+		manager ExecutionManager
+	}
+
+	// taskManagerCompositeOperations implements the TaskManager operations made of several reads of
+	// the same manager, see executionManagerCompositeOperations
+	taskManagerCompositeOperations struct {
+		manager TaskManager
+	}
+
+	// historyManagerCompositeOperations implements the HistoryManager operations made of several reads of
+	// the same manager, see executionManagerCompositeOperations
+	historyManagerCompositeOperations struct {
+		manager HistoryManager
+	}
+
+	// metadataManagerCompositeOperations implements the MetadataManager operations made of several reads of
+	// the same manager, see executionManagerCompositeOperations
+	metadataManagerCompositeOperations struct {
+		manager MetadataManager
+	}
+)
+
+// GetReplicationTasksFromAllDLQs reads the DLQ of every source cluster in the request with GetReplicationTasksFromDLQ
+func (o executionManagerCompositeOperations) GetReplicationTasksFromAllDLQs(
+	ctx context.Context,
+	request *GetReplicationTasksFromAllDLQsRequest,
+) (*GetReplicationTasksFromAllDLQsResponse, error) {
+	sourceClusters := make([]string, 0, len(request.Requests))
+	for sourceCluster := range request.Requests {
+		sourceClusters = append(sourceClusters, sourceCluster)
+	}
+	responses := make([]*GetReplicationTasksFromDLQResponse, len(sourceClusters))
+	errs := runConcurrently(ctx, len(sourceClusters), getReplicationTasksFromAllDLQsConcurrency, func(i int) error {
+		var err error
+		responses[i], err = o.manager.GetReplicationTasksFromDLQ(ctx, &GetReplicationTasksFromDLQRequest{
+			SourceClusterName:          sourceClusters[i],
+			TaskTypeFilter:             request.TaskTypeFilter,
+			GetReplicationTasksRequest: request.Requests[sourceClusters[i]],
+		})
+		return err
+	})
+
+	result := &GetReplicationTasksFromAllDLQsResponse{
+		Responses: make(map[string]*GetReplicationTasksFromDLQResponse, len(sourceClusters)),
+	}
+	for i, sourceCluster := range sourceClusters {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		result.Responses[sourceCluster] = responses[i]
+	}
+	return result, nil
+}
+
+// GetTimerIndexTasksIterator returns an iterator which pages through GetTimerIndexTasks
+func (o executionManagerCompositeOperations) GetTimerIndexTasksIterator(
+	request *GetTimerIndexTasksRequest,
+) TimerTaskIterator {
+	return NewTimerTaskIterator(o.manager, request)
+}
+
+// GetTasksIterator returns an iterator which pages through GetTasks
+func (o taskManagerCompositeOperations) GetTasksIterator(
+	request *GetTasksRequest,
+) TaskIterator {
+	return NewTaskIterator(o.manager, request)
+}
+
+// ReadHistoryBranchIterator returns history node data for a branch one event at a time
+// Pagination is delegated to ReadHistoryBranch, so only a single page of events is held in memory
+func (o historyManagerCompositeOperations) ReadHistoryBranchIterator(
+	ctx context.Context,
+	request *ReadHistoryBranchRequest,
+	callback func(event *types.HistoryEvent) error,
+) error {
+	return ReadHistoryBranchIterator(ctx, o.manager, request, callback)
+}
+
+// BatchGetDomains reads every domain in the request with GetDomain, domains which do not exist are returned as missing
+func (o metadataManagerCompositeOperations) BatchGetDomains(
+	ctx context.Context,
+	request *BatchGetDomainsRequest,
+) (*BatchGetDomainsResponse, error) {
+	ids := request.IDs
+	responses := make([]*GetDomainResponse, len(ids))
+	errs := runConcurrently(ctx, len(ids), batchGetDomainsConcurrency, func(i int) error {
+		var err error
+		responses[i], err = o.manager.GetDomain(ctx, &GetDomainRequest{ID: ids[i]})
+		return err
+	})
+
+	result := &BatchGetDomainsResponse{
+		Domains: make(map[string]*GetDomainResponse, len(ids)),
+	}
+	for i, id := range ids {
+		if errs[i] != nil {
+			if _, ok := errs[i].(*types.EntityNotExistsError); ok {
+				result.MissingIDs = append(result.MissingIDs, id)
+				continue
+			}
+			return nil, errs[i]
+		}
+		result.Domains[id] = responses[i]
+	}
+	return result, nil
+}
+
+// runConcurrently calls fn for every index in [0, n) with at most concurrency calls in flight and returns the
+// error of each call, indexes which are not started before ctx is done get the error of ctx instead
+func runConcurrently(
+	ctx context.Context,
+	n int,
+	concurrency int,
+	fn func(i int) error,
+) []error {
+	errs := make([]error, n)
+
+	indexCh := make(chan int, n)
+	for i := 0; i < n; i++ {
+		indexCh <- i
+	}
+	close(indexCh)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexCh {
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+				errs[i] = fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+	return errs
+}
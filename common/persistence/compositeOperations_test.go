@@ -0,0 +1,146 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/types"
+)
+
+type countingLimiter struct {
+	allowed int32
+	calls   int32
+}
+
+func (l *countingLimiter) Allow() bool {
+	return atomic.AddInt32(&l.calls, 1) <= l.allowed
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	return nil
+}
+
+func TestRunConcurrently(t *testing.T) {
+	var inFlight, maxInFlight int32
+	errs := runConcurrently(context.Background(), 20, 3, func(i int) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			peak := atomic.LoadInt32(&maxInFlight)
+			if n <= peak || atomic.CompareAndSwapInt32(&maxInFlight, peak, n) {
+				break
+			}
+		}
+		defer atomic.AddInt32(&inFlight, -1)
+		if i%2 == 0 {
+			return errors.New("even")
+		}
+		return nil
+	})
+	require.Len(t, errs, 20)
+	for i, err := range errs {
+		assert.Equal(t, i%2 == 0, err != nil)
+	}
+	assert.LessOrEqual(t, maxInFlight, int32(3))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errs = runConcurrently(ctx, 2, 1, func(i int) error {
+		t.Error("no call is expected once the context is done")
+		return nil
+	})
+	assert.Equal(t, []error{context.Canceled, context.Canceled}, errs)
+}
+
+func TestGetReplicationTasksFromAllDLQsChargesEveryRead(t *testing.T) {
+	store, manager := newTestExecutionManager(t)
+	store.EXPECT().GetReplicationTasksFromDLQ(gomock.Any(), gomock.Any()).
+		Return(&InternalGetReplicationTasksFromDLQResponse{}, nil).Times(2)
+	limiter := &countingLimiter{allowed: 2}
+	client := NewWorkflowExecutionPersistenceRateLimitedClient(manager, limiter, loggerimpl.NewNopLogger())
+
+	_, err := client.GetReplicationTasksFromAllDLQs(context.Background(), &GetReplicationTasksFromAllDLQsRequest{
+		Requests: map[string]GetReplicationTasksRequest{
+			"cluster-a": {BatchSize: 1},
+			"cluster-b": {BatchSize: 1},
+			"cluster-c": {BatchSize: 1},
+		},
+	})
+	assert.Equal(t, ErrPersistenceLimitExceeded, err)
+	assert.Equal(t, int32(3), limiter.calls)
+}
+
+func TestGetTimerIndexTasksIteratorChargesEveryPage(t *testing.T) {
+	store, manager := newTestExecutionManager(t)
+	store.EXPECT().GetTimerIndexTasks(gomock.Any(), gomock.Any()).Return(&GetTimerIndexTasksResponse{
+		Timers:        []*TimerTaskInfo{{TaskID: 1}},
+		NextPageToken: []byte("page-2"),
+	}, nil).Times(1)
+	limiter := &countingLimiter{allowed: 1}
+	client := NewWorkflowExecutionPersistenceRateLimitedClient(manager, limiter, loggerimpl.NewNopLogger())
+
+	iterator := client.GetTimerIndexTasksIterator(&GetTimerIndexTasksRequest{BatchSize: 1})
+	timer, err := iterator.Next(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), timer.TaskID)
+	_, err = iterator.Next(context.Background())
+	assert.Equal(t, ErrPersistenceLimitExceeded, err)
+	assert.Equal(t, int32(2), limiter.calls)
+}
+
+func TestBatchGetDomains(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	store := NewMockMetadataStore(controller)
+	manager := newTestMetadataManager(store)
+
+	store.EXPECT().GetDomain(gomock.Any(), &GetDomainRequest{ID: "id-1"}).
+		Return(&InternalGetDomainResponse{Info: &DomainInfo{ID: "id-1", Name: "domain-1"}}, nil).Times(2)
+	store.EXPECT().GetDomain(gomock.Any(), &GetDomainRequest{ID: "id-2"}).
+		Return(nil, &types.EntityNotExistsError{Message: "domain does not exist"}).Times(2)
+	resp, err := manager.BatchGetDomains(context.Background(), &BatchGetDomainsRequest{IDs: []string{"id-1", "id-2"}})
+	require.NoError(t, err)
+	require.Len(t, resp.Domains, 1)
+	assert.Equal(t, "domain-1", resp.Domains["id-1"].Info.Name)
+	assert.Equal(t, []string{"id-2"}, resp.MissingIDs)
+
+	// every domain read takes its own token, whether or not the domain exists
+	limiter := &countingLimiter{allowed: 2}
+	client := NewMetadataPersistenceRateLimitedClient(manager, limiter, loggerimpl.NewNopLogger())
+	_, err = client.BatchGetDomains(context.Background(), &BatchGetDomainsRequest{IDs: []string{"id-1", "id-2"}})
+	require.NoError(t, err)
+	_, err = client.BatchGetDomains(context.Background(), &BatchGetDomainsRequest{IDs: []string{"id-1"}})
+	assert.Equal(t, ErrPersistenceLimitExceeded, err)
+	assert.Equal(t, int32(3), limiter.calls)
+
+	store.EXPECT().GetDomain(gomock.Any(), &GetDomainRequest{ID: "id-3"}).
+		Return(nil, &types.InternalServiceError{Message: "unavailable"}).Times(1)
+	_, err = manager.BatchGetDomains(context.Background(), &BatchGetDomainsRequest{IDs: []string{"id-3"}})
+	assert.IsType(t, &types.InternalServiceError{}, err)
+}
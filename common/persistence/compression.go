@@ -0,0 +1,118 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/uber/cadence/common"
+)
+
+type (
+	// CompressionType is the algorithm the data of a DataBlob is compressed with
+	CompressionType string
+)
+
+const (
+	// CompressionTypeNone means the data is not compressed
+	CompressionTypeNone CompressionType = ""
+	// CompressionTypeGzip means the data is compressed with gzip
+	CompressionTypeGzip CompressionType = "gzip"
+)
+
+// storedEncodingSeparator separates the encoding from the compression in the stored encoding of a
+// compressed blob, e.g. thriftrw/gzip
+const storedEncodingSeparator = "/"
+
+// GetStoredEncoding returns the value stores write to the encoding column of the blob, the compression
+// is only appended for compressed blobs so uncompressed blobs are written the way they always were.
+// Older hosts fail to decode the encoding of compressed blobs, see AppendHistoryNodesRequest.Compression
+func (d *DataBlob) GetStoredEncoding() string {
+	if d.Compression == CompressionTypeNone {
+		return string(d.Encoding)
+	}
+	return string(d.Encoding) + storedEncodingSeparator + string(d.Compression)
+}
+
+// ParseStoredEncoding splits the encoding column written by GetStoredEncoding into the encoding and
+// the compression of the blob
+func ParseStoredEncoding(storedEncoding string) (common.EncodingType, CompressionType) {
+	parts := strings.SplitN(storedEncoding, storedEncodingSeparator, 2)
+	if len(parts) == 1 {
+		return common.EncodingType(storedEncoding), CompressionTypeNone
+	}
+	return common.EncodingType(parts[0]), CompressionType(parts[1])
+}
+
+// CompressDataBlob returns a copy of an uncompressed blob with its data compressed with the given algorithm
+func CompressDataBlob(blob *DataBlob, compression CompressionType) (*DataBlob, error) {
+	if blob.Compression != CompressionTypeNone {
+		return nil, fmt.Errorf("data blob is already compressed with %v", blob.Compression)
+	}
+	switch compression {
+	case CompressionTypeNone:
+		return blob, nil
+	case CompressionTypeGzip:
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(blob.Data); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		return &DataBlob{
+			Encoding:    blob.Encoding,
+			Compression: compression,
+			Data:        buf.Bytes(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported compression type: %v", compression)
+	}
+}
+
+// DecompressDataBlob returns a copy of the blob with its data decompressed, uncompressed blobs are returned as is
+func DecompressDataBlob(blob *DataBlob) (*DataBlob, error) {
+	switch blob.Compression {
+	case CompressionTypeNone:
+		return blob, nil
+	case CompressionTypeGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(blob.Data))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		return &DataBlob{
+			Encoding: blob.Encoding,
+			Data:     data,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported compression type: %v", blob.Compression)
+	}
+}
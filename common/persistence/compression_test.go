@@ -0,0 +1,87 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common"
+)
+
+func TestCompressDataBlob(t *testing.T) {
+	blob := NewDataBlob(bytes.Repeat([]byte("history event batch "), 100), common.EncodingTypeThriftRW)
+
+	uncompressed, err := CompressDataBlob(blob, CompressionTypeNone)
+	require.NoError(t, err)
+	assert.Equal(t, blob, uncompressed)
+
+	compressed, err := CompressDataBlob(blob, CompressionTypeGzip)
+	require.NoError(t, err)
+	assert.Equal(t, common.EncodingTypeThriftRW, compressed.Encoding)
+	assert.Equal(t, CompressionTypeGzip, compressed.Compression)
+	assert.True(t, len(compressed.Data) < len(blob.Data))
+
+	_, err = CompressDataBlob(compressed, CompressionTypeGzip)
+	assert.Error(t, err)
+	_, err = CompressDataBlob(blob, CompressionType("lz4"))
+	assert.Error(t, err)
+
+	decompressed, err := DecompressDataBlob(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, blob, decompressed)
+
+	decompressed, err = DecompressDataBlob(blob)
+	require.NoError(t, err)
+	assert.Equal(t, blob, decompressed)
+
+	_, err = DecompressDataBlob(&DataBlob{Encoding: common.EncodingTypeThriftRW, Compression: CompressionTypeGzip, Data: blob.Data})
+	assert.Error(t, err)
+}
+
+func TestStoredEncoding(t *testing.T) {
+	testCases := []struct {
+		blob           *DataBlob
+		storedEncoding string
+	}{
+		{
+			blob:           &DataBlob{Encoding: common.EncodingTypeThriftRW},
+			storedEncoding: "thriftrw",
+		},
+		{
+			blob:           &DataBlob{Encoding: common.EncodingTypeJSON},
+			storedEncoding: "json",
+		},
+		{
+			blob:           &DataBlob{Encoding: common.EncodingTypeThriftRW, Compression: CompressionTypeGzip},
+			storedEncoding: "thriftrw/gzip",
+		},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.storedEncoding, tc.blob.GetStoredEncoding())
+		encoding, compression := ParseStoredEncoding(tc.storedEncoding)
+		assert.Equal(t, tc.blob.Encoding, encoding)
+		assert.Equal(t, tc.blob.Compression, compression)
+	}
+}
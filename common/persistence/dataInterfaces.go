@@ -24,6 +24,7 @@ package persistence
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -93,6 +94,20 @@ const (
 	UpdateWorkflowModeBypassCurrent
 )
 
+// MutableStateField is a section of the mutable state which GetWorkflowExecution can load on its own
+type MutableStateField int
+
+// Mutable State Field
+const (
+	MutableStateFieldActivityInfos MutableStateField = iota + 1
+	MutableStateFieldTimerInfos
+	MutableStateFieldChildExecutionInfos
+	MutableStateFieldRequestCancelInfos
+	MutableStateFieldSignalInfos
+	MutableStateFieldSignalRequestedIDs
+	MutableStateFieldBufferedEvents
+)
+
 // ConflictResolveWorkflowMode conflict resolve mode
 type ConflictResolveWorkflowMode int
 
@@ -106,6 +121,25 @@ const (
 	ConflictResolveWorkflowModeBypassCurrent
 )
 
+// ReadConsistency is the consistency level of a read
+type ReadConsistency int
+
+// Read consistency levels
+const (
+	// Read with the default consistency of the store
+	ReadConsistencyDefault ReadConsistency = iota
+	// Read from a quorum of the replicas in the local data center
+	ReadConsistencyLocalQuorum
+	// Read from a quorum of all the replicas
+	ReadConsistencyQuorum
+	// Read the latest value in the local data center, including the effects
+	// of lightweight transactions which are not fully committed yet
+	ReadConsistencyLocalSerial
+	// Read the latest value, including the effects of lightweight transactions
+	// which are not fully committed yet
+	ReadConsistencySerial
+)
+
 // Workflow execution states
 const (
 	WorkflowStateCreated = iota
@@ -152,6 +186,13 @@ const (
 	TransferTaskTypeUpsertWorkflowSearchAttributes
 )
 
+// Types of cross-cluster tasks
+const (
+	CrossClusterTaskTypeStartChildExecution = iota
+	CrossClusterTaskTypeCancelExecution
+	CrossClusterTaskTypeSignalExecution
+)
+
 // Types of replication tasks
 const (
 	ReplicationTaskTypeHistory = iota
@@ -196,7 +237,27 @@ const (
 
 const numItemsInGarbageInfo = 3
 
+// DefaultRetryPolicy is the default policy for retrying transient persistence errors with RetryTransient
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:        5,
+	InitialInterval:    50 * time.Millisecond,
+	BackoffCoefficient: 2,
+	MaxInterval:        2 * time.Second,
+}
+
 type (
+	// RetryPolicy is the policy for retrying transient persistence errors with RetryTransient
+	RetryPolicy struct {
+		// MaxAttempts is the max number of attempts including the first one, no limit if not positive
+		MaxAttempts int
+		// InitialInterval is the backoff before the first retry
+		InitialInterval time.Duration
+		// BackoffCoefficient is the multiplier applied to the backoff after each retry
+		BackoffCoefficient float64
+		// MaxInterval is the max backoff between two attempts, no limit if not positive
+		MaxInterval time.Duration
+	}
+
 	// InvalidPersistenceRequestError represents invalid request to persistence
 	InvalidPersistenceRequestError struct {
 		Msg string
@@ -207,6 +268,14 @@ type (
 		Msg string
 	}
 
+	// CurrentWorkflowStillRunningError is returned when reusing a workflow ID is blocked
+	// because the current run of the workflow is not closed yet
+	CurrentWorkflowStillRunningError struct {
+		Msg   string
+		RunID string
+		State int
+	}
+
 	// ConditionFailedError represents a failed conditional update for execution record
 	ConditionFailedError struct {
 		Msg string
@@ -223,6 +292,52 @@ type (
 		Msg     string
 	}
 
+	// ShardRangeIDMismatchError is returned when conditional update fails because the RangeID persisted
+	// for the shard no longer matches the RangeID the update was issued with. Unlike ShardOwnershipLostError
+	// it carries both RangeIDs, so the caller can tell a RangeID renewed by itself from the shard being stolen.
+	ShardRangeIDMismatchError struct {
+		ShardID         int
+		ExpectedRangeID int64
+		ActualRangeID   int64
+		Msg             string
+	}
+
+	// TaskListNotOwnedError is returned when a write to a task list fails because the RangeID
+	// the write was issued with no longer matches the persisted one, i.e. the lease was lost
+	TaskListNotOwnedError struct {
+		DomainID        string
+		TaskListName    string
+		TaskType        int
+		ExpectedRangeID int64
+		ActualRangeID   int64
+		Msg             string
+	}
+
+	// DomainVersionConflictError is returned when a domain write fails because the domain metadata
+	// NotificationVersion it was based on is stale, ActualVersion is the version found in the database
+	DomainVersionConflictError struct {
+		ExpectedVersion int64
+		ActualVersion   int64
+		Msg             string
+	}
+
+	// QueueMessageIDConflictError is returned when enqueueing a message at a caller-specified ID
+	// that is already taken in the queue
+	QueueMessageIDConflictError struct {
+		QueueType QueueType
+		MessageID int64
+		Msg       string
+	}
+
+	// HistoryNodeConflictError is returned when appending a history node with FailIfExists
+	// and the node already exists in the branch
+	HistoryNodeConflictError struct {
+		TreeID   string
+		BranchID string
+		NodeID   int64
+		Msg      string
+	}
+
 	// WorkflowExecutionAlreadyStartedError is returned when creating a new workflow failed.
 	WorkflowExecutionAlreadyStartedError struct {
 		Msg              string
@@ -233,9 +348,32 @@ type (
 		LastWriteVersion int64
 	}
 
+	// WorkflowExecutionNotExistsError is returned when a concrete or current execution record does not exist
+	WorkflowExecutionNotExistsError struct {
+		DomainID   string
+		WorkflowID string
+		RunID      string
+		Msg        string
+	}
+
+	// WorkflowExecutionCorruptedError is returned when the checksum of a workflow execution does not match its mutable state
+	WorkflowExecutionCorruptedError struct {
+		Msg             string
+		DomainID        string
+		WorkflowID      string
+		RunID           string
+		ChecksumFlavor  checksum.Flavor
+		ChecksumVersion int
+	}
+
 	// TimeoutError is returned when a write operation fails due to a timeout
 	TimeoutError struct {
 		Msg string
+		// WriteType is the type of write reported by the coordinator when a write timed out after the mutation
+		// was accepted, it is empty for read timeouts and for timeouts where the outcome is unknown
+		WriteType string
+		// Idempotent is whether the timed out write can be safely retried, only meaningful when WriteType is set
+		Idempotent bool
 	}
 
 	// TransactionSizeLimitError is returned when the transaction size is too large
@@ -243,6 +381,11 @@ type (
 		Msg string
 	}
 
+	// OperationNotSupportedError is returned when the persistence store does not support an operation
+	OperationNotSupportedError struct {
+		Msg string
+	}
+
 	// ShardInfo describes a shard
 	ShardInfo struct {
 		ShardID                       int                               `json:"shard_id"`
@@ -386,6 +529,25 @@ type (
 		RecordVisibility        bool
 	}
 
+	// CrossClusterTaskInfo describes a cross-cluster task
+	CrossClusterTaskInfo struct {
+		DomainID                string
+		WorkflowID              string
+		RunID                   string
+		VisibilityTimestamp     time.Time
+		TaskID                  int64
+		TargetCluster           string
+		TargetDomainID          string
+		TargetWorkflowID        string
+		TargetRunID             string
+		TargetChildWorkflowOnly bool
+		TaskList                string
+		TaskType                int
+		ScheduleID              int64
+		Version                 int64
+		RecordVisibility        bool
+	}
+
 	// ReplicationTaskInfo describes the replication task created for replication of history events
 	ReplicationTaskInfo struct {
 		DomainID          string
@@ -399,7 +561,8 @@ type (
 		ScheduledID       int64
 		BranchToken       []byte
 		NewRunBranchToken []byte
-		CreationTime      int64
+		// CreationTime is in unix nanoseconds, use GetCreationTimestamp to read it as a time.Time
+		CreationTime int64
 	}
 
 	// TimerTaskInfo describes a timer task.
@@ -457,6 +620,32 @@ type (
 		SetTaskID(id int64)
 		GetVisibilityTimestamp() time.Time
 		SetVisibilityTimestamp(timestamp time.Time)
+		// Accept calls the method of the visitor matching the concrete type of the task
+		Accept(visitor TaskVisitor) error
+	}
+
+	// TaskVisitor handles each concrete Task type in its own method, so adding
+	// a Task type fails to compile until every visitor handles it
+	TaskVisitor interface {
+		VisitActivityTask(task *ActivityTask) error
+		VisitDecisionTask(task *DecisionTask) error
+		VisitRecordWorkflowStartedTask(task *RecordWorkflowStartedTask) error
+		VisitResetWorkflowTask(task *ResetWorkflowTask) error
+		VisitCloseExecutionTask(task *CloseExecutionTask) error
+		VisitDeleteHistoryEventTask(task *DeleteHistoryEventTask) error
+		VisitDecisionTimeoutTask(task *DecisionTimeoutTask) error
+		VisitActivityTimeoutTask(task *ActivityTimeoutTask) error
+		VisitUserTimerTask(task *UserTimerTask) error
+		VisitActivityRetryTimerTask(task *ActivityRetryTimerTask) error
+		VisitWorkflowBackoffTimerTask(task *WorkflowBackoffTimerTask) error
+		VisitWorkflowTimeoutTask(task *WorkflowTimeoutTask) error
+		VisitCancelExecutionTask(task *CancelExecutionTask) error
+		VisitSignalExecutionTask(task *SignalExecutionTask) error
+		VisitUpsertWorkflowSearchAttributesTask(task *UpsertWorkflowSearchAttributesTask) error
+		VisitStartChildExecutionTask(task *StartChildExecutionTask) error
+		VisitHistoryReplicationTask(task *HistoryReplicationTask) error
+		VisitSyncActivityTask(task *SyncActivityTask) error
+		VisitFailoverMarkerTask(task *FailoverMarkerTask) error
 	}
 
 	// ActivityTask identifies a transfer task for activity
@@ -601,7 +790,7 @@ type (
 		TaskID              int64
 		EventID             int64 // TODO this attribute is not used?
 		Version             int64
-		TimeoutType         int // 0 for retry, 1 for cron.
+		TimeoutType         int // one of the WorkflowBackoffTimeoutType constants
 	}
 
 	// HistoryReplicationTask is the replication task created for shipping history replication events to other clusters
@@ -707,7 +896,8 @@ type (
 		LastFailureReason  string
 		LastWorkerIdentity string
 		LastFailureDetails []byte
-		// Not written to database - This is used only for deduping heartbeat timer creation
+		// Not written to database - This is used only for deduping heartbeat timer creation.
+		// The value is in unix seconds, use GetLastHeartbeatTimeoutVisibility to read it as a time.Time
 		LastHeartbeatTimeoutVisibilityInSeconds int64
 	}
 
@@ -770,10 +960,39 @@ type (
 		ShardInfo *ShardInfo
 	}
 
+	// GetShardsRequest is used to get the information of multiple shards
+	GetShardsRequest struct {
+		ShardIDs []int
+	}
+
+	// GetShardsResponse is the response to GetShards
+	GetShardsResponse struct {
+		ShardInfos       map[int]*ShardInfo
+		NotFoundShardIDs []int
+	}
+
+	// ListShardsRequest is used to list the shards which exist in the persistence store
+	ListShardsRequest struct {
+		PageSize  int
+		PageToken []byte
+	}
+
+	// ListShardsResponse is the response to ListShards, shards are not returned in any particular order
+	// and a page can hold fewer than PageSize shards while NextPageToken is still non-empty
+	ListShardsResponse struct {
+		ShardInfos    []*ShardInfo
+		NextPageToken []byte
+	}
+
 	// UpdateShardRequest  is used to update shard information
 	UpdateShardRequest struct {
 		ShardInfo       *ShardInfo
 		PreviousRangeID int64
+		// PreviousOwner, if set, additionally conditions the update on the current owner of the shard,
+		// ShardOwnershipLostError is returned if the shard is owned by another host.
+		// On Cassandra, a shard row written before schema version 0.34 only records its owner once it has been
+		// updated without PreviousOwner, so the condition fails until then
+		PreviousOwner string
 	}
 
 	// CreateWorkflowExecutionRequest is used to write a new workflow execution
@@ -786,6 +1005,8 @@ type (
 		PreviousLastWriteVersion int64
 
 		NewWorkflowSnapshot WorkflowSnapshot
+
+		WorkflowSizeLimits
 	}
 
 	// CreateWorkflowExecutionResponse is the response to CreateWorkflowExecutionRequest
@@ -796,24 +1017,72 @@ type (
 	GetWorkflowExecutionRequest struct {
 		DomainID  string
 		Execution types.WorkflowExecution
+		// VerifyChecksum verifies the stored checksum against the loaded mutable state,
+		// WorkflowExecutionCorruptedError is returned on mismatch
+		VerifyChecksum bool
+		// ExcludeBufferedEvents skips loading the buffered events, State.BufferedEvents is left empty
+		ExcludeBufferedEvents bool
+		// ExcludeActivityInfos skips loading the activity infos, State.ActivityInfos is left empty
+		// the checksum can not be verified when any part of the mutable state is excluded
+		ExcludeActivityInfos bool
+		// Fields, if not empty, only loads the listed sections of the mutable state on top of the execution info
+		// and the version histories, the sections which are not listed are nil. The exclusions above still apply.
+		Fields []MutableStateField
 	}
 
 	// GetWorkflowExecutionResponse is the response to GetworkflowExecutionRequest
 	GetWorkflowExecutionResponse struct {
 		State             *WorkflowMutableState
 		MutableStateStats *MutableStateStats
+		// DBRecordVersion changes every time the execution record is written, so two reads of the same
+		// execution with equal versions returned the same mutable state. It is 0 if the store does not
+		// provide one, and versions of different executions must not be compared.
+		DBRecordVersion int64
+	}
+
+	// GetWorkflowExecutionForUpdateRequest is used to read the mutable state of a workflow execution
+	// which is about to be updated
+	GetWorkflowExecutionForUpdateRequest struct {
+		DomainID  string
+		Execution types.WorkflowExecution
+		// RangeID of the shard the update is going to be written with
+		RangeID int64
+	}
+
+	// GetWorkflowExecutionForUpdateResponse is the response to GetWorkflowExecutionForUpdateRequest
+	GetWorkflowExecutionForUpdateResponse struct {
+		State             *WorkflowMutableState
+		MutableStateStats *MutableStateStats
+		// UpdateToken is an opaque stamp of the state at read time,
+		// it is meant to be passed back with UpdateWorkflowExecutionRequest.UpdateToken
+		UpdateToken []byte
 	}
 
 	// GetCurrentExecutionRequest is used to retrieve the current RunId for an execution
 	GetCurrentExecutionRequest struct {
 		DomainID   string
 		WorkflowID string
+		// IncludeStats is whether the ExecutionStats of the current run are returned as well
+		IncludeStats bool
+		// Consistency overrides the consistency of the read, stores which always read
+		// the latest committed value ignore it
+		Consistency ReadConsistency
+	}
+
+	// GetCurrentRunIDRequest is used to retrieve only the current RunId for an execution
+	GetCurrentRunIDRequest struct {
+		DomainID   string
+		WorkflowID string
 	}
 
 	// ListCurrentExecutionsRequest is request to ListCurrentExecutions
 	ListCurrentExecutionsRequest struct {
 		PageSize  int
 		PageToken []byte
+		// DomainID limits the result to the current executions of the given domain, all domains are returned if empty.
+		// The filter is applied after a page is read, so a page can hold fewer than PageSize executions, or none at all,
+		// while PageToken is still non-empty.
+		DomainID string
 	}
 
 	// ListCurrentExecutionsResponse is the response to ListCurrentExecutionsRequest
@@ -829,10 +1098,36 @@ type (
 		RunID      string
 	}
 
+	// WorkflowExistsRequest is used to check if any run of a workflow exists
+	WorkflowExistsRequest struct {
+		DomainID   string
+		WorkflowID string
+	}
+
+	// CheckCurrentExecutionRequest is used to check if the current record of a workflow points to a run
+	// which exists
+	CheckCurrentExecutionRequest struct {
+		DomainID   string
+		WorkflowID string
+		// Repair deletes the current record if its run does not exist
+		Repair bool
+		// RangeID of the shard, the repair is only applied if the shard is still owned with it
+		RangeID int64
+	}
+
 	// ListConcreteExecutionsRequest is request to ListConcreteExecutions
 	ListConcreteExecutionsRequest struct {
 		PageSize  int
 		PageToken []byte
+		// StateFilter limits the result to executions in one of the given workflow states, all states are returned if empty.
+		// The filter is applied after a page is read, so a page can hold fewer than PageSize executions, or none at all,
+		// while PageToken is still non-empty.
+		StateFilter []int
+		// SnapshotTime pins a scan to the executions which already existed when it started, executions started
+		// after it are skipped. Pass the same value on every page of a scan. Cassandra reads pages in primary key
+		// order and SQL stores in workflow ID order, in both PageToken is a cursor into that order, so an execution
+		// is returned at most once within a scan. No executions are skipped if SnapshotTime is zero.
+		SnapshotTime time.Time
 	}
 
 	// ListConcreteExecutionsResponse is response to ListConcreteExecutions
@@ -847,6 +1142,19 @@ type (
 		VersionHistories *VersionHistories
 	}
 
+	// CountExecutionsRequest is request to ExecutionManager.CountWorkflowExecutions
+	// the shard is implied by the execution manager the request is sent to
+	CountExecutionsRequest struct{}
+
+	// CountExecutionsResponse is response to ExecutionManager.CountWorkflowExecutions
+	// NOTE: the count is not a snapshot, it may be approximate under concurrent writes
+	CountExecutionsResponse struct {
+		Count int64
+		// ApproximateOnly is set when the backend can only estimate the count, e.g. when the
+		// count is not read from a consistent snapshot of the shard
+		ApproximateOnly bool
+	}
+
 	// GetCurrentExecutionResponse is the response to GetCurrentExecution
 	GetCurrentExecutionResponse struct {
 		StartRequestID   string
@@ -854,6 +1162,13 @@ type (
 		State            int
 		CloseStatus      int
 		LastWriteVersion int64
+		// Stats is only set if IncludeStats is set on the request
+		Stats *ExecutionStats
+	}
+
+	// GetCurrentRunIDResponse is the response to GetCurrentRunID
+	GetCurrentRunIDResponse struct {
+		RunID string
 	}
 
 	// IsWorkflowExecutionExistsResponse is the response to IsWorkflowExecutionExists
@@ -861,6 +1176,34 @@ type (
 		Exists bool
 	}
 
+	// WorkflowExistsResponse is the response to WorkflowExists
+	WorkflowExistsResponse struct {
+		Exists bool
+	}
+
+	// CheckCurrentExecutionResponse is the response to CheckCurrentExecution
+	CheckCurrentExecutionResponse struct {
+		CurrentRunID string
+		// Exists is whether the concrete execution of CurrentRunID exists
+		Exists bool
+		// Repaired is whether the dangling current record was deleted
+		Repaired bool
+	}
+
+	// FlushBufferedEventsRequest is used to move the buffered events of a workflow into its history
+	FlushBufferedEventsRequest struct {
+		// RangeID of the shard, the flush is only applied if the shard is still owned with it
+		RangeID   int64
+		DomainID  string
+		Execution types.WorkflowExecution
+		// HistoryManager appends the flushed events to the current branch of the workflow
+		HistoryManager HistoryManager
+		// TransactionID of the appended history node, it is also used as the task ID of the flushed events
+		TransactionID int64
+		// optional binary encoding type
+		Encoding common.EncodingType
+	}
+
 	// UpdateWorkflowExecutionRequest is used to update a workflow execution
 	UpdateWorkflowExecutionRequest struct {
 		RangeID int64
@@ -872,6 +1215,28 @@ type (
 		NewWorkflowSnapshot *WorkflowSnapshot
 
 		Encoding common.EncodingType // optional binary encoding type
+
+		// UpdateToken is the optional token returned by GetWorkflowExecutionForUpdate, if set the update is
+		// rejected with ConditionFailedError unless RangeID and UpdateWorkflowMutation.Condition match the read
+		UpdateToken []byte
+
+		// CurrentRunID is the optional run ID the caller expects the current record to point to. If set, it must be
+		// the updated run for UpdateWorkflowModeUpdateCurrent, and must not be for UpdateWorkflowModeBypassCurrent.
+		// If not set, the mode is only enforced by the conditional write of the store
+		CurrentRunID string
+
+		WorkflowSizeLimits
+	}
+
+	// WorkflowSizeLimits are the optional budgets in bytes enforced on every workflow written by a request,
+	// zero means no limit
+	WorkflowSizeLimits struct {
+		// MaxMemoSize and MaxSearchAttributesSize are the budgets of the memo and the search attributes
+		// of each workflow, counting both keys and values
+		MaxMemoSize             int
+		MaxSearchAttributesSize int
+		// MaxSignalInputSize is the budget of the input and control of each written signal
+		MaxSignalInputSize int
 	}
 
 	// ConflictResolveWorkflowExecutionRequest is used to reset workflow execution state for a single run
@@ -890,6 +1255,12 @@ type (
 		CurrentWorkflowMutation *WorkflowMutation
 
 		Encoding common.EncodingType // optional binary encoding type
+
+		// DryRun runs all the precondition checks of the live path and returns the error the request
+		// would hit, without writing anything
+		DryRun bool
+
+		WorkflowSizeLimits
 	}
 
 	// ResetWorkflowExecutionRequest is used to reset workflow execution state for current run and create new run
@@ -911,6 +1282,8 @@ type (
 		NewWorkflowSnapshot WorkflowSnapshot
 
 		Encoding common.EncodingType // optional binary encoding type
+
+		WorkflowSizeLimits
 	}
 
 	// WorkflowEvents is used as generic workflow history events transaction container
@@ -984,6 +1357,21 @@ type (
 		DomainID   string
 		WorkflowID string
 		RunID      string
+		// RangeID conditions the delete on the shard range ID, the delete is unconditional if it is zero
+		RangeID int64
+	}
+
+	// DeleteWorkflowExecutionsRequest is used to delete a list of runs of a single workflow
+	DeleteWorkflowExecutionsRequest struct {
+		DomainID   string
+		WorkflowID string
+		RunIDs     []string
+	}
+
+	// DeleteWorkflowExecutionsResponse is the response to DeleteWorkflowExecutionsRequest
+	// Errors holds the error of every run that could not be deleted, keyed by run ID
+	DeleteWorkflowExecutionsResponse struct {
+		Errors map[string]error
 	}
 
 	// GetTransferTasksRequest is used to read tasks from the transfer task queue
@@ -992,12 +1380,31 @@ type (
 		MaxReadLevel  int64
 		BatchSize     int
 		NextPageToken []byte
+		// GroupByType is whether the tasks are also returned grouped by TaskType in TasksByType
+		GroupByType bool
 	}
 
 	// GetTransferTasksResponse is the response to GetTransferTasksRequest
 	GetTransferTasksResponse struct {
 		Tasks         []*TransferTaskInfo
 		NextPageToken []byte
+		// TasksByType is only set if GroupByType is set on the request, tasks keep their order within a type
+		TasksByType map[int][]*TransferTaskInfo
+	}
+
+	// GetCrossClusterTasksRequest is used to read tasks from the cross-cluster task queue of a target cluster
+	GetCrossClusterTasksRequest struct {
+		TargetCluster string
+		ReadLevel     int64
+		MaxReadLevel  int64
+		BatchSize     int
+		NextPageToken []byte
+	}
+
+	// GetCrossClusterTasksResponse is the response to GetCrossClusterTasksRequest
+	GetCrossClusterTasksResponse struct {
+		Tasks         []*CrossClusterTaskInfo
+		NextPageToken []byte
 	}
 
 	// GetReplicationTasksRequest is used to read tasks from the replication task queue
@@ -1006,12 +1413,21 @@ type (
 		MaxReadLevel  int64
 		BatchSize     int
 		NextPageToken []byte
+		// DomainIDFilter, if not nil, only returns the tasks of the given domains.
+		// Replication tasks are not indexed by domain, so the filter is applied to each page after it is read:
+		// a page may contain fewer tasks than BatchSize, or even none, while NextPageToken is still
+		// non empty. Callers should keep paging until NextPageToken is empty.
+		DomainIDFilter map[string]struct{}
 	}
 
 	// GetReplicationTasksResponse is the response to GetReplicationTask
 	GetReplicationTasksResponse struct {
 		Tasks         []*ReplicationTaskInfo
 		NextPageToken []byte
+		// MinTaskID and MaxTaskID are the bounds of the task IDs of the page read from the store,
+		// including tasks which were filtered out of Tasks, both are 0 if the page is empty
+		MinTaskID int64
+		MaxTaskID int64
 	}
 
 	// CompleteTransferTaskRequest is used to complete a task in the transfer task queue
@@ -1025,13 +1441,48 @@ type (
 		InclusiveEndTaskID   int64
 	}
 
+	// CompleteTransferTasksRequest is used to complete a set of tasks in the transfer task queue
+	CompleteTransferTasksRequest struct {
+		// TaskIDs are the IDs of the tasks to complete, they don't need to be sorted or contiguous
+		TaskIDs []int64
+	}
+
+	// CompleteCrossClusterTaskRequest is used to complete a task in the cross-cluster task queue
+	CompleteCrossClusterTaskRequest struct {
+		TargetCluster string
+		TaskID        int64
+	}
+
+	// RangeCompleteCrossClusterTaskRequest is used to complete a range of tasks in the cross-cluster task queue
+	RangeCompleteCrossClusterTaskRequest struct {
+		TargetCluster        string
+		ExclusiveBeginTaskID int64
+		InclusiveEndTaskID   int64
+	}
+
 	// CompleteReplicationTaskRequest is used to complete a task in the replication task queue
 	CompleteReplicationTaskRequest struct {
 		TaskID int64
 	}
 
+	// GetFailoverMarkerTasksRequest is used to read the failover marker tasks of a domain from the replication queue.
+	// The replication queue is not indexed by domain or task type, so the filter is applied to each page
+	// after it is read: a page may contain fewer tasks than BatchSize, or even none, while NextPageToken
+	// is still non empty. Callers should keep paging until NextPageToken is empty.
+	GetFailoverMarkerTasksRequest struct {
+		DomainID string
+		GetReplicationTasksRequest
+	}
+
+	// GetFailoverMarkerTasksResponse is the response for GetFailoverMarkerTasks
+	GetFailoverMarkerTasksResponse struct {
+		Tasks         []*ReplicationTaskInfo
+		NextPageToken []byte
+	}
+
 	// RangeCompleteReplicationTaskRequest is used to complete a range of task in the replication task queue
 	RangeCompleteReplicationTaskRequest struct {
+		// InclusiveEndTaskID is the only bound of the range, every task up to it is completed
 		InclusiveEndTaskID int64
 	}
 
@@ -1041,12 +1492,34 @@ type (
 		TaskInfo          *ReplicationTaskInfo
 	}
 
+	// PutReplicationTasksToDLQRequest is used to put a batch of replication tasks to dlq
+	PutReplicationTasksToDLQRequest struct {
+		SourceClusterName string
+		TaskInfos         []*ReplicationTaskInfo
+	}
+
 	// GetReplicationTasksFromDLQRequest is used to get replication tasks from dlq
 	GetReplicationTasksFromDLQRequest struct {
 		SourceClusterName string
+		// TaskTypeFilter, if set, only returns the tasks of the given replication task type.
+		// The DLQ is not indexed by task type, so the filter is applied to each page after it is read:
+		// a page may contain fewer tasks than BatchSize, or even none, while NextPageToken is still
+		// non empty. Callers should keep paging until NextPageToken is empty.
+		TaskTypeFilter *int
 		GetReplicationTasksRequest
 	}
 
+	// GetReplicationTasksFromAllDLQsRequest is used to read a page of replication tasks from the dlq of
+	// several source clusters at once
+	GetReplicationTasksFromAllDLQsRequest struct {
+		// Requests holds the read of each dlq keyed by the source cluster name, the NextPageToken of each
+		// request pages the dlq of its own source cluster
+		Requests map[string]GetReplicationTasksRequest
+		// TaskTypeFilter, if set, only returns the tasks of the given replication task type, see
+		// GetReplicationTasksFromDLQRequest
+		TaskTypeFilter *int
+	}
+
 	// GetReplicationDLQSizeRequest is used to get one replication task from dlq
 	GetReplicationDLQSizeRequest struct {
 		SourceClusterName string
@@ -1065,9 +1538,22 @@ type (
 		InclusiveEndTaskID   int64
 	}
 
+	// DeleteReplicationTasksFromDLQRequest is used to delete a set of replication tasks from DLQ
+	DeleteReplicationTasksFromDLQRequest struct {
+		SourceClusterName string
+		// TaskIDs are the IDs of the tasks to delete, they don't need to be sorted or contiguous
+		TaskIDs []int64
+	}
+
 	// GetReplicationTasksFromDLQResponse is the response for GetReplicationTasksFromDLQ
 	GetReplicationTasksFromDLQResponse = GetReplicationTasksResponse
 
+	// GetReplicationTasksFromAllDLQsResponse is the response for GetReplicationTasksFromAllDLQs
+	// Responses holds the page read from the dlq of each source cluster, keyed by the source cluster name
+	GetReplicationTasksFromAllDLQsResponse struct {
+		Responses map[string]*GetReplicationTasksFromDLQResponse
+	}
+
 	// GetReplicationDLQSizeResponse is the response for GetReplicationDLQSize
 	GetReplicationDLQSizeResponse struct {
 		Size int64
@@ -1079,6 +1565,14 @@ type (
 		ExclusiveEndTimestamp   time.Time
 	}
 
+	// CompleteTimerTasksForDomainRequest is used to complete all timer tasks of a domain
+	// within an inclusive range of visibility timestamps
+	CompleteTimerTasksForDomainRequest struct {
+		DomainID                string
+		InclusiveBeginTimestamp time.Time
+		InclusiveEndTimestamp   time.Time
+	}
+
 	// CompleteTimerTaskRequest is used to complete a task in the timer task queue
 	CompleteTimerTaskRequest struct {
 		VisibilityTimestamp time.Time
@@ -1092,6 +1586,9 @@ type (
 		TaskType     int
 		TaskListKind int
 		RangeID      int64
+		// ReadOnly returns the current TaskListInfo without bumping the RangeID or taking
+		// over the lease. The task list must already exist.
+		ReadOnly bool
 	}
 
 	// LeaseTaskListResponse is response to LeaseTaskListRequest
@@ -1112,6 +1609,17 @@ type (
 	ListTaskListRequest struct {
 		PageSize  int
 		PageToken []byte
+		// ExcludeExpired skips the task lists which are expired, see TaskListInfo.IsExpired.
+		// The filter is applied after a page is read, so a page can hold fewer than PageSize task lists,
+		// or none at all, while NextPageToken is still non-empty.
+		ExcludeExpired bool
+	}
+
+	// ListTaskListByDomainRequest contains the request params needed to invoke ListTaskListByDomain API
+	ListTaskListByDomainRequest struct {
+		DomainID  string
+		PageSize  int
+		PageToken []byte
 	}
 
 	// ListTaskListResponse is the response from ListTaskList API
@@ -1129,20 +1637,29 @@ type (
 	}
 
 	// CreateTasksRequest is used to create a new task for a workflow exectution
+	// IdempotencyKey is optional, when set a batch whose key matches the last batch
+	// applied to the task list is skipped and reported as success. Only the most
+	// recent batch is remembered, which covers retrying a batch after a timeout.
+	// When IdempotencyKey is empty and every task carries its own key, the batch is
+	// keyed by the task keys instead.
 	CreateTasksRequest struct {
-		TaskListInfo *TaskListInfo
-		Tasks        []*CreateTaskInfo
+		TaskListInfo   *TaskListInfo
+		Tasks          []*CreateTaskInfo
+		IdempotencyKey string
 	}
 
 	// CreateTaskInfo describes a task to be created in CreateTasksRequest
 	CreateTaskInfo struct {
-		Execution types.WorkflowExecution
-		Data      *TaskInfo
-		TaskID    int64
+		Execution      types.WorkflowExecution
+		Data           *TaskInfo
+		TaskID         int64
+		IdempotencyKey string
 	}
 
 	// CreateTasksResponse is the response to CreateTasksRequest
 	CreateTasksResponse struct {
+		// MaxTaskID is the highest task ID written by the request
+		MaxTaskID int64
 	}
 
 	// GetTasksRequest is used to retrieve tasks of a task list
@@ -1160,7 +1677,15 @@ type (
 		Tasks []*TaskInfo
 	}
 
+	// TaskIterator lazily pages through tasks
+	TaskIterator interface {
+		// Next returns the next task, or nil once all tasks up to the max read level have been returned
+		Next(ctx context.Context) (*TaskInfo, error)
+	}
+
 	// CompleteTaskRequest is used to complete a task
+	// If TaskList.RangeID is set, the task is only completed while the task list is still
+	// leased at that RangeID, otherwise TaskListNotOwnedError is returned
 	CompleteTaskRequest struct {
 		TaskList *TaskListInfo
 		TaskID   int64
@@ -1176,28 +1701,45 @@ type (
 	}
 
 	// GetOrphanTasksRequest contains the request params need to invoke the GetOrphanTasks API
+	// Limit caps the number of tasks returned per page, NextPageToken resumes the scan
+	// after the last task of the previous page. A store may bound the work done per page,
+	// so a page can hold fewer than Limit tasks, or none, while NextPageToken is still set.
 	GetOrphanTasksRequest struct {
-		Limit int
+		Limit         int
+		NextPageToken []byte
 	}
 
 	// GetOrphanTasksResponse is the response to GetOrphanTasksRequests
 	GetOrphanTasksResponse struct {
-		Tasks []*TaskKey
+		Tasks         []*TaskKey
+		NextPageToken []byte
 	}
 
 	// GetTimerIndexTasksRequest is the request for GetTimerIndexTasks
-	// TODO: replace this with an iterator that can configure min and max index.
+	// TaskTypeFilter is optional, when set only timers of the listed task types are returned.
+	// The filter is applied to each page after it is read, so a page may hold fewer than
+	// BatchSize timers, or none at all, while NextPageToken is still set.
 	GetTimerIndexTasksRequest struct {
-		MinTimestamp  time.Time
-		MaxTimestamp  time.Time
-		BatchSize     int
-		NextPageToken []byte
+		MinTimestamp   time.Time
+		MaxTimestamp   time.Time
+		BatchSize      int
+		NextPageToken  []byte
+		TaskTypeFilter []int
 	}
 
 	// GetTimerIndexTasksResponse is the response for GetTimerIndexTasks
 	GetTimerIndexTasksResponse struct {
 		Timers        []*TimerTaskInfo
 		NextPageToken []byte
+		// MinVisibilityTimestamp is the earliest visibility timestamp of the page read from the store,
+		// before TaskTypeFilter is applied, it is the zero time if the page is empty
+		MinVisibilityTimestamp time.Time
+	}
+
+	// TimerTaskIterator lazily pages through timer tasks
+	TimerTaskIterator interface {
+		// Next returns the next timer task, or nil once all timers have been returned
+		Next(ctx context.Context) (*TimerTaskInfo, error)
 	}
 
 	// DomainInfo describes the domain entity
@@ -1235,14 +1777,17 @@ type (
 	}
 
 	// CreateDomainRequest is used to create the domain
+	// SkipRetentionValidation bypasses the MetadataManager retention bounds, it is meant for
+	// domains replicated from another cluster, which have already been validated there
 	CreateDomainRequest struct {
-		Info              *DomainInfo
-		Config            *DomainConfig
-		ReplicationConfig *DomainReplicationConfig
-		IsGlobalDomain    bool
-		ConfigVersion     int64
-		FailoverVersion   int64
-		LastUpdatedTime   int64
+		Info                    *DomainInfo
+		Config                  *DomainConfig
+		ReplicationConfig       *DomainReplicationConfig
+		IsGlobalDomain          bool
+		ConfigVersion           int64
+		FailoverVersion         int64
+		LastUpdatedTime         int64
+		SkipRetentionValidation bool
 	}
 
 	// CreateDomainResponse is the response for CreateDomain
@@ -1256,6 +1801,31 @@ type (
 		Name string
 	}
 
+	// BatchGetDomainsRequest is used to read several domains by ID at once
+	BatchGetDomainsRequest struct {
+		IDs []string
+	}
+
+	// BatchGetDomainsResponse is the response for BatchGetDomains
+	BatchGetDomainsResponse struct {
+		// Domains holds the domains which were found, keyed by domain ID
+		Domains map[string]*GetDomainResponse
+		// MissingIDs holds the IDs of the domains which do not exist
+		MissingIDs []string
+	}
+
+	// GetDomainConfigVersionRequest is used to read the versions of a domain, either by ID or by name
+	GetDomainConfigVersionRequest struct {
+		ID   string
+		Name string
+	}
+
+	// GetDomainConfigVersionResponse is the response for GetDomainConfigVersion
+	GetDomainConfigVersionResponse struct {
+		ConfigVersion   int64
+		FailoverVersion int64
+	}
+
 	// GetDomainResponse is the response for GetDomain
 	GetDomainResponse struct {
 		Info                        *DomainInfo
@@ -1269,9 +1839,11 @@ type (
 		FailoverEndTime             *int64
 		LastUpdatedTime             int64
 		NotificationVersion         int64
+		DeletionTime                *int64
 	}
 
 	// UpdateDomainRequest is used to update domain
+	// SkipRetentionValidation has the same meaning as in CreateDomainRequest
 	UpdateDomainRequest struct {
 		Info                        *DomainInfo
 		Config                      *DomainConfig
@@ -1283,6 +1855,15 @@ type (
 		FailoverEndTime             *int64
 		LastUpdatedTime             int64
 		NotificationVersion         int64
+		DeletionTime                *int64
+		SkipRetentionValidation     bool
+	}
+
+	// MarkDomainForDeletionRequest is used to soft delete a domain, the domain is kept around
+	// with DomainStatusDeleted until it is purged with DeleteDomain and DeleteDomainByName
+	MarkDomainForDeletionRequest struct {
+		Name         string
+		DeletionTime int64
 	}
 
 	// DeleteDomainRequest is used to delete domain entry from domains table
@@ -1299,6 +1880,12 @@ type (
 	ListDomainsRequest struct {
 		PageSize      int
 		NextPageToken []byte
+		// ExcludeDeleted skips the domains which are marked for deletion, by default they are returned
+		ExcludeDeleted bool
+		// MinNotificationVersion, if set, only returns the domains with a larger NotificationVersion, so a cache can
+		// refresh the domains which changed since the last version it has seen. The filter is applied after a page
+		// is read, so a page can hold fewer than PageSize domains, or none at all, while NextPageToken is still non-empty.
+		MinNotificationVersion *int64
 	}
 
 	// ListDomainsResponse is the response for GetDomain
@@ -1380,8 +1967,22 @@ type (
 		TransactionID int64
 		// optional binary encoding type
 		Encoding common.EncodingType
+		// optional compression of the serialized events, the events are written uncompressed if it is not set.
+		// Readers decompress the events transparently.
+		// Compressed nodes are stored with a data_encoding such as thriftrw/gzip, which hosts running a version
+		// without compression support cannot read, so only set it once every host of the cluster has been upgraded.
+		Compression CompressionType
 		// The shard to get history node data
 		ShardID *int
+		// optional TTL of the appended node, zero means the node lives until the branch is deleted.
+		// The TTL only applies to this node, it is only safe to use if the caller guarantees that
+		// all nodes of the branch are written with the same TTL, otherwise the branch ends up with holes.
+		// SQL stores do not support it and fail with OperationNotSupportedError
+		TTLSeconds int32
+		// optional, if set the append fails with HistoryNodeConflictError when the node already exists with any
+		// TransactionID instead of being overwritten. Meant for one-shot writers such as importers, it is not safe
+		// against concurrent writers of the same node.
+		FailIfExists bool
 	}
 
 	// AppendHistoryNodesResponse is a response to AppendHistoryNodesRequest
@@ -1405,6 +2006,11 @@ type (
 		NextPageToken []byte
 		// The shard to get history branch data
 		ShardID *int
+		// Read the history nodes from MaxEventID down to MinEventID. Batches are returned newest first,
+		// and so are the events of ReadHistoryBranch, while the events within a batch returned by
+		// ReadHistoryBranchByBatch stay in ascending order. Not supported by ReadRawHistoryBranch.
+		// A token returned by a reverse read can only be used to continue a reverse read.
+		ReverseOrder bool
 	}
 
 	// ReadHistoryBranchResponse is the response to ReadHistoryBranchRequest
@@ -1417,10 +2023,31 @@ type (
 		NextPageToken []byte
 		// Size of history read from store
 		Size int
-		// the first_event_id of last loaded batch
+		// the first_event_id of last loaded batch, for reverse reads this is the
+		// oldest batch of the page, as batches are loaded newest first
 		LastFirstEventID int64
 	}
 
+	// ReadHistoryNodeRequest is used to read a single history node
+	ReadHistoryNodeRequest struct {
+		// The branch to read the node from, the node may belong to an ancestor of the branch
+		BranchToken []byte
+		// The ID of the node, which is the ID of the first event in the batch
+		NodeID int64
+		// The shard to get history node data
+		ShardID *int
+	}
+
+	// ReadHistoryNodeResponse is the response to ReadHistoryNodeRequest
+	ReadHistoryNodeResponse struct {
+		// Events of the batch
+		Events []*types.HistoryEvent
+		// TransactionID of the latest write of the node
+		TransactionID int64
+		// Size of history read from store
+		Size int
+	}
+
 	// ReadHistoryBranchByBatchResponse is the response to ReadHistoryBranchRequest
 	ReadHistoryBranchByBatchResponse struct {
 		// History events by batch
@@ -1431,7 +2058,8 @@ type (
 		NextPageToken []byte
 		// Size of history read from store
 		Size int
-		// the first_event_id of last loaded batch
+		// the first_event_id of last loaded batch, for reverse reads this is the
+		// oldest batch of the page, as batches are loaded newest first
 		LastFirstEventID int64
 	}
 
@@ -1447,6 +2075,22 @@ type (
 		Size int
 	}
 
+	// ReadHistoryBranchWithBlobsResponse is the response to ReadHistoryBranchWithBlobs
+	ReadHistoryBranchWithBlobsResponse struct {
+		// History events by batch
+		History []*types.History
+		// HistoryEventBlobs are the blobs the batches are decoded from, HistoryEventBlobs[i] holds History[i]
+		HistoryEventBlobs []*DataBlob
+		// Token to read next page if there are more events beyond page size.
+		// Use this to set NextPageToken on ReadHistoryBranchRequest to read the next page.
+		// Empty means we have reached the last page, not need to continue
+		NextPageToken []byte
+		// Size of history read from store
+		Size int
+		// the first_event_id of last loaded batch
+		LastFirstEventID int64
+	}
+
 	// ForkHistoryBranchRequest is used to fork a history branch
 	ForkHistoryBranchRequest struct {
 		// The base branch to fork from
@@ -1483,6 +2127,9 @@ type (
 		BranchToken []byte
 		// The shard to delete history branch data
 		ShardID *int
+		// PreserveAncestors restricts the deletion to the nodes exclusively owned by this branch,
+		// so nodes still reachable from any other branch of the tree are kept
+		PreserveAncestors bool
 	}
 
 	// GetHistoryTreeRequest is used to retrieve branch info of a history tree
@@ -1493,6 +2140,10 @@ type (
 		ShardID *int
 		// optional: can provide treeID via branchToken if treeID is empty
 		BranchToken []byte
+		// optional: maximum number of branches returned per page, all branches are returned when zero
+		PageSize int
+		// pagination token, pass in empty slice for first page
+		NextPageToken []byte
 	}
 
 	// HistoryBranchDetail contains detailed information of a branch
@@ -1505,8 +2156,10 @@ type (
 
 	// GetHistoryTreeResponse is a response to GetHistoryTreeRequest
 	GetHistoryTreeResponse struct {
-		// all branches of a tree
+		// all branches of a tree, or a single page of them when PageSize is set
 		Branches []*workflow.HistoryBranch
+		// token to read the next page, empty means there are no more branches
+		NextPageToken []byte
 	}
 
 	// GetAllHistoryTreeBranchesRequest is a request of GetAllHistoryTreeBranches
@@ -1536,55 +2189,106 @@ type (
 		Close()
 	}
 
+	// ContextCloseable is a Closeable which can be closed within a deadline
+	ContextCloseable interface {
+		Closeable
+		// CloseWithContext releases the resources like Close, but stops waiting once ctx is done.
+		// ctx.Err() is returned if the resources are not released by then, they keep being released in the background.
+		CloseWithContext(ctx context.Context) error
+	}
+
 	// ShardManager is used to manage all shards
 	ShardManager interface {
-		Closeable
+		ContextCloseable
 		GetName() string
 		CreateShard(ctx context.Context, request *CreateShardRequest) error
 		GetShard(ctx context.Context, request *GetShardRequest) (*GetShardResponse, error)
+		GetShards(ctx context.Context, request *GetShardsRequest) (*GetShardsResponse, error)
+		ListShards(ctx context.Context, request *ListShardsRequest) (*ListShardsResponse, error)
 		UpdateShard(ctx context.Context, request *UpdateShardRequest) error
 	}
 
 	// ExecutionManager is used to manage workflow executions
 	ExecutionManager interface {
-		Closeable
+		ContextCloseable
 		GetName() string
 		GetShardID() int
 
 		CreateWorkflowExecution(ctx context.Context, request *CreateWorkflowExecutionRequest) (*CreateWorkflowExecutionResponse, error)
 		GetWorkflowExecution(ctx context.Context, request *GetWorkflowExecutionRequest) (*GetWorkflowExecutionResponse, error)
+		// GetWorkflowExecutionForUpdate returns the mutable state along with a token to be passed to UpdateWorkflowExecution,
+		// so that an update made from a stale read is rejected
+		GetWorkflowExecutionForUpdate(ctx context.Context, request *GetWorkflowExecutionForUpdateRequest) (*GetWorkflowExecutionForUpdateResponse, error)
 		UpdateWorkflowExecution(ctx context.Context, request *UpdateWorkflowExecutionRequest) (*UpdateWorkflowExecutionResponse, error)
+		// FlushBufferedEvents appends the buffered events of a workflow to its history and clears them from the
+		// mutable state, without driving a decision. It is a repair primitive for workflows stuck with a large buffer.
+		// Unlike a flush driven by a decision, the started IDs which refer to a buffered event, such as the StartedID
+		// of an activity info, are not remapped to the flushed event IDs
+		FlushBufferedEvents(ctx context.Context, request *FlushBufferedEventsRequest) error
 		ConflictResolveWorkflowExecution(ctx context.Context, request *ConflictResolveWorkflowExecutionRequest) error
 		ResetWorkflowExecution(ctx context.Context, request *ResetWorkflowExecutionRequest) error
 		DeleteWorkflowExecution(ctx context.Context, request *DeleteWorkflowExecutionRequest) error
 		DeleteCurrentWorkflowExecution(ctx context.Context, request *DeleteCurrentWorkflowExecutionRequest) error
+		// DeleteWorkflowExecutions deletes the concrete execution, and the current record when it points to
+		// the run, of every run in the request, a failure of one run does not stop the others
+		DeleteWorkflowExecutions(ctx context.Context, request *DeleteWorkflowExecutionsRequest) (*DeleteWorkflowExecutionsResponse, error)
 		GetCurrentExecution(ctx context.Context, request *GetCurrentExecutionRequest) (*GetCurrentExecutionResponse, error)
+		// GetCurrentRunID is a cheaper version of GetCurrentExecution which only reads the current RunId
+		GetCurrentRunID(ctx context.Context, request *GetCurrentRunIDRequest) (*GetCurrentRunIDResponse, error)
 		IsWorkflowExecutionExists(ctx context.Context, request *IsWorkflowExecutionExistsRequest) (*IsWorkflowExecutionExistsResponse, error)
+		// WorkflowExists checks if any run of the workflow exists without loading it
+		WorkflowExists(ctx context.Context, request *WorkflowExistsRequest) (*WorkflowExistsResponse, error)
+		// CheckCurrentExecution checks if the run of the current record exists, and optionally deletes the
+		// current record if it does not
+		CheckCurrentExecution(ctx context.Context, request *CheckCurrentExecutionRequest) (*CheckCurrentExecutionResponse, error)
 
 		// Transfer task related methods
 		GetTransferTasks(ctx context.Context, request *GetTransferTasksRequest) (*GetTransferTasksResponse, error)
 		CompleteTransferTask(ctx context.Context, request *CompleteTransferTaskRequest) error
 		RangeCompleteTransferTask(ctx context.Context, request *RangeCompleteTransferTaskRequest) error
+		// CompleteTransferTasks completes the given set of tasks, tasks are not deleted in any particular
+		// order and some of them may already be deleted when an error is returned
+		CompleteTransferTasks(ctx context.Context, request *CompleteTransferTasksRequest) error
+
+		// Cross-cluster task related methods
+		GetCrossClusterTasks(ctx context.Context, request *GetCrossClusterTasksRequest) (*GetCrossClusterTasksResponse, error)
+		CompleteCrossClusterTask(ctx context.Context, request *CompleteCrossClusterTaskRequest) error
+		RangeCompleteCrossClusterTask(ctx context.Context, request *RangeCompleteCrossClusterTaskRequest) error
 
 		// Replication task related methods
 		GetReplicationTasks(ctx context.Context, request *GetReplicationTasksRequest) (*GetReplicationTasksResponse, error)
+		GetFailoverMarkerTasks(ctx context.Context, request *GetFailoverMarkerTasksRequest) (*GetFailoverMarkerTasksResponse, error)
 		CompleteReplicationTask(ctx context.Context, request *CompleteReplicationTaskRequest) error
 		RangeCompleteReplicationTask(ctx context.Context, request *RangeCompleteReplicationTaskRequest) error
 		PutReplicationTaskToDLQ(ctx context.Context, request *PutReplicationTaskToDLQRequest) error
+		PutReplicationTasksToDLQ(ctx context.Context, request *PutReplicationTasksToDLQRequest) error
 		GetReplicationTasksFromDLQ(ctx context.Context, request *GetReplicationTasksFromDLQRequest) (*GetReplicationTasksFromDLQResponse, error)
+		// GetReplicationTasksFromAllDLQs reads a page from the dlq of every source cluster in the request,
+		// the dlqs are read concurrently and the call fails if any of the reads fails
+		GetReplicationTasksFromAllDLQs(ctx context.Context, request *GetReplicationTasksFromAllDLQsRequest) (*GetReplicationTasksFromAllDLQsResponse, error)
 		GetReplicationDLQSize(ctx context.Context, request *GetReplicationDLQSizeRequest) (*GetReplicationDLQSizeResponse, error)
 		DeleteReplicationTaskFromDLQ(ctx context.Context, request *DeleteReplicationTaskFromDLQRequest) error
 		RangeDeleteReplicationTaskFromDLQ(ctx context.Context, request *RangeDeleteReplicationTaskFromDLQRequest) error
+		// DeleteReplicationTasksFromDLQ deletes the given set of tasks from DLQ, tasks are not deleted in any particular
+		// order and some of them may already be deleted when an error is returned
+		DeleteReplicationTasksFromDLQ(ctx context.Context, request *DeleteReplicationTasksFromDLQRequest) error
 		CreateFailoverMarkerTasks(ctx context.Context, request *CreateFailoverMarkersRequest) error
 
 		// Timer related methods.
 		GetTimerIndexTasks(ctx context.Context, request *GetTimerIndexTasksRequest) (*GetTimerIndexTasksResponse, error)
+		// GetTimerIndexTasksIterator returns an iterator that lazily pages through GetTimerIndexTasks
+		GetTimerIndexTasksIterator(request *GetTimerIndexTasksRequest) TimerTaskIterator
 		CompleteTimerTask(ctx context.Context, request *CompleteTimerTaskRequest) error
 		RangeCompleteTimerTask(ctx context.Context, request *RangeCompleteTimerTaskRequest) error
+		// CompleteTimerTasksForDomain returns the number of tasks deleted, or UnknownNumRowsAffected
+		// if the underlying store is not able to report it
+		CompleteTimerTasksForDomain(ctx context.Context, request *CompleteTimerTasksForDomainRequest) (int, error)
 
 		// Scan operations
 		ListConcreteExecutions(ctx context.Context, request *ListConcreteExecutionsRequest) (*ListConcreteExecutionsResponse, error)
 		ListCurrentExecutions(ctx context.Context, request *ListCurrentExecutionsRequest) (*ListCurrentExecutionsResponse, error)
+		// CountWorkflowExecutions counts the concrete executions in the shard, the count may be approximate under concurrent writes
+		CountWorkflowExecutions(ctx context.Context, request *CountExecutionsRequest) (*CountExecutionsResponse, error)
 	}
 
 	// ExecutionManagerFactory creates an instance of ExecutionManager for a given shard
@@ -1595,14 +2299,17 @@ type (
 
 	// TaskManager is used to manage tasks
 	TaskManager interface {
-		Closeable
+		ContextCloseable
 		GetName() string
 		LeaseTaskList(ctx context.Context, request *LeaseTaskListRequest) (*LeaseTaskListResponse, error)
 		UpdateTaskList(ctx context.Context, request *UpdateTaskListRequest) (*UpdateTaskListResponse, error)
 		ListTaskList(ctx context.Context, request *ListTaskListRequest) (*ListTaskListResponse, error)
+		ListTaskListByDomain(ctx context.Context, request *ListTaskListByDomainRequest) (*ListTaskListResponse, error)
 		DeleteTaskList(ctx context.Context, request *DeleteTaskListRequest) error
 		CreateTasks(ctx context.Context, request *CreateTasksRequest) (*CreateTasksResponse, error)
 		GetTasks(ctx context.Context, request *GetTasksRequest) (*GetTasksResponse, error)
+		// GetTasksIterator returns an iterator that lazily pages through GetTasks
+		GetTasksIterator(request *GetTasksRequest) TaskIterator
 		CompleteTask(ctx context.Context, request *CompleteTaskRequest) error
 		CompleteTasksLessThan(ctx context.Context, request *CompleteTasksLessThanRequest) (int, error)
 		GetOrphanTasks(ctx context.Context, request *GetOrphanTasksRequest) (*GetOrphanTasksResponse, error)
@@ -1610,7 +2317,7 @@ type (
 
 	// HistoryManager is used to manager workflow history events
 	HistoryManager interface {
-		Closeable
+		ContextCloseable
 		GetName() string
 
 		// The below are history V2 APIs
@@ -1623,9 +2330,16 @@ type (
 		ReadHistoryBranch(ctx context.Context, request *ReadHistoryBranchRequest) (*ReadHistoryBranchResponse, error)
 		// ReadHistoryBranchByBatch returns history node data for a branch ByBatch
 		ReadHistoryBranchByBatch(ctx context.Context, request *ReadHistoryBranchRequest) (*ReadHistoryBranchByBatchResponse, error)
+		// ReadHistoryBranchIterator pages through a branch and invokes the callback for every event, stopping on the first callback error
+		ReadHistoryBranchIterator(ctx context.Context, request *ReadHistoryBranchRequest, callback func(event *types.HistoryEvent) error) error
 		// ReadRawHistoryBranch returns history node raw data for a branch ByBatch
 		// NOTE: this API should only be used by 3+DC
 		ReadRawHistoryBranch(ctx context.Context, request *ReadHistoryBranchRequest) (*ReadRawHistoryBranchResponse, error)
+		// ReadHistoryBranchWithBlobs returns history node data for a branch by batch together with the raw blob
+		// of each batch, so callers needing both don't read the branch twice. Does not support ReverseOrder.
+		ReadHistoryBranchWithBlobs(ctx context.Context, request *ReadHistoryBranchRequest) (*ReadHistoryBranchWithBlobsResponse, error)
+		// ReadHistoryNode returns the single batch of events starting at the node ID, without reading the rest of the branch
+		ReadHistoryNode(ctx context.Context, request *ReadHistoryNodeRequest) (*ReadHistoryNodeResponse, error)
 		// ForkHistoryBranch forks a new branch from a old branch
 		ForkHistoryBranch(ctx context.Context, request *ForkHistoryBranchRequest) (*ForkHistoryBranchResponse, error)
 		// DeleteHistoryBranch removes a branch
@@ -1639,11 +2353,18 @@ type (
 
 	// MetadataManager is used to manage metadata CRUD for domain entities
 	MetadataManager interface {
-		Closeable
+		ContextCloseable
 		GetName() string
 		CreateDomain(ctx context.Context, request *CreateDomainRequest) (*CreateDomainResponse, error)
 		GetDomain(ctx context.Context, request *GetDomainRequest) (*GetDomainResponse, error)
+		// BatchGetDomains reads the domains with the given IDs concurrently, the IDs of the domains which
+		// do not exist are returned instead of an error
+		BatchGetDomains(ctx context.Context, request *BatchGetDomainsRequest) (*BatchGetDomainsResponse, error)
+		// GetDomainConfigVersion reads only the versions of a domain, so a cache can cheaply tell if it is stale
+		GetDomainConfigVersion(ctx context.Context, request *GetDomainConfigVersionRequest) (*GetDomainConfigVersionResponse, error)
+		// UpdateDomain returns DomainVersionConflictError if the request NotificationVersion is stale
 		UpdateDomain(ctx context.Context, request *UpdateDomainRequest) error
+		MarkDomainForDeletion(ctx context.Context, request *MarkDomainForDeletionRequest) error
 		DeleteDomain(ctx context.Context, request *DeleteDomainRequest) error
 		DeleteDomainByName(ctx context.Context, request *DeleteDomainByNameRequest) error
 		ListDomains(ctx context.Context, request *ListDomainsRequest) (*ListDomainsResponse, error)
@@ -1652,8 +2373,14 @@ type (
 
 	// QueueManager is used to manage queue store
 	QueueManager interface {
-		Closeable
+		ContextCloseable
 		EnqueueMessage(ctx context.Context, messagePayload []byte) error
+		// EnqueueMessageWithID writes the message at the given ID instead of the next free one, so a
+		// replayed message lands on the same ID. It returns QueueMessageIDConflictError if the ID is taken.
+		EnqueueMessageWithID(ctx context.Context, messagePayload []byte, messageID int64) error
+		// EnqueueMessages writes the messages at consecutive IDs and returns the assigned IDs in order.
+		// The write is all or nothing: on error none of the messages are enqueued.
+		EnqueueMessages(ctx context.Context, messagePayloads [][]byte) ([]int64, error)
 		ReadMessages(ctx context.Context, lastMessageID int64, maxCount int) ([]*QueueMessage, error)
 		DeleteMessagesBefore(ctx context.Context, messageID int64) error
 		UpdateAckLevel(ctx context.Context, messageID int64, clusterName string) error
@@ -1683,6 +2410,10 @@ func (e *CurrentWorkflowConditionFailedError) Error() string {
 	return e.Msg
 }
 
+func (e *CurrentWorkflowStillRunningError) Error() string {
+	return e.Msg
+}
+
 func (e *ConditionFailedError) Error() string {
 	return e.Msg
 }
@@ -1695,10 +2426,38 @@ func (e *ShardOwnershipLostError) Error() string {
 	return e.Msg
 }
 
+func (e *ShardRangeIDMismatchError) Error() string {
+	return e.Msg
+}
+
+func (e *TaskListNotOwnedError) Error() string {
+	return e.Msg
+}
+
+func (e *DomainVersionConflictError) Error() string {
+	return e.Msg
+}
+
+func (e *QueueMessageIDConflictError) Error() string {
+	return e.Msg
+}
+
+func (e *HistoryNodeConflictError) Error() string {
+	return e.Msg
+}
+
 func (e *WorkflowExecutionAlreadyStartedError) Error() string {
 	return e.Msg
 }
 
+func (e *WorkflowExecutionNotExistsError) Error() string {
+	return e.Msg
+}
+
+func (e *WorkflowExecutionCorruptedError) Error() string {
+	return e.Msg
+}
+
 func (e *TimeoutError) Error() string {
 	return e.Msg
 }
@@ -1707,12 +2466,51 @@ func (e *TransactionSizeLimitError) Error() string {
 	return e.Msg
 }
 
+func (e *OperationNotSupportedError) Error() string {
+	return e.Msg
+}
+
 // IsTimeoutError check whether error is TimeoutError
 func IsTimeoutError(err error) bool {
 	_, ok := err.(*TimeoutError)
 	return ok
 }
 
+// IsShardOwnershipLostError checks whether error indicates the shard is owned by someone else.
+// ShardRangeIDMismatchError is deliberately excluded, as the RangeID may have been moved by the current owner.
+func IsShardOwnershipLostError(err error) bool {
+	_, ok := err.(*ShardOwnershipLostError)
+	return ok
+}
+
+// IsCurrentWorkflowStillRunningError checks whether error indicates a workflow ID could not be reused
+// because its current run is still open
+func IsCurrentWorkflowStillRunningError(err error) bool {
+	_, ok := err.(*CurrentWorkflowStillRunningError)
+	return ok
+}
+
+// IsTaskListNotOwnedError checks whether error indicates the task list lease is held at a different RangeID
+func IsTaskListNotOwnedError(err error) bool {
+	_, ok := err.(*TaskListNotOwnedError)
+	return ok
+}
+
+// IsDomainVersionConflictError checks whether error indicates a domain write was based on a stale NotificationVersion
+func IsDomainVersionConflictError(err error) bool {
+	_, ok := err.(*DomainVersionConflictError)
+	return ok
+}
+
+// IsNotExistsError checks whether error indicates the requested entity does not exist
+func IsNotExistsError(err error) bool {
+	switch err.(type) {
+	case *WorkflowExecutionNotExistsError, *types.EntityNotExistsError:
+		return true
+	}
+	return false
+}
+
 // GetType returns the type of the activity task
 func (a *ActivityTask) GetType() int {
 	return TransferTaskTypeActivityTask
@@ -1748,6 +2546,11 @@ func (a *ActivityTask) SetVisibilityTimestamp(timestamp time.Time) {
 	a.VisibilityTimestamp = timestamp
 }
 
+// Accept calls the method of the visitor matching the ActivityTask
+func (a *ActivityTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitActivityTask(a)
+}
+
 // GetType returns the type of the decision task
 func (d *DecisionTask) GetType() int {
 	return TransferTaskTypeDecisionTask
@@ -1783,6 +2586,11 @@ func (d *DecisionTask) SetVisibilityTimestamp(timestamp time.Time) {
 	d.VisibilityTimestamp = timestamp
 }
 
+// Accept calls the method of the visitor matching the DecisionTask
+func (d *DecisionTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitDecisionTask(d)
+}
+
 // GetType returns the type of the record workflow started task
 func (a *RecordWorkflowStartedTask) GetType() int {
 	return TransferTaskTypeRecordWorkflowStarted
@@ -1818,6 +2626,11 @@ func (a *RecordWorkflowStartedTask) SetVisibilityTimestamp(timestamp time.Time)
 	a.VisibilityTimestamp = timestamp
 }
 
+// Accept calls the method of the visitor matching the RecordWorkflowStartedTask
+func (a *RecordWorkflowStartedTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitRecordWorkflowStartedTask(a)
+}
+
 // GetType returns the type of the ResetWorkflowTask
 func (a *ResetWorkflowTask) GetType() int {
 	return TransferTaskTypeResetWorkflow
@@ -1853,6 +2666,11 @@ func (a *ResetWorkflowTask) SetVisibilityTimestamp(timestamp time.Time) {
 	a.VisibilityTimestamp = timestamp
 }
 
+// Accept calls the method of the visitor matching the ResetWorkflowTask
+func (a *ResetWorkflowTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitResetWorkflowTask(a)
+}
+
 // GetType returns the type of the close execution task
 func (a *CloseExecutionTask) GetType() int {
 	return TransferTaskTypeCloseExecution
@@ -1888,6 +2706,11 @@ func (a *CloseExecutionTask) SetVisibilityTimestamp(timestamp time.Time) {
 	a.VisibilityTimestamp = timestamp
 }
 
+// Accept calls the method of the visitor matching the CloseExecutionTask
+func (a *CloseExecutionTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitCloseExecutionTask(a)
+}
+
 // GetType returns the type of the delete execution task
 func (a *DeleteHistoryEventTask) GetType() int {
 	return TaskTypeDeleteHistoryEvent
@@ -1923,6 +2746,11 @@ func (a *DeleteHistoryEventTask) SetVisibilityTimestamp(timestamp time.Time) {
 	a.VisibilityTimestamp = timestamp
 }
 
+// Accept calls the method of the visitor matching the DeleteHistoryEventTask
+func (a *DeleteHistoryEventTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitDeleteHistoryEventTask(a)
+}
+
 // GetType returns the type of the timer task
 func (d *DecisionTimeoutTask) GetType() int {
 	return TaskTypeDecisionTimeout
@@ -1958,6 +2786,11 @@ func (d *DecisionTimeoutTask) SetVisibilityTimestamp(t time.Time) {
 	d.VisibilityTimestamp = t
 }
 
+// Accept calls the method of the visitor matching the DecisionTimeoutTask
+func (d *DecisionTimeoutTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitDecisionTimeoutTask(d)
+}
+
 // GetType returns the type of the timer task
 func (a *ActivityTimeoutTask) GetType() int {
 	return TaskTypeActivityTimeout
@@ -1993,6 +2826,11 @@ func (a *ActivityTimeoutTask) SetVisibilityTimestamp(t time.Time) {
 	a.VisibilityTimestamp = t
 }
 
+// Accept calls the method of the visitor matching the ActivityTimeoutTask
+func (a *ActivityTimeoutTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitActivityTimeoutTask(a)
+}
+
 // GetType returns the type of the timer task
 func (u *UserTimerTask) GetType() int {
 	return TaskTypeUserTimer
@@ -2028,6 +2866,11 @@ func (u *UserTimerTask) SetVisibilityTimestamp(t time.Time) {
 	u.VisibilityTimestamp = t
 }
 
+// Accept calls the method of the visitor matching the UserTimerTask
+func (u *UserTimerTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitUserTimerTask(u)
+}
+
 // GetType returns the type of the retry timer task
 func (r *ActivityRetryTimerTask) GetType() int {
 	return TaskTypeActivityRetryTimer
@@ -2063,6 +2906,42 @@ func (r *ActivityRetryTimerTask) SetVisibilityTimestamp(t time.Time) {
 	r.VisibilityTimestamp = t
 }
 
+// Accept calls the method of the visitor matching the ActivityRetryTimerTask
+func (r *ActivityRetryTimerTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitActivityRetryTimerTask(r)
+}
+
+// NewWorkflowBackoffTimerTask creates a WorkflowBackoffTimerTask,
+// an error is returned if timeoutType is not one of the WorkflowBackoffTimeoutType constants
+func NewWorkflowBackoffTimerTask(
+	visibilityTimestamp time.Time,
+	timeoutType int,
+	version int64,
+) (*WorkflowBackoffTimerTask, error) {
+	switch timeoutType {
+	case WorkflowBackoffTimeoutTypeRetry, WorkflowBackoffTimeoutTypeCron:
+	default:
+		return nil, &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("unknown workflow backoff timeout type: %v", timeoutType),
+		}
+	}
+	return &WorkflowBackoffTimerTask{
+		VisibilityTimestamp: visibilityTimestamp,
+		TimeoutType:         timeoutType,
+		Version:             version,
+	}, nil
+}
+
+// IsRetry returns whether the backoff is for retrying the workflow
+func (r *WorkflowBackoffTimerTask) IsRetry() bool {
+	return r.TimeoutType == WorkflowBackoffTimeoutTypeRetry
+}
+
+// IsCron returns whether the backoff is for the next cron run of the workflow
+func (r *WorkflowBackoffTimerTask) IsCron() bool {
+	return r.TimeoutType == WorkflowBackoffTimeoutTypeCron
+}
+
 // GetType returns the type of the retry timer task
 func (r *WorkflowBackoffTimerTask) GetType() int {
 	return TaskTypeWorkflowBackoffTimer
@@ -2098,6 +2977,11 @@ func (r *WorkflowBackoffTimerTask) SetVisibilityTimestamp(t time.Time) {
 	r.VisibilityTimestamp = t
 }
 
+// Accept calls the method of the visitor matching the WorkflowBackoffTimerTask
+func (r *WorkflowBackoffTimerTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitWorkflowBackoffTimerTask(r)
+}
+
 // GetType returns the type of the timeout task.
 func (u *WorkflowTimeoutTask) GetType() int {
 	return TaskTypeWorkflowTimeout
@@ -2133,6 +3017,11 @@ func (u *WorkflowTimeoutTask) SetVisibilityTimestamp(t time.Time) {
 	u.VisibilityTimestamp = t
 }
 
+// Accept calls the method of the visitor matching the WorkflowTimeoutTask
+func (u *WorkflowTimeoutTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitWorkflowTimeoutTask(u)
+}
+
 // GetType returns the type of the cancel transfer task
 func (u *CancelExecutionTask) GetType() int {
 	return TransferTaskTypeCancelExecution
@@ -2168,6 +3057,11 @@ func (u *CancelExecutionTask) SetVisibilityTimestamp(timestamp time.Time) {
 	u.VisibilityTimestamp = timestamp
 }
 
+// Accept calls the method of the visitor matching the CancelExecutionTask
+func (u *CancelExecutionTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitCancelExecutionTask(u)
+}
+
 // GetType returns the type of the signal transfer task
 func (u *SignalExecutionTask) GetType() int {
 	return TransferTaskTypeSignalExecution
@@ -2203,6 +3097,11 @@ func (u *SignalExecutionTask) SetVisibilityTimestamp(timestamp time.Time) {
 	u.VisibilityTimestamp = timestamp
 }
 
+// Accept calls the method of the visitor matching the SignalExecutionTask
+func (u *SignalExecutionTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitSignalExecutionTask(u)
+}
+
 // GetType returns the type of the upsert search attributes transfer task
 func (u *UpsertWorkflowSearchAttributesTask) GetType() int {
 	return TransferTaskTypeUpsertWorkflowSearchAttributes
@@ -2238,6 +3137,11 @@ func (u *UpsertWorkflowSearchAttributesTask) SetVisibilityTimestamp(timestamp ti
 	u.VisibilityTimestamp = timestamp
 }
 
+// Accept calls the method of the visitor matching the UpsertWorkflowSearchAttributesTask
+func (u *UpsertWorkflowSearchAttributesTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitUpsertWorkflowSearchAttributesTask(u)
+}
+
 // GetType returns the type of the start child transfer task
 func (u *StartChildExecutionTask) GetType() int {
 	return TransferTaskTypeStartChildExecution
@@ -2273,6 +3177,11 @@ func (u *StartChildExecutionTask) SetVisibilityTimestamp(timestamp time.Time) {
 	u.VisibilityTimestamp = timestamp
 }
 
+// Accept calls the method of the visitor matching the StartChildExecutionTask
+func (u *StartChildExecutionTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitStartChildExecutionTask(u)
+}
+
 // GetType returns the type of the history replication task
 func (a *HistoryReplicationTask) GetType() int {
 	return ReplicationTaskTypeHistory
@@ -2308,6 +3217,11 @@ func (a *HistoryReplicationTask) SetVisibilityTimestamp(timestamp time.Time) {
 	a.VisibilityTimestamp = timestamp
 }
 
+// Accept calls the method of the visitor matching the HistoryReplicationTask
+func (a *HistoryReplicationTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitHistoryReplicationTask(a)
+}
+
 // GetType returns the type of the history replication task
 func (a *SyncActivityTask) GetType() int {
 	return ReplicationTaskTypeSyncActivity
@@ -2343,6 +3257,11 @@ func (a *SyncActivityTask) SetVisibilityTimestamp(timestamp time.Time) {
 	a.VisibilityTimestamp = timestamp
 }
 
+// Accept calls the method of the visitor matching the SyncActivityTask
+func (a *SyncActivityTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitSyncActivityTask(a)
+}
+
 // GetType returns the type of the history replication task
 func (a *FailoverMarkerTask) GetType() int {
 	return ReplicationTaskTypeFailoverMarker
@@ -2378,6 +3297,11 @@ func (a *FailoverMarkerTask) SetVisibilityTimestamp(timestamp time.Time) {
 	a.VisibilityTimestamp = timestamp
 }
 
+// Accept calls the method of the visitor matching the FailoverMarkerTask
+func (a *FailoverMarkerTask) Accept(visitor TaskVisitor) error {
+	return visitor.VisitFailoverMarkerTask(a)
+}
+
 // GetTaskID returns the task ID for transfer task
 func (t *TransferTaskInfo) GetTaskID() int64 {
 	return t.TaskID
@@ -2421,6 +3345,49 @@ func (t *TransferTaskInfo) String() string {
 	)
 }
 
+// GetTaskID returns the task ID for cross-cluster task
+func (t *CrossClusterTaskInfo) GetTaskID() int64 {
+	return t.TaskID
+}
+
+// GetVersion returns the task version for cross-cluster task
+func (t *CrossClusterTaskInfo) GetVersion() int64 {
+	return t.Version
+}
+
+// GetTaskType returns the task type for cross-cluster task
+func (t *CrossClusterTaskInfo) GetTaskType() int {
+	return t.TaskType
+}
+
+// GetVisibilityTimestamp returns the task type for cross-cluster task
+func (t *CrossClusterTaskInfo) GetVisibilityTimestamp() time.Time {
+	return t.VisibilityTimestamp
+}
+
+// GetWorkflowID returns the workflow ID for cross-cluster task
+func (t *CrossClusterTaskInfo) GetWorkflowID() string {
+	return t.WorkflowID
+}
+
+// GetRunID returns the run ID for cross-cluster task
+func (t *CrossClusterTaskInfo) GetRunID() string {
+	return t.RunID
+}
+
+// GetDomainID returns the domain ID for cross-cluster task
+func (t *CrossClusterTaskInfo) GetDomainID() string {
+	return t.DomainID
+}
+
+// String returns string
+func (t *CrossClusterTaskInfo) String() string {
+	return fmt.Sprintf(
+		"{DomainID: %v, WorkflowID: %v, RunID: %v, TaskID: %v, TargetCluster: %v, TargetDomainID: %v, TargetWorkflowID %v, TargetRunID: %v, TargetChildWorkflowOnly: %v, TaskList: %v, TaskType: %v, ScheduleID: %v, Version: %v.}",
+		t.DomainID, t.WorkflowID, t.RunID, t.TaskID, t.TargetCluster, t.TargetDomainID, t.TargetWorkflowID, t.TargetRunID, t.TargetChildWorkflowOnly, t.TaskList, t.TaskType, t.ScheduleID, t.Version,
+	)
+}
+
 // GetTaskID returns the task ID for replication task
 func (t *ReplicationTaskInfo) GetTaskID() int64 {
 	return t.TaskID
@@ -2456,6 +3423,12 @@ func (t *ReplicationTaskInfo) GetDomainID() string {
 	return t.DomainID
 }
 
+// GetCreationTimestamp returns the creation time of the replication task,
+// the zero time is returned if the creation time is not set
+func (t *ReplicationTaskInfo) GetCreationTimestamp() time.Time {
+	return unixNanoToTime(t.CreationTime)
+}
+
 // GetTaskID returns the task ID for timer task
 func (t *TimerTaskInfo) GetTaskID() int64 {
 	return t.TaskID
@@ -2499,6 +3472,494 @@ func (t *TimerTaskInfo) String() string {
 	)
 }
 
+// DeepCopy returns a copy of the mutable state which shares no maps, slices or pointers with the original,
+// so the copy can be mutated without affecting the original. History events are copied down to their
+// attributes, the payloads referenced by the attributes are shared and must not be mutated.
+func (s *WorkflowMutableState) DeepCopy() *WorkflowMutableState {
+	if s == nil {
+		return nil
+	}
+
+	copied := &WorkflowMutableState{
+		ExecutionInfo:    s.ExecutionInfo.deepCopy(),
+		VersionHistories: s.VersionHistories.deepCopy(),
+		ReplicationState: s.ReplicationState.deepCopy(),
+		Checksum: checksum.Checksum{
+			Version: s.Checksum.Version,
+			Flavor:  s.Checksum.Flavor,
+			Value:   copyBytes(s.Checksum.Value),
+		},
+	}
+	if s.ExecutionStats != nil {
+		stats := *s.ExecutionStats
+		copied.ExecutionStats = &stats
+	}
+	if s.ActivityInfos != nil {
+		copied.ActivityInfos = make(map[int64]*ActivityInfo, len(s.ActivityInfos))
+		for id, info := range s.ActivityInfos {
+			copied.ActivityInfos[id] = info.deepCopy()
+		}
+	}
+	if s.TimerInfos != nil {
+		copied.TimerInfos = make(map[string]*TimerInfo, len(s.TimerInfos))
+		for id, info := range s.TimerInfos {
+			copied.TimerInfos[id] = info.deepCopy()
+		}
+	}
+	if s.ChildExecutionInfos != nil {
+		copied.ChildExecutionInfos = make(map[int64]*ChildExecutionInfo, len(s.ChildExecutionInfos))
+		for id, info := range s.ChildExecutionInfos {
+			copied.ChildExecutionInfos[id] = info.deepCopy()
+		}
+	}
+	if s.RequestCancelInfos != nil {
+		copied.RequestCancelInfos = make(map[int64]*RequestCancelInfo, len(s.RequestCancelInfos))
+		for id, info := range s.RequestCancelInfos {
+			copied.RequestCancelInfos[id] = info.deepCopy()
+		}
+	}
+	if s.SignalInfos != nil {
+		copied.SignalInfos = make(map[int64]*SignalInfo, len(s.SignalInfos))
+		for id, info := range s.SignalInfos {
+			copied.SignalInfos[id] = info.deepCopy()
+		}
+	}
+	if s.SignalRequestedIDs != nil {
+		copied.SignalRequestedIDs = make(map[string]struct{}, len(s.SignalRequestedIDs))
+		for id := range s.SignalRequestedIDs {
+			copied.SignalRequestedIDs[id] = struct{}{}
+		}
+	}
+	if s.BufferedEvents != nil {
+		copied.BufferedEvents = make([]*types.HistoryEvent, 0, len(s.BufferedEvents))
+		for _, event := range s.BufferedEvents {
+			copied.BufferedEvents = append(copied.BufferedEvents, copyHistoryEvent(event))
+		}
+	}
+	return copied
+}
+
+// Validate checks the referential integrity of the mutable state, so state machine bugs are caught
+// before the state is written instead of corrupting the run
+func (s *WorkflowMutableState) Validate() error {
+	if info := s.ExecutionInfo; info != nil && info.DecisionScheduleID != common.EmptyEventID {
+		// the schedule ID of a transient decision is the next event ID, as its event is not written yet
+		if info.DecisionScheduleID < common.FirstEventID || info.DecisionScheduleID > info.NextEventID {
+			return &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("decision schedule ID: %v does not refer to a scheduled event, next event ID: %v",
+					info.DecisionScheduleID, info.NextEventID),
+			}
+		}
+	}
+
+	for initiatedID, childInfo := range s.ChildExecutionInfos {
+		if childInfo == nil || childInfo.InitiatedID != initiatedID {
+			return &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("child execution info keyed by initiated ID: %v does not have the same initiated ID", initiatedID),
+			}
+		}
+	}
+	return nil
+}
+
+func (e *WorkflowExecutionInfo) deepCopy() *WorkflowExecutionInfo {
+	if e == nil {
+		return nil
+	}
+	copied := *e
+	copied.CompletionEvent = copyHistoryEvent(e.CompletionEvent)
+	copied.ExecutionContext = copyBytes(e.ExecutionContext)
+	copied.Memo = copyBytesMap(e.Memo)
+	copied.SearchAttributes = copyBytesMap(e.SearchAttributes)
+	copied.NonRetriableErrors = copyStrings(e.NonRetriableErrors)
+	copied.BranchToken = copyBytes(e.BranchToken)
+	copied.AutoResetPoints = copyResetPoints(e.AutoResetPoints)
+	return &copied
+}
+
+// GetDataInt returns the value of the given domain data key parsed as an int,
+// def is returned if the key is not set or its value is not an int
+func (d *DomainInfo) GetDataInt(key string, def int) int {
+	value, ok := d.Data[key]
+	if !ok {
+		return def
+	}
+	parsed, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return def
+	}
+	return parsed
+}
+
+// GetDataBool returns the value of the given domain data key parsed as a bool,
+// def is returned if the key is not set or its value is not a bool
+func (d *DomainInfo) GetDataBool(key string, def bool) bool {
+	value, ok := d.Data[key]
+	if !ok {
+		return def
+	}
+	parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return def
+	}
+	return parsed
+}
+
+// GetDataString returns the value of the given domain data key, def is returned if the key is not set
+func (d *DomainInfo) GetDataString(key string, def string) string {
+	value, ok := d.Data[key]
+	if !ok {
+		return def
+	}
+	return value
+}
+
+// GetLastHeartbeatTimeoutVisibility returns the visibility time of the last heartbeat timer created for the activity,
+// the zero time is returned if no heartbeat timer has been created
+func (a *ActivityInfo) GetLastHeartbeatTimeoutVisibility() time.Time {
+	if a.LastHeartbeatTimeoutVisibilityInSeconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(a.LastHeartbeatTimeoutVisibilityInSeconds, 0)
+}
+
+// GetLastFailure returns the last failure of the activity, nil is returned if the activity has not failed
+func (a *ActivityInfo) GetLastFailure() *types.Failure {
+	if a.LastFailureReason == "" && len(a.LastFailureDetails) == 0 {
+		return nil
+	}
+	return &types.Failure{
+		Reason:  a.LastFailureReason,
+		Details: a.LastFailureDetails,
+	}
+}
+
+// SetLastFailure records the given failure as the last failure of the activity, a nil failure clears it
+func (a *ActivityInfo) SetLastFailure(failure *types.Failure) {
+	a.LastFailureReason = failure.GetReason()
+	a.LastFailureDetails = failure.GetDetails()
+}
+
+func (a *ActivityInfo) deepCopy() *ActivityInfo {
+	if a == nil {
+		return nil
+	}
+	copied := *a
+	copied.ScheduledEvent = copyHistoryEvent(a.ScheduledEvent)
+	copied.StartedEvent = copyHistoryEvent(a.StartedEvent)
+	copied.Details = copyBytes(a.Details)
+	copied.NonRetriableErrors = copyStrings(a.NonRetriableErrors)
+	copied.LastFailureDetails = copyBytes(a.LastFailureDetails)
+	return &copied
+}
+
+func (t *TimerInfo) deepCopy() *TimerInfo {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
+
+func (c *ChildExecutionInfo) deepCopy() *ChildExecutionInfo {
+	if c == nil {
+		return nil
+	}
+	copied := *c
+	copied.InitiatedEvent = copyHistoryEvent(c.InitiatedEvent)
+	copied.StartedEvent = copyHistoryEvent(c.StartedEvent)
+	return &copied
+}
+
+func (r *RequestCancelInfo) deepCopy() *RequestCancelInfo {
+	if r == nil {
+		return nil
+	}
+	copied := *r
+	return &copied
+}
+
+func (s *SignalInfo) deepCopy() *SignalInfo {
+	if s == nil {
+		return nil
+	}
+	copied := *s
+	copied.Input = copyBytes(s.Input)
+	copied.Control = copyBytes(s.Control)
+	return &copied
+}
+
+func (r *ReplicationState) deepCopy() *ReplicationState {
+	if r == nil {
+		return nil
+	}
+	copied := *r
+	if r.LastReplicationInfo != nil {
+		copied.LastReplicationInfo = make(map[string]*ReplicationInfo, len(r.LastReplicationInfo))
+		for cluster, info := range r.LastReplicationInfo {
+			if info == nil {
+				copied.LastReplicationInfo[cluster] = nil
+				continue
+			}
+			infoCopy := *info
+			copied.LastReplicationInfo[cluster] = &infoCopy
+		}
+	}
+	return &copied
+}
+
+func (h *VersionHistories) deepCopy() *VersionHistories {
+	if h == nil {
+		return nil
+	}
+	return h.Duplicate()
+}
+
+// copyHistoryEvent copies the event and its attributes, the values the attributes point to
+// such as payloads are shared with the original
+func copyHistoryEvent(e *types.HistoryEvent) *types.HistoryEvent {
+	if e == nil {
+		return nil
+	}
+	copied := *e
+	copied.Timestamp = copyInt64Ptr(e.Timestamp)
+	if e.EventType != nil {
+		eventType := *e.EventType
+		copied.EventType = &eventType
+	}
+	if e.WorkflowExecutionStartedEventAttributes != nil {
+		attributes := *e.WorkflowExecutionStartedEventAttributes
+		copied.WorkflowExecutionStartedEventAttributes = &attributes
+	}
+	if e.WorkflowExecutionCompletedEventAttributes != nil {
+		attributes := *e.WorkflowExecutionCompletedEventAttributes
+		copied.WorkflowExecutionCompletedEventAttributes = &attributes
+	}
+	if e.WorkflowExecutionFailedEventAttributes != nil {
+		attributes := *e.WorkflowExecutionFailedEventAttributes
+		copied.WorkflowExecutionFailedEventAttributes = &attributes
+	}
+	if e.WorkflowExecutionTimedOutEventAttributes != nil {
+		attributes := *e.WorkflowExecutionTimedOutEventAttributes
+		copied.WorkflowExecutionTimedOutEventAttributes = &attributes
+	}
+	if e.DecisionTaskScheduledEventAttributes != nil {
+		attributes := *e.DecisionTaskScheduledEventAttributes
+		copied.DecisionTaskScheduledEventAttributes = &attributes
+	}
+	if e.DecisionTaskStartedEventAttributes != nil {
+		attributes := *e.DecisionTaskStartedEventAttributes
+		copied.DecisionTaskStartedEventAttributes = &attributes
+	}
+	if e.DecisionTaskCompletedEventAttributes != nil {
+		attributes := *e.DecisionTaskCompletedEventAttributes
+		copied.DecisionTaskCompletedEventAttributes = &attributes
+	}
+	if e.DecisionTaskTimedOutEventAttributes != nil {
+		attributes := *e.DecisionTaskTimedOutEventAttributes
+		copied.DecisionTaskTimedOutEventAttributes = &attributes
+	}
+	if e.DecisionTaskFailedEventAttributes != nil {
+		attributes := *e.DecisionTaskFailedEventAttributes
+		copied.DecisionTaskFailedEventAttributes = &attributes
+	}
+	if e.ActivityTaskScheduledEventAttributes != nil {
+		attributes := *e.ActivityTaskScheduledEventAttributes
+		copied.ActivityTaskScheduledEventAttributes = &attributes
+	}
+	if e.ActivityTaskStartedEventAttributes != nil {
+		attributes := *e.ActivityTaskStartedEventAttributes
+		copied.ActivityTaskStartedEventAttributes = &attributes
+	}
+	if e.ActivityTaskCompletedEventAttributes != nil {
+		attributes := *e.ActivityTaskCompletedEventAttributes
+		copied.ActivityTaskCompletedEventAttributes = &attributes
+	}
+	if e.ActivityTaskFailedEventAttributes != nil {
+		attributes := *e.ActivityTaskFailedEventAttributes
+		copied.ActivityTaskFailedEventAttributes = &attributes
+	}
+	if e.ActivityTaskTimedOutEventAttributes != nil {
+		attributes := *e.ActivityTaskTimedOutEventAttributes
+		copied.ActivityTaskTimedOutEventAttributes = &attributes
+	}
+	if e.TimerStartedEventAttributes != nil {
+		attributes := *e.TimerStartedEventAttributes
+		copied.TimerStartedEventAttributes = &attributes
+	}
+	if e.TimerFiredEventAttributes != nil {
+		attributes := *e.TimerFiredEventAttributes
+		copied.TimerFiredEventAttributes = &attributes
+	}
+	if e.ActivityTaskCancelRequestedEventAttributes != nil {
+		attributes := *e.ActivityTaskCancelRequestedEventAttributes
+		copied.ActivityTaskCancelRequestedEventAttributes = &attributes
+	}
+	if e.RequestCancelActivityTaskFailedEventAttributes != nil {
+		attributes := *e.RequestCancelActivityTaskFailedEventAttributes
+		copied.RequestCancelActivityTaskFailedEventAttributes = &attributes
+	}
+	if e.ActivityTaskCanceledEventAttributes != nil {
+		attributes := *e.ActivityTaskCanceledEventAttributes
+		copied.ActivityTaskCanceledEventAttributes = &attributes
+	}
+	if e.TimerCanceledEventAttributes != nil {
+		attributes := *e.TimerCanceledEventAttributes
+		copied.TimerCanceledEventAttributes = &attributes
+	}
+	if e.CancelTimerFailedEventAttributes != nil {
+		attributes := *e.CancelTimerFailedEventAttributes
+		copied.CancelTimerFailedEventAttributes = &attributes
+	}
+	if e.MarkerRecordedEventAttributes != nil {
+		attributes := *e.MarkerRecordedEventAttributes
+		copied.MarkerRecordedEventAttributes = &attributes
+	}
+	if e.WorkflowExecutionSignaledEventAttributes != nil {
+		attributes := *e.WorkflowExecutionSignaledEventAttributes
+		copied.WorkflowExecutionSignaledEventAttributes = &attributes
+	}
+	if e.WorkflowExecutionTerminatedEventAttributes != nil {
+		attributes := *e.WorkflowExecutionTerminatedEventAttributes
+		copied.WorkflowExecutionTerminatedEventAttributes = &attributes
+	}
+	if e.WorkflowExecutionCancelRequestedEventAttributes != nil {
+		attributes := *e.WorkflowExecutionCancelRequestedEventAttributes
+		copied.WorkflowExecutionCancelRequestedEventAttributes = &attributes
+	}
+	if e.WorkflowExecutionCanceledEventAttributes != nil {
+		attributes := *e.WorkflowExecutionCanceledEventAttributes
+		copied.WorkflowExecutionCanceledEventAttributes = &attributes
+	}
+	if e.RequestCancelExternalWorkflowExecutionInitiatedEventAttributes != nil {
+		attributes := *e.RequestCancelExternalWorkflowExecutionInitiatedEventAttributes
+		copied.RequestCancelExternalWorkflowExecutionInitiatedEventAttributes = &attributes
+	}
+	if e.RequestCancelExternalWorkflowExecutionFailedEventAttributes != nil {
+		attributes := *e.RequestCancelExternalWorkflowExecutionFailedEventAttributes
+		copied.RequestCancelExternalWorkflowExecutionFailedEventAttributes = &attributes
+	}
+	if e.ExternalWorkflowExecutionCancelRequestedEventAttributes != nil {
+		attributes := *e.ExternalWorkflowExecutionCancelRequestedEventAttributes
+		copied.ExternalWorkflowExecutionCancelRequestedEventAttributes = &attributes
+	}
+	if e.WorkflowExecutionContinuedAsNewEventAttributes != nil {
+		attributes := *e.WorkflowExecutionContinuedAsNewEventAttributes
+		copied.WorkflowExecutionContinuedAsNewEventAttributes = &attributes
+	}
+	if e.StartChildWorkflowExecutionInitiatedEventAttributes != nil {
+		attributes := *e.StartChildWorkflowExecutionInitiatedEventAttributes
+		copied.StartChildWorkflowExecutionInitiatedEventAttributes = &attributes
+	}
+	if e.StartChildWorkflowExecutionFailedEventAttributes != nil {
+		attributes := *e.StartChildWorkflowExecutionFailedEventAttributes
+		copied.StartChildWorkflowExecutionFailedEventAttributes = &attributes
+	}
+	if e.ChildWorkflowExecutionStartedEventAttributes != nil {
+		attributes := *e.ChildWorkflowExecutionStartedEventAttributes
+		copied.ChildWorkflowExecutionStartedEventAttributes = &attributes
+	}
+	if e.ChildWorkflowExecutionCompletedEventAttributes != nil {
+		attributes := *e.ChildWorkflowExecutionCompletedEventAttributes
+		copied.ChildWorkflowExecutionCompletedEventAttributes = &attributes
+	}
+	if e.ChildWorkflowExecutionFailedEventAttributes != nil {
+		attributes := *e.ChildWorkflowExecutionFailedEventAttributes
+		copied.ChildWorkflowExecutionFailedEventAttributes = &attributes
+	}
+	if e.ChildWorkflowExecutionCanceledEventAttributes != nil {
+		attributes := *e.ChildWorkflowExecutionCanceledEventAttributes
+		copied.ChildWorkflowExecutionCanceledEventAttributes = &attributes
+	}
+	if e.ChildWorkflowExecutionTimedOutEventAttributes != nil {
+		attributes := *e.ChildWorkflowExecutionTimedOutEventAttributes
+		copied.ChildWorkflowExecutionTimedOutEventAttributes = &attributes
+	}
+	if e.ChildWorkflowExecutionTerminatedEventAttributes != nil {
+		attributes := *e.ChildWorkflowExecutionTerminatedEventAttributes
+		copied.ChildWorkflowExecutionTerminatedEventAttributes = &attributes
+	}
+	if e.SignalExternalWorkflowExecutionInitiatedEventAttributes != nil {
+		attributes := *e.SignalExternalWorkflowExecutionInitiatedEventAttributes
+		copied.SignalExternalWorkflowExecutionInitiatedEventAttributes = &attributes
+	}
+	if e.SignalExternalWorkflowExecutionFailedEventAttributes != nil {
+		attributes := *e.SignalExternalWorkflowExecutionFailedEventAttributes
+		copied.SignalExternalWorkflowExecutionFailedEventAttributes = &attributes
+	}
+	if e.ExternalWorkflowExecutionSignaledEventAttributes != nil {
+		attributes := *e.ExternalWorkflowExecutionSignaledEventAttributes
+		copied.ExternalWorkflowExecutionSignaledEventAttributes = &attributes
+	}
+	if e.UpsertWorkflowSearchAttributesEventAttributes != nil {
+		attributes := *e.UpsertWorkflowSearchAttributesEventAttributes
+		copied.UpsertWorkflowSearchAttributesEventAttributes = &attributes
+	}
+	return &copied
+}
+
+func copyResetPoints(r *types.ResetPoints) *types.ResetPoints {
+	if r == nil {
+		return nil
+	}
+	copied := &types.ResetPoints{}
+	if r.Points != nil {
+		copied.Points = make([]*types.ResetPointInfo, 0, len(r.Points))
+		for _, point := range r.Points {
+			var pointCopy *types.ResetPointInfo
+			if point != nil {
+				pointCopy = &types.ResetPointInfo{
+					BinaryChecksum:           point.BinaryChecksum,
+					RunID:                    point.RunID,
+					FirstDecisionCompletedID: point.FirstDecisionCompletedID,
+					CreatedTimeNano:          copyInt64Ptr(point.CreatedTimeNano),
+					ExpiringTimeNano:         copyInt64Ptr(point.ExpiringTimeNano),
+					Resettable:               point.Resettable,
+				}
+			}
+			copied.Points = append(copied.Points, pointCopy)
+		}
+	}
+	return copied
+}
+
+func copyInt64Ptr(input *int64) *int64 {
+	if input == nil {
+		return nil
+	}
+	result := *input
+	return &result
+}
+
+func copyBytes(input []byte) []byte {
+	if input == nil {
+		return nil
+	}
+	result := make([]byte, len(input))
+	copy(result, input)
+	return result
+}
+
+func copyStrings(input []string) []string {
+	if input == nil {
+		return nil
+	}
+	result := make([]string, len(input))
+	copy(result, input)
+	return result
+}
+
+func copyBytesMap(input map[string][]byte) map[string][]byte {
+	if input == nil {
+		return nil
+	}
+	result := make(map[string][]byte, len(input))
+	for key, value := range input {
+		result[key] = copyBytes(value)
+	}
+	return result
+}
+
 // SerializeClusterConfigs makes an array of *ClusterReplicationConfig serializable
 // by flattening them into map[string]interface{}
 func SerializeClusterConfigs(replicationConfigs []*ClusterReplicationConfig) []map[string]interface{} {
@@ -2537,6 +3998,90 @@ func (config *ClusterReplicationConfig) GetCopy() *ClusterReplicationConfig {
 	return &res
 }
 
+// Validate checks that the cluster names are unique and the active cluster is one of them,
+// an empty active cluster or cluster list is left to the stores to fill with the current cluster
+func (config *DomainReplicationConfig) Validate() error {
+	clusterNames := make(map[string]struct{}, len(config.Clusters))
+	for _, cluster := range config.Clusters {
+		if cluster == nil {
+			continue
+		}
+		if _, ok := clusterNames[cluster.ClusterName]; ok {
+			return &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("cluster: %v is configured more than once", cluster.ClusterName),
+			}
+		}
+		clusterNames[cluster.ClusterName] = struct{}{}
+	}
+	if config.ActiveClusterName == "" || len(clusterNames) == 0 {
+		return nil
+	}
+	if _, ok := clusterNames[config.ActiveClusterName]; !ok {
+		return &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("active cluster: %v is not one of the configured clusters", config.ActiveClusterName),
+		}
+	}
+	return nil
+}
+
+// IsExpired returns whether the task list expired at the given time, a task list without an expiry,
+// which is every task list but the sticky ones, never expires
+func (t *TaskListInfo) IsExpired(now time.Time) bool {
+	return !t.Expiry.IsZero() && !now.Before(t.Expiry)
+}
+
+// IsStaleCompared returns whether the last write of the replication state is stale compared to
+// the given version and event ID, a higher version wins and the event ID breaks a tie between equal versions
+func (r *ReplicationState) IsStaleCompared(version int64, eventID int64) bool {
+	return compareReplicationPosition(r.LastWriteVersion, r.LastWriteEventID, version, eventID) < 0
+}
+
+// MergeLastReplicationInfo merges the given replication info into LastReplicationInfo, per cluster
+// the info with the higher version, or the higher last event ID for equal versions, is kept
+func (r *ReplicationState) MergeLastReplicationInfo(infos map[string]*ReplicationInfo) {
+	for cluster, info := range infos {
+		if info == nil {
+			continue
+		}
+		if r.LastReplicationInfo == nil {
+			r.LastReplicationInfo = make(map[string]*ReplicationInfo, len(infos))
+		}
+		existing, ok := r.LastReplicationInfo[cluster]
+		if ok && existing != nil &&
+			compareReplicationPosition(existing.Version, existing.LastEventID, info.Version, info.LastEventID) >= 0 {
+			continue
+		}
+		infoCopy := *info
+		r.LastReplicationInfo[cluster] = &infoCopy
+	}
+}
+
+// compareReplicationPosition compares two (version, event ID) positions, returning a negative number when
+// the first one is behind the second one, zero when they are equal and a positive number otherwise
+func compareReplicationPosition(version1 int64, eventID1 int64, version2 int64, eventID2 int64) int {
+	switch {
+	case version1 != version2:
+		if version1 < version2 {
+			return -1
+		}
+		return 1
+	case eventID1 < eventID2:
+		return -1
+	case eventID1 > eventID2:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// unixNanoToTime converts a unix nanoseconds timestamp to a time.Time, treating zero as unset
+func unixNanoToTime(timestamp int64) time.Time {
+	if timestamp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, timestamp)
+}
+
 // DBTimestampToUnixNano converts Milliseconds timestamp to UnixNano
 func DBTimestampToUnixNano(milliseconds int64) int64 {
 	return milliseconds * 1000 * 1000 // Milliseconds are 10⁻³, nanoseconds are 10⁻⁹, (-3) - (-9) = 6, so multiply by 10⁶
@@ -2566,10 +4111,23 @@ func NewHistoryBranchToken(treeID string) ([]byte, error) {
 
 // NewHistoryBranchTokenByBranchID return a new branch token with treeID/branchID
 func NewHistoryBranchTokenByBranchID(treeID, branchID string) ([]byte, error) {
+	return NewHistoryBranchTokenWithAncestors(treeID, branchID, nil)
+}
+
+// NewHistoryBranchTokenWithAncestors return a new branch token with treeID/branchID, which is forked from the
+// given ancestor ranges
+func NewHistoryBranchTokenWithAncestors(
+	treeID string,
+	branchID string,
+	ancestors []*workflow.HistoryBranchRange,
+) ([]byte, error) {
+	if ancestors == nil {
+		ancestors = []*workflow.HistoryBranchRange{}
+	}
 	bi := &workflow.HistoryBranch{
 		TreeID:    &treeID,
 		BranchID:  &branchID,
-		Ancestors: []*workflow.HistoryBranchRange{},
+		Ancestors: ancestors,
 	}
 	token, err := internalThriftEncoder.Encode(bi)
 	if err != nil {
@@ -2578,24 +4136,22 @@ func NewHistoryBranchTokenByBranchID(treeID, branchID string) ([]byte, error) {
 	return token, nil
 }
 
-// NewHistoryBranchTokenFromAnother make up a branchToken
-func NewHistoryBranchTokenFromAnother(branchID string, anotherToken []byte) ([]byte, error) {
+// ParseHistoryBranchToken decodes a branch token into the history branch it refers to
+func ParseHistoryBranchToken(token []byte) (*workflow.HistoryBranch, error) {
 	var branch workflow.HistoryBranch
-	err := internalThriftEncoder.Decode(anotherToken, &branch)
-	if err != nil {
+	if err := internalThriftEncoder.Decode(token, &branch); err != nil {
 		return nil, err
 	}
+	return &branch, nil
+}
 
-	bi := &workflow.HistoryBranch{
-		TreeID:    branch.TreeID,
-		BranchID:  &branchID,
-		Ancestors: []*workflow.HistoryBranchRange{},
-	}
-	token, err := internalThriftEncoder.Encode(bi)
+// NewHistoryBranchTokenFromAnother make up a branchToken
+func NewHistoryBranchTokenFromAnother(branchID string, anotherToken []byte) ([]byte, error) {
+	branch, err := ParseHistoryBranchToken(anotherToken)
 	if err != nil {
 		return nil, err
 	}
-	return token, nil
+	return NewHistoryBranchTokenByBranchID(branch.GetTreeID(), branchID)
 }
 
 // BuildHistoryGarbageCleanupInfo combine the workflow identity information into a string
@@ -2636,12 +4192,244 @@ func NewGetReplicationTasksFromDLQRequest(
 	}
 }
 
+// Validate checks the basic sanity of the create workflow execution request,
+// so malformed requests are rejected before reaching the persistence store
+func (r *CreateWorkflowExecutionRequest) Validate() error {
+	info := r.NewWorkflowSnapshot.ExecutionInfo
+	if info == nil {
+		return &InvalidPersistenceRequestError{Msg: "CreateWorkflowExecution: execution info is not set"}
+	}
+	if info.DomainID == "" || info.WorkflowID == "" || info.RunID == "" {
+		return &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("CreateWorkflowExecution: domain ID: %q, workflow ID: %q and run ID: %q must all be set",
+				info.DomainID, info.WorkflowID, info.RunID),
+		}
+	}
+	if info.DecisionScheduleID != common.EmptyEventID && info.DecisionScheduleID > info.NextEventID {
+		return &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("CreateWorkflowExecution: decision schedule ID: %v is larger than next event ID: %v",
+				info.DecisionScheduleID, info.NextEventID),
+		}
+	}
+	if err := validateWorkflowSnapshotSize(
+		"CreateWorkflowExecution",
+		&r.NewWorkflowSnapshot,
+		r.WorkflowSizeLimits,
+	); err != nil {
+		return err
+	}
+
+	switch r.Mode {
+	case CreateWorkflowModeBrandNew:
+		if r.PreviousRunID != "" {
+			return &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("CreateWorkflowExecution: previous run ID: %v is not allowed for brand new workflow", r.PreviousRunID),
+			}
+		}
+	case CreateWorkflowModeWorkflowIDReuse, CreateWorkflowModeContinueAsNew:
+		if r.PreviousRunID == "" {
+			return &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("CreateWorkflowExecution: previous run ID is required for create mode: %v", r.Mode),
+			}
+		}
+	case CreateWorkflowModeZombie:
+		// noop
+	default:
+		return &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("CreateWorkflowExecution: unknown create mode: %v", r.Mode),
+		}
+	}
+
+	for _, tasks := range [][]Task{
+		r.NewWorkflowSnapshot.TransferTasks,
+		r.NewWorkflowSnapshot.ReplicationTasks,
+	} {
+		for _, task := range tasks {
+			if task == nil {
+				return &InvalidPersistenceRequestError{Msg: "CreateWorkflowExecution: task is nil"}
+			}
+		}
+	}
+	for _, task := range r.NewWorkflowSnapshot.TimerTasks {
+		if task == nil {
+			return &InvalidPersistenceRequestError{Msg: "CreateWorkflowExecution: timer task is nil"}
+		}
+		if task.GetVisibilityTimestamp().IsZero() {
+			return &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("CreateWorkflowExecution: visibility timestamp is not set for timer task with type: %v", task.GetType()),
+			}
+		}
+	}
+	return nil
+}
+
+// Validate rejects an inverted range, an empty range is allowed as it is a valid no-op
+func (r *RangeCompleteTransferTaskRequest) Validate() error {
+	if r.ExclusiveBeginTaskID > r.InclusiveEndTaskID {
+		return &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("RangeCompleteTransferTask: exclusive begin task ID: %v is larger than inclusive end task ID: %v",
+				r.ExclusiveBeginTaskID, r.InclusiveEndTaskID),
+		}
+	}
+	return nil
+}
+
+// Validate rejects an inverted range, an empty range is allowed as it is a valid no-op
+func (r *RangeCompleteCrossClusterTaskRequest) Validate() error {
+	if r.ExclusiveBeginTaskID > r.InclusiveEndTaskID {
+		return &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("RangeCompleteCrossClusterTask: exclusive begin task ID: %v is larger than inclusive end task ID: %v",
+				r.ExclusiveBeginTaskID, r.InclusiveEndTaskID),
+		}
+	}
+	return nil
+}
+
+// Validate rejects an inverted or an empty range of visibility timestamps
+func (r *RangeCompleteTimerTaskRequest) Validate() error {
+	if !r.InclusiveBeginTimestamp.Before(r.ExclusiveEndTimestamp) {
+		return &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("RangeCompleteTimerTask: inclusive begin timestamp: %v is not before exclusive end timestamp: %v",
+				r.InclusiveBeginTimestamp, r.ExclusiveEndTimestamp),
+		}
+	}
+	return nil
+}
+
+// Validate rejects the signal if the total size of its input and control exceeds maxInputBytes,
+// a non-positive maxInputBytes means no limit
+func (s *SignalInfo) Validate(maxInputBytes int) error {
+	if maxInputBytes <= 0 {
+		return nil
+	}
+	size := len(s.Input) + len(s.Control)
+	if size <= maxInputBytes {
+		return nil
+	}
+	return &InvalidPersistenceRequestError{
+		Msg: fmt.Sprintf("signal %q with initiated ID %v: input and control size of %v bytes exceeds the limit of %v bytes",
+			s.SignalName, s.InitiatedID, size, maxInputBytes),
+	}
+}
+
+// validateWorkflowSnapshotSize checks the execution info and the signals written by the snapshot against the limits
+func validateWorkflowSnapshotSize(
+	operation string,
+	snapshot *WorkflowSnapshot,
+	limits WorkflowSizeLimits,
+) error {
+	if err := validateMemoAndSearchAttributesSize(operation, snapshot.ExecutionInfo, limits); err != nil {
+		return err
+	}
+	return validateSignalInfosSize(snapshot.SignalInfos, limits)
+}
+
+// validateWorkflowMutationSize checks the execution info and the signals upserted by the mutation against the limits
+func validateWorkflowMutationSize(
+	operation string,
+	mutation *WorkflowMutation,
+	limits WorkflowSizeLimits,
+) error {
+	if err := validateMemoAndSearchAttributesSize(operation, mutation.ExecutionInfo, limits); err != nil {
+		return err
+	}
+	return validateSignalInfosSize(mutation.UpsertSignalInfos, limits)
+}
+
+func validateMemoAndSearchAttributesSize(
+	operation string,
+	info *WorkflowExecutionInfo,
+	limits WorkflowSizeLimits,
+) error {
+	if info == nil {
+		return nil
+	}
+	if err := validateAttributesSize(operation, "memo", info.Memo, limits.MaxMemoSize); err != nil {
+		return err
+	}
+	return validateAttributesSize(operation, "search attributes", info.SearchAttributes, limits.MaxSearchAttributesSize)
+}
+
+func validateSignalInfosSize(
+	signalInfos []*SignalInfo,
+	limits WorkflowSizeLimits,
+) error {
+	for _, signalInfo := range signalInfos {
+		if err := signalInfo.Validate(limits.MaxSignalInputSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateAttributesSize rejects the attributes if the total size of their keys and values exceeds maxSize,
+// the largest attribute is named in the error as it is the most likely one to be trimmed
+func validateAttributesSize(
+	operation string,
+	name string,
+	attributes map[string][]byte,
+	maxSize int,
+) error {
+	if maxSize <= 0 {
+		return nil
+	}
+
+	totalSize := 0
+	largestKey := ""
+	largestSize := 0
+	for key, value := range attributes {
+		size := len(key) + len(value)
+		totalSize += size
+		if size > largestSize || (size == largestSize && key < largestKey) {
+			largestKey = key
+			largestSize = size
+		}
+	}
+	if totalSize <= maxSize {
+		return nil
+	}
+	return &InvalidPersistenceRequestError{
+		Msg: fmt.Sprintf("%v: %v size of %v bytes exceeds the limit of %v bytes, largest key: %q with %v bytes",
+			operation, name, totalSize, maxSize, largestKey, largestSize),
+	}
+}
+
 // IsTransientError checks if the error is a transient persistence error
 func IsTransientError(err error) bool {
-	switch err.(type) {
-	case *types.InternalServiceError, *types.ServiceBusyError, *TimeoutError:
+	switch err := err.(type) {
+	case *types.InternalServiceError, *types.ServiceBusyError:
 		return true
+	case *TimeoutError:
+		// a write which timed out after the coordinator accepted the mutation may have been applied,
+		// so it is only retried if applying it twice is harmless
+		return err.WriteType == "" || err.Idempotent
 	}
 
 	return false
 }
+
+// CloseWithContext closes c within the deadline of ctx. If c doesn't support closing with a context,
+// it is closed in the background and ctx.Err() is returned if that doesn't finish before ctx is done.
+func CloseWithContext(ctx context.Context, c Closeable) error {
+	if cc, ok := c.(ContextCloseable); ok {
+		return cc.CloseWithContext(ctx)
+	}
+	return WaitForClose(ctx, c.Close)
+}
+
+// WaitForClose runs closeFn in the background and waits for it until ctx is done, it is meant for stores
+// whose underlying client can only be closed synchronously
+func WaitForClose(ctx context.Context, closeFn func()) error {
+	done := make(chan struct{})
+	go func() {
+		closeFn()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -21,12 +21,16 @@
 package persistence
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/types"
 )
 
@@ -43,6 +47,7 @@ func TestIsTransientError(t *testing.T) {
 		&types.ServiceBusyError{},
 		&types.InternalServiceError{},
 		&TimeoutError{},
+		&TimeoutError{WriteType: "SIMPLE", Idempotent: true},
 	}
 	for _, err := range transientErrors {
 		require.True(t, IsTransientError(err))
@@ -52,9 +57,614 @@ func TestIsTransientError(t *testing.T) {
 		&types.EntityNotExistsError{},
 		&types.DomainAlreadyExistsError{},
 		&WorkflowExecutionAlreadyStartedError{},
+		&TimeoutError{WriteType: "CAS"},
 		errors.New("some unknown error"),
 	}
 	for _, err := range nonRetryableErrors {
 		require.False(t, IsTransientError(err))
 	}
 }
+
+func TestIsShardOwnershipLostError(t *testing.T) {
+	require.True(t, IsShardOwnershipLostError(&ShardOwnershipLostError{ShardID: 1}))
+	require.False(t, IsShardOwnershipLostError(&ShardRangeIDMismatchError{ShardID: 1, ExpectedRangeID: 1, ActualRangeID: 2}))
+	require.False(t, IsShardOwnershipLostError(&ConditionFailedError{}))
+	require.False(t, IsShardOwnershipLostError(nil))
+}
+
+func TestIsCurrentWorkflowStillRunningError(t *testing.T) {
+	require.True(t, IsCurrentWorkflowStillRunningError(&CurrentWorkflowStillRunningError{RunID: "run", State: WorkflowStateRunning}))
+	require.False(t, IsCurrentWorkflowStillRunningError(&CurrentWorkflowConditionFailedError{}))
+	require.False(t, IsCurrentWorkflowStillRunningError(&WorkflowExecutionAlreadyStartedError{}))
+	require.False(t, IsCurrentWorkflowStillRunningError(nil))
+}
+
+func TestDomainInfoDataGetters(t *testing.T) {
+	info := &DomainInfo{
+		Data: map[string]string{
+			"int":     " 42",
+			"bool":    "true",
+			"string":  "value",
+			"empty":   "",
+			"invalid": "not a number",
+		},
+	}
+	assert.Equal(t, 42, info.GetDataInt("int", 7))
+	assert.Equal(t, 7, info.GetDataInt("invalid", 7))
+	assert.Equal(t, 7, info.GetDataInt("missing", 7))
+	assert.True(t, info.GetDataBool("bool", false))
+	assert.True(t, info.GetDataBool("invalid", true))
+	assert.False(t, info.GetDataBool("missing", false))
+	assert.Equal(t, "value", info.GetDataString("string", "default"))
+	assert.Equal(t, "", info.GetDataString("empty", "default"))
+	assert.Equal(t, "default", info.GetDataString("missing", "default"))
+
+	empty := &DomainInfo{}
+	assert.Equal(t, 7, empty.GetDataInt("int", 7))
+	assert.True(t, empty.GetDataBool("bool", true))
+	assert.Equal(t, "default", empty.GetDataString("string", "default"))
+}
+
+func TestTimestampGetters(t *testing.T) {
+	now := time.Unix(0, time.Now().UnixNano())
+
+	replicationTask := &ReplicationTaskInfo{}
+	assert.True(t, replicationTask.GetCreationTimestamp().IsZero())
+	replicationTask.CreationTime = now.UnixNano()
+	assert.True(t, now.Equal(replicationTask.GetCreationTimestamp()))
+
+	activityInfo := &ActivityInfo{}
+	assert.True(t, activityInfo.GetLastHeartbeatTimeoutVisibility().IsZero())
+	activityInfo.LastHeartbeatTimeoutVisibilityInSeconds = now.Unix()
+	assert.True(t, now.Truncate(time.Second).Equal(activityInfo.GetLastHeartbeatTimeoutVisibility()))
+
+	executionInfo := &WorkflowExecutionInfo{DecisionScheduledTimestamp: now.UnixNano()}
+	assert.True(t, now.Equal(executionInfo.GetDecisionScheduledTimestamp()))
+	assert.True(t, executionInfo.GetDecisionStartedTimestamp().IsZero())
+	assert.True(t, executionInfo.GetDecisionOriginalScheduledTimestamp().IsZero())
+}
+
+func TestActivityInfoLastFailure(t *testing.T) {
+	info := &ActivityInfo{}
+	assert.Nil(t, info.GetLastFailure())
+
+	failure := &types.Failure{
+		Reason:  "some random reason",
+		Details: []byte("some random details"),
+	}
+	info.SetLastFailure(failure)
+	assert.Equal(t, failure.Reason, info.LastFailureReason)
+	assert.Equal(t, failure.Details, info.LastFailureDetails)
+	assert.Equal(t, failure, info.GetLastFailure())
+
+	info.SetLastFailure(&types.Failure{Reason: "reason only"})
+	assert.Equal(t, &types.Failure{Reason: "reason only"}, info.GetLastFailure())
+
+	info.SetLastFailure(nil)
+	assert.Empty(t, info.LastFailureReason)
+	assert.Nil(t, info.LastFailureDetails)
+	assert.Nil(t, info.GetLastFailure())
+}
+
+type recordingTaskVisitor struct {
+	visited []Task
+}
+
+var _ TaskVisitor = (*recordingTaskVisitor)(nil)
+
+func (v *recordingTaskVisitor) record(task Task) error {
+	v.visited = append(v.visited, task)
+	return nil
+}
+
+func (v *recordingTaskVisitor) VisitActivityTask(task *ActivityTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitDecisionTask(task *DecisionTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitRecordWorkflowStartedTask(task *RecordWorkflowStartedTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitResetWorkflowTask(task *ResetWorkflowTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitCloseExecutionTask(task *CloseExecutionTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitDeleteHistoryEventTask(task *DeleteHistoryEventTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitDecisionTimeoutTask(task *DecisionTimeoutTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitActivityTimeoutTask(task *ActivityTimeoutTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitUserTimerTask(task *UserTimerTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitActivityRetryTimerTask(task *ActivityRetryTimerTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitWorkflowBackoffTimerTask(task *WorkflowBackoffTimerTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitWorkflowTimeoutTask(task *WorkflowTimeoutTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitCancelExecutionTask(task *CancelExecutionTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitSignalExecutionTask(task *SignalExecutionTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitUpsertWorkflowSearchAttributesTask(task *UpsertWorkflowSearchAttributesTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitStartChildExecutionTask(task *StartChildExecutionTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitHistoryReplicationTask(task *HistoryReplicationTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitSyncActivityTask(task *SyncActivityTask) error {
+	return v.record(task)
+}
+
+func (v *recordingTaskVisitor) VisitFailoverMarkerTask(task *FailoverMarkerTask) error {
+	return v.record(task)
+}
+
+func TestTaskAccept(t *testing.T) {
+	tasks := []Task{
+		&ActivityTask{},
+		&DecisionTask{},
+		&RecordWorkflowStartedTask{},
+		&ResetWorkflowTask{},
+		&CloseExecutionTask{},
+		&DeleteHistoryEventTask{},
+		&DecisionTimeoutTask{},
+		&ActivityTimeoutTask{},
+		&UserTimerTask{},
+		&ActivityRetryTimerTask{},
+		&WorkflowBackoffTimerTask{},
+		&WorkflowTimeoutTask{},
+		&CancelExecutionTask{},
+		&SignalExecutionTask{},
+		&UpsertWorkflowSearchAttributesTask{},
+		&StartChildExecutionTask{},
+		&HistoryReplicationTask{},
+		&SyncActivityTask{},
+		&FailoverMarkerTask{},
+	}
+	visitor := &recordingTaskVisitor{}
+	for _, task := range tasks {
+		require.NoError(t, task.Accept(visitor))
+	}
+	// each task must be passed to the visitor as is, so the visitor saw the same pointers
+	require.Len(t, visitor.visited, len(tasks))
+	for i, task := range tasks {
+		assert.Same(t, task, visitor.visited[i])
+	}
+}
+
+func TestDomainReplicationConfigValidate(t *testing.T) {
+	clusters := []*ClusterReplicationConfig{{ClusterName: "active"}, {ClusterName: "standby"}}
+
+	assert.NoError(t, (&DomainReplicationConfig{}).Validate())
+	assert.NoError(t, (&DomainReplicationConfig{ActiveClusterName: "active"}).Validate())
+	assert.NoError(t, (&DomainReplicationConfig{Clusters: clusters}).Validate())
+	assert.NoError(t, (&DomainReplicationConfig{ActiveClusterName: "standby", Clusters: clusters}).Validate())
+
+	err := (&DomainReplicationConfig{ActiveClusterName: "other", Clusters: clusters}).Validate()
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+
+	err = (&DomainReplicationConfig{
+		ActiveClusterName: "active",
+		Clusters:          append(clusters, &ClusterReplicationConfig{ClusterName: "standby"}),
+	}).Validate()
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+}
+
+func TestNewWorkflowBackoffTimerTask(t *testing.T) {
+	now := time.Now()
+	task, err := NewWorkflowBackoffTimerTask(now, WorkflowBackoffTimeoutTypeRetry, 10)
+	require.NoError(t, err)
+	assert.Equal(t, now, task.GetVisibilityTimestamp())
+	assert.Equal(t, int64(10), task.GetVersion())
+	assert.True(t, task.IsRetry())
+	assert.False(t, task.IsCron())
+
+	task, err = NewWorkflowBackoffTimerTask(now, WorkflowBackoffTimeoutTypeCron, 10)
+	require.NoError(t, err)
+	assert.False(t, task.IsRetry())
+	assert.True(t, task.IsCron())
+
+	_, err = NewWorkflowBackoffTimerTask(now, WorkflowBackoffTimeoutTypeCron+1, 10)
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+}
+
+func TestTaskListInfoIsExpired(t *testing.T) {
+	now := time.Now()
+	assert.False(t, (&TaskListInfo{}).IsExpired(now))
+	assert.False(t, (&TaskListInfo{Expiry: now.Add(time.Second)}).IsExpired(now))
+	assert.True(t, (&TaskListInfo{Expiry: now}).IsExpired(now))
+	assert.True(t, (&TaskListInfo{Expiry: now.Add(-time.Second)}).IsExpired(now))
+}
+
+func TestReplicationStateIsStaleCompared(t *testing.T) {
+	state := &ReplicationState{LastWriteVersion: 10, LastWriteEventID: 20}
+	assert.True(t, state.IsStaleCompared(11, 1))
+	assert.True(t, state.IsStaleCompared(10, 21))
+	assert.False(t, state.IsStaleCompared(10, 20))
+	assert.False(t, state.IsStaleCompared(10, 19))
+	assert.False(t, state.IsStaleCompared(9, 100))
+}
+
+func TestReplicationStateMergeLastReplicationInfo(t *testing.T) {
+	state := &ReplicationState{}
+	state.MergeLastReplicationInfo(map[string]*ReplicationInfo{
+		"active":  {Version: 10, LastEventID: 20},
+		"standby": {Version: 5, LastEventID: 30},
+		"other":   nil,
+	})
+	incoming := map[string]*ReplicationInfo{
+		"active":  {Version: 10, LastEventID: 15},
+		"standby": {Version: 6, LastEventID: 1},
+		"new":     {Version: 1, LastEventID: 2},
+	}
+	state.MergeLastReplicationInfo(incoming)
+	assert.Equal(t, map[string]*ReplicationInfo{
+		"active":  {Version: 10, LastEventID: 20},
+		"standby": {Version: 6, LastEventID: 1},
+		"new":     {Version: 1, LastEventID: 2},
+	}, state.LastReplicationInfo)
+
+	incoming["new"].LastEventID = 100
+	assert.Equal(t, int64(2), state.LastReplicationInfo["new"].LastEventID)
+}
+
+func TestWorkflowMutableStateDeepCopy(t *testing.T) {
+	newState := func() *WorkflowMutableState {
+		return &WorkflowMutableState{
+			ActivityInfos: map[int64]*ActivityInfo{
+				5: {
+					ScheduleID:     5,
+					ScheduledEvent: &types.HistoryEvent{EventID: 5, Version: 1},
+					Details:        []byte("activity details"),
+				},
+			},
+			TimerInfos: map[string]*TimerInfo{
+				"timer": {TimerID: "timer", StartedID: 6},
+			},
+			ChildExecutionInfos: map[int64]*ChildExecutionInfo{
+				7: {InitiatedID: 7, InitiatedEvent: &types.HistoryEvent{EventID: 7, Version: 1}},
+			},
+			RequestCancelInfos: map[int64]*RequestCancelInfo{
+				8: {InitiatedID: 8, CancelRequestID: "cancel request"},
+			},
+			SignalInfos: map[int64]*SignalInfo{
+				9: {InitiatedID: 9, Input: []byte("signal input")},
+			},
+			SignalRequestedIDs: map[string]struct{}{
+				"signal request": {},
+			},
+			ExecutionInfo: &WorkflowExecutionInfo{
+				DomainID:         "domain",
+				WorkflowID:       "workflow",
+				RunID:            "run",
+				NextEventID:      10,
+				BranchToken:      []byte("branch token"),
+				Memo:             map[string][]byte{"memo": []byte("memo value")},
+				SearchAttributes: map[string][]byte{"attr": []byte("attr value")},
+				AutoResetPoints: &types.ResetPoints{
+					Points: []*types.ResetPointInfo{{BinaryChecksum: "checksum", CreatedTimeNano: common.Int64Ptr(1)}},
+				},
+				NonRetriableErrors: []string{},
+			},
+			ExecutionStats: &ExecutionStats{HistorySize: 1024},
+			BufferedEvents: []*types.HistoryEvent{
+				{
+					EventID:   11,
+					Version:   1,
+					EventType: types.EventTypeActivityTaskCompleted.Ptr(),
+					ActivityTaskCompletedEventAttributes: &types.ActivityTaskCompletedEventAttributes{
+						Result:           []byte{},
+						ScheduledEventID: 5,
+						StartedEventID:   common.EmptyEventID,
+					},
+				},
+			},
+			VersionHistories: NewVersionHistories(NewVersionHistory(
+				[]byte("version history branch token"),
+				[]*VersionHistoryItem{NewVersionHistoryItem(9, 1)},
+			)),
+		}
+	}
+
+	original := newState()
+	copied := original.DeepCopy()
+	require.Equal(t, original, copied)
+
+	copied.ActivityInfos[5].ScheduledEvent.EventID = 100
+	copied.ActivityInfos[5].Details[0] = 'x'
+	copied.ActivityInfos[6] = &ActivityInfo{}
+	copied.TimerInfos["timer"].StartedID = 100
+	copied.ChildExecutionInfos[7].InitiatedEvent.Version = 100
+	copied.RequestCancelInfos[8].CancelRequestID = "other cancel request"
+	copied.SignalInfos[9].Input[0] = 'x'
+	copied.SignalRequestedIDs["other signal request"] = struct{}{}
+	copied.ExecutionInfo.NextEventID = 100
+	copied.ExecutionInfo.BranchToken[0] = 'x'
+	copied.ExecutionInfo.Memo["memo"][0] = 'x'
+	copied.ExecutionInfo.SearchAttributes["attr"][0] = 'x'
+	copied.ExecutionInfo.AutoResetPoints.Points[0].BinaryChecksum = "other checksum"
+	*copied.ExecutionInfo.AutoResetPoints.Points[0].CreatedTimeNano = 100
+	copied.ExecutionStats.HistorySize = 100
+	copied.BufferedEvents[0].EventID = 100
+	*copied.BufferedEvents[0].EventType = types.EventTypeActivityTaskFailed
+	copied.BufferedEvents[0].ActivityTaskCompletedEventAttributes.StartedEventID = 6
+	copied.VersionHistories.Histories[0].BranchToken[0] = 'x'
+	copied.VersionHistories.Histories[0].Items[0].EventID = 100
+
+	assert.Equal(t, newState(), original)
+	assert.Nil(t, (*WorkflowMutableState)(nil).DeepCopy())
+}
+
+func TestWorkflowMutableStateValidate(t *testing.T) {
+	newState := func() *WorkflowMutableState {
+		return &WorkflowMutableState{
+			ExecutionInfo: &WorkflowExecutionInfo{
+				DecisionScheduleID: 5,
+				NextEventID:        6,
+			},
+			ChildExecutionInfos: map[int64]*ChildExecutionInfo{
+				3: {InitiatedID: 3},
+				4: {InitiatedID: 4},
+			},
+			SignalRequestedIDs: map[string]struct{}{
+				"6e4e9cba-7df2-4a1b-9d8e-8b7a7c9e1b2a": {},
+				"1f0bd2e4-2c4d-4b5e-8f6a-7b8c9d0e1f2a": {},
+			},
+		}
+	}
+	require.NoError(t, newState().Validate())
+
+	state := newState()
+	state.ExecutionInfo.DecisionScheduleID = common.EmptyEventID
+	assert.NoError(t, state.Validate())
+
+	// the schedule event of a transient decision is not written yet
+	state = newState()
+	state.ExecutionInfo.DecisionScheduleID = 6
+	assert.NoError(t, state.Validate())
+
+	state = newState()
+	state.ExecutionInfo.DecisionScheduleID = 7
+	assert.IsType(t, &InvalidPersistenceRequestError{}, state.Validate())
+
+	state = newState()
+	state.ExecutionInfo.DecisionScheduleID = 0
+	assert.IsType(t, &InvalidPersistenceRequestError{}, state.Validate())
+
+	state = newState()
+	state.ChildExecutionInfos[4].InitiatedID = 3
+	assert.IsType(t, &InvalidPersistenceRequestError{}, state.Validate())
+
+	state = newState()
+	state.ChildExecutionInfos[5] = nil
+	assert.IsType(t, &InvalidPersistenceRequestError{}, state.Validate())
+
+	// request IDs are opaque, so IDs which only differ in case are distinct
+	state = newState()
+	state.SignalRequestedIDs["6E4E9CBA-7DF2-4A1B-9D8E-8B7A7C9E1B2A"] = struct{}{}
+	assert.NoError(t, state.Validate())
+}
+
+func TestCreateWorkflowExecutionRequestValidate(t *testing.T) {
+	newRequest := func() *CreateWorkflowExecutionRequest {
+		return &CreateWorkflowExecutionRequest{
+			Mode: CreateWorkflowModeBrandNew,
+			NewWorkflowSnapshot: WorkflowSnapshot{
+				ExecutionInfo: &WorkflowExecutionInfo{
+					DomainID:           "domain",
+					WorkflowID:         "workflow",
+					RunID:              "run",
+					NextEventID:        3,
+					DecisionScheduleID: 2,
+				},
+				TransferTasks: []Task{&DecisionTask{}},
+				TimerTasks:    []Task{&WorkflowTimeoutTask{VisibilityTimestamp: time.Now()}},
+			},
+		}
+	}
+	require.NoError(t, newRequest().Validate())
+
+	testCases := map[string]func(r *CreateWorkflowExecutionRequest){
+		"nil execution info": func(r *CreateWorkflowExecutionRequest) {
+			r.NewWorkflowSnapshot.ExecutionInfo = nil
+		},
+		"empty run ID": func(r *CreateWorkflowExecutionRequest) {
+			r.NewWorkflowSnapshot.ExecutionInfo.RunID = ""
+		},
+		"decision schedule ID beyond next event ID": func(r *CreateWorkflowExecutionRequest) {
+			r.NewWorkflowSnapshot.ExecutionInfo.DecisionScheduleID = 10
+		},
+		"brand new with previous run ID": func(r *CreateWorkflowExecutionRequest) {
+			r.PreviousRunID = "previous run"
+		},
+		"workflow ID reuse without previous run ID": func(r *CreateWorkflowExecutionRequest) {
+			r.Mode = CreateWorkflowModeWorkflowIDReuse
+		},
+		"unknown mode": func(r *CreateWorkflowExecutionRequest) {
+			r.Mode = CreateWorkflowMode(100)
+		},
+		"nil transfer task": func(r *CreateWorkflowExecutionRequest) {
+			r.NewWorkflowSnapshot.TransferTasks = append(r.NewWorkflowSnapshot.TransferTasks, nil)
+		},
+		"timer task without visibility timestamp": func(r *CreateWorkflowExecutionRequest) {
+			r.NewWorkflowSnapshot.TimerTasks = []Task{&UserTimerTask{}}
+		},
+		"memo over budget": func(r *CreateWorkflowExecutionRequest) {
+			r.MaxMemoSize = 10
+			r.NewWorkflowSnapshot.ExecutionInfo.Memo = map[string][]byte{"key": []byte("large memo")}
+		},
+		"search attributes over budget": func(r *CreateWorkflowExecutionRequest) {
+			r.MaxSearchAttributesSize = 10
+			r.NewWorkflowSnapshot.ExecutionInfo.SearchAttributes = map[string][]byte{"key": []byte("large attribute")}
+		},
+	}
+	for name, mutate := range testCases {
+		request := newRequest()
+		mutate(request)
+		err := request.Validate()
+		assert.IsType(t, &InvalidPersistenceRequestError{}, err, name)
+	}
+}
+
+func TestRangeCompleteRequestsValidate(t *testing.T) {
+	assert.NoError(t, (&RangeCompleteTransferTaskRequest{ExclusiveBeginTaskID: 1, InclusiveEndTaskID: 10}).Validate())
+	assert.NoError(t, (&RangeCompleteTransferTaskRequest{ExclusiveBeginTaskID: 10, InclusiveEndTaskID: 10}).Validate())
+	assert.IsType(t, &InvalidPersistenceRequestError{}, (&RangeCompleteTransferTaskRequest{ExclusiveBeginTaskID: 11, InclusiveEndTaskID: 10}).Validate())
+
+	assert.NoError(t, (&RangeCompleteCrossClusterTaskRequest{ExclusiveBeginTaskID: 1, InclusiveEndTaskID: 10}).Validate())
+	assert.IsType(t, &InvalidPersistenceRequestError{}, (&RangeCompleteCrossClusterTaskRequest{ExclusiveBeginTaskID: 11, InclusiveEndTaskID: 10}).Validate())
+
+	now := time.Now()
+	assert.NoError(t, (&RangeCompleteTimerTaskRequest{InclusiveBeginTimestamp: now, ExclusiveEndTimestamp: now.Add(time.Second)}).Validate())
+	assert.IsType(t, &InvalidPersistenceRequestError{}, (&RangeCompleteTimerTaskRequest{InclusiveBeginTimestamp: now, ExclusiveEndTimestamp: now}).Validate())
+	assert.IsType(t, &InvalidPersistenceRequestError{}, (&RangeCompleteTimerTaskRequest{InclusiveBeginTimestamp: now, ExclusiveEndTimestamp: now.Add(-time.Second)}).Validate())
+	assert.IsType(t, &InvalidPersistenceRequestError{}, (&RangeCompleteTimerTaskRequest{}).Validate())
+}
+
+func TestValidateAttributesSize(t *testing.T) {
+	attributes := map[string][]byte{
+		"small": []byte("1"),
+		"large": []byte("1234567890"),
+	}
+	assert.NoError(t, validateAttributesSize("operation", "memo", attributes, 0))
+	assert.NoError(t, validateAttributesSize("operation", "memo", attributes, 21))
+
+	err := validateAttributesSize("operation", "memo", attributes, 20)
+	require.IsType(t, &InvalidPersistenceRequestError{}, err)
+	assert.Equal(t,
+		`operation: memo size of 21 bytes exceeds the limit of 20 bytes, largest key: "large" with 15 bytes`,
+		err.Error())
+}
+
+func TestSignalInfoValidate(t *testing.T) {
+	signalInfo := &SignalInfo{
+		InitiatedID: 5,
+		SignalName:  "signal",
+		Input:       []byte("1234567890"),
+		Control:     []byte("12345"),
+	}
+	assert.NoError(t, signalInfo.Validate(0))
+	assert.NoError(t, signalInfo.Validate(15))
+
+	err := signalInfo.Validate(14)
+	require.IsType(t, &InvalidPersistenceRequestError{}, err)
+	assert.Equal(t,
+		`signal "signal" with initiated ID 5: input and control size of 15 bytes exceeds the limit of 14 bytes`,
+		err.Error())
+}
+
+func TestDataBlobGetEncodingType(t *testing.T) {
+	assert.Equal(t, common.EncodingTypeThriftRW, (&DataBlob{Encoding: common.EncodingTypeThriftRW}).GetEncodingType())
+	assert.Equal(t, common.EncodingTypeJSON, (&DataBlob{Encoding: common.EncodingTypeJSON}).GetEncodingType())
+	assert.Equal(t, common.EncodingTypeUnknown, (&DataBlob{Encoding: "snappy"}).GetEncodingType())
+	var blob *DataBlob
+	assert.Equal(t, common.EncodingTypeUnknown, blob.GetEncodingType())
+}
+
+func TestReadRawHistoryBranchResponseValidateEncodings(t *testing.T) {
+	response := &ReadRawHistoryBranchResponse{
+		HistoryEventBlobs: []*DataBlob{
+			{Encoding: common.EncodingTypeThriftRW, Data: []byte("a")},
+			{Encoding: common.EncodingTypeThriftRW, Data: []byte("b")},
+		},
+	}
+	assert.NoError(t, response.ValidateEncodings(common.EncodingTypeThriftRW))
+	assert.IsType(t, &InvalidPersistenceRequestError{}, response.ValidateEncodings(common.EncodingTypeJSON))
+
+	response.HistoryEventBlobs = append(response.HistoryEventBlobs, &DataBlob{Encoding: "snappy", Data: []byte("c")})
+	assert.IsType(t, &InvalidPersistenceRequestError{}, response.ValidateEncodings(common.EncodingTypeThriftRW))
+
+	response.HistoryEventBlobs[2] = nil
+	assert.IsType(t, &InvalidPersistenceRequestError{}, response.ValidateEncodings(common.EncodingTypeThriftRW))
+
+	assert.NoError(t, (&ReadRawHistoryBranchResponse{}).ValidateEncodings(common.EncodingTypeJSON))
+}
+
+type blockingCloseable struct {
+	release chan struct{}
+	closed  chan struct{}
+}
+
+func (c *blockingCloseable) Close() {
+	<-c.release
+	close(c.closed)
+}
+
+func TestCloseWithContext(t *testing.T) {
+	c := &blockingCloseable{release: make(chan struct{}), closed: make(chan struct{})}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, CloseWithContext(ctx, c))
+
+	// closing carries on in the background after the deadline
+	close(c.release)
+	select {
+	case <-c.closed:
+	case <-time.After(time.Second):
+		t.Fatal("close did not complete")
+	}
+
+	c = &blockingCloseable{release: make(chan struct{}), closed: make(chan struct{})}
+	close(c.release)
+	assert.NoError(t, CloseWithContext(context.Background(), c))
+}
+
+func TestHistoryBranchTokenRoundTrip(t *testing.T) {
+	ancestors := []*workflow.HistoryBranchRange{
+		{BranchID: common.StringPtr("ancestor-1"), BeginNodeID: common.Int64Ptr(1), EndNodeID: common.Int64Ptr(5)},
+		{BranchID: common.StringPtr("ancestor-2"), BeginNodeID: common.Int64Ptr(5), EndNodeID: common.Int64Ptr(9)},
+	}
+	token, err := NewHistoryBranchTokenWithAncestors("tree", "branch", ancestors)
+	require.NoError(t, err)
+
+	branch, err := ParseHistoryBranchToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "tree", branch.GetTreeID())
+	assert.Equal(t, "branch", branch.GetBranchID())
+	assert.Equal(t, ancestors, branch.Ancestors)
+
+	token, err = NewHistoryBranchTokenFromAnother("new-branch", token)
+	require.NoError(t, err)
+	branch, err = ParseHistoryBranchToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "tree", branch.GetTreeID())
+	assert.Equal(t, "new-branch", branch.GetBranchID())
+	assert.Empty(t, branch.Ancestors)
+
+	_, err = ParseHistoryBranchToken([]byte("invalid token"))
+	assert.Error(t, err)
+}
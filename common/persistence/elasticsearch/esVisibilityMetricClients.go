@@ -323,3 +323,7 @@ func (p *visibilityMetricsClient) updateErrorMetric(scope int, err error) {
 func (p *visibilityMetricsClient) Close() {
 	p.persistence.Close()
 }
+
+func (p *visibilityMetricsClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
@@ -23,16 +23,33 @@ package persistence
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/checksum"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/types"
 )
 
+const (
+	// deleteWorkflowExecutionsConcurrency is the max number of runs deleted concurrently by DeleteWorkflowExecutions
+	deleteWorkflowExecutionsConcurrency = 16
+)
+
 type (
+	// workflowUpdateToken is the content of the opaque token returned by GetWorkflowExecutionForUpdate
+	workflowUpdateToken struct {
+		RunID       string
+		RangeID     int64
+		NextEventID int64
+	}
+
 	// executionManagerImpl implements ExecutionManager based on ExecutionStore, statsComputer and PayloadSerializer
 	executionManagerImpl struct {
+		executionManagerCompositeOperations
+
 		serializer    PayloadSerializer
 		persistence   ExecutionStore
 		statsComputer statsComputer
@@ -48,12 +65,14 @@ func NewExecutionManagerImpl(
 	logger log.Logger,
 ) ExecutionManager {
 
-	return &executionManagerImpl{
+	m := &executionManagerImpl{
 		serializer:    NewPayloadSerializer(),
 		persistence:   persistence,
 		statsComputer: statsComputer{},
 		logger:        logger,
 	}
+	m.executionManagerCompositeOperations = executionManagerCompositeOperations{manager: m}
+	return m
 }
 
 func (m *executionManagerImpl) GetName() string {
@@ -71,8 +90,16 @@ func (m *executionManagerImpl) GetWorkflowExecution(
 ) (*GetWorkflowExecutionResponse, error) {
 
 	internalRequest := &InternalGetWorkflowExecutionRequest{
-		DomainID:  request.DomainID,
-		Execution: request.Execution,
+		DomainID:              request.DomainID,
+		Execution:             request.Execution,
+		ExcludeBufferedEvents: request.ExcludeBufferedEvents,
+		ExcludeActivityInfos:  request.ExcludeActivityInfos,
+		Fields:                request.Fields,
+	}
+	if request.VerifyChecksum && !internalRequest.LoadsAllFields() {
+		return nil, &InvalidPersistenceRequestError{
+			Msg: "GetWorkflowExecution can not verify the checksum of a partially loaded mutable state",
+		}
 	}
 	response, err := m.persistence.GetWorkflowExecution(ctx, internalRequest)
 	if err != nil {
@@ -87,6 +114,7 @@ func (m *executionManagerImpl) GetWorkflowExecution(
 			ReplicationState:   response.State.ReplicationState, // TODO: remove this after all 2DC workflows complete
 			Checksum:           response.State.Checksum,
 		},
+		DBRecordVersion: response.DBRecordVersion,
 	}
 
 	newResponse.State.ActivityInfos, err = m.DeserializeActivityInfos(response.State.ActivityInfos)
@@ -110,11 +138,99 @@ func (m *executionManagerImpl) GetWorkflowExecution(
 		return nil, err
 	}
 	newResponse.State.VersionHistories = versionHistories
+	if len(request.Fields) > 0 {
+		clearUnloadedMutableStateFields(internalRequest, newResponse.State)
+	}
 	newResponse.MutableStateStats = m.statsComputer.computeMutableStateStats(response)
 
+	if request.VerifyChecksum {
+		if err := m.verifyChecksum(request, newResponse.State); err != nil {
+			return nil, err
+		}
+	}
 	return newResponse, nil
 }
 
+// clearUnloadedMutableStateFields sets the sections of the mutable state which were not loaded to nil,
+// so callers can tell them apart from empty ones
+func clearUnloadedMutableStateFields(
+	request *InternalGetWorkflowExecutionRequest,
+	state *WorkflowMutableState,
+) {
+	if !request.LoadsField(MutableStateFieldActivityInfos) {
+		state.ActivityInfos = nil
+	}
+	if !request.LoadsField(MutableStateFieldTimerInfos) {
+		state.TimerInfos = nil
+	}
+	if !request.LoadsField(MutableStateFieldChildExecutionInfos) {
+		state.ChildExecutionInfos = nil
+	}
+	if !request.LoadsField(MutableStateFieldRequestCancelInfos) {
+		state.RequestCancelInfos = nil
+	}
+	if !request.LoadsField(MutableStateFieldSignalInfos) {
+		state.SignalInfos = nil
+	}
+	if !request.LoadsField(MutableStateFieldSignalRequestedIDs) {
+		state.SignalRequestedIDs = nil
+	}
+	if !request.LoadsField(MutableStateFieldBufferedEvents) {
+		state.BufferedEvents = nil
+	}
+}
+
+func (m *executionManagerImpl) GetWorkflowExecutionForUpdate(
+	ctx context.Context,
+	request *GetWorkflowExecutionForUpdateRequest,
+) (*GetWorkflowExecutionForUpdateResponse, error) {
+
+	resp, err := m.GetWorkflowExecution(ctx, &GetWorkflowExecutionRequest{
+		DomainID:  request.DomainID,
+		Execution: request.Execution,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := json.Marshal(&workflowUpdateToken{
+		RunID:       resp.State.ExecutionInfo.RunID,
+		RangeID:     request.RangeID,
+		NextEventID: resp.State.ExecutionInfo.NextEventID,
+	})
+	if err != nil {
+		return nil, &types.InternalServiceError{Message: fmt.Sprintf("unable to create update token: %v", err)}
+	}
+	return &GetWorkflowExecutionForUpdateResponse{
+		State:             resp.State,
+		MutableStateStats: resp.MutableStateStats,
+		UpdateToken:       token,
+	}, nil
+}
+
+func (m *executionManagerImpl) verifyChecksum(
+	request *GetWorkflowExecutionRequest,
+	state *WorkflowMutableState,
+) error {
+	csum := state.Checksum
+	if len(csum.Value) == 0 {
+		// no checksum was persisted with the mutable state
+		return nil
+	}
+	if err := VerifyMutableStateChecksum(state, csum); err != nil {
+		return &WorkflowExecutionCorruptedError{
+			Msg: fmt.Sprintf("Workflow execution checksum verification failed. DomainID: %v, WorkflowID: %v, RunID: %v, checksum flavor: %v, checksum version: %v, error: %v",
+				request.DomainID, request.Execution.WorkflowID, request.Execution.RunID, csum.Flavor, csum.Version, err),
+			DomainID:        request.DomainID,
+			WorkflowID:      request.Execution.WorkflowID,
+			RunID:           request.Execution.RunID,
+			ChecksumFlavor:  csum.Flavor,
+			ChecksumVersion: csum.Version,
+		}
+	}
+	return nil
+}
+
 func (m *executionManagerImpl) DeserializeExecutionInfo(
 	info *InternalWorkflowExecutionInfo,
 ) (*WorkflowExecutionInfo, *ExecutionStats, error) {
@@ -314,13 +430,47 @@ func (m *executionManagerImpl) UpdateWorkflowExecution(
 	request *UpdateWorkflowExecutionRequest,
 ) (*UpdateWorkflowExecutionResponse, error) {
 
-	serializedWorkflowMutation, err := m.SerializeWorkflowMutation(&request.UpdateWorkflowMutation, request.Encoding)
+	if len(request.UpdateToken) > 0 {
+		if err := validateWorkflowUpdateToken(request); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateUpdateWorkflowModeCurrentRunID(request); err != nil {
+		return nil, err
+	}
+	if err := validateWorkflowMutationSize(
+		"UpdateWorkflowExecution",
+		&request.UpdateWorkflowMutation,
+		request.WorkflowSizeLimits,
+	); err != nil {
+		return nil, err
+	}
+	if request.NewWorkflowSnapshot != nil {
+		if err := validateWorkflowSnapshotSize(
+			"UpdateWorkflowExecution",
+			request.NewWorkflowSnapshot,
+			request.WorkflowSizeLimits,
+		); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateWorkflowMutation(&request.UpdateWorkflowMutation); err != nil {
+		return nil, err
+	}
+	if request.NewWorkflowSnapshot != nil {
+		if err := validateWorkflowSnapshot(request.NewWorkflowSnapshot); err != nil {
+			return nil, err
+		}
+	}
+
+	encoding := ResolveEncoding(request.Encoding)
+	serializedWorkflowMutation, err := m.SerializeWorkflowMutation(&request.UpdateWorkflowMutation, encoding)
 	if err != nil {
 		return nil, err
 	}
 	var serializedNewWorkflowSnapshot *InternalWorkflowSnapshot
 	if request.NewWorkflowSnapshot != nil {
-		serializedNewWorkflowSnapshot, err = m.SerializeWorkflowSnapshot(request.NewWorkflowSnapshot, request.Encoding)
+		serializedNewWorkflowSnapshot, err = m.SerializeWorkflowSnapshot(request.NewWorkflowSnapshot, encoding)
 		if err != nil {
 			return nil, err
 		}
@@ -339,6 +489,166 @@ func (m *executionManagerImpl) UpdateWorkflowExecution(
 	return &UpdateWorkflowExecutionResponse{MutableStateUpdateSessionStats: msuss}, err1
 }
 
+func (m *executionManagerImpl) FlushBufferedEvents(
+	ctx context.Context,
+	request *FlushBufferedEventsRequest,
+) error {
+	if request.RangeID == 0 {
+		return &InvalidPersistenceRequestError{
+			Msg: "FlushBufferedEvents: RangeID is required",
+		}
+	}
+	if request.HistoryManager == nil {
+		return &InvalidPersistenceRequestError{
+			Msg: "FlushBufferedEvents: HistoryManager is required",
+		}
+	}
+	resp, err := m.GetWorkflowExecution(ctx, &GetWorkflowExecutionRequest{
+		DomainID:  request.DomainID,
+		Execution: request.Execution,
+	})
+	if err != nil {
+		return err
+	}
+	state := resp.State
+	if len(state.BufferedEvents) == 0 {
+		return nil
+	}
+	executionInfo := state.ExecutionInfo
+	condition := executionInfo.NextEventID
+
+	branchToken := executionInfo.BranchToken
+	var currentVersionHistory *VersionHistory
+	if state.VersionHistories != nil {
+		currentVersionHistory, err = state.VersionHistories.GetCurrentVersionHistory()
+		if err != nil {
+			return err
+		}
+		branchToken = currentVersionHistory.GetBranchToken()
+	}
+
+	events := state.BufferedEvents
+	for i, event := range events {
+		event.EventID = condition + int64(i)
+		event.TaskID = request.TransactionID
+	}
+	lastEvent := events[len(events)-1]
+	if currentVersionHistory != nil {
+		if err := currentVersionHistory.AddOrUpdateItem(NewVersionHistoryItem(lastEvent.EventID, lastEvent.Version)); err != nil {
+			return err
+		}
+	}
+
+	shardID := m.GetShardID()
+	if _, err := request.HistoryManager.AppendHistoryNodes(ctx, &AppendHistoryNodesRequest{
+		BranchToken:   branchToken,
+		Events:        events,
+		TransactionID: request.TransactionID,
+		Encoding:      request.Encoding,
+		ShardID:       &shardID,
+	}); err != nil {
+		return err
+	}
+
+	currentRun, err := m.persistence.GetCurrentRunID(ctx, &GetCurrentRunIDRequest{
+		DomainID:   request.DomainID,
+		WorkflowID: request.Execution.GetWorkflowID(),
+	})
+	if err != nil {
+		return err
+	}
+	mode := UpdateWorkflowModeBypassCurrent
+	if currentRun.RunID == executionInfo.RunID {
+		mode = UpdateWorkflowModeUpdateCurrent
+	}
+
+	executionInfo.SetLastFirstEventID(events[0].EventID)
+	executionInfo.SetNextEventID(lastEvent.EventID + 1)
+	// if the mutable state is not updated, the appended node is left dangling past NextEventID, it is
+	// overwritten by the next append of the same node which always comes with a larger TransactionID
+	_, err = m.UpdateWorkflowExecution(ctx, &UpdateWorkflowExecutionRequest{
+		RangeID: request.RangeID,
+		Mode:    mode,
+		UpdateWorkflowMutation: WorkflowMutation{
+			ExecutionInfo:       executionInfo,
+			ExecutionStats:      state.ExecutionStats,
+			VersionHistories:    state.VersionHistories,
+			ClearBufferedEvents: true,
+			// no checksum is written, the persisted one does not match the flushed mutable state
+			Condition: condition,
+		},
+		Encoding:     request.Encoding,
+		CurrentRunID: currentRun.RunID,
+	})
+	return err
+}
+
+// validateWorkflowUpdateToken checks that the update is made from the read the token was issued for,
+// the store then enforces that the RangeID and the Condition still match the database
+func validateWorkflowUpdateToken(
+	request *UpdateWorkflowExecutionRequest,
+) error {
+
+	var token workflowUpdateToken
+	if err := json.Unmarshal(request.UpdateToken, &token); err != nil {
+		return &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("invalid update token: %v", err),
+		}
+	}
+
+	mutation := request.UpdateWorkflowMutation
+	if mutation.ExecutionInfo == nil || mutation.ExecutionInfo.RunID != token.RunID {
+		return &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("update token was issued for run %v", token.RunID),
+		}
+	}
+	if request.RangeID != token.RangeID || mutation.Condition != token.NextEventID {
+		return &ConditionFailedError{
+			Msg: fmt.Sprintf("update does not match the read it is based on. RangeID: %v, expected: %v, Condition: %v, expected: %v",
+				request.RangeID, token.RangeID, mutation.Condition, token.NextEventID),
+		}
+	}
+	return nil
+}
+
+// validateUpdateWorkflowModeCurrentRunID checks the update mode against the current run the caller provides,
+// the mode is left to the conditional write of the store if the caller does not provide it
+func validateUpdateWorkflowModeCurrentRunID(
+	request *UpdateWorkflowExecutionRequest,
+) error {
+
+	executionInfo := request.UpdateWorkflowMutation.ExecutionInfo
+	if executionInfo == nil || request.CurrentRunID == "" {
+		return nil
+	}
+	return validateUpdateWorkflowMode(request.Mode, executionInfo.RunID, request.CurrentRunID)
+}
+
+func validateUpdateWorkflowMode(
+	mode UpdateWorkflowMode,
+	runID string,
+	currentRunID string,
+) error {
+
+	switch mode {
+	case UpdateWorkflowModeUpdateCurrent:
+		if runID != currentRunID {
+			return &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("UpdateWorkflowExecution: run %v is not the current run %v, current record can not be updated",
+					runID, currentRunID),
+			}
+		}
+	case UpdateWorkflowModeBypassCurrent:
+		if runID == currentRunID {
+			return &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("UpdateWorkflowExecution: run %v is the current run, current record can not be bypassed",
+					runID),
+			}
+		}
+	}
+	return nil
+}
+
 func (m *executionManagerImpl) SerializeUpsertChildExecutionInfos(
 	infos []*ChildExecutionInfo,
 	encoding common.EncodingType,
@@ -515,20 +825,25 @@ func (m *executionManagerImpl) ConflictResolveWorkflowExecution(
 	request *ConflictResolveWorkflowExecutionRequest,
 ) error {
 
-	serializedResetWorkflowSnapshot, err := m.SerializeWorkflowSnapshot(&request.ResetWorkflowSnapshot, request.Encoding)
+	if err := validateConflictResolveWorkflowExecutionRequest(request); err != nil {
+		return err
+	}
+
+	encoding := ResolveEncoding(request.Encoding)
+	serializedResetWorkflowSnapshot, err := m.SerializeWorkflowSnapshot(&request.ResetWorkflowSnapshot, encoding)
 	if err != nil {
 		return err
 	}
 	var serializedCurrentWorkflowMutation *InternalWorkflowMutation
 	if request.CurrentWorkflowMutation != nil {
-		serializedCurrentWorkflowMutation, err = m.SerializeWorkflowMutation(request.CurrentWorkflowMutation, request.Encoding)
+		serializedCurrentWorkflowMutation, err = m.SerializeWorkflowMutation(request.CurrentWorkflowMutation, encoding)
 		if err != nil {
 			return err
 		}
 	}
 	var serializedNewWorkflowMutation *InternalWorkflowSnapshot
 	if request.NewWorkflowSnapshot != nil {
-		serializedNewWorkflowMutation, err = m.SerializeWorkflowSnapshot(request.NewWorkflowSnapshot, request.Encoding)
+		serializedNewWorkflowMutation, err = m.SerializeWorkflowSnapshot(request.NewWorkflowSnapshot, encoding)
 		if err != nil {
 			return err
 		}
@@ -544,22 +859,257 @@ func (m *executionManagerImpl) ConflictResolveWorkflowExecution(
 		NewWorkflowSnapshot: serializedNewWorkflowMutation,
 
 		CurrentWorkflowMutation: serializedCurrentWorkflowMutation,
+
+		DryRun: request.DryRun,
 	}
 	return m.persistence.ConflictResolveWorkflowExecution(ctx, newRequest)
 }
 
+// validateConflictResolveWorkflowExecutionRequest checks the parts of the request which can be validated
+// without reading the database, it is shared by the dry run and the live path
+func validateConflictResolveWorkflowExecutionRequest(
+	request *ConflictResolveWorkflowExecutionRequest,
+) error {
+
+	if request.ResetWorkflowSnapshot.ExecutionInfo == nil {
+		return &InvalidPersistenceRequestError{
+			Msg: "ConflictResolveWorkflowExecution: reset workflow is missing execution info",
+		}
+	}
+	if err := validateVersionHistories("reset workflow", request.ResetWorkflowSnapshot.VersionHistories); err != nil {
+		return err
+	}
+	if err := validateChecksum("reset workflow", request.ResetWorkflowSnapshot.Checksum); err != nil {
+		return err
+	}
+	if err := validateWorkflowSnapshot(&request.ResetWorkflowSnapshot); err != nil {
+		return err
+	}
+	if err := validateWorkflowSnapshotSize(
+		"ConflictResolveWorkflowExecution",
+		&request.ResetWorkflowSnapshot,
+		request.WorkflowSizeLimits,
+	); err != nil {
+		return err
+	}
+
+	if request.NewWorkflowSnapshot != nil {
+		if request.NewWorkflowSnapshot.ExecutionInfo == nil {
+			return &InvalidPersistenceRequestError{
+				Msg: "ConflictResolveWorkflowExecution: new workflow is missing execution info",
+			}
+		}
+		if err := validateVersionHistories("new workflow", request.NewWorkflowSnapshot.VersionHistories); err != nil {
+			return err
+		}
+		if err := validateChecksum("new workflow", request.NewWorkflowSnapshot.Checksum); err != nil {
+			return err
+		}
+		if err := validateWorkflowSnapshot(request.NewWorkflowSnapshot); err != nil {
+			return err
+		}
+		if err := validateWorkflowSnapshotSize(
+			"ConflictResolveWorkflowExecution",
+			request.NewWorkflowSnapshot,
+			request.WorkflowSizeLimits,
+		); err != nil {
+			return err
+		}
+	}
+
+	if request.CurrentWorkflowMutation != nil {
+		if request.CurrentWorkflowMutation.ExecutionInfo == nil {
+			return &InvalidPersistenceRequestError{
+				Msg: "ConflictResolveWorkflowExecution: current workflow is missing execution info",
+			}
+		}
+		if err := validateChecksum("current workflow", request.CurrentWorkflowMutation.Checksum); err != nil {
+			return err
+		}
+		if err := validateWorkflowMutation(request.CurrentWorkflowMutation); err != nil {
+			return err
+		}
+		if err := validateWorkflowMutationSize(
+			"ConflictResolveWorkflowExecution",
+			request.CurrentWorkflowMutation,
+			request.WorkflowSizeLimits,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateVersionHistories checks that the current version history is not empty and that the items
+// of every version history have strictly increasing event IDs and versions, nil version histories are
+// allowed for workflows which are not using them
+func validateVersionHistories(
+	workflow string,
+	versionHistories *VersionHistories,
+) error {
+
+	if versionHistories == nil {
+		return nil
+	}
+
+	currentVersionHistory, err := versionHistories.GetCurrentVersionHistory()
+	if err != nil {
+		return &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("ConflictResolveWorkflowExecution: %v has invalid version histories: %v", workflow, err),
+		}
+	}
+	if currentVersionHistory.IsEmpty() {
+		return &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("ConflictResolveWorkflowExecution: %v current version history is empty", workflow),
+		}
+	}
+
+	for historyIndex, versionHistory := range versionHistories.Histories {
+		for itemIndex, item := range versionHistory.Items {
+			if item == nil {
+				return &InvalidPersistenceRequestError{
+					Msg: fmt.Sprintf("ConflictResolveWorkflowExecution: %v version history %v has a nil item at index %v",
+						workflow, historyIndex, itemIndex),
+				}
+			}
+			if itemIndex == 0 {
+				continue
+			}
+			prevItem := versionHistory.Items[itemIndex-1]
+			if item.EventID <= prevItem.EventID || item.Version <= prevItem.Version {
+				return &InvalidPersistenceRequestError{
+					Msg: fmt.Sprintf("ConflictResolveWorkflowExecution: %v version history %v is not continuous, item %v (%v, %v) follows (%v, %v)",
+						workflow, historyIndex, itemIndex, item.EventID, item.Version, prevItem.EventID, prevItem.Version),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateChecksum checks that a checksum is either absent or complete, a partially populated
+// checksum would fail verification on every subsequent load
+func validateChecksum(
+	workflow string,
+	cs checksum.Checksum,
+) error {
+
+	if len(cs.Value) == 0 && cs.Flavor == checksum.FlavorUnknown {
+		return nil
+	}
+	if len(cs.Value) == 0 || !cs.Flavor.IsValid() {
+		return &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("ConflictResolveWorkflowExecution: %v has an incomplete checksum, flavor: %v, value length: %v",
+				workflow, cs.Flavor, len(cs.Value)),
+		}
+	}
+	return nil
+}
+
+// validateWorkflowSnapshot checks the referential integrity of the mutable state written by the snapshot
+func validateWorkflowSnapshot(
+	snapshot *WorkflowSnapshot,
+) error {
+
+	state, err := newMutableStateForValidation(
+		snapshot.ExecutionInfo,
+		snapshot.ChildExecutionInfos,
+		snapshot.SignalRequestedIDs,
+	)
+	if err != nil {
+		return err
+	}
+	return state.Validate()
+}
+
+// validateWorkflowMutation checks the referential integrity of the rows upserted by the mutation,
+// rows which are not part of the mutation are not known here and so are not checked
+func validateWorkflowMutation(
+	mutation *WorkflowMutation,
+) error {
+
+	state, err := newMutableStateForValidation(
+		mutation.ExecutionInfo,
+		mutation.UpsertChildExecutionInfos,
+		mutation.UpsertSignalRequestedIDs,
+	)
+	if err != nil {
+		return err
+	}
+	return state.Validate()
+}
+
+// newMutableStateForValidation builds the mutable state holding the given rows, the rows are keyed the same
+// way as in the database so a child execution or signal request given more than once is rejected
+func newMutableStateForValidation(
+	executionInfo *WorkflowExecutionInfo,
+	childExecutionInfos []*ChildExecutionInfo,
+	signalRequestedIDs []string,
+) (*WorkflowMutableState, error) {
+
+	state := &WorkflowMutableState{
+		ExecutionInfo:       executionInfo,
+		ChildExecutionInfos: make(map[int64]*ChildExecutionInfo, len(childExecutionInfos)),
+		SignalRequestedIDs:  make(map[string]struct{}, len(signalRequestedIDs)),
+	}
+	for _, childInfo := range childExecutionInfos {
+		if childInfo == nil {
+			return nil, &InvalidPersistenceRequestError{Msg: "child execution info is not set"}
+		}
+		if _, ok := state.ChildExecutionInfos[childInfo.InitiatedID]; ok {
+			return nil, &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("child execution initiated ID: %v is not unique", childInfo.InitiatedID),
+			}
+		}
+		state.ChildExecutionInfos[childInfo.InitiatedID] = childInfo
+	}
+	for _, requestID := range signalRequestedIDs {
+		if _, ok := state.SignalRequestedIDs[requestID]; ok {
+			return nil, &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("signal requested ID: %v is not unique", requestID),
+			}
+		}
+		state.SignalRequestedIDs[requestID] = struct{}{}
+	}
+	return state, nil
+}
+
 func (m *executionManagerImpl) ResetWorkflowExecution(
 	ctx context.Context,
 	request *ResetWorkflowExecutionRequest,
 ) error {
 
-	serializedNewWorkflowSnapshot, err := m.SerializeWorkflowSnapshot(&request.NewWorkflowSnapshot, request.Encoding)
+	if err := validateWorkflowSnapshot(&request.NewWorkflowSnapshot); err != nil {
+		return err
+	}
+	if err := validateWorkflowSnapshotSize(
+		"ResetWorkflowExecution",
+		&request.NewWorkflowSnapshot,
+		request.WorkflowSizeLimits,
+	); err != nil {
+		return err
+	}
+	if request.CurrentWorkflowMutation != nil {
+		if err := validateWorkflowMutation(request.CurrentWorkflowMutation); err != nil {
+			return err
+		}
+		if err := validateWorkflowMutationSize(
+			"ResetWorkflowExecution",
+			request.CurrentWorkflowMutation,
+			request.WorkflowSizeLimits,
+		); err != nil {
+			return err
+		}
+	}
+
+	encoding := ResolveEncoding(request.Encoding)
+	serializedNewWorkflowSnapshot, err := m.SerializeWorkflowSnapshot(&request.NewWorkflowSnapshot, encoding)
 	if err != nil {
 		return err
 	}
 	var serializedUpdateWorkflowSnapshot *InternalWorkflowMutation
 	if request.CurrentWorkflowMutation != nil {
-		serializedUpdateWorkflowSnapshot, err = m.SerializeWorkflowMutation(request.CurrentWorkflowMutation, request.Encoding)
+		serializedUpdateWorkflowSnapshot, err = m.SerializeWorkflowMutation(request.CurrentWorkflowMutation, encoding)
 		if err != nil {
 			return err
 		}
@@ -586,7 +1136,15 @@ func (m *executionManagerImpl) CreateWorkflowExecution(
 	request *CreateWorkflowExecutionRequest,
 ) (*CreateWorkflowExecutionResponse, error) {
 
-	encoding := common.EncodingTypeThriftRW
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	if err := validateWorkflowSnapshot(&request.NewWorkflowSnapshot); err != nil {
+		return nil, err
+	}
+
+	// the request does not carry an encoding, so the new run is always written with the default one
+	encoding := ResolveEncoding(common.EncodingTypeEmpty)
 
 	serializedNewWorkflowSnapshot, err := m.SerializeWorkflowSnapshot(&request.NewWorkflowSnapshot, encoding)
 	if err != nil {
@@ -775,18 +1333,89 @@ func (m *executionManagerImpl) DeleteCurrentWorkflowExecution(
 	return m.persistence.DeleteCurrentWorkflowExecution(ctx, request)
 }
 
+func (m *executionManagerImpl) DeleteWorkflowExecutions(
+	ctx context.Context,
+	request *DeleteWorkflowExecutionsRequest,
+) (*DeleteWorkflowExecutionsResponse, error) {
+	runIDs := request.RunIDs
+	errs := runConcurrently(ctx, len(runIDs), deleteWorkflowExecutionsConcurrency, func(i int) error {
+		return m.deleteWorkflowExecution(ctx, request.DomainID, request.WorkflowID, runIDs[i])
+	})
+
+	result := &DeleteWorkflowExecutionsResponse{
+		Errors: make(map[string]error),
+	}
+	for i, runID := range runIDs {
+		if errs[i] != nil {
+			result.Errors[runID] = errs[i]
+		}
+	}
+	return result, nil
+}
+
+// deleteWorkflowExecution removes the current record first, so it never points to a deleted run,
+// the current record is only removed if it points to the run
+func (m *executionManagerImpl) deleteWorkflowExecution(
+	ctx context.Context,
+	domainID string,
+	workflowID string,
+	runID string,
+) error {
+	if err := m.persistence.DeleteCurrentWorkflowExecution(ctx, &DeleteCurrentWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowID,
+		RunID:      runID,
+	}); err != nil {
+		return err
+	}
+	return m.persistence.DeleteWorkflowExecution(ctx, &DeleteWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowID,
+		RunID:      runID,
+	})
+}
+
 func (m *executionManagerImpl) GetCurrentExecution(
 	ctx context.Context,
 	request *GetCurrentExecutionRequest,
 ) (*GetCurrentExecutionResponse, error) {
+	if request.Consistency < ReadConsistencyDefault || request.Consistency > ReadConsistencySerial {
+		return nil, &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("GetCurrentExecution: unknown read consistency: %v", request.Consistency),
+		}
+	}
 	return m.persistence.GetCurrentExecution(ctx, request)
 }
 
+func (m *executionManagerImpl) GetCurrentRunID(
+	ctx context.Context,
+	request *GetCurrentRunIDRequest,
+) (*GetCurrentRunIDResponse, error) {
+	return m.persistence.GetCurrentRunID(ctx, request)
+}
+
 func (m *executionManagerImpl) ListCurrentExecutions(
 	ctx context.Context,
 	request *ListCurrentExecutionsRequest,
 ) (*ListCurrentExecutionsResponse, error) {
-	return m.persistence.ListCurrentExecutions(ctx, request)
+	response, err := m.persistence.ListCurrentExecutions(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if request.DomainID != "" {
+		// filtering happens after the page is read, so the page token returned by the store is still valid
+		executions := make([]*CurrentWorkflowExecution, 0, len(response.Executions))
+		for _, e := range response.Executions {
+			if e.DomainID == request.DomainID {
+				executions = append(executions, e)
+			}
+		}
+		response = &ListCurrentExecutionsResponse{
+			Executions: executions,
+			PageToken:  response.PageToken,
+		}
+	}
+	return response, nil
 }
 
 func (m *executionManagerImpl) IsWorkflowExecutionExists(
@@ -796,6 +1425,72 @@ func (m *executionManagerImpl) IsWorkflowExecutionExists(
 	return m.persistence.IsWorkflowExecutionExists(ctx, request)
 }
 
+// WorkflowExists checks if any run of the workflow exists, every workflow with a run has a current record,
+// so only the run ID of the current record is read
+func (m *executionManagerImpl) WorkflowExists(
+	ctx context.Context,
+	request *WorkflowExistsRequest,
+) (*WorkflowExistsResponse, error) {
+	_, err := m.persistence.GetCurrentRunID(ctx, &GetCurrentRunIDRequest{
+		DomainID:   request.DomainID,
+		WorkflowID: request.WorkflowID,
+	})
+	if err != nil {
+		if IsNotExistsError(err) {
+			return &WorkflowExistsResponse{Exists: false}, nil
+		}
+		return nil, err
+	}
+	return &WorkflowExistsResponse{Exists: true}, nil
+}
+
+// CheckCurrentExecution detects a current record pointing to a run whose concrete execution was deleted,
+// such a dangling record is deleted under the shard range ID if a repair is requested
+func (m *executionManagerImpl) CheckCurrentExecution(
+	ctx context.Context,
+	request *CheckCurrentExecutionRequest,
+) (*CheckCurrentExecutionResponse, error) {
+	if request.Repair && request.RangeID == 0 {
+		return nil, &InvalidPersistenceRequestError{
+			Msg: "CheckCurrentExecution: RangeID is required to repair the current record",
+		}
+	}
+	currentRun, err := m.persistence.GetCurrentRunID(ctx, &GetCurrentRunIDRequest{
+		DomainID:   request.DomainID,
+		WorkflowID: request.WorkflowID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	exists, err := m.persistence.IsWorkflowExecutionExists(ctx, &IsWorkflowExecutionExistsRequest{
+		DomainID:   request.DomainID,
+		WorkflowID: request.WorkflowID,
+		RunID:      currentRun.RunID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	response := &CheckCurrentExecutionResponse{
+		CurrentRunID: currentRun.RunID,
+		Exists:       exists.Exists,
+	}
+	if exists.Exists || !request.Repair {
+		return response, nil
+	}
+	// the delete is conditioned on the run ID as well, so a current record which moved on to a new run
+	// since it was read is left alone
+	if err := m.persistence.DeleteCurrentWorkflowExecution(ctx, &DeleteCurrentWorkflowExecutionRequest{
+		DomainID:   request.DomainID,
+		WorkflowID: request.WorkflowID,
+		RunID:      currentRun.RunID,
+		RangeID:    request.RangeID,
+	}); err != nil {
+		return nil, err
+	}
+	response.Repaired = true
+	return response, nil
+}
+
 func (m *executionManagerImpl) ListConcreteExecutions(
 	ctx context.Context,
 	request *ListConcreteExecutionsRequest,
@@ -804,11 +1499,25 @@ func (m *executionManagerImpl) ListConcreteExecutions(
 	if err != nil {
 		return nil, err
 	}
+	executions := response.Executions
+	if len(request.StateFilter) > 0 || !request.SnapshotTime.IsZero() {
+		// filtering happens after the page is read, so the page token returned by the store is still valid
+		executions = make([]*InternalListConcreteExecutionsEntity, 0, len(response.Executions))
+		for _, e := range response.Executions {
+			if len(request.StateFilter) > 0 && !containsInt(request.StateFilter, e.ExecutionInfo.State) {
+				continue
+			}
+			if !request.SnapshotTime.IsZero() && e.ExecutionInfo.StartTimestamp.After(request.SnapshotTime) {
+				continue
+			}
+			executions = append(executions, e)
+		}
+	}
 	newResponse := &ListConcreteExecutionsResponse{
-		Executions: make([]*ListConcreteExecutionsEntity, len(response.Executions), len(response.Executions)),
+		Executions: make([]*ListConcreteExecutionsEntity, len(executions), len(executions)),
 		PageToken:  response.NextPageToken,
 	}
-	for i, e := range response.Executions {
+	for i, e := range executions {
 		info, _, err := m.DeserializeExecutionInfo(e.ExecutionInfo)
 		if err != nil {
 			return nil, err
@@ -825,12 +1534,38 @@ func (m *executionManagerImpl) ListConcreteExecutions(
 	return newResponse, nil
 }
 
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *executionManagerImpl) CountWorkflowExecutions(
+	ctx context.Context,
+	request *CountExecutionsRequest,
+) (*CountExecutionsResponse, error) {
+	return m.persistence.CountWorkflowExecutions(ctx, request)
+}
+
 // Transfer task related methods
 func (m *executionManagerImpl) GetTransferTasks(
 	ctx context.Context,
 	request *GetTransferTasksRequest,
 ) (*GetTransferTasksResponse, error) {
-	return m.persistence.GetTransferTasks(ctx, request)
+	response, err := m.persistence.GetTransferTasks(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if request.GroupByType {
+		response.TasksByType = make(map[int][]*TransferTaskInfo)
+		for _, task := range response.Tasks {
+			response.TasksByType[task.TaskType] = append(response.TasksByType[task.TaskType], task)
+		}
+	}
+	return response, nil
 }
 
 func (m *executionManagerImpl) CompleteTransferTask(
@@ -844,9 +1579,46 @@ func (m *executionManagerImpl) RangeCompleteTransferTask(
 	ctx context.Context,
 	request *RangeCompleteTransferTaskRequest,
 ) error {
+	if err := request.Validate(); err != nil {
+		return err
+	}
 	return m.persistence.RangeCompleteTransferTask(ctx, request)
 }
 
+func (m *executionManagerImpl) CompleteTransferTasks(
+	ctx context.Context,
+	request *CompleteTransferTasksRequest,
+) error {
+	if len(request.TaskIDs) == 0 {
+		return nil
+	}
+	return m.persistence.CompleteTransferTasks(ctx, request)
+}
+
+func (m *executionManagerImpl) GetCrossClusterTasks(
+	ctx context.Context,
+	request *GetCrossClusterTasksRequest,
+) (*GetCrossClusterTasksResponse, error) {
+	return m.persistence.GetCrossClusterTasks(ctx, request)
+}
+
+func (m *executionManagerImpl) CompleteCrossClusterTask(
+	ctx context.Context,
+	request *CompleteCrossClusterTaskRequest,
+) error {
+	return m.persistence.CompleteCrossClusterTask(ctx, request)
+}
+
+func (m *executionManagerImpl) RangeCompleteCrossClusterTask(
+	ctx context.Context,
+	request *RangeCompleteCrossClusterTaskRequest,
+) error {
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	return m.persistence.RangeCompleteCrossClusterTask(ctx, request)
+}
+
 // Replication task related methods
 func (m *executionManagerImpl) GetReplicationTasks(
 	ctx context.Context,
@@ -857,8 +1629,44 @@ func (m *executionManagerImpl) GetReplicationTasks(
 		return nil, err
 	}
 
+	tasks := resp.Tasks
+	if request.DomainIDFilter != nil {
+		// filtering happens after the page is read, so the page token returned by the store is still valid
+		tasks = filterReplicationTasks(resp.Tasks, func(task *InternalReplicationTaskInfo) bool {
+			_, ok := request.DomainIDFilter[task.DomainID]
+			return ok
+		})
+	}
+	minTaskID, maxTaskID := replicationTaskIDRange(resp.Tasks)
 	return &GetReplicationTasksResponse{
-		Tasks:         m.fromInternalReplicationTaskInfos(resp.Tasks),
+		Tasks:         m.fromInternalReplicationTaskInfos(tasks),
+		NextPageToken: resp.NextPageToken,
+		MinTaskID:     minTaskID,
+		MaxTaskID:     maxTaskID,
+	}, nil
+}
+
+func (m *executionManagerImpl) GetFailoverMarkerTasks(
+	ctx context.Context,
+	request *GetFailoverMarkerTasksRequest,
+) (*GetFailoverMarkerTasksResponse, error) {
+	if request.DomainID == "" {
+		return nil, &InvalidPersistenceRequestError{
+			Msg: "GetFailoverMarkerTasks requires a domain ID",
+		}
+	}
+
+	resp, err := m.persistence.GetReplicationTasks(ctx, &request.GetReplicationTasksRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	// filtering happens after the page is read, so the page token returned by the store is still valid
+	tasks := filterReplicationTasks(resp.Tasks, func(task *InternalReplicationTaskInfo) bool {
+		return task.TaskType == ReplicationTaskTypeFailoverMarker && task.DomainID == request.DomainID
+	})
+	return &GetFailoverMarkerTasksResponse{
+		Tasks:         m.fromInternalReplicationTaskInfos(tasks),
 		NextPageToken: resp.NextPageToken,
 	}, nil
 }
@@ -888,6 +1696,23 @@ func (m *executionManagerImpl) PutReplicationTaskToDLQ(
 	return m.persistence.PutReplicationTaskToDLQ(ctx, internalRequest)
 }
 
+func (m *executionManagerImpl) PutReplicationTasksToDLQ(
+	ctx context.Context,
+	request *PutReplicationTasksToDLQRequest,
+) error {
+	if len(request.TaskInfos) == 0 {
+		return nil
+	}
+	internalRequest := &InternalPutReplicationTasksToDLQRequest{
+		SourceClusterName: request.SourceClusterName,
+		TaskInfos:         make([]*InternalReplicationTaskInfo, 0, len(request.TaskInfos)),
+	}
+	for _, taskInfo := range request.TaskInfos {
+		internalRequest.TaskInfos = append(internalRequest.TaskInfos, m.toInternalReplicationTaskInfo(taskInfo))
+	}
+	return m.persistence.PutReplicationTasksToDLQ(ctx, internalRequest)
+}
+
 func (m *executionManagerImpl) GetReplicationTasksFromDLQ(
 	ctx context.Context,
 	request *GetReplicationTasksFromDLQRequest,
@@ -896,9 +1721,28 @@ func (m *executionManagerImpl) GetReplicationTasksFromDLQ(
 	if err != nil {
 		return nil, err
 	}
+
+	tasks := resp.Tasks
+	if request.TaskTypeFilter != nil || request.DomainIDFilter != nil {
+		// filtering happens after the page is read, so the page token returned by the store is still valid
+		tasks = filterReplicationTasks(resp.Tasks, func(task *InternalReplicationTaskInfo) bool {
+			if request.TaskTypeFilter != nil && task.TaskType != *request.TaskTypeFilter {
+				return false
+			}
+			if request.DomainIDFilter != nil {
+				if _, ok := request.DomainIDFilter[task.DomainID]; !ok {
+					return false
+				}
+			}
+			return true
+		})
+	}
+	minTaskID, maxTaskID := replicationTaskIDRange(resp.Tasks)
 	return &GetReplicationTasksFromDLQResponse{
-		Tasks:         m.fromInternalReplicationTaskInfos(resp.Tasks),
+		Tasks:         m.fromInternalReplicationTaskInfos(tasks),
 		NextPageToken: resp.NextPageToken,
+		MinTaskID:     minTaskID,
+		MaxTaskID:     maxTaskID,
 	}, nil
 }
 
@@ -923,6 +1767,16 @@ func (m *executionManagerImpl) RangeDeleteReplicationTaskFromDLQ(
 	return m.persistence.RangeDeleteReplicationTaskFromDLQ(ctx, request)
 }
 
+func (m *executionManagerImpl) DeleteReplicationTasksFromDLQ(
+	ctx context.Context,
+	request *DeleteReplicationTasksFromDLQRequest,
+) error {
+	if len(request.TaskIDs) == 0 {
+		return nil
+	}
+	return m.persistence.DeleteReplicationTasksFromDLQ(ctx, request)
+}
+
 func (m *executionManagerImpl) CreateFailoverMarkerTasks(
 	ctx context.Context,
 	request *CreateFailoverMarkersRequest,
@@ -935,7 +1789,39 @@ func (m *executionManagerImpl) GetTimerIndexTasks(
 	ctx context.Context,
 	request *GetTimerIndexTasksRequest,
 ) (*GetTimerIndexTasksResponse, error) {
-	return m.persistence.GetTimerIndexTasks(ctx, request)
+	response, err := m.persistence.GetTimerIndexTasks(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	timers := response.Timers
+	if len(request.TaskTypeFilter) != 0 {
+		// like the task ID range of replication tasks, the visibility timestamp covers the whole page read from the store
+		timers = make([]*TimerTaskInfo, 0, len(response.Timers))
+		for _, timer := range response.Timers {
+			if containsInt(request.TaskTypeFilter, timer.TaskType) {
+				timers = append(timers, timer)
+			}
+		}
+	}
+	return &GetTimerIndexTasksResponse{
+		Timers:                 timers,
+		NextPageToken:          response.NextPageToken,
+		MinVisibilityTimestamp: minTimerVisibilityTimestamp(response.Timers),
+	}, nil
+}
+
+// minTimerVisibilityTimestamp returns the earliest visibility timestamp of the timers, or the zero time if there are none
+func minTimerVisibilityTimestamp(
+	timers []*TimerTaskInfo,
+) time.Time {
+	var minTimestamp time.Time
+	for i, timer := range timers {
+		if i == 0 || timer.VisibilityTimestamp.Before(minTimestamp) {
+			minTimestamp = timer.VisibilityTimestamp
+		}
+	}
+	return minTimestamp
 }
 
 func (m *executionManagerImpl) CompleteTimerTask(
@@ -949,13 +1835,28 @@ func (m *executionManagerImpl) RangeCompleteTimerTask(
 	ctx context.Context,
 	request *RangeCompleteTimerTaskRequest,
 ) error {
+	if err := request.Validate(); err != nil {
+		return err
+	}
 	return m.persistence.RangeCompleteTimerTask(ctx, request)
 }
 
+func (m *executionManagerImpl) CompleteTimerTasksForDomain(
+	ctx context.Context,
+	request *CompleteTimerTasksForDomainRequest,
+) (int, error) {
+	return m.persistence.CompleteTimerTasksForDomain(ctx, request)
+}
+
 func (m *executionManagerImpl) Close() {
 	m.persistence.Close()
 }
 
+// CloseWithContext closes the execution store within the deadline of ctx
+func (m *executionManagerImpl) CloseWithContext(ctx context.Context) error {
+	return CloseWithContext(ctx, m.persistence)
+}
+
 func (m *executionManagerImpl) fromInternalReplicationTaskInfos(internalInfos []*InternalReplicationTaskInfo) []*ReplicationTaskInfo {
 	if internalInfos == nil {
 		return nil
@@ -987,6 +1888,38 @@ func (m *executionManagerImpl) fromInternalReplicationTaskInfo(internalInfo *Int
 	}
 }
 
+func filterReplicationTasks(
+	tasks []*InternalReplicationTaskInfo,
+	keep func(*InternalReplicationTaskInfo) bool,
+) []*InternalReplicationTaskInfo {
+	filtered := make([]*InternalReplicationTaskInfo, 0, len(tasks))
+	for _, task := range tasks {
+		if keep(task) {
+			filtered = append(filtered, task)
+		}
+	}
+	return filtered
+}
+
+// replicationTaskIDRange returns the smallest and the largest task ID of the tasks, or 0 for both if there are none
+func replicationTaskIDRange(
+	tasks []*InternalReplicationTaskInfo,
+) (int64, int64) {
+	if len(tasks) == 0 {
+		return 0, 0
+	}
+	minTaskID, maxTaskID := tasks[0].TaskID, tasks[0].TaskID
+	for _, task := range tasks[1:] {
+		if task.TaskID < minTaskID {
+			minTaskID = task.TaskID
+		}
+		if task.TaskID > maxTaskID {
+			maxTaskID = task.TaskID
+		}
+	}
+	return minTaskID, maxTaskID
+}
+
 func (m *executionManagerImpl) toInternalReplicationTaskInfos(infos []*ReplicationTaskInfo) []*InternalReplicationTaskInfo {
 	if infos == nil {
 		return nil
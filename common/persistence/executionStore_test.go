@@ -0,0 +1,1042 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/checksum"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/types"
+)
+
+func newTestExecutionManager(t *testing.T) (*MockExecutionStore, ExecutionManager) {
+	controller := gomock.NewController(t)
+	t.Cleanup(controller.Finish)
+	store := NewMockExecutionStore(controller)
+	return store, NewExecutionManagerImpl(store, loggerimpl.NewNopLogger())
+}
+
+func TestListConcreteExecutionsWithStateFilter(t *testing.T) {
+	newEntity := func(workflowID string, state int) *InternalListConcreteExecutionsEntity {
+		return &InternalListConcreteExecutionsEntity{
+			ExecutionInfo: &InternalWorkflowExecutionInfo{WorkflowID: workflowID, State: state},
+		}
+	}
+	pages := []*InternalListConcreteExecutionsResponse{
+		{
+			Executions:    []*InternalListConcreteExecutionsEntity{newEntity("wf-1", WorkflowStateRunning), newEntity("wf-2", WorkflowStateCorrupted)},
+			NextPageToken: []byte("page-2"),
+		},
+		{
+			Executions:    []*InternalListConcreteExecutionsEntity{newEntity("wf-3", WorkflowStateCompleted), newEntity("wf-4", WorkflowStateCreated)},
+			NextPageToken: []byte("page-3"),
+		},
+		{
+			Executions: []*InternalListConcreteExecutionsEntity{newEntity("wf-5", WorkflowStateZombie), newEntity("wf-6", WorkflowStateRunning)},
+		},
+	}
+	store, mgr := newTestExecutionManager(t)
+
+	listAll := func(stateFilter []int) []string {
+		var token []byte
+		for _, page := range pages {
+			store.EXPECT().ListConcreteExecutions(gomock.Any(), &ListConcreteExecutionsRequest{
+				PageSize:    2,
+				PageToken:   token,
+				StateFilter: stateFilter,
+			}).Return(page, nil).Times(1)
+			token = page.NextPageToken
+		}
+
+		var workflowIDs []string
+		request := &ListConcreteExecutionsRequest{PageSize: 2, StateFilter: stateFilter}
+		for {
+			resp, err := mgr.ListConcreteExecutions(context.Background(), request)
+			require.NoError(t, err)
+			for _, e := range resp.Executions {
+				workflowIDs = append(workflowIDs, e.ExecutionInfo.WorkflowID)
+			}
+			if len(resp.PageToken) == 0 {
+				return workflowIDs
+			}
+			request.PageToken = resp.PageToken
+		}
+	}
+
+	assert.Equal(t, []string{"wf-1", "wf-2", "wf-3", "wf-4", "wf-5", "wf-6"}, listAll(nil))
+	// the second page has no matching execution, but paging must continue past it
+	assert.Equal(t, []string{"wf-2", "wf-5"}, listAll([]int{WorkflowStateCorrupted, WorkflowStateZombie}))
+	assert.Empty(t, listAll([]int{WorkflowStateVoid}))
+}
+
+func TestListConcreteExecutionsWithSnapshotTime(t *testing.T) {
+	snapshotTime := time.Unix(1000, 0)
+	newEntity := func(workflowID string, state int, startTimestamp time.Time) *InternalListConcreteExecutionsEntity {
+		return &InternalListConcreteExecutionsEntity{
+			ExecutionInfo: &InternalWorkflowExecutionInfo{WorkflowID: workflowID, State: state, StartTimestamp: startTimestamp},
+		}
+	}
+	store, mgr := newTestExecutionManager(t)
+	store.EXPECT().ListConcreteExecutions(gomock.Any(), gomock.Any()).Return(&InternalListConcreteExecutionsResponse{
+		Executions: []*InternalListConcreteExecutionsEntity{
+			newEntity("wf-1", WorkflowStateRunning, snapshotTime.Add(-time.Minute)),
+			newEntity("wf-2", WorkflowStateRunning, snapshotTime.Add(time.Minute)),
+			newEntity("wf-3", WorkflowStateCompleted, snapshotTime),
+		},
+	}, nil).Times(3)
+
+	resp, err := mgr.ListConcreteExecutions(context.Background(), &ListConcreteExecutionsRequest{PageSize: 3})
+	require.NoError(t, err)
+	assert.Len(t, resp.Executions, 3)
+
+	resp, err = mgr.ListConcreteExecutions(context.Background(), &ListConcreteExecutionsRequest{
+		PageSize:     3,
+		SnapshotTime: snapshotTime,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Executions, 2)
+	assert.Equal(t, "wf-1", resp.Executions[0].ExecutionInfo.WorkflowID)
+	assert.Equal(t, "wf-3", resp.Executions[1].ExecutionInfo.WorkflowID)
+
+	resp, err = mgr.ListConcreteExecutions(context.Background(), &ListConcreteExecutionsRequest{
+		PageSize:     3,
+		StateFilter:  []int{WorkflowStateRunning},
+		SnapshotTime: snapshotTime,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Executions, 1)
+	assert.Equal(t, "wf-1", resp.Executions[0].ExecutionInfo.WorkflowID)
+}
+
+func TestListCurrentExecutionsWithDomainID(t *testing.T) {
+	newExecution := func(domainID, workflowID string) *CurrentWorkflowExecution {
+		return &CurrentWorkflowExecution{DomainID: domainID, WorkflowID: workflowID}
+	}
+	pages := []*ListCurrentExecutionsResponse{
+		{
+			Executions: []*CurrentWorkflowExecution{newExecution("domain-1", "wf-1"), newExecution("domain-2", "wf-2")},
+			PageToken:  []byte("page-2"),
+		},
+		{
+			Executions: []*CurrentWorkflowExecution{newExecution("domain-2", "wf-3"), newExecution("domain-2", "wf-4")},
+			PageToken:  []byte("page-3"),
+		},
+		{
+			Executions: []*CurrentWorkflowExecution{newExecution("domain-3", "wf-5"), newExecution("domain-1", "wf-6")},
+		},
+	}
+	store, mgr := newTestExecutionManager(t)
+
+	listAll := func(domainID string) []string {
+		var token []byte
+		for _, page := range pages {
+			store.EXPECT().ListCurrentExecutions(gomock.Any(), &ListCurrentExecutionsRequest{
+				PageSize:  2,
+				PageToken: token,
+				DomainID:  domainID,
+			}).Return(page, nil).Times(1)
+			token = page.PageToken
+		}
+
+		var workflowIDs []string
+		request := &ListCurrentExecutionsRequest{PageSize: 2, DomainID: domainID}
+		for {
+			resp, err := mgr.ListCurrentExecutions(context.Background(), request)
+			require.NoError(t, err)
+			for _, e := range resp.Executions {
+				workflowIDs = append(workflowIDs, e.WorkflowID)
+			}
+			if len(resp.PageToken) == 0 {
+				return workflowIDs
+			}
+			request.PageToken = resp.PageToken
+		}
+	}
+
+	assert.Equal(t, []string{"wf-1", "wf-2", "wf-3", "wf-4", "wf-5", "wf-6"}, listAll(""))
+	// the second page has no execution of domain-1, but paging must continue past it
+	assert.Equal(t, []string{"wf-1", "wf-6"}, listAll("domain-1"))
+	assert.Empty(t, listAll("unknown-domain"))
+}
+
+func TestGetWorkflowExecutionVerifyChecksum(t *testing.T) {
+	execution := types.WorkflowExecution{WorkflowID: "wf", RunID: "run"}
+	newState := func(csum checksum.Checksum) *InternalGetWorkflowExecutionResponse {
+		return &InternalGetWorkflowExecutionResponse{
+			State: &InternalWorkflowMutableState{
+				ExecutionInfo: &InternalWorkflowExecutionInfo{
+					WorkflowID:  execution.WorkflowID,
+					RunID:       execution.RunID,
+					State:       WorkflowStateRunning,
+					NextEventID: 10,
+				},
+				TimerInfos:  map[string]*TimerInfo{"timer": {TimerID: "timer", StartedID: 5}},
+				SignalInfos: map[int64]*SignalInfo{7: {InitiatedID: 7}},
+				Checksum:    csum,
+			},
+		}
+	}
+	csum, err := GenerateMutableStateChecksum(&WorkflowMutableState{
+		ExecutionInfo: &WorkflowExecutionInfo{
+			WorkflowID:  execution.WorkflowID,
+			RunID:       execution.RunID,
+			State:       WorkflowStateRunning,
+			NextEventID: 10,
+		},
+		TimerInfos:  map[string]*TimerInfo{"timer": {TimerID: "timer", StartedID: 5}},
+		SignalInfos: map[int64]*SignalInfo{7: {InitiatedID: 7}},
+	})
+	require.NoError(t, err)
+	corrupted := checksum.Checksum{
+		Version: csum.Version,
+		Flavor:  csum.Flavor,
+		Value:   []byte{0, 1, 2, 3},
+	}
+	store, manager := newTestExecutionManager(t)
+	request := &GetWorkflowExecutionRequest{DomainID: "domain", Execution: execution, VerifyChecksum: true}
+
+	store.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).Return(newState(csum), nil).Times(1)
+	resp, err := manager.GetWorkflowExecution(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, csum, resp.State.Checksum)
+
+	// mutable state without checksum is not verified
+	store.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).Return(newState(checksum.Checksum{}), nil).Times(1)
+	_, err = manager.GetWorkflowExecution(context.Background(), request)
+	require.NoError(t, err)
+
+	store.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).Return(newState(corrupted), nil).Times(1)
+	_, err = manager.GetWorkflowExecution(context.Background(), request)
+	require.IsType(t, &WorkflowExecutionCorruptedError{}, err)
+	corruptedErr := err.(*WorkflowExecutionCorruptedError)
+	assert.Equal(t, "wf", corruptedErr.WorkflowID)
+	assert.Equal(t, "run", corruptedErr.RunID)
+	assert.Equal(t, checksum.FlavorIEEECRC32OverThriftBinary, corruptedErr.ChecksumFlavor)
+	assert.Equal(t, MutableStateChecksumPayloadV1, corruptedErr.ChecksumVersion)
+
+	// verification is skipped by default
+	request.VerifyChecksum = false
+	store.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).Return(newState(corrupted), nil).Times(1)
+	_, err = manager.GetWorkflowExecution(context.Background(), request)
+	require.NoError(t, err)
+}
+
+func TestGetWorkflowExecutionExcludeColumnsWithChecksum(t *testing.T) {
+	_, manager := newTestExecutionManager(t)
+
+	// a partially loaded mutable state can not be verified against its checksum
+	for _, request := range []*GetWorkflowExecutionRequest{
+		{ExcludeBufferedEvents: true, VerifyChecksum: true},
+		{ExcludeActivityInfos: true, VerifyChecksum: true},
+	} {
+		request.DomainID = "domain"
+		request.Execution = types.WorkflowExecution{WorkflowID: "wf", RunID: "run"}
+		_, err := manager.GetWorkflowExecution(context.Background(), request)
+		assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+	}
+}
+
+func TestGetWorkflowExecutionFields(t *testing.T) {
+	execution := types.WorkflowExecution{WorkflowID: "wf", RunID: "run"}
+	store, manager := newTestExecutionManager(t)
+
+	request := &GetWorkflowExecutionRequest{
+		DomainID:  "domain",
+		Execution: execution,
+		Fields:    []MutableStateField{MutableStateFieldChildExecutionInfos},
+	}
+	store.EXPECT().GetWorkflowExecution(gomock.Any(), &InternalGetWorkflowExecutionRequest{
+		DomainID:  "domain",
+		Execution: execution,
+		Fields:    request.Fields,
+	}).Return(&InternalGetWorkflowExecutionResponse{
+		State: &InternalWorkflowMutableState{
+			ExecutionInfo: &InternalWorkflowExecutionInfo{
+				WorkflowID: execution.WorkflowID,
+				RunID:      execution.RunID,
+			},
+			ChildExecutionInfos: map[int64]*InternalChildExecutionInfo{5: {InitiatedID: 5}},
+			TimerInfos:          map[string]*TimerInfo{},
+		},
+	}, nil).Times(1)
+	resp, err := manager.GetWorkflowExecution(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, execution.WorkflowID, resp.State.ExecutionInfo.WorkflowID)
+	assert.Len(t, resp.State.ChildExecutionInfos, 1)
+	assert.Nil(t, resp.State.ActivityInfos)
+	assert.Nil(t, resp.State.TimerInfos)
+	assert.Nil(t, resp.State.RequestCancelInfos)
+	assert.Nil(t, resp.State.SignalInfos)
+	assert.Nil(t, resp.State.SignalRequestedIDs)
+	assert.Nil(t, resp.State.BufferedEvents)
+
+	request.VerifyChecksum = true
+	_, err = manager.GetWorkflowExecution(context.Background(), request)
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+}
+
+func TestInternalGetWorkflowExecutionRequestLoadsField(t *testing.T) {
+	request := &InternalGetWorkflowExecutionRequest{}
+	assert.True(t, request.LoadsAllFields())
+
+	request.ExcludeBufferedEvents = true
+	assert.False(t, request.LoadsField(MutableStateFieldBufferedEvents))
+	assert.True(t, request.LoadsField(MutableStateFieldActivityInfos))
+	assert.False(t, request.LoadsAllFields())
+
+	request = &InternalGetWorkflowExecutionRequest{
+		Fields:               []MutableStateField{MutableStateFieldActivityInfos, MutableStateFieldSignalInfos},
+		ExcludeActivityInfos: true,
+	}
+	assert.False(t, request.LoadsField(MutableStateFieldActivityInfos))
+	assert.True(t, request.LoadsField(MutableStateFieldSignalInfos))
+	assert.False(t, request.LoadsField(MutableStateFieldTimerInfos))
+
+	request = &InternalGetWorkflowExecutionRequest{
+		Fields: []MutableStateField{
+			MutableStateFieldActivityInfos,
+			MutableStateFieldTimerInfos,
+			MutableStateFieldChildExecutionInfos,
+			MutableStateFieldRequestCancelInfos,
+			MutableStateFieldSignalInfos,
+			MutableStateFieldSignalRequestedIDs,
+			MutableStateFieldBufferedEvents,
+		},
+	}
+	assert.True(t, request.LoadsAllFields())
+}
+
+func TestConflictResolveWorkflowExecutionValidation(t *testing.T) {
+	store, manager := newTestExecutionManager(t)
+	newRequest := func() *ConflictResolveWorkflowExecutionRequest {
+		return &ConflictResolveWorkflowExecutionRequest{
+			Mode: ConflictResolveWorkflowModeUpdateCurrent,
+			ResetWorkflowSnapshot: WorkflowSnapshot{
+				ExecutionInfo:  &WorkflowExecutionInfo{DomainID: "domain", WorkflowID: "wf", RunID: "run", DecisionScheduleID: common.EmptyEventID},
+				ExecutionStats: &ExecutionStats{},
+				VersionHistories: NewVersionHistories(NewVersionHistory([]byte("token"), []*VersionHistoryItem{
+					NewVersionHistoryItem(3, 1),
+					NewVersionHistoryItem(7, 2),
+				})),
+			},
+			DryRun: true,
+		}
+	}
+
+	store.EXPECT().ConflictResolveWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *InternalConflictResolveWorkflowExecutionRequest) error {
+			assert.True(t, request.DryRun)
+			return nil
+		},
+	).Times(1)
+	require.NoError(t, manager.ConflictResolveWorkflowExecution(context.Background(), newRequest()))
+
+	// invalid requests do not reach the store
+	request := newRequest()
+	request.ResetWorkflowSnapshot.VersionHistories.Histories[0].Items[1].Version = 1
+	err := manager.ConflictResolveWorkflowExecution(context.Background(), request)
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+
+	// the live path runs the same validation
+	request.DryRun = false
+	err = manager.ConflictResolveWorkflowExecution(context.Background(), request)
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+
+	request = newRequest()
+	request.ResetWorkflowSnapshot.VersionHistories.Histories[0].Items = nil
+	err = manager.ConflictResolveWorkflowExecution(context.Background(), request)
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+
+	request = newRequest()
+	request.ResetWorkflowSnapshot.Checksum = checksum.Checksum{Flavor: checksum.FlavorIEEECRC32OverThriftBinary}
+	err = manager.ConflictResolveWorkflowExecution(context.Background(), request)
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+
+	request = newRequest()
+	request.ResetWorkflowSnapshot.ChildExecutionInfos = []*ChildExecutionInfo{{InitiatedID: 5}, {InitiatedID: 5}}
+	err = manager.ConflictResolveWorkflowExecution(context.Background(), request)
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+
+	request = newRequest()
+	request.CurrentWorkflowMutation = &WorkflowMutation{
+		ExecutionInfo:  &WorkflowExecutionInfo{DecisionScheduleID: 9, NextEventID: 8},
+		ExecutionStats: &ExecutionStats{},
+	}
+	err = manager.ConflictResolveWorkflowExecution(context.Background(), request)
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+
+	request = newRequest()
+	request.MaxMemoSize = 8
+	request.ResetWorkflowSnapshot.ExecutionInfo.Memo = map[string][]byte{"key": []byte("value-too-large")}
+	err = manager.ConflictResolveWorkflowExecution(context.Background(), request)
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+
+	request = newRequest()
+	request.MaxSignalInputSize = 8
+	request.NewWorkflowSnapshot = &WorkflowSnapshot{
+		ExecutionInfo:  &WorkflowExecutionInfo{DecisionScheduleID: common.EmptyEventID},
+		ExecutionStats: &ExecutionStats{},
+		SignalInfos:    []*SignalInfo{{InitiatedID: 5, Input: []byte("input-too-large")}},
+	}
+	err = manager.ConflictResolveWorkflowExecution(context.Background(), request)
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+
+	store.EXPECT().ConflictResolveWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	request = newRequest()
+	request.ResetWorkflowSnapshot.Checksum = checksum.Checksum{Flavor: checksum.FlavorIEEECRC32OverThriftBinary, Value: []byte("crc")}
+	assert.NoError(t, manager.ConflictResolveWorkflowExecution(context.Background(), request))
+}
+
+func TestWorkflowWritesValidateMutableState(t *testing.T) {
+	_, manager := newTestExecutionManager(t)
+	newExecutionInfo := func() *WorkflowExecutionInfo {
+		return &WorkflowExecutionInfo{
+			DomainID:           "domain",
+			WorkflowID:         "workflow",
+			RunID:              "run",
+			NextEventID:        6,
+			DecisionScheduleID: common.EmptyEventID,
+		}
+	}
+
+	// invalid states do not reach the store
+	_, err := manager.CreateWorkflowExecution(context.Background(), &CreateWorkflowExecutionRequest{
+		Mode: CreateWorkflowModeBrandNew,
+		NewWorkflowSnapshot: WorkflowSnapshot{
+			ExecutionInfo:      newExecutionInfo(),
+			ExecutionStats:     &ExecutionStats{},
+			SignalRequestedIDs: []string{"request", "request"},
+		},
+	})
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+
+	executionInfo := newExecutionInfo()
+	executionInfo.DecisionScheduleID = 0
+	_, err = manager.UpdateWorkflowExecution(context.Background(), &UpdateWorkflowExecutionRequest{
+		Mode:         UpdateWorkflowModeUpdateCurrent,
+		CurrentRunID: "run",
+		UpdateWorkflowMutation: WorkflowMutation{
+			ExecutionInfo:  executionInfo,
+			ExecutionStats: &ExecutionStats{},
+		},
+	})
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+
+	_, err = manager.UpdateWorkflowExecution(context.Background(), &UpdateWorkflowExecutionRequest{
+		Mode:         UpdateWorkflowModeUpdateCurrent,
+		CurrentRunID: "run",
+		UpdateWorkflowMutation: WorkflowMutation{
+			ExecutionInfo:             newExecutionInfo(),
+			ExecutionStats:            &ExecutionStats{},
+			UpsertChildExecutionInfos: []*ChildExecutionInfo{{InitiatedID: 3}, {InitiatedID: 3}},
+		},
+	})
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+}
+
+func TestGetWorkflowExecutionForUpdate(t *testing.T) {
+	execution := types.WorkflowExecution{WorkflowID: "wf", RunID: "run"}
+	store, manager := newTestExecutionManager(t)
+	store.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).Return(&InternalGetWorkflowExecutionResponse{
+		State: &InternalWorkflowMutableState{
+			ExecutionInfo: &InternalWorkflowExecutionInfo{
+				WorkflowID:  execution.WorkflowID,
+				RunID:       execution.RunID,
+				NextEventID: 10,
+			},
+		},
+	}, nil).Times(1)
+
+	resp, err := manager.GetWorkflowExecutionForUpdate(context.Background(), &GetWorkflowExecutionForUpdateRequest{
+		DomainID:  "domain",
+		Execution: execution,
+		RangeID:   5,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.UpdateToken)
+	assert.Equal(t, int64(10), resp.State.ExecutionInfo.NextEventID)
+
+	newRequest := func(rangeID int64, condition int64) *UpdateWorkflowExecutionRequest {
+		return &UpdateWorkflowExecutionRequest{
+			RangeID: rangeID,
+			UpdateWorkflowMutation: WorkflowMutation{
+				ExecutionInfo: &WorkflowExecutionInfo{
+					WorkflowID:         execution.WorkflowID,
+					RunID:              execution.RunID,
+					NextEventID:        condition + 1,
+					DecisionScheduleID: common.EmptyEventID,
+				},
+				ExecutionStats: &ExecutionStats{},
+				Condition:      condition,
+			},
+			UpdateToken: resp.UpdateToken,
+		}
+	}
+
+	store.EXPECT().UpdateWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	_, err = manager.UpdateWorkflowExecution(context.Background(), newRequest(5, 10))
+	require.NoError(t, err)
+
+	_, err = manager.UpdateWorkflowExecution(context.Background(), newRequest(5, 11))
+	assert.IsType(t, &ConditionFailedError{}, err)
+	_, err = manager.UpdateWorkflowExecution(context.Background(), newRequest(6, 10))
+	assert.IsType(t, &ConditionFailedError{}, err)
+
+	request := newRequest(5, 10)
+	request.UpdateToken = []byte("garbage")
+	_, err = manager.UpdateWorkflowExecution(context.Background(), request)
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+}
+
+func TestWorkflowExists(t *testing.T) {
+	store, manager := newTestExecutionManager(t)
+
+	store.EXPECT().GetCurrentRunID(gomock.Any(), &GetCurrentRunIDRequest{DomainID: "domain", WorkflowID: "workflow"}).
+		Return(&GetCurrentRunIDResponse{RunID: "run"}, nil).Times(1)
+	response, err := manager.WorkflowExists(context.Background(), &WorkflowExistsRequest{DomainID: "domain", WorkflowID: "workflow"})
+	require.NoError(t, err)
+	assert.True(t, response.Exists)
+
+	store.EXPECT().GetCurrentRunID(gomock.Any(), &GetCurrentRunIDRequest{DomainID: "domain", WorkflowID: "other"}).
+		Return(nil, &WorkflowExecutionNotExistsError{DomainID: "domain", WorkflowID: "other"}).Times(1)
+	response, err = manager.WorkflowExists(context.Background(), &WorkflowExistsRequest{DomainID: "domain", WorkflowID: "other"})
+	require.NoError(t, err)
+	assert.False(t, response.Exists)
+
+	storeErr := &types.InternalServiceError{Message: "error"}
+	store.EXPECT().GetCurrentRunID(gomock.Any(), gomock.Any()).Return(nil, storeErr).Times(1)
+	_, err = manager.WorkflowExists(context.Background(), &WorkflowExistsRequest{DomainID: "domain", WorkflowID: "workflow"})
+	assert.Equal(t, storeErr, err)
+}
+
+func TestCheckCurrentExecution(t *testing.T) {
+	store, manager := newTestExecutionManager(t)
+	expectCurrentRun := func(workflowID, runID string, exists bool) {
+		store.EXPECT().GetCurrentRunID(gomock.Any(), &GetCurrentRunIDRequest{DomainID: "domain", WorkflowID: workflowID}).
+			Return(&GetCurrentRunIDResponse{RunID: runID}, nil).Times(1)
+		store.EXPECT().IsWorkflowExecutionExists(gomock.Any(), &IsWorkflowExecutionExistsRequest{
+			DomainID:   "domain",
+			WorkflowID: workflowID,
+			RunID:      runID,
+		}).Return(&IsWorkflowExecutionExistsResponse{Exists: exists}, nil).Times(1)
+	}
+
+	expectCurrentRun("workflow", "run", true)
+	response, err := manager.CheckCurrentExecution(context.Background(), &CheckCurrentExecutionRequest{
+		DomainID:   "domain",
+		WorkflowID: "workflow",
+		Repair:     true,
+		RangeID:    5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &CheckCurrentExecutionResponse{CurrentRunID: "run", Exists: true}, response)
+
+	expectCurrentRun("dangling", "deleted-run", false)
+	response, err = manager.CheckCurrentExecution(context.Background(), &CheckCurrentExecutionRequest{
+		DomainID:   "domain",
+		WorkflowID: "dangling",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &CheckCurrentExecutionResponse{CurrentRunID: "deleted-run"}, response)
+
+	_, err = manager.CheckCurrentExecution(context.Background(), &CheckCurrentExecutionRequest{
+		DomainID:   "domain",
+		WorkflowID: "dangling",
+		Repair:     true,
+	})
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+
+	expectCurrentRun("dangling", "deleted-run", false)
+	store.EXPECT().DeleteCurrentWorkflowExecution(gomock.Any(), &DeleteCurrentWorkflowExecutionRequest{
+		DomainID:   "domain",
+		WorkflowID: "dangling",
+		RunID:      "deleted-run",
+		RangeID:    5,
+	}).Return(nil).Times(1)
+	response, err = manager.CheckCurrentExecution(context.Background(), &CheckCurrentExecutionRequest{
+		DomainID:   "domain",
+		WorkflowID: "dangling",
+		Repair:     true,
+		RangeID:    5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &CheckCurrentExecutionResponse{CurrentRunID: "deleted-run", Repaired: true}, response)
+
+	store.EXPECT().GetCurrentRunID(gomock.Any(), gomock.Any()).
+		Return(nil, &WorkflowExecutionNotExistsError{DomainID: "domain", WorkflowID: "other"}).Times(1)
+	_, err = manager.CheckCurrentExecution(context.Background(), &CheckCurrentExecutionRequest{
+		DomainID:   "domain",
+		WorkflowID: "other",
+	})
+	assert.IsType(t, &WorkflowExecutionNotExistsError{}, err)
+}
+
+func TestFlushBufferedEvents(t *testing.T) {
+	execution := types.WorkflowExecution{WorkflowID: "workflow", RunID: "run"}
+	bufferedEvents, err := NewPayloadSerializer().SerializeBatchEvents([]*types.HistoryEvent{
+		{EventID: common.BufferedEventID, TaskID: common.EmptyEventTaskID, Version: 1},
+		{EventID: common.BufferedEventID, TaskID: common.EmptyEventTaskID, Version: 1},
+	}, common.EncodingTypeThriftRW)
+	require.NoError(t, err)
+	branchToken, err := NewHistoryBranchToken("tree")
+	require.NoError(t, err)
+	newState := func(bufferedEvents ...*DataBlob) *InternalWorkflowMutableState {
+		return &InternalWorkflowMutableState{
+			ExecutionInfo: &InternalWorkflowExecutionInfo{
+				WorkflowID:         execution.WorkflowID,
+				RunID:              execution.RunID,
+				BranchToken:        branchToken,
+				LastFirstEventID:   5,
+				NextEventID:        10,
+				DecisionScheduleID: common.EmptyEventID,
+			},
+			BufferedEvents: bufferedEvents,
+		}
+	}
+
+	store, manager := newTestExecutionManager(t)
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	historyStore := NewMockHistoryStore(controller)
+	historyManager := NewHistoryV2ManagerImpl(historyStore, loggerimpl.NewNopLogger(), dynamicconfig.GetIntPropertyFn(1024*1024))
+	store.EXPECT().GetShardID().Return(1).AnyTimes()
+
+	err = manager.FlushBufferedEvents(context.Background(), &FlushBufferedEventsRequest{
+		DomainID:       "domain",
+		Execution:      execution,
+		HistoryManager: historyManager,
+		TransactionID:  100,
+	})
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+
+	store.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).
+		Return(&InternalGetWorkflowExecutionResponse{State: newState(bufferedEvents)}, nil).Times(1)
+	historyStore.EXPECT().AppendHistoryNodes(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *InternalAppendHistoryNodesRequest) error {
+			assert.Equal(t, int64(10), request.NodeID)
+			assert.Equal(t, int64(100), request.TransactionID)
+			assert.Equal(t, 1, request.ShardID)
+			return nil
+		},
+	).Times(1)
+	store.EXPECT().GetCurrentRunID(gomock.Any(), gomock.Any()).Return(&GetCurrentRunIDResponse{RunID: execution.RunID}, nil).Times(1)
+	store.EXPECT().UpdateWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *InternalUpdateWorkflowExecutionRequest) error {
+			assert.Equal(t, int64(5), request.RangeID)
+			assert.Equal(t, UpdateWorkflowModeUpdateCurrent, request.Mode)
+			assert.Equal(t, int64(10), request.UpdateWorkflowMutation.Condition)
+			assert.True(t, request.UpdateWorkflowMutation.ClearBufferedEvents)
+			assert.Equal(t, int64(10), request.UpdateWorkflowMutation.ExecutionInfo.LastFirstEventID)
+			assert.Equal(t, int64(12), request.UpdateWorkflowMutation.ExecutionInfo.NextEventID)
+			return nil
+		},
+	).Times(1)
+	err = manager.FlushBufferedEvents(context.Background(), &FlushBufferedEventsRequest{
+		RangeID:        5,
+		DomainID:       "domain",
+		Execution:      execution,
+		HistoryManager: historyManager,
+		TransactionID:  100,
+	})
+	require.NoError(t, err)
+
+	// nothing is written if there are no buffered events
+	store.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).
+		Return(&InternalGetWorkflowExecutionResponse{State: newState()}, nil).Times(1)
+	err = manager.FlushBufferedEvents(context.Background(), &FlushBufferedEventsRequest{
+		RangeID:        5,
+		DomainID:       "domain",
+		Execution:      execution,
+		HistoryManager: historyManager,
+		TransactionID:  101,
+	})
+	require.NoError(t, err)
+}
+
+func TestValidateUpdateWorkflowModeCurrentRunID(t *testing.T) {
+	store, manager := newTestExecutionManager(t)
+	newRequest := func(mode UpdateWorkflowMode, currentRunID string) *UpdateWorkflowExecutionRequest {
+		return &UpdateWorkflowExecutionRequest{
+			Mode: mode,
+			UpdateWorkflowMutation: WorkflowMutation{
+				ExecutionInfo:  &WorkflowExecutionInfo{DomainID: "domain", WorkflowID: "workflow", RunID: "run", DecisionScheduleID: common.EmptyEventID},
+				ExecutionStats: &ExecutionStats{},
+			},
+			CurrentRunID: currentRunID,
+		}
+	}
+	// the current run provided by the caller is checked
+	store.EXPECT().UpdateWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	_, err := manager.UpdateWorkflowExecution(context.Background(), newRequest(UpdateWorkflowModeUpdateCurrent, "run"))
+	assert.NoError(t, err)
+	_, err = manager.UpdateWorkflowExecution(context.Background(), newRequest(UpdateWorkflowModeBypassCurrent, "other-run"))
+	assert.NoError(t, err)
+	_, err = manager.UpdateWorkflowExecution(context.Background(), newRequest(UpdateWorkflowModeUpdateCurrent, "other-run"))
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+	_, err = manager.UpdateWorkflowExecution(context.Background(), newRequest(UpdateWorkflowModeBypassCurrent, "run"))
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+
+	// otherwise the current record is not read, the mode is left to the conditional write of the store
+	store.EXPECT().UpdateWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	_, err = manager.UpdateWorkflowExecution(context.Background(), newRequest(UpdateWorkflowModeUpdateCurrent, ""))
+	assert.NoError(t, err)
+	_, err = manager.UpdateWorkflowExecution(context.Background(), newRequest(UpdateWorkflowModeBypassCurrent, ""))
+	assert.NoError(t, err)
+}
+
+func TestGetTimerIndexTasksIteratorWithTaskTypeFilter(t *testing.T) {
+	newTimer := func(taskID int64, taskType int) *TimerTaskInfo {
+		return &TimerTaskInfo{TaskID: taskID, TaskType: taskType}
+	}
+	expectPage := func(store *MockExecutionStore, pageToken []byte, nextPageToken []byte, timers ...*TimerTaskInfo) *gomock.Call {
+		return store.EXPECT().GetTimerIndexTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *GetTimerIndexTasksRequest) (*GetTimerIndexTasksResponse, error) {
+				assert.Equal(t, pageToken, request.NextPageToken)
+				return &GetTimerIndexTasksResponse{Timers: timers, NextPageToken: nextPageToken}, nil
+			},
+		).Times(1)
+	}
+	store, manager := newTestExecutionManager(t)
+	request := &GetTimerIndexTasksRequest{
+		BatchSize:      2,
+		TaskTypeFilter: []int{TaskTypeActivityTimeout},
+	}
+	// the mock fails the test if the iterator reads before Next is called
+	iter := manager.GetTimerIndexTasksIterator(request)
+
+	gomock.InOrder(
+		expectPage(store, nil, []byte("page-2"), newTimer(1, TaskTypeActivityTimeout), newTimer(2, TaskTypeUserTimer)),
+		expectPage(store, []byte("page-2"), []byte("page-3"), newTimer(3, TaskTypeUserTimer)),
+		expectPage(store, []byte("page-3"), nil, newTimer(4, TaskTypeActivityTimeout), newTimer(5, TaskTypeActivityTimeout)),
+	)
+	var taskIDs []int64
+	for {
+		timer, err := iter.Next(context.Background())
+		require.NoError(t, err)
+		if timer == nil {
+			break
+		}
+		taskIDs = append(taskIDs, timer.TaskID)
+	}
+	assert.Equal(t, []int64{1, 4, 5}, taskIDs)
+	assert.Nil(t, request.NextPageToken)
+
+	timer, err := iter.Next(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, timer)
+}
+
+func TestGetTimerIndexTasksMinVisibilityTimestamp(t *testing.T) {
+	now := time.Now()
+	newTimer := func(taskType int, delay time.Duration) *TimerTaskInfo {
+		return &TimerTaskInfo{TaskType: taskType, VisibilityTimestamp: now.Add(delay)}
+	}
+	store, manager := newTestExecutionManager(t)
+	store.EXPECT().GetTimerIndexTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *GetTimerIndexTasksRequest) (*GetTimerIndexTasksResponse, error) {
+			return &GetTimerIndexTasksResponse{
+				Timers: []*TimerTaskInfo{
+					newTimer(TaskTypeUserTimer, time.Second),
+					newTimer(TaskTypeActivityTimeout, 3*time.Second),
+					newTimer(TaskTypeActivityTimeout, 2*time.Second),
+				},
+			}, nil
+		},
+	).Times(2)
+
+	response, err := manager.GetTimerIndexTasks(context.Background(), &GetTimerIndexTasksRequest{BatchSize: 3})
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Second), response.MinVisibilityTimestamp)
+
+	response, err = manager.GetTimerIndexTasks(context.Background(), &GetTimerIndexTasksRequest{
+		BatchSize:      3,
+		TaskTypeFilter: []int{TaskTypeActivityTimeout},
+	})
+	require.NoError(t, err)
+	assert.Len(t, response.Timers, 2)
+	assert.Equal(t, now.Add(time.Second), response.MinVisibilityTimestamp)
+
+	store.EXPECT().GetTimerIndexTasks(gomock.Any(), gomock.Any()).Return(&GetTimerIndexTasksResponse{}, nil).Times(1)
+	response, err = manager.GetTimerIndexTasks(context.Background(), &GetTimerIndexTasksRequest{
+		BatchSize:     3,
+		NextPageToken: []byte("page-2"),
+	})
+	require.NoError(t, err)
+	assert.True(t, response.MinVisibilityTimestamp.IsZero())
+}
+
+func TestGetFailoverMarkerTasks(t *testing.T) {
+	store, manager := newTestExecutionManager(t)
+	store.EXPECT().GetReplicationTasks(gomock.Any(), &GetReplicationTasksRequest{BatchSize: 10}).
+		Return(&InternalGetReplicationTasksResponse{
+			Tasks: []*InternalReplicationTaskInfo{
+				{TaskID: 1, DomainID: "domain", TaskType: ReplicationTaskTypeHistory},
+				{TaskID: 2, DomainID: "domain", TaskType: ReplicationTaskTypeFailoverMarker},
+				{TaskID: 3, DomainID: "other-domain", TaskType: ReplicationTaskTypeFailoverMarker},
+				{TaskID: 4, DomainID: "domain", TaskType: ReplicationTaskTypeFailoverMarker},
+			},
+			NextPageToken: []byte("next"),
+		}, nil).Times(1)
+
+	resp, err := manager.GetFailoverMarkerTasks(context.Background(), &GetFailoverMarkerTasksRequest{
+		DomainID:                   "domain",
+		GetReplicationTasksRequest: GetReplicationTasksRequest{BatchSize: 10},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Tasks, 2)
+	assert.Equal(t, int64(2), resp.Tasks[0].TaskID)
+	assert.Equal(t, int64(4), resp.Tasks[1].TaskID)
+	assert.Equal(t, []byte("next"), resp.NextPageToken)
+
+	_, err = manager.GetFailoverMarkerTasks(context.Background(), &GetFailoverMarkerTasksRequest{})
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+}
+
+func TestGetReplicationTasksWithDomainIDFilter(t *testing.T) {
+	newTask := func(taskID int64, domainID string) *InternalReplicationTaskInfo {
+		return &InternalReplicationTaskInfo{TaskID: taskID, DomainID: domainID, TaskType: ReplicationTaskTypeHistory}
+	}
+	pages := []*InternalGetReplicationTasksResponse{
+		{
+			Tasks:         []*InternalReplicationTaskInfo{newTask(1, "other"), newTask(2, "other"), newTask(3, "domain")},
+			NextPageToken: []byte("page-2"),
+		},
+		{
+			Tasks:         []*InternalReplicationTaskInfo{newTask(4, "other"), newTask(5, "other"), newTask(6, "other")},
+			NextPageToken: []byte("page-3"),
+		},
+		{
+			Tasks:         []*InternalReplicationTaskInfo{newTask(7, "other"), newTask(8, "domain"), newTask(9, "domain")},
+			NextPageToken: []byte("page-4"),
+		},
+		{
+			Tasks: []*InternalReplicationTaskInfo{newTask(10, "other")},
+		},
+	}
+	store, manager := newTestExecutionManager(t)
+	domainIDFilter := map[string]struct{}{"domain": {}}
+	var token []byte
+	for _, page := range pages {
+		store.EXPECT().GetReplicationTasks(gomock.Any(), &GetReplicationTasksRequest{
+			BatchSize:      3,
+			NextPageToken:  token,
+			DomainIDFilter: domainIDFilter,
+		}).Return(page, nil).Times(1)
+		token = page.NextPageToken
+	}
+
+	request := &GetReplicationTasksRequest{
+		BatchSize:      3,
+		DomainIDFilter: domainIDFilter,
+	}
+	var pageSizes []int
+	var taskIDs []int64
+	var taskIDRanges [][2]int64
+	for {
+		resp, err := manager.GetReplicationTasks(context.Background(), request)
+		require.NoError(t, err)
+		pageSizes = append(pageSizes, len(resp.Tasks))
+		taskIDRanges = append(taskIDRanges, [2]int64{resp.MinTaskID, resp.MaxTaskID})
+		for _, task := range resp.Tasks {
+			assert.Equal(t, "domain", task.DomainID)
+			taskIDs = append(taskIDs, task.TaskID)
+		}
+		if len(resp.NextPageToken) == 0 {
+			break
+		}
+		request.NextPageToken = resp.NextPageToken
+	}
+	// pages which are filtered out entirely still carry the token to continue paging
+	assert.Equal(t, []int{1, 0, 2, 0}, pageSizes)
+	assert.Equal(t, []int64{3, 8, 9}, taskIDs)
+	// the task ID range covers the whole page read, not only the tasks left after filtering
+	assert.Equal(t, [][2]int64{{1, 3}, {4, 6}, {7, 9}, {10, 10}}, taskIDRanges)
+
+	// an empty filter matches no domain, while no filter returns every task
+	store.EXPECT().GetReplicationTasks(gomock.Any(), gomock.Any()).Return(pages[0], nil).Times(2)
+	resp, err := manager.GetReplicationTasks(context.Background(), &GetReplicationTasksRequest{
+		DomainIDFilter: map[string]struct{}{},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Tasks)
+	assert.Equal(t, []byte("page-2"), resp.NextPageToken)
+
+	resp, err = manager.GetReplicationTasks(context.Background(), &GetReplicationTasksRequest{})
+	require.NoError(t, err)
+	assert.Len(t, resp.Tasks, 3)
+}
+
+func TestGetReplicationTasksFromAllDLQs(t *testing.T) {
+	store, mgr := newTestExecutionManager(t)
+	taskType := ReplicationTaskTypeHistory
+	expectDLQRead := func(request *GetReplicationTasksFromDLQRequest, response *InternalGetReplicationTasksFromDLQResponse) {
+		store.EXPECT().GetReplicationTasksFromDLQ(gomock.Any(), request).Return(response, nil).Times(1)
+	}
+
+	expectDLQRead(&GetReplicationTasksFromDLQRequest{
+		SourceClusterName:          "cluster-a",
+		TaskTypeFilter:             &taskType,
+		GetReplicationTasksRequest: GetReplicationTasksRequest{BatchSize: 2},
+	}, &InternalGetReplicationTasksFromDLQResponse{
+		Tasks: []*InternalReplicationTaskInfo{
+			{TaskID: 1, TaskType: ReplicationTaskTypeHistory},
+			{TaskID: 2, TaskType: ReplicationTaskTypeSyncActivity},
+		},
+		NextPageToken: []byte("page-2"),
+	})
+	expectDLQRead(&GetReplicationTasksFromDLQRequest{
+		SourceClusterName:          "cluster-b",
+		TaskTypeFilter:             &taskType,
+		GetReplicationTasksRequest: GetReplicationTasksRequest{BatchSize: 2},
+	}, &InternalGetReplicationTasksFromDLQResponse{
+		Tasks: []*InternalReplicationTaskInfo{{TaskID: 10, TaskType: ReplicationTaskTypeHistory}},
+	})
+	resp, err := mgr.GetReplicationTasksFromAllDLQs(context.Background(), &GetReplicationTasksFromAllDLQsRequest{
+		Requests: map[string]GetReplicationTasksRequest{
+			"cluster-a": {BatchSize: 2},
+			"cluster-b": {BatchSize: 2},
+		},
+		TaskTypeFilter: &taskType,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Responses, 2)
+	require.Len(t, resp.Responses["cluster-a"].Tasks, 1)
+	assert.Equal(t, int64(1), resp.Responses["cluster-a"].Tasks[0].TaskID)
+	assert.Equal(t, []byte("page-2"), resp.Responses["cluster-a"].NextPageToken)
+	require.Len(t, resp.Responses["cluster-b"].Tasks, 1)
+	assert.Equal(t, int64(10), resp.Responses["cluster-b"].Tasks[0].TaskID)
+	assert.Empty(t, resp.Responses["cluster-b"].NextPageToken)
+
+	// each cluster is paged with its own token
+	expectDLQRead(&GetReplicationTasksFromDLQRequest{
+		SourceClusterName:          "cluster-a",
+		GetReplicationTasksRequest: GetReplicationTasksRequest{BatchSize: 2, NextPageToken: []byte("page-2")},
+	}, &InternalGetReplicationTasksFromDLQResponse{
+		Tasks: []*InternalReplicationTaskInfo{{TaskID: 3, TaskType: ReplicationTaskTypeHistory}},
+	})
+	resp, err = mgr.GetReplicationTasksFromAllDLQs(context.Background(), &GetReplicationTasksFromAllDLQsRequest{
+		Requests: map[string]GetReplicationTasksRequest{
+			"cluster-a": {BatchSize: 2, NextPageToken: resp.Responses["cluster-a"].NextPageToken},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Responses, 1)
+	require.Len(t, resp.Responses["cluster-a"].Tasks, 1)
+	assert.Equal(t, int64(3), resp.Responses["cluster-a"].Tasks[0].TaskID)
+	assert.Empty(t, resp.Responses["cluster-a"].NextPageToken)
+
+	expectDLQRead(&GetReplicationTasksFromDLQRequest{
+		SourceClusterName:          "cluster-a",
+		GetReplicationTasksRequest: GetReplicationTasksRequest{BatchSize: 2},
+	}, &InternalGetReplicationTasksFromDLQResponse{})
+	store.EXPECT().GetReplicationTasksFromDLQ(gomock.Any(), &GetReplicationTasksFromDLQRequest{
+		SourceClusterName:          "unknown-cluster",
+		GetReplicationTasksRequest: GetReplicationTasksRequest{BatchSize: 2},
+	}).Return(nil, &types.InternalServiceError{Message: "unknown source cluster"}).Times(1)
+	_, err = mgr.GetReplicationTasksFromAllDLQs(context.Background(), &GetReplicationTasksFromAllDLQsRequest{
+		Requests: map[string]GetReplicationTasksRequest{
+			"cluster-a":       {BatchSize: 2},
+			"unknown-cluster": {BatchSize: 2},
+		},
+	})
+	assert.IsType(t, &types.InternalServiceError{}, err)
+}
+
+func TestGetTransferTasksGroupByType(t *testing.T) {
+	store, manager := newTestExecutionManager(t)
+	store.EXPECT().GetTransferTasks(gomock.Any(), gomock.Any()).Return(&GetTransferTasksResponse{
+		Tasks: []*TransferTaskInfo{
+			{TaskID: 1, TaskType: TransferTaskTypeDecisionTask},
+			{TaskID: 2, TaskType: TransferTaskTypeActivityTask},
+			{TaskID: 3, TaskType: TransferTaskTypeDecisionTask},
+		},
+		NextPageToken: []byte("next"),
+	}, nil).Times(2)
+
+	resp, err := manager.GetTransferTasks(context.Background(), &GetTransferTasksRequest{BatchSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, resp.Tasks, 3)
+	assert.Nil(t, resp.TasksByType)
+
+	resp, err = manager.GetTransferTasks(context.Background(), &GetTransferTasksRequest{BatchSize: 10, GroupByType: true})
+	require.NoError(t, err)
+	assert.Len(t, resp.Tasks, 3)
+	assert.Equal(t, []byte("next"), resp.NextPageToken)
+	require.Len(t, resp.TasksByType, 2)
+	require.Len(t, resp.TasksByType[TransferTaskTypeDecisionTask], 2)
+	assert.Equal(t, int64(1), resp.TasksByType[TransferTaskTypeDecisionTask][0].TaskID)
+	assert.Equal(t, int64(3), resp.TasksByType[TransferTaskTypeDecisionTask][1].TaskID)
+	require.Len(t, resp.TasksByType[TransferTaskTypeActivityTask], 1)
+	assert.Equal(t, int64(2), resp.TasksByType[TransferTaskTypeActivityTask][0].TaskID)
+}
+
+func TestDeleteWorkflowExecutions(t *testing.T) {
+	store, mgr := newTestExecutionManager(t)
+	request := &DeleteWorkflowExecutionsRequest{
+		DomainID:   "domain",
+		WorkflowID: "workflow",
+		RunIDs:     []string{"run-1", "run-2", "run-3"},
+	}
+	deleteErr := errors.New("delete failed")
+	for _, runID := range request.RunIDs {
+		var err error
+		if runID == "run-2" {
+			err = deleteErr
+		}
+		// the current record is removed before the run it points to
+		gomock.InOrder(
+			store.EXPECT().DeleteCurrentWorkflowExecution(gomock.Any(), &DeleteCurrentWorkflowExecutionRequest{
+				DomainID:   "domain",
+				WorkflowID: "workflow",
+				RunID:      runID,
+			}).Return(nil).Times(1),
+			store.EXPECT().DeleteWorkflowExecution(gomock.Any(), &DeleteWorkflowExecutionRequest{
+				DomainID:   "domain",
+				WorkflowID: "workflow",
+				RunID:      runID,
+			}).Return(err).Times(1),
+		)
+	}
+
+	resp, err := mgr.DeleteWorkflowExecutions(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]error{"run-2": deleteErr}, resp.Errors)
+
+	// a cancelled context does not reach the store
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resp, err = mgr.DeleteWorkflowExecutions(ctx, request)
+	require.NoError(t, err)
+	assert.Len(t, resp.Errors, 3)
+	for _, runID := range request.RunIDs {
+		assert.Equal(t, context.Canceled, resp.Errors[runID])
+	}
+}
@@ -39,6 +39,8 @@ import (
 type (
 	// historyManagerImpl implements HistoryManager based on HistoryStore and PayloadSerializer
 	historyV2ManagerImpl struct {
+		historyManagerCompositeOperations
+
 		historySerializer     PayloadSerializer
 		persistence           HistoryStore
 		logger                log.Logger
@@ -62,7 +64,7 @@ func NewHistoryV2ManagerImpl(
 	transactionSizeLimit dynamicconfig.IntPropertyFn,
 ) HistoryManager {
 
-	return &historyV2ManagerImpl{
+	m := &historyV2ManagerImpl{
 		historySerializer:     NewPayloadSerializer(),
 		persistence:           persistence,
 		logger:                logger,
@@ -70,6 +72,8 @@ func NewHistoryV2ManagerImpl(
 		pagingTokenSerializer: newJSONHistoryTokenSerializer(),
 		transactionSizeLimit:  transactionSizeLimit,
 	}
+	m.historyManagerCompositeOperations = historyManagerCompositeOperations{manager: m}
+	return m
 }
 
 func (m *historyV2ManagerImpl) GetName() string {
@@ -143,8 +147,9 @@ func (m *historyV2ManagerImpl) DeleteHistoryBranch(
 		}
 	}
 	req := &InternalDeleteHistoryBranchRequest{
-		BranchInfo: *thrift.ToHistoryBranch(&branch),
-		ShardID:    shardID,
+		BranchInfo:        *thrift.ToHistoryBranch(&branch),
+		ShardID:           shardID,
+		PreserveAncestors: request.PreserveAncestors,
 	}
 
 	return m.persistence.DeleteHistoryBranch(ctx, req)
@@ -164,9 +169,11 @@ func (m *historyV2ManagerImpl) GetHistoryTree(
 		request.TreeID = branch.GetTreeID()
 	}
 	internalRequest := &InternalGetHistoryTreeRequest{
-		TreeID:      request.TreeID,
-		ShardID:     request.ShardID,
-		BranchToken: request.BranchToken,
+		TreeID:        request.TreeID,
+		ShardID:       request.ShardID,
+		BranchToken:   request.BranchToken,
+		PageSize:      request.PageSize,
+		NextPageToken: request.NextPageToken,
 	}
 	resp, err := m.persistence.GetHistoryTree(ctx, internalRequest)
 	if err != nil {
@@ -177,7 +184,8 @@ func (m *historyV2ManagerImpl) GetHistoryTree(
 		branches = append(branches, thrift.FromHistoryBranch(b))
 	}
 	return &GetHistoryTreeResponse{
-		Branches: branches,
+		Branches:      branches,
+		NextPageToken: resp.NextPageToken,
 	}, nil
 }
 
@@ -206,6 +214,11 @@ func (m *historyV2ManagerImpl) AppendHistoryNodes(
 			Msg: fmt.Sprintf("eventID cannot be less than 1"),
 		}
 	}
+	if request.TTLSeconds < 0 {
+		return nil, &InvalidPersistenceRequestError{
+			Msg: "TTL cannot be negative",
+		}
+	}
 	for _, e := range request.Events {
 		if e.Version != version {
 			return nil, &InvalidPersistenceRequestError{
@@ -221,10 +234,14 @@ func (m *historyV2ManagerImpl) AppendHistoryNodes(
 	}
 
 	// nodeID will be the first eventID
-	blob, err := m.historySerializer.SerializeBatchEvents(request.Events, request.Encoding)
+	blob, err := m.historySerializer.SerializeBatchEvents(request.Events, ResolveEncoding(request.Encoding))
 	if err != nil {
 		return nil, err
 	}
+	blob, err = CompressDataBlob(blob, request.Compression)
+	if err != nil {
+		return nil, &InvalidPersistenceRequestError{Msg: err.Error()}
+	}
 	size := len(blob.Data)
 	sizeLimit := m.transactionSizeLimit()
 	if size > sizeLimit {
@@ -247,6 +264,8 @@ func (m *historyV2ManagerImpl) AppendHistoryNodes(
 		Events:        blob,
 		TransactionID: request.TransactionID,
 		ShardID:       shardID,
+		TTLSeconds:    request.TTLSeconds,
+		FailIfExists:  request.FailIfExists,
 	}
 
 	err = m.persistence.AppendHistoryNodes(ctx, req)
@@ -265,7 +284,11 @@ func (m *historyV2ManagerImpl) ReadHistoryBranchByBatch(
 
 	resp := &ReadHistoryBranchByBatchResponse{}
 	var err error
-	_, resp.History, resp.NextPageToken, resp.Size, resp.LastFirstEventID, err = m.readHistoryBranch(ctx, true, request)
+	if request.ReverseOrder {
+		_, resp.History, resp.NextPageToken, resp.Size, resp.LastFirstEventID, err = m.readHistoryBranchReverse(ctx, true, request)
+	} else {
+		_, resp.History, _, resp.NextPageToken, resp.Size, resp.LastFirstEventID, err = m.readHistoryBranch(ctx, true, request)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -281,7 +304,11 @@ func (m *historyV2ManagerImpl) ReadHistoryBranch(
 
 	resp := &ReadHistoryBranchResponse{}
 	var err error
-	resp.HistoryEvents, _, resp.NextPageToken, resp.Size, resp.LastFirstEventID, err = m.readHistoryBranch(ctx, false, request)
+	if request.ReverseOrder {
+		resp.HistoryEvents, _, resp.NextPageToken, resp.Size, resp.LastFirstEventID, err = m.readHistoryBranchReverse(ctx, false, request)
+	} else {
+		resp.HistoryEvents, _, _, resp.NextPageToken, resp.Size, resp.LastFirstEventID, err = m.readHistoryBranch(ctx, false, request)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -296,6 +323,12 @@ func (m *historyV2ManagerImpl) ReadRawHistoryBranch(
 	request *ReadHistoryBranchRequest,
 ) (*ReadRawHistoryBranchResponse, error) {
 
+	if request.ReverseOrder {
+		return nil, &InvalidPersistenceRequestError{
+			Msg: "ReadRawHistoryBranch does not support reading in reverse order",
+		}
+	}
+
 	dataBlobs, token, dataSize, _, err := m.readRawHistoryBranch(ctx, request)
 	if err != nil {
 		return nil, err
@@ -313,6 +346,90 @@ func (m *historyV2ManagerImpl) ReadRawHistoryBranch(
 	}, nil
 }
 
+// ReadHistoryBranchWithBlobs returns history node data for a branch by batch, together with the raw blob of each batch
+// Pagination is implemented here, the actual minNodeID passing to persistence layer is calculated along with token's LastNodeID
+func (m *historyV2ManagerImpl) ReadHistoryBranchWithBlobs(
+	ctx context.Context,
+	request *ReadHistoryBranchRequest,
+) (*ReadHistoryBranchWithBlobsResponse, error) {
+
+	if request.ReverseOrder {
+		return nil, &InvalidPersistenceRequestError{
+			Msg: "ReadHistoryBranchWithBlobs does not support reading in reverse order",
+		}
+	}
+
+	resp := &ReadHistoryBranchWithBlobsResponse{}
+	var err error
+	_, resp.History, resp.HistoryEventBlobs, resp.NextPageToken, resp.Size, resp.LastFirstEventID, err = m.readHistoryBranch(ctx, true, request)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ReadHistoryNode returns the single batch of events starting at the node ID
+func (m *historyV2ManagerImpl) ReadHistoryNode(
+	ctx context.Context,
+	request *ReadHistoryNodeRequest,
+) (*ReadHistoryNodeResponse, error) {
+
+	var branch workflow.HistoryBranch
+	err := m.thriftEncoder.Decode(request.BranchToken, &branch)
+	if err != nil {
+		return nil, err
+	}
+	if request.NodeID < common.FirstEventID {
+		return nil, &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("node ID %v cannot be less than %v", request.NodeID, common.FirstEventID),
+		}
+	}
+
+	// nodes before the fork point of the branch are stored under the ancestor branches
+	branchID := branch.GetBranchID()
+	for _, ancestor := range branch.Ancestors {
+		if request.NodeID >= ancestor.GetBeginNodeID() && request.NodeID < ancestor.GetEndNodeID() {
+			branchID = ancestor.GetBranchID()
+			break
+		}
+	}
+
+	shardID, err := getShardID(request.ShardID)
+	if err != nil {
+		m.logger.Error("shardID is not set in read history node operation", tag.Error(err))
+		return nil, &types.InternalServiceError{Message: err.Error()}
+	}
+	resp, err := m.persistence.ReadHistoryNode(ctx, &InternalReadHistoryNodeRequest{
+		TreeID:   branch.GetTreeID(),
+		BranchID: branchID,
+		NodeID:   request.NodeID,
+		ShardID:  shardID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	history, err := DecompressDataBlob(resp.History)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := m.historySerializer.DeserializeBatchEvents(history)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 || events[0].GetEventID() != request.NodeID {
+		return nil, &types.InternalDataInconsistencyError{
+			Message: fmt.Sprintf("corrupted history node %v, first event ID does not match the node ID", request.NodeID),
+		}
+	}
+
+	return &ReadHistoryNodeResponse{
+		Events:        events,
+		TransactionID: resp.TransactionID,
+		Size:          len(resp.History.Data),
+	}, nil
+}
+
 func (m *historyV2ManagerImpl) GetAllHistoryTreeBranches(
 	ctx context.Context,
 	request *GetAllHistoryTreeBranchesRequest,
@@ -422,10 +539,16 @@ func (m *historyV2ManagerImpl) readRawHistoryBranch(
 		return nil, nil, 0, nil, &types.EntityNotExistsError{Message: "Workflow execution history not found."}
 	}
 
-	dataBlobs := resp.History
+	dataBlobs := make([]*DataBlob, 0, len(resp.History))
 	dataSize := 0
 	for _, dataBlob := range resp.History {
+		// the size is the one read from the store, before the blob is decompressed
 		dataSize += len(dataBlob.Data)
+		dataBlob, err = DecompressDataBlob(dataBlob)
+		if err != nil {
+			return nil, nil, 0, nil, err
+		}
+		dataBlobs = append(dataBlobs, dataBlob)
 	}
 
 	token.StoreToken = resp.NextPageToken
@@ -439,31 +562,37 @@ func (m *historyV2ManagerImpl) readRawHistoryBranch(
 	return dataBlobs, token, dataSize, logger, nil
 }
 
+// readHistoryBranch reads a page of a branch, when byBatch is set the events are returned by batch
+// together with the blob each batch is decoded from, otherwise they are returned as a single list
 func (m *historyV2ManagerImpl) readHistoryBranch(
 	ctx context.Context,
 	byBatch bool,
 	request *ReadHistoryBranchRequest,
-) ([]*types.HistoryEvent, []*types.History, []byte, int, int64, error) {
+) ([]*types.HistoryEvent, []*types.History, []*DataBlob, []byte, int, int64, error) {
 
 	dataBlobs, token, dataSize, logger, err := m.readRawHistoryBranch(ctx, request)
 	if err != nil {
-		return nil, nil, nil, 0, 0, err
+		return nil, nil, nil, nil, 0, 0, err
 	}
 	defaultLastEventID := request.MinEventID - 1
 
 	historyEvents := make([]*types.HistoryEvent, 0, request.PageSize)
 	historyEventBatches := make([]*types.History, 0, request.PageSize)
+	var historyEventBlobs []*DataBlob
+	if byBatch {
+		historyEventBlobs = make([]*DataBlob, 0, request.PageSize)
+	}
 	// first_event_id of the last batch
 	lastFirstEventID := common.EmptyEventID
 
 	for _, batch := range dataBlobs {
 		events, err := m.historySerializer.DeserializeBatchEvents(batch)
 		if err != nil {
-			return nil, nil, nil, 0, 0, err
+			return nil, nil, nil, nil, 0, 0, err
 		}
 		if len(events) == 0 {
 			logger.Error("Empty events in a batch")
-			return nil, nil, nil, 0, 0, &types.InternalDataInconsistencyError{
+			return nil, nil, nil, nil, 0, 0, &types.InternalDataInconsistencyError{
 				Message: fmt.Sprintf("corrupted history event batch, empty events"),
 			}
 		}
@@ -478,7 +607,7 @@ func (m *historyV2ManagerImpl) readHistoryBranch(
 				tag.FirstEventVersion(firstEvent.GetVersion()), tag.WorkflowFirstEventID(firstEvent.GetEventID()),
 				tag.LastEventVersion(lastEvent.GetVersion()), tag.WorkflowNextEventID(lastEvent.GetEventID()),
 				tag.Counter(eventCount))
-			return nil, nil, nil, 0, 0, &types.InternalDataInconsistencyError{
+			return nil, nil, nil, nil, 0, 0, &types.InternalDataInconsistencyError{
 				Message: fmt.Sprintf("corrupted history event batch, wrong version and IDs"),
 			}
 		}
@@ -504,7 +633,7 @@ func (m *historyV2ManagerImpl) readHistoryBranch(
 					tag.LastEventVersion(lastEvent.GetVersion()), tag.WorkflowNextEventID(lastEvent.GetEventID()),
 					tag.TokenLastEventVersion(token.LastEventVersion), tag.TokenLastEventID(token.LastEventID),
 					tag.Counter(eventCount))
-				return nil, nil, nil, 0, 0, &types.InternalDataInconsistencyError{
+				return nil, nil, nil, nil, 0, 0, &types.InternalDataInconsistencyError{
 					Message: fmt.Sprintf("corrupted history event batch, eventID is not continouous"),
 				}
 			}
@@ -514,6 +643,7 @@ func (m *historyV2ManagerImpl) readHistoryBranch(
 		token.LastEventID = lastEvent.GetEventID()
 		if byBatch {
 			historyEventBatches = append(historyEventBatches, &types.History{Events: events})
+			historyEventBlobs = append(historyEventBlobs, batch)
 		} else {
 			historyEvents = append(historyEvents, events...)
 		}
@@ -521,13 +651,133 @@ func (m *historyV2ManagerImpl) readHistoryBranch(
 	}
 
 	nextPageToken, err := m.serializeToken(token)
+	if err != nil {
+		return nil, nil, nil, nil, 0, 0, err
+	}
+
+	return historyEvents, historyEventBatches, historyEventBlobs, nextPageToken, dataSize, lastFirstEventID, nil
+}
+
+// readHistoryBranchReverse pages through a branch from MaxEventID down to MinEventID.
+// Stores can only read forward, so every page is read forward from a window of event IDs below
+// the lowest event returned so far, and the window is doubled until it holds PageSize batches.
+// The paging token only carries the lowest event ID returned so far in LastEventID.
+func (m *historyV2ManagerImpl) readHistoryBranchReverse(
+	ctx context.Context,
+	byBatch bool,
+	request *ReadHistoryBranchRequest,
+) ([]*types.HistoryEvent, []*types.History, []byte, int, int64, error) {
+
+	if request.PageSize <= 0 || request.MinEventID >= request.MaxEventID {
+		return nil, nil, nil, 0, 0, &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf(
+				"no events can be found for pageSize %v, minEventID %v, maxEventID: %v",
+				request.PageSize,
+				request.MinEventID,
+				request.MaxEventID,
+			),
+		}
+	}
+
+	token, err := m.deserializeToken(request.NextPageToken, request.MaxEventID)
 	if err != nil {
 		return nil, nil, nil, 0, 0, err
 	}
+	upperEventID := token.LastEventID
+
+	var batches []*types.History
+	dataSize := 0
+	lowerEventID := upperEventID
+	for window := int64(request.PageSize); lowerEventID > request.MinEventID; window *= 2 {
+		lowerEventID = upperEventID - window
+		if lowerEventID < request.MinEventID {
+			lowerEventID = request.MinEventID
+		}
+		var size int
+		batches, size, err = m.readHistoryBatches(ctx, request, lowerEventID, upperEventID)
+		if err != nil {
+			return nil, nil, nil, 0, 0, err
+		}
+		dataSize += size
+		if len(batches) >= request.PageSize {
+			break
+		}
+	}
+	if len(batches) == 0 && len(request.NextPageToken) == 0 {
+		return nil, nil, nil, 0, 0, &types.EntityNotExistsError{Message: "Workflow execution history not found."}
+	}
+
+	// more pages are left if the window did not reach MinEventID, or held more batches than fit in this page
+	hasMore := lowerEventID > request.MinEventID || len(batches) > request.PageSize
+	if len(batches) > request.PageSize {
+		batches = batches[len(batches)-request.PageSize:]
+	}
+
+	historyEvents := make([]*types.HistoryEvent, 0, request.PageSize)
+	historyEventBatches := make([]*types.History, 0, len(batches))
+	lastFirstEventID := common.EmptyEventID
+	for i := len(batches) - 1; i >= 0; i-- {
+		events := batches[i].Events
+		if byBatch {
+			historyEventBatches = append(historyEventBatches, batches[i])
+		} else {
+			for j := len(events) - 1; j >= 0; j-- {
+				historyEvents = append(historyEvents, events[j])
+			}
+		}
+		lastFirstEventID = events[0].GetEventID()
+	}
 
+	if !hasMore {
+		return historyEvents, historyEventBatches, nil, dataSize, lastFirstEventID, nil
+	}
+	if len(batches) > 0 {
+		token.LastEventID = batches[0].Events[0].GetEventID()
+	} else {
+		token.LastEventID = lowerEventID
+	}
+	nextPageToken, err := m.pagingTokenSerializer.Serialize(token)
+	if err != nil {
+		return nil, nil, nil, 0, 0, err
+	}
 	return historyEvents, historyEventBatches, nextPageToken, dataSize, lastFirstEventID, nil
 }
 
+// readHistoryBatches reads all batches of a branch starting in [minEventID, maxEventID) in ascending order
+func (m *historyV2ManagerImpl) readHistoryBatches(
+	ctx context.Context,
+	request *ReadHistoryBranchRequest,
+	minEventID int64,
+	maxEventID int64,
+) ([]*types.History, int, error) {
+
+	req := &ReadHistoryBranchRequest{
+		BranchToken: request.BranchToken,
+		MinEventID:  minEventID,
+		MaxEventID:  maxEventID,
+		PageSize:    request.PageSize,
+		ShardID:     request.ShardID,
+	}
+	var batches []*types.History
+	dataSize := 0
+	for {
+		_, page, _, nextPageToken, size, _, err := m.readHistoryBranch(ctx, true, req)
+		if err != nil {
+			if _, ok := err.(*types.EntityNotExistsError); ok {
+				// no batch starts in the window, a wider window is needed
+				return batches, dataSize, nil
+			}
+			return nil, 0, err
+		}
+		batches = append(batches, page...)
+		dataSize += size
+		if len(nextPageToken) == 0 {
+			return batches, dataSize, nil
+		}
+		req.NextPageToken = nextPageToken
+	}
+}
+
 func (m *historyV2ManagerImpl) deserializeToken(
 	token []byte,
 	defaultLastEventID int64,
@@ -562,3 +812,8 @@ func (m *historyV2ManagerImpl) serializeToken(
 func (m *historyV2ManagerImpl) Close() {
 	m.persistence.Close()
 }
+
+// CloseWithContext closes the history store within the deadline of ctx
+func (m *historyV2ManagerImpl) CloseWithContext(ctx context.Context) error {
+	return CloseWithContext(ctx, m.persistence)
+}
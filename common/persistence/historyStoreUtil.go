@@ -24,6 +24,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/types"
 )
 
@@ -79,6 +80,33 @@ func ReadFullPageV2EventsByBatch(
 	}
 }
 
+// ReadHistoryBranchIterator pages through a history branch with ReadHistoryBranch and invokes the callback once
+// per event, so callers never need to hold more than a single page of events. Iteration stops as soon as the
+// callback returns an error, and that error is returned as is. The request passed in is not modified.
+func ReadHistoryBranchIterator(
+	ctx context.Context,
+	historyV2Mgr HistoryManager,
+	request *ReadHistoryBranchRequest,
+	callback func(event *types.HistoryEvent) error,
+) error {
+	req := *request
+	for {
+		response, err := historyV2Mgr.ReadHistoryBranch(ctx, &req)
+		if err != nil {
+			return err
+		}
+		for _, event := range response.HistoryEvents {
+			if err := callback(event); err != nil {
+				return err
+			}
+		}
+		if len(response.NextPageToken) == 0 {
+			return nil
+		}
+		req.NextPageToken = response.NextPageToken
+	}
+}
+
 // GetBeginNodeID gets node id from last ancestor
 func GetBeginNodeID(bi types.HistoryBranch) int64 {
 	if len(bi.Ancestors) == 0 {
@@ -89,6 +117,67 @@ func GetBeginNodeID(bi types.HistoryBranch) int64 {
 	return *bi.Ancestors[idx].EndNodeID
 }
 
+// GetHistoryBranchRangesToDelete returns the node ranges that can be removed when deleting the given branch,
+// ordered from the branch itself up to its ancestors. The BeginNodeID of each returned range is the first node
+// to delete; all nodes of that branch from there on can be removed. treeBranches are all branches of the tree.
+// When preserveAncestors is set, the deleted branch itself is not counted as a reference and branches that are
+// still alive keep all of their own nodes, so only nodes exclusively owned by the deleted branch are returned.
+func GetHistoryBranchRangesToDelete(
+	branch types.HistoryBranch,
+	treeBranches []*types.HistoryBranch,
+	preserveAncestors bool,
+) []*types.HistoryBranchRange {
+
+	brsToDelete := make([]*types.HistoryBranchRange, 0, len(branch.Ancestors)+1)
+	brsToDelete = append(brsToDelete, branch.Ancestors...)
+	brsToDelete = append(brsToDelete, &types.HistoryBranchRange{
+		BranchID:    branch.BranchID,
+		BeginNodeID: common.Int64Ptr(GetBeginNodeID(branch)),
+	})
+
+	// validBRsMaxEndNode is to know each branch range that is being used, we want to know what is the max nodeID referred by other valid branch
+	validBRsMaxEndNode := map[string]int64{}
+	liveBranches := map[string]struct{}{}
+	for _, b := range treeBranches {
+		if preserveAncestors {
+			if b.GetBranchID() == branch.GetBranchID() {
+				continue
+			}
+			liveBranches[b.GetBranchID()] = struct{}{}
+		}
+		for _, br := range b.Ancestors {
+			curr, ok := validBRsMaxEndNode[*br.BranchID]
+			if !ok || curr < *br.EndNodeID {
+				validBRsMaxEndNode[*br.BranchID] = *br.EndNodeID
+			}
+		}
+	}
+
+	var ranges []*types.HistoryBranchRange
+	// for each branch range to delete, we iterate from bottom to up, and delete up to the point according to validBRsEndNode
+	for i := len(brsToDelete) - 1; i >= 0; i-- {
+		br := brsToDelete[i]
+		if _, ok := liveBranches[*br.BranchID]; ok {
+			// the range belongs to a branch that is still alive, it and everything above it must be kept
+			break
+		}
+		if maxReferredEndNodeID, ok := validBRsMaxEndNode[*br.BranchID]; ok {
+			// we can only delete from the maxEndNode and stop here
+			ranges = append(ranges, &types.HistoryBranchRange{
+				BranchID:    br.BranchID,
+				BeginNodeID: common.Int64Ptr(maxReferredEndNodeID),
+			})
+			break
+		}
+		// No any branch is using this range, we can delete all of it
+		ranges = append(ranges, &types.HistoryBranchRange{
+			BranchID:    br.BranchID,
+			BeginNodeID: br.BeginNodeID,
+		})
+	}
+	return ranges
+}
+
 // PaginateHistory return paged history
 func PaginateHistory(
 	ctx context.Context,
@@ -0,0 +1,189 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/types"
+)
+
+type pagedHistoryManager struct {
+	HistoryManager
+	pages    [][]*types.HistoryEvent
+	requests []ReadHistoryBranchRequest
+}
+
+func (m *pagedHistoryManager) ReadHistoryBranch(
+	_ context.Context,
+	request *ReadHistoryBranchRequest,
+) (*ReadHistoryBranchResponse, error) {
+	m.requests = append(m.requests, *request)
+	page := 0
+	if len(request.NextPageToken) > 0 {
+		page, _ = strconv.Atoi(string(request.NextPageToken))
+	}
+	resp := &ReadHistoryBranchResponse{HistoryEvents: m.pages[page]}
+	if page+1 < len(m.pages) {
+		resp.NextPageToken = []byte(strconv.Itoa(page + 1))
+	}
+	return resp, nil
+}
+
+func TestReadHistoryBranchIterator(t *testing.T) {
+	newManager := func() *pagedHistoryManager {
+		return &pagedHistoryManager{
+			pages: [][]*types.HistoryEvent{
+				{{EventID: 1}, {EventID: 2}},
+				{{EventID: 3}},
+				{{EventID: 4}, {EventID: 5}},
+			},
+		}
+	}
+	request := &ReadHistoryBranchRequest{MinEventID: 1, MaxEventID: 6, PageSize: 2}
+
+	mgr := newManager()
+	var eventIDs []int64
+	err := ReadHistoryBranchIterator(context.Background(), mgr, request, func(event *types.HistoryEvent) error {
+		eventIDs = append(eventIDs, event.EventID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, eventIDs)
+	assert.Len(t, mgr.requests, 3)
+	for _, req := range mgr.requests {
+		assert.Equal(t, int64(1), req.MinEventID)
+		assert.Equal(t, int64(6), req.MaxEventID)
+	}
+	assert.Nil(t, request.NextPageToken)
+
+	mgr = newManager()
+	stopErr := errors.New("stop")
+	eventIDs = nil
+	err = ReadHistoryBranchIterator(context.Background(), mgr, request, func(event *types.HistoryEvent) error {
+		eventIDs = append(eventIDs, event.EventID)
+		if event.EventID == 3 {
+			return stopErr
+		}
+		return nil
+	})
+	assert.Equal(t, stopErr, err)
+	assert.Equal(t, []int64{1, 2, 3}, eventIDs)
+	assert.Len(t, mgr.requests, 2)
+}
+
+func TestGetHistoryBranchRangesToDelete(t *testing.T) {
+	ancestor := func(branchID string, begin, end int64) *types.HistoryBranchRange {
+		return &types.HistoryBranchRange{
+			BranchID:    common.StringPtr(branchID),
+			BeginNodeID: common.Int64Ptr(begin),
+			EndNodeID:   common.Int64Ptr(end),
+		}
+	}
+	toDelete := func(branchID string, minNodeID int64) *types.HistoryBranchRange {
+		return &types.HistoryBranchRange{
+			BranchID:    common.StringPtr(branchID),
+			BeginNodeID: common.Int64Ptr(minNodeID),
+		}
+	}
+	newBranch := func(branchID string, ancestors ...*types.HistoryBranchRange) *types.HistoryBranch {
+		return &types.HistoryBranch{
+			TreeID:    common.StringPtr("tree"),
+			BranchID:  common.StringPtr(branchID),
+			Ancestors: ancestors,
+		}
+	}
+
+	// b1 is the root, b2 forks from b1 at 6, b3 forks from b2 at 8 and b4 forks from b1 at 4
+	b1 := newBranch("b1")
+	b2 := newBranch("b2", ancestor("b1", 1, 6))
+	b3 := newBranch("b3", ancestor("b1", 1, 6), ancestor("b2", 6, 8))
+	b4 := newBranch("b4", ancestor("b1", 1, 4))
+
+	tests := []struct {
+		name              string
+		branch            *types.HistoryBranch
+		treeBranches      []*types.HistoryBranch
+		preserveAncestors bool
+		expected          []*types.HistoryBranchRange
+	}{
+		{
+			name:         "single root branch",
+			branch:       b1,
+			treeBranches: []*types.HistoryBranch{b1},
+			expected:     []*types.HistoryBranchRange{toDelete("b1", 1)},
+		},
+		{
+			name:              "single root branch preserving ancestors",
+			branch:            b1,
+			treeBranches:      []*types.HistoryBranch{b1},
+			preserveAncestors: true,
+			expected:          []*types.HistoryBranchRange{toDelete("b1", 1)},
+		},
+		{
+			name:         "live ancestor branch is trimmed by default",
+			branch:       b2,
+			treeBranches: []*types.HistoryBranch{b1, b2},
+			expected:     []*types.HistoryBranchRange{toDelete("b2", 6), toDelete("b1", 6)},
+		},
+		{
+			name:              "live ancestor branch is kept when preserving ancestors",
+			branch:            b2,
+			treeBranches:      []*types.HistoryBranch{b1, b2},
+			preserveAncestors: true,
+			expected:          []*types.HistoryBranchRange{toDelete("b2", 6)},
+		},
+		{
+			name:              "nodes referred by a child branch are kept",
+			branch:            b2,
+			treeBranches:      []*types.HistoryBranch{b2, b3},
+			preserveAncestors: true,
+			expected:          []*types.HistoryBranchRange{toDelete("b2", 8)},
+		},
+		{
+			name:              "shared ancestor is only trimmed past the sibling",
+			branch:            b4,
+			treeBranches:      []*types.HistoryBranch{b2, b4},
+			preserveAncestors: true,
+			expected:          []*types.HistoryBranchRange{toDelete("b4", 4), toDelete("b1", 6)},
+		},
+		{
+			name:              "last branch of the tree removes all ancestors",
+			branch:            b3,
+			treeBranches:      []*types.HistoryBranch{b3},
+			preserveAncestors: true,
+			expected:          []*types.HistoryBranchRange{toDelete("b3", 8), toDelete("b2", 6), toDelete("b1", 1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges := GetHistoryBranchRangesToDelete(*tt.branch, tt.treeBranches, tt.preserveAncestors)
+			assert.Equal(t, tt.expected, ranges)
+		})
+	}
+}
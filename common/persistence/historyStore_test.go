@@ -0,0 +1,79 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/types"
+)
+
+func TestAppendHistoryNodesTTL(t *testing.T) {
+	branchToken, err := NewHistoryBranchToken("tree-id")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		ttlSeconds int32
+		expectErr  bool
+	}{
+		"no TTL":       {ttlSeconds: 0},
+		"positive TTL": {ttlSeconds: 3600},
+		"negative TTL": {ttlSeconds: -1, expectErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := NewMockHistoryStore(ctrl)
+			if !test.expectErr {
+				store.EXPECT().AppendHistoryNodes(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, request *InternalAppendHistoryNodesRequest) error {
+						assert.Equal(t, test.ttlSeconds, request.TTLSeconds)
+						return nil
+					},
+				).Times(1)
+			}
+			manager := NewHistoryV2ManagerImpl(store, loggerimpl.NewNopLogger(), dynamicconfig.GetIntPropertyFn(1024*1024))
+
+			_, err := manager.AppendHistoryNodes(context.Background(), &AppendHistoryNodesRequest{
+				BranchToken: branchToken,
+				Events:      []*types.HistoryEvent{{EventID: 1, Version: 1, EventType: types.EventTypeWorkflowExecutionStarted.Ptr()}},
+				ShardID:     common.IntPtr(1),
+				TTLSeconds:  test.ttlSeconds,
+			})
+			if test.expectErr {
+				assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
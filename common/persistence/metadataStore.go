@@ -22,32 +22,56 @@ package persistence
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/types"
 )
 
+const (
+	// DefaultMinDomainRetentionDays is the default lower bound of the domain retention enforced by the MetadataManager
+	DefaultMinDomainRetentionDays = 1
+	// DefaultMaxDomainRetentionDays is the default upper bound of the domain retention enforced by the MetadataManager,
+	// it is looser than the bound of the frontend as the persistence layer only guards against misconfigured domains
+	DefaultMaxDomainRetentionDays = 90
+)
+
 type (
 
 	// metadataManagerImpl implements MetadataManager based on MetadataStore and PayloadSerializer
 	metadataManagerImpl struct {
-		serializer  PayloadSerializer
-		persistence MetadataStore
-		logger      log.Logger
+		metadataManagerCompositeOperations
+
+		serializer       PayloadSerializer
+		persistence      MetadataStore
+		logger           log.Logger
+		minRetentionDays dynamicconfig.IntPropertyFn
+		maxRetentionDays dynamicconfig.IntPropertyFn
 	}
 )
 
 var _ MetadataManager = (*metadataManagerImpl)(nil)
 
-//NewMetadataManagerImpl returns new MetadataManager
-func NewMetadataManagerImpl(persistence MetadataStore, logger log.Logger) MetadataManager {
-	return &metadataManagerImpl{
-		serializer:  NewPayloadSerializer(),
-		persistence: persistence,
-		logger:      logger,
+//NewMetadataManagerImpl returns new MetadataManager, domains with a retention outside of
+// [minRetentionDays, maxRetentionDays] are rejected unless the bounds are nil
+func NewMetadataManagerImpl(
+	persistence MetadataStore,
+	logger log.Logger,
+	minRetentionDays dynamicconfig.IntPropertyFn,
+	maxRetentionDays dynamicconfig.IntPropertyFn,
+) MetadataManager {
+	m := &metadataManagerImpl{
+		serializer:       NewPayloadSerializer(),
+		persistence:      persistence,
+		logger:           logger,
+		minRetentionDays: minRetentionDays,
+		maxRetentionDays: maxRetentionDays,
 	}
+	m.metadataManagerCompositeOperations = metadataManagerCompositeOperations{manager: m}
+	return m
 }
 
 func (m *metadataManagerImpl) GetName() string {
@@ -58,6 +82,16 @@ func (m *metadataManagerImpl) CreateDomain(
 	ctx context.Context,
 	request *CreateDomainRequest,
 ) (*CreateDomainResponse, error) {
+	if !request.SkipRetentionValidation {
+		if err := m.validateRetention("CreateDomain", request.Config); err != nil {
+			return nil, err
+		}
+	}
+	if request.ReplicationConfig != nil {
+		if err := request.ReplicationConfig.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	dc, err := m.toInternalDomainConfig(request.Config)
 	if err != nil {
 		return nil, err
@@ -102,13 +136,44 @@ func (m *metadataManagerImpl) GetDomain(
 	if internalResp.FailoverEndTime != nil {
 		resp.FailoverEndTime = common.Int64Ptr(internalResp.FailoverEndTime.UnixNano())
 	}
+	if internalResp.DeletionTime != nil {
+		resp.DeletionTime = common.Int64Ptr(internalResp.DeletionTime.UnixNano())
+	}
 	return resp, nil
 }
 
+func (m *metadataManagerImpl) GetDomainConfigVersion(
+	ctx context.Context,
+	request *GetDomainConfigVersionRequest,
+) (*GetDomainConfigVersionResponse, error) {
+	return m.persistence.GetDomainConfigVersion(ctx, request)
+}
+
 func (m *metadataManagerImpl) UpdateDomain(
 	ctx context.Context,
 	request *UpdateDomainRequest,
 ) error {
+	if !request.SkipRetentionValidation {
+		if err := m.validateRetention("UpdateDomain", request.Config); err != nil {
+			// domains written before the bounds were set or changed can still be updated
+			// as long as their retention is left unchanged
+			if request.Info == nil {
+				return err
+			}
+			current, getErr := m.GetDomain(ctx, &GetDomainRequest{ID: request.Info.ID})
+			if getErr != nil {
+				return getErr
+			}
+			if current.Config == nil || current.Config.Retention != request.Config.Retention {
+				return err
+			}
+		}
+	}
+	if request.ReplicationConfig != nil {
+		if err := request.ReplicationConfig.Validate(); err != nil {
+			return err
+		}
+	}
 	dc, err := m.toInternalDomainConfig(request.Config)
 	if err != nil {
 		return err
@@ -127,9 +192,44 @@ func (m *metadataManagerImpl) UpdateDomain(
 	if request.FailoverEndTime != nil {
 		internalReq.FailoverEndTime = common.TimePtr(time.Unix(0, *request.FailoverEndTime))
 	}
+	if request.DeletionTime != nil {
+		internalReq.DeletionTime = common.TimePtr(time.Unix(0, *request.DeletionTime))
+	}
 	return m.persistence.UpdateDomain(ctx, internalReq)
 }
 
+// MarkDomainForDeletion soft deletes a domain by setting its status to deleted and recording
+// the deletion time, the domain records are only removed later by DeleteDomain and DeleteDomainByName
+func (m *metadataManagerImpl) MarkDomainForDeletion(
+	ctx context.Context,
+	request *MarkDomainForDeletionRequest,
+) error {
+	// the notification version needs to be read before the domain as it guards the conditional update
+	metadata, err := m.GetMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := m.GetDomain(ctx, &GetDomainRequest{Name: request.Name})
+	if err != nil {
+		return err
+	}
+
+	resp.Info.Status = DomainStatusDeleted
+	return m.UpdateDomain(ctx, &UpdateDomainRequest{
+		Info:                        resp.Info,
+		Config:                      resp.Config,
+		ReplicationConfig:           resp.ReplicationConfig,
+		ConfigVersion:               resp.ConfigVersion + 1,
+		FailoverVersion:             resp.FailoverVersion,
+		FailoverNotificationVersion: resp.FailoverNotificationVersion,
+		PreviousFailoverVersion:     resp.PreviousFailoverVersion,
+		FailoverEndTime:             resp.FailoverEndTime,
+		LastUpdatedTime:             request.DeletionTime,
+		NotificationVersion:         metadata.NotificationVersion,
+		DeletionTime:                common.Int64Ptr(request.DeletionTime),
+	})
+}
+
 func (m *metadataManagerImpl) DeleteDomain(
 	ctx context.Context,
 	request *DeleteDomainRequest,
@@ -154,6 +254,12 @@ func (m *metadataManagerImpl) ListDomains(
 	}
 	domains := make([]*GetDomainResponse, 0, len(resp.Domains))
 	for _, d := range resp.Domains {
+		if request.ExcludeDeleted && d.Info.Status == DomainStatusDeleted {
+			continue
+		}
+		if request.MinNotificationVersion != nil && d.NotificationVersion <= *request.MinNotificationVersion {
+			continue
+		}
 		dc, err := m.fromInternalDomainConfig(d.Config)
 		if err != nil {
 			return nil, err
@@ -172,6 +278,9 @@ func (m *metadataManagerImpl) ListDomains(
 		if d.FailoverEndTime != nil {
 			currResp.FailoverEndTime = common.Int64Ptr(d.FailoverEndTime.UnixNano())
 		}
+		if d.DeletionTime != nil {
+			currResp.DeletionTime = common.Int64Ptr(d.DeletionTime.UnixNano())
+		}
 		domains = append(domains, currResp)
 	}
 	return &ListDomainsResponse{
@@ -180,6 +289,21 @@ func (m *metadataManagerImpl) ListDomains(
 	}, nil
 }
 
+func (m *metadataManagerImpl) validateRetention(operation string, c *DomainConfig) error {
+	if c == nil || m.minRetentionDays == nil || m.maxRetentionDays == nil {
+		return nil
+	}
+	minRetentionDays := int32(m.minRetentionDays())
+	maxRetentionDays := int32(m.maxRetentionDays())
+	if c.Retention < minRetentionDays || c.Retention > maxRetentionDays {
+		return &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("%v: retention of %v days is outside of the allowed range [%v, %v]",
+				operation, c.Retention, minRetentionDays, maxRetentionDays),
+		}
+	}
+	return nil
+}
+
 func (m *metadataManagerImpl) toInternalDomainConfig(c *DomainConfig) (InternalDomainConfig, error) {
 	if c == nil {
 		return InternalDomainConfig{}, nil
@@ -233,3 +357,8 @@ func (m *metadataManagerImpl) GetMetadata(
 func (m *metadataManagerImpl) Close() {
 	m.persistence.Close()
 }
+
+// CloseWithContext closes the metadata store within the deadline of ctx
+func (m *metadataManagerImpl) CloseWithContext(ctx context.Context) error {
+	return CloseWithContext(ctx, m.persistence)
+}
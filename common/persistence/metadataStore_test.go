@@ -0,0 +1,173 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+)
+
+func newTestMetadataManager(store MetadataStore) MetadataManager {
+	return NewMetadataManagerImpl(
+		store,
+		loggerimpl.NewNopLogger(),
+		dynamicconfig.GetIntPropertyFn(DefaultMinDomainRetentionDays),
+		dynamicconfig.GetIntPropertyFn(DefaultMaxDomainRetentionDays),
+	)
+}
+
+func TestMetadataManagerCreateDomainRetention(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	store := NewMockMetadataStore(controller)
+	manager := newTestMetadataManager(store)
+
+	for _, retention := range []int32{0, 91} {
+		_, err := manager.CreateDomain(context.Background(), &CreateDomainRequest{
+			Info:   &DomainInfo{ID: "id", Name: "name"},
+			Config: &DomainConfig{Retention: retention},
+		})
+		assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+	}
+
+	// replicated domains were validated by the source cluster
+	store.EXPECT().CreateDomain(gomock.Any(), gomock.Any()).Return(&CreateDomainResponse{ID: "id"}, nil).Times(1)
+	_, err := manager.CreateDomain(context.Background(), &CreateDomainRequest{
+		Info:                    &DomainInfo{ID: "id", Name: "name"},
+		Config:                  &DomainConfig{Retention: 91},
+		SkipRetentionValidation: true,
+	})
+	assert.NoError(t, err)
+
+	// bounds which are not set are not enforced
+	store.EXPECT().CreateDomain(gomock.Any(), gomock.Any()).Return(&CreateDomainResponse{ID: "id"}, nil).Times(1)
+	_, err = NewMetadataManagerImpl(store, loggerimpl.NewNopLogger(), nil, nil).CreateDomain(
+		context.Background(),
+		&CreateDomainRequest{
+			Info:   &DomainInfo{ID: "id", Name: "name"},
+			Config: &DomainConfig{Retention: 0},
+		},
+	)
+	assert.NoError(t, err)
+}
+
+func TestMetadataManagerUpdateDomainRetention(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	store := NewMockMetadataStore(controller)
+	manager := newTestMetadataManager(store)
+
+	// the stored domain predates the bounds
+	store.EXPECT().GetDomain(gomock.Any(), &GetDomainRequest{ID: "id"}).Return(&InternalGetDomainResponse{
+		Info:   &DomainInfo{ID: "id", Name: "name"},
+		Config: &InternalDomainConfig{Retention: common.DaysToDuration(120)},
+	}, nil).Times(2)
+
+	// the retention is left unchanged
+	store.EXPECT().UpdateDomain(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	err := manager.UpdateDomain(context.Background(), &UpdateDomainRequest{
+		Info:   &DomainInfo{ID: "id", Name: "name"},
+		Config: &DomainConfig{Retention: 120},
+	})
+	require.NoError(t, err)
+
+	// the retention is changed to another invalid value
+	err = manager.UpdateDomain(context.Background(), &UpdateDomainRequest{
+		Info:   &DomainInfo{ID: "id", Name: "name"},
+		Config: &DomainConfig{Retention: 100},
+	})
+	assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+
+	// a valid retention does not read the stored domain
+	store.EXPECT().UpdateDomain(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	err = manager.UpdateDomain(context.Background(), &UpdateDomainRequest{
+		Info:   &DomainInfo{ID: "id", Name: "name"},
+		Config: &DomainConfig{Retention: 30},
+	})
+	assert.NoError(t, err)
+}
+
+func TestMetadataManagerMarkDomainForDeletion(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	store := NewMockMetadataStore(controller)
+	manager := newTestMetadataManager(store)
+
+	deletionTime := time.Unix(0, 1000)
+	store.EXPECT().GetMetadata(gomock.Any()).Return(&GetMetadataResponse{NotificationVersion: 5}, nil).Times(1)
+	store.EXPECT().GetDomain(gomock.Any(), &GetDomainRequest{Name: "name"}).Return(&InternalGetDomainResponse{
+		Info:          &DomainInfo{ID: "id", Name: "name", Status: DomainStatusRegistered},
+		Config:        &InternalDomainConfig{Retention: common.DaysToDuration(7)},
+		ConfigVersion: 2,
+	}, nil).Times(1)
+	store.EXPECT().UpdateDomain(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *InternalUpdateDomainRequest) error {
+			assert.Equal(t, DomainStatusDeleted, request.Info.Status)
+			assert.Equal(t, int64(3), request.ConfigVersion)
+			assert.Equal(t, int64(5), request.NotificationVersion)
+			require.NotNil(t, request.DeletionTime)
+			assert.True(t, deletionTime.Equal(*request.DeletionTime))
+			return nil
+		},
+	).Times(1)
+
+	err := manager.MarkDomainForDeletion(context.Background(), &MarkDomainForDeletionRequest{
+		Name:         "name",
+		DeletionTime: deletionTime.UnixNano(),
+	})
+	assert.NoError(t, err)
+}
+
+func TestMetadataManagerListDomainsExcludeDeleted(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	store := NewMockMetadataStore(controller)
+	manager := newTestMetadataManager(store)
+
+	deletionTime := time.Unix(0, 1000)
+	store.EXPECT().ListDomains(gomock.Any(), gomock.Any()).Return(&InternalListDomainsResponse{
+		Domains: []*InternalGetDomainResponse{
+			{Info: &DomainInfo{ID: "registered", Status: DomainStatusRegistered}},
+			{Info: &DomainInfo{ID: "deleted", Status: DomainStatusDeleted}, DeletionTime: &deletionTime},
+		},
+	}, nil).Times(2)
+
+	// deleted domains are returned unless they are explicitly excluded
+	resp, err := manager.ListDomains(context.Background(), &ListDomainsRequest{PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, resp.Domains, 2)
+	assert.Equal(t, "deleted", resp.Domains[1].Info.ID)
+	assert.Equal(t, common.Int64Ptr(deletionTime.UnixNano()), resp.Domains[1].DeletionTime)
+
+	resp, err = manager.ListDomains(context.Background(), &ListDomainsRequest{PageSize: 10, ExcludeDeleted: true})
+	require.NoError(t, err)
+	require.Len(t, resp.Domains, 1)
+	assert.Equal(t, "registered", resp.Domains[0].Info.ID)
+}
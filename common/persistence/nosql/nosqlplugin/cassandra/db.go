@@ -21,10 +21,14 @@
 package cassandra
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra/gocql"
 )
@@ -38,6 +42,10 @@ type cdb struct {
 	logger  log.Logger
 	client  gocql.Client
 	session gocql.Session
+	// scanTimeout bounds each page fetched by iterator-style queries, it is 0 if ScanQueryTimeout is not configured
+	scanTimeout time.Duration
+	// pointTimeout bounds point operations, it is 0 unless the session timeout is raised to cover scanTimeout
+	pointTimeout time.Duration
 }
 
 var _ nosqlplugin.DB = (*cdb)(nil)
@@ -52,16 +60,22 @@ func NewCassandraDBFromSession(client gocql.Client, session gocql.Session, logge
 }
 
 // NewCassandraDB return a new DB
-func NewCassandraDB(cfg config.Cassandra, logger log.Logger) (nosqlplugin.DB, error) {
-	session, err := CreateSession(cfg)
+func NewCassandraDB(cfg config.Cassandra, logger log.Logger, metricsClient metrics.Client) (nosqlplugin.DB, error) {
+	timeout := sessionTimeout(cfg)
+	session, err := createSessionWithMetrics(context.Background(), cfg, logger, metricsClient, timeout)
 	if err != nil {
 		return nil, err
 	}
-	return &cdb{
-		client:  cfg.CQLClient,
-		session: session,
-		logger:  logger,
-	}, nil
+	db := &cdb{
+		client:      cfg.CQLClient,
+		session:     session,
+		scanTimeout: cfg.ScanQueryTimeout,
+		logger:      logger,
+	}
+	if timeout > defaultSessionTimeout {
+		db.pointTimeout = defaultSessionTimeout
+	}
+	return db, nil
 }
 
 func (db *cdb) Close() {
@@ -70,6 +84,29 @@ func (db *cdb) Close() {
 	}
 }
 
+// CloseWithContext closes the session in the background, as gocql can only close it synchronously,
+// the session keeps closing after ctx is done
+func (db *cdb) CloseWithContext(ctx context.Context) error {
+	return persistence.WaitForClose(ctx, db.Close)
+}
+
+// scanContext bounds an iterator-style read, which may page through a large number of rows, by ScanQueryTimeout
+func (db *cdb) scanContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, db.scanTimeout)
+}
+
+// pointContext bounds a point operation by the default timeout, in case the session timeout is raised for scans
+func (db *cdb) pointContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, db.pointTimeout)
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 func (db *cdb) PluginName() string {
 	return PluginName
 }
@@ -86,6 +123,10 @@ func (db *cdb) IsThrottlingError(err error) bool {
 	return db.client.IsThrottlingError(err)
 }
 
+func (db *cdb) WriteTimeoutType(err error) (string, bool) {
+	return db.client.WriteTimeoutType(err)
+}
+
 func (db *cdb) IsConditionFailedError(err error) bool {
 	if err == errConditionFailed {
 		return true
@@ -37,6 +37,7 @@ const (
 	constDomainPartition     = 0
 	domainMetadataRecordName = "cadence-domain-metadata"
 	emptyFailoverEndTime     = int64(0)
+	emptyDeletionTime        = int64(0)
 )
 
 const (
@@ -96,7 +97,13 @@ const (
 		`previous_failover_version, ` +
 		`failover_end_time, ` +
 		`last_updated_time, ` +
-		`notification_version ` +
+		`notification_version, ` +
+		`deletion_time ` +
+		`FROM domains_by_name_v2 ` +
+		`WHERE domains_partition = ? ` +
+		`and name = ?`
+
+	templateGetDomainConfigVersionByNameQueryV2 = `SELECT config_version, failover_version ` +
 		`FROM domains_by_name_v2 ` +
 		`WHERE domains_partition = ? ` +
 		`and name = ?`
@@ -111,7 +118,8 @@ const (
 		`previous_failover_version = ? , ` +
 		`failover_end_time = ?,` +
 		`last_updated_time = ?,` +
-		`notification_version = ? ` +
+		`notification_version = ?, ` +
+		`deletion_time = ? ` +
 		`WHERE domains_partition = ? ` +
 		`and name = ?`
 
@@ -144,7 +152,8 @@ const (
 		`previous_failover_version, ` +
 		`failover_end_time, ` +
 		`last_updated_time, ` +
-		`notification_version ` +
+		`notification_version, ` +
+		`deletion_time ` +
 		`FROM domains_by_name_v2 ` +
 		`WHERE domains_partition = ? `
 )
@@ -155,6 +164,8 @@ func (db *cdb) InsertDomain(
 	ctx context.Context,
 	row *nosqlplugin.DomainRow,
 ) error {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
 	query := db.session.Query(templateCreateDomainQuery, row.Info.ID, row.Info.Name).WithContext(ctx)
 	applied, err := query.MapScanCAS(make(map[string]interface{}))
 	if err != nil {
@@ -220,7 +231,7 @@ func (db *cdb) InsertDomain(
 
 	if !applied {
 		// Domain already exist.  Delete orphan domain record before returning back to user
-		if errDelete := db.session.Query(templateDeleteDomainQuery, row.Info.ID).WithContext(ctx).Exec(); errDelete != nil {
+		if errDelete := db.session.Query(templateDeleteDomainQuery, row.Info.ID).Idempotent(true).WithContext(ctx).Exec(); errDelete != nil {
 			db.logger.Warn("Unable to delete orphan domain record. Error", tag.Error(errDelete))
 		}
 
@@ -270,11 +281,17 @@ func (db *cdb) UpdateDomain(
 	ctx context.Context,
 	row *nosqlplugin.DomainRow,
 ) error {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
 	batch := db.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
 	failoverEndTime := emptyFailoverEndTime
 	if row.FailoverEndTime != nil {
 		failoverEndTime = row.FailoverEndTime.UnixNano()
 	}
+	deletionTime := emptyDeletionTime
+	if row.DeletionTime != nil {
+		deletionTime = row.DeletionTime.UnixNano()
+	}
 	batch.Query(templateUpdateDomainByNameQueryWithinBatchV2,
 		row.Info.ID,
 		row.Info.Name,
@@ -301,6 +318,7 @@ func (db *cdb) UpdateDomain(
 		failoverEndTime,
 		row.LastUpdatedTime.UnixNano(),
 		row.NotificationVersion,
+		deletionTime,
 		constDomainPartition,
 		row.Info.Name,
 	)
@@ -329,6 +347,8 @@ func (db *cdb) SelectDomain(
 	domainID *string,
 	domainName *string,
 ) (*nosqlplugin.DomainRow, error) {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
 	if domainID != nil && domainName != nil {
 		return nil, fmt.Errorf("GetDomain operation failed.  Both ID and Name specified in request")
 	} else if domainID == nil && domainName == nil {
@@ -360,6 +380,7 @@ func (db *cdb) SelectDomain(
 	var previousFailoverVersion int64
 	var failoverEndTime int64
 	var lastUpdatedTime int64
+	var deletionTime int64
 	var configVersion int64
 	var isGlobalDomain bool
 	var retentionDays int32
@@ -392,6 +413,7 @@ func (db *cdb) SelectDomain(
 		&failoverEndTime,
 		&lastUpdatedTime,
 		&notificationVersion,
+		&deletionTime,
 	)
 
 	if err != nil {
@@ -417,16 +439,51 @@ func (db *cdb) SelectDomain(
 	if failoverEndTime > emptyFailoverEndTime {
 		dr.FailoverEndTime = common.TimePtr(time.Unix(0, failoverEndTime))
 	}
+	if deletionTime > emptyDeletionTime {
+		dr.DeletionTime = common.TimePtr(time.Unix(0, deletionTime))
+	}
 
 	return dr, nil
 }
 
+// Get only the config and failover version of one domain, either by domainID or domainName
+func (db *cdb) SelectDomainConfigVersion(
+	ctx context.Context,
+	domainID *string,
+	domainName *string,
+) (int64, int64, error) {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
+	if domainID != nil && domainName != nil {
+		return 0, 0, fmt.Errorf("GetDomainConfigVersion operation failed.  Both ID and Name specified in request")
+	} else if domainID == nil && domainName == nil {
+		return 0, 0, fmt.Errorf("GetDomainConfigVersion operation failed.  Both ID and Name are empty")
+	}
+
+	if domainID != nil {
+		query := db.session.Query(templateGetDomainQuery, domainID).WithContext(ctx)
+		if err := query.Scan(&domainName); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	var configVersion int64
+	var failoverVersion int64
+	query := db.session.Query(templateGetDomainConfigVersionByNameQueryV2, constDomainPartition, domainName).WithContext(ctx)
+	if err := query.Scan(&configVersion, &failoverVersion); err != nil {
+		return 0, 0, err
+	}
+	return configVersion, failoverVersion, nil
+}
+
 // Get all domain data
 func (db *cdb) SelectAllDomains(
 	ctx context.Context,
 	pageSize int,
 	pageToken []byte,
 ) ([]*nosqlplugin.DomainRow, []byte, error) {
+	ctx, cancel := db.scanContext(ctx)
+	defer cancel()
 	var query gocql.Query
 	query = db.session.Query(templateListDomainQueryV2, constDomainPartition).WithContext(ctx)
 	iter := query.PageSize(pageSize).PageState(pageToken).Iter()
@@ -448,6 +505,7 @@ func (db *cdb) SelectAllDomains(
 	var retentionDays int32
 	var failoverEndTime int64
 	var lastUpdateTime int64
+	var deletionTime int64
 	var rows []*nosqlplugin.DomainRow
 	for iter.Scan(
 		&name,
@@ -477,6 +535,7 @@ func (db *cdb) SelectAllDomains(
 		&failoverEndTime,
 		&lastUpdateTime,
 		&domain.NotificationVersion,
+		&deletionTime,
 	) {
 		if name != domainMetadataRecordName {
 			// do not include the metadata record
@@ -487,12 +546,16 @@ func (db *cdb) SelectAllDomains(
 			if failoverEndTime > emptyFailoverEndTime {
 				domain.FailoverEndTime = common.TimePtr(time.Unix(0, failoverEndTime))
 			}
+			if deletionTime > emptyDeletionTime {
+				domain.DeletionTime = common.TimePtr(time.Unix(0, deletionTime))
+			}
 			rows = append(rows, domain)
 		}
 		replicationClusters = []map[string]interface{}{}
 		badBinariesData = []byte("")
 		badBinariesDataEncoding = ""
 		failoverEndTime = 0
+		deletionTime = 0
 		lastUpdateTime = 0
 		retentionDays = 0
 		domain = &nosqlplugin.DomainRow{
@@ -515,6 +578,8 @@ func (db *cdb) DeleteDomain(
 	domainID *string,
 	domainName *string,
 ) error {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
 	if domainName == nil && domainID == nil {
 		return fmt.Errorf("must provide either domainID or domainName")
 	}
@@ -549,6 +614,8 @@ func (db *cdb) DeleteDomain(
 func (db *cdb) SelectDomainMetadata(
 	ctx context.Context,
 ) (int64, error) {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
 	var notificationVersion int64
 	query := db.session.Query(templateGetMetadataQueryV2, constDomainPartition, domainMetadataRecordName)
 	err := query.Scan(&notificationVersion)
@@ -568,11 +635,11 @@ func (db *cdb) deleteDomain(
 	ctx context.Context,
 	name, ID string,
 ) error {
-	query := db.session.Query(templateDeleteDomainByNameQueryV2, constDomainPartition, name).WithContext(ctx)
+	query := db.session.Query(templateDeleteDomainByNameQueryV2, constDomainPartition, name).Idempotent(true).WithContext(ctx)
 	if err := query.Exec(); err != nil {
 		return err
 	}
 
-	query = db.session.Query(templateDeleteDomainQuery, ID).WithContext(ctx)
+	query = db.session.Query(templateDeleteDomainQuery, ID).Idempotent(true).WithContext(ctx)
 	return query.Exec()
 }
@@ -39,9 +39,18 @@ const (
 		`tree_id, branch_id, node_id, txn_id, data, data_encoding) ` +
 		`VALUES (?, ?, ?, ?, ?, ?) `
 
+	v2templateUpsertDataWithTTL = v2templateUpsertData + `USING TTL ?`
+
+	v2templateInsertDataIfNotExists = v2templateUpsertData + `IF NOT EXISTS `
+
+	v2templateInsertDataIfNotExistsWithTTL = v2templateInsertDataIfNotExists + `USING TTL ?`
+
 	v2templateReadData = `SELECT node_id, txn_id, data, data_encoding FROM history_node ` +
 		`WHERE tree_id = ? AND branch_id = ? AND node_id >= ? AND node_id < ? `
 
+	v2templateReadOneData = `SELECT node_id, txn_id, data, data_encoding FROM history_node ` +
+		`WHERE tree_id = ? AND branch_id = ? AND node_id = ? LIMIT 1`
+
 	v2templateRangeDeleteData = `DELETE FROM history_node WHERE tree_id = ? AND branch_id = ? AND node_id >= ? `
 
 	// below are templates for history_tree table
@@ -58,6 +67,8 @@ const (
 
 // InsertIntoHistoryTreeAndNode inserts one or two rows: tree row and node row(at least one of them)
 func (db *cdb) InsertIntoHistoryTreeAndNode(ctx context.Context, treeRow *nosqlplugin.HistoryTreeRow, nodeRow *nosqlplugin.HistoryNodeRow) error {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
 	if treeRow == nil && nodeRow == nil {
 		return fmt.Errorf("require at least a tree row or a node row to insert")
 	}
@@ -72,24 +83,29 @@ func (db *cdb) InsertIntoHistoryTreeAndNode(ctx context.Context, treeRow *nosqlp
 		}
 	}
 
+	if nodeRow != nil && nodeRow.FailIfExists {
+		return db.insertIntoHistoryTreeAndNodeIfNotExists(ctx, treeRow, ancs, nodeRow)
+	}
+
 	var err error
 	if treeRow != nil && nodeRow != nil {
 		// Note: for perf, prefer using batch for inserting more than one records
 		batch := db.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
 		batch.Query(v2templateInsertTree,
 			treeRow.TreeID, treeRow.BranchID, ancs, p.UnixNanoToDBTimestamp(treeRow.CreateTimestamp.UnixNano()), treeRow.Info)
-		batch.Query(v2templateUpsertData,
-			nodeRow.TreeID, nodeRow.BranchID, nodeRow.NodeID, nodeRow.TxnID, nodeRow.Data, nodeRow.DataEncoding)
+		stmt, values := upsertHistoryNodeQuery(nodeRow)
+		batch.Query(stmt, values...)
 		err = db.session.ExecuteBatch(batch)
 	} else {
 		var query gocql.Query
 		if treeRow != nil {
 			query = db.session.Query(v2templateInsertTree,
-				treeRow.TreeID, treeRow.BranchID, ancs, p.UnixNanoToDBTimestamp(treeRow.CreateTimestamp.UnixNano()), treeRow.Info).WithContext(ctx)
+				treeRow.TreeID, treeRow.BranchID, ancs, p.UnixNanoToDBTimestamp(treeRow.CreateTimestamp.UnixNano()), treeRow.Info).Idempotent(true).WithContext(ctx)
 		}
 		if nodeRow != nil {
-			query = db.session.Query(v2templateUpsertData,
-				nodeRow.TreeID, nodeRow.BranchID, nodeRow.NodeID, nodeRow.TxnID, nodeRow.Data, nodeRow.DataEncoding).WithContext(ctx)
+			stmt, values := upsertHistoryNodeQuery(nodeRow)
+			// a node with a TTL is not idempotent, a late duplicate of the write would restart the TTL
+			query = db.session.Query(stmt, values...).Idempotent(nodeRow.TTLSeconds == 0).WithContext(ctx)
 		}
 		err = query.Exec()
 	}
@@ -97,8 +113,63 @@ func (db *cdb) InsertIntoHistoryTreeAndNode(ctx context.Context, treeRow *nosqlp
 	return err
 }
 
+// insertIntoHistoryTreeAndNodeIfNotExists inserts the node row only if the node does not exist yet.
+// A conditional batch cannot span the history_tree and history_node tables, and IF NOT EXISTS only covers
+// the TxnID of the row, so the node is first checked for any transaction and the tree row is written on its own.
+func (db *cdb) insertIntoHistoryTreeAndNodeIfNotExists(
+	ctx context.Context,
+	treeRow *nosqlplugin.HistoryTreeRow,
+	ancs []map[string]interface{},
+	nodeRow *nosqlplugin.HistoryNodeRow,
+) error {
+	_, err := db.SelectOneFromHistoryNode(ctx, nodeRow.TreeID, nodeRow.BranchID, nodeRow.NodeID)
+	if err == nil {
+		return errConditionFailed
+	}
+	if !db.IsNotFoundError(err) {
+		return err
+	}
+
+	if treeRow != nil {
+		query := db.session.Query(v2templateInsertTree,
+			treeRow.TreeID, treeRow.BranchID, ancs, p.UnixNanoToDBTimestamp(treeRow.CreateTimestamp.UnixNano()), treeRow.Info).Idempotent(true).WithContext(ctx)
+		if err := query.Exec(); err != nil {
+			return err
+		}
+	}
+
+	stmt, values := upsertHistoryNodeQuery(nodeRow)
+	previous := make(map[string]interface{})
+	applied, err := db.session.Query(stmt, values...).WithContext(ctx).MapScanCAS(previous)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return errConditionFailed
+	}
+	return nil
+}
+
+// upsertHistoryNodeQuery returns the statement and values inserting the node row, with a TTL if the row has one
+// and conditioned on the row not existing if the row has FailIfExists set
+func upsertHistoryNodeQuery(nodeRow *nosqlplugin.HistoryNodeRow) (string, []interface{}) {
+	values := []interface{}{nodeRow.TreeID, nodeRow.BranchID, nodeRow.NodeID, nodeRow.TxnID, nodeRow.Data, nodeRow.DataEncoding}
+	if nodeRow.FailIfExists {
+		if nodeRow.TTLSeconds > 0 {
+			return v2templateInsertDataIfNotExistsWithTTL, append(values, nodeRow.TTLSeconds)
+		}
+		return v2templateInsertDataIfNotExists, values
+	}
+	if nodeRow.TTLSeconds > 0 {
+		return v2templateUpsertDataWithTTL, append(values, nodeRow.TTLSeconds)
+	}
+	return v2templateUpsertData, values
+}
+
 // SelectFromHistoryNode read nodes based on a filter
 func (db *cdb) SelectFromHistoryNode(ctx context.Context, filter *nosqlplugin.HistoryNodeFilter) ([]*nosqlplugin.HistoryNodeRow, []byte, error) {
+	ctx, cancel := db.scanContext(ctx)
+	defer cancel()
 	query := db.session.Query(v2templateReadData, filter.TreeID, filter.BranchID, filter.MinNodeID, filter.MaxNodeID).WithContext(ctx)
 
 	iter := query.PageSize(filter.PageSize).PageState(filter.NextPageToken).Iter()
@@ -121,8 +192,27 @@ func (db *cdb) SelectFromHistoryNode(ctx context.Context, filter *nosqlplugin.Hi
 	return rows, pagingToken, nil
 }
 
+// SelectOneFromHistoryNode reads the latest transaction of a single node,
+// transactions of a node are clustered by txn_id in descending order so the first row is the latest
+func (db *cdb) SelectOneFromHistoryNode(ctx context.Context, treeID string, branchID string, nodeID int64) (*nosqlplugin.HistoryNodeRow, error) {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
+	query := db.session.Query(v2templateReadOneData, treeID, branchID, nodeID).WithContext(ctx)
+
+	row := &nosqlplugin.HistoryNodeRow{
+		TreeID:   treeID,
+		BranchID: branchID,
+	}
+	if err := query.Scan(&row.NodeID, &row.TxnID, &row.Data, &row.DataEncoding); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
 // DeleteFromHistoryTreeAndNode delete a branch record, and a list of ranges of nodes.
 func (db *cdb) DeleteFromHistoryTreeAndNode(ctx context.Context, treeFilter *nosqlplugin.HistoryTreeFilter, nodeFilters []*nosqlplugin.HistoryNodeFilter) error {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
 	batch := db.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
 	batch.Query(v2templateDeleteBranch, treeFilter.TreeID, treeFilter.BranchID)
 	for _, nodeFilter := range nodeFilters {
@@ -136,6 +226,8 @@ func (db *cdb) DeleteFromHistoryTreeAndNode(ctx context.Context, treeFilter *nos
 
 // SelectAllHistoryTrees will return all tree branches with pagination
 func (db *cdb) SelectAllHistoryTrees(ctx context.Context, nextPageToken []byte, pageSize int) ([]*nosqlplugin.HistoryTreeRow, []byte, error) {
+	ctx, cancel := db.scanContext(ctx)
+	defer cancel()
 	query := db.session.Query(v2templateScanAllTreeBranches).WithContext(ctx)
 
 	iter := query.PageSize(int(pageSize)).PageState(nextPageToken).Iter()
@@ -163,46 +255,15 @@ func (db *cdb) SelectAllHistoryTrees(ctx context.Context, nextPageToken []byte,
 
 // SelectFromHistoryTree read branch records for a tree
 func (db *cdb) SelectFromHistoryTree(ctx context.Context, filter *nosqlplugin.HistoryTreeFilter) ([]*nosqlplugin.HistoryTreeRow, error) {
-	query := db.session.Query(v2templateReadAllBranches, filter.TreeID).WithContext(ctx)
 	var pagingToken []byte
-	var iter gocql.Iter
 	var rows []*nosqlplugin.HistoryTreeRow
 	for {
-		iter = query.PageSize(100).PageState(pagingToken).Iter()
-		if iter == nil {
-			return nil, &types.InternalServiceError{
-				Message: "SelectFromHistoryTree operation failed.  Not able to create query iterator.",
-			}
-		}
-		pagingToken = iter.PageState()
-
-		branchUUID := ""
-		var ancsResult []map[string]interface{}
-		// Ideally we should just use int64. But we have been using time.Time for a long time.
-		// I am not sure using a int64 will behave the same.
-		// Therefore, here still using a time.Time to read, and then convert to int64
-		createTime := time.Time{}
-		info := ""
-
-		for iter.Scan(&branchUUID, &ancsResult, &createTime, &info) {
-			ancs := parseBranchAncestors(ancsResult)
-			row := &nosqlplugin.HistoryTreeRow{
-				TreeID:    filter.TreeID,
-				BranchID:  branchUUID,
-				Ancestors: ancs,
-			}
-			rows = append(rows, row)
-
-			branchUUID = ""
-			ancsResult = []map[string]interface{}{}
-			createTime = time.Time{}
-			info = ""
-		}
-
-		if err := iter.Close(); err != nil {
+		page, nextPageToken, err := db.SelectFromHistoryTreeWithPagination(ctx, filter, pagingToken, 100)
+		if err != nil {
 			return nil, err
 		}
-
+		rows = append(rows, page...)
+		pagingToken = nextPageToken
 		if len(pagingToken) == 0 {
 			break
 		}
@@ -210,6 +271,54 @@ func (db *cdb) SelectFromHistoryTree(ctx context.Context, filter *nosqlplugin.Hi
 	return rows, nil
 }
 
+// SelectFromHistoryTreeWithPagination read one page of branch records for a tree
+func (db *cdb) SelectFromHistoryTreeWithPagination(
+	ctx context.Context,
+	filter *nosqlplugin.HistoryTreeFilter,
+	nextPageToken []byte,
+	pageSize int,
+) ([]*nosqlplugin.HistoryTreeRow, []byte, error) {
+	ctx, cancel := db.scanContext(ctx)
+	defer cancel()
+	query := db.session.Query(v2templateReadAllBranches, filter.TreeID).WithContext(ctx)
+	iter := query.PageSize(pageSize).PageState(nextPageToken).Iter()
+	if iter == nil {
+		return nil, nil, &types.InternalServiceError{
+			Message: "SelectFromHistoryTree operation failed.  Not able to create query iterator.",
+		}
+	}
+	pagingToken := iter.PageState()
+
+	branchUUID := ""
+	var ancsResult []map[string]interface{}
+	// Ideally we should just use int64. But we have been using time.Time for a long time.
+	// I am not sure using a int64 will behave the same.
+	// Therefore, here still using a time.Time to read, and then convert to int64
+	createTime := time.Time{}
+	info := ""
+
+	var rows []*nosqlplugin.HistoryTreeRow
+	for iter.Scan(&branchUUID, &ancsResult, &createTime, &info) {
+		ancs := parseBranchAncestors(ancsResult)
+		row := &nosqlplugin.HistoryTreeRow{
+			TreeID:    filter.TreeID,
+			BranchID:  branchUUID,
+			Ancestors: ancs,
+		}
+		rows = append(rows, row)
+
+		branchUUID = ""
+		ancsResult = []map[string]interface{}{}
+		createTime = time.Time{}
+		info = ""
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, nil, err
+	}
+	return rows, pagingToken, nil
+}
+
 func parseBranchAncestors(
 	ancestors []map[string]interface{},
 ) []*types.HistoryBranchRange {
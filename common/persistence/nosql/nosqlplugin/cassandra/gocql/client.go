@@ -26,6 +26,8 @@ import (
 	"strings"
 
 	"github.com/gocql/gocql"
+
+	"github.com/uber/cadence/common/log/loggerimpl"
 )
 
 var _ Client = client{}
@@ -59,8 +61,18 @@ func (c client) IsTimeoutError(err error) bool {
 	if err == gocql.ErrConnectionClosed {
 		return true
 	}
-	_, ok := err.(*gocql.RequestErrWriteTimeout)
-	return ok
+	switch err.(type) {
+	case *gocql.RequestErrWriteTimeout, *gocql.RequestErrReadTimeout:
+		return true
+	}
+	return false
+}
+
+func (c client) WriteTimeoutType(err error) (string, bool) {
+	if timeoutErr, ok := err.(*gocql.RequestErrWriteTimeout); ok {
+		return timeoutErr.WriteType, true
+	}
+	return "", false
 }
 
 func (c client) IsNotFoundError(err error) bool {
@@ -75,7 +87,7 @@ func (c client) IsThrottlingError(err error) bool {
 	return false
 }
 
-func newCassandraCluster(cfg ClusterConfig) *gocql.ClusterConfig {
+func newCassandraCluster(cfg ClusterConfig, poolStats *poolStatsObserver) (*gocql.ClusterConfig, error) {
 	hosts := parseHosts(cfg.Hosts)
 	cluster := gocql.NewCluster(hosts...)
 	cluster.ProtoVersion = 4
@@ -99,24 +111,39 @@ func newCassandraCluster(cfg ClusterConfig) *gocql.ClusterConfig {
 	}
 
 	if cfg.TLS != nil && cfg.TLS.Enabled {
+		tlsConfig := &tls.Config{
+			ServerName: cfg.TLS.ServerName,
+		}
+		if cfg.TLS.CertFile != "" && cfg.TLS.KeyFile != "" {
+			// the client certificate is loaded per connection instead of once by gocql,
+			// existing connections keep the old certificate until they are recycled
+			logger := cfg.Logger
+			if logger == nil {
+				logger = loggerimpl.NewNopLogger()
+			}
+			reloader, err := newClientCertificateReloader(cfg.TLS.CertFile, cfg.TLS.KeyFile, logger, poolStats)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.GetClientCertificate = reloader.GetClientCertificate
+		}
 		cluster.SslOpts = &gocql.SslOptions{
-			CertPath:               cfg.TLS.CertFile,
-			KeyPath:                cfg.TLS.KeyFile,
 			CaPath:                 cfg.TLS.CaFile,
 			EnableHostVerification: cfg.TLS.EnableHostVerification,
 
-			Config: &tls.Config{
-				ServerName: cfg.TLS.ServerName,
-			},
+			Config: tlsConfig,
 		}
 	}
 	if cfg.MaxConns > 0 {
 		cluster.NumConns = cfg.MaxConns
 	}
 
-	cluster.PoolConfig.HostSelectionPolicy = gocql.TokenAwareHostPolicy(gocql.RoundRobinHostPolicy())
+	cluster.PoolConfig.HostSelectionPolicy = mustConvertHostSelectionPolicy(cfg.HostSelectionPolicy, cfg.Datacenter)
+	if cfg.RetryPolicy.NumRetries > 0 {
+		cluster.RetryPolicy = newRetryPolicy(cfg.RetryPolicy, false)
+	}
 
-	return cluster
+	return cluster, nil
 }
 
 // regionHostFilter returns a gocql host filter for the given region name
@@ -0,0 +1,52 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gocql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTimeoutError(t *testing.T) {
+	c := NewClient()
+	assert.True(t, c.IsTimeoutError(context.DeadlineExceeded))
+	assert.True(t, c.IsTimeoutError(gocql.ErrTimeoutNoResponse))
+	assert.True(t, c.IsTimeoutError(&gocql.RequestErrWriteTimeout{WriteType: "SIMPLE"}))
+	assert.True(t, c.IsTimeoutError(&gocql.RequestErrReadTimeout{}))
+	assert.False(t, c.IsTimeoutError(gocql.ErrNotFound))
+	assert.False(t, c.IsTimeoutError(errors.New("some random error")))
+}
+
+func TestWriteTimeoutType(t *testing.T) {
+	c := NewClient()
+	writeType, ok := c.WriteTimeoutType(&gocql.RequestErrWriteTimeout{WriteType: "CAS"})
+	assert.True(t, ok)
+	assert.Equal(t, "CAS", writeType)
+
+	_, ok = c.WriteTimeoutType(&gocql.RequestErrReadTimeout{})
+	assert.False(t, ok)
+	_, ok = c.WriteTimeoutType(gocql.ErrTimeoutNoResponse)
+	assert.False(t, ok)
+}
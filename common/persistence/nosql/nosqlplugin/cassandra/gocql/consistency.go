@@ -22,6 +22,7 @@ package gocql
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gocql/gocql"
 )
@@ -37,6 +38,10 @@ const (
 	LocalQuorum
 	EachQuorum
 	LocalOne
+	// SerialRead and LocalSerialRead can only be used for reads,
+	// which then see the effects of all lightweight transactions
+	SerialRead
+	LocalSerialRead
 )
 
 // Definition of all SerialConsistency levels
@@ -45,6 +50,46 @@ const (
 	LocalSerial
 )
 
+// ParseConsistency converts the string representation of a consistency level, e.g. LOCAL_QUORUM,
+// to Consistency, an error is returned if the level is unknown
+func ParseConsistency(s string) (Consistency, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "ANY":
+		return Any, nil
+	case "ONE":
+		return One, nil
+	case "TWO":
+		return Two, nil
+	case "THREE":
+		return Three, nil
+	case "QUORUM":
+		return Quorum, nil
+	case "ALL":
+		return All, nil
+	case "LOCAL_QUORUM":
+		return LocalQuorum, nil
+	case "EACH_QUORUM":
+		return EachQuorum, nil
+	case "LOCAL_ONE":
+		return LocalOne, nil
+	default:
+		return LocalQuorum, fmt.Errorf("unknown gocql Consistency level: %v", s)
+	}
+}
+
+// ParseSerialConsistency converts the string representation of a serial consistency level, e.g. LOCAL_SERIAL,
+// to SerialConsistency, an error is returned if the level is unknown
+func ParseSerialConsistency(s string) (SerialConsistency, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "SERIAL":
+		return Serial, nil
+	case "LOCAL_SERIAL":
+		return LocalSerial, nil
+	default:
+		return LocalSerial, fmt.Errorf("unknown gocql SerialConsistency level: %v", s)
+	}
+}
+
 func mustConvertConsistency(c Consistency) gocql.Consistency {
 	switch c {
 	case Any:
@@ -65,6 +110,10 @@ func mustConvertConsistency(c Consistency) gocql.Consistency {
 		return gocql.EachQuorum
 	case LocalOne:
 		return gocql.LocalOne
+	case SerialRead:
+		return gocql.Consistency(gocql.Serial)
+	case LocalSerialRead:
+		return gocql.Consistency(gocql.LocalSerial)
 	default:
 		panic(fmt.Sprintf("Unknown gocql Consistency level: %v", c))
 	}
@@ -0,0 +1,83 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gocql
+
+import (
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConsistency(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    Consistency
+		expectedErr bool
+	}{
+		{input: "ONE", expected: One},
+		{input: "local_quorum", expected: LocalQuorum},
+		{input: " EACH_QUORUM ", expected: EachQuorum},
+		{input: "LOCAL_ONE", expected: LocalOne},
+		{input: "QUORUM", expected: Quorum},
+		{input: "LOCALQUORUM", expectedErr: true},
+		{input: "", expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		consistency, err := ParseConsistency(tc.input)
+		if tc.expectedErr {
+			assert.Error(t, err, tc.input)
+			continue
+		}
+		assert.NoError(t, err, tc.input)
+		assert.Equal(t, tc.expected, consistency, tc.input)
+	}
+}
+
+func TestParseSerialConsistency(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    SerialConsistency
+		expectedErr bool
+	}{
+		{input: "SERIAL", expected: Serial},
+		{input: "local_serial", expected: LocalSerial},
+		{input: "LOCAL_QUORUM", expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		serialConsistency, err := ParseSerialConsistency(tc.input)
+		if tc.expectedErr {
+			assert.Error(t, err, tc.input)
+			continue
+		}
+		assert.NoError(t, err, tc.input)
+		assert.Equal(t, tc.expected, serialConsistency, tc.input)
+	}
+}
+
+func TestConvertConsistency(t *testing.T) {
+	assert.Equal(t, gocql.LocalQuorum, mustConvertConsistency(LocalQuorum))
+	assert.Equal(t, gocql.Consistency(gocql.Serial), mustConvertConsistency(SerialRead))
+	assert.Equal(t, gocql.Consistency(gocql.LocalSerial), mustConvertConsistency(LocalSerialRead))
+	assert.Panics(t, func() { mustConvertConsistency(Consistency(100)) })
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gocql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gocql/gocql"
+)
+
+// Definition of all HostSelectionPolicy options
+const (
+	// TokenAwareDCAware routes queries to a replica owning the partition, preferring hosts in the
+	// configured datacenter, falls back to token-aware round robin over all hosts if no datacenter is configured
+	TokenAwareDCAware HostSelectionPolicy = iota
+	// RoundRobin routes queries to all known hosts in turn, regardless of token ownership or datacenter
+	RoundRobin
+)
+
+// ParseHostSelectionPolicy converts the string representation of a host selection policy, e.g. TokenAwareDCAware,
+// to HostSelectionPolicy, an error is returned if the policy is unknown
+func ParseHostSelectionPolicy(s string) (HostSelectionPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "tokenawaredcaware":
+		return TokenAwareDCAware, nil
+	case "roundrobin":
+		return RoundRobin, nil
+	default:
+		return TokenAwareDCAware, fmt.Errorf("unknown gocql HostSelectionPolicy: %v", s)
+	}
+}
+
+func mustConvertHostSelectionPolicy(p HostSelectionPolicy, datacenter string) gocql.HostSelectionPolicy {
+	switch p {
+	case TokenAwareDCAware:
+		if datacenter == "" {
+			return gocql.TokenAwareHostPolicy(gocql.RoundRobinHostPolicy())
+		}
+		return gocql.TokenAwareHostPolicy(gocql.DCAwareRoundRobinPolicy(datacenter))
+	case RoundRobin:
+		return gocql.RoundRobinHostPolicy()
+	default:
+		panic(fmt.Sprintf("Unknown gocql HostSelectionPolicy: %v", p))
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gocql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHostSelectionPolicy(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    HostSelectionPolicy
+		expectedErr bool
+	}{
+		{input: "TokenAwareDCAware", expected: TokenAwareDCAware},
+		{input: " roundrobin ", expected: RoundRobin},
+		{input: "RoundRobin", expected: RoundRobin},
+		{input: "DCAware", expectedErr: true},
+		{input: "", expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		policy, err := ParseHostSelectionPolicy(tc.input)
+		if tc.expectedErr {
+			assert.Error(t, err, tc.input)
+			continue
+		}
+		assert.NoError(t, err, tc.input)
+		assert.Equal(t, tc.expected, policy, tc.input)
+	}
+}
+
+func TestConvertHostSelectionPolicy(t *testing.T) {
+	assert.NotNil(t, mustConvertHostSelectionPolicy(TokenAwareDCAware, "dc1"))
+	assert.NotNil(t, mustConvertHostSelectionPolicy(TokenAwareDCAware, ""))
+	assert.NotNil(t, mustConvertHostSelectionPolicy(RoundRobin, "dc1"))
+	assert.Panics(t, func() { mustConvertHostSelectionPolicy(HostSelectionPolicy(100), "") })
+}
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/uber/cadence/common/auth"
+	"github.com/uber/cadence/common/log"
 )
 
 // Note: this file defines the minimal interface that is needed by Cadence's cassandra
@@ -48,7 +49,11 @@ type (
 		NewBatch(BatchType) Batch
 		ExecuteBatch(Batch) error
 		MapExecuteBatchCAS(Batch, map[string]interface{}) (bool, Iter, error)
+		PoolStats() PoolStats
 		Close()
+		// CloseWithContext closes the session like Close, but stops waiting once ctx is done,
+		// in which case ctx.Err() is returned and the session keeps closing in the background
+		CloseWithContext(context.Context) error
 	}
 
 	// Query is the interface for query object.
@@ -64,6 +69,9 @@ type (
 		WithContext(context.Context) Query
 		WithTimestamp(int64) Query
 		Consistency(Consistency) Query
+		// Idempotent marks the query as safe to apply more than once, it is ignored if DisableIdempotentQueries is set.
+		// Conditional updates and counter updates must never be marked idempotent
+		Idempotent(bool) Query
 		Bind(...interface{}) Query
 	}
 
@@ -92,6 +100,21 @@ type (
 		IsTimeoutError(error) bool
 		IsNotFoundError(error) bool
 		IsThrottlingError(error) bool
+		// WriteTimeoutType returns the write type reported by the coordinator if the error is a write timeout
+		WriteTimeoutType(error) (string, bool)
+	}
+
+	// PoolStats is a snapshot of the connection pool stats of a session
+	PoolStats struct {
+		// ConnectsPerHost is the total number of connections established to each host, including the ones
+		// of the sessions recreated since. gocql doesn't report closed connections, so it only grows
+		ConnectsPerHost map[string]int64
+		// InFlightQueries is the number of queries and batches currently executing
+		InFlightQueries int64
+		// Errors is the total number of connect, query and batch errors observed
+		Errors int64
+		// CertificateReloadErrors is the total number of failures to reload the client certificate
+		CertificateReloadErrors int64
 	}
 
 	// BatchType is the type of the Batch operation
@@ -103,20 +126,37 @@ type (
 	// SerialConsistency is the serial consistency level used by a Query
 	SerialConsistency uint16
 
+	// HostSelectionPolicy is the policy used to pick the hosts a query is sent to
+	HostSelectionPolicy uint16
+
+	// RetryPolicy is the policy used to retry queries failing with unavailable replicas or a coordinator timeout,
+	// with an exponential backoff between MinBackoff and MaxBackoff, queries are not retried if NumRetries is zero
+	RetryPolicy struct {
+		NumRetries int
+		MinBackoff time.Duration
+		MaxBackoff time.Duration
+	}
+
 	// ClusterConfig is the config for cassandra connection
 	ClusterConfig struct {
-		Hosts             string
-		Port              int
-		User              string
-		Password          string
-		Keyspace          string
-		Region            string
-		Datacenter        string
-		MaxConns          int
-		TLS               *auth.TLS
-		ProtoVersion      int
-		Consistency       Consistency
-		SerialConsistency SerialConsistency
-		Timeout           time.Duration
+		Hosts               string
+		Port                int
+		User                string
+		Password            string
+		Keyspace            string
+		Region              string
+		Datacenter          string
+		MaxConns            int
+		TLS                 *auth.TLS
+		ProtoVersion        int
+		Consistency         Consistency
+		SerialConsistency   SerialConsistency
+		HostSelectionPolicy HostSelectionPolicy
+		Timeout             time.Duration
+		RetryPolicy         RetryPolicy
+		// DisableIdempotentQueries makes every query non-idempotent, whatever the query was marked with
+		DisableIdempotentQueries bool
+		// Logger logs the failures to reload the client certificate, nothing is logged if it's nil
+		Logger log.Logger
 	}
 )
@@ -113,6 +113,21 @@ func (mr *MockClientMockRecorder) IsThrottlingError(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsThrottlingError", reflect.TypeOf((*MockClient)(nil).IsThrottlingError), arg0)
 }
 
+// WriteTimeoutType mocks base method
+func (m *MockClient) WriteTimeoutType(arg0 error) (string, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteTimeoutType", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// WriteTimeoutType indicates an expected call of WriteTimeoutType
+func (mr *MockClientMockRecorder) WriteTimeoutType(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteTimeoutType", reflect.TypeOf((*MockClient)(nil).WriteTimeoutType), arg0)
+}
+
 // MockSession is a mock of Session interface
 type MockSession struct {
 	ctrl     *gomock.Controller
@@ -199,6 +214,20 @@ func (mr *MockSessionMockRecorder) MapExecuteBatchCAS(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MapExecuteBatchCAS", reflect.TypeOf((*MockSession)(nil).MapExecuteBatchCAS), arg0, arg1)
 }
 
+// PoolStats mocks base method
+func (m *MockSession) PoolStats() PoolStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PoolStats")
+	ret0, _ := ret[0].(PoolStats)
+	return ret0
+}
+
+// PoolStats indicates an expected call of PoolStats
+func (mr *MockSessionMockRecorder) PoolStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PoolStats", reflect.TypeOf((*MockSession)(nil).PoolStats))
+}
+
 // Close mocks base method
 func (m *MockSession) Close() {
 	m.ctrl.T.Helper()
@@ -211,6 +240,20 @@ func (mr *MockSessionMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSession)(nil).Close))
 }
 
+// CloseWithContext mocks base method
+func (m *MockSession) CloseWithContext(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseWithContext", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseWithContext indicates an expected call of CloseWithContext
+func (mr *MockSessionMockRecorder) CloseWithContext(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseWithContext", reflect.TypeOf((*MockSession)(nil).CloseWithContext), arg0)
+}
+
 // MockQuery is a mock of Query interface
 type MockQuery struct {
 	ctrl     *gomock.Controller
@@ -398,6 +441,20 @@ func (mr *MockQueryMockRecorder) Consistency(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consistency", reflect.TypeOf((*MockQuery)(nil).Consistency), arg0)
 }
 
+// Idempotent mocks base method
+func (m *MockQuery) Idempotent(arg0 bool) Query {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Idempotent", arg0)
+	ret0, _ := ret[0].(Query)
+	return ret0
+}
+
+// Idempotent indicates an expected call of Idempotent
+func (mr *MockQueryMockRecorder) Idempotent(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Idempotent", reflect.TypeOf((*MockQuery)(nil).Idempotent), arg0)
+}
+
 // Bind mocks base method
 func (m *MockQuery) Bind(arg0 ...interface{}) Query {
 	m.ctrl.T.Helper()
@@ -668,3 +725,18 @@ func (mr *MockErrorCheckerMockRecorder) IsThrottlingError(arg0 interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsThrottlingError", reflect.TypeOf((*MockErrorChecker)(nil).IsThrottlingError), arg0)
 }
+
+// WriteTimeoutType mocks base method
+func (m *MockErrorChecker) WriteTimeoutType(arg0 error) (string, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteTimeoutType", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// WriteTimeoutType indicates an expected call of WriteTimeoutType
+func (mr *MockErrorCheckerMockRecorder) WriteTimeoutType(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteTimeoutType", reflect.TypeOf((*MockErrorChecker)(nil).WriteTimeoutType), arg0)
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gocql
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gocql/gocql"
+)
+
+var _ gocql.ConnectObserver = (*poolStatsObserver)(nil)
+var _ gocql.QueryObserver = (*poolStatsObserver)(nil)
+var _ gocql.BatchObserver = (*poolStatsObserver)(nil)
+
+type (
+	// poolStatsObserver collects connection pool stats through the gocql observer hooks
+	poolStatsObserver struct {
+		sync.Mutex
+		connectsPerHost map[string]int64
+
+		inFlightQueries         int64
+		errors                  int64
+		certificateReloadErrors int64
+	}
+)
+
+func newPoolStatsObserver() *poolStatsObserver {
+	return &poolStatsObserver{
+		connectsPerHost: make(map[string]int64),
+	}
+}
+
+func (o *poolStatsObserver) ObserveConnect(connect gocql.ObservedConnect) {
+	if connect.Err != nil {
+		atomic.AddInt64(&o.errors, 1)
+		return
+	}
+	if connect.Host == nil {
+		return
+	}
+
+	o.Lock()
+	defer o.Unlock()
+	o.connectsPerHost[connect.Host.ConnectAddress().String()]++
+}
+
+func (o *poolStatsObserver) ObserveQuery(_ context.Context, query gocql.ObservedQuery) {
+	if query.Err != nil {
+		atomic.AddInt64(&o.errors, 1)
+	}
+}
+
+func (o *poolStatsObserver) ObserveBatch(_ context.Context, batch gocql.ObservedBatch) {
+	if batch.Err != nil {
+		atomic.AddInt64(&o.errors, 1)
+	}
+}
+
+// certificateReloadFailed counts a failure to reload the client certificate
+func (o *poolStatsObserver) certificateReloadFailed() {
+	atomic.AddInt64(&o.certificateReloadErrors, 1)
+}
+
+// startQuery marks a query as in flight, the returned func must be called once the query returns
+func (o *poolStatsObserver) startQuery() func() {
+	atomic.AddInt64(&o.inFlightQueries, 1)
+	return func() {
+		atomic.AddInt64(&o.inFlightQueries, -1)
+	}
+}
+
+func (o *poolStatsObserver) stats() PoolStats {
+	o.Lock()
+	connectsPerHost := make(map[string]int64, len(o.connectsPerHost))
+	for host, count := range o.connectsPerHost {
+		connectsPerHost[host] = count
+	}
+	o.Unlock()
+
+	return PoolStats{
+		ConnectsPerHost: connectsPerHost,
+		InFlightQueries: atomic.LoadInt64(&o.inFlightQueries),
+		Errors:          atomic.LoadInt64(&o.errors),
+
+		CertificateReloadErrors: atomic.LoadInt64(&o.certificateReloadErrors),
+	}
+}
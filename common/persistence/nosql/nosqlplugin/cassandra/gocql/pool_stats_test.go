@@ -0,0 +1,62 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gocql
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolStatsObserver(t *testing.T) {
+	host1 := (&gocql.HostInfo{}).SetConnectAddress(net.ParseIP("10.0.0.1"))
+	host2 := (&gocql.HostInfo{}).SetConnectAddress(net.ParseIP("10.0.0.2"))
+	someErr := errors.New("some error")
+
+	observer := newPoolStatsObserver()
+	observer.ObserveConnect(gocql.ObservedConnect{Host: host1})
+	observer.ObserveConnect(gocql.ObservedConnect{Host: host1})
+	observer.ObserveConnect(gocql.ObservedConnect{Host: host2})
+	observer.ObserveConnect(gocql.ObservedConnect{Host: host2, Err: someErr})
+	observer.ObserveQuery(context.Background(), gocql.ObservedQuery{Host: host1})
+	observer.ObserveQuery(context.Background(), gocql.ObservedQuery{Host: host1, Err: someErr})
+	observer.ObserveBatch(context.Background(), gocql.ObservedBatch{Host: host2, Err: someErr})
+
+	done1 := observer.startQuery()
+	done2 := observer.startQuery()
+	done1()
+
+	stats := observer.stats()
+	assert.Equal(t, map[string]int64{"10.0.0.1": 2, "10.0.0.2": 1}, stats.ConnectsPerHost)
+	assert.Equal(t, int64(1), stats.InFlightQueries)
+	assert.Equal(t, int64(3), stats.Errors)
+
+	done2()
+	observer.ObserveConnect(gocql.ObservedConnect{Host: host2})
+	stats = observer.stats()
+	assert.Equal(t, map[string]int64{"10.0.0.1": 2, "10.0.0.2": 2}, stats.ConnectsPerHost)
+	assert.Equal(t, int64(0), stats.InFlightQueries)
+	assert.Equal(t, int64(3), stats.Errors)
+}
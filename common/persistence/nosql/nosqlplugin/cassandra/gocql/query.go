@@ -48,6 +48,10 @@ func newQuery(
 }
 
 func (q *query) Exec() error {
+	if q.session.isClosed() {
+		return gocql.ErrSessionClosed
+	}
+	defer q.session.poolStats.startQuery()()
 	err := q.Query.Exec()
 	return q.handleError(err)
 }
@@ -55,6 +59,10 @@ func (q *query) Exec() error {
 func (q *query) Scan(
 	dest ...interface{},
 ) error {
+	if q.session.isClosed() {
+		return gocql.ErrSessionClosed
+	}
+	defer q.session.poolStats.startQuery()()
 	err := q.Query.Scan(dest...)
 	return q.handleError(err)
 }
@@ -62,6 +70,10 @@ func (q *query) Scan(
 func (q *query) ScanCAS(
 	dest ...interface{},
 ) (bool, error) {
+	if q.session.isClosed() {
+		return false, gocql.ErrSessionClosed
+	}
+	defer q.session.poolStats.startQuery()()
 	applied, err := q.Query.ScanCAS(dest...)
 	return applied, q.handleError(err)
 }
@@ -69,6 +81,10 @@ func (q *query) ScanCAS(
 func (q *query) MapScan(
 	m map[string]interface{},
 ) error {
+	if q.session.isClosed() {
+		return gocql.ErrSessionClosed
+	}
+	defer q.session.poolStats.startQuery()()
 	err := q.Query.MapScan(m)
 	return q.handleError(err)
 }
@@ -76,11 +92,16 @@ func (q *query) MapScan(
 func (q *query) MapScanCAS(
 	dest map[string]interface{},
 ) (bool, error) {
+	if q.session.isClosed() {
+		return false, gocql.ErrSessionClosed
+	}
+	defer q.session.poolStats.startQuery()()
 	applied, err := q.Query.MapScanCAS(dest)
 	return applied, q.handleError(err)
 }
 
 func (q *query) Iter() Iter {
+	defer q.session.poolStats.startQuery()()
 	iter := q.Query.Iter()
 	if iter == nil {
 		return nil
@@ -103,6 +124,16 @@ func (q *query) Consistency(c Consistency) Query {
 	return q
 }
 
+// Idempotent marks the query as idempotent, so that the retry policy retries it after a write timeout
+func (q *query) Idempotent(value bool) Query {
+	idempotent := value && !q.session.config.DisableIdempotentQueries
+	q.Query.Idempotent(idempotent)
+	if retryPolicy := q.session.retryPolicy(idempotent); retryPolicy != nil {
+		q.Query.RetryPolicy(retryPolicy)
+	}
+	return q
+}
+
 func (q *query) WithTimestamp(timestamp int64) Query {
 	q.Query.WithTimestamp(timestamp)
 	return q
@@ -0,0 +1,64 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gocql
+
+import (
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryIdempotent(t *testing.T) {
+	testCases := []struct {
+		name       string
+		disabled   bool
+		value      bool
+		idempotent bool
+	}{
+		{name: "idempotent", value: true, idempotent: true},
+		{name: "not idempotent", value: false, idempotent: false},
+		{name: "disabled", disabled: true, value: true, idempotent: false},
+		{name: "disabled not idempotent", disabled: true, value: false, idempotent: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &session{config: ClusterConfig{
+				RetryPolicy:              RetryPolicy{NumRetries: 1},
+				DisableIdempotentQueries: tc.disabled,
+			}}
+			q := newQuery(s, &gocql.Query{})
+			q.Idempotent(tc.value)
+			assert.Equal(t, tc.idempotent, q.Query.IsIdempotent())
+		})
+	}
+}
+
+func TestSessionRetryPolicy(t *testing.T) {
+	writeTimeout := &gocql.RequestErrWriteTimeout{WriteType: WriteTypeSimple}
+	s := &session{config: ClusterConfig{RetryPolicy: RetryPolicy{NumRetries: 1}}}
+	assert.Equal(t, gocql.RetryNextHost, s.retryPolicy(true).GetRetryType(writeTimeout))
+	assert.Equal(t, gocql.Rethrow, s.retryPolicy(false).GetRetryType(writeTimeout))
+
+	// queries are not retried, so the policy configured on the cluster is kept
+	s = &session{}
+	assert.Nil(t, s.retryPolicy(true))
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gocql
+
+import (
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
+
+type retryPolicy struct {
+	gocql.ExponentialBackoffRetryPolicy
+
+	idempotent bool
+}
+
+// Validate returns an error if the retry policy has negative values or a MaxBackoff below MinBackoff
+func (p RetryPolicy) Validate() error {
+	if p.NumRetries < 0 || p.MinBackoff < 0 || p.MaxBackoff < 0 {
+		return fmt.Errorf("retry policy values can not be negative: %+v", p)
+	}
+	if p.MaxBackoff > 0 && p.MaxBackoff < p.MinBackoff {
+		return fmt.Errorf("retry policy MaxBackoff: %v is smaller than MinBackoff: %v", p.MaxBackoff, p.MinBackoff)
+	}
+	return nil
+}
+
+// Write types reported by the coordinator when a write times out, the gocql version in use only exposes them as strings
+const (
+	WriteTypeSimple        = "SIMPLE"
+	WriteTypeBatch         = "BATCH"
+	WriteTypeUnloggedBatch = "UNLOGGED_BATCH"
+	WriteTypeCounter       = "COUNTER"
+	WriteTypeBatchLog      = "BATCH_LOG"
+	WriteTypeCAS           = "CAS"
+	WriteTypeView          = "VIEW"
+	WriteTypeCDC           = "CDC"
+)
+
+func newRetryPolicy(p RetryPolicy, idempotent bool) gocql.RetryPolicy {
+	return &retryPolicy{
+		ExponentialBackoffRetryPolicy: gocql.ExponentialBackoffRetryPolicy{
+			NumRetries: p.NumRetries,
+			Min:        p.MinBackoff,
+			Max:        p.MaxBackoff,
+		},
+		idempotent: idempotent,
+	}
+}
+
+// GetRetryType retries unavailable errors and read timeouts on the next host, write timeouts are only retried
+// for idempotent queries whose mutation is safe to apply twice, every other error is returned to the caller
+func (p *retryPolicy) GetRetryType(err error) gocql.RetryType {
+	switch err := err.(type) {
+	case *gocql.RequestErrUnavailable, *gocql.RequestErrReadTimeout:
+		return gocql.RetryNextHost
+	case *gocql.RequestErrWriteTimeout:
+		if p.idempotent && isRetryableWriteType(err.WriteType) {
+			return gocql.RetryNextHost
+		}
+	}
+	return gocql.Rethrow
+}
+
+// isRetryableWriteType returns whether a write which timed out with the given write type can be retried,
+// an unlogged batch may have been partially applied and CAS and counter writes are never idempotent
+func isRetryableWriteType(writeType string) bool {
+	switch writeType {
+	case WriteTypeSimple, WriteTypeBatch, WriteTypeBatchLog:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gocql
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyValidate(t *testing.T) {
+	assert.NoError(t, RetryPolicy{}.Validate())
+	assert.NoError(t, RetryPolicy{NumRetries: 1, MinBackoff: time.Millisecond, MaxBackoff: time.Second}.Validate())
+	assert.NoError(t, RetryPolicy{NumRetries: 1, MinBackoff: time.Millisecond}.Validate())
+	assert.Error(t, RetryPolicy{NumRetries: -1}.Validate())
+	assert.Error(t, RetryPolicy{NumRetries: 1, MinBackoff: -time.Millisecond}.Validate())
+	assert.Error(t, RetryPolicy{NumRetries: 1, MinBackoff: time.Second, MaxBackoff: time.Millisecond}.Validate())
+}
+
+func TestRetryPolicyGetRetryType(t *testing.T) {
+	policy := newRetryPolicy(RetryPolicy{NumRetries: 1}, false)
+	idempotentPolicy := newRetryPolicy(RetryPolicy{NumRetries: 1}, true)
+
+	for _, err := range []error{&gocql.RequestErrUnavailable{}, &gocql.RequestErrReadTimeout{}} {
+		assert.Equal(t, gocql.RetryNextHost, policy.GetRetryType(err))
+		assert.Equal(t, gocql.RetryNextHost, idempotentPolicy.GetRetryType(err))
+	}
+	for _, err := range []error{
+		errors.New("some random error"),
+		gocql.ErrTimeoutNoResponse,
+		&gocql.RequestErrWriteFailure{},
+		&gocql.RequestErrReadFailure{},
+	} {
+		assert.Equal(t, gocql.Rethrow, policy.GetRetryType(err))
+		assert.Equal(t, gocql.Rethrow, idempotentPolicy.GetRetryType(err))
+	}
+
+	testCases := []struct {
+		writeType string
+		retryable bool
+	}{
+		{writeType: WriteTypeSimple, retryable: true},
+		{writeType: WriteTypeBatch, retryable: true},
+		{writeType: WriteTypeBatchLog, retryable: true},
+		{writeType: WriteTypeUnloggedBatch, retryable: false},
+		{writeType: WriteTypeCounter, retryable: false},
+		{writeType: WriteTypeCAS, retryable: false},
+		{writeType: WriteTypeView, retryable: false},
+		{writeType: WriteTypeCDC, retryable: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.writeType, func(t *testing.T) {
+			err := &gocql.RequestErrWriteTimeout{WriteType: tc.writeType}
+			assert.Equal(t, gocql.Rethrow, policy.GetRetryType(err))
+			if tc.retryable {
+				assert.Equal(t, gocql.RetryNextHost, idempotentPolicy.GetRetryType(err))
+			} else {
+				assert.Equal(t, gocql.Rethrow, idempotentPolicy.GetRetryType(err))
+			}
+		})
+	}
+}
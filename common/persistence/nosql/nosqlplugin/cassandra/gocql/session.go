@@ -22,6 +22,7 @@
 package gocql
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -45,13 +46,15 @@ type (
 		status          int32
 		config          ClusterConfig
 		sessionInitTime time.Time
+		poolStats       *poolStatsObserver
 	}
 )
 
 func newSession(
 	config ClusterConfig,
 ) (*session, error) {
-	gocqlSession, err := initSession(config)
+	poolStats := newPoolStatsObserver()
+	gocqlSession, err := initSession(config, poolStats)
 	if err != nil {
 		return nil, err
 	}
@@ -59,6 +62,7 @@ func newSession(
 		status:          common.DaemonStatusStarted,
 		config:          config,
 		sessionInitTime: time.Now().UTC(),
+		poolStats:       poolStats,
 	}
 	session.Value.Store(gocqlSession)
 	return session, nil
@@ -66,12 +70,19 @@ func newSession(
 
 func initSession(
 	config ClusterConfig,
+	poolStats *poolStatsObserver,
 ) (*gocql.Session, error) {
-	cluster := newCassandraCluster(config)
+	cluster, err := newCassandraCluster(config, poolStats)
+	if err != nil {
+		return nil, err
+	}
 	cluster.ProtoVersion = config.ProtoVersion
 	cluster.Consistency = mustConvertConsistency(config.Consistency)
 	cluster.SerialConsistency = mustConvertSerialConsistency(config.SerialConsistency)
 	cluster.Timeout = config.Timeout
+	cluster.ConnectObserver = poolStats
+	cluster.QueryObserver = poolStats
+	cluster.BatchObserver = poolStats
 	return cluster.CreateSession()
 }
 
@@ -87,7 +98,7 @@ func (s *session) refresh() error {
 		return nil
 	}
 
-	newSession, err := initSession(s.config)
+	newSession, err := initSession(s.config, s.poolStats)
 	if err != nil {
 		return err
 	}
@@ -99,6 +110,14 @@ func (s *session) refresh() error {
 	return nil
 }
 
+// retryPolicy returns the retry policy of a query, or nil if queries are not retried
+func (s *session) retryPolicy(idempotent bool) gocql.RetryPolicy {
+	if s.config.RetryPolicy.NumRetries <= 0 {
+		return nil
+	}
+	return newRetryPolicy(s.config.RetryPolicy, idempotent)
+}
+
 func (s *session) Query(
 	stmt string,
 	values ...interface{},
@@ -123,6 +142,10 @@ func (s *session) NewBatch(
 func (s *session) ExecuteBatch(
 	b Batch,
 ) error {
+	if s.isClosed() {
+		return gocql.ErrSessionClosed
+	}
+	defer s.poolStats.startQuery()()
 	err := s.Value.Load().(*gocql.Session).ExecuteBatch(b.(*batch).Batch)
 	return s.handleError(err)
 }
@@ -131,6 +154,10 @@ func (s *session) MapExecuteBatchCAS(
 	b Batch,
 	previous map[string]interface{},
 ) (bool, Iter, error) {
+	if s.isClosed() {
+		return false, nil, gocql.ErrSessionClosed
+	}
+	defer s.poolStats.startQuery()()
 	applied, iter, err := s.Value.Load().(*gocql.Session).MapExecuteBatchCAS(b.(*batch).Batch, previous)
 	if iter == nil {
 		return applied, nil, s.handleError(err)
@@ -138,12 +165,37 @@ func (s *session) MapExecuteBatchCAS(
 	return applied, iter, s.handleError(err)
 }
 
+func (s *session) PoolStats() PoolStats {
+	return s.poolStats.stats()
+}
+
 func (s *session) Close() {
+	_ = s.CloseWithContext(context.Background())
+}
+
+// CloseWithContext rejects new queries right away and closes the underlying session in the background,
+// waiting for the connections to drain until ctx is done
+func (s *session) CloseWithContext(ctx context.Context) error {
 	if !atomic.CompareAndSwapInt32(&s.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
-		return
+		return nil
 	}
 
-	s.Value.Load().(*gocql.Session).Close()
+	done := make(chan struct{})
+	go func() {
+		s.Value.Load().(*gocql.Session).Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *session) isClosed() bool {
+	return atomic.LoadInt32(&s.status) != common.DaemonStatusStarted
 }
 
 func (s *session) handleError(err error) error {
@@ -0,0 +1,82 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gocql
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sync"
+
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+)
+
+type (
+	// clientCertificateReloader loads the client certificate from disk on every TLS handshake,
+	// so new connections pick up a rotated certificate without a restart
+	clientCertificateReloader struct {
+		certFile  string
+		keyFile   string
+		logger    log.Logger
+		poolStats *poolStatsObserver
+
+		sync.Mutex
+		lastCertificate *tls.Certificate
+	}
+)
+
+// newClientCertificateReloader loads the client certificate once, so a misconfigured key pair fails
+// the session creation instead of every handshake
+func newClientCertificateReloader(
+	certFile string,
+	keyFile string,
+	logger log.Logger,
+	poolStats *poolStatsObserver,
+) (*clientCertificateReloader, error) {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cassandra client certificate: %v", err)
+	}
+	return &clientCertificateReloader{
+		certFile:        certFile,
+		keyFile:         keyFile,
+		logger:          logger,
+		poolStats:       poolStats,
+		lastCertificate: &certificate,
+	}, nil
+}
+
+// GetClientCertificate implements tls.Config.GetClientCertificate
+// If the key pair can't be reloaded, e.g. while the files are being rotated, the failure is logged and
+// counted in the pool stats, and the last successfully loaded certificate is returned
+func (r *clientCertificateReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		r.poolStats.certificateReloadFailed()
+		r.logger.Warn("Failed to reload cassandra client certificate, using the last loaded one", tag.Error(err))
+		return r.lastCertificate, nil
+	}
+	r.lastCertificate = &certificate
+	return r.lastCertificate, nil
+}
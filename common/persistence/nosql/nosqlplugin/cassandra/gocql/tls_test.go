@@ -0,0 +1,98 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gocql
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/log/loggerimpl"
+)
+
+func TestClientCertificateReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassandra-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+	poolStats := newPoolStatsObserver()
+
+	_, err = newClientCertificateReloader(certFile, keyFile, loggerimpl.NewNopLogger(), poolStats)
+	assert.Error(t, err, "no certificate has been written yet")
+
+	writeTestKeyPair(t, certFile, keyFile, 1)
+	reloader, err := newClientCertificateReloader(certFile, keyFile, loggerimpl.NewNopLogger(), poolStats)
+	require.NoError(t, err)
+	certificate, err := reloader.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), parseSerialNumber(t, certificate.Certificate[0]))
+
+	writeTestKeyPair(t, certFile, keyFile, 2)
+	certificate, err = reloader.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), parseSerialNumber(t, certificate.Certificate[0]))
+	assert.Equal(t, int64(0), poolStats.stats().CertificateReloadErrors)
+
+	// a half-rotated key pair falls back to the last loaded certificate and is counted as a reload error
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte("rotating"), 0600))
+	certificate, err = reloader.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), parseSerialNumber(t, certificate.Certificate[0]))
+	assert.Equal(t, int64(1), poolStats.stats().CertificateReloadErrors)
+}
+
+func writeTestKeyPair(t *testing.T, certFile, keyFile string, serialNumber int64) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serialNumber),
+		Subject:      pkix.Name{CommonName: "cadence"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+}
+
+func parseSerialNumber(t *testing.T, der []byte) int64 {
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return certificate.SerialNumber.Int64()
+}
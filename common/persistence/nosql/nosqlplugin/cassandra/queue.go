@@ -26,6 +26,7 @@ import (
 
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin"
+	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra/gocql"
 )
 
 const (
@@ -48,6 +49,8 @@ func (db *cdb) InsertIntoQueue(
 	ctx context.Context,
 	row *nosqlplugin.QueueMessageRow,
 ) error {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
 	query := db.session.Query(templateEnqueueMessageQuery, row.QueueType, row.ID, row.Payload).WithContext(ctx)
 	previous := make(map[string]interface{})
 	applied, err := query.MapScanCAS(previous)
@@ -61,11 +64,45 @@ func (db *cdb) InsertIntoQueue(
 	return nil
 }
 
+// Insert messages of the same queue atomically, return error if failed or any of them already exists
+// Must return conditionFailed error if any row already exists, in which case none of the rows are inserted
+func (db *cdb) InsertIntoQueueInBatch(
+	ctx context.Context,
+	rows []*nosqlplugin.QueueMessageRow,
+) error {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
+	// all rows share the queue_type partition, so the conditional batch is applied atomically
+	batch := db.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	for _, row := range rows {
+		batch.Query(templateEnqueueMessageQuery, row.QueueType, row.ID, row.Payload)
+	}
+
+	previous := make(map[string]interface{})
+	applied, iter, err := db.session.MapExecuteBatchCAS(batch, previous)
+	defer func() {
+		if iter != nil {
+			_ = iter.Close()
+		}
+	}()
+
+	if err != nil {
+		return err
+	}
+
+	if !applied {
+		return errConditionFailed
+	}
+	return nil
+}
+
 // Get the ID of last message inserted into the queue
 func (db *cdb) SelectLastEnqueuedMessageID(
 	ctx context.Context,
 	queueType persistence.QueueType,
 ) (int64, error) {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
 	query := db.session.Query(templateGetLastMessageIDQuery, queueType).WithContext(ctx)
 	result := make(map[string]interface{})
 	err := query.MapScan(result)
@@ -83,6 +120,8 @@ func (db *cdb) SelectMessagesFrom(
 	exclusiveBeginMessageID int64,
 	maxRows int,
 ) ([]*nosqlplugin.QueueMessageRow, error) {
+	ctx, cancel := db.scanContext(ctx)
+	defer cancel()
 	// Reading replication tasks need to be quorum level consistent, otherwise we could loose task
 	query := db.session.Query(templateGetMessagesQuery,
 		queueType,
@@ -116,6 +155,8 @@ func (db *cdb) SelectMessagesBetween(
 	ctx context.Context,
 	request nosqlplugin.SelectMessagesBetweenRequest,
 ) (*nosqlplugin.SelectMessagesBetweenResponse, error) {
+	ctx, cancel := db.scanContext(ctx)
+	defer cancel()
 	// Reading replication tasks need to be quorum level consistent, otherwise we could loose task
 	// Use negative queue type as the dlq type
 	query := db.session.Query(templateGetMessagesFromDLQQuery,
@@ -155,7 +196,9 @@ func (db *cdb) DeleteMessagesBefore(
 	queueType persistence.QueueType,
 	exclusiveBeginMessageID int64,
 ) error {
-	query := db.session.Query(templateRangeDeleteMessagesBeforeQuery, queueType, exclusiveBeginMessageID).WithContext(ctx)
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
+	query := db.session.Query(templateRangeDeleteMessagesBeforeQuery, queueType, exclusiveBeginMessageID).Idempotent(true).WithContext(ctx)
 	return query.Exec()
 }
 
@@ -166,7 +209,9 @@ func (db *cdb) DeleteMessagesInRange(
 	exclusiveBeginMessageID int64,
 	inclusiveEndMessageID int64,
 ) error {
-	query := db.session.Query(templateRangeDeleteMessagesBetweenQuery, queueType, exclusiveBeginMessageID, inclusiveEndMessageID).WithContext(ctx)
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
+	query := db.session.Query(templateRangeDeleteMessagesBetweenQuery, queueType, exclusiveBeginMessageID, inclusiveEndMessageID).Idempotent(true).WithContext(ctx)
 	return query.Exec()
 }
 
@@ -176,7 +221,9 @@ func (db *cdb) DeleteMessage(
 	queueType persistence.QueueType,
 	messageID int64,
 ) error {
-	query := db.session.Query(templateDeleteMessageQuery, queueType, messageID).WithContext(ctx)
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
+	query := db.session.Query(templateDeleteMessageQuery, queueType, messageID).Idempotent(true).WithContext(ctx)
 	return query.Exec()
 }
 
@@ -186,6 +233,8 @@ func (db *cdb) InsertQueueMetadata(
 	queueType persistence.QueueType,
 	version int64,
 ) error {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
 	clusterAckLevels := map[string]int64{}
 	query := db.session.Query(templateInsertQueueMetadataQuery, queueType, clusterAckLevels, version).WithContext(ctx)
 
@@ -207,6 +256,8 @@ func (db *cdb) UpdateQueueMetadataCas(
 	ctx context.Context,
 	row nosqlplugin.QueueMetadataRow,
 ) error {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
 	query := db.session.Query(templateUpdateQueueMetadataQuery,
 		row.ClusterAckLevels,
 		row.Version,
@@ -233,6 +284,8 @@ func (db *cdb) SelectQueueMetadata(
 	ctx context.Context,
 	queueType persistence.QueueType,
 ) (*nosqlplugin.QueueMetadataRow, error) {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
 	query := db.session.Query(templateGetQueueMetadataQuery, queueType).WithContext(ctx)
 	var ackLevels map[string]int64
 	var version int64
@@ -256,6 +309,8 @@ func (db *cdb) GetQueueSize(
 	ctx context.Context,
 	queueType persistence.QueueType,
 ) (int64, error) {
+	ctx, cancel := db.pointContext(ctx)
+	defer cancel()
 
 	query := db.session.Query(templateGetQueueSizeQuery, queueType).WithContext(ctx)
 	result := make(map[string]interface{})
@@ -21,33 +21,237 @@
 package cassandra
 
 import (
+	"context"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra/gocql"
 )
 
 const (
 	cassandraProtoVersion = 4
 	defaultSessionTimeout = 10 * time.Second
+
+	poolMetricsEmitInterval = time.Minute
+)
+
+// defaultRetryPolicy retries a failed query once on another host, writes which are not marked idempotent
+// are never retried after a write timeout
+var defaultRetryPolicy = gocql.RetryPolicy{
+	NumRetries: 1,
+	MinBackoff: 100 * time.Millisecond,
+	MaxBackoff: time.Second,
+}
+
+type (
+	// poolMetricsSession periodically emits the connection pool stats of the wrapped session
+	poolMetricsSession struct {
+		gocql.Session
+
+		metricsClient metrics.Client
+		shutdownCh    chan struct{}
+		closeOnce     sync.Once
+		// the stats of the last emit, the pool stats are cumulative and are emitted as counters of the increase
+		lastConnectsPerHost         map[string]int64
+		lastErrors                  int64
+		lastCertificateReloadErrors int64
+	}
 )
 
 // CreateSession creates a new session
+// logger is used to log the failures to reload the TLS client certificate
+// metricsClient is optional, connection pool metrics are emitted only if it's not nil
+// and pool metrics are enabled in the config
 // TODO this will be converted to private later, after all cassandra code moved to plugin pkg
-func CreateSession(cfg config.Cassandra) (gocql.Session, error) {
-	return cfg.CQLClient.CreateSession(gocql.ClusterConfig{
-		Hosts:             cfg.Hosts,
-		Port:              cfg.Port,
-		User:              cfg.User,
-		Password:          cfg.Password,
-		Keyspace:          cfg.Keyspace,
-		Region:            cfg.Region,
-		Datacenter:        cfg.Datacenter,
-		MaxConns:          cfg.MaxConns,
-		TLS:               cfg.TLS,
-		ProtoVersion:      cassandraProtoVersion,
-		Consistency:       gocql.LocalQuorum,
-		SerialConsistency: gocql.LocalSerial,
-		Timeout:           defaultSessionTimeout,
+func CreateSession(cfg config.Cassandra, logger log.Logger, metricsClient metrics.Client) (gocql.Session, error) {
+	return CreateSessionWithContext(context.Background(), cfg, logger, metricsClient)
+}
+
+// CreateSessionWithContext creates a new session like CreateSession, but gives up
+// establishing the session once ctx is cancelled or its deadline is exceeded
+func CreateSessionWithContext(
+	ctx context.Context,
+	cfg config.Cassandra,
+	logger log.Logger,
+	metricsClient metrics.Client,
+) (gocql.Session, error) {
+	return createSessionWithMetrics(ctx, cfg, logger, metricsClient, defaultSessionTimeout)
+}
+
+func createSessionWithMetrics(
+	ctx context.Context,
+	cfg config.Cassandra,
+	logger log.Logger,
+	metricsClient metrics.Client,
+	timeout time.Duration,
+) (gocql.Session, error) {
+	session, err := createSession(ctx, cfg, logger, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.EnablePoolMetrics || metricsClient == nil {
+		return session, nil
+	}
+
+	metricsSession := &poolMetricsSession{
+		Session:             session,
+		metricsClient:       metricsClient,
+		shutdownCh:          make(chan struct{}),
+		lastConnectsPerHost: make(map[string]int64),
+	}
+	go metricsSession.emitLoop()
+	return metricsSession, nil
+}
+
+func (s *poolMetricsSession) Close() {
+	s.closeOnce.Do(func() {
+		close(s.shutdownCh)
+	})
+	s.Session.Close()
+}
+
+func (s *poolMetricsSession) CloseWithContext(ctx context.Context) error {
+	s.closeOnce.Do(func() {
+		close(s.shutdownCh)
 	})
+	return s.Session.CloseWithContext(ctx)
+}
+
+func (s *poolMetricsSession) emitLoop() {
+	ticker := time.NewTicker(poolMetricsEmitInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdownCh:
+			return
+		case <-ticker.C:
+			s.emitPoolMetrics()
+		}
+	}
+}
+
+func (s *poolMetricsSession) emitPoolMetrics() {
+	stats := s.Session.PoolStats()
+	for host, connects := range stats.ConnectsPerHost {
+		if delta := connects - s.lastConnectsPerHost[host]; delta > 0 {
+			s.metricsClient.Scope(metrics.CassandraSessionScope, metrics.CassandraHostTag(host)).
+				AddCounter(metrics.CassandraPoolConnectsCounter, delta)
+		}
+		s.lastConnectsPerHost[host] = connects
+	}
+	s.metricsClient.UpdateGauge(metrics.CassandraSessionScope, metrics.CassandraPoolInFlightQueriesGauge, float64(stats.InFlightQueries))
+	if delta := stats.Errors - s.lastErrors; delta > 0 {
+		s.metricsClient.AddCounter(metrics.CassandraSessionScope, metrics.CassandraPoolErrorsCounter, delta)
+	}
+	s.lastErrors = stats.Errors
+	if delta := stats.CertificateReloadErrors - s.lastCertificateReloadErrors; delta > 0 {
+		s.metricsClient.AddCounter(metrics.CassandraSessionScope, metrics.CassandraClientCertificateReloadErrorsCounter, delta)
+	}
+	s.lastCertificateReloadErrors = stats.CertificateReloadErrors
+}
+
+// sessionTimeout returns the timeout of the session of a DB, gocql applies it per connection and a query
+// can only be bound to a shorter timeout with its context, so it has to cover cfg.ScanQueryTimeout
+func sessionTimeout(cfg config.Cassandra) time.Duration {
+	if cfg.ScanQueryTimeout > defaultSessionTimeout {
+		return cfg.ScanQueryTimeout
+	}
+	return defaultSessionTimeout
+}
+
+func createSession(
+	ctx context.Context,
+	cfg config.Cassandra,
+	logger log.Logger,
+	timeout time.Duration,
+) (gocql.Session, error) {
+	consistency := gocql.LocalQuorum
+	if cfg.Consistency != "" {
+		var err error
+		if consistency, err = gocql.ParseConsistency(cfg.Consistency); err != nil {
+			return nil, fmt.Errorf("invalid cassandra consistency config: %v", err)
+		}
+	}
+	serialConsistency := gocql.LocalSerial
+	if cfg.SerialConsistency != "" {
+		var err error
+		if serialConsistency, err = gocql.ParseSerialConsistency(cfg.SerialConsistency); err != nil {
+			return nil, fmt.Errorf("invalid cassandra serial consistency config: %v", err)
+		}
+	}
+	hostSelectionPolicy := gocql.TokenAwareDCAware
+	if cfg.HostSelectionPolicy != "" {
+		var err error
+		if hostSelectionPolicy, err = gocql.ParseHostSelectionPolicy(cfg.HostSelectionPolicy); err != nil {
+			return nil, fmt.Errorf("invalid cassandra host selection policy config: %v", err)
+		}
+	}
+
+	retryPolicy := defaultRetryPolicy
+	if cfg.RetryPolicy != nil {
+		retryPolicy = gocql.RetryPolicy{
+			NumRetries: cfg.RetryPolicy.NumRetries,
+			MinBackoff: cfg.RetryPolicy.MinBackoff,
+			MaxBackoff: cfg.RetryPolicy.MaxBackoff,
+		}
+		if err := retryPolicy.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid cassandra retry policy config: %v", err)
+		}
+	}
+
+	clusterConfig := gocql.ClusterConfig{
+		Hosts:               cfg.Hosts,
+		Port:                cfg.Port,
+		User:                cfg.User,
+		Password:            cfg.Password,
+		Keyspace:            cfg.Keyspace,
+		Region:              cfg.Region,
+		Datacenter:          cfg.Datacenter,
+		MaxConns:            cfg.MaxConns,
+		TLS:                 cfg.TLS,
+		ProtoVersion:        cassandraProtoVersion,
+		Consistency:         consistency,
+		SerialConsistency:   serialConsistency,
+		HostSelectionPolicy: hostSelectionPolicy,
+		Timeout:             timeout,
+		RetryPolicy:         retryPolicy,
+
+		DisableIdempotentQueries: cfg.DisableIdempotentQueries,
+		Logger:                   logger,
+	}
+	if ctx.Done() == nil {
+		return cfg.CQLClient.CreateSession(clusterConfig)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("cassandra session creation aborted: %v", err)
+	}
+
+	// the gocql client has no context support, so wait for it in the background
+	type result struct {
+		session gocql.Session
+		err     error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		session, err := cfg.CQLClient.CreateSession(clusterConfig)
+		resultCh <- result{session: session, err: err}
+	}()
+
+	select {
+	case r := <-resultCh:
+		return r.session, r.err
+	case <-ctx.Done():
+		go func() {
+			// close the session if it is established after all, so it doesn't leak
+			if r := <-resultCh; r.session != nil {
+				r.session.Close()
+			}
+		}()
+		return nil, fmt.Errorf("cassandra session creation aborted: %v", ctx.Err())
+	}
 }
@@ -34,6 +34,8 @@ type (
 	DB interface {
 		PluginName() string
 		Close()
+		// CloseWithContext closes the DB like Close, but stops waiting once ctx is done
+		CloseWithContext(ctx context.Context) error
 
 		IsConditionFailedError(err error) bool
 		gocql.ErrorChecker
@@ -59,11 +61,16 @@ type (
 		**/
 
 		// InsertIntoHistoryTreeAndNode inserts one or two rows: tree row and node row(at least one of them)
+		// Must return conditionFailed error if nodeRow.FailIfExists is set and the node already exists
 		InsertIntoHistoryTreeAndNode(ctx context.Context, treeRow *HistoryTreeRow, nodeRow *HistoryNodeRow) error
 
 		// SelectFromHistoryNode read nodes based on a filter
 		SelectFromHistoryNode(ctx context.Context, filter *HistoryNodeFilter) ([]*HistoryNodeRow, []byte, error)
 
+		// SelectOneFromHistoryNode reads the row of a single node with the largest transaction ID,
+		// it must return a NotFound error if the node doesn't exist
+		SelectOneFromHistoryNode(ctx context.Context, treeID string, branchID string, nodeID int64) (*HistoryNodeRow, error)
+
 		// DeleteFromHistoryTreeAndNode delete a branch record, and a list of ranges of nodes.
 		// for each range, it will delete all nodes starting from MinNodeID(inclusive)
 		DeleteFromHistoryTreeAndNode(ctx context.Context, treeFilter *HistoryTreeFilter, nodeFilters []*HistoryNodeFilter) error
@@ -74,6 +81,9 @@ type (
 		// SelectFromHistoryTree read branch records for a tree.
 		// It returns without pagination, because we assume one tree won't have too many branches.
 		SelectFromHistoryTree(ctx context.Context, filter *HistoryTreeFilter) ([]*HistoryTreeRow, error)
+
+		// SelectFromHistoryTreeWithPagination read one page of branch records for a tree
+		SelectFromHistoryTreeWithPagination(ctx context.Context, filter *HistoryTreeFilter, nextPageToken []byte, pageSize int) ([]*HistoryTreeRow, []byte, error)
 	}
 
 	// messageQueueCRUD is for the message queue storage system
@@ -82,6 +92,9 @@ type (
 		//Insert message into queue, return error if failed or already exists
 		// Must return conditionFailed error if row already exists
 		InsertIntoQueue(ctx context.Context, row *QueueMessageRow) error
+		// Insert messages of the same queue atomically, return error if failed or any of them already exists
+		// Must return conditionFailed error if any row already exists, in which case none of the rows are inserted
+		InsertIntoQueueInBatch(ctx context.Context, rows []*QueueMessageRow) error
 		// Get the ID of last message inserted into the queue
 		SelectLastEnqueuedMessageID(ctx context.Context, queueType persistence.QueueType) (int64, error)
 		// Read queue messages starting from the exclusiveBeginMessageID
@@ -126,6 +139,8 @@ type (
 		UpdateDomain(ctx context.Context, row *DomainRow) error
 		// Get one domain data, either by domainID or domainName
 		SelectDomain(ctx context.Context, domainID *string, domainName *string) (*DomainRow, error)
+		// Get only the config and failover version of one domain, either by domainID or domainName
+		SelectDomainConfigVersion(ctx context.Context, domainID *string, domainName *string) (configVersion int64, failoverVersion int64, err error)
 		// Get all domain data
 		SelectAllDomains(ctx context.Context, pageSize int, pageToken []byte) ([]*DomainRow, []byte, error)
 		//  Delete a domain, either by domainID or domainName
@@ -147,6 +162,7 @@ type (
 		NotificationVersion         int64
 		LastUpdatedTime             time.Time
 		IsGlobalDomain              bool
+		DeletionTime                *time.Time
 	}
 
 	// NoSQLInternalDomainConfig defines the struct for the domainConfig
@@ -201,6 +217,10 @@ type (
 		TxnID        *int64
 		Data         []byte
 		DataEncoding string
+		// TTLSeconds is only used when inserting, zero means no TTL
+		TTLSeconds int32
+		// FailIfExists is only used when inserting, if set the insert fails if the node exists with any TxnID
+		FailIfExists bool
 	}
 
 	// HistoryNodeFilter contains the column names within history_node table that
@@ -179,6 +179,56 @@ func (s *ExecutionManagerSuite) TestCreateWorkflowExecutionDeDup() {
 	s.IsType(&p.WorkflowExecutionAlreadyStartedError{}, err)
 }
 
+// TestCreateWorkflowExecutionReuseRunning test
+func (s *ExecutionManagerSuite) TestCreateWorkflowExecutionReuseRunning() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	domainID := uuid.New()
+	workflowID := "create-workflow-test-reuse-running"
+	runID := uuid.New()
+	nextEventID := int64(3)
+	versionHistory := p.NewVersionHistory([]byte{}, []*p.VersionHistoryItem{
+		{nextEventID, common.EmptyVersion},
+	})
+	req := &p.CreateWorkflowExecutionRequest{
+		NewWorkflowSnapshot: p.WorkflowSnapshot{
+			ExecutionInfo: &p.WorkflowExecutionInfo{
+				CreateRequestID:             uuid.New(),
+				DomainID:                    domainID,
+				WorkflowID:                  workflowID,
+				RunID:                       runID,
+				TaskList:                    "some random tasklist",
+				WorkflowTypeName:            "some random workflow type",
+				WorkflowTimeout:             10,
+				DecisionStartToCloseTimeout: 14,
+				LastFirstEventID:            common.FirstEventID,
+				NextEventID:                 nextEventID,
+				State:                       p.WorkflowStateCreated,
+				CloseStatus:                 p.WorkflowCloseStatusNone,
+			},
+			ExecutionStats:   &p.ExecutionStats{},
+			VersionHistories: p.NewVersionHistories(versionHistory),
+		},
+		RangeID: s.ShardInfo.RangeID,
+		Mode:    p.CreateWorkflowModeBrandNew,
+	}
+	_, err := s.ExecutionManager.CreateWorkflowExecution(ctx, req)
+	s.NoError(err)
+
+	req.NewWorkflowSnapshot.ExecutionInfo.RunID = uuid.New()
+	req.NewWorkflowSnapshot.ExecutionInfo.CreateRequestID = uuid.New()
+	req.Mode = p.CreateWorkflowModeWorkflowIDReuse
+	req.PreviousRunID = runID
+	req.PreviousLastWriteVersion = common.EmptyVersion
+	_, err = s.ExecutionManager.CreateWorkflowExecution(ctx, req)
+	s.Error(err)
+	s.True(p.IsCurrentWorkflowStillRunningError(err), err)
+	stillRunningErr := err.(*p.CurrentWorkflowStillRunningError)
+	s.Equal(runID, stillRunningErr.RunID)
+	s.Equal(p.WorkflowStateCreated, stillRunningErr.State)
+}
+
 // TestCreateWorkflowExecutionStateCloseStatus test
 func (s *ExecutionManagerSuite) TestCreateWorkflowExecutionStateCloseStatus() {
 	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
@@ -358,7 +408,7 @@ func (s *ExecutionManagerSuite) TestCreateWorkflowExecutionWithZombieState() {
 	_, err := s.ExecutionManager.CreateWorkflowExecution(ctx, req)
 	s.Nil(err) // allow creating a zombie workflow if no current running workflow
 	_, err = s.GetCurrentWorkflowRunID(ctx, domainID, workflowID)
-	s.IsType(&types.EntityNotExistsError{}, err) // no current workflow
+	s.IsType(&p.WorkflowExecutionNotExistsError{}, err) // no current workflow
 
 	workflowExecutionRunning := types.WorkflowExecution{
 		WorkflowID: workflowID,
@@ -1102,7 +1152,7 @@ func (s *ExecutionManagerSuite) TestPersistenceStartWorkflow() {
 	s.Error(err2, "Expected workflow creation to fail.")
 	s.Nil(response)
 	log.Infof("Unable to start workflow execution: %v", err2)
-	s.IsType(&p.ShardOwnershipLostError{}, err2)
+	s.IsType(&p.ShardRangeIDMismatchError{}, err2)
 }
 
 // TestGetWorkflow test
@@ -1236,6 +1286,21 @@ func (s *ExecutionManagerSuite) TestGetWorkflow() {
 	s.Equal(createReq.NewWorkflowSnapshot.ExecutionInfo.NonRetriableErrors, info.NonRetriableErrors)
 	s.Equal(testResetPoints, *info.AutoResetPoints)
 	s.Equal(createReq.NewWorkflowSnapshot.ExecutionStats.HistorySize, state.ExecutionStats.HistorySize)
+	currentResp, err := s.ExecutionManager.GetCurrentExecution(ctx, &p.GetCurrentExecutionRequest{
+		DomainID:     info.DomainID,
+		WorkflowID:   info.WorkflowID,
+		IncludeStats: true,
+	})
+	s.NoError(err)
+	s.Equal(info.RunID, currentResp.RunID)
+	currentResp, err = s.ExecutionManager.GetCurrentExecution(ctx, &p.GetCurrentExecutionRequest{
+		DomainID:    info.DomainID,
+		WorkflowID:  info.WorkflowID,
+		Consistency: p.ReadConsistencySerial,
+	})
+	s.NoError(err)
+	s.Equal(info.RunID, currentResp.RunID)
+	s.Equal(createReq.NewWorkflowSnapshot.ExecutionStats.HistorySize, currentResp.Stats.HistorySize)
 	val, ok := info.SearchAttributes[testSearchAttrKey]
 	s.True(ok)
 	s.Equal(testSearchAttrVal, val)
@@ -1246,6 +1311,48 @@ func (s *ExecutionManagerSuite) TestGetWorkflow() {
 	s.assertChecksumsEqual(csum, state.Checksum)
 }
 
+// TestGetWorkflowExecutionDBRecordVersion test
+func (s *ExecutionManagerSuite) TestGetWorkflowExecutionDBRecordVersion() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	if s.ExecutionManager.GetName() != "cassandra" {
+		// the record version is only provided by cassandra
+		return
+	}
+
+	domainID := uuid.New()
+	workflowExecution := types.WorkflowExecution{
+		WorkflowID: "get-workflow-db-record-version-test",
+		RunID:      uuid.New(),
+	}
+	_, err := s.CreateWorkflowExecution(ctx, domainID, workflowExecution, "queue1", "wType", 20, 13, nil, 3, 0, 2, nil)
+	s.NoError(err)
+
+	getRequest := &p.GetWorkflowExecutionRequest{
+		DomainID:  domainID,
+		Execution: workflowExecution,
+	}
+	resp0, err := s.ExecutionManager.GetWorkflowExecution(ctx, getRequest)
+	s.NoError(err)
+	s.NotZero(resp0.DBRecordVersion)
+	resp1, err := s.ExecutionManager.GetWorkflowExecution(ctx, getRequest)
+	s.NoError(err)
+	s.Equal(resp0.DBRecordVersion, resp1.DBRecordVersion)
+
+	updatedInfo := copyWorkflowExecutionInfo(resp0.State.ExecutionInfo)
+	updatedInfo.LastProcessedEvent = int64(2)
+	versionHistory := p.NewVersionHistory([]byte{}, []*p.VersionHistoryItem{
+		{updatedInfo.NextEventID, common.EmptyVersion},
+	})
+	err = s.UpdateWorkflowExecution(ctx, updatedInfo, resp0.State.ExecutionStats, p.NewVersionHistories(versionHistory), nil, nil, updatedInfo.NextEventID, nil, nil, nil, nil, nil)
+	s.NoError(err)
+
+	resp2, err := s.ExecutionManager.GetWorkflowExecution(ctx, getRequest)
+	s.NoError(err)
+	s.NotEqual(resp0.DBRecordVersion, resp2.DBRecordVersion)
+}
+
 // TestUpdateWorkflow test
 func (s *ExecutionManagerSuite) TestUpdateWorkflow() {
 	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
@@ -1370,6 +1477,13 @@ func (s *ExecutionManagerSuite) TestUpdateWorkflow() {
 	s.Equal(updatedInfo.ClientImpl, info1.ClientImpl)
 	s.Equal(updatedInfo.SignalCount, info1.SignalCount)
 	s.EqualValues(updatedStats.HistorySize, state1.ExecutionStats.HistorySize)
+	currentResp, err := s.ExecutionManager.GetCurrentExecution(ctx, &p.GetCurrentExecutionRequest{
+		DomainID:     domainID,
+		WorkflowID:   workflowExecution.GetWorkflowID(),
+		IncludeStats: true,
+	})
+	s.NoError(err)
+	s.EqualValues(updatedStats.HistorySize, currentResp.Stats.HistorySize)
 	s.Equal(updatedInfo.InitialInterval, info1.InitialInterval)
 	s.Equal(updatedInfo.BackoffCoefficient, info1.BackoffCoefficient)
 	s.Equal(updatedInfo.MaximumInterval, info1.MaximumInterval)
@@ -1439,7 +1553,7 @@ func (s *ExecutionManagerSuite) TestUpdateWorkflow() {
 
 	err5 := s.UpdateWorkflowExecutionWithRangeID(ctx, failedUpdateInfo, failedUpdateStats, versionHistories, []int64{int64(5)}, nil, int64(12345), int64(5), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	s.Error(err5, "expected non nil error.")
-	s.IsType(&p.ShardOwnershipLostError{}, err5)
+	s.IsType(&p.ShardRangeIDMismatchError{}, err5)
 	log.Errorf("Conditional update failed with error: %v", err5)
 
 	state3, err6 := s.GetWorkflowExecutionInfo(ctx, domainID, workflowExecution)
@@ -1489,7 +1603,7 @@ func (s *ExecutionManagerSuite) TestUpdateWorkflow() {
 	//update with incorrect rangeID and condition(next_event_id)
 	err7 := s.UpdateWorkflowExecutionWithRangeID(ctx, failedUpdateInfo, failedUpdateStats, versionHistories, []int64{int64(5)}, nil, int64(12345), int64(3), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	s.Error(err7, "expected non nil error.")
-	s.IsType(&p.ShardOwnershipLostError{}, err7)
+	s.IsType(&p.ShardRangeIDMismatchError{}, err7)
 	log.Errorf("Conditional update failed with error: %v", err7)
 
 	state4, err8 := s.GetWorkflowExecutionInfo(ctx, domainID, workflowExecution)
@@ -1577,7 +1691,7 @@ func (s *ExecutionManagerSuite) TestDeleteWorkflow() {
 
 	_, err3 := s.GetWorkflowExecutionInfo(ctx, domainID, workflowExecution)
 	s.Error(err3, "expected non nil error.")
-	s.IsType(&types.EntityNotExistsError{}, err3)
+	s.IsType(&p.WorkflowExecutionNotExistsError{}, err3)
 
 	err5 := s.DeleteWorkflowExecution(ctx, info0)
 	s.NoError(err5)
@@ -1643,7 +1757,7 @@ func (s *ExecutionManagerSuite) TestDeleteCurrentWorkflow() {
 	runID0, err1 = s.GetCurrentWorkflowRunID(ctx, domainID, workflowExecution.GetWorkflowID())
 	s.Error(err1)
 	s.Empty(runID0)
-	_, ok := err1.(*types.EntityNotExistsError)
+	_, ok := err1.(*p.WorkflowExecutionNotExistsError)
 	s.True(ok)
 
 	// execution record should still be there
@@ -1700,13 +1814,13 @@ func (s *ExecutionManagerSuite) TestUpdateDeleteWorkflow() {
 	runID0, err1 = s.GetCurrentWorkflowRunID(ctx, domainID, workflowExecution.GetWorkflowID())
 	s.Error(err1)
 	s.Empty(runID0)
-	_, ok := err1.(*types.EntityNotExistsError)
+	_, ok := err1.(*p.WorkflowExecutionNotExistsError)
 	s.True(ok)
 
 	// execution record should still be there
 	_, err2 = s.GetWorkflowExecutionInfo(ctx, domainID, workflowExecution)
 	s.Error(err2)
-	_, ok = err2.(*types.EntityNotExistsError)
+	_, ok = err2.(*p.WorkflowExecutionNotExistsError)
 	s.True(ok)
 }
 
@@ -1738,7 +1852,7 @@ func (s *ExecutionManagerSuite) TestCleanupCorruptedWorkflow() {
 	runID0, err4 := s.GetCurrentWorkflowRunID(ctx, domainID, workflowExecution.GetWorkflowID())
 	s.Error(err4)
 	s.Empty(runID0)
-	_, ok := err4.(*types.EntityNotExistsError)
+	_, ok := err4.(*p.WorkflowExecutionNotExistsError)
 	s.True(ok)
 
 	// we should still be able to load with runID
@@ -1776,10 +1890,122 @@ func (s *ExecutionManagerSuite) TestCleanupCorruptedWorkflow() {
 	// execution record should be gone
 	_, err9 := s.GetWorkflowExecutionInfo(ctx, domainID, workflowExecution)
 	s.Error(err9)
-	_, ok = err9.(*types.EntityNotExistsError)
+	_, ok = err9.(*p.WorkflowExecutionNotExistsError)
 	s.True(ok)
 }
 
+// TestIsWorkflowExecutionExists test
+func (s *ExecutionManagerSuite) TestIsWorkflowExecutionExists() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	domainID := "a3d1e9c6-5b1f-4d8e-9c1e-3f0f5a4c7b21"
+	workflowExecution := types.WorkflowExecution{
+		WorkflowID: "is-workflow-execution-exists-test",
+		RunID:      "0f8c2b6e-7f1a-4a5b-8e3d-6c9d2e1b4a70",
+	}
+
+	response, err := s.ExecutionManager.IsWorkflowExecutionExists(ctx, &p.IsWorkflowExecutionExistsRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowExecution.GetWorkflowID(),
+		RunID:      workflowExecution.GetRunID(),
+	})
+	s.NoError(err)
+	s.False(response.Exists)
+
+	_, err = s.CreateWorkflowExecution(ctx, domainID, workflowExecution, "queue1", "wType", 20, 13, nil, 3, 0, 2, nil)
+	s.NoError(err)
+
+	response, err = s.ExecutionManager.IsWorkflowExecutionExists(ctx, &p.IsWorkflowExecutionExistsRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowExecution.GetWorkflowID(),
+		RunID:      workflowExecution.GetRunID(),
+	})
+	s.NoError(err)
+	s.True(response.Exists)
+}
+
+// TestListConcreteExecutionsWithSnapshotTime test
+func (s *ExecutionManagerSuite) TestListConcreteExecutionsWithSnapshotTime() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	domainID := uuid.New()
+	var existing []string
+	for i := 0; i < 3; i++ {
+		workflowExecution := types.WorkflowExecution{
+			WorkflowID: fmt.Sprintf("list-concrete-executions-snapshot-test-%v", i),
+			RunID:      uuid.New(),
+		}
+		_, err := s.CreateWorkflowExecution(ctx, domainID, workflowExecution, "queue1", "wType", 20, 13, nil, 3, 0, 2, nil)
+		s.NoError(err)
+		existing = append(existing, domainID+"/"+workflowExecution.GetWorkflowID()+"/"+workflowExecution.GetRunID())
+	}
+	snapshotTime := time.Now()
+
+	seen := make(map[string]int)
+	var pageToken []byte
+	for page := 0; ; page++ {
+		response, err := s.ExecutionManager.ListConcreteExecutions(ctx, &p.ListConcreteExecutionsRequest{
+			PageSize:     1,
+			PageToken:    pageToken,
+			SnapshotTime: snapshotTime,
+		})
+		s.NoError(err)
+		for _, execution := range response.Executions {
+			seen[execution.ExecutionInfo.DomainID+"/"+execution.ExecutionInfo.WorkflowID+"/"+execution.ExecutionInfo.RunID]++
+		}
+
+		if page == 0 {
+			// started mid-scan with a workflow ID which sorts after the ones above, so the scan still reaches it,
+			// stores stamp the start time themselves and may truncate it to milliseconds
+			time.Sleep(2 * time.Millisecond)
+			versionHistories := p.NewVersionHistories(p.NewVersionHistory(nil, []*p.VersionHistoryItem{
+				{EventID: 2, Version: common.EmptyVersion},
+			}))
+			_, err := s.ExecutionManager.CreateWorkflowExecution(ctx, &p.CreateWorkflowExecutionRequest{
+				NewWorkflowSnapshot: p.WorkflowSnapshot{
+					ExecutionInfo: &p.WorkflowExecutionInfo{
+						CreateRequestID:             uuid.New(),
+						DomainID:                    domainID,
+						WorkflowID:                  "list-concrete-executions-snapshot-test-new",
+						RunID:                       uuid.New(),
+						TaskList:                    "queue1",
+						WorkflowTypeName:            "wType",
+						WorkflowTimeout:             20,
+						DecisionStartToCloseTimeout: 13,
+						State:                       p.WorkflowStateRunning,
+						CloseStatus:                 p.WorkflowCloseStatusNone,
+						LastFirstEventID:            common.FirstEventID,
+						NextEventID:                 3,
+						DecisionScheduleID:          2,
+						DecisionStartedID:           common.EmptyEventID,
+						DecisionTimeout:             1,
+					},
+					ExecutionStats:   &p.ExecutionStats{},
+					Checksum:         testWorkflowChecksum,
+					VersionHistories: versionHistories,
+				},
+				RangeID: s.ShardInfo.RangeID,
+			})
+			s.NoError(err)
+		}
+
+		if len(response.PageToken) == 0 {
+			break
+		}
+		pageToken = response.PageToken
+	}
+
+	for key, count := range seen {
+		s.Equal(1, count, "execution %v returned more than once", key)
+		s.NotContains(key, "list-concrete-executions-snapshot-test-new")
+	}
+	for _, key := range existing {
+		s.Contains(seen, key)
+	}
+}
+
 // TestGetCurrentWorkflow test
 func (s *ExecutionManagerSuite) TestGetCurrentWorkflow() {
 	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
@@ -1802,6 +2028,7 @@ func (s *ExecutionManagerSuite) TestGetCurrentWorkflow() {
 	s.NoError(err)
 	s.Equal(workflowExecution.GetRunID(), response.RunID)
 	s.Equal(common.EmptyVersion, response.LastWriteVersion)
+	s.Nil(response.Stats)
 
 	info0, err2 := s.GetWorkflowExecutionInfo(ctx, domainID, workflowExecution)
 	s.NoError(err2)
@@ -1831,6 +2058,108 @@ func (s *ExecutionManagerSuite) TestGetCurrentWorkflow() {
 	s.Empty(task1, "Expected empty task identifier.")
 }
 
+// TestGetCurrentRunID test
+func (s *ExecutionManagerSuite) TestGetCurrentRunID() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	domainID := "0b1a6d4e-5c7a-4f6e-9d0c-7f2b3e1a9c48"
+	workflowExecution := types.WorkflowExecution{
+		WorkflowID: "get-current-run-id-test",
+		RunID:      "e7b0c3d2-1a4f-4b8e-a6d5-2c9f8e7b6a13",
+	}
+
+	_, err := s.ExecutionManager.GetCurrentRunID(ctx, &p.GetCurrentRunIDRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowExecution.GetWorkflowID(),
+	})
+	s.IsType(&p.WorkflowExecutionNotExistsError{}, err)
+
+	task0, err0 := s.CreateWorkflowExecution(ctx, domainID, workflowExecution, "queue1", "wType", 20, 13, nil, 3, 0, 2, nil)
+	s.NoError(err0)
+	s.NotNil(task0, "Expected non empty task identifier.")
+
+	response, err := s.ExecutionManager.GetCurrentRunID(ctx, &p.GetCurrentRunIDRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowExecution.GetWorkflowID(),
+	})
+	s.NoError(err)
+	s.Equal(workflowExecution.GetRunID(), response.RunID)
+}
+
+// TestCountWorkflowExecutions test
+func (s *ExecutionManagerSuite) TestCountWorkflowExecutions() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	before, err := s.ExecutionManager.CountWorkflowExecutions(ctx, &p.CountExecutionsRequest{})
+	s.NoError(err)
+	// only the cassandra count is not read from a consistent snapshot
+	s.Equal(s.ExecutionManager.GetName() == "cassandra", before.ApproximateOnly)
+
+	domainID := "8d0a3f5e-2b7c-4e19-9a64-1f3c5b7d9e20"
+	workflowExecution := types.WorkflowExecution{
+		WorkflowID: "count-workflow-executions-test",
+		RunID:      "4c2e6a8b-0d1f-4a3c-8e5b-7f9a1c3e5d70",
+	}
+	_, err = s.CreateWorkflowExecution(ctx, domainID, workflowExecution, "queue1", "wType", 20, 13, nil, 3, 0, 2, nil)
+	s.NoError(err)
+
+	// the current record written along with the execution must not be counted
+	after, err := s.ExecutionManager.CountWorkflowExecutions(ctx, &p.CountExecutionsRequest{})
+	s.NoError(err)
+	s.Equal(before.Count+1, after.Count)
+}
+
+// TestCheckCurrentExecution test
+func (s *ExecutionManagerSuite) TestCheckCurrentExecution() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	domainID := "5d3c9a1e-8f2b-4c6d-b7a0-1e9f4d2c8b57"
+	workflowExecution := types.WorkflowExecution{
+		WorkflowID: "check-current-execution-test",
+		RunID:      "a4f8e2c1-6b3d-4e9a-8c7f-0d5b1e2a3f96",
+	}
+
+	task0, err0 := s.CreateWorkflowExecution(ctx, domainID, workflowExecution, "queue1", "wType", 20, 13, nil, 3, 0, 2, nil)
+	s.NoError(err0)
+	s.NotNil(task0, "Expected non empty task identifier.")
+
+	request := &p.CheckCurrentExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowExecution.GetWorkflowID(),
+		Repair:     true,
+		RangeID:    s.ShardInfo.RangeID,
+	}
+	response, err := s.ExecutionManager.CheckCurrentExecution(ctx, request)
+	s.NoError(err)
+	s.Equal(&p.CheckCurrentExecutionResponse{CurrentRunID: workflowExecution.GetRunID(), Exists: true}, response)
+
+	err = s.ExecutionManager.DeleteWorkflowExecution(ctx, &p.DeleteWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowExecution.GetWorkflowID(),
+		RunID:      workflowExecution.GetRunID(),
+	})
+	s.NoError(err)
+
+	// a current record pointing to another run is not deleted
+	err = s.ExecutionManager.DeleteCurrentWorkflowExecution(ctx, &p.DeleteCurrentWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowExecution.GetWorkflowID(),
+		RunID:      "0e6f3b2a-9c1d-4a7e-b5f8-2d4c6a8e1b39",
+		RangeID:    s.ShardInfo.RangeID,
+	})
+	s.IsType(&p.ConditionFailedError{}, err)
+
+	response, err = s.ExecutionManager.CheckCurrentExecution(ctx, request)
+	s.NoError(err)
+	s.Equal(&p.CheckCurrentExecutionResponse{CurrentRunID: workflowExecution.GetRunID(), Repaired: true}, response)
+
+	_, err = s.ExecutionManager.CheckCurrentExecution(ctx, request)
+	s.IsType(&p.WorkflowExecutionNotExistsError{}, err)
+}
+
 // TestTransferTasksThroughUpdate test
 func (s *ExecutionManagerSuite) TestTransferTasksThroughUpdate() {
 	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
@@ -2415,6 +2744,67 @@ func (s *ExecutionManagerSuite) TestTransferTasksRangeComplete() {
 	s.Empty(txTasks, "expected empty task list.")
 }
 
+// TestTransferTasksCompleteSet test
+func (s *ExecutionManagerSuite) TestTransferTasksCompleteSet() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	domainID := "8bfb47be-5b57-4d55-9109-5fb35e20b1d8"
+	workflowExecution := types.WorkflowExecution{
+		WorkflowID: "get-transfer-tasks-test-complete-set",
+		RunID:      "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaab",
+	}
+	tasklist := "some random tasklist"
+
+	task0, err := s.CreateWorkflowExecution(ctx, domainID, workflowExecution, tasklist, "wType", 20, 13, nil, 3, 0, 2, nil)
+	s.NoError(err)
+	s.NotNil(task0, "Expected non empty task identifier.")
+
+	state0, err := s.GetWorkflowExecutionInfo(ctx, domainID, workflowExecution)
+	s.NoError(err)
+	updatedInfo := copyWorkflowExecutionInfo(state0.ExecutionInfo)
+	updatedStats := copyExecutionStats(state0.ExecutionStats)
+	updatedInfo.NextEventID = int64(6)
+	updatedInfo.LastProcessedEvent = int64(2)
+	scheduleID := int64(123)
+	currentTransferID := s.GetTransferReadLevel()
+	now := time.Now()
+	tasks := []p.Task{
+		&p.ActivityTask{now, currentTransferID + 10001, domainID, tasklist, scheduleID, 111},
+		&p.ActivityTask{now, currentTransferID + 10002, domainID, tasklist, scheduleID, 222},
+		&p.ActivityTask{now, currentTransferID + 10003, domainID, tasklist, scheduleID, 333},
+		&p.ActivityTask{now, currentTransferID + 10004, domainID, tasklist, scheduleID, 444},
+	}
+	versionHistory := p.NewVersionHistory([]byte{}, []*p.VersionHistoryItem{
+		{scheduleID, common.EmptyVersion},
+	})
+	versionHistories := p.NewVersionHistories(versionHistory)
+	err = s.UpdateWorklowStateAndReplication(ctx, updatedInfo, updatedStats, versionHistories, int64(3), tasks)
+	s.NoError(err)
+
+	txTasks, err := s.GetTransferTasks(ctx, 100, true)
+	s.NoError(err)
+	s.Equal(len(tasks)+1, len(txTasks))
+
+	// complete a sparse and unsorted set of tasks, including the decision task of the workflow creation
+	err = s.CompleteTransferTasks(ctx, []int64{txTasks[4].TaskID, txTasks[0].TaskID, txTasks[2].TaskID})
+	s.NoError(err)
+	err = s.CompleteTransferTasks(ctx, nil)
+	s.NoError(err)
+
+	remainingTasks, err := s.GetTransferTasks(ctx, 100, true)
+	s.NoError(err)
+	s.Equal(2, len(remainingTasks))
+	s.Equal(txTasks[1].TaskID, remainingTasks[0].TaskID)
+	s.Equal(txTasks[3].TaskID, remainingTasks[1].TaskID)
+
+	err = s.CompleteTransferTasks(ctx, []int64{txTasks[1].TaskID, txTasks[3].TaskID})
+	s.NoError(err)
+	remainingTasks, err = s.GetTransferTasks(ctx, 100, false)
+	s.NoError(err)
+	s.Empty(remainingTasks, "expected empty task list.")
+}
+
 // TestTimerTasksComplete test
 func (s *ExecutionManagerSuite) TestTimerTasksComplete() {
 	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
@@ -3997,6 +4387,71 @@ func (s *ExecutionManagerSuite) TestConflictResolveWorkflowExecutionWithCASMisma
 	s.Equal(workflowExecutionCurrent.GetRunID(), runID)
 }
 
+// TestConflictResolveWorkflowExecutionDryRun test
+func (s *ExecutionManagerSuite) TestConflictResolveWorkflowExecutionDryRun() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	domainID := "4ca1faac-1a3a-47af-8e51-fdaa2b3d45b9"
+	workflowExecution := types.WorkflowExecution{
+		WorkflowID: "test-reset-mutable-state-test-dry-run",
+		RunID:      "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaa2",
+	}
+	nextEventID := int64(3)
+	_, err := s.CreateWorkflowExecutionWithReplication(
+		ctx,
+		domainID,
+		workflowExecution,
+		"taskList",
+		"wType",
+		20,
+		13,
+		nextEventID,
+		0,
+		2,
+		nil,
+	)
+	s.NoError(err)
+
+	state, err := s.GetWorkflowExecutionInfo(ctx, domainID, workflowExecution)
+	s.NoError(err)
+	resetInfo := copyWorkflowExecutionInfo(state.ExecutionInfo)
+	resetInfo.NextEventID = int64(5)
+	resetInfo.LastProcessedEvent = int64(2)
+	versionHistories := p.NewVersionHistories(p.NewVersionHistory([]byte{}, []*p.VersionHistoryItem{
+		{resetInfo.LastProcessedEvent, common.EmptyVersion},
+	}))
+	newRequest := func(rangeID int64, condition int64) *p.ConflictResolveWorkflowExecutionRequest {
+		return &p.ConflictResolveWorkflowExecutionRequest{
+			RangeID: rangeID,
+			Mode:    p.ConflictResolveWorkflowModeUpdateCurrent,
+			ResetWorkflowSnapshot: p.WorkflowSnapshot{
+				ExecutionInfo:    resetInfo,
+				ExecutionStats:   copyExecutionStats(state.ExecutionStats),
+				Condition:        condition,
+				Checksum:         testWorkflowChecksum,
+				VersionHistories: versionHistories,
+			},
+			Encoding: pickRandomEncoding(),
+			DryRun:   true,
+		}
+	}
+
+	err = s.ExecutionManager.ConflictResolveWorkflowExecution(ctx, newRequest(s.ShardInfo.RangeID, nextEventID))
+	s.NoError(err)
+
+	err = s.ExecutionManager.ConflictResolveWorkflowExecution(ctx, newRequest(s.ShardInfo.RangeID, nextEventID+1))
+	s.IsType(&p.ConditionFailedError{}, err)
+
+	err = s.ExecutionManager.ConflictResolveWorkflowExecution(ctx, newRequest(s.ShardInfo.RangeID+1, nextEventID))
+	s.Error(err)
+
+	// nothing is written by a dry run
+	state, err = s.GetWorkflowExecutionInfo(ctx, domainID, workflowExecution)
+	s.NoError(err)
+	s.Equal(nextEventID, state.ExecutionInfo.NextEventID)
+}
+
 // TestConflictResolveWorkflowExecutionWithTransactionCurrentIsNotSelf test
 func (s *ExecutionManagerSuite) TestConflictResolveWorkflowExecutionWithTransactionCurrentIsNotSelf() {
 	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
@@ -5097,6 +5552,137 @@ func (s *ExecutionManagerSuite) TestReplicationDLQ() {
 	s.Len(resp.Tasks, 0)
 }
 
+// TestDeleteReplicationTasksFromDLQ test
+func (s *ExecutionManagerSuite) TestDeleteReplicationTasksFromDLQ() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	sourceCluster := "test-delete-set"
+	for taskID := int64(10); taskID < 15; taskID++ {
+		err := s.PutReplicationTaskToDLQ(ctx, sourceCluster, &p.ReplicationTaskInfo{
+			DomainID:   uuid.New(),
+			WorkflowID: uuid.New(),
+			RunID:      uuid.New(),
+			TaskID:     taskID,
+			TaskType:   0,
+		})
+		s.NoError(err)
+	}
+
+	err := s.DeleteReplicationTasksFromDLQ(ctx, sourceCluster, []int64{14, 10, 12})
+	s.NoError(err)
+	resp, err := s.GetReplicationTasksFromDLQ(ctx, sourceCluster, 9, 14, 10, nil)
+	s.NoError(err)
+	s.Len(resp.Tasks, 2)
+	s.Equal(int64(11), resp.Tasks[0].TaskID)
+	s.Equal(int64(13), resp.Tasks[1].TaskID)
+
+	// deleting missing tasks or an empty set is not an error
+	err = s.DeleteReplicationTasksFromDLQ(ctx, sourceCluster, []int64{10, 100})
+	s.NoError(err)
+	err = s.DeleteReplicationTasksFromDLQ(ctx, sourceCluster, nil)
+	s.NoError(err)
+
+	err = s.RangeDeleteReplicationTaskFromDLQ(ctx, sourceCluster, 9, 14)
+	s.NoError(err)
+}
+
+// TestPutReplicationTasksToDLQ test
+func (s *ExecutionManagerSuite) TestPutReplicationTasksToDLQ() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	sourceCluster := "test-put-batch"
+	var taskInfos []*p.ReplicationTaskInfo
+	for taskID := int64(1); taskID <= 5; taskID++ {
+		taskInfos = append(taskInfos, &p.ReplicationTaskInfo{
+			DomainID:   uuid.New(),
+			WorkflowID: uuid.New(),
+			RunID:      uuid.New(),
+			TaskID:     taskID,
+			TaskType:   0,
+		})
+	}
+
+	err := s.PutReplicationTasksToDLQ(ctx, sourceCluster, taskInfos)
+	s.NoError(err)
+	// tasks are immutable, so putting them again is not an error
+	err = s.PutReplicationTasksToDLQ(ctx, sourceCluster, taskInfos[3:])
+	s.NoError(err)
+	err = s.PutReplicationTasksToDLQ(ctx, sourceCluster, nil)
+	s.NoError(err)
+
+	resp, err := s.GetReplicationTasksFromDLQ(ctx, sourceCluster, 0, 5, 10, nil)
+	s.NoError(err)
+	s.Len(resp.Tasks, len(taskInfos))
+	for i, task := range resp.Tasks {
+		s.Equal(taskInfos[i].TaskID, task.TaskID)
+		s.Equal(taskInfos[i].WorkflowID, task.WorkflowID)
+	}
+
+	err = s.RangeDeleteReplicationTaskFromDLQ(ctx, sourceCluster, 0, 5)
+	s.NoError(err)
+}
+
+// TestReplicationDLQWithTaskTypeFilter test
+func (s *ExecutionManagerSuite) TestReplicationDLQWithTaskTypeFilter() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	sourceCluster := "test-task-type-filter"
+	taskTypes := []int{
+		p.ReplicationTaskTypeHistory,
+		p.ReplicationTaskTypeSyncActivity,
+		p.ReplicationTaskTypeHistory,
+		p.ReplicationTaskTypeSyncActivity,
+		p.ReplicationTaskTypeFailoverMarker,
+		p.ReplicationTaskTypeSyncActivity,
+	}
+	for i, taskType := range taskTypes {
+		err := s.PutReplicationTaskToDLQ(ctx, sourceCluster, &p.ReplicationTaskInfo{
+			DomainID:   uuid.New(),
+			WorkflowID: uuid.New(),
+			RunID:      uuid.New(),
+			TaskID:     int64(i + 1),
+			TaskType:   taskType,
+		})
+		s.NoError(err)
+	}
+
+	taskTypeFilter := p.ReplicationTaskTypeSyncActivity
+	var taskIDs []int64
+	var pageToken []byte
+	for {
+		resp, err := s.ExecutionManager.GetReplicationTasksFromDLQ(ctx, &p.GetReplicationTasksFromDLQRequest{
+			SourceClusterName: sourceCluster,
+			TaskTypeFilter:    &taskTypeFilter,
+			GetReplicationTasksRequest: p.GetReplicationTasksRequest{
+				ReadLevel:     0,
+				MaxReadLevel:  int64(len(taskTypes)),
+				BatchSize:     2,
+				NextPageToken: pageToken,
+			},
+		})
+		s.NoError(err)
+		for _, task := range resp.Tasks {
+			s.Equal(p.ReplicationTaskTypeSyncActivity, task.TaskType)
+			taskIDs = append(taskIDs, task.TaskID)
+		}
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+	s.Equal([]int64{2, 4, 6}, taskIDs)
+
+	resp, err := s.GetReplicationTasksFromDLQ(ctx, sourceCluster, 0, int64(len(taskTypes)), len(taskTypes), nil)
+	s.NoError(err)
+	s.Len(resp.Tasks, len(taskTypes))
+
+	err = s.RangeDeleteReplicationTaskFromDLQ(ctx, sourceCluster, 0, int64(len(taskTypes)))
+	s.NoError(err)
+}
+
 // TestCreateFailoverMarkerTasks test
 func (s *ExecutionManagerSuite) TestCreateFailoverMarkerTasks() {
 	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
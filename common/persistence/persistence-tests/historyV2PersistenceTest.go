@@ -359,6 +359,306 @@ func (s *HistoryV2PersistenceSuite) TestReadBranchByPagination() {
 	s.Equal(0, len(branches))
 }
 
+// TestReadBranchInReverseOrder test
+func (s *HistoryV2PersistenceSuite) TestReadBranchInReverseOrder() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	treeID := uuid.New()
+	bi, err := s.newHistoryBranch(treeID)
+	s.Nil(err)
+
+	err = s.appendNewBranchAndFirstNode(ctx, bi, s.genRandomEvents([]int64{1, 2, 3}, 0), 1, "branchInfo")
+	s.Nil(err)
+	err = s.appendNewNode(ctx, bi, s.genRandomEvents([]int64{4}, 0), 2)
+	s.Nil(err)
+	err = s.appendNewNode(ctx, bi, s.genRandomEvents([]int64{5, 6, 7, 8}, 0), 3)
+	s.Nil(err)
+	err = s.appendNewNode(ctx, bi, s.genRandomEvents([]int64{9}, 0), 4)
+	s.Nil(err)
+	err = s.appendNewNode(ctx, bi, s.genRandomEvents([]int64{10}, 0), 5)
+	s.Nil(err)
+
+	req := &p.ReadHistoryBranchRequest{
+		BranchToken:  bi,
+		MinEventID:   1,
+		MaxEventID:   11,
+		PageSize:     2,
+		ShardID:      common.IntPtr(s.ShardInfo.ShardID),
+		ReverseOrder: true,
+	}
+	batchResp, err := s.HistoryV2Mgr.ReadHistoryBranchByBatch(ctx, req)
+	s.Nil(err)
+	s.Equal(2, len(batchResp.History))
+	s.Equal(int64(10), batchResp.History[0].Events[0].GetEventID())
+	s.Equal(int64(9), batchResp.History[1].Events[0].GetEventID())
+	s.Equal(int64(9), batchResp.LastFirstEventID)
+	s.NotEmpty(batchResp.NextPageToken)
+
+	var eventIDs []int64
+	for {
+		resp, err := s.HistoryV2Mgr.ReadHistoryBranch(ctx, req)
+		s.Nil(err)
+		for _, event := range resp.HistoryEvents {
+			eventIDs = append(eventIDs, event.GetEventID())
+		}
+		if len(resp.NextPageToken) == 0 {
+			break
+		}
+		req.NextPageToken = resp.NextPageToken
+	}
+	s.Equal([]int64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}, eventIDs)
+}
+
+// TestReadBranchWithBlobs test
+func (s *HistoryV2PersistenceSuite) TestReadBranchWithBlobs() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	treeID := uuid.New()
+	bi, err := s.newHistoryBranch(treeID)
+	s.Nil(err)
+
+	err = s.appendNewBranchAndFirstNode(ctx, bi, s.genRandomEvents([]int64{1, 2, 3}, 0), 1, "branchInfo")
+	s.Nil(err)
+	err = s.appendNewNode(ctx, bi, s.genRandomEvents([]int64{4}, 0), 2)
+	s.Nil(err)
+	// the node is overridden by a later transaction, only the latest batch is returned
+	err = s.appendNewNode(ctx, bi, s.genRandomEvents([]int64{5, 6}, 0), 3)
+	s.Nil(err)
+	err = s.appendNewNode(ctx, bi, s.genRandomEvents([]int64{5, 6, 7}, 1), 4)
+	s.Nil(err)
+
+	req := &p.ReadHistoryBranchRequest{
+		BranchToken: bi,
+		MinEventID:  1,
+		MaxEventID:  8,
+		PageSize:    10,
+		ShardID:     common.IntPtr(s.ShardInfo.ShardID),
+	}
+	resp, err := s.HistoryV2Mgr.ReadHistoryBranchWithBlobs(ctx, req)
+	s.Nil(err)
+	s.Equal(3, len(resp.History))
+	s.Equal(len(resp.History), len(resp.HistoryEventBlobs))
+	s.Equal(int64(5), resp.LastFirstEventID)
+	serializer := p.NewPayloadSerializer()
+	for i, batch := range resp.History {
+		events, err := serializer.DeserializeBatchEvents(resp.HistoryEventBlobs[i])
+		s.Nil(err)
+		s.Equal(batch.Events, events)
+	}
+	s.Equal(int64(1), resp.History[0].Events[0].GetEventID())
+	s.Equal(int64(4), resp.History[1].Events[0].GetEventID())
+	s.Equal(3, len(resp.History[2].Events))
+
+	req.ReverseOrder = true
+	_, err = s.HistoryV2Mgr.ReadHistoryBranchWithBlobs(ctx, req)
+	s.IsType(&p.InvalidPersistenceRequestError{}, err)
+}
+
+// TestReadHistoryNode test
+func (s *HistoryV2PersistenceSuite) TestReadHistoryNode() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	treeID := uuid.New()
+	bi, err := s.newHistoryBranch(treeID)
+	s.Nil(err)
+
+	err = s.appendNewBranchAndFirstNode(ctx, bi, s.genRandomEvents([]int64{1, 2, 3}, 0), 1, "branchInfo")
+	s.Nil(err)
+	err = s.appendNewNode(ctx, bi, s.genRandomEvents([]int64{4}, 0), 2)
+	s.Nil(err)
+	// the node is overridden by a later transaction
+	err = s.appendNewNode(ctx, bi, s.genRandomEvents([]int64{5, 6}, 0), 3)
+	s.Nil(err)
+	err = s.appendNewNode(ctx, bi, s.genRandomEvents([]int64{5, 6, 7}, 1), 4)
+	s.Nil(err)
+
+	req := &p.ReadHistoryNodeRequest{
+		BranchToken: bi,
+		NodeID:      5,
+		ShardID:     common.IntPtr(s.ShardInfo.ShardID),
+	}
+	resp, err := s.HistoryV2Mgr.ReadHistoryNode(ctx, req)
+	s.Nil(err)
+	s.Equal(int64(4), resp.TransactionID)
+	s.Equal(3, len(resp.Events))
+	s.Equal(int64(5), resp.Events[0].GetEventID())
+	s.Equal(int64(1), resp.Events[0].GetVersion())
+
+	// a node of the ancestor can be read through the forked branch
+	forked, err := s.fork(ctx, bi, 5)
+	s.Nil(err)
+	req.BranchToken = forked
+	req.NodeID = 4
+	resp, err = s.HistoryV2Mgr.ReadHistoryNode(ctx, req)
+	s.Nil(err)
+	s.Equal(int64(2), resp.TransactionID)
+	s.Equal(1, len(resp.Events))
+	s.Equal(int64(4), resp.Events[0].GetEventID())
+
+	// event 2 is not the first event of a batch
+	req.BranchToken = bi
+	req.NodeID = 2
+	_, err = s.HistoryV2Mgr.ReadHistoryNode(ctx, req)
+	s.IsType(&types.EntityNotExistsError{}, err)
+
+	err = s.deleteHistoryBranch(ctx, forked)
+	s.Nil(err)
+	err = s.deleteHistoryBranch(ctx, bi)
+	s.Nil(err)
+}
+
+// TestAppendHistoryNodesFailIfExists test
+func (s *HistoryV2PersistenceSuite) TestAppendHistoryNodesFailIfExists() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	treeID := uuid.New()
+	bi, err := s.newHistoryBranch(treeID)
+	s.Nil(err)
+
+	appendFailIfExists := func(events []*types.HistoryEvent, txnID int64, isNewBranch bool) error {
+		_, err := s.HistoryV2Mgr.AppendHistoryNodes(ctx, &p.AppendHistoryNodesRequest{
+			IsNewBranch:   isNewBranch,
+			Info:          "branchInfo",
+			BranchToken:   bi,
+			Events:        events,
+			TransactionID: txnID,
+			Encoding:      pickRandomEncoding(),
+			ShardID:       common.IntPtr(s.ShardInfo.ShardID),
+			FailIfExists:  true,
+		})
+		return err
+	}
+
+	err = appendFailIfExists(s.genRandomEvents([]int64{1, 2}, 0), 1, true)
+	s.Nil(err)
+	err = appendFailIfExists(s.genRandomEvents([]int64{3}, 0), 2, false)
+	s.Nil(err)
+
+	// a larger transaction ID does not overwrite the node
+	err = appendFailIfExists(s.genRandomEvents([]int64{3, 4}, 1), 3, false)
+	s.IsType(&p.HistoryNodeConflictError{}, err)
+	s.Equal(int64(3), err.(*p.HistoryNodeConflictError).NodeID)
+	// neither does the same transaction ID
+	err = appendFailIfExists(s.genRandomEvents([]int64{1, 2}, 0), 1, false)
+	s.IsType(&p.HistoryNodeConflictError{}, err)
+
+	events := s.read(ctx, bi, 1, 5)
+	s.Equal(3, len(events))
+	s.Equal(int64(0), events[2].GetVersion())
+
+	err = s.deleteHistoryBranch(ctx, bi)
+	s.Nil(err)
+}
+
+// TestAppendHistoryNodesWithCompression test
+func (s *HistoryV2PersistenceSuite) TestAppendHistoryNodesWithCompression() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	treeID := uuid.New()
+	bi, err := s.newHistoryBranch(treeID)
+	s.Nil(err)
+
+	appendCompressed := func(events []*types.HistoryEvent, txnID int64, isNewBranch bool, compression p.CompressionType) {
+		_, err := s.HistoryV2Mgr.AppendHistoryNodes(ctx, &p.AppendHistoryNodesRequest{
+			IsNewBranch:   isNewBranch,
+			Info:          "branchInfo",
+			BranchToken:   bi,
+			Events:        events,
+			TransactionID: txnID,
+			Encoding:      pickRandomEncoding(),
+			Compression:   compression,
+			ShardID:       common.IntPtr(s.ShardInfo.ShardID),
+		})
+		s.Nil(err)
+	}
+
+	// compressed and uncompressed nodes can be mixed in a branch
+	firstBatch := s.genRandomEvents([]int64{1, 2}, 0)
+	appendCompressed(firstBatch, 1, true, p.CompressionTypeGzip)
+	appendCompressed(s.genRandomEvents([]int64{3}, 0), 2, false, p.CompressionTypeNone)
+	appendCompressed(s.genRandomEvents([]int64{4, 5}, 0), 3, false, p.CompressionTypeGzip)
+
+	events := s.read(ctx, bi, 1, 6)
+	s.Equal(5, len(events))
+	s.Equal(firstBatch, events[:2])
+
+	node, err := s.HistoryV2Mgr.ReadHistoryNode(ctx, &p.ReadHistoryNodeRequest{
+		BranchToken: bi,
+		NodeID:      4,
+		ShardID:     common.IntPtr(s.ShardInfo.ShardID),
+	})
+	s.Nil(err)
+	s.Equal(2, len(node.Events))
+
+	rawResp, err := s.HistoryV2Mgr.ReadRawHistoryBranch(ctx, &p.ReadHistoryBranchRequest{
+		BranchToken: bi,
+		MinEventID:  1,
+		MaxEventID:  6,
+		PageSize:    10,
+		ShardID:     common.IntPtr(s.ShardInfo.ShardID),
+	})
+	s.Nil(err)
+	s.Equal(3, len(rawResp.HistoryEventBlobs))
+	for _, blob := range rawResp.HistoryEventBlobs {
+		s.Equal(p.CompressionTypeNone, blob.Compression)
+	}
+
+	_, err = s.HistoryV2Mgr.AppendHistoryNodes(ctx, &p.AppendHistoryNodesRequest{
+		BranchToken:   bi,
+		Events:        s.genRandomEvents([]int64{6}, 0),
+		TransactionID: 4,
+		Compression:   p.CompressionType("unknown"),
+		ShardID:       common.IntPtr(s.ShardInfo.ShardID),
+	})
+	s.IsType(&p.InvalidPersistenceRequestError{}, err)
+
+	err = s.deleteHistoryBranch(ctx, bi)
+	s.Nil(err)
+}
+
+// TestGetHistoryTreeWithPagination test
+func (s *HistoryV2PersistenceSuite) TestGetHistoryTreeWithPagination() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	treeID := uuid.New()
+	bi, err := s.newHistoryBranch(treeID)
+	s.Nil(err)
+	err = s.appendNewBranchAndFirstNode(ctx, bi, s.genRandomEvents([]int64{1, 2, 3}, 0), 1, "branchInfo")
+	s.Nil(err)
+	for i := 0; i < 2; i++ {
+		_, err = s.fork(ctx, bi, 2)
+		s.Nil(err)
+	}
+	s.Equal(3, len(s.descTree(ctx, treeID)))
+
+	branchIDs := make(map[string]struct{})
+	var token []byte
+	for {
+		resp, err := s.HistoryV2Mgr.GetHistoryTree(ctx, &p.GetHistoryTreeRequest{
+			TreeID:        treeID,
+			ShardID:       common.IntPtr(s.ShardInfo.ShardID),
+			PageSize:      2,
+			NextPageToken: token,
+		})
+		s.Nil(err)
+		s.True(len(resp.Branches) <= 2)
+		for _, branch := range resp.Branches {
+			branchIDs[branch.GetBranchID()] = struct{}{}
+		}
+		token = resp.NextPageToken
+		if len(token) == 0 {
+			break
+		}
+	}
+	s.Equal(3, len(branchIDs))
+}
+
 // TestConcurrentlyCreateAndAppendBranches test
 func (s *HistoryV2PersistenceSuite) TestConcurrentlyCreateAndAppendBranches() {
 	ctx, cancel := context.WithTimeout(context.Background(), largeTestContextTimeout)
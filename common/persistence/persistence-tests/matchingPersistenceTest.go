@@ -116,6 +116,139 @@ func (s *MatchingPersistenceSuite) TestCreateTask() {
 	}
 }
 
+// TestCreateTasksWithIdempotencyKey test
+func (s *MatchingPersistenceSuite) TestCreateTasksWithIdempotencyKey() {
+	if s.TaskMgr.GetName() != "cassandra" {
+		// only cassandra dedups CreateTasks batches
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	domainID := "1a5a3d1c-2f2e-4a3b-9b0a-43d2b1f6f7c2"
+	workflowExecution := types.WorkflowExecution{WorkflowID: "create-tasks-idempotency-test",
+		RunID: "f2c1e3a8-6c0d-4b57-8f3e-7d0e5b9a1c44"}
+	taskList := "7d0e5b9a1c44"
+	leaseResponse, err := s.TaskMgr.LeaseTaskList(ctx, &p.LeaseTaskListRequest{
+		DomainID: domainID,
+		TaskList: taskList,
+		TaskType: p.TaskListTypeDecision,
+	})
+	s.NoError(err)
+
+	createTasks := func(key string, scheduleID int64) {
+		taskID := s.GetNextSequenceNumber()
+		_, err := s.TaskMgr.CreateTasks(ctx, &p.CreateTasksRequest{
+			TaskListInfo: leaseResponse.TaskListInfo,
+			Tasks: []*p.CreateTaskInfo{
+				{
+					TaskID:    taskID,
+					Execution: workflowExecution,
+					Data: &p.TaskInfo{
+						DomainID:   domainID,
+						WorkflowID: workflowExecution.WorkflowID,
+						RunID:      workflowExecution.RunID,
+						TaskID:     taskID,
+						ScheduleID: scheduleID,
+					},
+				},
+			},
+			IdempotencyKey: key,
+		})
+		s.NoError(err)
+	}
+
+	createTasks("batch-1", 5)
+	createTasks("batch-1", 5)
+	resp, err := s.GetTasks(ctx, domainID, taskList, p.TaskListTypeDecision, 100)
+	s.NoError(err)
+	s.Equal(1, len(resp.Tasks))
+
+	createTasks("batch-2", 6)
+	createTasks("batch-1", 7)
+	resp, err = s.GetTasks(ctx, domainID, taskList, p.TaskListTypeDecision, 100)
+	s.NoError(err)
+	s.Equal(3, len(resp.Tasks), "only the most recent batch is deduped")
+}
+
+// TestTaskListNotOwned test
+func (s *MatchingPersistenceSuite) TestTaskListNotOwned() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	domainID := "5c6e2c1d-8f0b-4e39-9d7a-0b3c6a4f2e11"
+	workflowExecution := types.WorkflowExecution{WorkflowID: "task-list-not-owned-test",
+		RunID: "9e4d7a2b-1c3f-4b6e-8a5d-2f7c9b0e4a63"}
+	taskList := "2f7c9b0e4a63"
+	leaseResponse, err := s.TaskMgr.LeaseTaskList(ctx, &p.LeaseTaskListRequest{
+		DomainID: domainID,
+		TaskList: taskList,
+		TaskType: p.TaskListTypeDecision,
+	})
+	s.NoError(err)
+	staleInfo := leaseResponse.TaskListInfo
+
+	taskID := s.GetNextSequenceNumber()
+	_, err = s.TaskMgr.CreateTasks(ctx, &p.CreateTasksRequest{
+		TaskListInfo: staleInfo,
+		Tasks: []*p.CreateTaskInfo{
+			{
+				TaskID:    taskID,
+				Execution: workflowExecution,
+				Data: &p.TaskInfo{
+					DomainID:   domainID,
+					WorkflowID: workflowExecution.WorkflowID,
+					RunID:      workflowExecution.RunID,
+					TaskID:     taskID,
+					ScheduleID: 5,
+				},
+			},
+		},
+	})
+	s.NoError(err)
+
+	// another host steals the task list
+	leaseResponse, err = s.TaskMgr.LeaseTaskList(ctx, &p.LeaseTaskListRequest{
+		DomainID: domainID,
+		TaskList: taskList,
+		TaskType: p.TaskListTypeDecision,
+	})
+	s.NoError(err)
+
+	_, err = s.TaskMgr.CreateTasks(ctx, &p.CreateTasksRequest{
+		TaskListInfo: staleInfo,
+		Tasks: []*p.CreateTaskInfo{
+			{
+				TaskID:    s.GetNextSequenceNumber(),
+				Execution: workflowExecution,
+				Data: &p.TaskInfo{
+					DomainID:   domainID,
+					WorkflowID: workflowExecution.WorkflowID,
+					RunID:      workflowExecution.RunID,
+					ScheduleID: 6,
+				},
+			},
+		},
+	})
+	s.True(p.IsTaskListNotOwnedError(err))
+	notOwnedErr := err.(*p.TaskListNotOwnedError)
+	s.Equal(staleInfo.RangeID, notOwnedErr.ExpectedRangeID)
+	s.Equal(leaseResponse.TaskListInfo.RangeID, notOwnedErr.ActualRangeID)
+
+	err = s.TaskMgr.CompleteTask(ctx, &p.CompleteTaskRequest{
+		TaskList: staleInfo,
+		TaskID:   taskID,
+	})
+	s.True(p.IsTaskListNotOwnedError(err))
+
+	err = s.TaskMgr.CompleteTask(ctx, &p.CompleteTaskRequest{
+		TaskList: leaseResponse.TaskListInfo,
+		TaskID:   taskID,
+	})
+	s.NoError(err)
+}
+
 // TestGetDecisionTasks test
 func (s *MatchingPersistenceSuite) TestGetDecisionTasks() {
 	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
@@ -399,6 +532,55 @@ func (s *MatchingPersistenceSuite) TestLeaseAndUpdateTaskListSticky() {
 	s.NoError(err)
 }
 
+// TestLeaseTaskListReadOnly test
+func (s *MatchingPersistenceSuite) TestLeaseTaskListReadOnly() {
+	domainID := "a8d1ecd5-3a2b-4d44-bb0d-6e1c5bd6fd2e"
+	taskList := "read-only-lease-tasklist"
+
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	_, err := s.TaskMgr.LeaseTaskList(ctx, &p.LeaseTaskListRequest{
+		DomainID: domainID,
+		TaskList: taskList,
+		TaskType: p.TaskListTypeDecision,
+		ReadOnly: true,
+	})
+	s.IsType(&types.EntityNotExistsError{}, err)
+
+	response, err := s.TaskMgr.LeaseTaskList(ctx, &p.LeaseTaskListRequest{
+		DomainID: domainID,
+		TaskList: taskList,
+		TaskType: p.TaskListTypeDecision,
+	})
+	s.NoError(err)
+	s.EqualValues(1, response.TaskListInfo.RangeID)
+
+	for i := 0; i < 2; i++ {
+		response, err = s.TaskMgr.LeaseTaskList(ctx, &p.LeaseTaskListRequest{
+			DomainID: domainID,
+			TaskList: taskList,
+			TaskType: p.TaskListTypeDecision,
+			ReadOnly: true,
+		})
+		s.NoError(err)
+		tli := response.TaskListInfo
+		s.EqualValues(1, tli.RangeID)
+		s.EqualValues(0, tli.AckLevel)
+		s.Equal(p.TaskListKindNormal, tli.Kind)
+	}
+
+	// the lease taken before the reads is still valid
+	response, err = s.TaskMgr.LeaseTaskList(ctx, &p.LeaseTaskListRequest{
+		DomainID: domainID,
+		TaskList: taskList,
+		TaskType: p.TaskListTypeDecision,
+		RangeID:  1,
+	})
+	s.NoError(err)
+	s.EqualValues(2, response.TaskListInfo.RangeID)
+}
+
 func (s *MatchingPersistenceSuite) deleteAllTaskList() {
 	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
 	defer cancel()
@@ -555,6 +737,60 @@ func (s *MatchingPersistenceSuite) TestListWithMultipleTaskList() {
 	s.Equal(0, len(resp.Items))
 }
 
+// TestListTaskListByDomain test
+func (s *MatchingPersistenceSuite) TestListTaskListByDomain() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	domainIDs := []string{uuid.New(), uuid.New()}
+	expectedNames := make(map[string]map[string]struct{})
+	for i, domainID := range domainIDs {
+		expectedNames[domainID] = make(map[string]struct{})
+		for j := 0; j < 3+i; j++ {
+			name := fmt.Sprintf("test-list-by-domain-%v-%v", i, j)
+			_, err := s.TaskMgr.LeaseTaskList(ctx, &p.LeaseTaskListRequest{
+				DomainID:     domainID,
+				TaskList:     name,
+				TaskType:     p.TaskListTypeDecision,
+				TaskListKind: p.TaskListKindNormal,
+			})
+			s.NoError(err)
+			expectedNames[domainID][name] = struct{}{}
+		}
+	}
+
+	for _, domainID := range domainIDs {
+		for _, pageSize := range []int{1, 2, 100} {
+			listedNames := make(map[string]struct{})
+			var nextPageToken []byte
+			for {
+				resp, err := s.TaskMgr.ListTaskListByDomain(ctx, &p.ListTaskListByDomainRequest{
+					DomainID:  domainID,
+					PageSize:  pageSize,
+					PageToken: nextPageToken,
+				})
+				s.NoError(err)
+				s.True(len(resp.Items) <= pageSize)
+				for _, it := range resp.Items {
+					s.Equal(domainID, it.DomainID)
+					s.Equal(p.TaskListTypeDecision, it.TaskType)
+					_, ok := listedNames[it.Name]
+					s.False(ok, "list API returns duplicate entries - have: %+v got:%v", listedNames, it.Name)
+					listedNames[it.Name] = struct{}{}
+				}
+				nextPageToken = resp.NextPageToken
+				if len(nextPageToken) == 0 {
+					break
+				}
+			}
+			s.Equal(expectedNames[domainID], listedNames, "page size %v", pageSize)
+		}
+	}
+
+	_, err := s.TaskMgr.ListTaskListByDomain(ctx, &p.ListTaskListByDomainRequest{DomainID: domainIDs[0]})
+	s.IsType(&p.InvalidPersistenceRequestError{}, err)
+}
+
 func (s *MatchingPersistenceSuite) TestGetOrphanTasks() {
 	if s.TaskMgr.GetName() == "cassandra" {
 		// GetOrphanTasks API is currently not supported in cassandra"
@@ -625,3 +861,74 @@ func (s *MatchingPersistenceSuite) TestGetOrphanTasks() {
 	}
 	s.True(found)
 }
+
+// TestGetOrphanTasksPagination test
+func (s *MatchingPersistenceSuite) TestGetOrphanTasksPagination() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	domainID := uuid.New()
+	taskList := "test-orphan-tasks-pagination"
+	resp, err := s.TaskMgr.LeaseTaskList(ctx, &p.LeaseTaskListRequest{
+		DomainID: domainID,
+		TaskList: taskList,
+		TaskType: p.TaskListTypeActivity,
+	})
+	s.NoError(err)
+
+	wid := uuid.New()
+	rid := uuid.New()
+	var tasks []*p.CreateTaskInfo
+	for i := int64(1); i <= 3; i++ {
+		tasks = append(tasks, &p.CreateTaskInfo{
+			TaskID:    i,
+			Execution: types.WorkflowExecution{WorkflowID: wid, RunID: rid},
+			Data: &p.TaskInfo{
+				DomainID:   domainID,
+				WorkflowID: wid,
+				RunID:      rid,
+				TaskID:     i,
+				ScheduleID: i,
+			},
+		})
+	}
+	_, err = s.TaskMgr.CreateTasks(ctx, &p.CreateTasksRequest{
+		TaskListInfo: resp.TaskListInfo,
+		Tasks:        tasks,
+	})
+	s.NoError(err)
+
+	err = s.TaskMgr.DeleteTaskList(ctx, &p.DeleteTaskListRequest{
+		DomainID:     domainID,
+		TaskListName: taskList,
+		TaskListType: p.TaskListTypeActivity,
+		RangeID:      resp.TaskListInfo.RangeID,
+	})
+	s.NoError(err)
+
+	// orphans left behind by other tests are paged through as well
+	seen := make(map[p.TaskKey]struct{})
+	var found []int64
+	var pageToken []byte
+	for {
+		oresp, err := s.TaskMgr.GetOrphanTasks(ctx, &p.GetOrphanTasksRequest{
+			Limit:         2,
+			NextPageToken: pageToken,
+		})
+		s.NoError(err)
+		s.True(len(oresp.Tasks) <= 2)
+		for _, task := range oresp.Tasks {
+			_, ok := seen[*task]
+			s.False(ok, "orphan task returned twice")
+			seen[*task] = struct{}{}
+			if task.DomainID == domainID && task.TaskListName == taskList {
+				found = append(found, task.TaskID)
+			}
+		}
+		pageToken = oresp.NextPageToken
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+	s.Equal([]int64{1, 2, 3}, found)
+}
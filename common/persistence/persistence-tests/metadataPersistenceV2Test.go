@@ -371,6 +371,149 @@ func (m *MetadataPersistenceSuiteV2) TestGetDomain() {
 	m.Error(err6)
 }
 
+// TestGetDomainConfigVersion test
+func (m *MetadataPersistenceSuiteV2) TestGetDomainConfigVersion() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	id := uuid.New()
+	name := "get-domain-config-version-test-name"
+	configVersion := int64(12)
+	failoverVersion := int64(61)
+
+	_, err := m.MetadataManager.GetDomainConfigVersion(ctx, &p.GetDomainConfigVersionRequest{Name: name})
+	m.IsType(&types.EntityNotExistsError{}, err)
+
+	_, err = m.CreateDomain(
+		ctx,
+		&p.DomainInfo{
+			ID:     id,
+			Name:   name,
+			Status: p.DomainStatusRegistered,
+			Data:   map[string]string{},
+		},
+		&p.DomainConfig{
+			Retention:   1,
+			BadBinaries: types.BadBinaries{Binaries: map[string]*types.BadBinaryInfo{}},
+		},
+		&p.DomainReplicationConfig{},
+		false,
+		configVersion,
+		failoverVersion,
+		0,
+	)
+	m.NoError(err)
+
+	for _, request := range []*p.GetDomainConfigVersionRequest{{ID: id}, {Name: name}} {
+		resp, err := m.MetadataManager.GetDomainConfigVersion(ctx, request)
+		m.NoError(err)
+		m.Equal(configVersion, resp.ConfigVersion)
+		m.Equal(failoverVersion, resp.FailoverVersion)
+	}
+
+	_, err = m.MetadataManager.GetDomainConfigVersion(ctx, &p.GetDomainConfigVersionRequest{ID: id, Name: name})
+	m.IsType(&types.BadRequestError{}, err)
+}
+
+// TestBatchGetDomains test
+func (m *MetadataPersistenceSuiteV2) TestBatchGetDomains() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	ids := []string{uuid.New(), uuid.New()}
+	for i, id := range ids {
+		_, err := m.CreateDomain(
+			ctx,
+			&p.DomainInfo{
+				ID:     id,
+				Name:   fmt.Sprintf("batch-get-domains-test-name-%v", i),
+				Status: p.DomainStatusRegistered,
+				Data:   map[string]string{},
+			},
+			&p.DomainConfig{
+				Retention:   1,
+				BadBinaries: types.BadBinaries{Binaries: map[string]*types.BadBinaryInfo{}},
+			},
+			&p.DomainReplicationConfig{},
+			false,
+			0,
+			0,
+			0,
+		)
+		m.NoError(err)
+	}
+	missingID := uuid.New()
+
+	resp, err := m.MetadataManager.BatchGetDomains(ctx, &p.BatchGetDomainsRequest{
+		IDs: []string{ids[0], missingID, ids[1]},
+	})
+	m.NoError(err)
+	m.Equal(2, len(resp.Domains))
+	for i, id := range ids {
+		m.Equal(fmt.Sprintf("batch-get-domains-test-name-%v", i), resp.Domains[id].Info.Name)
+	}
+	m.Equal([]string{missingID}, resp.MissingIDs)
+
+	resp, err = m.MetadataManager.BatchGetDomains(ctx, &p.BatchGetDomainsRequest{})
+	m.NoError(err)
+	m.Empty(resp.Domains)
+	m.Empty(resp.MissingIDs)
+}
+
+// TestListDomainsWithMinNotificationVersion test
+func (m *MetadataPersistenceSuiteV2) TestListDomainsWithMinNotificationVersion() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	ids := []string{uuid.New(), uuid.New()}
+	for i, id := range ids {
+		_, err := m.CreateDomain(
+			ctx,
+			&p.DomainInfo{
+				ID:     id,
+				Name:   fmt.Sprintf("list-domains-min-notification-version-test-name-%v", i),
+				Status: p.DomainStatusRegistered,
+				Data:   map[string]string{},
+			},
+			&p.DomainConfig{
+				Retention:   1,
+				BadBinaries: types.BadBinaries{Binaries: map[string]*types.BadBinaryInfo{}},
+			},
+			&p.DomainReplicationConfig{},
+			false,
+			0,
+			0,
+			0,
+		)
+		m.NoError(err)
+	}
+	first, err := m.GetDomain(ctx, ids[0], "")
+	m.NoError(err)
+
+	listed := make(map[string]int64)
+	var token []byte
+	for {
+		resp, err := m.MetadataManager.ListDomains(ctx, &p.ListDomainsRequest{
+			PageSize:               1,
+			NextPageToken:          token,
+			MinNotificationVersion: common.Int64Ptr(first.NotificationVersion),
+		})
+		m.NoError(err)
+		for _, domain := range resp.Domains {
+			listed[domain.Info.ID] = domain.NotificationVersion
+		}
+		token = resp.NextPageToken
+		if len(token) == 0 {
+			break
+		}
+	}
+	m.NotContains(listed, ids[0])
+	m.Contains(listed, ids[1])
+	for _, notificationVersion := range listed {
+		m.True(notificationVersion > first.NotificationVersion)
+	}
+}
+
 // TestConcurrentCreateDomain test
 func (m *MetadataPersistenceSuiteV2) TestConcurrentCreateDomain() {
 	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
@@ -933,6 +1076,67 @@ func (m *MetadataPersistenceSuiteV2) TestUpdateDomain() {
 	m.Nil(resp6.FailoverEndTime)
 }
 
+// TestUpdateDomainVersionConflict test
+func (m *MetadataPersistenceSuiteV2) TestUpdateDomainVersionConflict() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	id := uuid.New()
+	name := "update-domain-version-conflict-test-name"
+	clusterActive := "some random active cluster name"
+	_, err := m.CreateDomain(
+		ctx,
+		&p.DomainInfo{
+			ID:     id,
+			Name:   name,
+			Status: p.DomainStatusRegistered,
+			Data:   map[string]string{},
+		},
+		&p.DomainConfig{Retention: 1},
+		&p.DomainReplicationConfig{
+			ActiveClusterName: clusterActive,
+			Clusters:          []*p.ClusterReplicationConfig{{ClusterName: clusterActive}},
+		},
+		false,
+		0,
+		0,
+		0,
+	)
+	m.NoError(err)
+
+	resp, err := m.GetDomain(ctx, id, "")
+	m.NoError(err)
+	metadata, err := m.MetadataManager.GetMetadata(ctx)
+	m.NoError(err)
+	staleVersion := metadata.NotificationVersion
+
+	update := func(notificationVersion int64) error {
+		return m.UpdateDomain(
+			ctx,
+			resp.Info,
+			resp.Config,
+			resp.ReplicationConfig,
+			resp.ConfigVersion,
+			resp.FailoverVersion,
+			resp.FailoverNotificationVersion,
+			resp.PreviousFailoverVersion,
+			nil,
+			notificationVersion,
+		)
+	}
+	m.NoError(update(staleVersion))
+
+	err = update(staleVersion)
+	m.Error(err)
+	m.True(p.IsDomainVersionConflictError(err), "unexpected error: %v", err)
+	conflictErr := err.(*p.DomainVersionConflictError)
+	m.Equal(staleVersion, conflictErr.ExpectedVersion)
+	m.Equal(staleVersion+1, conflictErr.ActualVersion)
+
+	// retrying with the actual version succeeds
+	m.NoError(update(conflictErr.ActualVersion))
+}
+
 // TestDeleteDomain test
 func (m *MetadataPersistenceSuiteV2) TestDeleteDomain() {
 	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
@@ -1057,6 +1261,73 @@ func (m *MetadataPersistenceSuiteV2) TestDeleteDomain() {
 	m.Nil(resp9)
 }
 
+// TestMarkDomainForDeletion test
+func (m *MetadataPersistenceSuiteV2) TestMarkDomainForDeletion() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	id := uuid.New()
+	name := "mark-domain-for-deletion-test-name"
+	resp1, err1 := m.CreateDomain(
+		ctx,
+		&p.DomainInfo{
+			ID:     id,
+			Name:   name,
+			Status: p.DomainStatusRegistered,
+		},
+		&p.DomainConfig{
+			Retention:  10,
+			EmitMetric: true,
+		},
+		&p.DomainReplicationConfig{
+			ActiveClusterName: cluster.TestCurrentClusterName,
+			Clusters: []*p.ClusterReplicationConfig{
+				{ClusterName: cluster.TestCurrentClusterName},
+			},
+		},
+		false,
+		0,
+		0,
+		0,
+	)
+	m.NoError(err1)
+	m.Equal(id, resp1.ID)
+
+	resp2, err2 := m.GetDomain(ctx, id, "")
+	m.NoError(err2)
+	m.Nil(resp2.DeletionTime)
+
+	deletionTime := time.Now().UnixNano()
+	err3 := m.MetadataManager.MarkDomainForDeletion(ctx, &p.MarkDomainForDeletionRequest{
+		Name:         name,
+		DeletionTime: deletionTime,
+	})
+	m.NoError(err3)
+
+	resp4, err4 := m.GetDomain(ctx, id, "")
+	m.NoError(err4)
+	m.Equal(p.DomainStatusDeleted, resp4.Info.Status)
+	m.Equal(resp2.ConfigVersion+1, resp4.ConfigVersion)
+	m.Equal(common.Int64Ptr(deletionTime), resp4.DeletionTime)
+
+	var token []byte
+	for {
+		resp5, err5 := m.MetadataManager.ListDomains(ctx, &p.ListDomainsRequest{
+			PageSize:       2,
+			NextPageToken:  token,
+			ExcludeDeleted: true,
+		})
+		m.NoError(err5)
+		for _, domain := range resp5.Domains {
+			m.NotEqual(id, domain.Info.ID)
+		}
+		token = resp5.NextPageToken
+		if len(token) == 0 {
+			break
+		}
+	}
+}
+
 // TestListDomains test
 func (m *MetadataPersistenceSuiteV2) TestListDomains() {
 	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
@@ -1561,6 +1561,19 @@ func (s *TestBase) PutReplicationTaskToDLQ(
 	})
 }
 
+// PutReplicationTasksToDLQ is a utility method to insert a batch of replication task infos
+func (s *TestBase) PutReplicationTasksToDLQ(
+	ctx context.Context,
+	sourceCluster string,
+	taskInfos []*p.ReplicationTaskInfo,
+) error {
+
+	return s.ExecutionManager.PutReplicationTasksToDLQ(ctx, &p.PutReplicationTasksToDLQRequest{
+		SourceClusterName: sourceCluster,
+		TaskInfos:         taskInfos,
+	})
+}
+
 // GetReplicationTasksFromDLQ is a utility method to read replication task info
 func (s *TestBase) GetReplicationTasksFromDLQ(
 	ctx context.Context,
@@ -1621,6 +1634,19 @@ func (s *TestBase) RangeDeleteReplicationTaskFromDLQ(
 	})
 }
 
+// DeleteReplicationTasksFromDLQ is a utility method to delete a set of replication task infos
+func (s *TestBase) DeleteReplicationTasksFromDLQ(
+	ctx context.Context,
+	sourceCluster string,
+	taskIDs []int64,
+) error {
+
+	return s.ExecutionManager.DeleteReplicationTasksFromDLQ(ctx, &p.DeleteReplicationTasksFromDLQRequest{
+		SourceClusterName: sourceCluster,
+		TaskIDs:           taskIDs,
+	})
+}
+
 // CreateFailoverMarkers is a utility method to create failover markers
 func (s *TestBase) CreateFailoverMarkers(
 	ctx context.Context,
@@ -1649,6 +1675,13 @@ func (s *TestBase) RangeCompleteTransferTask(ctx context.Context, exclusiveBegin
 	})
 }
 
+// CompleteTransferTasks is a utility method to complete a set of transfer tasks
+func (s *TestBase) CompleteTransferTasks(ctx context.Context, taskIDs []int64) error {
+	return s.ExecutionManager.CompleteTransferTasks(ctx, &p.CompleteTransferTasksRequest{
+		TaskIDs: taskIDs,
+	})
+}
+
 // CompleteReplicationTask is a utility method to complete a replication task
 func (s *TestBase) CompleteReplicationTask(ctx context.Context, taskID int64) error {
 
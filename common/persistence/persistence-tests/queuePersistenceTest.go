@@ -28,6 +28,8 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
+
+	p "github.com/uber/cadence/common/persistence"
 )
 
 type (
@@ -216,3 +218,47 @@ func (s *QueuePersistenceSuite) TestDomainDLQMetadataOperations() {
 	s.Require().NoError(err)
 	s.Equal(int64(10), ackLevel[clusterName])
 }
+
+// TestEnqueueMessageWithID tests enqueueing messages at caller-specified IDs
+func (s *QueuePersistenceSuite) TestEnqueueMessageWithID() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	messageID := int64(1 << 20)
+	err := s.DomainReplicationQueueMgr.EnqueueMessageWithID(ctx, []byte{1}, messageID)
+	s.NoError(err)
+
+	err = s.DomainReplicationQueueMgr.EnqueueMessageWithID(ctx, []byte{2}, messageID)
+	s.IsType(&p.QueueMessageIDConflictError{}, err)
+	s.Equal(messageID, err.(*p.QueueMessageIDConflictError).MessageID)
+
+	result, err := s.GetReplicationMessages(ctx, messageID-1, 1)
+	s.NoError(err)
+	s.Len(result, 1)
+	s.Equal(messageID, result[0].ID)
+	s.Equal([]byte{1}, result[0].Payload)
+}
+
+// TestEnqueueMessages tests enqueueing a batch of messages
+func (s *QueuePersistenceSuite) TestEnqueueMessages() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	messageIDs, err := s.DomainReplicationQueueMgr.EnqueueMessages(ctx, [][]byte{{1}, {2}, {3}})
+	s.NoError(err)
+	s.Len(messageIDs, 3)
+	s.Equal(messageIDs[0]+1, messageIDs[1])
+	s.Equal(messageIDs[1]+1, messageIDs[2])
+
+	result, err := s.GetReplicationMessages(ctx, messageIDs[0]-1, 3)
+	s.NoError(err)
+	s.Len(result, 3)
+	for i, message := range result {
+		s.Equal(messageIDs[i], message.ID)
+		s.Equal([]byte{byte(i + 1)}, message.Payload)
+	}
+
+	nextMessageIDs, err := s.DomainReplicationQueueMgr.EnqueueMessages(ctx, [][]byte{{4}})
+	s.NoError(err)
+	s.Equal([]int64{messageIDs[2] + 1}, nextMessageIDs)
+}
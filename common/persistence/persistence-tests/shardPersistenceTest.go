@@ -100,6 +100,70 @@ func (s *ShardPersistenceSuite) TestGetShard() {
 	log.Infof("GetShard failed with error: %v", err2)
 }
 
+// TestGetShards test
+func (s *ShardPersistenceSuite) TestGetShards() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	owner := "test_get_shards"
+	shardIDs := []int{21, 22, 23}
+	for i, shardID := range shardIDs {
+		err := s.CreateShard(ctx, shardID, owner, int64(151+i))
+		s.Nil(err, "No error expected.")
+	}
+
+	resp, err := s.ShardMgr.GetShards(ctx, &p.GetShardsRequest{
+		ShardIDs: []int{21, 22, 4767, 23},
+	})
+	s.NoError(err)
+	s.Len(resp.ShardInfos, len(shardIDs))
+	for i, shardID := range shardIDs {
+		shardInfo, ok := resp.ShardInfos[shardID]
+		s.True(ok)
+		s.Equal(shardID, shardInfo.ShardID)
+		s.Equal(owner, shardInfo.Owner)
+		s.Equal(int64(151+i), shardInfo.RangeID)
+	}
+	s.Equal([]int{4767}, resp.NotFoundShardIDs)
+}
+
+// TestListShards test
+func (s *ShardPersistenceSuite) TestListShards() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	owner := "test_list_shards"
+	shardIDs := []int{41, 42, 43}
+	for i, shardID := range shardIDs {
+		err := s.CreateShard(ctx, shardID, owner, int64(161+i))
+		s.Nil(err, "No error expected.")
+	}
+
+	found := make(map[int]*p.ShardInfo)
+	var pageToken []byte
+	for {
+		resp, err := s.ShardMgr.ListShards(ctx, &p.ListShardsRequest{
+			PageSize:  2,
+			PageToken: pageToken,
+		})
+		s.NoError(err)
+		for _, shardInfo := range resp.ShardInfos {
+			found[shardInfo.ShardID] = shardInfo
+		}
+		if len(resp.NextPageToken) == 0 {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	for i, shardID := range shardIDs {
+		shardInfo, ok := found[shardID]
+		s.True(ok)
+		s.Equal(owner, shardInfo.Owner)
+		s.Equal(int64(161+i), shardInfo.RangeID)
+	}
+}
+
 // TestUpdateShard test
 func (s *ShardPersistenceSuite) TestUpdateShard() {
 	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
@@ -165,6 +229,48 @@ func (s *ShardPersistenceSuite) TestUpdateShard() {
 	s.EqualTimes(updatedTimerAckLevel, info1.TimerAckLevel)
 }
 
+// TestUpdateShardWithPreviousOwner test
+func (s *ShardPersistenceSuite) TestUpdateShardWithPreviousOwner() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	shardID := 31
+	owner := "test_update_shard_with_previous_owner"
+	rangeID := int64(141)
+	err := s.CreateShard(ctx, shardID, owner, rangeID)
+	s.NoError(err)
+
+	shardInfo, err := s.GetShard(ctx, shardID)
+	s.NoError(err)
+
+	updatedInfo := copyShardInfo(shardInfo)
+	updatedInfo.Owner = "contested_owner"
+	updatedInfo.TransferAckLevel = int64(1000)
+	err = s.ShardMgr.UpdateShard(ctx, &p.UpdateShardRequest{
+		ShardInfo:       updatedInfo,
+		PreviousRangeID: rangeID,
+		PreviousOwner:   "other_owner",
+	})
+	s.IsType(&p.ShardOwnershipLostError{}, err)
+
+	info, err := s.GetShard(ctx, shardID)
+	s.NoError(err)
+	s.Equal(owner, info.Owner)
+	s.Equal(shardInfo.TransferAckLevel, info.TransferAckLevel)
+
+	err = s.ShardMgr.UpdateShard(ctx, &p.UpdateShardRequest{
+		ShardInfo:       updatedInfo,
+		PreviousRangeID: rangeID,
+		PreviousOwner:   owner,
+	})
+	s.NoError(err)
+
+	info, err = s.GetShard(ctx, shardID)
+	s.NoError(err)
+	s.Equal("contested_owner", info.Owner)
+	s.Equal(int64(1000), info.TransferAckLevel)
+}
+
 func copyShardInfo(sourceInfo *p.ShardInfo) *p.ShardInfo {
 	return &p.ShardInfo{
 		ShardID:             sourceInfo.ShardID,
@@ -0,0 +1,1621 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"context"
+
+	"github.com/uber/cadence/common/log"
+)
+
+type (
+	shardCircuitBreakerPersistenceClient struct {
+		circuitBreaker CircuitBreaker
+		persistence    ShardManager
+		logger         log.Logger
+	}
+
+	workflowExecutionCircuitBreakerPersistenceClient struct {
+		executionManagerCompositeOperations
+
+		circuitBreaker CircuitBreaker
+		persistence    ExecutionManager
+		logger         log.Logger
+	}
+
+	taskCircuitBreakerPersistenceClient struct {
+		taskManagerCompositeOperations
+
+		circuitBreaker CircuitBreaker
+		persistence    TaskManager
+		logger         log.Logger
+	}
+
+	historyCircuitBreakerPersistenceClient struct {
+		historyManagerCompositeOperations
+
+		circuitBreaker CircuitBreaker
+		persistence    HistoryManager
+		logger         log.Logger
+	}
+
+	metadataCircuitBreakerPersistenceClient struct {
+		metadataManagerCompositeOperations
+
+		circuitBreaker CircuitBreaker
+		persistence    MetadataManager
+		logger         log.Logger
+	}
+
+	visibilityCircuitBreakerPersistenceClient struct {
+		circuitBreaker CircuitBreaker
+		persistence    VisibilityManager
+		logger         log.Logger
+	}
+
+	queueCircuitBreakerPersistenceClient struct {
+		circuitBreaker CircuitBreaker
+		persistence    QueueManager
+		logger         log.Logger
+	}
+)
+
+var _ ShardManager = (*shardCircuitBreakerPersistenceClient)(nil)
+var _ ExecutionManager = (*workflowExecutionCircuitBreakerPersistenceClient)(nil)
+var _ TaskManager = (*taskCircuitBreakerPersistenceClient)(nil)
+var _ HistoryManager = (*historyCircuitBreakerPersistenceClient)(nil)
+var _ MetadataManager = (*metadataCircuitBreakerPersistenceClient)(nil)
+var _ VisibilityManager = (*visibilityCircuitBreakerPersistenceClient)(nil)
+var _ QueueManager = (*queueCircuitBreakerPersistenceClient)(nil)
+
+// NewShardPersistenceCircuitBreakerClient creates a client to manage shards
+func NewShardPersistenceCircuitBreakerClient(
+	persistence ShardManager,
+	circuitBreaker CircuitBreaker,
+	logger log.Logger,
+) ShardManager {
+	return &shardCircuitBreakerPersistenceClient{
+		persistence:    persistence,
+		circuitBreaker: circuitBreaker,
+		logger:         logger,
+	}
+}
+
+// NewWorkflowExecutionPersistenceCircuitBreakerClient creates a client to manage executions
+func NewWorkflowExecutionPersistenceCircuitBreakerClient(
+	persistence ExecutionManager,
+	circuitBreaker CircuitBreaker,
+	logger log.Logger,
+) ExecutionManager {
+	client := &workflowExecutionCircuitBreakerPersistenceClient{
+		persistence:    persistence,
+		circuitBreaker: circuitBreaker,
+		logger:         logger,
+	}
+	client.executionManagerCompositeOperations = executionManagerCompositeOperations{manager: client}
+	return client
+}
+
+// NewTaskPersistenceCircuitBreakerClient creates a client to manage tasks
+func NewTaskPersistenceCircuitBreakerClient(
+	persistence TaskManager,
+	circuitBreaker CircuitBreaker,
+	logger log.Logger,
+) TaskManager {
+	client := &taskCircuitBreakerPersistenceClient{
+		persistence:    persistence,
+		circuitBreaker: circuitBreaker,
+		logger:         logger,
+	}
+	client.taskManagerCompositeOperations = taskManagerCompositeOperations{manager: client}
+	return client
+}
+
+// NewHistoryPersistenceCircuitBreakerClient creates a HistoryManager client to manage workflow execution history
+func NewHistoryPersistenceCircuitBreakerClient(
+	persistence HistoryManager,
+	circuitBreaker CircuitBreaker,
+	logger log.Logger,
+) HistoryManager {
+	client := &historyCircuitBreakerPersistenceClient{
+		persistence:    persistence,
+		circuitBreaker: circuitBreaker,
+		logger:         logger,
+	}
+	client.historyManagerCompositeOperations = historyManagerCompositeOperations{manager: client}
+	return client
+}
+
+// NewMetadataPersistenceCircuitBreakerClient creates a MetadataManager client to manage metadata
+func NewMetadataPersistenceCircuitBreakerClient(
+	persistence MetadataManager,
+	circuitBreaker CircuitBreaker,
+	logger log.Logger,
+) MetadataManager {
+	client := &metadataCircuitBreakerPersistenceClient{
+		persistence:    persistence,
+		circuitBreaker: circuitBreaker,
+		logger:         logger,
+	}
+	client.metadataManagerCompositeOperations = metadataManagerCompositeOperations{manager: client}
+	return client
+}
+
+// NewVisibilityPersistenceCircuitBreakerClient creates a client to manage visibility
+func NewVisibilityPersistenceCircuitBreakerClient(
+	persistence VisibilityManager,
+	circuitBreaker CircuitBreaker,
+	logger log.Logger,
+) VisibilityManager {
+	return &visibilityCircuitBreakerPersistenceClient{
+		persistence:    persistence,
+		circuitBreaker: circuitBreaker,
+		logger:         logger,
+	}
+}
+
+// NewQueuePersistenceCircuitBreakerClient creates a client to manage queue
+func NewQueuePersistenceCircuitBreakerClient(
+	persistence QueueManager,
+	circuitBreaker CircuitBreaker,
+	logger log.Logger,
+) QueueManager {
+	return &queueCircuitBreakerPersistenceClient{
+		persistence:    persistence,
+		circuitBreaker: circuitBreaker,
+		logger:         logger,
+	}
+}
+
+func (p *shardCircuitBreakerPersistenceClient) GetName() string {
+	return p.persistence.GetName()
+}
+
+func (p *shardCircuitBreakerPersistenceClient) CreateShard(
+	ctx context.Context,
+	request *CreateShardRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.CreateShard(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *shardCircuitBreakerPersistenceClient) GetShard(
+	ctx context.Context,
+	request *GetShardRequest,
+) (*GetShardResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetShard(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *shardCircuitBreakerPersistenceClient) GetShards(
+	ctx context.Context,
+	request *GetShardsRequest,
+) (*GetShardsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetShards(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *shardCircuitBreakerPersistenceClient) ListShards(
+	ctx context.Context,
+	request *ListShardsRequest,
+) (*ListShardsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.ListShards(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *shardCircuitBreakerPersistenceClient) UpdateShard(
+	ctx context.Context,
+	request *UpdateShardRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.UpdateShard(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *shardCircuitBreakerPersistenceClient) Close() {
+	p.persistence.Close()
+}
+
+// CloseWithContext closes the wrapped persistence whatever the state of the circuit breaker
+func (p *shardCircuitBreakerPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) GetName() string {
+	return p.persistence.GetName()
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) GetShardID() int {
+	return p.persistence.GetShardID()
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) CreateWorkflowExecution(
+	ctx context.Context,
+	request *CreateWorkflowExecutionRequest,
+) (*CreateWorkflowExecutionResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.CreateWorkflowExecution(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) GetWorkflowExecution(
+	ctx context.Context,
+	request *GetWorkflowExecutionRequest,
+) (*GetWorkflowExecutionResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetWorkflowExecution(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) GetWorkflowExecutionForUpdate(
+	ctx context.Context,
+	request *GetWorkflowExecutionForUpdateRequest,
+) (*GetWorkflowExecutionForUpdateResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetWorkflowExecutionForUpdate(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) UpdateWorkflowExecution(
+	ctx context.Context,
+	request *UpdateWorkflowExecutionRequest,
+) (*UpdateWorkflowExecutionResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	resp, err := p.persistence.UpdateWorkflowExecution(ctx, request)
+	p.circuitBreaker.Record(err)
+	return resp, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) FlushBufferedEvents(
+	ctx context.Context,
+	request *FlushBufferedEventsRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.FlushBufferedEvents(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) ConflictResolveWorkflowExecution(
+	ctx context.Context,
+	request *ConflictResolveWorkflowExecutionRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.ConflictResolveWorkflowExecution(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) ResetWorkflowExecution(
+	ctx context.Context,
+	request *ResetWorkflowExecutionRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.ResetWorkflowExecution(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) DeleteWorkflowExecution(
+	ctx context.Context,
+	request *DeleteWorkflowExecutionRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.DeleteWorkflowExecution(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) DeleteCurrentWorkflowExecution(
+	ctx context.Context,
+	request *DeleteCurrentWorkflowExecutionRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.DeleteCurrentWorkflowExecution(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) DeleteWorkflowExecutions(
+	ctx context.Context,
+	request *DeleteWorkflowExecutionsRequest,
+) (*DeleteWorkflowExecutionsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.DeleteWorkflowExecutions(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) GetCurrentExecution(
+	ctx context.Context,
+	request *GetCurrentExecutionRequest,
+) (*GetCurrentExecutionResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetCurrentExecution(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) GetCurrentRunID(
+	ctx context.Context,
+	request *GetCurrentRunIDRequest,
+) (*GetCurrentRunIDResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetCurrentRunID(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) ListCurrentExecutions(
+	ctx context.Context,
+	request *ListCurrentExecutionsRequest,
+) (*ListCurrentExecutionsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.ListCurrentExecutions(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) IsWorkflowExecutionExists(
+	ctx context.Context,
+	request *IsWorkflowExecutionExistsRequest,
+) (*IsWorkflowExecutionExistsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.IsWorkflowExecutionExists(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) WorkflowExists(
+	ctx context.Context,
+	request *WorkflowExistsRequest,
+) (*WorkflowExistsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.WorkflowExists(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) CheckCurrentExecution(
+	ctx context.Context,
+	request *CheckCurrentExecutionRequest,
+) (*CheckCurrentExecutionResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.CheckCurrentExecution(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) ListConcreteExecutions(
+	ctx context.Context,
+	request *ListConcreteExecutionsRequest,
+) (*ListConcreteExecutionsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.ListConcreteExecutions(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) CountWorkflowExecutions(
+	ctx context.Context,
+	request *CountExecutionsRequest,
+) (*CountExecutionsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.CountWorkflowExecutions(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) GetTransferTasks(
+	ctx context.Context,
+	request *GetTransferTasksRequest,
+) (*GetTransferTasksResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetTransferTasks(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) GetReplicationTasks(
+	ctx context.Context,
+	request *GetReplicationTasksRequest,
+) (*GetReplicationTasksResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetReplicationTasks(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) GetFailoverMarkerTasks(
+	ctx context.Context,
+	request *GetFailoverMarkerTasksRequest,
+) (*GetFailoverMarkerTasksResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetFailoverMarkerTasks(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) CompleteTransferTask(
+	ctx context.Context,
+	request *CompleteTransferTaskRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.CompleteTransferTask(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) RangeCompleteTransferTask(
+	ctx context.Context,
+	request *RangeCompleteTransferTaskRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.RangeCompleteTransferTask(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) CompleteTransferTasks(
+	ctx context.Context,
+	request *CompleteTransferTasksRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.CompleteTransferTasks(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) GetCrossClusterTasks(
+	ctx context.Context,
+	request *GetCrossClusterTasksRequest,
+) (*GetCrossClusterTasksResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetCrossClusterTasks(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) CompleteCrossClusterTask(
+	ctx context.Context,
+	request *CompleteCrossClusterTaskRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.CompleteCrossClusterTask(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) RangeCompleteCrossClusterTask(
+	ctx context.Context,
+	request *RangeCompleteCrossClusterTaskRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.RangeCompleteCrossClusterTask(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) CompleteReplicationTask(
+	ctx context.Context,
+	request *CompleteReplicationTaskRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.CompleteReplicationTask(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) RangeCompleteReplicationTask(
+	ctx context.Context,
+	request *RangeCompleteReplicationTaskRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.RangeCompleteReplicationTask(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) PutReplicationTaskToDLQ(
+	ctx context.Context,
+	request *PutReplicationTaskToDLQRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.PutReplicationTaskToDLQ(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) PutReplicationTasksToDLQ(
+	ctx context.Context,
+	request *PutReplicationTasksToDLQRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.PutReplicationTasksToDLQ(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) GetReplicationTasksFromDLQ(
+	ctx context.Context,
+	request *GetReplicationTasksFromDLQRequest,
+) (*GetReplicationTasksFromDLQResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetReplicationTasksFromDLQ(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) GetReplicationDLQSize(
+	ctx context.Context,
+	request *GetReplicationDLQSizeRequest,
+) (*GetReplicationDLQSizeResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetReplicationDLQSize(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) DeleteReplicationTaskFromDLQ(
+	ctx context.Context,
+	request *DeleteReplicationTaskFromDLQRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.DeleteReplicationTaskFromDLQ(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) RangeDeleteReplicationTaskFromDLQ(
+	ctx context.Context,
+	request *RangeDeleteReplicationTaskFromDLQRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.RangeDeleteReplicationTaskFromDLQ(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) DeleteReplicationTasksFromDLQ(
+	ctx context.Context,
+	request *DeleteReplicationTasksFromDLQRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.DeleteReplicationTasksFromDLQ(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) CreateFailoverMarkerTasks(
+	ctx context.Context,
+	request *CreateFailoverMarkersRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.CreateFailoverMarkerTasks(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) GetTimerIndexTasks(
+	ctx context.Context,
+	request *GetTimerIndexTasksRequest,
+) (*GetTimerIndexTasksResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetTimerIndexTasks(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) CompleteTimerTask(
+	ctx context.Context,
+	request *CompleteTimerTaskRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.CompleteTimerTask(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) RangeCompleteTimerTask(
+	ctx context.Context,
+	request *RangeCompleteTimerTaskRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.RangeCompleteTimerTask(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) CompleteTimerTasksForDomain(
+	ctx context.Context,
+	request *CompleteTimerTasksForDomainRequest,
+) (int, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return 0, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.CompleteTimerTasksForDomain(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *workflowExecutionCircuitBreakerPersistenceClient) Close() {
+	p.persistence.Close()
+}
+
+// CloseWithContext closes the wrapped persistence whatever the state of the circuit breaker
+func (p *workflowExecutionCircuitBreakerPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
+func (p *taskCircuitBreakerPersistenceClient) GetName() string {
+	return p.persistence.GetName()
+}
+
+func (p *taskCircuitBreakerPersistenceClient) CreateTasks(
+	ctx context.Context,
+	request *CreateTasksRequest,
+) (*CreateTasksResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.CreateTasks(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *taskCircuitBreakerPersistenceClient) GetTasks(
+	ctx context.Context,
+	request *GetTasksRequest,
+) (*GetTasksResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetTasks(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *taskCircuitBreakerPersistenceClient) CompleteTask(
+	ctx context.Context,
+	request *CompleteTaskRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.CompleteTask(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *taskCircuitBreakerPersistenceClient) CompleteTasksLessThan(
+	ctx context.Context,
+	request *CompleteTasksLessThanRequest,
+) (int, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return 0, ErrPersistenceCircuitOpen
+	}
+	response, err := p.persistence.CompleteTasksLessThan(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *taskCircuitBreakerPersistenceClient) GetOrphanTasks(ctx context.Context, request *GetOrphanTasksRequest) (*GetOrphanTasksResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+	response, err := p.persistence.GetOrphanTasks(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *taskCircuitBreakerPersistenceClient) LeaseTaskList(
+	ctx context.Context,
+	request *LeaseTaskListRequest,
+) (*LeaseTaskListResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.LeaseTaskList(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *taskCircuitBreakerPersistenceClient) UpdateTaskList(
+	ctx context.Context,
+	request *UpdateTaskListRequest,
+) (*UpdateTaskListResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.UpdateTaskList(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *taskCircuitBreakerPersistenceClient) ListTaskList(
+	ctx context.Context,
+	request *ListTaskListRequest,
+) (*ListTaskListResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+	response, err := p.persistence.ListTaskList(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *taskCircuitBreakerPersistenceClient) ListTaskListByDomain(
+	ctx context.Context,
+	request *ListTaskListByDomainRequest,
+) (*ListTaskListResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.ListTaskListByDomain(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *taskCircuitBreakerPersistenceClient) DeleteTaskList(
+	ctx context.Context,
+	request *DeleteTaskListRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+	err := p.persistence.DeleteTaskList(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *taskCircuitBreakerPersistenceClient) Close() {
+	p.persistence.Close()
+}
+
+// CloseWithContext closes the wrapped persistence whatever the state of the circuit breaker
+func (p *taskCircuitBreakerPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
+func (p *metadataCircuitBreakerPersistenceClient) GetName() string {
+	return p.persistence.GetName()
+}
+
+func (p *metadataCircuitBreakerPersistenceClient) CreateDomain(
+	ctx context.Context,
+	request *CreateDomainRequest,
+) (*CreateDomainResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.CreateDomain(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *metadataCircuitBreakerPersistenceClient) GetDomain(
+	ctx context.Context,
+	request *GetDomainRequest,
+) (*GetDomainResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetDomain(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *metadataCircuitBreakerPersistenceClient) GetDomainConfigVersion(
+	ctx context.Context,
+	request *GetDomainConfigVersionRequest,
+) (*GetDomainConfigVersionResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetDomainConfigVersion(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *metadataCircuitBreakerPersistenceClient) UpdateDomain(
+	ctx context.Context,
+	request *UpdateDomainRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.UpdateDomain(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *metadataCircuitBreakerPersistenceClient) MarkDomainForDeletion(
+	ctx context.Context,
+	request *MarkDomainForDeletionRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.MarkDomainForDeletion(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *metadataCircuitBreakerPersistenceClient) DeleteDomain(
+	ctx context.Context,
+	request *DeleteDomainRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.DeleteDomain(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *metadataCircuitBreakerPersistenceClient) DeleteDomainByName(
+	ctx context.Context,
+	request *DeleteDomainByNameRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.DeleteDomainByName(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *metadataCircuitBreakerPersistenceClient) ListDomains(
+	ctx context.Context,
+	request *ListDomainsRequest,
+) (*ListDomainsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.ListDomains(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *metadataCircuitBreakerPersistenceClient) GetMetadata(
+	ctx context.Context,
+) (*GetMetadataResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetMetadata(ctx)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *metadataCircuitBreakerPersistenceClient) Close() {
+	p.persistence.Close()
+}
+
+// CloseWithContext closes the wrapped persistence whatever the state of the circuit breaker
+func (p *metadataCircuitBreakerPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) GetName() string {
+	return p.persistence.GetName()
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) RecordWorkflowExecutionStarted(
+	ctx context.Context,
+	request *RecordWorkflowExecutionStartedRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.RecordWorkflowExecutionStarted(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) RecordWorkflowExecutionClosed(
+	ctx context.Context,
+	request *RecordWorkflowExecutionClosedRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.RecordWorkflowExecutionClosed(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) UpsertWorkflowExecution(
+	ctx context.Context,
+	request *UpsertWorkflowExecutionRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.UpsertWorkflowExecution(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) ListOpenWorkflowExecutions(
+	ctx context.Context,
+	request *ListWorkflowExecutionsRequest,
+) (*ListWorkflowExecutionsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.ListOpenWorkflowExecutions(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) ListClosedWorkflowExecutions(
+	ctx context.Context,
+	request *ListWorkflowExecutionsRequest,
+) (*ListWorkflowExecutionsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.ListClosedWorkflowExecutions(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) ListOpenWorkflowExecutionsByType(
+	ctx context.Context,
+	request *ListWorkflowExecutionsByTypeRequest,
+) (*ListWorkflowExecutionsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.ListOpenWorkflowExecutionsByType(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) ListClosedWorkflowExecutionsByType(
+	ctx context.Context,
+	request *ListWorkflowExecutionsByTypeRequest,
+) (*ListWorkflowExecutionsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.ListClosedWorkflowExecutionsByType(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) ListOpenWorkflowExecutionsByWorkflowID(
+	ctx context.Context,
+	request *ListWorkflowExecutionsByWorkflowIDRequest,
+) (*ListWorkflowExecutionsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.ListOpenWorkflowExecutionsByWorkflowID(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) ListClosedWorkflowExecutionsByWorkflowID(
+	ctx context.Context,
+	request *ListWorkflowExecutionsByWorkflowIDRequest,
+) (*ListWorkflowExecutionsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.ListClosedWorkflowExecutionsByWorkflowID(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) ListClosedWorkflowExecutionsByStatus(
+	ctx context.Context,
+	request *ListClosedWorkflowExecutionsByStatusRequest,
+) (*ListWorkflowExecutionsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.ListClosedWorkflowExecutionsByStatus(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) GetClosedWorkflowExecution(
+	ctx context.Context,
+	request *GetClosedWorkflowExecutionRequest,
+) (*GetClosedWorkflowExecutionResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetClosedWorkflowExecution(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) DeleteWorkflowExecution(
+	ctx context.Context,
+	request *VisibilityDeleteWorkflowExecutionRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+	err := p.persistence.DeleteWorkflowExecution(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) ListWorkflowExecutions(
+	ctx context.Context,
+	request *ListWorkflowExecutionsByQueryRequest,
+) (*ListWorkflowExecutionsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+	response, err := p.persistence.ListWorkflowExecutions(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) ScanWorkflowExecutions(
+	ctx context.Context,
+	request *ListWorkflowExecutionsByQueryRequest,
+) (*ListWorkflowExecutionsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+	response, err := p.persistence.ScanWorkflowExecutions(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) CountWorkflowExecutions(
+	ctx context.Context,
+	request *CountWorkflowExecutionsRequest,
+) (*CountWorkflowExecutionsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+	response, err := p.persistence.CountWorkflowExecutions(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *visibilityCircuitBreakerPersistenceClient) Close() {
+	p.persistence.Close()
+}
+
+// CloseWithContext closes the wrapped persistence whatever the state of the circuit breaker
+func (p *visibilityCircuitBreakerPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
+func (p *historyCircuitBreakerPersistenceClient) GetName() string {
+	return p.persistence.GetName()
+}
+
+func (p *historyCircuitBreakerPersistenceClient) Close() {
+	p.persistence.Close()
+}
+
+// CloseWithContext closes the wrapped persistence whatever the state of the circuit breaker
+func (p *historyCircuitBreakerPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
+// AppendHistoryNodes add(or override) a node to a history branch
+func (p *historyCircuitBreakerPersistenceClient) AppendHistoryNodes(
+	ctx context.Context,
+	request *AppendHistoryNodesRequest,
+) (*AppendHistoryNodesResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+	response, err := p.persistence.AppendHistoryNodes(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+// ReadHistoryBranch returns history node data for a branch
+func (p *historyCircuitBreakerPersistenceClient) ReadHistoryBranch(
+	ctx context.Context,
+	request *ReadHistoryBranchRequest,
+) (*ReadHistoryBranchResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+	response, err := p.persistence.ReadHistoryBranch(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+// ReadHistoryBranchByBatch returns history node data for a branch
+func (p *historyCircuitBreakerPersistenceClient) ReadHistoryBranchByBatch(
+	ctx context.Context,
+	request *ReadHistoryBranchRequest,
+) (*ReadHistoryBranchByBatchResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+	response, err := p.persistence.ReadHistoryBranchByBatch(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+// ReadHistoryBranchByBatch returns history node data for a branch
+func (p *historyCircuitBreakerPersistenceClient) ReadRawHistoryBranch(
+	ctx context.Context,
+	request *ReadHistoryBranchRequest,
+) (*ReadRawHistoryBranchResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+	response, err := p.persistence.ReadRawHistoryBranch(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *historyCircuitBreakerPersistenceClient) ReadHistoryBranchWithBlobs(
+	ctx context.Context,
+	request *ReadHistoryBranchRequest,
+) (*ReadHistoryBranchWithBlobsResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.ReadHistoryBranchWithBlobs(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+// ReadHistoryNode returns the single batch of events starting at the node ID
+func (p *historyCircuitBreakerPersistenceClient) ReadHistoryNode(
+	ctx context.Context,
+	request *ReadHistoryNodeRequest,
+) (*ReadHistoryNodeResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+	response, err := p.persistence.ReadHistoryNode(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+// ForkHistoryBranch forks a new branch from a old branch
+func (p *historyCircuitBreakerPersistenceClient) ForkHistoryBranch(
+	ctx context.Context,
+	request *ForkHistoryBranchRequest,
+) (*ForkHistoryBranchResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+	response, err := p.persistence.ForkHistoryBranch(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+// DeleteHistoryBranch removes a branch
+func (p *historyCircuitBreakerPersistenceClient) DeleteHistoryBranch(
+	ctx context.Context,
+	request *DeleteHistoryBranchRequest,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+	err := p.persistence.DeleteHistoryBranch(ctx, request)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+// GetHistoryTree returns all branch information of a tree
+func (p *historyCircuitBreakerPersistenceClient) GetHistoryTree(
+	ctx context.Context,
+	request *GetHistoryTreeRequest,
+) (*GetHistoryTreeResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+	response, err := p.persistence.GetHistoryTree(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *historyCircuitBreakerPersistenceClient) GetAllHistoryTreeBranches(
+	ctx context.Context,
+	request *GetAllHistoryTreeBranchesRequest,
+) (*GetAllHistoryTreeBranchesResponse, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+	response, err := p.persistence.GetAllHistoryTreeBranches(ctx, request)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *queueCircuitBreakerPersistenceClient) EnqueueMessage(
+	ctx context.Context,
+	message []byte,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.EnqueueMessage(ctx, message)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *queueCircuitBreakerPersistenceClient) EnqueueMessageWithID(
+	ctx context.Context,
+	message []byte,
+	messageID int64,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.EnqueueMessageWithID(ctx, message, messageID)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *queueCircuitBreakerPersistenceClient) EnqueueMessages(
+	ctx context.Context,
+	messages [][]byte,
+) ([]int64, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.EnqueueMessages(ctx, messages)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *queueCircuitBreakerPersistenceClient) ReadMessages(
+	ctx context.Context,
+	lastMessageID int64,
+	maxCount int,
+) ([]*QueueMessage, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.ReadMessages(ctx, lastMessageID, maxCount)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *queueCircuitBreakerPersistenceClient) UpdateAckLevel(
+	ctx context.Context,
+	messageID int64,
+	clusterName string,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.UpdateAckLevel(ctx, messageID, clusterName)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *queueCircuitBreakerPersistenceClient) GetAckLevels(
+	ctx context.Context,
+) (map[string]int64, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetAckLevels(ctx)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *queueCircuitBreakerPersistenceClient) DeleteMessagesBefore(
+	ctx context.Context,
+	messageID int64,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.DeleteMessagesBefore(ctx, messageID)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *queueCircuitBreakerPersistenceClient) EnqueueMessageToDLQ(
+	ctx context.Context,
+	message []byte,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.EnqueueMessageToDLQ(ctx, message)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *queueCircuitBreakerPersistenceClient) ReadMessagesFromDLQ(
+	ctx context.Context,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*QueueMessage, []byte, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, nil, ErrPersistenceCircuitOpen
+	}
+
+	messages, nextPageToken, err := p.persistence.ReadMessagesFromDLQ(ctx, firstMessageID, lastMessageID, pageSize, pageToken)
+	p.circuitBreaker.Record(err)
+	return messages, nextPageToken, err
+}
+
+func (p *queueCircuitBreakerPersistenceClient) RangeDeleteMessagesFromDLQ(
+	ctx context.Context,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.RangeDeleteMessagesFromDLQ(ctx, firstMessageID, lastMessageID)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *queueCircuitBreakerPersistenceClient) UpdateDLQAckLevel(
+	ctx context.Context,
+	messageID int64,
+	clusterName string,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.UpdateDLQAckLevel(ctx, messageID, clusterName)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *queueCircuitBreakerPersistenceClient) GetDLQAckLevels(
+	ctx context.Context,
+) (map[string]int64, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return nil, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetDLQAckLevels(ctx)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *queueCircuitBreakerPersistenceClient) GetDLQSize(
+	ctx context.Context,
+) (int64, error) {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return 0, ErrPersistenceCircuitOpen
+	}
+
+	response, err := p.persistence.GetDLQSize(ctx)
+	p.circuitBreaker.Record(err)
+	return response, err
+}
+
+func (p *queueCircuitBreakerPersistenceClient) DeleteMessageFromDLQ(
+	ctx context.Context,
+	messageID int64,
+) error {
+	if ok := p.circuitBreaker.Allow(); !ok {
+		return ErrPersistenceCircuitOpen
+	}
+
+	err := p.persistence.DeleteMessageFromDLQ(ctx, messageID)
+	p.circuitBreaker.Record(err)
+	return err
+}
+
+func (p *queueCircuitBreakerPersistenceClient) Close() {
+	p.persistence.Close()
+}
+
+// CloseWithContext closes the wrapped persistence whatever the state of the circuit breaker
+func (p *queueCircuitBreakerPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
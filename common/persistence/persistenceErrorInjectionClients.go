@@ -55,24 +55,32 @@ type (
 	}
 
 	workflowExecutionErrorInjectionPersistenceClient struct {
+		executionManagerCompositeOperations
+
 		persistence ExecutionManager
 		errorRate   float64
 		logger      log.Logger
 	}
 
 	taskErrorInjectionPersistenceClient struct {
+		taskManagerCompositeOperations
+
 		persistence TaskManager
 		errorRate   float64
 		logger      log.Logger
 	}
 
 	historyErrorInjectionPersistenceClient struct {
+		historyManagerCompositeOperations
+
 		persistence HistoryManager
 		errorRate   float64
 		logger      log.Logger
 	}
 
 	metadataErrorInjectionPersistenceClient struct {
+		metadataManagerCompositeOperations
+
 		persistence MetadataManager
 		errorRate   float64
 		logger      log.Logger
@@ -118,11 +126,13 @@ func NewWorkflowExecutionPersistenceErrorInjectionClient(
 	errorRate float64,
 	logger log.Logger,
 ) ExecutionManager {
-	return &workflowExecutionErrorInjectionPersistenceClient{
+	client := &workflowExecutionErrorInjectionPersistenceClient{
 		persistence: persistence,
 		errorRate:   errorRate,
 		logger:      logger,
 	}
+	client.executionManagerCompositeOperations = executionManagerCompositeOperations{manager: client}
+	return client
 }
 
 // NewTaskPersistenceErrorInjectionClient creates an error injection client to manage tasks
@@ -131,11 +141,13 @@ func NewTaskPersistenceErrorInjectionClient(
 	errorRate float64,
 	logger log.Logger,
 ) TaskManager {
-	return &taskErrorInjectionPersistenceClient{
+	client := &taskErrorInjectionPersistenceClient{
 		persistence: persistence,
 		errorRate:   errorRate,
 		logger:      logger,
 	}
+	client.taskManagerCompositeOperations = taskManagerCompositeOperations{manager: client}
+	return client
 }
 
 // NewHistoryPersistenceErrorInjectionClient creates an error injection HistoryManager client to manage workflow execution history
@@ -144,11 +156,13 @@ func NewHistoryPersistenceErrorInjectionClient(
 	errorRate float64,
 	logger log.Logger,
 ) HistoryManager {
-	return &historyErrorInjectionPersistenceClient{
+	client := &historyErrorInjectionPersistenceClient{
 		persistence: persistence,
 		errorRate:   errorRate,
 		logger:      logger,
 	}
+	client.historyManagerCompositeOperations = historyManagerCompositeOperations{manager: client}
+	return client
 }
 
 // NewMetadataPersistenceErrorInjectionClient creates an error injection MetadataManager client to manage metadata
@@ -157,11 +171,13 @@ func NewMetadataPersistenceErrorInjectionClient(
 	errorRate float64,
 	logger log.Logger,
 ) MetadataManager {
-	return &metadataErrorInjectionPersistenceClient{
+	client := &metadataErrorInjectionPersistenceClient{
 		persistence: persistence,
 		errorRate:   errorRate,
 		logger:      logger,
 	}
+	client.metadataManagerCompositeOperations = metadataManagerCompositeOperations{manager: client}
+	return client
 }
 
 // NewVisibilityPersistenceErrorInjectionClient creates an error injection client to manage visibility
@@ -233,6 +249,56 @@ func (p *shardErrorInjectionPersistenceClient) GetShard(
 	return response, persistenceErr
 }
 
+func (p *shardErrorInjectionPersistenceClient) GetShards(
+	ctx context.Context,
+	request *GetShardsRequest,
+) (*GetShardsResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *GetShardsResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.GetShards(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationGetShards,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
+func (p *shardErrorInjectionPersistenceClient) ListShards(
+	ctx context.Context,
+	request *ListShardsRequest,
+) (*ListShardsResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *ListShardsResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.ListShards(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationListShards,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
 func (p *shardErrorInjectionPersistenceClient) UpdateShard(
 	ctx context.Context,
 	request *UpdateShardRequest,
@@ -256,6 +322,11 @@ func (p *shardErrorInjectionPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence, errors are never injected into closing
+func (p *shardErrorInjectionPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *workflowExecutionErrorInjectionPersistenceClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -314,6 +385,31 @@ func (p *workflowExecutionErrorInjectionPersistenceClient) GetWorkflowExecution(
 	return response, persistenceErr
 }
 
+func (p *workflowExecutionErrorInjectionPersistenceClient) GetWorkflowExecutionForUpdate(
+	ctx context.Context,
+	request *GetWorkflowExecutionForUpdateRequest,
+) (*GetWorkflowExecutionForUpdateResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *GetWorkflowExecutionForUpdateResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.GetWorkflowExecutionForUpdate(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationGetWorkflowExecutionForUpdate,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
 func (p *workflowExecutionErrorInjectionPersistenceClient) UpdateWorkflowExecution(
 	ctx context.Context,
 	request *UpdateWorkflowExecutionRequest,
@@ -339,6 +435,30 @@ func (p *workflowExecutionErrorInjectionPersistenceClient) UpdateWorkflowExecuti
 	return response, persistenceErr
 }
 
+func (p *workflowExecutionErrorInjectionPersistenceClient) FlushBufferedEvents(
+	ctx context.Context,
+	request *FlushBufferedEventsRequest,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.FlushBufferedEvents(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationFlushBufferedEvents,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
 func (p *workflowExecutionErrorInjectionPersistenceClient) ConflictResolveWorkflowExecution(
 	ctx context.Context,
 	request *ConflictResolveWorkflowExecutionRequest,
@@ -435,6 +555,31 @@ func (p *workflowExecutionErrorInjectionPersistenceClient) DeleteCurrentWorkflow
 	return persistenceErr
 }
 
+func (p *workflowExecutionErrorInjectionPersistenceClient) DeleteWorkflowExecutions(
+	ctx context.Context,
+	request *DeleteWorkflowExecutionsRequest,
+) (*DeleteWorkflowExecutionsResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *DeleteWorkflowExecutionsResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.DeleteWorkflowExecutions(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationDeleteWorkflowExecutions,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
 func (p *workflowExecutionErrorInjectionPersistenceClient) GetCurrentExecution(
 	ctx context.Context,
 	request *GetCurrentExecutionRequest,
@@ -460,6 +605,31 @@ func (p *workflowExecutionErrorInjectionPersistenceClient) GetCurrentExecution(
 	return response, persistenceErr
 }
 
+func (p *workflowExecutionErrorInjectionPersistenceClient) GetCurrentRunID(
+	ctx context.Context,
+	request *GetCurrentRunIDRequest,
+) (*GetCurrentRunIDResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *GetCurrentRunIDResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.GetCurrentRunID(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationGetCurrentRunID,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
 func (p *workflowExecutionErrorInjectionPersistenceClient) ListCurrentExecutions(
 	ctx context.Context,
 	request *ListCurrentExecutionsRequest,
@@ -510,6 +680,56 @@ func (p *workflowExecutionErrorInjectionPersistenceClient) IsWorkflowExecutionEx
 	return response, persistenceErr
 }
 
+func (p *workflowExecutionErrorInjectionPersistenceClient) WorkflowExists(
+	ctx context.Context,
+	request *WorkflowExistsRequest,
+) (*WorkflowExistsResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *WorkflowExistsResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.WorkflowExists(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationWorkflowExists,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
+func (p *workflowExecutionErrorInjectionPersistenceClient) CheckCurrentExecution(
+	ctx context.Context,
+	request *CheckCurrentExecutionRequest,
+) (*CheckCurrentExecutionResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *CheckCurrentExecutionResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.CheckCurrentExecution(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationCheckCurrentExecution,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
 func (p *workflowExecutionErrorInjectionPersistenceClient) ListConcreteExecutions(
 	ctx context.Context,
 	request *ListConcreteExecutionsRequest,
@@ -535,6 +755,31 @@ func (p *workflowExecutionErrorInjectionPersistenceClient) ListConcreteExecution
 	return response, persistenceErr
 }
 
+func (p *workflowExecutionErrorInjectionPersistenceClient) CountWorkflowExecutions(
+	ctx context.Context,
+	request *CountExecutionsRequest,
+) (*CountExecutionsResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *CountExecutionsResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.CountWorkflowExecutions(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationCountExecutions,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
 func (p *workflowExecutionErrorInjectionPersistenceClient) GetTransferTasks(
 	ctx context.Context,
 	request *GetTransferTasksRequest,
@@ -585,6 +830,31 @@ func (p *workflowExecutionErrorInjectionPersistenceClient) GetReplicationTasks(
 	return response, persistenceErr
 }
 
+func (p *workflowExecutionErrorInjectionPersistenceClient) GetFailoverMarkerTasks(
+	ctx context.Context,
+	request *GetFailoverMarkerTasksRequest,
+) (*GetFailoverMarkerTasksResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *GetFailoverMarkerTasksResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.GetFailoverMarkerTasks(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationGetFailoverMarkerTasks,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
 func (p *workflowExecutionErrorInjectionPersistenceClient) CompleteTransferTask(
 	ctx context.Context,
 	request *CompleteTransferTaskRequest,
@@ -633,6 +903,103 @@ func (p *workflowExecutionErrorInjectionPersistenceClient) RangeCompleteTransfer
 	return persistenceErr
 }
 
+func (p *workflowExecutionErrorInjectionPersistenceClient) CompleteTransferTasks(
+	ctx context.Context,
+	request *CompleteTransferTasksRequest,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.CompleteTransferTasks(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationCompleteTransferTasks,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
+func (p *workflowExecutionErrorInjectionPersistenceClient) GetCrossClusterTasks(
+	ctx context.Context,
+	request *GetCrossClusterTasksRequest,
+) (*GetCrossClusterTasksResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *GetCrossClusterTasksResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.GetCrossClusterTasks(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationGetCrossClusterTasks,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
+func (p *workflowExecutionErrorInjectionPersistenceClient) CompleteCrossClusterTask(
+	ctx context.Context,
+	request *CompleteCrossClusterTaskRequest,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.CompleteCrossClusterTask(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationCompleteCrossClusterTask,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
+func (p *workflowExecutionErrorInjectionPersistenceClient) RangeCompleteCrossClusterTask(
+	ctx context.Context,
+	request *RangeCompleteCrossClusterTaskRequest,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.RangeCompleteCrossClusterTask(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationRangeCompleteCrossClusterTask,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
 func (p *workflowExecutionErrorInjectionPersistenceClient) CompleteReplicationTask(
 	ctx context.Context,
 	request *CompleteReplicationTaskRequest,
@@ -705,6 +1072,30 @@ func (p *workflowExecutionErrorInjectionPersistenceClient) PutReplicationTaskToD
 	return persistenceErr
 }
 
+func (p *workflowExecutionErrorInjectionPersistenceClient) PutReplicationTasksToDLQ(
+	ctx context.Context,
+	request *PutReplicationTasksToDLQRequest,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.PutReplicationTasksToDLQ(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationPutReplicationTasksToDLQ,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
 func (p *workflowExecutionErrorInjectionPersistenceClient) GetReplicationTasksFromDLQ(
 	ctx context.Context,
 	request *GetReplicationTasksFromDLQRequest,
@@ -803,6 +1194,30 @@ func (p *workflowExecutionErrorInjectionPersistenceClient) RangeDeleteReplicatio
 	return persistenceErr
 }
 
+func (p *workflowExecutionErrorInjectionPersistenceClient) DeleteReplicationTasksFromDLQ(
+	ctx context.Context,
+	request *DeleteReplicationTasksFromDLQRequest,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.DeleteReplicationTasksFromDLQ(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationDeleteReplicationTasksFromDLQ,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
 func (p *workflowExecutionErrorInjectionPersistenceClient) CreateFailoverMarkerTasks(
 	ctx context.Context,
 	request *CreateFailoverMarkersRequest,
@@ -900,10 +1315,40 @@ func (p *workflowExecutionErrorInjectionPersistenceClient) RangeCompleteTimerTas
 	return persistenceErr
 }
 
+func (p *workflowExecutionErrorInjectionPersistenceClient) CompleteTimerTasksForDomain(
+	ctx context.Context,
+	request *CompleteTimerTasksForDomainRequest,
+) (int, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response int
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.CompleteTimerTasksForDomain(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationCompleteTimerTasksForDomain,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return 0, fakeErr
+	}
+	return response, persistenceErr
+}
+
 func (p *workflowExecutionErrorInjectionPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence, errors are never injected into closing
+func (p *workflowExecutionErrorInjectionPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *taskErrorInjectionPersistenceClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -1107,6 +1552,31 @@ func (p *taskErrorInjectionPersistenceClient) ListTaskList(
 	return response, persistenceErr
 }
 
+func (p *taskErrorInjectionPersistenceClient) ListTaskListByDomain(
+	ctx context.Context,
+	request *ListTaskListByDomainRequest,
+) (*ListTaskListResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *ListTaskListResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.ListTaskListByDomain(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationListTaskListByDomain,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
 func (p *taskErrorInjectionPersistenceClient) DeleteTaskList(
 	ctx context.Context,
 	request *DeleteTaskListRequest,
@@ -1135,6 +1605,11 @@ func (p *taskErrorInjectionPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence, errors are never injected into closing
+func (p *taskErrorInjectionPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *metadataErrorInjectionPersistenceClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -1189,6 +1664,31 @@ func (p *metadataErrorInjectionPersistenceClient) GetDomain(
 	return response, persistenceErr
 }
 
+func (p *metadataErrorInjectionPersistenceClient) GetDomainConfigVersion(
+	ctx context.Context,
+	request *GetDomainConfigVersionRequest,
+) (*GetDomainConfigVersionResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *GetDomainConfigVersionResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.GetDomainConfigVersion(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationGetDomainConfigVersion,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
 func (p *metadataErrorInjectionPersistenceClient) UpdateDomain(
 	ctx context.Context,
 	request *UpdateDomainRequest,
@@ -1213,6 +1713,30 @@ func (p *metadataErrorInjectionPersistenceClient) UpdateDomain(
 	return persistenceErr
 }
 
+func (p *metadataErrorInjectionPersistenceClient) MarkDomainForDeletion(
+	ctx context.Context,
+	request *MarkDomainForDeletionRequest,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.MarkDomainForDeletion(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationMarkDomainForDeletion,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
 func (p *metadataErrorInjectionPersistenceClient) DeleteDomain(
 	ctx context.Context,
 	request *DeleteDomainRequest,
@@ -1314,6 +1838,11 @@ func (p *metadataErrorInjectionPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence, errors are never injected into closing
+func (p *metadataErrorInjectionPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *visibilityErrorInjectionPersistenceClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -1693,6 +2222,11 @@ func (p *visibilityErrorInjectionPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence, errors are never injected into closing
+func (p *visibilityErrorInjectionPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *historyErrorInjectionPersistenceClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -1801,6 +2335,57 @@ func (p *historyErrorInjectionPersistenceClient) ReadRawHistoryBranch(
 	return response, persistenceErr
 }
 
+func (p *historyErrorInjectionPersistenceClient) ReadHistoryBranchWithBlobs(
+	ctx context.Context,
+	request *ReadHistoryBranchRequest,
+) (*ReadHistoryBranchWithBlobsResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *ReadHistoryBranchWithBlobsResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.ReadHistoryBranchWithBlobs(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationReadHistoryBranchWithBlobs,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
+// ReadHistoryNode returns the single batch of events starting at the node ID
+func (p *historyErrorInjectionPersistenceClient) ReadHistoryNode(
+	ctx context.Context,
+	request *ReadHistoryNodeRequest,
+) (*ReadHistoryNodeResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *ReadHistoryNodeResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.ReadHistoryNode(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationReadHistoryNode,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
 // ForkHistoryBranch forks a new branch from a old branch
 func (p *historyErrorInjectionPersistenceClient) ForkHistoryBranch(
 	ctx context.Context,
@@ -1907,6 +2492,11 @@ func (p *historyErrorInjectionPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence, errors are never injected into closing
+func (p *historyErrorInjectionPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *queueErrorInjectionPersistenceClient) EnqueueMessage(
 	ctx context.Context,
 	message []byte,
@@ -1931,6 +2521,56 @@ func (p *queueErrorInjectionPersistenceClient) EnqueueMessage(
 	return persistenceErr
 }
 
+func (p *queueErrorInjectionPersistenceClient) EnqueueMessageWithID(
+	ctx context.Context,
+	message []byte,
+	messageID int64,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.EnqueueMessageWithID(ctx, message, messageID)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationEnqueueMessageWithID,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
+func (p *queueErrorInjectionPersistenceClient) EnqueueMessages(
+	ctx context.Context,
+	messages [][]byte,
+) ([]int64, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response []int64
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.EnqueueMessages(ctx, messages)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationEnqueueMessages,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
 func (p *queueErrorInjectionPersistenceClient) ReadMessages(
 	ctx context.Context,
 	lastMessageID int64,
@@ -2209,6 +2849,11 @@ func (p *queueErrorInjectionPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence, errors are never injected into closing
+func (p *queueErrorInjectionPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func shouldForwardCallToPersistence(
 	err error,
 ) bool {
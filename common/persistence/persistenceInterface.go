@@ -19,6 +19,8 @@
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
 // THE SOFTWARE.
 
+//go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination persistenceInterface_mock.go -self_package github.com/uber/cadence/common/persistence -aux_files github.com/uber/cadence/common/persistence=dataInterfaces.go
+
 package persistence
 
 import (
@@ -46,6 +48,7 @@ type (
 		GetName() string
 		CreateShard(ctx context.Context, request *InternalCreateShardRequest) error
 		GetShard(ctx context.Context, request *InternalGetShardRequest) (*InternalGetShardResponse, error)
+		ListShards(ctx context.Context, request *ListShardsRequest) (*InternalListShardsResponse, error)
 		UpdateShard(ctx context.Context, request *InternalUpdateShardRequest) error
 	}
 
@@ -56,6 +59,9 @@ type (
 		LeaseTaskList(ctx context.Context, request *LeaseTaskListRequest) (*LeaseTaskListResponse, error)
 		UpdateTaskList(ctx context.Context, request *UpdateTaskListRequest) (*UpdateTaskListResponse, error)
 		ListTaskList(ctx context.Context, request *ListTaskListRequest) (*ListTaskListResponse, error)
+		// ListTaskListByDomain lists the task lists of a single domain, a page may contain
+		// fewer than PageSize items even when more pages follow
+		ListTaskListByDomain(ctx context.Context, request *ListTaskListByDomainRequest) (*ListTaskListResponse, error)
 		DeleteTaskList(ctx context.Context, request *DeleteTaskListRequest) error
 		CreateTasks(ctx context.Context, request *InternalCreateTasksRequest) (*CreateTasksResponse, error)
 		GetTasks(ctx context.Context, request *GetTasksRequest) (*InternalGetTasksResponse, error)
@@ -82,6 +88,7 @@ type (
 		GetName() string
 		CreateDomain(ctx context.Context, request *InternalCreateDomainRequest) (*CreateDomainResponse, error)
 		GetDomain(ctx context.Context, request *GetDomainRequest) (*InternalGetDomainResponse, error)
+		GetDomainConfigVersion(ctx context.Context, request *GetDomainConfigVersionRequest) (*GetDomainConfigVersionResponse, error)
 		UpdateDomain(ctx context.Context, request *InternalUpdateDomainRequest) error
 		DeleteDomain(ctx context.Context, request *DeleteDomainRequest) error
 		DeleteDomainByName(ctx context.Context, request *DeleteDomainByNameRequest) error
@@ -104,32 +111,43 @@ type (
 		DeleteWorkflowExecution(ctx context.Context, request *DeleteWorkflowExecutionRequest) error
 		DeleteCurrentWorkflowExecution(ctx context.Context, request *DeleteCurrentWorkflowExecutionRequest) error
 		GetCurrentExecution(ctx context.Context, request *GetCurrentExecutionRequest) (*GetCurrentExecutionResponse, error)
+		GetCurrentRunID(ctx context.Context, request *GetCurrentRunIDRequest) (*GetCurrentRunIDResponse, error)
 		IsWorkflowExecutionExists(ctx context.Context, request *IsWorkflowExecutionExistsRequest) (*IsWorkflowExecutionExistsResponse, error)
 
 		// Transfer task related methods
 		GetTransferTasks(ctx context.Context, request *GetTransferTasksRequest) (*GetTransferTasksResponse, error)
 		CompleteTransferTask(ctx context.Context, request *CompleteTransferTaskRequest) error
 		RangeCompleteTransferTask(ctx context.Context, request *RangeCompleteTransferTaskRequest) error
+		CompleteTransferTasks(ctx context.Context, request *CompleteTransferTasksRequest) error
+
+		// Cross-cluster task related methods
+		GetCrossClusterTasks(ctx context.Context, request *GetCrossClusterTasksRequest) (*GetCrossClusterTasksResponse, error)
+		CompleteCrossClusterTask(ctx context.Context, request *CompleteCrossClusterTaskRequest) error
+		RangeCompleteCrossClusterTask(ctx context.Context, request *RangeCompleteCrossClusterTaskRequest) error
 
 		// Replication task related methods
 		GetReplicationTasks(ctx context.Context, request *GetReplicationTasksRequest) (*InternalGetReplicationTasksResponse, error)
 		CompleteReplicationTask(ctx context.Context, request *CompleteReplicationTaskRequest) error
 		RangeCompleteReplicationTask(ctx context.Context, request *RangeCompleteReplicationTaskRequest) error
 		PutReplicationTaskToDLQ(ctx context.Context, request *InternalPutReplicationTaskToDLQRequest) error
+		PutReplicationTasksToDLQ(ctx context.Context, request *InternalPutReplicationTasksToDLQRequest) error
 		GetReplicationTasksFromDLQ(ctx context.Context, request *GetReplicationTasksFromDLQRequest) (*InternalGetReplicationTasksFromDLQResponse, error)
 		GetReplicationDLQSize(ctx context.Context, request *GetReplicationDLQSizeRequest) (*GetReplicationDLQSizeResponse, error)
 		DeleteReplicationTaskFromDLQ(ctx context.Context, request *DeleteReplicationTaskFromDLQRequest) error
 		RangeDeleteReplicationTaskFromDLQ(ctx context.Context, request *RangeDeleteReplicationTaskFromDLQRequest) error
+		DeleteReplicationTasksFromDLQ(ctx context.Context, request *DeleteReplicationTasksFromDLQRequest) error
 		CreateFailoverMarkerTasks(ctx context.Context, request *CreateFailoverMarkersRequest) error
 
 		// Timer related methods.
 		GetTimerIndexTasks(ctx context.Context, request *GetTimerIndexTasksRequest) (*GetTimerIndexTasksResponse, error)
 		CompleteTimerTask(ctx context.Context, request *CompleteTimerTaskRequest) error
 		RangeCompleteTimerTask(ctx context.Context, request *RangeCompleteTimerTaskRequest) error
+		CompleteTimerTasksForDomain(ctx context.Context, request *CompleteTimerTasksForDomainRequest) (int, error)
 
 		// Scan related methods
 		ListConcreteExecutions(ctx context.Context, request *ListConcreteExecutionsRequest) (*InternalListConcreteExecutionsResponse, error)
 		ListCurrentExecutions(ctx context.Context, request *ListCurrentExecutionsRequest) (*ListCurrentExecutionsResponse, error)
+		CountWorkflowExecutions(ctx context.Context, request *CountExecutionsRequest) (*CountExecutionsResponse, error)
 	}
 
 	// HistoryStore is to manager workflow history events
@@ -144,6 +162,8 @@ type (
 		AppendHistoryNodes(ctx context.Context, request *InternalAppendHistoryNodesRequest) error
 		// ReadHistoryBranch returns history node data for a branch
 		ReadHistoryBranch(ctx context.Context, request *InternalReadHistoryBranchRequest) (*InternalReadHistoryBranchResponse, error)
+		// ReadHistoryNode returns the latest transaction of a single history node
+		ReadHistoryNode(ctx context.Context, request *InternalReadHistoryNodeRequest) (*InternalReadHistoryNodeResponse, error)
 		// ForkHistoryBranch forks a new branch from a old branch
 		ForkHistoryBranch(ctx context.Context, request *InternalForkHistoryBranchRequest) (*InternalForkHistoryBranchResponse, error)
 		// DeleteHistoryBranch removes a branch
@@ -179,6 +199,8 @@ type (
 	Queue interface {
 		Closeable
 		EnqueueMessage(ctx context.Context, messagePayload []byte) error
+		EnqueueMessageWithID(ctx context.Context, messagePayload []byte, messageID int64) error
+		EnqueueMessages(ctx context.Context, messagePayloads [][]byte) ([]int64, error)
 		ReadMessages(ctx context.Context, lastMessageID int64, maxCount int) ([]*InternalQueueMessage, error)
 		DeleteMessagesBefore(ctx context.Context, messageID int64) error
 		UpdateAckLevel(ctx context.Context, messageID int64, clusterName string) error
@@ -204,7 +226,9 @@ type (
 	// Note that it should be only used for Persistence layer, below dataInterface and application(historyEngine/etc)
 	DataBlob struct {
 		Encoding common.EncodingType
-		Data     []byte
+		// Compression is the algorithm Data is compressed with, stores persist it with GetStoredEncoding
+		Compression CompressionType
+		Data        []byte
 	}
 
 	// InternalCreateWorkflowExecutionRequest is used to write a new workflow execution
@@ -231,6 +255,12 @@ type (
 		TaskInfo          *InternalReplicationTaskInfo
 	}
 
+	// InternalPutReplicationTasksToDLQRequest is used to put a batch of replication tasks to dlq
+	InternalPutReplicationTasksToDLQRequest struct {
+		SourceClusterName string
+		TaskInfos         []*InternalReplicationTaskInfo
+	}
+
 	// InternalGetReplicationTasksFromDLQResponse is the response for GetReplicationTasksFromDLQ
 	InternalGetReplicationTasksFromDLQResponse = InternalGetReplicationTasksResponse
 
@@ -410,6 +440,9 @@ type (
 
 		// current workflow
 		CurrentWorkflowMutation *InternalWorkflowMutation
+
+		// only run the precondition checks, nothing is written
+		DryRun bool
 	}
 
 	// InternalResetWorkflowExecutionRequest is used to reset workflow execution state for Persistence Interface
@@ -513,17 +546,25 @@ type (
 		TransactionID int64
 		// Used in sharded data stores to identify which shard to use
 		ShardID int
+		// TTL of the appended node, zero means no TTL
+		TTLSeconds int32
+		// Fail with HistoryNodeConflictError instead of overwriting if the node exists
+		FailIfExists bool
 	}
 
 	// InternalGetWorkflowExecutionRequest is used to retrieve the info of a workflow execution
 	InternalGetWorkflowExecutionRequest struct {
-		DomainID  string
-		Execution types.WorkflowExecution
+		DomainID              string
+		Execution             types.WorkflowExecution
+		ExcludeBufferedEvents bool
+		ExcludeActivityInfos  bool
+		Fields                []MutableStateField
 	}
 
 	// InternalGetWorkflowExecutionResponse is the response to GetWorkflowExecution for Persistence Interface
 	InternalGetWorkflowExecutionResponse struct {
-		State *InternalWorkflowMutableState
+		State           *InternalWorkflowMutableState
+		DBRecordVersion int64
 	}
 
 	// InternalListConcreteExecutionsResponse is the response to ListConcreteExecutions for Persistence Interface
@@ -564,6 +605,8 @@ type (
 		BranchInfo types.HistoryBranch
 		// Used in sharded data stores to identify which shard to use
 		ShardID int
+		// only delete the nodes exclusively owned by this branch
+		PreserveAncestors bool
 	}
 
 	// InternalReadHistoryBranchRequest is used to read a history branch
@@ -610,6 +653,25 @@ type (
 		LastTransactionID int64
 	}
 
+	// InternalReadHistoryNodeRequest is used to read a single history node
+	InternalReadHistoryNodeRequest struct {
+		// The tree and branch the node belongs to
+		TreeID   string
+		BranchID string
+		// The ID of the node
+		NodeID int64
+		// Used in sharded data stores to identify which shard to use
+		ShardID int
+	}
+
+	// InternalReadHistoryNodeResponse is the response to InternalReadHistoryNodeRequest
+	InternalReadHistoryNodeResponse struct {
+		// History events of the node
+		History *DataBlob
+		// TransactionID of the latest write of the node
+		TransactionID int64
+	}
+
 	// InternalGetHistoryTreeRequest is used to get history tree
 	InternalGetHistoryTreeRequest struct {
 		// A UUID of a tree
@@ -618,12 +680,18 @@ type (
 		ShardID *int
 		// optional: can provide treeID via branchToken if treeID is empty
 		BranchToken []byte
+		// optional: maximum number of branches returned per page, all branches are returned when zero
+		PageSize int
+		// pagination token
+		NextPageToken []byte
 	}
 
 	// InternalGetHistoryTreeResponse is the response to GetHistoryTree
 	InternalGetHistoryTreeResponse struct {
-		// all branches of a tree
+		// all branches of a tree, or a single page of them when PageSize is set
 		Branches []*types.HistoryBranch
+		// pagination token
+		NextPageToken []byte
 	}
 
 	// InternalVisibilityWorkflowExecutionInfo is visibility info for internal response
@@ -779,6 +847,7 @@ type (
 		FailoverEndTime             *time.Time
 		LastUpdatedTime             time.Time
 		NotificationVersion         int64
+		DeletionTime                *time.Time
 	}
 
 	// InternalUpdateDomainRequest is used to update domain
@@ -793,6 +862,7 @@ type (
 		FailoverEndTime             *time.Time
 		LastUpdatedTime             time.Time
 		NotificationVersion         int64
+		DeletionTime                *time.Time
 	}
 
 	// InternalListDomainsResponse is the response for GetDomain
@@ -837,6 +907,7 @@ type (
 	InternalUpdateShardRequest struct {
 		ShardInfo       *InternalShardInfo
 		PreviousRangeID int64
+		PreviousOwner   string
 	}
 
 	// InternalGetShardResponse is the response to GetShard
@@ -844,6 +915,12 @@ type (
 		ShardInfo *InternalShardInfo
 	}
 
+	// InternalListShardsResponse is the response to ListShards
+	InternalListShardsResponse struct {
+		ShardInfos    []*InternalShardInfo
+		NextPageToken []byte
+	}
+
 	// InternalTaskInfo describes a Task
 	InternalTaskInfo struct {
 		DomainID               string
@@ -865,8 +942,9 @@ type (
 
 	// InternalCreateTasksRequest is request to CreateTasks
 	InternalCreateTasksRequest struct {
-		TaskListInfo *TaskListInfo
-		Tasks        []*InternalCreateTasksInfo
+		TaskListInfo   *TaskListInfo
+		Tasks          []*InternalCreateTasksInfo
+		IdempotencyKey string
 	}
 
 	// InternalGetTasksResponse is response from GetTasks
@@ -875,6 +953,34 @@ type (
 	}
 )
 
+// LoadsField returns whether the given section of the mutable state is loaded by the request
+func (r *InternalGetWorkflowExecutionRequest) LoadsField(field MutableStateField) bool {
+	switch {
+	case field == MutableStateFieldActivityInfos && r.ExcludeActivityInfos:
+		return false
+	case field == MutableStateFieldBufferedEvents && r.ExcludeBufferedEvents:
+		return false
+	case len(r.Fields) == 0:
+		return true
+	}
+	for _, f := range r.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadsAllFields returns whether the request loads the whole mutable state
+func (r *InternalGetWorkflowExecutionRequest) LoadsAllFields() bool {
+	for field := MutableStateFieldActivityInfos; field <= MutableStateFieldBufferedEvents; field++ {
+		if !r.LoadsField(field) {
+			return false
+		}
+	}
+	return true
+}
+
 // NewDataBlob returns a new DataBlob
 func NewDataBlob(data []byte, encodingType common.EncodingType) *DataBlob {
 	if data == nil || len(data) == 0 {
@@ -915,6 +1021,14 @@ func (d *DataBlob) GetEncoding() common.EncodingType {
 	}
 }
 
+// GetEncodingType returns the encoding type of the blob, a nil blob has EncodingTypeUnknown
+func (d *DataBlob) GetEncodingType() common.EncodingType {
+	if d == nil {
+		return common.EncodingTypeUnknown
+	}
+	return d.GetEncoding()
+}
+
 // ToInternal convert data blob to internal representation
 func (d *DataBlob) ToInternal() *types.DataBlob {
 	switch d.Encoding {
@@ -950,3 +1064,21 @@ func NewDataBlobFromInternal(blob *types.DataBlob) *DataBlob {
 		panic(fmt.Sprintf("NewDataBlobFromInternal seeing unsupported enconding type: %v", blob.GetEncodingType()))
 	}
 }
+
+// ValidateEncodings returns an InvalidPersistenceRequestError if any of the history event blobs is missing
+// or is not encoded with the expected encoding type
+func (r *ReadRawHistoryBranchResponse) ValidateEncodings(expected common.EncodingType) error {
+	for i, blob := range r.HistoryEventBlobs {
+		if blob == nil {
+			return &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("history event blob at index %v is nil", i),
+			}
+		}
+		if encoding := blob.GetEncodingType(); encoding != expected {
+			return &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("history event blob at index %v has encoding %q, expected %q", i, encoding, expected),
+			}
+		}
+	}
+	return nil
+}
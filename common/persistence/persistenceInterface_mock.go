@@ -0,0 +1,1732 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: persistenceInterface.go
+
+// Package persistence is a generated GoMock package.
+package persistence
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockShardStore is a mock of ShardStore interface
+type MockShardStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockShardStoreMockRecorder
+}
+
+// MockShardStoreMockRecorder is the mock recorder for MockShardStore
+type MockShardStoreMockRecorder struct {
+	mock *MockShardStore
+}
+
+// NewMockShardStore creates a new mock instance
+func NewMockShardStore(ctrl *gomock.Controller) *MockShardStore {
+	mock := &MockShardStore{ctrl: ctrl}
+	mock.recorder = &MockShardStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockShardStore) EXPECT() *MockShardStoreMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockShardStore) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close
+func (mr *MockShardStoreMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockShardStore)(nil).Close))
+}
+
+// GetName mocks base method
+func (m *MockShardStore) GetName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetName indicates an expected call of GetName
+func (mr *MockShardStoreMockRecorder) GetName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetName", reflect.TypeOf((*MockShardStore)(nil).GetName))
+}
+
+// CreateShard mocks base method
+func (m *MockShardStore) CreateShard(ctx context.Context, request *InternalCreateShardRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateShard", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateShard indicates an expected call of CreateShard
+func (mr *MockShardStoreMockRecorder) CreateShard(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShard", reflect.TypeOf((*MockShardStore)(nil).CreateShard), ctx, request)
+}
+
+// GetShard mocks base method
+func (m *MockShardStore) GetShard(ctx context.Context, request *InternalGetShardRequest) (*InternalGetShardResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShard", ctx, request)
+	ret0, _ := ret[0].(*InternalGetShardResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShard indicates an expected call of GetShard
+func (mr *MockShardStoreMockRecorder) GetShard(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShard", reflect.TypeOf((*MockShardStore)(nil).GetShard), ctx, request)
+}
+
+// ListShards mocks base method
+func (m *MockShardStore) ListShards(ctx context.Context, request *ListShardsRequest) (*InternalListShardsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShards", ctx, request)
+	ret0, _ := ret[0].(*InternalListShardsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShards indicates an expected call of ListShards
+func (mr *MockShardStoreMockRecorder) ListShards(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShards", reflect.TypeOf((*MockShardStore)(nil).ListShards), ctx, request)
+}
+
+// UpdateShard mocks base method
+func (m *MockShardStore) UpdateShard(ctx context.Context, request *InternalUpdateShardRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateShard", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateShard indicates an expected call of UpdateShard
+func (mr *MockShardStoreMockRecorder) UpdateShard(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateShard", reflect.TypeOf((*MockShardStore)(nil).UpdateShard), ctx, request)
+}
+
+// MockTaskStore is a mock of TaskStore interface
+type MockTaskStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskStoreMockRecorder
+}
+
+// MockTaskStoreMockRecorder is the mock recorder for MockTaskStore
+type MockTaskStoreMockRecorder struct {
+	mock *MockTaskStore
+}
+
+// NewMockTaskStore creates a new mock instance
+func NewMockTaskStore(ctrl *gomock.Controller) *MockTaskStore {
+	mock := &MockTaskStore{ctrl: ctrl}
+	mock.recorder = &MockTaskStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTaskStore) EXPECT() *MockTaskStoreMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockTaskStore) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close
+func (mr *MockTaskStoreMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockTaskStore)(nil).Close))
+}
+
+// GetName mocks base method
+func (m *MockTaskStore) GetName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetName indicates an expected call of GetName
+func (mr *MockTaskStoreMockRecorder) GetName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetName", reflect.TypeOf((*MockTaskStore)(nil).GetName))
+}
+
+// LeaseTaskList mocks base method
+func (m *MockTaskStore) LeaseTaskList(ctx context.Context, request *LeaseTaskListRequest) (*LeaseTaskListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaseTaskList", ctx, request)
+	ret0, _ := ret[0].(*LeaseTaskListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LeaseTaskList indicates an expected call of LeaseTaskList
+func (mr *MockTaskStoreMockRecorder) LeaseTaskList(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaseTaskList", reflect.TypeOf((*MockTaskStore)(nil).LeaseTaskList), ctx, request)
+}
+
+// UpdateTaskList mocks base method
+func (m *MockTaskStore) UpdateTaskList(ctx context.Context, request *UpdateTaskListRequest) (*UpdateTaskListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTaskList", ctx, request)
+	ret0, _ := ret[0].(*UpdateTaskListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTaskList indicates an expected call of UpdateTaskList
+func (mr *MockTaskStoreMockRecorder) UpdateTaskList(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaskList", reflect.TypeOf((*MockTaskStore)(nil).UpdateTaskList), ctx, request)
+}
+
+// ListTaskList mocks base method
+func (m *MockTaskStore) ListTaskList(ctx context.Context, request *ListTaskListRequest) (*ListTaskListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskList", ctx, request)
+	ret0, _ := ret[0].(*ListTaskListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskList indicates an expected call of ListTaskList
+func (mr *MockTaskStoreMockRecorder) ListTaskList(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskList", reflect.TypeOf((*MockTaskStore)(nil).ListTaskList), ctx, request)
+}
+
+// ListTaskListByDomain mocks base method
+func (m *MockTaskStore) ListTaskListByDomain(ctx context.Context, request *ListTaskListByDomainRequest) (*ListTaskListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskListByDomain", ctx, request)
+	ret0, _ := ret[0].(*ListTaskListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskListByDomain indicates an expected call of ListTaskListByDomain
+func (mr *MockTaskStoreMockRecorder) ListTaskListByDomain(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskListByDomain", reflect.TypeOf((*MockTaskStore)(nil).ListTaskListByDomain), ctx, request)
+}
+
+// DeleteTaskList mocks base method
+func (m *MockTaskStore) DeleteTaskList(ctx context.Context, request *DeleteTaskListRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaskList", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTaskList indicates an expected call of DeleteTaskList
+func (mr *MockTaskStoreMockRecorder) DeleteTaskList(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaskList", reflect.TypeOf((*MockTaskStore)(nil).DeleteTaskList), ctx, request)
+}
+
+// CreateTasks mocks base method
+func (m *MockTaskStore) CreateTasks(ctx context.Context, request *InternalCreateTasksRequest) (*CreateTasksResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTasks", ctx, request)
+	ret0, _ := ret[0].(*CreateTasksResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTasks indicates an expected call of CreateTasks
+func (mr *MockTaskStoreMockRecorder) CreateTasks(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTasks", reflect.TypeOf((*MockTaskStore)(nil).CreateTasks), ctx, request)
+}
+
+// GetTasks mocks base method
+func (m *MockTaskStore) GetTasks(ctx context.Context, request *GetTasksRequest) (*InternalGetTasksResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTasks", ctx, request)
+	ret0, _ := ret[0].(*InternalGetTasksResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTasks indicates an expected call of GetTasks
+func (mr *MockTaskStoreMockRecorder) GetTasks(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasks", reflect.TypeOf((*MockTaskStore)(nil).GetTasks), ctx, request)
+}
+
+// CompleteTask mocks base method
+func (m *MockTaskStore) CompleteTask(ctx context.Context, request *CompleteTaskRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteTask", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteTask indicates an expected call of CompleteTask
+func (mr *MockTaskStoreMockRecorder) CompleteTask(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTask", reflect.TypeOf((*MockTaskStore)(nil).CompleteTask), ctx, request)
+}
+
+// CompleteTasksLessThan mocks base method
+func (m *MockTaskStore) CompleteTasksLessThan(ctx context.Context, request *CompleteTasksLessThanRequest) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteTasksLessThan", ctx, request)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteTasksLessThan indicates an expected call of CompleteTasksLessThan
+func (mr *MockTaskStoreMockRecorder) CompleteTasksLessThan(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTasksLessThan", reflect.TypeOf((*MockTaskStore)(nil).CompleteTasksLessThan), ctx, request)
+}
+
+// GetOrphanTasks mocks base method
+func (m *MockTaskStore) GetOrphanTasks(ctx context.Context, request *GetOrphanTasksRequest) (*GetOrphanTasksResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrphanTasks", ctx, request)
+	ret0, _ := ret[0].(*GetOrphanTasksResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrphanTasks indicates an expected call of GetOrphanTasks
+func (mr *MockTaskStoreMockRecorder) GetOrphanTasks(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrphanTasks", reflect.TypeOf((*MockTaskStore)(nil).GetOrphanTasks), ctx, request)
+}
+
+// MockMetadataStore is a mock of MetadataStore interface
+type MockMetadataStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetadataStoreMockRecorder
+}
+
+// MockMetadataStoreMockRecorder is the mock recorder for MockMetadataStore
+type MockMetadataStoreMockRecorder struct {
+	mock *MockMetadataStore
+}
+
+// NewMockMetadataStore creates a new mock instance
+func NewMockMetadataStore(ctrl *gomock.Controller) *MockMetadataStore {
+	mock := &MockMetadataStore{ctrl: ctrl}
+	mock.recorder = &MockMetadataStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMetadataStore) EXPECT() *MockMetadataStoreMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockMetadataStore) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close
+func (mr *MockMetadataStoreMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockMetadataStore)(nil).Close))
+}
+
+// GetName mocks base method
+func (m *MockMetadataStore) GetName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetName indicates an expected call of GetName
+func (mr *MockMetadataStoreMockRecorder) GetName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetName", reflect.TypeOf((*MockMetadataStore)(nil).GetName))
+}
+
+// CreateDomain mocks base method
+func (m *MockMetadataStore) CreateDomain(ctx context.Context, request *InternalCreateDomainRequest) (*CreateDomainResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDomain", ctx, request)
+	ret0, _ := ret[0].(*CreateDomainResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDomain indicates an expected call of CreateDomain
+func (mr *MockMetadataStoreMockRecorder) CreateDomain(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDomain", reflect.TypeOf((*MockMetadataStore)(nil).CreateDomain), ctx, request)
+}
+
+// GetDomain mocks base method
+func (m *MockMetadataStore) GetDomain(ctx context.Context, request *GetDomainRequest) (*InternalGetDomainResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDomain", ctx, request)
+	ret0, _ := ret[0].(*InternalGetDomainResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDomain indicates an expected call of GetDomain
+func (mr *MockMetadataStoreMockRecorder) GetDomain(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDomain", reflect.TypeOf((*MockMetadataStore)(nil).GetDomain), ctx, request)
+}
+
+// GetDomainConfigVersion mocks base method
+func (m *MockMetadataStore) GetDomainConfigVersion(ctx context.Context, request *GetDomainConfigVersionRequest) (*GetDomainConfigVersionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDomainConfigVersion", ctx, request)
+	ret0, _ := ret[0].(*GetDomainConfigVersionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDomainConfigVersion indicates an expected call of GetDomainConfigVersion
+func (mr *MockMetadataStoreMockRecorder) GetDomainConfigVersion(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDomainConfigVersion", reflect.TypeOf((*MockMetadataStore)(nil).GetDomainConfigVersion), ctx, request)
+}
+
+// UpdateDomain mocks base method
+func (m *MockMetadataStore) UpdateDomain(ctx context.Context, request *InternalUpdateDomainRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDomain", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDomain indicates an expected call of UpdateDomain
+func (mr *MockMetadataStoreMockRecorder) UpdateDomain(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDomain", reflect.TypeOf((*MockMetadataStore)(nil).UpdateDomain), ctx, request)
+}
+
+// DeleteDomain mocks base method
+func (m *MockMetadataStore) DeleteDomain(ctx context.Context, request *DeleteDomainRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDomain", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDomain indicates an expected call of DeleteDomain
+func (mr *MockMetadataStoreMockRecorder) DeleteDomain(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDomain", reflect.TypeOf((*MockMetadataStore)(nil).DeleteDomain), ctx, request)
+}
+
+// DeleteDomainByName mocks base method
+func (m *MockMetadataStore) DeleteDomainByName(ctx context.Context, request *DeleteDomainByNameRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDomainByName", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDomainByName indicates an expected call of DeleteDomainByName
+func (mr *MockMetadataStoreMockRecorder) DeleteDomainByName(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDomainByName", reflect.TypeOf((*MockMetadataStore)(nil).DeleteDomainByName), ctx, request)
+}
+
+// ListDomains mocks base method
+func (m *MockMetadataStore) ListDomains(ctx context.Context, request *ListDomainsRequest) (*InternalListDomainsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDomains", ctx, request)
+	ret0, _ := ret[0].(*InternalListDomainsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDomains indicates an expected call of ListDomains
+func (mr *MockMetadataStoreMockRecorder) ListDomains(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDomains", reflect.TypeOf((*MockMetadataStore)(nil).ListDomains), ctx, request)
+}
+
+// GetMetadata mocks base method
+func (m *MockMetadataStore) GetMetadata(ctx context.Context) (*GetMetadataResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetadata", ctx)
+	ret0, _ := ret[0].(*GetMetadataResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetadata indicates an expected call of GetMetadata
+func (mr *MockMetadataStoreMockRecorder) GetMetadata(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetadata", reflect.TypeOf((*MockMetadataStore)(nil).GetMetadata), ctx)
+}
+
+// MockExecutionStore is a mock of ExecutionStore interface
+type MockExecutionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockExecutionStoreMockRecorder
+}
+
+// MockExecutionStoreMockRecorder is the mock recorder for MockExecutionStore
+type MockExecutionStoreMockRecorder struct {
+	mock *MockExecutionStore
+}
+
+// NewMockExecutionStore creates a new mock instance
+func NewMockExecutionStore(ctrl *gomock.Controller) *MockExecutionStore {
+	mock := &MockExecutionStore{ctrl: ctrl}
+	mock.recorder = &MockExecutionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockExecutionStore) EXPECT() *MockExecutionStoreMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockExecutionStore) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close
+func (mr *MockExecutionStoreMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockExecutionStore)(nil).Close))
+}
+
+// GetName mocks base method
+func (m *MockExecutionStore) GetName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetName indicates an expected call of GetName
+func (mr *MockExecutionStoreMockRecorder) GetName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetName", reflect.TypeOf((*MockExecutionStore)(nil).GetName))
+}
+
+// GetShardID mocks base method
+func (m *MockExecutionStore) GetShardID() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShardID")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetShardID indicates an expected call of GetShardID
+func (mr *MockExecutionStoreMockRecorder) GetShardID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShardID", reflect.TypeOf((*MockExecutionStore)(nil).GetShardID))
+}
+
+// GetWorkflowExecution mocks base method
+func (m *MockExecutionStore) GetWorkflowExecution(ctx context.Context, request *InternalGetWorkflowExecutionRequest) (*InternalGetWorkflowExecutionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkflowExecution", ctx, request)
+	ret0, _ := ret[0].(*InternalGetWorkflowExecutionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkflowExecution indicates an expected call of GetWorkflowExecution
+func (mr *MockExecutionStoreMockRecorder) GetWorkflowExecution(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflowExecution", reflect.TypeOf((*MockExecutionStore)(nil).GetWorkflowExecution), ctx, request)
+}
+
+// UpdateWorkflowExecution mocks base method
+func (m *MockExecutionStore) UpdateWorkflowExecution(ctx context.Context, request *InternalUpdateWorkflowExecutionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkflowExecution", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkflowExecution indicates an expected call of UpdateWorkflowExecution
+func (mr *MockExecutionStoreMockRecorder) UpdateWorkflowExecution(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkflowExecution", reflect.TypeOf((*MockExecutionStore)(nil).UpdateWorkflowExecution), ctx, request)
+}
+
+// ConflictResolveWorkflowExecution mocks base method
+func (m *MockExecutionStore) ConflictResolveWorkflowExecution(ctx context.Context, request *InternalConflictResolveWorkflowExecutionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConflictResolveWorkflowExecution", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConflictResolveWorkflowExecution indicates an expected call of ConflictResolveWorkflowExecution
+func (mr *MockExecutionStoreMockRecorder) ConflictResolveWorkflowExecution(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConflictResolveWorkflowExecution", reflect.TypeOf((*MockExecutionStore)(nil).ConflictResolveWorkflowExecution), ctx, request)
+}
+
+// ResetWorkflowExecution mocks base method
+func (m *MockExecutionStore) ResetWorkflowExecution(ctx context.Context, request *InternalResetWorkflowExecutionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetWorkflowExecution", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetWorkflowExecution indicates an expected call of ResetWorkflowExecution
+func (mr *MockExecutionStoreMockRecorder) ResetWorkflowExecution(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetWorkflowExecution", reflect.TypeOf((*MockExecutionStore)(nil).ResetWorkflowExecution), ctx, request)
+}
+
+// CreateWorkflowExecution mocks base method
+func (m *MockExecutionStore) CreateWorkflowExecution(ctx context.Context, request *InternalCreateWorkflowExecutionRequest) (*CreateWorkflowExecutionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkflowExecution", ctx, request)
+	ret0, _ := ret[0].(*CreateWorkflowExecutionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkflowExecution indicates an expected call of CreateWorkflowExecution
+func (mr *MockExecutionStoreMockRecorder) CreateWorkflowExecution(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkflowExecution", reflect.TypeOf((*MockExecutionStore)(nil).CreateWorkflowExecution), ctx, request)
+}
+
+// DeleteWorkflowExecution mocks base method
+func (m *MockExecutionStore) DeleteWorkflowExecution(ctx context.Context, request *DeleteWorkflowExecutionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkflowExecution", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkflowExecution indicates an expected call of DeleteWorkflowExecution
+func (mr *MockExecutionStoreMockRecorder) DeleteWorkflowExecution(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkflowExecution", reflect.TypeOf((*MockExecutionStore)(nil).DeleteWorkflowExecution), ctx, request)
+}
+
+// DeleteCurrentWorkflowExecution mocks base method
+func (m *MockExecutionStore) DeleteCurrentWorkflowExecution(ctx context.Context, request *DeleteCurrentWorkflowExecutionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCurrentWorkflowExecution", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCurrentWorkflowExecution indicates an expected call of DeleteCurrentWorkflowExecution
+func (mr *MockExecutionStoreMockRecorder) DeleteCurrentWorkflowExecution(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCurrentWorkflowExecution", reflect.TypeOf((*MockExecutionStore)(nil).DeleteCurrentWorkflowExecution), ctx, request)
+}
+
+// GetCurrentExecution mocks base method
+func (m *MockExecutionStore) GetCurrentExecution(ctx context.Context, request *GetCurrentExecutionRequest) (*GetCurrentExecutionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentExecution", ctx, request)
+	ret0, _ := ret[0].(*GetCurrentExecutionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentExecution indicates an expected call of GetCurrentExecution
+func (mr *MockExecutionStoreMockRecorder) GetCurrentExecution(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentExecution", reflect.TypeOf((*MockExecutionStore)(nil).GetCurrentExecution), ctx, request)
+}
+
+// GetCurrentRunID mocks base method
+func (m *MockExecutionStore) GetCurrentRunID(ctx context.Context, request *GetCurrentRunIDRequest) (*GetCurrentRunIDResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentRunID", ctx, request)
+	ret0, _ := ret[0].(*GetCurrentRunIDResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentRunID indicates an expected call of GetCurrentRunID
+func (mr *MockExecutionStoreMockRecorder) GetCurrentRunID(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentRunID", reflect.TypeOf((*MockExecutionStore)(nil).GetCurrentRunID), ctx, request)
+}
+
+// IsWorkflowExecutionExists mocks base method
+func (m *MockExecutionStore) IsWorkflowExecutionExists(ctx context.Context, request *IsWorkflowExecutionExistsRequest) (*IsWorkflowExecutionExistsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsWorkflowExecutionExists", ctx, request)
+	ret0, _ := ret[0].(*IsWorkflowExecutionExistsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsWorkflowExecutionExists indicates an expected call of IsWorkflowExecutionExists
+func (mr *MockExecutionStoreMockRecorder) IsWorkflowExecutionExists(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsWorkflowExecutionExists", reflect.TypeOf((*MockExecutionStore)(nil).IsWorkflowExecutionExists), ctx, request)
+}
+
+// GetTransferTasks mocks base method
+func (m *MockExecutionStore) GetTransferTasks(ctx context.Context, request *GetTransferTasksRequest) (*GetTransferTasksResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferTasks", ctx, request)
+	ret0, _ := ret[0].(*GetTransferTasksResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferTasks indicates an expected call of GetTransferTasks
+func (mr *MockExecutionStoreMockRecorder) GetTransferTasks(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferTasks", reflect.TypeOf((*MockExecutionStore)(nil).GetTransferTasks), ctx, request)
+}
+
+// CompleteTransferTask mocks base method
+func (m *MockExecutionStore) CompleteTransferTask(ctx context.Context, request *CompleteTransferTaskRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteTransferTask", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteTransferTask indicates an expected call of CompleteTransferTask
+func (mr *MockExecutionStoreMockRecorder) CompleteTransferTask(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTransferTask", reflect.TypeOf((*MockExecutionStore)(nil).CompleteTransferTask), ctx, request)
+}
+
+// RangeCompleteTransferTask mocks base method
+func (m *MockExecutionStore) RangeCompleteTransferTask(ctx context.Context, request *RangeCompleteTransferTaskRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RangeCompleteTransferTask", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RangeCompleteTransferTask indicates an expected call of RangeCompleteTransferTask
+func (mr *MockExecutionStoreMockRecorder) RangeCompleteTransferTask(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RangeCompleteTransferTask", reflect.TypeOf((*MockExecutionStore)(nil).RangeCompleteTransferTask), ctx, request)
+}
+
+// CompleteTransferTasks mocks base method
+func (m *MockExecutionStore) CompleteTransferTasks(ctx context.Context, request *CompleteTransferTasksRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteTransferTasks", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteTransferTasks indicates an expected call of CompleteTransferTasks
+func (mr *MockExecutionStoreMockRecorder) CompleteTransferTasks(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTransferTasks", reflect.TypeOf((*MockExecutionStore)(nil).CompleteTransferTasks), ctx, request)
+}
+
+// GetCrossClusterTasks mocks base method
+func (m *MockExecutionStore) GetCrossClusterTasks(ctx context.Context, request *GetCrossClusterTasksRequest) (*GetCrossClusterTasksResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCrossClusterTasks", ctx, request)
+	ret0, _ := ret[0].(*GetCrossClusterTasksResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCrossClusterTasks indicates an expected call of GetCrossClusterTasks
+func (mr *MockExecutionStoreMockRecorder) GetCrossClusterTasks(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCrossClusterTasks", reflect.TypeOf((*MockExecutionStore)(nil).GetCrossClusterTasks), ctx, request)
+}
+
+// CompleteCrossClusterTask mocks base method
+func (m *MockExecutionStore) CompleteCrossClusterTask(ctx context.Context, request *CompleteCrossClusterTaskRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteCrossClusterTask", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteCrossClusterTask indicates an expected call of CompleteCrossClusterTask
+func (mr *MockExecutionStoreMockRecorder) CompleteCrossClusterTask(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteCrossClusterTask", reflect.TypeOf((*MockExecutionStore)(nil).CompleteCrossClusterTask), ctx, request)
+}
+
+// RangeCompleteCrossClusterTask mocks base method
+func (m *MockExecutionStore) RangeCompleteCrossClusterTask(ctx context.Context, request *RangeCompleteCrossClusterTaskRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RangeCompleteCrossClusterTask", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RangeCompleteCrossClusterTask indicates an expected call of RangeCompleteCrossClusterTask
+func (mr *MockExecutionStoreMockRecorder) RangeCompleteCrossClusterTask(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RangeCompleteCrossClusterTask", reflect.TypeOf((*MockExecutionStore)(nil).RangeCompleteCrossClusterTask), ctx, request)
+}
+
+// GetReplicationTasks mocks base method
+func (m *MockExecutionStore) GetReplicationTasks(ctx context.Context, request *GetReplicationTasksRequest) (*InternalGetReplicationTasksResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplicationTasks", ctx, request)
+	ret0, _ := ret[0].(*InternalGetReplicationTasksResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReplicationTasks indicates an expected call of GetReplicationTasks
+func (mr *MockExecutionStoreMockRecorder) GetReplicationTasks(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicationTasks", reflect.TypeOf((*MockExecutionStore)(nil).GetReplicationTasks), ctx, request)
+}
+
+// CompleteReplicationTask mocks base method
+func (m *MockExecutionStore) CompleteReplicationTask(ctx context.Context, request *CompleteReplicationTaskRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteReplicationTask", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteReplicationTask indicates an expected call of CompleteReplicationTask
+func (mr *MockExecutionStoreMockRecorder) CompleteReplicationTask(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteReplicationTask", reflect.TypeOf((*MockExecutionStore)(nil).CompleteReplicationTask), ctx, request)
+}
+
+// RangeCompleteReplicationTask mocks base method
+func (m *MockExecutionStore) RangeCompleteReplicationTask(ctx context.Context, request *RangeCompleteReplicationTaskRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RangeCompleteReplicationTask", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RangeCompleteReplicationTask indicates an expected call of RangeCompleteReplicationTask
+func (mr *MockExecutionStoreMockRecorder) RangeCompleteReplicationTask(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RangeCompleteReplicationTask", reflect.TypeOf((*MockExecutionStore)(nil).RangeCompleteReplicationTask), ctx, request)
+}
+
+// PutReplicationTaskToDLQ mocks base method
+func (m *MockExecutionStore) PutReplicationTaskToDLQ(ctx context.Context, request *InternalPutReplicationTaskToDLQRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutReplicationTaskToDLQ", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutReplicationTaskToDLQ indicates an expected call of PutReplicationTaskToDLQ
+func (mr *MockExecutionStoreMockRecorder) PutReplicationTaskToDLQ(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutReplicationTaskToDLQ", reflect.TypeOf((*MockExecutionStore)(nil).PutReplicationTaskToDLQ), ctx, request)
+}
+
+// PutReplicationTasksToDLQ mocks base method
+func (m *MockExecutionStore) PutReplicationTasksToDLQ(ctx context.Context, request *InternalPutReplicationTasksToDLQRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutReplicationTasksToDLQ", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutReplicationTasksToDLQ indicates an expected call of PutReplicationTasksToDLQ
+func (mr *MockExecutionStoreMockRecorder) PutReplicationTasksToDLQ(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutReplicationTasksToDLQ", reflect.TypeOf((*MockExecutionStore)(nil).PutReplicationTasksToDLQ), ctx, request)
+}
+
+// GetReplicationTasksFromDLQ mocks base method
+func (m *MockExecutionStore) GetReplicationTasksFromDLQ(ctx context.Context, request *GetReplicationTasksFromDLQRequest) (*InternalGetReplicationTasksFromDLQResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplicationTasksFromDLQ", ctx, request)
+	ret0, _ := ret[0].(*InternalGetReplicationTasksFromDLQResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReplicationTasksFromDLQ indicates an expected call of GetReplicationTasksFromDLQ
+func (mr *MockExecutionStoreMockRecorder) GetReplicationTasksFromDLQ(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicationTasksFromDLQ", reflect.TypeOf((*MockExecutionStore)(nil).GetReplicationTasksFromDLQ), ctx, request)
+}
+
+// GetReplicationDLQSize mocks base method
+func (m *MockExecutionStore) GetReplicationDLQSize(ctx context.Context, request *GetReplicationDLQSizeRequest) (*GetReplicationDLQSizeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplicationDLQSize", ctx, request)
+	ret0, _ := ret[0].(*GetReplicationDLQSizeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReplicationDLQSize indicates an expected call of GetReplicationDLQSize
+func (mr *MockExecutionStoreMockRecorder) GetReplicationDLQSize(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicationDLQSize", reflect.TypeOf((*MockExecutionStore)(nil).GetReplicationDLQSize), ctx, request)
+}
+
+// DeleteReplicationTaskFromDLQ mocks base method
+func (m *MockExecutionStore) DeleteReplicationTaskFromDLQ(ctx context.Context, request *DeleteReplicationTaskFromDLQRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReplicationTaskFromDLQ", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReplicationTaskFromDLQ indicates an expected call of DeleteReplicationTaskFromDLQ
+func (mr *MockExecutionStoreMockRecorder) DeleteReplicationTaskFromDLQ(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReplicationTaskFromDLQ", reflect.TypeOf((*MockExecutionStore)(nil).DeleteReplicationTaskFromDLQ), ctx, request)
+}
+
+// RangeDeleteReplicationTaskFromDLQ mocks base method
+func (m *MockExecutionStore) RangeDeleteReplicationTaskFromDLQ(ctx context.Context, request *RangeDeleteReplicationTaskFromDLQRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RangeDeleteReplicationTaskFromDLQ", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RangeDeleteReplicationTaskFromDLQ indicates an expected call of RangeDeleteReplicationTaskFromDLQ
+func (mr *MockExecutionStoreMockRecorder) RangeDeleteReplicationTaskFromDLQ(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RangeDeleteReplicationTaskFromDLQ", reflect.TypeOf((*MockExecutionStore)(nil).RangeDeleteReplicationTaskFromDLQ), ctx, request)
+}
+
+// DeleteReplicationTasksFromDLQ mocks base method
+func (m *MockExecutionStore) DeleteReplicationTasksFromDLQ(ctx context.Context, request *DeleteReplicationTasksFromDLQRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReplicationTasksFromDLQ", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReplicationTasksFromDLQ indicates an expected call of DeleteReplicationTasksFromDLQ
+func (mr *MockExecutionStoreMockRecorder) DeleteReplicationTasksFromDLQ(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReplicationTasksFromDLQ", reflect.TypeOf((*MockExecutionStore)(nil).DeleteReplicationTasksFromDLQ), ctx, request)
+}
+
+// CreateFailoverMarkerTasks mocks base method
+func (m *MockExecutionStore) CreateFailoverMarkerTasks(ctx context.Context, request *CreateFailoverMarkersRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFailoverMarkerTasks", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFailoverMarkerTasks indicates an expected call of CreateFailoverMarkerTasks
+func (mr *MockExecutionStoreMockRecorder) CreateFailoverMarkerTasks(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFailoverMarkerTasks", reflect.TypeOf((*MockExecutionStore)(nil).CreateFailoverMarkerTasks), ctx, request)
+}
+
+// GetTimerIndexTasks mocks base method
+func (m *MockExecutionStore) GetTimerIndexTasks(ctx context.Context, request *GetTimerIndexTasksRequest) (*GetTimerIndexTasksResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimerIndexTasks", ctx, request)
+	ret0, _ := ret[0].(*GetTimerIndexTasksResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimerIndexTasks indicates an expected call of GetTimerIndexTasks
+func (mr *MockExecutionStoreMockRecorder) GetTimerIndexTasks(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimerIndexTasks", reflect.TypeOf((*MockExecutionStore)(nil).GetTimerIndexTasks), ctx, request)
+}
+
+// CompleteTimerTask mocks base method
+func (m *MockExecutionStore) CompleteTimerTask(ctx context.Context, request *CompleteTimerTaskRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteTimerTask", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteTimerTask indicates an expected call of CompleteTimerTask
+func (mr *MockExecutionStoreMockRecorder) CompleteTimerTask(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTimerTask", reflect.TypeOf((*MockExecutionStore)(nil).CompleteTimerTask), ctx, request)
+}
+
+// RangeCompleteTimerTask mocks base method
+func (m *MockExecutionStore) RangeCompleteTimerTask(ctx context.Context, request *RangeCompleteTimerTaskRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RangeCompleteTimerTask", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RangeCompleteTimerTask indicates an expected call of RangeCompleteTimerTask
+func (mr *MockExecutionStoreMockRecorder) RangeCompleteTimerTask(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RangeCompleteTimerTask", reflect.TypeOf((*MockExecutionStore)(nil).RangeCompleteTimerTask), ctx, request)
+}
+
+// CompleteTimerTasksForDomain mocks base method
+func (m *MockExecutionStore) CompleteTimerTasksForDomain(ctx context.Context, request *CompleteTimerTasksForDomainRequest) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteTimerTasksForDomain", ctx, request)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteTimerTasksForDomain indicates an expected call of CompleteTimerTasksForDomain
+func (mr *MockExecutionStoreMockRecorder) CompleteTimerTasksForDomain(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTimerTasksForDomain", reflect.TypeOf((*MockExecutionStore)(nil).CompleteTimerTasksForDomain), ctx, request)
+}
+
+// ListConcreteExecutions mocks base method
+func (m *MockExecutionStore) ListConcreteExecutions(ctx context.Context, request *ListConcreteExecutionsRequest) (*InternalListConcreteExecutionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConcreteExecutions", ctx, request)
+	ret0, _ := ret[0].(*InternalListConcreteExecutionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConcreteExecutions indicates an expected call of ListConcreteExecutions
+func (mr *MockExecutionStoreMockRecorder) ListConcreteExecutions(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConcreteExecutions", reflect.TypeOf((*MockExecutionStore)(nil).ListConcreteExecutions), ctx, request)
+}
+
+// ListCurrentExecutions mocks base method
+func (m *MockExecutionStore) ListCurrentExecutions(ctx context.Context, request *ListCurrentExecutionsRequest) (*ListCurrentExecutionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCurrentExecutions", ctx, request)
+	ret0, _ := ret[0].(*ListCurrentExecutionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCurrentExecutions indicates an expected call of ListCurrentExecutions
+func (mr *MockExecutionStoreMockRecorder) ListCurrentExecutions(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrentExecutions", reflect.TypeOf((*MockExecutionStore)(nil).ListCurrentExecutions), ctx, request)
+}
+
+// CountWorkflowExecutions mocks base method
+func (m *MockExecutionStore) CountWorkflowExecutions(ctx context.Context, request *CountExecutionsRequest) (*CountExecutionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWorkflowExecutions", ctx, request)
+	ret0, _ := ret[0].(*CountExecutionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWorkflowExecutions indicates an expected call of CountWorkflowExecutions
+func (mr *MockExecutionStoreMockRecorder) CountWorkflowExecutions(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWorkflowExecutions", reflect.TypeOf((*MockExecutionStore)(nil).CountWorkflowExecutions), ctx, request)
+}
+
+// MockHistoryStore is a mock of HistoryStore interface
+type MockHistoryStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryStoreMockRecorder
+}
+
+// MockHistoryStoreMockRecorder is the mock recorder for MockHistoryStore
+type MockHistoryStoreMockRecorder struct {
+	mock *MockHistoryStore
+}
+
+// NewMockHistoryStore creates a new mock instance
+func NewMockHistoryStore(ctrl *gomock.Controller) *MockHistoryStore {
+	mock := &MockHistoryStore{ctrl: ctrl}
+	mock.recorder = &MockHistoryStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockHistoryStore) EXPECT() *MockHistoryStoreMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockHistoryStore) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close
+func (mr *MockHistoryStoreMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockHistoryStore)(nil).Close))
+}
+
+// GetName mocks base method
+func (m *MockHistoryStore) GetName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetName indicates an expected call of GetName
+func (mr *MockHistoryStoreMockRecorder) GetName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetName", reflect.TypeOf((*MockHistoryStore)(nil).GetName))
+}
+
+// AppendHistoryNodes mocks base method
+func (m *MockHistoryStore) AppendHistoryNodes(ctx context.Context, request *InternalAppendHistoryNodesRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendHistoryNodes", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendHistoryNodes indicates an expected call of AppendHistoryNodes
+func (mr *MockHistoryStoreMockRecorder) AppendHistoryNodes(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendHistoryNodes", reflect.TypeOf((*MockHistoryStore)(nil).AppendHistoryNodes), ctx, request)
+}
+
+// ReadHistoryBranch mocks base method
+func (m *MockHistoryStore) ReadHistoryBranch(ctx context.Context, request *InternalReadHistoryBranchRequest) (*InternalReadHistoryBranchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadHistoryBranch", ctx, request)
+	ret0, _ := ret[0].(*InternalReadHistoryBranchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadHistoryBranch indicates an expected call of ReadHistoryBranch
+func (mr *MockHistoryStoreMockRecorder) ReadHistoryBranch(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadHistoryBranch", reflect.TypeOf((*MockHistoryStore)(nil).ReadHistoryBranch), ctx, request)
+}
+
+// ReadHistoryNode mocks base method
+func (m *MockHistoryStore) ReadHistoryNode(ctx context.Context, request *InternalReadHistoryNodeRequest) (*InternalReadHistoryNodeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadHistoryNode", ctx, request)
+	ret0, _ := ret[0].(*InternalReadHistoryNodeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadHistoryNode indicates an expected call of ReadHistoryNode
+func (mr *MockHistoryStoreMockRecorder) ReadHistoryNode(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadHistoryNode", reflect.TypeOf((*MockHistoryStore)(nil).ReadHistoryNode), ctx, request)
+}
+
+// ForkHistoryBranch mocks base method
+func (m *MockHistoryStore) ForkHistoryBranch(ctx context.Context, request *InternalForkHistoryBranchRequest) (*InternalForkHistoryBranchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForkHistoryBranch", ctx, request)
+	ret0, _ := ret[0].(*InternalForkHistoryBranchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForkHistoryBranch indicates an expected call of ForkHistoryBranch
+func (mr *MockHistoryStoreMockRecorder) ForkHistoryBranch(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForkHistoryBranch", reflect.TypeOf((*MockHistoryStore)(nil).ForkHistoryBranch), ctx, request)
+}
+
+// DeleteHistoryBranch mocks base method
+func (m *MockHistoryStore) DeleteHistoryBranch(ctx context.Context, request *InternalDeleteHistoryBranchRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHistoryBranch", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHistoryBranch indicates an expected call of DeleteHistoryBranch
+func (mr *MockHistoryStoreMockRecorder) DeleteHistoryBranch(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHistoryBranch", reflect.TypeOf((*MockHistoryStore)(nil).DeleteHistoryBranch), ctx, request)
+}
+
+// GetHistoryTree mocks base method
+func (m *MockHistoryStore) GetHistoryTree(ctx context.Context, request *InternalGetHistoryTreeRequest) (*InternalGetHistoryTreeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistoryTree", ctx, request)
+	ret0, _ := ret[0].(*InternalGetHistoryTreeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistoryTree indicates an expected call of GetHistoryTree
+func (mr *MockHistoryStoreMockRecorder) GetHistoryTree(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistoryTree", reflect.TypeOf((*MockHistoryStore)(nil).GetHistoryTree), ctx, request)
+}
+
+// GetAllHistoryTreeBranches mocks base method
+func (m *MockHistoryStore) GetAllHistoryTreeBranches(ctx context.Context, request *GetAllHistoryTreeBranchesRequest) (*GetAllHistoryTreeBranchesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllHistoryTreeBranches", ctx, request)
+	ret0, _ := ret[0].(*GetAllHistoryTreeBranchesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllHistoryTreeBranches indicates an expected call of GetAllHistoryTreeBranches
+func (mr *MockHistoryStoreMockRecorder) GetAllHistoryTreeBranches(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllHistoryTreeBranches", reflect.TypeOf((*MockHistoryStore)(nil).GetAllHistoryTreeBranches), ctx, request)
+}
+
+// MockVisibilityStore is a mock of VisibilityStore interface
+type MockVisibilityStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockVisibilityStoreMockRecorder
+}
+
+// MockVisibilityStoreMockRecorder is the mock recorder for MockVisibilityStore
+type MockVisibilityStoreMockRecorder struct {
+	mock *MockVisibilityStore
+}
+
+// NewMockVisibilityStore creates a new mock instance
+func NewMockVisibilityStore(ctrl *gomock.Controller) *MockVisibilityStore {
+	mock := &MockVisibilityStore{ctrl: ctrl}
+	mock.recorder = &MockVisibilityStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockVisibilityStore) EXPECT() *MockVisibilityStoreMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockVisibilityStore) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close
+func (mr *MockVisibilityStoreMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockVisibilityStore)(nil).Close))
+}
+
+// GetName mocks base method
+func (m *MockVisibilityStore) GetName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetName indicates an expected call of GetName
+func (mr *MockVisibilityStoreMockRecorder) GetName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetName", reflect.TypeOf((*MockVisibilityStore)(nil).GetName))
+}
+
+// RecordWorkflowExecutionStarted mocks base method
+func (m *MockVisibilityStore) RecordWorkflowExecutionStarted(ctx context.Context, request *InternalRecordWorkflowExecutionStartedRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWorkflowExecutionStarted", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordWorkflowExecutionStarted indicates an expected call of RecordWorkflowExecutionStarted
+func (mr *MockVisibilityStoreMockRecorder) RecordWorkflowExecutionStarted(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWorkflowExecutionStarted", reflect.TypeOf((*MockVisibilityStore)(nil).RecordWorkflowExecutionStarted), ctx, request)
+}
+
+// RecordWorkflowExecutionClosed mocks base method
+func (m *MockVisibilityStore) RecordWorkflowExecutionClosed(ctx context.Context, request *InternalRecordWorkflowExecutionClosedRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWorkflowExecutionClosed", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordWorkflowExecutionClosed indicates an expected call of RecordWorkflowExecutionClosed
+func (mr *MockVisibilityStoreMockRecorder) RecordWorkflowExecutionClosed(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWorkflowExecutionClosed", reflect.TypeOf((*MockVisibilityStore)(nil).RecordWorkflowExecutionClosed), ctx, request)
+}
+
+// UpsertWorkflowExecution mocks base method
+func (m *MockVisibilityStore) UpsertWorkflowExecution(ctx context.Context, request *InternalUpsertWorkflowExecutionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkflowExecution", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkflowExecution indicates an expected call of UpsertWorkflowExecution
+func (mr *MockVisibilityStoreMockRecorder) UpsertWorkflowExecution(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkflowExecution", reflect.TypeOf((*MockVisibilityStore)(nil).UpsertWorkflowExecution), ctx, request)
+}
+
+// ListOpenWorkflowExecutions mocks base method
+func (m *MockVisibilityStore) ListOpenWorkflowExecutions(ctx context.Context, request *InternalListWorkflowExecutionsRequest) (*InternalListWorkflowExecutionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpenWorkflowExecutions", ctx, request)
+	ret0, _ := ret[0].(*InternalListWorkflowExecutionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpenWorkflowExecutions indicates an expected call of ListOpenWorkflowExecutions
+func (mr *MockVisibilityStoreMockRecorder) ListOpenWorkflowExecutions(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpenWorkflowExecutions", reflect.TypeOf((*MockVisibilityStore)(nil).ListOpenWorkflowExecutions), ctx, request)
+}
+
+// ListClosedWorkflowExecutions mocks base method
+func (m *MockVisibilityStore) ListClosedWorkflowExecutions(ctx context.Context, request *InternalListWorkflowExecutionsRequest) (*InternalListWorkflowExecutionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClosedWorkflowExecutions", ctx, request)
+	ret0, _ := ret[0].(*InternalListWorkflowExecutionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClosedWorkflowExecutions indicates an expected call of ListClosedWorkflowExecutions
+func (mr *MockVisibilityStoreMockRecorder) ListClosedWorkflowExecutions(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClosedWorkflowExecutions", reflect.TypeOf((*MockVisibilityStore)(nil).ListClosedWorkflowExecutions), ctx, request)
+}
+
+// ListOpenWorkflowExecutionsByType mocks base method
+func (m *MockVisibilityStore) ListOpenWorkflowExecutionsByType(ctx context.Context, request *InternalListWorkflowExecutionsByTypeRequest) (*InternalListWorkflowExecutionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpenWorkflowExecutionsByType", ctx, request)
+	ret0, _ := ret[0].(*InternalListWorkflowExecutionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpenWorkflowExecutionsByType indicates an expected call of ListOpenWorkflowExecutionsByType
+func (mr *MockVisibilityStoreMockRecorder) ListOpenWorkflowExecutionsByType(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpenWorkflowExecutionsByType", reflect.TypeOf((*MockVisibilityStore)(nil).ListOpenWorkflowExecutionsByType), ctx, request)
+}
+
+// ListClosedWorkflowExecutionsByType mocks base method
+func (m *MockVisibilityStore) ListClosedWorkflowExecutionsByType(ctx context.Context, request *InternalListWorkflowExecutionsByTypeRequest) (*InternalListWorkflowExecutionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClosedWorkflowExecutionsByType", ctx, request)
+	ret0, _ := ret[0].(*InternalListWorkflowExecutionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClosedWorkflowExecutionsByType indicates an expected call of ListClosedWorkflowExecutionsByType
+func (mr *MockVisibilityStoreMockRecorder) ListClosedWorkflowExecutionsByType(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClosedWorkflowExecutionsByType", reflect.TypeOf((*MockVisibilityStore)(nil).ListClosedWorkflowExecutionsByType), ctx, request)
+}
+
+// ListOpenWorkflowExecutionsByWorkflowID mocks base method
+func (m *MockVisibilityStore) ListOpenWorkflowExecutionsByWorkflowID(ctx context.Context, request *InternalListWorkflowExecutionsByWorkflowIDRequest) (*InternalListWorkflowExecutionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpenWorkflowExecutionsByWorkflowID", ctx, request)
+	ret0, _ := ret[0].(*InternalListWorkflowExecutionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpenWorkflowExecutionsByWorkflowID indicates an expected call of ListOpenWorkflowExecutionsByWorkflowID
+func (mr *MockVisibilityStoreMockRecorder) ListOpenWorkflowExecutionsByWorkflowID(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpenWorkflowExecutionsByWorkflowID", reflect.TypeOf((*MockVisibilityStore)(nil).ListOpenWorkflowExecutionsByWorkflowID), ctx, request)
+}
+
+// ListClosedWorkflowExecutionsByWorkflowID mocks base method
+func (m *MockVisibilityStore) ListClosedWorkflowExecutionsByWorkflowID(ctx context.Context, request *InternalListWorkflowExecutionsByWorkflowIDRequest) (*InternalListWorkflowExecutionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClosedWorkflowExecutionsByWorkflowID", ctx, request)
+	ret0, _ := ret[0].(*InternalListWorkflowExecutionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClosedWorkflowExecutionsByWorkflowID indicates an expected call of ListClosedWorkflowExecutionsByWorkflowID
+func (mr *MockVisibilityStoreMockRecorder) ListClosedWorkflowExecutionsByWorkflowID(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClosedWorkflowExecutionsByWorkflowID", reflect.TypeOf((*MockVisibilityStore)(nil).ListClosedWorkflowExecutionsByWorkflowID), ctx, request)
+}
+
+// ListClosedWorkflowExecutionsByStatus mocks base method
+func (m *MockVisibilityStore) ListClosedWorkflowExecutionsByStatus(ctx context.Context, request *InternalListClosedWorkflowExecutionsByStatusRequest) (*InternalListWorkflowExecutionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClosedWorkflowExecutionsByStatus", ctx, request)
+	ret0, _ := ret[0].(*InternalListWorkflowExecutionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClosedWorkflowExecutionsByStatus indicates an expected call of ListClosedWorkflowExecutionsByStatus
+func (mr *MockVisibilityStoreMockRecorder) ListClosedWorkflowExecutionsByStatus(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClosedWorkflowExecutionsByStatus", reflect.TypeOf((*MockVisibilityStore)(nil).ListClosedWorkflowExecutionsByStatus), ctx, request)
+}
+
+// GetClosedWorkflowExecution mocks base method
+func (m *MockVisibilityStore) GetClosedWorkflowExecution(ctx context.Context, request *InternalGetClosedWorkflowExecutionRequest) (*InternalGetClosedWorkflowExecutionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClosedWorkflowExecution", ctx, request)
+	ret0, _ := ret[0].(*InternalGetClosedWorkflowExecutionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClosedWorkflowExecution indicates an expected call of GetClosedWorkflowExecution
+func (mr *MockVisibilityStoreMockRecorder) GetClosedWorkflowExecution(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClosedWorkflowExecution", reflect.TypeOf((*MockVisibilityStore)(nil).GetClosedWorkflowExecution), ctx, request)
+}
+
+// DeleteWorkflowExecution mocks base method
+func (m *MockVisibilityStore) DeleteWorkflowExecution(ctx context.Context, request *VisibilityDeleteWorkflowExecutionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkflowExecution", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkflowExecution indicates an expected call of DeleteWorkflowExecution
+func (mr *MockVisibilityStoreMockRecorder) DeleteWorkflowExecution(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkflowExecution", reflect.TypeOf((*MockVisibilityStore)(nil).DeleteWorkflowExecution), ctx, request)
+}
+
+// ListWorkflowExecutions mocks base method
+func (m *MockVisibilityStore) ListWorkflowExecutions(ctx context.Context, request *ListWorkflowExecutionsByQueryRequest) (*InternalListWorkflowExecutionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkflowExecutions", ctx, request)
+	ret0, _ := ret[0].(*InternalListWorkflowExecutionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkflowExecutions indicates an expected call of ListWorkflowExecutions
+func (mr *MockVisibilityStoreMockRecorder) ListWorkflowExecutions(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkflowExecutions", reflect.TypeOf((*MockVisibilityStore)(nil).ListWorkflowExecutions), ctx, request)
+}
+
+// ScanWorkflowExecutions mocks base method
+func (m *MockVisibilityStore) ScanWorkflowExecutions(ctx context.Context, request *ListWorkflowExecutionsByQueryRequest) (*InternalListWorkflowExecutionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanWorkflowExecutions", ctx, request)
+	ret0, _ := ret[0].(*InternalListWorkflowExecutionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanWorkflowExecutions indicates an expected call of ScanWorkflowExecutions
+func (mr *MockVisibilityStoreMockRecorder) ScanWorkflowExecutions(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanWorkflowExecutions", reflect.TypeOf((*MockVisibilityStore)(nil).ScanWorkflowExecutions), ctx, request)
+}
+
+// CountWorkflowExecutions mocks base method
+func (m *MockVisibilityStore) CountWorkflowExecutions(ctx context.Context, request *CountWorkflowExecutionsRequest) (*CountWorkflowExecutionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWorkflowExecutions", ctx, request)
+	ret0, _ := ret[0].(*CountWorkflowExecutionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWorkflowExecutions indicates an expected call of CountWorkflowExecutions
+func (mr *MockVisibilityStoreMockRecorder) CountWorkflowExecutions(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWorkflowExecutions", reflect.TypeOf((*MockVisibilityStore)(nil).CountWorkflowExecutions), ctx, request)
+}
+
+// MockQueue is a mock of Queue interface
+type MockQueue struct {
+	ctrl     *gomock.Controller
+	recorder *MockQueueMockRecorder
+}
+
+// MockQueueMockRecorder is the mock recorder for MockQueue
+type MockQueueMockRecorder struct {
+	mock *MockQueue
+}
+
+// NewMockQueue creates a new mock instance
+func NewMockQueue(ctrl *gomock.Controller) *MockQueue {
+	mock := &MockQueue{ctrl: ctrl}
+	mock.recorder = &MockQueueMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockQueue) EXPECT() *MockQueueMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockQueue) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close
+func (mr *MockQueueMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockQueue)(nil).Close))
+}
+
+// EnqueueMessage mocks base method
+func (m *MockQueue) EnqueueMessage(ctx context.Context, messagePayload []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueMessage", ctx, messagePayload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueMessage indicates an expected call of EnqueueMessage
+func (mr *MockQueueMockRecorder) EnqueueMessage(ctx, messagePayload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueMessage", reflect.TypeOf((*MockQueue)(nil).EnqueueMessage), ctx, messagePayload)
+}
+
+// EnqueueMessageWithID mocks base method
+func (m *MockQueue) EnqueueMessageWithID(ctx context.Context, messagePayload []byte, messageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueMessageWithID", ctx, messagePayload, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueMessageWithID indicates an expected call of EnqueueMessageWithID
+func (mr *MockQueueMockRecorder) EnqueueMessageWithID(ctx, messagePayload, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueMessageWithID", reflect.TypeOf((*MockQueue)(nil).EnqueueMessageWithID), ctx, messagePayload, messageID)
+}
+
+// EnqueueMessages mocks base method
+func (m *MockQueue) EnqueueMessages(ctx context.Context, messagePayloads [][]byte) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueMessages", ctx, messagePayloads)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueMessages indicates an expected call of EnqueueMessages
+func (mr *MockQueueMockRecorder) EnqueueMessages(ctx, messagePayloads interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueMessages", reflect.TypeOf((*MockQueue)(nil).EnqueueMessages), ctx, messagePayloads)
+}
+
+// ReadMessages mocks base method
+func (m *MockQueue) ReadMessages(ctx context.Context, lastMessageID int64, maxCount int) ([]*InternalQueueMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadMessages", ctx, lastMessageID, maxCount)
+	ret0, _ := ret[0].([]*InternalQueueMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadMessages indicates an expected call of ReadMessages
+func (mr *MockQueueMockRecorder) ReadMessages(ctx, lastMessageID, maxCount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadMessages", reflect.TypeOf((*MockQueue)(nil).ReadMessages), ctx, lastMessageID, maxCount)
+}
+
+// DeleteMessagesBefore mocks base method
+func (m *MockQueue) DeleteMessagesBefore(ctx context.Context, messageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessagesBefore", ctx, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessagesBefore indicates an expected call of DeleteMessagesBefore
+func (mr *MockQueueMockRecorder) DeleteMessagesBefore(ctx, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessagesBefore", reflect.TypeOf((*MockQueue)(nil).DeleteMessagesBefore), ctx, messageID)
+}
+
+// UpdateAckLevel mocks base method
+func (m *MockQueue) UpdateAckLevel(ctx context.Context, messageID int64, clusterName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAckLevel", ctx, messageID, clusterName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAckLevel indicates an expected call of UpdateAckLevel
+func (mr *MockQueueMockRecorder) UpdateAckLevel(ctx, messageID, clusterName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAckLevel", reflect.TypeOf((*MockQueue)(nil).UpdateAckLevel), ctx, messageID, clusterName)
+}
+
+// GetAckLevels mocks base method
+func (m *MockQueue) GetAckLevels(ctx context.Context) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAckLevels", ctx)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAckLevels indicates an expected call of GetAckLevels
+func (mr *MockQueueMockRecorder) GetAckLevels(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAckLevels", reflect.TypeOf((*MockQueue)(nil).GetAckLevels), ctx)
+}
+
+// EnqueueMessageToDLQ mocks base method
+func (m *MockQueue) EnqueueMessageToDLQ(ctx context.Context, messagePayload []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueMessageToDLQ", ctx, messagePayload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueMessageToDLQ indicates an expected call of EnqueueMessageToDLQ
+func (mr *MockQueueMockRecorder) EnqueueMessageToDLQ(ctx, messagePayload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueMessageToDLQ", reflect.TypeOf((*MockQueue)(nil).EnqueueMessageToDLQ), ctx, messagePayload)
+}
+
+// ReadMessagesFromDLQ mocks base method
+func (m *MockQueue) ReadMessagesFromDLQ(ctx context.Context, firstMessageID, lastMessageID int64, pageSize int, pageToken []byte) ([]*InternalQueueMessage, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadMessagesFromDLQ", ctx, firstMessageID, lastMessageID, pageSize, pageToken)
+	ret0, _ := ret[0].([]*InternalQueueMessage)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReadMessagesFromDLQ indicates an expected call of ReadMessagesFromDLQ
+func (mr *MockQueueMockRecorder) ReadMessagesFromDLQ(ctx, firstMessageID, lastMessageID, pageSize, pageToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadMessagesFromDLQ", reflect.TypeOf((*MockQueue)(nil).ReadMessagesFromDLQ), ctx, firstMessageID, lastMessageID, pageSize, pageToken)
+}
+
+// DeleteMessageFromDLQ mocks base method
+func (m *MockQueue) DeleteMessageFromDLQ(ctx context.Context, messageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessageFromDLQ", ctx, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessageFromDLQ indicates an expected call of DeleteMessageFromDLQ
+func (mr *MockQueueMockRecorder) DeleteMessageFromDLQ(ctx, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessageFromDLQ", reflect.TypeOf((*MockQueue)(nil).DeleteMessageFromDLQ), ctx, messageID)
+}
+
+// RangeDeleteMessagesFromDLQ mocks base method
+func (m *MockQueue) RangeDeleteMessagesFromDLQ(ctx context.Context, firstMessageID, lastMessageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RangeDeleteMessagesFromDLQ", ctx, firstMessageID, lastMessageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RangeDeleteMessagesFromDLQ indicates an expected call of RangeDeleteMessagesFromDLQ
+func (mr *MockQueueMockRecorder) RangeDeleteMessagesFromDLQ(ctx, firstMessageID, lastMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RangeDeleteMessagesFromDLQ", reflect.TypeOf((*MockQueue)(nil).RangeDeleteMessagesFromDLQ), ctx, firstMessageID, lastMessageID)
+}
+
+// UpdateDLQAckLevel mocks base method
+func (m *MockQueue) UpdateDLQAckLevel(ctx context.Context, messageID int64, clusterName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDLQAckLevel", ctx, messageID, clusterName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDLQAckLevel indicates an expected call of UpdateDLQAckLevel
+func (mr *MockQueueMockRecorder) UpdateDLQAckLevel(ctx, messageID, clusterName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDLQAckLevel", reflect.TypeOf((*MockQueue)(nil).UpdateDLQAckLevel), ctx, messageID, clusterName)
+}
+
+// GetDLQAckLevels mocks base method
+func (m *MockQueue) GetDLQAckLevels(ctx context.Context) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDLQAckLevels", ctx)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDLQAckLevels indicates an expected call of GetDLQAckLevels
+func (mr *MockQueueMockRecorder) GetDLQAckLevels(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQAckLevels", reflect.TypeOf((*MockQueue)(nil).GetDLQAckLevels), ctx)
+}
+
+// GetDLQSize mocks base method
+func (m *MockQueue) GetDLQSize(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDLQSize", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDLQSize indicates an expected call of GetDLQSize
+func (mr *MockQueueMockRecorder) GetDLQSize(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQSize", reflect.TypeOf((*MockQueue)(nil).GetDLQSize), ctx)
+}
@@ -37,24 +37,32 @@ type (
 	}
 
 	workflowExecutionPersistenceClient struct {
+		executionManagerCompositeOperations
+
 		metricClient metrics.Client
 		persistence  ExecutionManager
 		logger       log.Logger
 	}
 
 	taskPersistenceClient struct {
+		taskManagerCompositeOperations
+
 		metricClient metrics.Client
 		persistence  TaskManager
 		logger       log.Logger
 	}
 
 	historyPersistenceClient struct {
+		historyManagerCompositeOperations
+
 		metricClient metrics.Client
 		persistence  HistoryManager
 		logger       log.Logger
 	}
 
 	metadataPersistenceClient struct {
+		metadataManagerCompositeOperations
+
 		metricClient metrics.Client
 		persistence  MetadataManager
 		logger       log.Logger
@@ -100,11 +108,13 @@ func NewWorkflowExecutionPersistenceMetricsClient(
 	metricClient metrics.Client,
 	logger log.Logger,
 ) ExecutionManager {
-	return &workflowExecutionPersistenceClient{
+	client := &workflowExecutionPersistenceClient{
 		persistence:  persistence,
 		metricClient: metricClient,
 		logger:       logger,
 	}
+	client.executionManagerCompositeOperations = executionManagerCompositeOperations{manager: client}
+	return client
 }
 
 // NewTaskPersistenceMetricsClient creates a client to manage tasks
@@ -113,11 +123,13 @@ func NewTaskPersistenceMetricsClient(
 	metricClient metrics.Client,
 	logger log.Logger,
 ) TaskManager {
-	return &taskPersistenceClient{
+	client := &taskPersistenceClient{
 		persistence:  persistence,
 		metricClient: metricClient,
 		logger:       logger,
 	}
+	client.taskManagerCompositeOperations = taskManagerCompositeOperations{manager: client}
+	return client
 }
 
 // NewHistoryPersistenceMetricsClient creates a HistoryManager client to manage workflow execution history
@@ -126,11 +138,13 @@ func NewHistoryPersistenceMetricsClient(
 	metricClient metrics.Client,
 	logger log.Logger,
 ) HistoryManager {
-	return &historyPersistenceClient{
+	client := &historyPersistenceClient{
 		persistence:  persistence,
 		metricClient: metricClient,
 		logger:       logger,
 	}
+	client.historyManagerCompositeOperations = historyManagerCompositeOperations{manager: client}
+	return client
 }
 
 // NewMetadataPersistenceMetricsClient creates a MetadataManager client to manage metadata
@@ -139,11 +153,13 @@ func NewMetadataPersistenceMetricsClient(
 	metricClient metrics.Client,
 	logger log.Logger,
 ) MetadataManager {
-	return &metadataPersistenceClient{
+	client := &metadataPersistenceClient{
 		persistence:  persistence,
 		metricClient: metricClient,
 		logger:       logger,
 	}
+	client.metadataManagerCompositeOperations = metadataManagerCompositeOperations{manager: client}
+	return client
 }
 
 // NewVisibilityPersistenceMetricsClient creates a client to manage visibility
@@ -210,6 +226,40 @@ func (p *shardPersistenceClient) GetShard(
 	return response, err
 }
 
+func (p *shardPersistenceClient) GetShards(
+	ctx context.Context,
+	request *GetShardsRequest,
+) (*GetShardsResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceGetShardsScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceGetShardsScope, metrics.PersistenceLatency)
+	response, err := p.persistence.GetShards(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceGetShardsScope, err)
+	}
+
+	return response, err
+}
+
+func (p *shardPersistenceClient) ListShards(
+	ctx context.Context,
+	request *ListShardsRequest,
+) (*ListShardsResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceListShardsScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceListShardsScope, metrics.PersistenceLatency)
+	response, err := p.persistence.ListShards(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceListShardsScope, err)
+	}
+
+	return response, err
+}
+
 func (p *shardPersistenceClient) UpdateShard(
 	ctx context.Context,
 	request *UpdateShardRequest,
@@ -248,6 +298,11 @@ func (p *shardPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence, closing is not measured
+func (p *shardPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *workflowExecutionPersistenceClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -290,6 +345,23 @@ func (p *workflowExecutionPersistenceClient) GetWorkflowExecution(
 	return response, err
 }
 
+func (p *workflowExecutionPersistenceClient) GetWorkflowExecutionForUpdate(
+	ctx context.Context,
+	request *GetWorkflowExecutionForUpdateRequest,
+) (*GetWorkflowExecutionForUpdateResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceGetWorkflowExecutionForUpdateScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceGetWorkflowExecutionForUpdateScope, metrics.PersistenceLatency)
+	response, err := p.persistence.GetWorkflowExecutionForUpdate(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceGetWorkflowExecutionForUpdateScope, err)
+	}
+
+	return response, err
+}
+
 func (p *workflowExecutionPersistenceClient) UpdateWorkflowExecution(
 	ctx context.Context,
 	request *UpdateWorkflowExecutionRequest,
@@ -307,6 +379,23 @@ func (p *workflowExecutionPersistenceClient) UpdateWorkflowExecution(
 	return resp, err
 }
 
+func (p *workflowExecutionPersistenceClient) FlushBufferedEvents(
+	ctx context.Context,
+	request *FlushBufferedEventsRequest,
+) error {
+	p.metricClient.IncCounter(metrics.PersistenceFlushBufferedEventsScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceFlushBufferedEventsScope, metrics.PersistenceLatency)
+	err := p.persistence.FlushBufferedEvents(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceFlushBufferedEventsScope, err)
+	}
+
+	return err
+}
+
 func (p *workflowExecutionPersistenceClient) ConflictResolveWorkflowExecution(
 	ctx context.Context,
 	request *ConflictResolveWorkflowExecutionRequest,
@@ -375,6 +464,23 @@ func (p *workflowExecutionPersistenceClient) DeleteCurrentWorkflowExecution(
 	return err
 }
 
+func (p *workflowExecutionPersistenceClient) DeleteWorkflowExecutions(
+	ctx context.Context,
+	request *DeleteWorkflowExecutionsRequest,
+) (*DeleteWorkflowExecutionsResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceDeleteWorkflowExecutionsScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceDeleteWorkflowExecutionsScope, metrics.PersistenceLatency)
+	response, err := p.persistence.DeleteWorkflowExecutions(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceDeleteWorkflowExecutionsScope, err)
+	}
+
+	return response, err
+}
+
 func (p *workflowExecutionPersistenceClient) GetCurrentExecution(
 	ctx context.Context,
 	request *GetCurrentExecutionRequest,
@@ -392,6 +498,23 @@ func (p *workflowExecutionPersistenceClient) GetCurrentExecution(
 	return response, err
 }
 
+func (p *workflowExecutionPersistenceClient) GetCurrentRunID(
+	ctx context.Context,
+	request *GetCurrentRunIDRequest,
+) (*GetCurrentRunIDResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceGetCurrentRunIDScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceGetCurrentRunIDScope, metrics.PersistenceLatency)
+	response, err := p.persistence.GetCurrentRunID(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceGetCurrentRunIDScope, err)
+	}
+
+	return response, err
+}
+
 func (p *workflowExecutionPersistenceClient) ListCurrentExecutions(
 	ctx context.Context,
 	request *ListCurrentExecutionsRequest,
@@ -426,6 +549,40 @@ func (p *workflowExecutionPersistenceClient) IsWorkflowExecutionExists(
 	return response, err
 }
 
+func (p *workflowExecutionPersistenceClient) WorkflowExists(
+	ctx context.Context,
+	request *WorkflowExistsRequest,
+) (*WorkflowExistsResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceWorkflowExistsScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceWorkflowExistsScope, metrics.PersistenceLatency)
+	response, err := p.persistence.WorkflowExists(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceWorkflowExistsScope, err)
+	}
+
+	return response, err
+}
+
+func (p *workflowExecutionPersistenceClient) CheckCurrentExecution(
+	ctx context.Context,
+	request *CheckCurrentExecutionRequest,
+) (*CheckCurrentExecutionResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceCheckCurrentExecutionScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceCheckCurrentExecutionScope, metrics.PersistenceLatency)
+	response, err := p.persistence.CheckCurrentExecution(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceCheckCurrentExecutionScope, err)
+	}
+
+	return response, err
+}
+
 func (p *workflowExecutionPersistenceClient) ListConcreteExecutions(
 	ctx context.Context,
 	request *ListConcreteExecutionsRequest,
@@ -443,6 +600,23 @@ func (p *workflowExecutionPersistenceClient) ListConcreteExecutions(
 	return response, err
 }
 
+func (p *workflowExecutionPersistenceClient) CountWorkflowExecutions(
+	ctx context.Context,
+	request *CountExecutionsRequest,
+) (*CountExecutionsResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceCountExecutionsScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceCountExecutionsScope, metrics.PersistenceLatency)
+	response, err := p.persistence.CountWorkflowExecutions(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceCountExecutionsScope, err)
+	}
+
+	return response, err
+}
+
 func (p *workflowExecutionPersistenceClient) GetTransferTasks(
 	ctx context.Context,
 	request *GetTransferTasksRequest,
@@ -477,6 +651,23 @@ func (p *workflowExecutionPersistenceClient) GetReplicationTasks(
 	return response, err
 }
 
+func (p *workflowExecutionPersistenceClient) GetFailoverMarkerTasks(
+	ctx context.Context,
+	request *GetFailoverMarkerTasksRequest,
+) (*GetFailoverMarkerTasksResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceGetFailoverMarkerTasksScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceGetFailoverMarkerTasksScope, metrics.PersistenceLatency)
+	response, err := p.persistence.GetFailoverMarkerTasks(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceGetFailoverMarkerTasksScope, err)
+	}
+
+	return response, err
+}
+
 func (p *workflowExecutionPersistenceClient) CompleteTransferTask(
 	ctx context.Context,
 	request *CompleteTransferTaskRequest,
@@ -511,6 +702,74 @@ func (p *workflowExecutionPersistenceClient) RangeCompleteTransferTask(
 	return err
 }
 
+func (p *workflowExecutionPersistenceClient) CompleteTransferTasks(
+	ctx context.Context,
+	request *CompleteTransferTasksRequest,
+) error {
+	p.metricClient.IncCounter(metrics.PersistenceCompleteTransferTasksScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceCompleteTransferTasksScope, metrics.PersistenceLatency)
+	err := p.persistence.CompleteTransferTasks(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceCompleteTransferTasksScope, err)
+	}
+
+	return err
+}
+
+func (p *workflowExecutionPersistenceClient) GetCrossClusterTasks(
+	ctx context.Context,
+	request *GetCrossClusterTasksRequest,
+) (*GetCrossClusterTasksResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceGetCrossClusterTasksScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceGetCrossClusterTasksScope, metrics.PersistenceLatency)
+	response, err := p.persistence.GetCrossClusterTasks(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceGetCrossClusterTasksScope, err)
+	}
+
+	return response, err
+}
+
+func (p *workflowExecutionPersistenceClient) CompleteCrossClusterTask(
+	ctx context.Context,
+	request *CompleteCrossClusterTaskRequest,
+) error {
+	p.metricClient.IncCounter(metrics.PersistenceCompleteCrossClusterTaskScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceCompleteCrossClusterTaskScope, metrics.PersistenceLatency)
+	err := p.persistence.CompleteCrossClusterTask(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceCompleteCrossClusterTaskScope, err)
+	}
+
+	return err
+}
+
+func (p *workflowExecutionPersistenceClient) RangeCompleteCrossClusterTask(
+	ctx context.Context,
+	request *RangeCompleteCrossClusterTaskRequest,
+) error {
+	p.metricClient.IncCounter(metrics.PersistenceRangeCompleteCrossClusterTaskScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceRangeCompleteCrossClusterTaskScope, metrics.PersistenceLatency)
+	err := p.persistence.RangeCompleteCrossClusterTask(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceRangeCompleteCrossClusterTaskScope, err)
+	}
+
+	return err
+}
+
 func (p *workflowExecutionPersistenceClient) CompleteReplicationTask(
 	ctx context.Context,
 	request *CompleteReplicationTaskRequest,
@@ -562,6 +821,23 @@ func (p *workflowExecutionPersistenceClient) PutReplicationTaskToDLQ(
 	return err
 }
 
+func (p *workflowExecutionPersistenceClient) PutReplicationTasksToDLQ(
+	ctx context.Context,
+	request *PutReplicationTasksToDLQRequest,
+) error {
+	p.metricClient.IncCounter(metrics.PersistencePutReplicationTasksToDLQScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistencePutReplicationTasksToDLQScope, metrics.PersistenceLatency)
+	err := p.persistence.PutReplicationTasksToDLQ(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistencePutReplicationTasksToDLQScope, err)
+	}
+
+	return err
+}
+
 func (p *workflowExecutionPersistenceClient) GetReplicationTasksFromDLQ(
 	ctx context.Context,
 	request *GetReplicationTasksFromDLQRequest,
@@ -630,6 +906,23 @@ func (p *workflowExecutionPersistenceClient) RangeDeleteReplicationTaskFromDLQ(
 	return nil
 }
 
+func (p *workflowExecutionPersistenceClient) DeleteReplicationTasksFromDLQ(
+	ctx context.Context,
+	request *DeleteReplicationTasksFromDLQRequest,
+) error {
+	p.metricClient.IncCounter(metrics.PersistenceDeleteReplicationTasksFromDLQScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceDeleteReplicationTasksFromDLQScope, metrics.PersistenceLatency)
+	err := p.persistence.DeleteReplicationTasksFromDLQ(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceDeleteReplicationTasksFromDLQScope, err)
+	}
+
+	return err
+}
+
 func (p *workflowExecutionPersistenceClient) CreateFailoverMarkerTasks(
 	ctx context.Context,
 	request *CreateFailoverMarkersRequest,
@@ -698,17 +991,36 @@ func (p *workflowExecutionPersistenceClient) RangeCompleteTimerTask(
 	return err
 }
 
+func (p *workflowExecutionPersistenceClient) CompleteTimerTasksForDomain(
+	ctx context.Context,
+	request *CompleteTimerTasksForDomainRequest,
+) (int, error) {
+	p.metricClient.IncCounter(metrics.PersistenceCompleteTimerTasksForDomainScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceCompleteTimerTasksForDomainScope, metrics.PersistenceLatency)
+	result, err := p.persistence.CompleteTimerTasksForDomain(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceCompleteTimerTasksForDomainScope, err)
+	}
+
+	return result, err
+}
+
 func (p *workflowExecutionPersistenceClient) updateErrorMetric(scope int, err error) {
 	switch err.(type) {
 	case *WorkflowExecutionAlreadyStartedError:
 		p.metricClient.IncCounter(scope, metrics.PersistenceErrExecutionAlreadyStartedCounter)
-	case *types.EntityNotExistsError:
+	case *types.EntityNotExistsError, *WorkflowExecutionNotExistsError:
 		p.metricClient.IncCounter(scope, metrics.PersistenceErrEntityNotExistsCounter)
 	case *ShardOwnershipLostError:
 		p.metricClient.IncCounter(scope, metrics.PersistenceErrShardOwnershipLostCounter)
+	case *ShardRangeIDMismatchError:
+		p.metricClient.IncCounter(scope, metrics.PersistenceErrShardRangeIDMismatchCounter)
 	case *ConditionFailedError:
 		p.metricClient.IncCounter(scope, metrics.PersistenceErrConditionFailedCounter)
-	case *CurrentWorkflowConditionFailedError:
+	case *CurrentWorkflowConditionFailedError, *CurrentWorkflowStillRunningError:
 		p.metricClient.IncCounter(scope, metrics.PersistenceErrCurrentWorkflowConditionFailedCounter)
 	case *TimeoutError:
 		p.metricClient.IncCounter(scope, metrics.PersistenceErrTimeoutCounter)
@@ -727,6 +1039,11 @@ func (p *workflowExecutionPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence, closing is not measured
+func (p *workflowExecutionPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *taskPersistenceClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -838,6 +1155,23 @@ func (p *taskPersistenceClient) ListTaskList(
 	return response, err
 }
 
+func (p *taskPersistenceClient) ListTaskListByDomain(
+	ctx context.Context,
+	request *ListTaskListByDomainRequest,
+) (*ListTaskListResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceListTaskListByDomainScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceListTaskListByDomainScope, metrics.PersistenceLatency)
+	response, err := p.persistence.ListTaskListByDomain(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceListTaskListByDomainScope, err)
+	}
+
+	return response, err
+}
+
 func (p *taskPersistenceClient) DeleteTaskList(
 	ctx context.Context,
 	request *DeleteTaskListRequest,
@@ -890,6 +1224,11 @@ func (p *taskPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence, closing is not measured
+func (p *taskPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *metadataPersistenceClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -928,6 +1267,23 @@ func (p *metadataPersistenceClient) GetDomain(
 	return response, err
 }
 
+func (p *metadataPersistenceClient) GetDomainConfigVersion(
+	ctx context.Context,
+	request *GetDomainConfigVersionRequest,
+) (*GetDomainConfigVersionResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceGetDomainConfigVersionScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceGetDomainConfigVersionScope, metrics.PersistenceLatency)
+	response, err := p.persistence.GetDomainConfigVersion(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceGetDomainConfigVersionScope, err)
+	}
+
+	return response, err
+}
+
 func (p *metadataPersistenceClient) UpdateDomain(
 	ctx context.Context,
 	request *UpdateDomainRequest,
@@ -945,6 +1301,23 @@ func (p *metadataPersistenceClient) UpdateDomain(
 	return err
 }
 
+func (p *metadataPersistenceClient) MarkDomainForDeletion(
+	ctx context.Context,
+	request *MarkDomainForDeletionRequest,
+) error {
+	p.metricClient.IncCounter(metrics.PersistenceMarkDomainForDeletionScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceMarkDomainForDeletionScope, metrics.PersistenceLatency)
+	err := p.persistence.MarkDomainForDeletion(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceMarkDomainForDeletionScope, err)
+	}
+
+	return err
+}
+
 func (p *metadataPersistenceClient) DeleteDomain(
 	ctx context.Context,
 	request *DeleteDomainRequest,
@@ -1016,6 +1389,11 @@ func (p *metadataPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence, closing is not measured
+func (p *metadataPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *metadataPersistenceClient) updateErrorMetric(scope int, err error) {
 	switch err.(type) {
 	case *types.DomainAlreadyExistsError:
@@ -1316,6 +1694,11 @@ func (p *visibilityPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence, closing is not measured
+func (p *visibilityPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *historyPersistenceClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -1324,6 +1707,11 @@ func (p *historyPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence, closing is not measured
+func (p *historyPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 // AppendHistoryNodes add(or override) a node to a history branch
 func (p *historyPersistenceClient) AppendHistoryNodes(
 	ctx context.Context,
@@ -1384,6 +1772,39 @@ func (p *historyPersistenceClient) ReadRawHistoryBranch(
 	return response, err
 }
 
+// ReadHistoryBranchWithBlobs returns history node data for a branch along with the blobs it was decoded from
+func (p *historyPersistenceClient) ReadHistoryBranchWithBlobs(
+	ctx context.Context,
+	request *ReadHistoryBranchRequest,
+) (*ReadHistoryBranchWithBlobsResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceReadHistoryBranchWithBlobsScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceReadHistoryBranchWithBlobsScope, metrics.PersistenceLatency)
+	response, err := p.persistence.ReadHistoryBranchWithBlobs(ctx, request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceReadHistoryBranchWithBlobsScope, err)
+	}
+
+	return response, err
+}
+
+// ReadHistoryNode returns the single batch of events starting at the node ID
+func (p *historyPersistenceClient) ReadHistoryNode(
+	ctx context.Context,
+	request *ReadHistoryNodeRequest,
+) (*ReadHistoryNodeResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceReadHistoryNodeScope, metrics.PersistenceRequests)
+	sw := p.metricClient.StartTimer(metrics.PersistenceReadHistoryNodeScope, metrics.PersistenceLatency)
+	response, err := p.persistence.ReadHistoryNode(ctx, request)
+	sw.Stop()
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceReadHistoryNodeScope, err)
+	}
+	return response, err
+}
+
 // ForkHistoryBranch forks a new branch from a old branch
 func (p *historyPersistenceClient) ForkHistoryBranch(
 	ctx context.Context,
@@ -1479,6 +1900,41 @@ func (p *queuePersistenceClient) EnqueueMessage(
 	return err
 }
 
+func (p *queuePersistenceClient) EnqueueMessageWithID(
+	ctx context.Context,
+	message []byte,
+	messageID int64,
+) error {
+	p.metricClient.IncCounter(metrics.PersistenceEnqueueMessageWithIDScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceEnqueueMessageWithIDScope, metrics.PersistenceLatency)
+	err := p.persistence.EnqueueMessageWithID(ctx, message, messageID)
+	sw.Stop()
+
+	if err != nil {
+		p.metricClient.IncCounter(metrics.PersistenceEnqueueMessageWithIDScope, metrics.PersistenceFailures)
+	}
+
+	return err
+}
+
+func (p *queuePersistenceClient) EnqueueMessages(
+	ctx context.Context,
+	messages [][]byte,
+) ([]int64, error) {
+	p.metricClient.IncCounter(metrics.PersistenceEnqueueMessagesScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceEnqueueMessagesScope, metrics.PersistenceLatency)
+	result, err := p.persistence.EnqueueMessages(ctx, messages)
+	sw.Stop()
+
+	if err != nil {
+		p.metricClient.IncCounter(metrics.PersistenceEnqueueMessagesScope, metrics.PersistenceFailures)
+	}
+
+	return result, err
+}
+
 func (p *queuePersistenceClient) ReadMessages(
 	ctx context.Context,
 	lastMessageID int64,
@@ -1673,3 +2129,8 @@ func (p *queuePersistenceClient) GetDLQSize(
 func (p *queuePersistenceClient) Close() {
 	p.persistence.Close()
 }
+
+// CloseWithContext closes the wrapped persistence, closing is not measured
+func (p *queuePersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
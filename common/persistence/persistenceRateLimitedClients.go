@@ -41,24 +41,32 @@ type (
 	}
 
 	workflowExecutionRateLimitedPersistenceClient struct {
+		executionManagerCompositeOperations
+
 		rateLimiter quotas.Limiter
 		persistence ExecutionManager
 		logger      log.Logger
 	}
 
 	taskRateLimitedPersistenceClient struct {
+		taskManagerCompositeOperations
+
 		rateLimiter quotas.Limiter
 		persistence TaskManager
 		logger      log.Logger
 	}
 
 	historyRateLimitedPersistenceClient struct {
+		historyManagerCompositeOperations
+
 		rateLimiter quotas.Limiter
 		persistence HistoryManager
 		logger      log.Logger
 	}
 
 	metadataRateLimitedPersistenceClient struct {
+		metadataManagerCompositeOperations
+
 		rateLimiter quotas.Limiter
 		persistence MetadataManager
 		logger      log.Logger
@@ -104,11 +112,13 @@ func NewWorkflowExecutionPersistenceRateLimitedClient(
 	rateLimiter quotas.Limiter,
 	logger log.Logger,
 ) ExecutionManager {
-	return &workflowExecutionRateLimitedPersistenceClient{
+	client := &workflowExecutionRateLimitedPersistenceClient{
 		persistence: persistence,
 		rateLimiter: rateLimiter,
 		logger:      logger,
 	}
+	client.executionManagerCompositeOperations = executionManagerCompositeOperations{manager: client}
+	return client
 }
 
 // NewTaskPersistenceRateLimitedClient creates a client to manage tasks
@@ -117,11 +127,13 @@ func NewTaskPersistenceRateLimitedClient(
 	rateLimiter quotas.Limiter,
 	logger log.Logger,
 ) TaskManager {
-	return &taskRateLimitedPersistenceClient{
+	client := &taskRateLimitedPersistenceClient{
 		persistence: persistence,
 		rateLimiter: rateLimiter,
 		logger:      logger,
 	}
+	client.taskManagerCompositeOperations = taskManagerCompositeOperations{manager: client}
+	return client
 }
 
 // NewHistoryPersistenceRateLimitedClient creates a HistoryManager client to manage workflow execution history
@@ -130,11 +142,13 @@ func NewHistoryPersistenceRateLimitedClient(
 	rateLimiter quotas.Limiter,
 	logger log.Logger,
 ) HistoryManager {
-	return &historyRateLimitedPersistenceClient{
+	client := &historyRateLimitedPersistenceClient{
 		persistence: persistence,
 		rateLimiter: rateLimiter,
 		logger:      logger,
 	}
+	client.historyManagerCompositeOperations = historyManagerCompositeOperations{manager: client}
+	return client
 }
 
 // NewMetadataPersistenceRateLimitedClient creates a MetadataManager client to manage metadata
@@ -143,11 +157,13 @@ func NewMetadataPersistenceRateLimitedClient(
 	rateLimiter quotas.Limiter,
 	logger log.Logger,
 ) MetadataManager {
-	return &metadataRateLimitedPersistenceClient{
+	client := &metadataRateLimitedPersistenceClient{
 		persistence: persistence,
 		rateLimiter: rateLimiter,
 		logger:      logger,
 	}
+	client.metadataManagerCompositeOperations = metadataManagerCompositeOperations{manager: client}
+	return client
 }
 
 // NewVisibilityPersistenceRateLimitedClient creates a client to manage visibility
@@ -204,6 +220,30 @@ func (p *shardRateLimitedPersistenceClient) GetShard(
 	return response, err
 }
 
+func (p *shardRateLimitedPersistenceClient) GetShards(
+	ctx context.Context,
+	request *GetShardsRequest,
+) (*GetShardsResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	response, err := p.persistence.GetShards(ctx, request)
+	return response, err
+}
+
+func (p *shardRateLimitedPersistenceClient) ListShards(
+	ctx context.Context,
+	request *ListShardsRequest,
+) (*ListShardsResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	response, err := p.persistence.ListShards(ctx, request)
+	return response, err
+}
+
 func (p *shardRateLimitedPersistenceClient) UpdateShard(
 	ctx context.Context,
 	request *UpdateShardRequest,
@@ -220,6 +260,11 @@ func (p *shardRateLimitedPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence without taking a token from the rate limiter
+func (p *shardRateLimitedPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *workflowExecutionRateLimitedPersistenceClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -252,6 +297,18 @@ func (p *workflowExecutionRateLimitedPersistenceClient) GetWorkflowExecution(
 	return response, err
 }
 
+func (p *workflowExecutionRateLimitedPersistenceClient) GetWorkflowExecutionForUpdate(
+	ctx context.Context,
+	request *GetWorkflowExecutionForUpdateRequest,
+) (*GetWorkflowExecutionForUpdateResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	response, err := p.persistence.GetWorkflowExecutionForUpdate(ctx, request)
+	return response, err
+}
+
 func (p *workflowExecutionRateLimitedPersistenceClient) UpdateWorkflowExecution(
 	ctx context.Context,
 	request *UpdateWorkflowExecutionRequest,
@@ -264,6 +321,18 @@ func (p *workflowExecutionRateLimitedPersistenceClient) UpdateWorkflowExecution(
 	return resp, err
 }
 
+func (p *workflowExecutionRateLimitedPersistenceClient) FlushBufferedEvents(
+	ctx context.Context,
+	request *FlushBufferedEventsRequest,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	err := p.persistence.FlushBufferedEvents(ctx, request)
+	return err
+}
+
 func (p *workflowExecutionRateLimitedPersistenceClient) ConflictResolveWorkflowExecution(
 	ctx context.Context,
 	request *ConflictResolveWorkflowExecutionRequest,
@@ -312,6 +381,18 @@ func (p *workflowExecutionRateLimitedPersistenceClient) DeleteCurrentWorkflowExe
 	return err
 }
 
+func (p *workflowExecutionRateLimitedPersistenceClient) DeleteWorkflowExecutions(
+	ctx context.Context,
+	request *DeleteWorkflowExecutionsRequest,
+) (*DeleteWorkflowExecutionsResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	response, err := p.persistence.DeleteWorkflowExecutions(ctx, request)
+	return response, err
+}
+
 func (p *workflowExecutionRateLimitedPersistenceClient) GetCurrentExecution(
 	ctx context.Context,
 	request *GetCurrentExecutionRequest,
@@ -324,6 +405,18 @@ func (p *workflowExecutionRateLimitedPersistenceClient) GetCurrentExecution(
 	return response, err
 }
 
+func (p *workflowExecutionRateLimitedPersistenceClient) GetCurrentRunID(
+	ctx context.Context,
+	request *GetCurrentRunIDRequest,
+) (*GetCurrentRunIDResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	response, err := p.persistence.GetCurrentRunID(ctx, request)
+	return response, err
+}
+
 func (p *workflowExecutionRateLimitedPersistenceClient) ListCurrentExecutions(
 	ctx context.Context,
 	request *ListCurrentExecutionsRequest,
@@ -348,6 +441,30 @@ func (p *workflowExecutionRateLimitedPersistenceClient) IsWorkflowExecutionExist
 	return response, err
 }
 
+func (p *workflowExecutionRateLimitedPersistenceClient) WorkflowExists(
+	ctx context.Context,
+	request *WorkflowExistsRequest,
+) (*WorkflowExistsResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	response, err := p.persistence.WorkflowExists(ctx, request)
+	return response, err
+}
+
+func (p *workflowExecutionRateLimitedPersistenceClient) CheckCurrentExecution(
+	ctx context.Context,
+	request *CheckCurrentExecutionRequest,
+) (*CheckCurrentExecutionResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	response, err := p.persistence.CheckCurrentExecution(ctx, request)
+	return response, err
+}
+
 func (p *workflowExecutionRateLimitedPersistenceClient) ListConcreteExecutions(
 	ctx context.Context,
 	request *ListConcreteExecutionsRequest,
@@ -360,6 +477,18 @@ func (p *workflowExecutionRateLimitedPersistenceClient) ListConcreteExecutions(
 	return response, err
 }
 
+func (p *workflowExecutionRateLimitedPersistenceClient) CountWorkflowExecutions(
+	ctx context.Context,
+	request *CountExecutionsRequest,
+) (*CountExecutionsResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	response, err := p.persistence.CountWorkflowExecutions(ctx, request)
+	return response, err
+}
+
 func (p *workflowExecutionRateLimitedPersistenceClient) GetTransferTasks(
 	ctx context.Context,
 	request *GetTransferTasksRequest,
@@ -384,6 +513,18 @@ func (p *workflowExecutionRateLimitedPersistenceClient) GetReplicationTasks(
 	return response, err
 }
 
+func (p *workflowExecutionRateLimitedPersistenceClient) GetFailoverMarkerTasks(
+	ctx context.Context,
+	request *GetFailoverMarkerTasksRequest,
+) (*GetFailoverMarkerTasksResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	response, err := p.persistence.GetFailoverMarkerTasks(ctx, request)
+	return response, err
+}
+
 func (p *workflowExecutionRateLimitedPersistenceClient) CompleteTransferTask(
 	ctx context.Context,
 	request *CompleteTransferTaskRequest,
@@ -408,6 +549,54 @@ func (p *workflowExecutionRateLimitedPersistenceClient) RangeCompleteTransferTas
 	return err
 }
 
+func (p *workflowExecutionRateLimitedPersistenceClient) CompleteTransferTasks(
+	ctx context.Context,
+	request *CompleteTransferTasksRequest,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	err := p.persistence.CompleteTransferTasks(ctx, request)
+	return err
+}
+
+func (p *workflowExecutionRateLimitedPersistenceClient) GetCrossClusterTasks(
+	ctx context.Context,
+	request *GetCrossClusterTasksRequest,
+) (*GetCrossClusterTasksResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	response, err := p.persistence.GetCrossClusterTasks(ctx, request)
+	return response, err
+}
+
+func (p *workflowExecutionRateLimitedPersistenceClient) CompleteCrossClusterTask(
+	ctx context.Context,
+	request *CompleteCrossClusterTaskRequest,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	err := p.persistence.CompleteCrossClusterTask(ctx, request)
+	return err
+}
+
+func (p *workflowExecutionRateLimitedPersistenceClient) RangeCompleteCrossClusterTask(
+	ctx context.Context,
+	request *RangeCompleteCrossClusterTaskRequest,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	err := p.persistence.RangeCompleteCrossClusterTask(ctx, request)
+	return err
+}
+
 func (p *workflowExecutionRateLimitedPersistenceClient) CompleteReplicationTask(
 	ctx context.Context,
 	request *CompleteReplicationTaskRequest,
@@ -443,6 +632,18 @@ func (p *workflowExecutionRateLimitedPersistenceClient) PutReplicationTaskToDLQ(
 	return p.persistence.PutReplicationTaskToDLQ(ctx, request)
 }
 
+func (p *workflowExecutionRateLimitedPersistenceClient) PutReplicationTasksToDLQ(
+	ctx context.Context,
+	request *PutReplicationTasksToDLQRequest,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	err := p.persistence.PutReplicationTasksToDLQ(ctx, request)
+	return err
+}
+
 func (p *workflowExecutionRateLimitedPersistenceClient) GetReplicationTasksFromDLQ(
 	ctx context.Context,
 	request *GetReplicationTasksFromDLQRequest,
@@ -487,6 +688,18 @@ func (p *workflowExecutionRateLimitedPersistenceClient) RangeDeleteReplicationTa
 	return p.persistence.RangeDeleteReplicationTaskFromDLQ(ctx, request)
 }
 
+func (p *workflowExecutionRateLimitedPersistenceClient) DeleteReplicationTasksFromDLQ(
+	ctx context.Context,
+	request *DeleteReplicationTasksFromDLQRequest,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	err := p.persistence.DeleteReplicationTasksFromDLQ(ctx, request)
+	return err
+}
+
 func (p *workflowExecutionRateLimitedPersistenceClient) CreateFailoverMarkerTasks(
 	ctx context.Context,
 	request *CreateFailoverMarkersRequest,
@@ -535,10 +748,26 @@ func (p *workflowExecutionRateLimitedPersistenceClient) RangeCompleteTimerTask(
 	return err
 }
 
+func (p *workflowExecutionRateLimitedPersistenceClient) CompleteTimerTasksForDomain(
+	ctx context.Context,
+	request *CompleteTimerTasksForDomainRequest,
+) (int, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return 0, ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.CompleteTimerTasksForDomain(ctx, request)
+}
+
 func (p *workflowExecutionRateLimitedPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence without taking a token from the rate limiter
+func (p *workflowExecutionRateLimitedPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *taskRateLimitedPersistenceClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -630,6 +859,18 @@ func (p *taskRateLimitedPersistenceClient) ListTaskList(
 	return p.persistence.ListTaskList(ctx, request)
 }
 
+func (p *taskRateLimitedPersistenceClient) ListTaskListByDomain(
+	ctx context.Context,
+	request *ListTaskListByDomainRequest,
+) (*ListTaskListResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	response, err := p.persistence.ListTaskListByDomain(ctx, request)
+	return response, err
+}
+
 func (p *taskRateLimitedPersistenceClient) DeleteTaskList(
 	ctx context.Context,
 	request *DeleteTaskListRequest,
@@ -644,6 +885,11 @@ func (p *taskRateLimitedPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence without taking a token from the rate limiter
+func (p *taskRateLimitedPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *metadataRateLimitedPersistenceClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -672,6 +918,18 @@ func (p *metadataRateLimitedPersistenceClient) GetDomain(
 	return response, err
 }
 
+func (p *metadataRateLimitedPersistenceClient) GetDomainConfigVersion(
+	ctx context.Context,
+	request *GetDomainConfigVersionRequest,
+) (*GetDomainConfigVersionResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	response, err := p.persistence.GetDomainConfigVersion(ctx, request)
+	return response, err
+}
+
 func (p *metadataRateLimitedPersistenceClient) UpdateDomain(
 	ctx context.Context,
 	request *UpdateDomainRequest,
@@ -684,6 +942,18 @@ func (p *metadataRateLimitedPersistenceClient) UpdateDomain(
 	return err
 }
 
+func (p *metadataRateLimitedPersistenceClient) MarkDomainForDeletion(
+	ctx context.Context,
+	request *MarkDomainForDeletionRequest,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	err := p.persistence.MarkDomainForDeletion(ctx, request)
+	return err
+}
+
 func (p *metadataRateLimitedPersistenceClient) DeleteDomain(
 	ctx context.Context,
 	request *DeleteDomainRequest,
@@ -735,6 +1005,11 @@ func (p *metadataRateLimitedPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence without taking a token from the rate limiter
+func (p *metadataRateLimitedPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *visibilityRateLimitedPersistenceClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -915,6 +1190,11 @@ func (p *visibilityRateLimitedPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence without taking a token from the rate limiter
+func (p *visibilityRateLimitedPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *historyRateLimitedPersistenceClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -923,6 +1203,11 @@ func (p *historyRateLimitedPersistenceClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence without taking a token from the rate limiter
+func (p *historyRateLimitedPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 // AppendHistoryNodes add(or override) a node to a history branch
 func (p *historyRateLimitedPersistenceClient) AppendHistoryNodes(
 	ctx context.Context,
@@ -970,6 +1255,30 @@ func (p *historyRateLimitedPersistenceClient) ReadRawHistoryBranch(
 	return response, err
 }
 
+func (p *historyRateLimitedPersistenceClient) ReadHistoryBranchWithBlobs(
+	ctx context.Context,
+	request *ReadHistoryBranchRequest,
+) (*ReadHistoryBranchWithBlobsResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	response, err := p.persistence.ReadHistoryBranchWithBlobs(ctx, request)
+	return response, err
+}
+
+// ReadHistoryNode returns the single batch of events starting at the node ID
+func (p *historyRateLimitedPersistenceClient) ReadHistoryNode(
+	ctx context.Context,
+	request *ReadHistoryNodeRequest,
+) (*ReadHistoryNodeResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+	response, err := p.persistence.ReadHistoryNode(ctx, request)
+	return response, err
+}
+
 // ForkHistoryBranch forks a new branch from a old branch
 func (p *historyRateLimitedPersistenceClient) ForkHistoryBranch(
 	ctx context.Context,
@@ -1028,6 +1337,29 @@ func (p *queueRateLimitedPersistenceClient) EnqueueMessage(
 	return p.persistence.EnqueueMessage(ctx, message)
 }
 
+func (p *queueRateLimitedPersistenceClient) EnqueueMessageWithID(
+	ctx context.Context,
+	message []byte,
+	messageID int64,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.EnqueueMessageWithID(ctx, message, messageID)
+}
+
+func (p *queueRateLimitedPersistenceClient) EnqueueMessages(
+	ctx context.Context,
+	messages [][]byte,
+) ([]int64, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.EnqueueMessages(ctx, messages)
+}
+
 func (p *queueRateLimitedPersistenceClient) ReadMessages(
 	ctx context.Context,
 	lastMessageID int64,
@@ -1156,3 +1488,8 @@ func (p *queueRateLimitedPersistenceClient) DeleteMessageFromDLQ(
 func (p *queueRateLimitedPersistenceClient) Close() {
 	p.persistence.Close()
 }
+
+// CloseWithContext closes the wrapped persistence without taking a token from the rate limiter
+func (p *queueRateLimitedPersistenceClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
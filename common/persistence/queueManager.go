@@ -47,10 +47,26 @@ func (q *queueManager) Close() {
 	q.persistence.Close()
 }
 
+// CloseWithContext closes the queue within the deadline of ctx
+func (q *queueManager) CloseWithContext(ctx context.Context) error {
+	return CloseWithContext(ctx, q.persistence)
+}
+
 func (q *queueManager) EnqueueMessage(ctx context.Context, messagePayload []byte) error {
 	return q.persistence.EnqueueMessage(ctx, messagePayload)
 }
 
+func (q *queueManager) EnqueueMessageWithID(ctx context.Context, messagePayload []byte, messageID int64) error {
+	return q.persistence.EnqueueMessageWithID(ctx, messagePayload, messageID)
+}
+
+func (q *queueManager) EnqueueMessages(ctx context.Context, messagePayloads [][]byte) ([]int64, error) {
+	if len(messagePayloads) == 0 {
+		return nil, nil
+	}
+	return q.persistence.EnqueueMessages(ctx, messagePayloads)
+}
+
 func (q *queueManager) ReadMessages(ctx context.Context, lastMessageID int64, maxCount int) ([]*QueueMessage, error) {
 	resp, err := q.persistence.ReadMessages(ctx, lastMessageID, maxCount)
 	if err != nil {
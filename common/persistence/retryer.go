@@ -24,6 +24,7 @@ package persistence
 
 import (
 	"context"
+	"time"
 
 	"github.com/uber/cadence/common/backoff"
 )
@@ -64,6 +65,38 @@ func NewPersistenceRetryer(
 	}
 }
 
+// RetryTransient calls op until it succeeds or fails with an error which is not transient,
+// retries are bounded by the policy and stop once ctx is done. The last error returned by op is returned.
+func RetryTransient(ctx context.Context, op func() error, policy RetryPolicy) error {
+	backoffPolicy := backoff.NewExponentialRetryPolicy(policy.InitialInterval)
+	backoffPolicy.SetBackoffCoefficient(policy.BackoffCoefficient)
+	backoffPolicy.SetMaximumInterval(policy.MaxInterval)
+	backoffPolicy.SetExpirationInterval(backoff.NoInterval)
+	retrier := backoff.NewRetrier(backoffPolicy, backoff.SystemClock)
+
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || !IsTransientError(err) {
+			return err
+		}
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return err
+		}
+
+		next := retrier.NextBackOff()
+		if next < 0 {
+			return err
+		}
+		timer := time.NewTimer(next)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
 // ListConcreteExecutions retries ListConcreteExecutions
 func (pr *persistenceRetryer) ListConcreteExecutions(
 	ctx context.Context,
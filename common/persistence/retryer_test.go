@@ -0,0 +1,72 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func TestRetryTransient(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:        3,
+		InitialInterval:    time.Millisecond,
+		BackoffCoefficient: 2,
+		MaxInterval:        5 * time.Millisecond,
+	}
+	transientErr := &types.ServiceBusyError{}
+
+	newOp := func(errs ...error) (func() error, *int) {
+		attempts := 0
+		return func() error {
+			attempts++
+			if attempts <= len(errs) {
+				return errs[attempts-1]
+			}
+			return nil
+		}, &attempts
+	}
+
+	op, attempts := newOp(transientErr, transientErr)
+	assert.NoError(t, RetryTransient(context.Background(), op, policy))
+	assert.Equal(t, 3, *attempts)
+
+	op, attempts = newOp(transientErr, transientErr, transientErr)
+	assert.Equal(t, transientErr, RetryTransient(context.Background(), op, policy))
+	assert.Equal(t, 3, *attempts)
+
+	nonTransientErr := errors.New("some error")
+	op, attempts = newOp(transientErr, nonTransientErr)
+	assert.Equal(t, nonTransientErr, RetryTransient(context.Background(), op, policy))
+	assert.Equal(t, 2, *attempts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	op, attempts = newOp(transientErr, transientErr)
+	assert.Equal(t, transientErr, RetryTransient(ctx, op, policy))
+	assert.Equal(t, 1, *attempts)
+}
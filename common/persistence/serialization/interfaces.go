@@ -80,6 +80,7 @@ type (
 		FailoverEndTimestamp        *time.Time
 		PreviousFailoverVersion     *int64
 		LastUpdatedTimestamp        *time.Time
+		DeletionTimestamp           *time.Time
 	}
 
 	// HistoryBranchRange blob in a serialization agnostic format
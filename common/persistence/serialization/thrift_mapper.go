@@ -123,6 +123,7 @@ func domainInfoToThrift(info *DomainInfo) *sqlblobs.DomainInfo {
 		RetentionDays:               durationToDays(info.Retention),
 		FailoverEndTime:             unixNanoPtr(info.FailoverEndTimestamp),
 		LastUpdatedTime:             unixNanoPtr(info.LastUpdatedTimestamp),
+		DeletionTime:                unixNanoPtr(info.DeletionTimestamp),
 	}
 }
 
@@ -155,6 +156,7 @@ func domainInfoFromThrift(info *sqlblobs.DomainInfo) *DomainInfo {
 		Retention:                   daysToDuration(info.RetentionDays),
 		FailoverEndTimestamp:        timePtr(info.FailoverEndTime),
 		LastUpdatedTimestamp:        timePtr(info.LastUpdatedTime),
+		DeletionTimestamp:           timePtr(info.DeletionTime),
 	}
 }
 
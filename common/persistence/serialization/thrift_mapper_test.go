@@ -105,6 +105,7 @@ func TestDomainInfo(t *testing.T) {
 		FailoverEndTimestamp:        common.TimePtr(time.Now()),
 		PreviousFailoverVersion:     common.Int64Ptr(int64(rand.Intn(1000))),
 		LastUpdatedTimestamp:        common.TimePtr(time.Now()),
+		DeletionTimestamp:           common.TimePtr(time.Now()),
 	}
 	actual := domainInfoFromThrift(domainInfoToThrift(expected))
 	assert.Equal(t, expected.Name, actual.Name)
@@ -130,6 +131,7 @@ func TestDomainInfo(t *testing.T) {
 	assert.Equal(t, expected.FailoverEndTimestamp.Sub(*actual.FailoverEndTimestamp), time.Duration(0))
 	assert.Equal(t, expected.PreviousFailoverVersion, actual.PreviousFailoverVersion)
 	assert.Equal(t, expected.LastUpdatedTimestamp.Sub(*actual.LastUpdatedTimestamp), time.Duration(0))
+	assert.Equal(t, expected.DeletionTimestamp.Sub(*actual.DeletionTimestamp), time.Duration(0))
 }
 
 func TestHistoryTreeInfo(t *testing.T) {
@@ -44,6 +44,20 @@ func MustParseUUID(s string) UUID {
 	return u[:]
 }
 
+// ParseUUID returns a UUID parsed from the given string representation
+// returns nil if the input is empty string
+// returns an error if the given input is malformed
+func ParseUUID(s string) (UUID, error) {
+	if s == "" {
+		return nil, nil
+	}
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return u[:], nil
+}
+
 // MustParsePtrUUID returns a UUID parsed from the given string representation
 // returns nil if the input is empty string
 // panics if the given input is malformed
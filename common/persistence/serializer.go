@@ -90,6 +90,23 @@ type (
 	}
 )
 
+// DefaultEncodingType is the encoding the managers write with when a request does not specify one.
+// It is only meant to be changed during startup, before any write, e.g. to roll out a new encoding.
+var DefaultEncodingType = common.EncodingTypeThriftRW
+
+// ResolveEncoding returns the encoding used to write a request, so that all write paths agree on it
+// unless it is explicitly overridden. The fallback order is:
+//  1. the requested encoding, if it is set
+//  2. DefaultEncodingType
+func ResolveEncoding(requested common.EncodingType) common.EncodingType {
+	switch requested {
+	case common.EncodingTypeEmpty, common.EncodingTypeUnknown:
+		return DefaultEncodingType
+	default:
+		return requested
+	}
+}
+
 // NewPayloadSerializer returns a PayloadSerializer
 func NewPayloadSerializer() PayloadSerializer {
 	return &serializerImpl{
@@ -479,3 +479,10 @@ func (s *cadenceSerializerSuite) TestSerializer() {
 	succ := common.AwaitWaitGroup(&doneWG, 10*time.Second)
 	s.True(succ, "test timed out")
 }
+
+func (s *cadenceSerializerSuite) TestResolveEncoding() {
+	s.Equal(DefaultEncodingType, ResolveEncoding(common.EncodingTypeEmpty))
+	s.Equal(DefaultEncodingType, ResolveEncoding(common.EncodingTypeUnknown))
+	s.Equal(common.EncodingTypeJSON, ResolveEncoding(common.EncodingTypeJSON))
+	s.Equal(common.EncodingTypeThriftRW, ResolveEncoding(common.EncodingTypeThriftRW))
+}
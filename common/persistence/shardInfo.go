@@ -0,0 +1,188 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/types"
+)
+
+// GetTransferProcessingQueueStates returns the transfer processing queue states,
+// or an empty ProcessingQueueStates if the shard was written without them
+func (s *ShardInfo) GetTransferProcessingQueueStates() *types.ProcessingQueueStates {
+	if s == nil || s.TransferProcessingQueueStates == nil {
+		return &types.ProcessingQueueStates{}
+	}
+	return s.TransferProcessingQueueStates
+}
+
+// GetTimerProcessingQueueStates returns the timer processing queue states,
+// or an empty ProcessingQueueStates if the shard was written without them
+func (s *ShardInfo) GetTimerProcessingQueueStates() *types.ProcessingQueueStates {
+	if s == nil || s.TimerProcessingQueueStates == nil {
+		return &types.ProcessingQueueStates{}
+	}
+	return s.TimerProcessingQueueStates
+}
+
+// IsThrashing returns true if the shard has been stolen at least threshold times since it was last renewed,
+// a non-positive threshold disables the check
+func (s *ShardInfo) IsThrashing(threshold int) bool {
+	if s == nil || threshold <= 0 {
+		return false
+	}
+	return s.StolenSinceRenew >= threshold
+}
+
+// Copy returns a deep copy of the shard info, so the copy can be mutated
+// without affecting the original
+func (s *ShardInfo) Copy() *ShardInfo {
+	if s == nil {
+		return nil
+	}
+
+	transferFailoverLevels := make(map[string]TransferFailoverLevel, len(s.TransferFailoverLevels))
+	for k, v := range s.TransferFailoverLevels {
+		v.DomainIDs = copyDomainIDSet(v.DomainIDs)
+		transferFailoverLevels[k] = v
+	}
+	timerFailoverLevels := make(map[string]TimerFailoverLevel, len(s.TimerFailoverLevels))
+	for k, v := range s.TimerFailoverLevels {
+		v.DomainIDs = copyDomainIDSet(v.DomainIDs)
+		timerFailoverLevels[k] = v
+	}
+	clusterTransferAckLevel := make(map[string]int64, len(s.ClusterTransferAckLevel))
+	for k, v := range s.ClusterTransferAckLevel {
+		clusterTransferAckLevel[k] = v
+	}
+	clusterTimerAckLevel := make(map[string]time.Time, len(s.ClusterTimerAckLevel))
+	for k, v := range s.ClusterTimerAckLevel {
+		clusterTimerAckLevel[k] = v
+	}
+	clusterReplicationLevel := make(map[string]int64, len(s.ClusterReplicationLevel))
+	for k, v := range s.ClusterReplicationLevel {
+		clusterReplicationLevel[k] = v
+	}
+	replicationDLQAckLevel := make(map[string]int64, len(s.ReplicationDLQAckLevel))
+	for k, v := range s.ReplicationDLQAckLevel {
+		replicationDLQAckLevel[k] = v
+	}
+	var pendingFailoverMarkers []*types.FailoverMarkerAttributes
+	if s.PendingFailoverMarkers != nil {
+		pendingFailoverMarkers = make([]*types.FailoverMarkerAttributes, 0, len(s.PendingFailoverMarkers))
+		for _, marker := range s.PendingFailoverMarkers {
+			pendingFailoverMarkers = append(pendingFailoverMarkers, copyFailoverMarker(marker))
+		}
+	}
+
+	return &ShardInfo{
+		ShardID:                       s.ShardID,
+		Owner:                         s.Owner,
+		RangeID:                       s.RangeID,
+		StolenSinceRenew:              s.StolenSinceRenew,
+		UpdatedAt:                     s.UpdatedAt,
+		ReplicationAckLevel:           s.ReplicationAckLevel,
+		ReplicationDLQAckLevel:        replicationDLQAckLevel,
+		TransferAckLevel:              s.TransferAckLevel,
+		TimerAckLevel:                 s.TimerAckLevel,
+		ClusterTransferAckLevel:       clusterTransferAckLevel,
+		ClusterTimerAckLevel:          clusterTimerAckLevel,
+		TransferProcessingQueueStates: copyProcessingQueueStates(s.TransferProcessingQueueStates),
+		TimerProcessingQueueStates:    copyProcessingQueueStates(s.TimerProcessingQueueStates),
+		TransferFailoverLevels:        transferFailoverLevels,
+		TimerFailoverLevels:           timerFailoverLevels,
+		ClusterReplicationLevel:       clusterReplicationLevel,
+		DomainNotificationVersion:     s.DomainNotificationVersion,
+		PendingFailoverMarkers:        pendingFailoverMarkers,
+	}
+}
+
+func copyProcessingQueueStates(states *types.ProcessingQueueStates) *types.ProcessingQueueStates {
+	if states == nil {
+		return nil
+	}
+	result := &types.ProcessingQueueStates{}
+	if states.StatesByCluster == nil {
+		return result
+	}
+	result.StatesByCluster = make(map[string][]*types.ProcessingQueueState, len(states.StatesByCluster))
+	for cluster, clusterStates := range states.StatesByCluster {
+		if clusterStates == nil {
+			result.StatesByCluster[cluster] = nil
+			continue
+		}
+		copiedStates := make([]*types.ProcessingQueueState, 0, len(clusterStates))
+		for _, state := range clusterStates {
+			copiedStates = append(copiedStates, copyProcessingQueueState(state))
+		}
+		result.StatesByCluster[cluster] = copiedStates
+	}
+	return result
+}
+
+func copyProcessingQueueState(state *types.ProcessingQueueState) *types.ProcessingQueueState {
+	if state == nil {
+		return nil
+	}
+	result := *state
+	if state.Level != nil {
+		result.Level = common.Int32Ptr(*state.Level)
+	}
+	if state.AckLevel != nil {
+		result.AckLevel = common.Int64Ptr(*state.AckLevel)
+	}
+	if state.MaxLevel != nil {
+		result.MaxLevel = common.Int64Ptr(*state.MaxLevel)
+	}
+	if state.DomainFilter != nil {
+		domainFilter := *state.DomainFilter
+		if state.DomainFilter.DomainIDs != nil {
+			domainFilter.DomainIDs = append(make([]string, 0, len(state.DomainFilter.DomainIDs)), state.DomainFilter.DomainIDs...)
+		}
+		result.DomainFilter = &domainFilter
+	}
+	return &result
+}
+
+func copyDomainIDSet(domainIDs map[string]struct{}) map[string]struct{} {
+	if domainIDs == nil {
+		return nil
+	}
+	result := make(map[string]struct{}, len(domainIDs))
+	for domainID := range domainIDs {
+		result[domainID] = struct{}{}
+	}
+	return result
+}
+
+func copyFailoverMarker(marker *types.FailoverMarkerAttributes) *types.FailoverMarkerAttributes {
+	if marker == nil {
+		return nil
+	}
+	result := *marker
+	if marker.CreationTime != nil {
+		creationTime := *marker.CreationTime
+		result.CreationTime = &creationTime
+	}
+	return &result
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/types"
+)
+
+func TestShardInfoGetProcessingQueueStates(t *testing.T) {
+	info := &ShardInfo{ShardID: 1}
+	assert.Equal(t, &types.ProcessingQueueStates{}, info.GetTransferProcessingQueueStates())
+	assert.Equal(t, &types.ProcessingQueueStates{}, info.GetTimerProcessingQueueStates())
+
+	var nilInfo *ShardInfo
+	assert.NotNil(t, nilInfo.GetTransferProcessingQueueStates())
+	assert.NotNil(t, nilInfo.GetTimerProcessingQueueStates())
+
+	states := &types.ProcessingQueueStates{
+		StatesByCluster: map[string][]*types.ProcessingQueueState{
+			"active": {{Level: common.Int32Ptr(0), AckLevel: common.Int64Ptr(10)}},
+		},
+	}
+	info.TransferProcessingQueueStates = states
+	info.TimerProcessingQueueStates = states
+	assert.Equal(t, states, info.GetTransferProcessingQueueStates())
+	assert.Equal(t, states, info.GetTimerProcessingQueueStates())
+}
+
+func TestShardInfoIsThrashing(t *testing.T) {
+	info := &ShardInfo{ShardID: 1, StolenSinceRenew: 3}
+	assert.True(t, info.IsThrashing(2))
+	assert.True(t, info.IsThrashing(3))
+	assert.False(t, info.IsThrashing(4))
+	assert.False(t, info.IsThrashing(0))
+
+	var nilInfo *ShardInfo
+	assert.False(t, nilInfo.IsThrashing(1))
+}
+
+func TestShardInfoCopy(t *testing.T) {
+	now := time.Now()
+	info := &ShardInfo{
+		ShardID:                 1,
+		Owner:                   "host",
+		RangeID:                 10,
+		ReplicationDLQAckLevel:  map[string]int64{"standby": 5},
+		ClusterTransferAckLevel: map[string]int64{"active": 20},
+		ClusterTimerAckLevel:    map[string]time.Time{"active": now},
+		ClusterReplicationLevel: map[string]int64{"standby": 30},
+		TransferProcessingQueueStates: &types.ProcessingQueueStates{
+			StatesByCluster: map[string][]*types.ProcessingQueueState{
+				"active": {{Level: common.Int32Ptr(0), AckLevel: common.Int64Ptr(20)}},
+			},
+		},
+		TransferFailoverLevels: map[string]TransferFailoverLevel{
+			"failover": {MinLevel: 1, MaxLevel: 10, DomainIDs: map[string]struct{}{"domain": {}}},
+		},
+		TimerFailoverLevels: map[string]TimerFailoverLevel{
+			"failover": {MinLevel: now, MaxLevel: now, DomainIDs: map[string]struct{}{"domain": {}}},
+		},
+		PendingFailoverMarkers: []*types.FailoverMarkerAttributes{
+			{DomainID: "domain", FailoverVersion: 2, CreationTime: common.Int64Ptr(now.UnixNano())},
+		},
+	}
+
+	copied := info.Copy()
+	assert.Equal(t, info, copied)
+
+	copied.ReplicationDLQAckLevel["standby"] = 6
+	copied.ClusterTransferAckLevel["active"] = 21
+	copied.ClusterTimerAckLevel["active"] = now.Add(time.Second)
+	copied.ClusterReplicationLevel["standby"] = 31
+	copied.TransferProcessingQueueStates.StatesByCluster["standby"] = nil
+	copied.TransferFailoverLevels["failover"].DomainIDs["other-domain"] = struct{}{}
+	copied.TimerFailoverLevels["other-failover"] = TimerFailoverLevel{}
+	copied.PendingFailoverMarkers[0].FailoverVersion = 3
+	*copied.PendingFailoverMarkers[0].CreationTime = 0
+
+	assert.Equal(t, int64(5), info.ReplicationDLQAckLevel["standby"])
+	assert.Equal(t, int64(20), info.ClusterTransferAckLevel["active"])
+	assert.Equal(t, now, info.ClusterTimerAckLevel["active"])
+	assert.Equal(t, int64(30), info.ClusterReplicationLevel["standby"])
+	assert.Len(t, info.TransferProcessingQueueStates.StatesByCluster, 1)
+	assert.Len(t, info.TransferFailoverLevels["failover"].DomainIDs, 1)
+	assert.Len(t, info.TimerFailoverLevels, 1)
+	assert.Equal(t, int64(2), info.PendingFailoverMarkers[0].FailoverVersion)
+	assert.Equal(t, now.UnixNano(), *info.PendingFailoverMarkers[0].CreationTime)
+
+	var nilInfo *ShardInfo
+	assert.Nil(t, nilInfo.Copy())
+}
+
+func TestCopyProcessingQueueStates(t *testing.T) {
+	assert.Nil(t, copyProcessingQueueStates(nil))
+	assert.Equal(t, &types.ProcessingQueueStates{}, copyProcessingQueueStates(&types.ProcessingQueueStates{}))
+
+	states := &types.ProcessingQueueStates{
+		StatesByCluster: map[string][]*types.ProcessingQueueState{
+			"active": {
+				{
+					Level:        common.Int32Ptr(0),
+					AckLevel:     common.Int64Ptr(20),
+					MaxLevel:     common.Int64Ptr(30),
+					DomainFilter: &types.DomainFilter{DomainIDs: []string{"domain"}, ReverseMatch: true},
+				},
+				{Level: common.Int32Ptr(1)},
+			},
+			"standby": {},
+			"removed": nil,
+		},
+	}
+	copied := copyProcessingQueueStates(states)
+	assert.Equal(t, states, copied)
+	assert.NotNil(t, copied.StatesByCluster["standby"])
+	assert.Nil(t, copied.StatesByCluster["removed"])
+
+	*copied.StatesByCluster["active"][0].Level = 1
+	*copied.StatesByCluster["active"][0].AckLevel = 21
+	*copied.StatesByCluster["active"][0].MaxLevel = 31
+	copied.StatesByCluster["active"][0].DomainFilter.DomainIDs[0] = "other-domain"
+	copied.StatesByCluster["active"][1] = nil
+	assert.Equal(t, int32(0), states.StatesByCluster["active"][0].GetLevel())
+	assert.Equal(t, int64(20), states.StatesByCluster["active"][0].GetAckLevel())
+	assert.Equal(t, int64(30), states.StatesByCluster["active"][0].GetMaxLevel())
+	assert.Equal(t, []string{"domain"}, states.StatesByCluster["active"][0].DomainFilter.DomainIDs)
+	assert.NotNil(t, states.StatesByCluster["active"][1])
+}
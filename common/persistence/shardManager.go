@@ -24,26 +24,54 @@ package persistence
 
 import (
 	"context"
+	"sync"
 
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	// getShardsConcurrency is the max number of concurrent reads issued by GetShards
+	getShardsConcurrency = 16
 )
 
 type (
 	shardManager struct {
-		persistence ShardStore
-		serializer  PayloadSerializer
+		persistence        ShardStore
+		serializer         PayloadSerializer
+		logger             log.Logger
+		metricsClient      metrics.Client
+		thrashingThreshold dynamicconfig.IntPropertyFn
+		// loggedStolenCounts holds the last StolenSinceRenew logged per shard,
+		// so a thrashing shard is only logged again once its count changes
+		loggedStolenCounts sync.Map
 	}
 )
 
 var _ ShardManager = (*shardManager)(nil)
 
-// NewShardManager returns a new ShardManager
+// NewShardManager returns a new ShardManager, shards updated with a StolenSinceRenew
+// at or above thrashingThreshold are logged and counted, a nil thrashingThreshold disables the check
+// and a nil metricsClient only disables the metric
 func NewShardManager(
 	persistence ShardStore,
+	logger log.Logger,
+	metricsClient metrics.Client,
+	thrashingThreshold dynamicconfig.IntPropertyFn,
 ) ShardManager {
+	if metricsClient == nil {
+		metricsClient = metrics.NewNoopMetricsClient()
+	}
 	return &shardManager{
-		persistence: persistence,
-		serializer:  NewPayloadSerializer(),
+		persistence:        persistence,
+		serializer:         NewPayloadSerializer(),
+		logger:             logger,
+		metricsClient:      metricsClient,
+		thrashingThreshold: thrashingThreshold,
 	}
 }
 
@@ -55,6 +83,11 @@ func (m *shardManager) Close() {
 	m.persistence.Close()
 }
 
+// CloseWithContext closes the shard store within the deadline of ctx
+func (m *shardManager) CloseWithContext(ctx context.Context) error {
+	return CloseWithContext(ctx, m.persistence)
+}
+
 func (m *shardManager) CreateShard(ctx context.Context, request *CreateShardRequest) error {
 	shardInfo, err := m.toInternalShardInfo(request.ShardInfo)
 	if err != nil {
@@ -84,6 +117,73 @@ func (m *shardManager) GetShard(ctx context.Context, request *GetShardRequest) (
 	return result, nil
 }
 
+// GetShards reads the given shards with bounded concurrency,
+// shards which do not exist are returned in NotFoundShardIDs instead of failing the call
+func (m *shardManager) GetShards(ctx context.Context, request *GetShardsRequest) (*GetShardsResponse, error) {
+	shardIDs := request.ShardIDs
+	internalResults := make([]*InternalGetShardResponse, len(shardIDs))
+	errs := make([]error, len(shardIDs))
+
+	indexCh := make(chan int, len(shardIDs))
+	for i := range shardIDs {
+		indexCh <- i
+	}
+	close(indexCh)
+
+	var wg sync.WaitGroup
+	for w := 0; w < getShardsConcurrency && w < len(shardIDs); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexCh {
+				internalResults[i], errs[i] = m.persistence.GetShard(ctx, &InternalGetShardRequest{
+					ShardID: shardIDs[i],
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	result := &GetShardsResponse{
+		ShardInfos: make(map[int]*ShardInfo, len(shardIDs)),
+	}
+	for i, shardID := range shardIDs {
+		if errs[i] != nil {
+			if _, ok := errs[i].(*types.EntityNotExistsError); ok {
+				result.NotFoundShardIDs = append(result.NotFoundShardIDs, shardID)
+				continue
+			}
+			return nil, errs[i]
+		}
+		shardInfo, err := m.fromInternalShardInfo(internalResults[i].ShardInfo)
+		if err != nil {
+			return nil, err
+		}
+		result.ShardInfos[shardID] = shardInfo
+	}
+	return result, nil
+}
+
+// ListShards returns a page of the shards which exist in the persistence store
+func (m *shardManager) ListShards(ctx context.Context, request *ListShardsRequest) (*ListShardsResponse, error) {
+	internalResult, err := m.persistence.ListShards(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	result := &ListShardsResponse{
+		ShardInfos:    make([]*ShardInfo, 0, len(internalResult.ShardInfos)),
+		NextPageToken: internalResult.NextPageToken,
+	}
+	for _, internalShardInfo := range internalResult.ShardInfos {
+		shardInfo, err := m.fromInternalShardInfo(internalShardInfo)
+		if err != nil {
+			return nil, err
+		}
+		result.ShardInfos = append(result.ShardInfos, shardInfo)
+	}
+	return result, nil
+}
+
 func (m *shardManager) UpdateShard(ctx context.Context, request *UpdateShardRequest) error {
 	shardInfo, err := m.toInternalShardInfo(request.ShardInfo)
 	if err != nil {
@@ -92,8 +192,35 @@ func (m *shardManager) UpdateShard(ctx context.Context, request *UpdateShardRequ
 	internalRequest := &InternalUpdateShardRequest{
 		ShardInfo:       shardInfo,
 		PreviousRangeID: request.PreviousRangeID,
+		PreviousOwner:   request.PreviousOwner,
+	}
+	if err := m.persistence.UpdateShard(ctx, internalRequest); err != nil {
+		return err
+	}
+	m.checkThrashing(request.ShardInfo)
+	return nil
+}
+
+// checkThrashing logs and counts the shard if its steal count reached the thrashing threshold,
+// each steal count is only reported once per shard as UpdateShard is called far more often than shards are stolen
+func (m *shardManager) checkThrashing(shardInfo *ShardInfo) {
+	if shardInfo == nil {
+		return
+	}
+	if m.thrashingThreshold == nil || !shardInfo.IsThrashing(m.thrashingThreshold()) {
+		m.loggedStolenCounts.Delete(shardInfo.ShardID)
+		return
+	}
+	if previous, ok := m.loggedStolenCounts.Load(shardInfo.ShardID); ok && previous.(int) == shardInfo.StolenSinceRenew {
+		return
 	}
-	return m.persistence.UpdateShard(ctx, internalRequest)
+	m.loggedStolenCounts.Store(shardInfo.ShardID, shardInfo.StolenSinceRenew)
+	m.metricsClient.IncCounter(metrics.PersistenceUpdateShardScope, metrics.PersistenceShardThrashingCounter)
+	m.logger.Warn("Shard ownership is thrashing",
+		tag.ShardID(shardInfo.ShardID),
+		tag.Counter(shardInfo.StolenSinceRenew),
+		tag.ShardRangeID(shardInfo.RangeID),
+	)
 }
 
 func (m *shardManager) toInternalShardInfo(shardInfo *ShardInfo) (*InternalShardInfo, error) {
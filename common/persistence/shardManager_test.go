@@ -0,0 +1,96 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
+)
+
+func TestUpdateShardReportsThrashingOncePerStealCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := NewMockShardStore(ctrl)
+	store.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	logger := &log.MockLogger{}
+	logger.On("Warn", "Shard ownership is thrashing", mock.Anything).Return()
+	scope := tally.NewTestScope("test", nil)
+	manager := NewShardManager(store, logger, metrics.NewClient(scope, metrics.History), dynamicconfig.GetIntPropertyFn(3))
+
+	updateShard := func(shardID, stolenSinceRenew int) {
+		err := manager.UpdateShard(context.Background(), &UpdateShardRequest{
+			ShardInfo: &ShardInfo{ShardID: shardID, StolenSinceRenew: stolenSinceRenew},
+		})
+		require.NoError(t, err)
+	}
+	thrashingCount := func() int64 {
+		var count int64
+		for _, counter := range scope.Snapshot().Counters() {
+			if counter.Name() == "test.persistence_shard_thrashing" {
+				count += counter.Value()
+			}
+		}
+		return count
+	}
+
+	updateShard(1, 2)
+	updateShard(1, 3)
+	updateShard(1, 3)
+	updateShard(2, 3)
+	assert.Equal(t, int64(2), thrashingCount())
+	logger.AssertNumberOfCalls(t, "Warn", 2)
+
+	// a new steal count is reported again
+	updateShard(1, 4)
+	assert.Equal(t, int64(3), thrashingCount())
+	logger.AssertNumberOfCalls(t, "Warn", 3)
+
+	// dropping below the threshold resets the shard
+	updateShard(1, 0)
+	updateShard(1, 4)
+	assert.Equal(t, int64(4), thrashingCount())
+	logger.AssertNumberOfCalls(t, "Warn", 4)
+}
+
+func TestUpdateShardWithoutThrashingThreshold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := NewMockShardStore(ctrl)
+	store.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	manager := NewShardManager(store, &log.MockLogger{}, nil, nil)
+
+	err := manager.UpdateShard(context.Background(), &UpdateShardRequest{
+		ShardInfo: &ShardInfo{ShardID: 1, StolenSinceRenew: 100},
+	})
+	assert.NoError(t, err)
+}
@@ -53,6 +53,12 @@ func (m *sqlStore) Close() {
 	}
 }
 
+// CloseWithContext closes the DB in the background, as database/sql waits for the queries in flight
+// to finish before closing, the DB keeps closing after ctx is done
+func (m *sqlStore) CloseWithContext(ctx context.Context) error {
+	return persistence.WaitForClose(ctx, m.Close)
+}
+
 func (m *sqlStore) txExecute(ctx context.Context, operation string, f func(tx sqlplugin.Tx) error) error {
 	tx, err := m.db.BeginTx(ctx)
 	if err != nil {
@@ -73,7 +79,10 @@ func (m *sqlStore) txExecute(ctx context.Context, operation string, f func(tx sq
 			*types.InternalServiceError,
 			*persistence.WorkflowExecutionAlreadyStartedError,
 			*types.DomainAlreadyExistsError,
-			*persistence.ShardOwnershipLostError:
+			*persistence.ShardOwnershipLostError,
+			*persistence.ShardRangeIDMismatchError,
+			*persistence.TaskListNotOwnedError,
+			*persistence.DomainVersionConflictError:
 			return err
 		default:
 			return &types.InternalServiceError{
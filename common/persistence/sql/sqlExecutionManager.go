@@ -156,10 +156,12 @@ func (m *sqlExecutionManager) createWorkflowExecutionTx(
 				}
 			}
 			if row.State != p.WorkflowStateCompleted {
-				return nil, &p.CurrentWorkflowConditionFailedError{
+				return nil, &p.CurrentWorkflowStillRunningError{
 					Msg: fmt.Sprintf("Workflow execution creation condition failed. WorkflowId: %v, "+
 						"State: %v, Expected: %v",
 						workflowID, row.State, p.WorkflowStateCompleted),
+					RunID: row.RunID.String(),
+					State: int(row.State),
 				}
 			}
 			runIDStr := row.RunID.String()
@@ -229,8 +231,11 @@ func (m *sqlExecutionManager) GetWorkflowExecution(
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, &types.EntityNotExistsError{
-				Message: fmt.Sprintf(
+			return nil, &p.WorkflowExecutionNotExistsError{
+				DomainID:   request.DomainID,
+				WorkflowID: request.Execution.GetWorkflowID(),
+				RunID:      request.Execution.GetRunID(),
+				Msg: fmt.Sprintf(
 					"Workflow execution not found.  WorkflowId: %v, RunId: %v",
 					request.Execution.GetWorkflowID(),
 					request.Execution.GetRunID(),
@@ -243,8 +248,11 @@ func (m *sqlExecutionManager) GetWorkflowExecution(
 	}
 
 	if len(executions) == 0 {
-		return nil, &types.EntityNotExistsError{
-			Message: fmt.Sprintf(
+		return nil, &p.WorkflowExecutionNotExistsError{
+			DomainID:   request.DomainID,
+			WorkflowID: request.Execution.GetWorkflowID(),
+			RunID:      request.Execution.GetRunID(),
+			Msg: fmt.Sprintf(
 				"Workflow execution not found.  WorkflowId: %v, RunId: %v",
 				request.Execution.GetWorkflowID(),
 				request.Execution.GetRunID(),
@@ -265,7 +273,7 @@ func (m *sqlExecutionManager) GetWorkflowExecution(
 		}
 	}
 
-	{
+	if request.LoadsField(p.MutableStateFieldActivityInfos) {
 		var err error
 		state.ActivityInfos, err = getActivityInfoMap(
 			ctx,
@@ -282,7 +290,7 @@ func (m *sqlExecutionManager) GetWorkflowExecution(
 		}
 	}
 
-	{
+	if request.LoadsField(p.MutableStateFieldTimerInfos) {
 		var err error
 		state.TimerInfos, err = getTimerInfoMap(
 			ctx,
@@ -299,7 +307,7 @@ func (m *sqlExecutionManager) GetWorkflowExecution(
 		}
 	}
 
-	{
+	if request.LoadsField(p.MutableStateFieldChildExecutionInfos) {
 		var err error
 		state.ChildExecutionInfos, err = getChildExecutionInfoMap(
 			ctx,
@@ -316,7 +324,7 @@ func (m *sqlExecutionManager) GetWorkflowExecution(
 		}
 	}
 
-	{
+	if request.LoadsField(p.MutableStateFieldRequestCancelInfos) {
 		var err error
 		state.RequestCancelInfos, err = getRequestCancelInfoMap(
 			ctx,
@@ -333,7 +341,7 @@ func (m *sqlExecutionManager) GetWorkflowExecution(
 		}
 	}
 
-	{
+	if request.LoadsField(p.MutableStateFieldSignalInfos) {
 		var err error
 		state.SignalInfos, err = getSignalInfoMap(
 			ctx,
@@ -350,7 +358,7 @@ func (m *sqlExecutionManager) GetWorkflowExecution(
 		}
 	}
 
-	{
+	if request.LoadsField(p.MutableStateFieldBufferedEvents) {
 		var err error
 		state.BufferedEvents, err = getBufferedEvents(
 			ctx,
@@ -366,7 +374,7 @@ func (m *sqlExecutionManager) GetWorkflowExecution(
 		}
 	}
 
-	{
+	if request.LoadsField(p.MutableStateFieldSignalRequestedIDs) {
 		var err error
 		state.SignalRequestedIDs, err = getSignalsRequested(
 			ctx,
@@ -652,32 +660,17 @@ func (m *sqlExecutionManager) conflictResolveWorkflowExecutionTx(
 		state := executionInfo.State
 		closeStatus := executionInfo.CloseStatus
 
+		// reset workflow is current unless there is a current workflow
+		prevRunID := serialization.MustParseUUID(resetWorkflow.ExecutionInfo.RunID)
 		if currentWorkflow != nil {
-			prevRunID := serialization.MustParseUUID(currentWorkflow.ExecutionInfo.RunID)
+			prevRunID = serialization.MustParseUUID(currentWorkflow.ExecutionInfo.RunID)
+		}
 
-			if err := assertRunIDAndUpdateCurrentExecution(
-				ctx,
-				tx,
-				m.shardID,
-				domainID,
-				workflowID,
-				runID,
-				prevRunID,
-				createRequestID,
-				state,
-				closeStatus,
-				startVersion,
-				lastWriteVersion); err != nil {
-				return &types.InternalServiceError{Message: fmt.Sprintf(
-					"ConflictResolveWorkflowExecution. Failed to comare and swap the current record. Error: %v",
-					err,
-				)}
-			}
+		var err error
+		if request.DryRun {
+			err = assertCurrentExecutionRunID(ctx, tx, m.shardID, domainID, workflowID, prevRunID)
 		} else {
-			// reset workflow is current
-			prevRunID := serialization.MustParseUUID(resetWorkflow.ExecutionInfo.RunID)
-
-			if err := assertRunIDAndUpdateCurrentExecution(
+			err = assertRunIDAndUpdateCurrentExecution(
 				ctx,
 				tx,
 				m.shardID,
@@ -689,12 +682,13 @@ func (m *sqlExecutionManager) conflictResolveWorkflowExecutionTx(
 				state,
 				closeStatus,
 				startVersion,
-				lastWriteVersion); err != nil {
-				return &types.InternalServiceError{Message: fmt.Sprintf(
-					"ConflictResolveWorkflowExecution. Failed to comare and swap the current record. Error: %v",
-					err,
-				)}
-			}
+				lastWriteVersion)
+		}
+		if err != nil {
+			return &types.InternalServiceError{Message: fmt.Sprintf(
+				"ConflictResolveWorkflowExecution. Failed to comare and swap the current record. Error: %v",
+				err,
+			)}
 		}
 
 	default:
@@ -703,6 +697,31 @@ func (m *sqlExecutionManager) conflictResolveWorkflowExecutionTx(
 		}
 	}
 
+	if request.DryRun {
+		// the live path checks the next event IDs while applying the workflows, check them without applying
+		if err := lockAndCheckNextEventID(
+			ctx,
+			tx,
+			shardID,
+			domainID,
+			workflowID,
+			serialization.MustParseUUID(resetWorkflow.ExecutionInfo.RunID),
+			resetWorkflow.Condition); err != nil {
+			return err
+		}
+		if currentWorkflow != nil {
+			return lockAndCheckNextEventID(
+				ctx,
+				tx,
+				shardID,
+				domainID,
+				workflowID,
+				serialization.MustParseUUID(currentWorkflow.ExecutionInfo.RunID),
+				currentWorkflow.Condition)
+		}
+		return nil
+	}
+
 	if err := applyWorkflowSnapshotTxAsReset(ctx, tx, shardID, &resetWorkflow, m.parser); err != nil {
 		return err
 	}
@@ -746,13 +765,33 @@ func (m *sqlExecutionManager) DeleteCurrentWorkflowExecution(
 
 	domainID := serialization.MustParseUUID(request.DomainID)
 	runID := serialization.MustParseUUID(request.RunID)
-	_, err := m.db.DeleteFromCurrentExecutions(ctx, &sqlplugin.CurrentExecutionsFilter{
+	filter := &sqlplugin.CurrentExecutionsFilter{
 		ShardID:    int64(m.shardID),
 		DomainID:   domainID,
 		WorkflowID: request.WorkflowID,
 		RunID:      runID,
+	}
+	if request.RangeID == 0 {
+		_, err := m.db.DeleteFromCurrentExecutions(ctx, filter)
+		return err
+	}
+	return m.txExecuteShardLocked(ctx, "DeleteCurrentWorkflowExecution", request.RangeID, func(tx sqlplugin.Tx) error {
+		result, err := tx.DeleteFromCurrentExecutions(ctx, filter)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			// the shard is still owned, so the current record points to another run by now
+			return &p.ConditionFailedError{
+				Msg: fmt.Sprintf("Failed to delete current workflow execution.  Request RunID: %v", request.RunID),
+			}
+		}
+		return nil
 	})
-	return err
 }
 
 func (m *sqlExecutionManager) GetCurrentExecution(
@@ -767,35 +806,124 @@ func (m *sqlExecutionManager) GetCurrentExecution(
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, &types.EntityNotExistsError{Message: err.Error()}
+			return nil, &p.WorkflowExecutionNotExistsError{
+				DomainID:   request.DomainID,
+				WorkflowID: request.WorkflowID,
+				Msg:        err.Error(),
+			}
 		}
 		return nil, &types.InternalServiceError{
 			Message: fmt.Sprintf("GetCurrentExecution operation failed. Error: %v", err),
 		}
 	}
-	return &p.GetCurrentExecutionResponse{
+	response := &p.GetCurrentExecutionResponse{
 		StartRequestID:   row.CreateRequestID,
 		RunID:            row.RunID.String(),
 		State:            int(row.State),
 		CloseStatus:      int(row.CloseStatus),
 		LastWriteVersion: row.LastWriteVersion,
-	}, nil
+	}
+	if request.IncludeStats {
+		stats, err := m.getExecutionStats(ctx, serialization.MustParseUUID(request.DomainID), request.WorkflowID, row.RunID)
+		if err != nil {
+			return nil, err
+		}
+		response.Stats = stats
+	}
+	return response, nil
+}
+
+func (m *sqlExecutionManager) GetCurrentRunID(
+	ctx context.Context,
+	request *p.GetCurrentRunIDRequest,
+) (*p.GetCurrentRunIDResponse, error) {
+
+	row, err := m.db.SelectFromCurrentExecutions(ctx, &sqlplugin.CurrentExecutionsFilter{
+		ShardID:    int64(m.shardID),
+		DomainID:   serialization.MustParseUUID(request.DomainID),
+		WorkflowID: request.WorkflowID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &p.WorkflowExecutionNotExistsError{
+				DomainID:   request.DomainID,
+				WorkflowID: request.WorkflowID,
+				Msg:        err.Error(),
+			}
+		}
+		return nil, &types.InternalServiceError{
+			Message: fmt.Sprintf("GetCurrentRunID operation failed. Error: %v", err),
+		}
+	}
+	return &p.GetCurrentRunIDResponse{RunID: row.RunID.String()}, nil
+}
+
+// getExecutionStats reads the stats of a run, they are only kept in the serialized execution info
+func (m *sqlExecutionManager) getExecutionStats(
+	ctx context.Context,
+	domainID serialization.UUID,
+	workflowID string,
+	runID serialization.UUID,
+) (*p.ExecutionStats, error) {
+
+	executions, err := m.db.SelectFromExecutions(ctx, &sqlplugin.ExecutionsFilter{
+		ShardID: m.shardID, DomainID: domainID, WorkflowID: workflowID, RunID: runID})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, &types.InternalServiceError{
+			Message: fmt.Sprintf("GetCurrentExecution operation failed. Error: %v", err),
+		}
+	}
+	if len(executions) == 0 {
+		return nil, &p.WorkflowExecutionNotExistsError{
+			DomainID:   domainID.String(),
+			WorkflowID: workflowID,
+			RunID:      runID.String(),
+			Msg: fmt.Sprintf(
+				"Workflow execution not found.  WorkflowId: %v, RunId: %v",
+				workflowID,
+				runID.String(),
+			),
+		}
+	}
+
+	info, err := m.parser.WorkflowExecutionInfoFromBlob(executions[0].Data, executions[0].DataEncoding)
+	if err != nil {
+		return nil, &types.InternalServiceError{
+			Message: fmt.Sprintf("GetCurrentExecution operation failed. Error: %v", err),
+		}
+	}
+	return &p.ExecutionStats{HistorySize: info.GetHistorySize()}, nil
 }
 
 func (m *sqlExecutionManager) ListCurrentExecutions(
 	_ context.Context,
 	_ *p.ListCurrentExecutionsRequest,
 ) (*p.ListCurrentExecutionsResponse, error) {
-	return nil, &types.InternalServiceError{Message: "Not yet implemented"}
+	return nil, &p.OperationNotSupportedError{Msg: "ListCurrentExecutions is not supported by SQL stores"}
 }
 
 func (m *sqlExecutionManager) IsWorkflowExecutionExists(
-	_ context.Context,
-	_ *p.IsWorkflowExecutionExistsRequest,
+	ctx context.Context,
+	request *p.IsWorkflowExecutionExistsRequest,
 ) (*p.IsWorkflowExecutionExistsResponse, error) {
-	return nil, &types.InternalServiceError{Message: "Not yet implemented"}
+
+	executions, err := m.db.SelectFromExecutions(ctx, &sqlplugin.ExecutionsFilter{
+		ShardID:    m.shardID,
+		DomainID:   serialization.MustParseUUID(request.DomainID),
+		WorkflowID: request.WorkflowID,
+		RunID:      serialization.MustParseUUID(request.RunID),
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, &types.InternalServiceError{
+			Message: fmt.Sprintf("IsWorkflowExecutionExists operation failed. Error: %v", err),
+		}
+	}
+	return &p.IsWorkflowExecutionExistsResponse{Exists: len(executions) > 0}, nil
 }
 
+// ListConcreteExecutions pages through the executions of the shard in workflow ID order. The page token is the last
+// workflow ID of the page and the next page starts after it, so an execution is never returned twice within a scan,
+// no matter what is written to the shard while it is in progress.
 func (m *sqlExecutionManager) ListConcreteExecutions(
 	ctx context.Context,
 	request *p.ListConcreteExecutionsRequest,
@@ -854,6 +982,24 @@ func (m *sqlExecutionManager) ListConcreteExecutions(
 	}, nil
 }
 
+func (m *sqlExecutionManager) CountWorkflowExecutions(
+	ctx context.Context,
+	_ *p.CountExecutionsRequest,
+) (*p.CountExecutionsResponse, error) {
+
+	count, err := m.db.CountFromExecutions(ctx, &sqlplugin.ExecutionsFilter{
+		ShardID: m.shardID,
+	})
+	if err != nil {
+		return nil, &types.InternalServiceError{
+			Message: fmt.Sprintf("CountWorkflowExecutions failed. Error: %v", err),
+		}
+	}
+	return &p.CountExecutionsResponse{
+		Count: count,
+	}, nil
+}
+
 func (m *sqlExecutionManager) GetTransferTasks(
 	ctx context.Context,
 	request *p.GetTransferTasksRequest,
@@ -925,6 +1071,45 @@ func (m *sqlExecutionManager) RangeCompleteTransferTask(
 	return nil
 }
 
+func (m *sqlExecutionManager) CompleteTransferTasks(
+	ctx context.Context,
+	request *p.CompleteTransferTasksRequest,
+) error {
+
+	return m.txExecute(ctx, "CompleteTransferTasks", func(tx sqlplugin.Tx) error {
+		for i := range request.TaskIDs {
+			if _, err := tx.DeleteFromTransferTasks(ctx, &sqlplugin.TransferTasksFilter{
+				ShardID: m.shardID,
+				TaskID:  &request.TaskIDs[i],
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (m *sqlExecutionManager) GetCrossClusterTasks(
+	_ context.Context,
+	_ *p.GetCrossClusterTasksRequest,
+) (*p.GetCrossClusterTasksResponse, error) {
+	return nil, &p.OperationNotSupportedError{Msg: "cross-cluster tasks are not supported by SQL stores"}
+}
+
+func (m *sqlExecutionManager) CompleteCrossClusterTask(
+	_ context.Context,
+	_ *p.CompleteCrossClusterTaskRequest,
+) error {
+	return &p.OperationNotSupportedError{Msg: "cross-cluster tasks are not supported by SQL stores"}
+}
+
+func (m *sqlExecutionManager) RangeCompleteCrossClusterTask(
+	_ context.Context,
+	_ *p.RangeCompleteCrossClusterTaskRequest,
+) error {
+	return &p.OperationNotSupportedError{Msg: "cross-cluster tasks are not supported by SQL stores"}
+}
+
 func (m *sqlExecutionManager) GetReplicationTasks(
 	ctx context.Context,
 	request *p.GetReplicationTasksRequest,
@@ -1140,6 +1325,27 @@ func (m *sqlExecutionManager) RangeDeleteReplicationTaskFromDLQ(
 	return nil
 }
 
+func (m *sqlExecutionManager) DeleteReplicationTasksFromDLQ(
+	ctx context.Context,
+	request *p.DeleteReplicationTasksFromDLQRequest,
+) error {
+
+	return m.txExecute(ctx, "DeleteReplicationTasksFromDLQ", func(tx sqlplugin.Tx) error {
+		for _, taskID := range request.TaskIDs {
+			if _, err := tx.DeleteMessageFromReplicationTasksDLQ(ctx, &sqlplugin.ReplicationTasksDLQFilter{
+				ReplicationTasksFilter: sqlplugin.ReplicationTasksFilter{
+					ShardID: m.shardID,
+					TaskID:  taskID,
+				},
+				SourceClusterName: request.SourceClusterName,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (m *sqlExecutionManager) CreateFailoverMarkerTasks(
 	ctx context.Context,
 	request *p.CreateFailoverMarkersRequest,
@@ -1287,6 +1493,13 @@ func (m *sqlExecutionManager) RangeCompleteTimerTask(
 	return nil
 }
 
+func (m *sqlExecutionManager) CompleteTimerTasksForDomain(
+	_ context.Context,
+	_ *p.CompleteTimerTasksForDomainRequest,
+) (int, error) {
+	return 0, &p.OperationNotSupportedError{Msg: "CompleteTimerTasksForDomain is not supported by SQL stores"}
+}
+
 func (m *sqlExecutionManager) PutReplicationTaskToDLQ(
 	ctx context.Context,
 	request *p.InternalPutReplicationTaskToDLQRequest,
@@ -1329,6 +1542,22 @@ func (m *sqlExecutionManager) PutReplicationTaskToDLQ(
 	return nil
 }
 
+func (m *sqlExecutionManager) PutReplicationTasksToDLQ(
+	ctx context.Context,
+	request *p.InternalPutReplicationTasksToDLQRequest,
+) error {
+	// tasks are inserted one by one as a duplicate entry would abort the whole transaction
+	for _, taskInfo := range request.TaskInfos {
+		if err := m.PutReplicationTaskToDLQ(ctx, &p.InternalPutReplicationTaskToDLQRequest{
+			SourceClusterName: request.SourceClusterName,
+			TaskInfo:          taskInfo,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *sqlExecutionManager) populateWorkflowMutableState(
 	execution sqlplugin.ExecutionsRow,
 ) (*p.InternalWorkflowMutableState, error) {
@@ -817,8 +817,11 @@ func lockNextEventID(
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, &types.EntityNotExistsError{
-				Message: fmt.Sprintf(
+			return nil, &p.WorkflowExecutionNotExistsError{
+				DomainID:   domainID.String(),
+				WorkflowID: workflowID,
+				RunID:      runID.String(),
+				Msg: fmt.Sprintf(
 					"lockNextEventID failed. Unable to lock executions row with (shard, domain, workflow, run) = (%v,%v,%v,%v) which does not exist.",
 					shardID,
 					domainID,
@@ -1176,21 +1179,33 @@ func assertRunIDAndUpdateCurrentExecution(
 	lastWriteVersion int64,
 ) error {
 
+	if err := assertCurrentExecutionRunID(ctx, tx, shardID, domainID, workflowID, previousRunID); err != nil {
+		return err
+	}
+
+	return updateCurrentExecution(ctx, tx, shardID, domainID, workflowID, newRunID, createRequestID, state, closeStatus, startVersion, lastWriteVersion)
+}
+
+func assertCurrentExecutionRunID(
+	ctx context.Context,
+	tx sqlplugin.Tx,
+	shardID int,
+	domainID serialization.UUID,
+	workflowID string,
+	previousRunID serialization.UUID,
+) error {
+
 	assertFn := func(currentRow *sqlplugin.CurrentExecutionsRow) error {
 		if !bytes.Equal(currentRow.RunID, previousRunID) {
 			return &p.ConditionFailedError{Msg: fmt.Sprintf(
-				"assertRunIDAndUpdateCurrentExecution failed. Current run ID was %v, expected %v",
+				"assertCurrentExecutionRunID failed. Current run ID was %v, expected %v",
 				currentRow.RunID,
 				previousRunID,
 			)}
 		}
 		return nil
 	}
-	if err := assertCurrentExecution(ctx, tx, shardID, domainID, workflowID, assertFn); err != nil {
-		return err
-	}
-
-	return updateCurrentExecution(ctx, tx, shardID, domainID, workflowID, newRunID, createRequestID, state, closeStatus, startVersion, lastWriteVersion)
+	return assertCurrentExecution(ctx, tx, shardID, domainID, workflowID, assertFn)
 }
 
 func assertAndUpdateCurrentExecution(
@@ -0,0 +1,65 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/log/loggerimpl"
+	p "github.com/uber/cadence/common/persistence"
+)
+
+func TestUnsupportedExecutionOperations(t *testing.T) {
+	store, err := NewSQLExecutionStore(nil, loggerimpl.NewNopLogger(), 0, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	tests := map[string]func() error{
+		"ListCurrentExecutions": func() error {
+			_, err := store.ListCurrentExecutions(ctx, &p.ListCurrentExecutionsRequest{})
+			return err
+		},
+		"GetCrossClusterTasks": func() error {
+			_, err := store.GetCrossClusterTasks(ctx, &p.GetCrossClusterTasksRequest{})
+			return err
+		},
+		"CompleteCrossClusterTask": func() error {
+			return store.CompleteCrossClusterTask(ctx, &p.CompleteCrossClusterTaskRequest{})
+		},
+		"RangeCompleteCrossClusterTask": func() error {
+			return store.RangeCompleteCrossClusterTask(ctx, &p.RangeCompleteCrossClusterTaskRequest{})
+		},
+		"CompleteTimerTasksForDomain": func() error {
+			_, err := store.CompleteTimerTasksForDomain(ctx, &p.CompleteTimerTasksForDomainRequest{})
+			return err
+		},
+	}
+
+	for name, operation := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.IsType(t, &p.OperationNotSupportedError{}, operation())
+		})
+	}
+}
@@ -75,6 +75,9 @@ func (m *sqlHistoryV2Manager) AppendHistoryNodes(
 			Msg: fmt.Sprintf("cannot append to ancestors' nodes"),
 		}
 	}
+	if request.TTLSeconds > 0 {
+		return &p.OperationNotSupportedError{Msg: "history node TTL is not supported by SQL stores"}
+	}
 
 	nodeRow := &sqlplugin.HistoryNodeRow{
 		TreeID:       serialization.MustParseUUID(branchInfo.GetTreeID()),
@@ -82,10 +85,34 @@ func (m *sqlHistoryV2Manager) AppendHistoryNodes(
 		NodeID:       request.NodeID,
 		TxnID:        &request.TransactionID,
 		Data:         request.Events.Data,
-		DataEncoding: string(request.Events.Encoding),
+		DataEncoding: request.Events.GetStoredEncoding(),
 		ShardID:      request.ShardID,
 	}
 
+	if request.FailIfExists {
+		// the node is checked for any transaction, the primary key only prevents duplicates of the same TxnID
+		rows, err := m.db.SelectFromHistoryNode(ctx, &sqlplugin.HistoryNodeFilter{
+			ShardID:   request.ShardID,
+			TreeID:    nodeRow.TreeID,
+			BranchID:  nodeRow.BranchID,
+			MinNodeID: common.Int64Ptr(request.NodeID),
+			MaxNodeID: common.Int64Ptr(request.NodeID + 1),
+			PageSize:  common.IntPtr(1),
+		})
+		if err != nil && err != sql.ErrNoRows {
+			return &types.InternalServiceError{Message: fmt.Sprintf("AppendHistoryNodes: %v", err)}
+		}
+		if len(rows) > 0 {
+			return &p.HistoryNodeConflictError{
+				TreeID:   branchInfo.GetTreeID(),
+				BranchID: branchInfo.GetBranchID(),
+				NodeID:   request.NodeID,
+				Msg: fmt.Sprintf("history node %v already exists in branch %v of tree %v",
+					request.NodeID, branchInfo.GetBranchID(), branchInfo.GetTreeID()),
+			}
+		}
+	}
+
 	if request.IsNewBranch {
 		var ancestors []*types.HistoryBranchRange
 		for _, anc := range branchInfo.Ancestors {
@@ -191,7 +218,7 @@ func (m *sqlHistoryV2Manager) ReadHistoryBranch(
 
 	for _, row := range rows {
 		eventBlob.Data = row.Data
-		eventBlob.Encoding = common.EncodingType(row.DataEncoding)
+		eventBlob.Encoding, eventBlob.Compression = p.ParseStoredEncoding(row.DataEncoding)
 
 		if *row.TxnID < lastTxnID {
 			// assuming that business logic layer is correct and transaction ID only increase
@@ -248,6 +275,43 @@ func (m *sqlHistoryV2Manager) ReadHistoryBranch(
 	}, nil
 }
 
+// ReadHistoryNode returns the latest transaction of a single history node
+func (m *sqlHistoryV2Manager) ReadHistoryNode(
+	ctx context.Context,
+	request *p.InternalReadHistoryNodeRequest,
+) (*p.InternalReadHistoryNodeResponse, error) {
+
+	// txn_id is stored negated, so the first row of the node is the one with the largest transaction ID
+	minNodeID := request.NodeID
+	maxNodeID := request.NodeID + 1
+	pageSize := 1
+	rows, err := m.db.SelectFromHistoryNode(ctx, &sqlplugin.HistoryNodeFilter{
+		TreeID:    serialization.MustParseUUID(request.TreeID),
+		BranchID:  serialization.MustParseUUID(request.BranchID),
+		MinNodeID: &minNodeID,
+		MaxNodeID: &maxNodeID,
+		PageSize:  &pageSize,
+		ShardID:   request.ShardID,
+	})
+	if err == sql.ErrNoRows || (err == nil && len(rows) == 0) {
+		return nil, &types.EntityNotExistsError{
+			Message: fmt.Sprintf("History node not found. TreeID: %v, BranchID: %v, NodeID: %v", request.TreeID, request.BranchID, request.NodeID),
+		}
+	}
+	if err != nil {
+		return nil, &types.InternalServiceError{
+			Message: fmt.Sprintf("ReadHistoryNode operation failed. Error: %v", err),
+		}
+	}
+
+	history := &p.DataBlob{Data: rows[0].Data}
+	history.Encoding, history.Compression = p.ParseStoredEncoding(rows[0].DataEncoding)
+	return &p.InternalReadHistoryNodeResponse{
+		History:       history,
+		TransactionID: *rows[0].TxnID,
+	}, nil
+}
+
 // ForkHistoryBranch forks a new branch from an existing branch
 // Note that application must provide a void forking nodeID, it must be a valid nodeID in that branch.
 // A valid forking nodeID can be an ancestor from the existing branch.
@@ -373,12 +437,6 @@ func (m *sqlHistoryV2Manager) DeleteHistoryBranch(
 
 	branch := request.BranchInfo
 	treeID := *branch.TreeID
-	brsToDelete := branch.Ancestors
-	beginNodeID := p.GetBeginNodeID(branch)
-	brsToDelete = append(brsToDelete, &types.HistoryBranchRange{
-		BranchID:    branch.BranchID,
-		BeginNodeID: common.Int64Ptr(beginNodeID),
-	})
 
 	rsp, err := m.GetHistoryTree(ctx, &p.InternalGetHistoryTreeRequest{
 		TreeID:  treeID,
@@ -387,17 +445,7 @@ func (m *sqlHistoryV2Manager) DeleteHistoryBranch(
 	if err != nil {
 		return err
 	}
-
-	// validBRsMaxEndNode is to for each branch range that is being used, we want to know what is the max nodeID referred by other valid branch
-	validBRsMaxEndNode := map[string]int64{}
-	for _, b := range rsp.Branches {
-		for _, br := range b.Ancestors {
-			curr, ok := validBRsMaxEndNode[*br.BranchID]
-			if !ok || curr < *br.EndNodeID {
-				validBRsMaxEndNode[*br.BranchID] = *br.EndNodeID
-			}
-		}
-	}
+	brsToDelete := p.GetHistoryBranchRangesToDelete(branch, rsp.Branches, request.PreserveAncestors)
 
 	return m.txExecute(ctx, "DeleteHistoryBranch", func(tx sqlplugin.Tx) error {
 		branchID := serialization.MustParseUUID(*branch.BranchID)
@@ -411,32 +459,17 @@ func (m *sqlHistoryV2Manager) DeleteHistoryBranch(
 			return err
 		}
 
-		done := false
-		// for each branch range to delete, we iterate from bottom to up, and delete up to the point according to validBRsEndNode
-		for i := len(brsToDelete) - 1; i >= 0; i-- {
-			br := brsToDelete[i]
-			maxReferredEndNodeID, ok := validBRsMaxEndNode[*br.BranchID]
+		for _, br := range brsToDelete {
 			nodeFilter := &sqlplugin.HistoryNodeFilter{
-				TreeID:   serialization.MustParseUUID(treeID),
-				BranchID: serialization.MustParseUUID(*br.BranchID),
-				ShardID:  request.ShardID,
-			}
-
-			if ok {
-				// we can only delete from the maxEndNode and stop here
-				nodeFilter.MinNodeID = &maxReferredEndNodeID
-				done = true
-			} else {
-				// No any branch is using this range, we can delete all of it
-				nodeFilter.MinNodeID = br.BeginNodeID
+				TreeID:    serialization.MustParseUUID(treeID),
+				BranchID:  serialization.MustParseUUID(*br.BranchID),
+				ShardID:   request.ShardID,
+				MinNodeID: br.BeginNodeID,
 			}
 			_, err := tx.DeleteFromHistoryNode(ctx, nodeFilter)
 			if err != nil {
 				return err
 			}
-			if done {
-				break
-			}
 		}
 		return nil
 	})
@@ -504,10 +537,21 @@ func (m *sqlHistoryV2Manager) GetHistoryTree(
 		TreeID:  treeID,
 		ShardID: *request.ShardID,
 	}
+	if request.PageSize > 0 {
+		// the page token is the last branch ID of the previous page
+		lastBranchID := serialization.UUID(request.NextPageToken)
+		treeFilter.BranchID = &lastBranchID
+		treeFilter.PageSize = &request.PageSize
+	}
 	rows, err := m.db.SelectFromHistoryTree(ctx, treeFilter)
 	if err == sql.ErrNoRows || (err == nil && len(rows) == 0) {
 		return &p.InternalGetHistoryTreeResponse{}, nil
 	}
+	var nextPageToken []byte
+	if request.PageSize > 0 && len(rows) >= request.PageSize {
+		// there could be more
+		nextPageToken = []byte(rows[len(rows)-1].BranchID)
+	}
 	for _, row := range rows {
 		treeInfo, err := m.parser.HistoryTreeInfoFromBlob(row.Data, row.DataEncoding)
 		if err != nil {
@@ -522,6 +566,7 @@ func (m *sqlHistoryV2Manager) GetHistoryTree(
 	}
 
 	return &p.InternalGetHistoryTreeResponse{
-		Branches: branches,
+		Branches:      branches,
+		NextPageToken: nextPageToken,
 	}, nil
 }
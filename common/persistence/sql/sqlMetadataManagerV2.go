@@ -68,6 +68,20 @@ func updateMetadata(ctx context.Context, tx sqlplugin.Tx, oldNotificationVersion
 		return &types.InternalServiceError{
 			Message: fmt.Sprintf("Could not verify whether domain metadata update occurred. Error: %v", err),
 		}
+	} else if rowsAffected == 0 {
+		// the metadata row is locked by the transaction, so the version read here is the one which caused the conflict
+		row, err := tx.SelectFromDomainMetadata(ctx)
+		if err != nil {
+			return &types.InternalServiceError{
+				Message: fmt.Sprintf("Failed to read domain metadata after a version conflict. Error: %v", err),
+			}
+		}
+		return &persistence.DomainVersionConflictError{
+			ExpectedVersion: oldNotificationVersion,
+			ActualVersion:   row.NotificationVersion,
+			Msg: fmt.Sprintf("Failed to update domain metadata. Expected notification version: %v, actual: %v",
+				oldNotificationVersion, row.NotificationVersion),
+		}
 	} else if rowsAffected != 1 {
 		return &types.InternalServiceError{
 			Message: fmt.Sprintf("Failed to update domain metadata. <>1 rows affected. Error: %v", err),
@@ -214,6 +228,25 @@ func (m *sqlMetadataManagerV2) GetDomain(
 	return response, nil
 }
 
+// GetDomainConfigVersion reads the whole domain, the versions are stored in the serialized domain
+// data in the same row, so there is no cheaper way to read them
+func (m *sqlMetadataManagerV2) GetDomainConfigVersion(
+	ctx context.Context,
+	request *persistence.GetDomainConfigVersionRequest,
+) (*persistence.GetDomainConfigVersionResponse, error) {
+	response, err := m.GetDomain(ctx, &persistence.GetDomainRequest{
+		ID:   request.ID,
+		Name: request.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &persistence.GetDomainConfigVersionResponse{
+		ConfigVersion:   response.ConfigVersion,
+		FailoverVersion: response.FailoverVersion,
+	}, nil
+}
+
 func (m *sqlMetadataManagerV2) domainRowToGetDomainResponse(row *sqlplugin.DomainRow) (*persistence.InternalGetDomainResponse, error) {
 	domainInfo, err := m.parser.DomainInfoFromBlob(row.Data, row.DataEncoding)
 	if err != nil {
@@ -262,6 +295,7 @@ func (m *sqlMetadataManagerV2) domainRowToGetDomainResponse(row *sqlplugin.Domai
 		PreviousFailoverVersion:     domainInfo.GetPreviousFailoverVersion(),
 		FailoverEndTime:             domainInfo.FailoverEndTimestamp,
 		LastUpdatedTime:             domainInfo.GetLastUpdatedTimestamp(),
+		DeletionTime:                domainInfo.DeletionTimestamp,
 	}, nil
 }
 
@@ -304,6 +338,7 @@ func (m *sqlMetadataManagerV2) UpdateDomain(
 		PreviousFailoverVersion:     common.Int64Ptr(request.PreviousFailoverVersion),
 		FailoverEndTimestamp:        request.FailoverEndTime,
 		LastUpdatedTimestamp:        &request.LastUpdatedTime,
+		DeletionTimestamp:           request.DeletionTime,
 		BadBinaries:                 badBinaries,
 		BadBinariesEncoding:         badBinariesEncoding,
 	}
@@ -83,6 +83,58 @@ func (q *sqlQueue) EnqueueMessage(
 	return nil
 }
 
+func (q *sqlQueue) EnqueueMessageWithID(
+	ctx context.Context,
+	messagePayload []byte,
+	messageID int64,
+) error {
+
+	_, err := q.db.InsertIntoQueue(ctx, newQueueRow(q.queueType, messageID, messagePayload))
+	if err != nil {
+		if q.db.IsDupEntryError(err) {
+			return &persistence.QueueMessageIDConflictError{
+				QueueType: q.queueType,
+				MessageID: messageID,
+				Msg:       fmt.Sprintf("message ID %v exists in queue %v", messageID, q.queueType),
+			}
+		}
+		return &types.InternalServiceError{Message: err.Error()}
+	}
+	return nil
+}
+
+func (q *sqlQueue) EnqueueMessages(
+	ctx context.Context,
+	messagePayloads [][]byte,
+) ([]int64, error) {
+
+	var messageIDs []int64
+	err := q.txExecute(ctx, "EnqueueMessages", func(tx sqlplugin.Tx) error {
+		lastMessageID, err := tx.GetLastEnqueuedMessageIDForUpdate(ctx, q.queueType)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				lastMessageID = -1
+			} else {
+				return fmt.Errorf("failed to get last enqueued message id: %v", err)
+			}
+		}
+
+		messageIDs = make([]int64, 0, len(messagePayloads))
+		for i, payload := range messagePayloads {
+			messageID := lastMessageID + 1 + int64(i)
+			if _, err := tx.InsertIntoQueue(ctx, newQueueRow(q.queueType, messageID, payload)); err != nil {
+				return fmt.Errorf("failed to enqueue message %v of %v, none of the messages were enqueued: %v", i+1, len(messagePayloads), err)
+			}
+			messageIDs = append(messageIDs, messageID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &types.InternalServiceError{Message: err.Error()}
+	}
+	return messageIDs, nil
+}
+
 func (q *sqlQueue) ReadMessages(
 	ctx context.Context,
 	lastMessageID int64,
@@ -101,6 +101,57 @@ func (m *sqlShardManager) GetShard(
 		}
 	}
 
+	shardInfo, err := m.shardsRowToInternalShardInfo(row)
+	if err != nil {
+		return nil, err
+	}
+	return &persistence.InternalGetShardResponse{ShardInfo: shardInfo}, nil
+}
+
+func (m *sqlShardManager) ListShards(
+	ctx context.Context,
+	request *persistence.ListShardsRequest,
+) (*persistence.InternalListShardsResponse, error) {
+	lastShardID := int64(-1)
+	if len(request.PageToken) > 0 {
+		var err error
+		lastShardID, err = deserializePageToken(request.PageToken)
+		if err != nil {
+			return nil, &types.InternalServiceError{
+				Message: fmt.Sprintf("ListShards operation failed. Error: %v", err),
+			}
+		}
+	}
+
+	rows, err := m.db.RangeSelectFromShards(ctx, &sqlplugin.ShardsFilter{
+		ShardIDGreaterThan: &lastShardID,
+		PageSize:           &request.PageSize,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, &types.InternalServiceError{
+			Message: fmt.Sprintf("ListShards operation failed. Failed to get records. Error: %v", err),
+		}
+	}
+
+	response := &persistence.InternalListShardsResponse{
+		ShardInfos: make([]*persistence.InternalShardInfo, 0, len(rows)),
+	}
+	for i := range rows {
+		shardInfo, err := m.shardsRowToInternalShardInfo(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		response.ShardInfos = append(response.ShardInfos, shardInfo)
+	}
+	if len(rows) == request.PageSize {
+		response.NextPageToken = serializePageToken(rows[len(rows)-1].ShardID)
+	}
+	return response, nil
+}
+
+func (m *sqlShardManager) shardsRowToInternalShardInfo(
+	row *sqlplugin.ShardsRow,
+) (*persistence.InternalShardInfo, error) {
 	shardInfo, err := m.parser.ShardInfoFromBlob(row.Data, row.DataEncoding)
 	if err != nil {
 		return nil, err
@@ -146,7 +197,7 @@ func (m *sqlShardManager) GetShard(
 		}
 	}
 
-	resp := &persistence.InternalGetShardResponse{ShardInfo: &persistence.InternalShardInfo{
+	return &persistence.InternalShardInfo{
 		ShardID:                       int(row.ShardID),
 		RangeID:                       row.RangeID,
 		Owner:                         shardInfo.GetOwner(),
@@ -162,9 +213,7 @@ func (m *sqlShardManager) GetShard(
 		DomainNotificationVersion:     shardInfo.GetDomainNotificationVersion(),
 		ClusterReplicationLevel:       shardInfo.ClusterReplicationLevel,
 		ReplicationDLQAckLevel:        shardInfo.ReplicationDlqAckLevel,
-	}}
-
-	return resp, nil
+	}, nil
 }
 
 func (m *sqlShardManager) UpdateShard(
@@ -181,6 +230,11 @@ func (m *sqlShardManager) UpdateShard(
 		if err := lockShard(ctx, tx, request.ShardInfo.ShardID, request.PreviousRangeID); err != nil {
 			return err
 		}
+		if request.PreviousOwner != "" {
+			if err := m.assertShardOwner(ctx, tx, request.ShardInfo.ShardID, request.PreviousOwner); err != nil {
+				return err
+			}
+		}
 		result, err := tx.UpdateShards(ctx, row)
 		if err != nil {
 			return err
@@ -196,6 +250,27 @@ func (m *sqlShardManager) UpdateShard(
 	})
 }
 
+// assertShardOwner must be called after the shard is locked
+func (m *sqlShardManager) assertShardOwner(ctx context.Context, tx sqlplugin.Tx, shardID int, previousOwner string) error {
+	row, err := tx.SelectFromShards(ctx, &sqlplugin.ShardsFilter{ShardID: int64(shardID)})
+	if err != nil {
+		return &types.InternalServiceError{
+			Message: fmt.Sprintf("Failed to read shard with ID: %v. Error: %v", shardID, err),
+		}
+	}
+	shardInfo, err := m.parser.ShardInfoFromBlob(row.Data, row.DataEncoding)
+	if err != nil {
+		return err
+	}
+	if shardInfo.GetOwner() != previousOwner {
+		return &persistence.ShardOwnershipLostError{
+			ShardID: shardID,
+			Msg:     fmt.Sprintf("Failed to update shard. Previous owner: %v; owner: %v", previousOwner, shardInfo.GetOwner()),
+		}
+	}
+	return nil
+}
+
 // initiated by the owning shard
 func lockShard(ctx context.Context, tx sqlplugin.Tx, shardID int, oldRangeID int64) error {
 	rangeID, err := tx.WriteLockShards(ctx, &sqlplugin.ShardsFilter{ShardID: int64(shardID)})
@@ -235,9 +310,11 @@ func readLockShard(ctx context.Context, tx sqlplugin.Tx, shardID int, oldRangeID
 	}
 
 	if int64(rangeID) != oldRangeID {
-		return &persistence.ShardOwnershipLostError{
-			ShardID: shardID,
-			Msg:     fmt.Sprintf("Failed to lock shard. Previous range ID: %v; new range ID: %v", oldRangeID, rangeID),
+		return &persistence.ShardRangeIDMismatchError{
+			ShardID:         shardID,
+			ExpectedRangeID: oldRangeID,
+			ActualRangeID:   int64(rangeID),
+			Msg:             fmt.Sprintf("Failed to lock shard. Previous range ID: %v; new range ID: %v", oldRangeID, rangeID),
 		}
 	}
 	return nil
@@ -24,6 +24,7 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"math"
 	"time"
@@ -77,6 +78,9 @@ func (m *sqlTaskManager) LeaseTaskList(
 		DomainID: &domainID,
 		Name:     &request.TaskList,
 		TaskType: common.Int64Ptr(int64(request.TaskType))})
+	if request.ReadOnly {
+		return m.readTaskList(request, rows, err)
+	}
 	if err != nil {
 		if err == sql.ErrNoRows {
 			tlInfo := &serialization.TaskListInfo{
@@ -194,6 +198,38 @@ func (m *sqlTaskManager) LeaseTaskList(
 	return resp, err
 }
 
+func (m *sqlTaskManager) readTaskList(
+	request *persistence.LeaseTaskListRequest,
+	rows []sqlplugin.TaskListsRow,
+	err error,
+) (*persistence.LeaseTaskListResponse, error) {
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &types.EntityNotExistsError{
+				Message: fmt.Sprintf("LeaseTaskList: task list %v of type %v does not exist", request.TaskList, request.TaskType),
+			}
+		}
+		return nil, &types.InternalServiceError{
+			Message: fmt.Sprintf("LeaseTaskList operation failed. Failed to read task list. Error: %v", err),
+		}
+	}
+
+	row := rows[0]
+	tlInfo, err := m.parser.TaskListInfoFromBlob(row.Data, row.DataEncoding)
+	if err != nil {
+		return nil, err
+	}
+	return &persistence.LeaseTaskListResponse{TaskListInfo: &persistence.TaskListInfo{
+		DomainID:    request.DomainID,
+		Name:        request.TaskList,
+		TaskType:    request.TaskType,
+		RangeID:     row.RangeID,
+		AckLevel:    tlInfo.GetAckLevel(),
+		Kind:        int(tlInfo.GetKind()),
+		LastUpdated: tlInfo.GetLastUpdated(),
+	}}, nil
+}
+
 func (m *sqlTaskManager) UpdateTaskList(
 	ctx context.Context,
 	request *persistence.UpdateTaskListRequest,
@@ -296,18 +332,71 @@ func (m *sqlTaskManager) ListTaskList(
 		pageToken = taskListPageToken{ShardID: pageToken.ShardID + 1, TaskType: math.MinInt16, DomainID: serialization.UUID{}}
 	}
 
+	return m.listTaskListResponse(pageToken.ShardID, rows, request.PageSize)
+}
+
+// ListTaskListByDomain lists the task lists of a single domain, the task lists of a domain
+// are spread over all task list shards, so the shards are walked in order
+func (m *sqlTaskManager) ListTaskListByDomain(
+	ctx context.Context,
+	request *persistence.ListTaskListByDomainRequest,
+) (*persistence.ListTaskListResponse, error) {
+	if request.PageSize <= 0 {
+		return nil, &persistence.InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("ListTaskListByDomain requires a positive page size, got %v", request.PageSize),
+		}
+	}
+	domainID, err := serialization.ParseUUID(request.DomainID)
+	if err != nil {
+		return nil, &types.BadRequestError{Message: fmt.Sprintf("ListTaskListByDomain: invalid domain ID: %v", err)}
+	}
+	pageToken := taskListPageToken{TaskType: math.MinInt16}
+	if request.PageToken != nil {
+		if err := gobDeserialize(request.PageToken, &pageToken); err != nil {
+			return nil, &types.InternalServiceError{Message: fmt.Sprintf("error deserializing page token: %v", err)}
+		}
+	}
+	var rows []sqlplugin.TaskListsRow
+	for pageToken.ShardID < m.nShards {
+		rows, err = m.db.SelectFromTaskLists(ctx, &sqlplugin.TaskListsFilter{
+			ShardID:             pageToken.ShardID,
+			DomainID:            &domainID,
+			NameGreaterThan:     &pageToken.Name,
+			TaskTypeGreaterThan: &pageToken.TaskType,
+			PageSize:            &request.PageSize,
+		})
+		if err != nil {
+			return nil, &types.InternalServiceError{Message: err.Error()}
+		}
+		if len(rows) > 0 {
+			break
+		}
+		pageToken = taskListPageToken{ShardID: pageToken.ShardID + 1, TaskType: math.MinInt16}
+	}
+
+	return m.listTaskListResponse(pageToken.ShardID, rows, request.PageSize)
+}
+
+// listTaskListResponse converts a page of task list rows read from the given shard into a response,
+// the next page token points after the last row, or at the next shard if the page is not full
+func (m *sqlTaskManager) listTaskListResponse(
+	shardID int,
+	rows []sqlplugin.TaskListsRow,
+	pageSize int,
+) (*persistence.ListTaskListResponse, error) {
 	var nextPageToken []byte
+	var err error
 	switch {
-	case len(rows) >= request.PageSize:
-		lastRow := &rows[request.PageSize-1]
+	case len(rows) >= pageSize:
+		lastRow := &rows[pageSize-1]
 		nextPageToken, err = gobSerialize(&taskListPageToken{
-			ShardID:  pageToken.ShardID,
+			ShardID:  shardID,
 			DomainID: lastRow.DomainID,
 			Name:     lastRow.Name,
 			TaskType: lastRow.TaskType,
 		})
-	case pageToken.ShardID+1 < m.nShards:
-		nextPageToken, err = gobSerialize(&taskListPageToken{ShardID: pageToken.ShardID + 1, TaskType: math.MinInt16, DomainID: serialization.UUID{}})
+	case shardID+1 < m.nShards:
+		nextPageToken, err = gobSerialize(&taskListPageToken{ShardID: shardID + 1, TaskType: math.MinInt16, DomainID: serialization.UUID{}})
 	}
 
 	if err != nil {
@@ -362,6 +451,7 @@ func (m *sqlTaskManager) DeleteTaskList(
 	return nil
 }
 
+// CreateTasks ignores the request IdempotencyKey, every batch is applied
 func (m *sqlTaskManager) CreateTasks(
 	ctx context.Context,
 	request *persistence.InternalCreateTasksRequest,
@@ -434,11 +524,7 @@ func (m *sqlTaskManager) CreateTasks(
 		}
 
 		// Lock task list before committing.
-		err1 := lockTaskList(ctx, tx,
-			m.shardID(request.TaskListInfo.DomainID, request.TaskListInfo.Name),
-			serialization.MustParseUUID(request.TaskListInfo.DomainID),
-			request.TaskListInfo.Name,
-			request.TaskListInfo.TaskType, request.TaskListInfo.RangeID)
+		err1 := m.lockOwnedTaskList(ctx, tx, request.TaskListInfo)
 		if err1 != nil {
 			return err1
 		}
@@ -492,11 +578,24 @@ func (m *sqlTaskManager) CompleteTask(
 ) error {
 	taskID := request.TaskID
 	taskList := request.TaskList
-	_, err := m.db.DeleteFromTasks(ctx, &sqlplugin.TasksFilter{
+	filter := &sqlplugin.TasksFilter{
 		DomainID:     serialization.MustParseUUID(taskList.DomainID),
 		TaskListName: taskList.Name,
 		TaskType:     int64(taskList.TaskType),
-		TaskID:       &taskID})
+		TaskID:       &taskID}
+	if taskList.RangeID > 0 {
+		return m.txExecute(ctx, "CompleteTask", func(tx sqlplugin.Tx) error {
+			if err := m.lockOwnedTaskList(ctx, tx, taskList); err != nil {
+				return err
+			}
+			if _, err := tx.DeleteFromTasks(ctx, filter); err != nil && err != sql.ErrNoRows {
+				return err
+			}
+			return nil
+		})
+	}
+
+	_, err := m.db.DeleteFromTasks(ctx, filter)
 	if err != nil && err != sql.ErrNoRows {
 		return &types.InternalServiceError{Message: err.Error()}
 	}
@@ -530,9 +629,31 @@ func (m *sqlTaskManager) CompleteTasksLessThan(
 // in the task_lists table.
 // TODO: Limit this query to a specific shard at a time. See https://github.com/uber/cadence/issues/4064
 func (m *sqlTaskManager) GetOrphanTasks(ctx context.Context, request *persistence.GetOrphanTasksRequest) (*persistence.GetOrphanTasksResponse, error) {
-	rows, err := m.db.GetOrphanTasks(ctx, &sqlplugin.OrphanTasksFilter{
+	filter := &sqlplugin.OrphanTasksFilter{
 		Limit: &request.Limit,
-	})
+	}
+	if len(request.NextPageToken) > 0 {
+		var lastTask persistence.TaskKey
+		if err := json.Unmarshal(request.NextPageToken, &lastTask); err != nil {
+			return nil, &types.BadRequestError{
+				Message: fmt.Sprintf("GetOrphanTasks: invalid next page token: %v", err),
+			}
+		}
+		domainID, err := serialization.ParseUUID(lastTask.DomainID)
+		if err != nil {
+			return nil, &types.BadRequestError{
+				Message: fmt.Sprintf("GetOrphanTasks: invalid next page token: %v", err),
+			}
+		}
+		filter.ExclusiveMinTaskKey = &sqlplugin.TaskKeyRow{
+			DomainID:     domainID,
+			TaskListName: lastTask.TaskListName,
+			TaskType:     int64(lastTask.TaskType),
+			TaskID:       lastTask.TaskID,
+		}
+	}
+
+	rows, err := m.db.GetOrphanTasks(ctx, filter)
 	if err != nil {
 		return nil, &types.InternalServiceError{Message: err.Error()}
 	}
@@ -547,7 +668,17 @@ func (m *sqlTaskManager) GetOrphanTasks(ctx context.Context, request *persistenc
 		}
 	}
 
-	return &persistence.GetOrphanTasksResponse{Tasks: tasks}, nil
+	response := &persistence.GetOrphanTasksResponse{Tasks: tasks}
+	if len(tasks) > 0 && len(tasks) == request.Limit {
+		token, err := json.Marshal(tasks[len(tasks)-1])
+		if err != nil {
+			return nil, &types.InternalServiceError{
+				Message: fmt.Sprintf("GetOrphanTasks: failed to serialize next page token: %v", err),
+			}
+		}
+		response.NextPageToken = token
+	}
+	return response, nil
 }
 
 func (m *sqlTaskManager) shardID(domainID string, name string) int {
@@ -578,6 +709,39 @@ func lockTaskList(ctx context.Context, tx sqlplugin.Tx, shardID int, domainID se
 	}
 }
 
+// lockOwnedTaskList locks the task list row and returns TaskListNotOwnedError
+// if it is no longer leased at the RangeID of the given task list info
+func (m *sqlTaskManager) lockOwnedTaskList(ctx context.Context, tx sqlplugin.Tx, tli *persistence.TaskListInfo) error {
+	domainID := serialization.MustParseUUID(tli.DomainID)
+	rangeID, err := tx.LockTaskLists(ctx, &sqlplugin.TaskListsFilter{
+		ShardID:  m.shardID(tli.DomainID, tli.Name),
+		DomainID: &domainID,
+		Name:     &tli.Name,
+		TaskType: common.Int64Ptr(int64(tli.TaskType))})
+
+	switch err {
+	case nil:
+		if rangeID == tli.RangeID {
+			return nil
+		}
+	case sql.ErrNoRows:
+		// the task list is gone, so it is not owned at any range ID
+	default:
+		return &types.InternalServiceError{
+			Message: fmt.Sprintf("Failed to lock task list. Error: %v", err),
+		}
+	}
+	return &persistence.TaskListNotOwnedError{
+		DomainID:        tli.DomainID,
+		TaskListName:    tli.Name,
+		TaskType:        tli.TaskType,
+		ExpectedRangeID: tli.RangeID,
+		ActualRangeID:   rangeID,
+		Msg: fmt.Sprintf("Task list %v of type %v range ID was %v when it should have been %v",
+			tli.Name, tli.TaskType, rangeID, tli.RangeID),
+	}
+}
+
 func stickyTaskListExpiry() time.Time {
 	return time.Now().Add(stickyTasksListsTTL)
 }
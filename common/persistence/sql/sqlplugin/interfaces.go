@@ -80,7 +80,9 @@ type (
 	// ShardsFilter contains the column names within shards table that
 	// can be used to filter results through a WHERE clause
 	ShardsFilter struct {
-		ShardID int64
+		ShardID            int64
+		ShardIDGreaterThan *int64
+		PageSize           *int
 	}
 
 	// TransferTasksRow represents a row in transfer_tasks table
@@ -210,8 +212,10 @@ type (
 	}
 
 	// OrphanTasksFilter contains the parameters controlling orphan deletion
+	// Rows are returned in primary key order, starting after ExclusiveMinTaskKey if it is set
 	OrphanTasksFilter struct {
-		Limit *int
+		Limit               *int
+		ExclusiveMinTaskKey *TaskKeyRow
 	}
 
 	// TaskListsRow represents a row in task_lists table
@@ -560,6 +564,9 @@ type (
 		InsertIntoShards(ctx context.Context, rows *ShardsRow) (sql.Result, error)
 		UpdateShards(ctx context.Context, row *ShardsRow) (sql.Result, error)
 		SelectFromShards(ctx context.Context, filter *ShardsFilter) (*ShardsRow, error)
+		// RangeSelectFromShards returns up to PageSize rows from shards table ordered by shard_id
+		// only ShardIDGreaterThan and PageSize in the filter are used
+		RangeSelectFromShards(ctx context.Context, filter *ShardsFilter) ([]ShardsRow, error)
 		ReadLockShards(ctx context.Context, filter *ShardsFilter) (int, error)
 		WriteLockShards(ctx context.Context, filter *ShardsFilter) (int, error)
 
@@ -602,6 +609,9 @@ type (
 		InsertIntoExecutions(ctx context.Context, row *ExecutionsRow) (sql.Result, error)
 		UpdateExecutions(ctx context.Context, row *ExecutionsRow) (sql.Result, error)
 		SelectFromExecutions(ctx context.Context, filter *ExecutionsFilter) ([]ExecutionsRow, error)
+		// CountFromExecutions returns the number of rows in executions table for the shard in the filter
+		// only ShardID in the filter is used
+		CountFromExecutions(ctx context.Context, filter *ExecutionsFilter) (int64, error)
 		DeleteFromExecutions(ctx context.Context, filter *ExecutionsFilter) (sql.Result, error)
 		ReadLockExecutions(ctx context.Context, filter *ExecutionsFilter) (int, error)
 		WriteLockExecutions(ctx context.Context, filter *ExecutionsFilter) (int, error)
@@ -45,6 +45,9 @@ const (
 
 	getHistoryTreeQuery = `SELECT branch_id, data, data_encoding FROM history_tree WHERE shard_id = ? AND tree_id = ? `
 
+	getHistoryTreePageQuery = `SELECT branch_id, data, data_encoding FROM history_tree ` +
+		`WHERE shard_id = ? AND tree_id = ? AND branch_id > ? ORDER BY branch_id LIMIT ? `
+
 	deleteHistoryTreeQuery = `DELETE FROM history_tree WHERE shard_id = ? AND tree_id = ? AND branch_id = ? `
 
 	getAllHistoryTreeQuery = `SELECT shard_id, tree_id, branch_id, data, data_encoding FROM history_tree WHERE (shard_id = ? AND tree_id = ? AND branch_id > ?) OR (shard_id = ? AND tree_id > ?) OR (shard_id > ?) ORDER BY shard_id, tree_id, branch_id LIMIT ?`
@@ -83,9 +86,14 @@ func (mdb *db) InsertIntoHistoryTree(ctx context.Context, row *sqlplugin.History
 	return mdb.conn.NamedExecContext(ctx, addHistoryTreeQuery, row)
 }
 
-// SelectFromHistoryTree reads one or more rows from history_tree table
+// SelectFromHistoryTree reads one or more rows from history_tree table,
+// if the filter has a page size, a page of rows ordered by branch ID and following the filter branch ID is read
 func (mdb *db) SelectFromHistoryTree(ctx context.Context, filter *sqlplugin.HistoryTreeFilter) ([]sqlplugin.HistoryTreeRow, error) {
 	var rows []sqlplugin.HistoryTreeRow
+	if filter.PageSize != nil {
+		err := mdb.conn.SelectContext(ctx, &rows, getHistoryTreePageQuery, filter.ShardID, filter.TreeID, *filter.BranchID, *filter.PageSize)
+		return rows, err
+	}
 	err := mdb.conn.SelectContext(ctx, &rows, getHistoryTreeQuery, filter.ShardID, filter.TreeID)
 	return rows, err
 }
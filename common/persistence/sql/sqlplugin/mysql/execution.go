@@ -43,6 +43,8 @@ const (
 	listExecutionQuery = `SELECT ` + executionsColumns + ` FROM executions
  WHERE shard_id = ? AND workflow_id > ? ORDER BY workflow_id LIMIT ?`
 
+	countExecutionQuery = `SELECT COUNT(1) FROM executions WHERE shard_id = ?`
+
 	deleteExecutionQuery = `DELETE FROM executions 
  WHERE shard_id = ? AND domain_id = ? AND workflow_id = ? AND run_id = ?`
 
@@ -194,6 +196,13 @@ func (mdb *db) SelectFromExecutions(ctx context.Context, filter *sqlplugin.Execu
 	return rows, err
 }
 
+// CountFromExecutions returns the number of rows in executions table for a shard
+func (mdb *db) CountFromExecutions(ctx context.Context, filter *sqlplugin.ExecutionsFilter) (int64, error) {
+	var count int64
+	err := mdb.conn.GetContext(ctx, &count, countExecutionQuery, filter.ShardID)
+	return count, err
+}
+
 // DeleteFromExecutions deletes a single row from executions table
 func (mdb *db) DeleteFromExecutions(ctx context.Context, filter *sqlplugin.ExecutionsFilter) (sql.Result, error) {
 	return mdb.conn.ExecContext(ctx, deleteExecutionQuery, filter.ShardID, filter.DomainID, filter.WorkflowID, filter.RunID)
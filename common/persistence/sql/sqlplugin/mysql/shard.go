@@ -35,6 +35,10 @@ const (
  shard_id, range_id, data, data_encoding
  FROM shards WHERE shard_id = ?`
 
+	rangeGetShardsQry = `SELECT
+ shard_id, range_id, data, data_encoding
+ FROM shards WHERE shard_id > ? ORDER BY shard_id LIMIT ?`
+
 	updateShardQry = `UPDATE shards 
  SET range_id = ?, data = ?, data_encoding = ? 
  WHERE shard_id = ?`
@@ -63,6 +67,13 @@ func (mdb *db) SelectFromShards(ctx context.Context, filter *sqlplugin.ShardsFil
 	return &row, err
 }
 
+// RangeSelectFromShards reads a page of rows from shards table
+func (mdb *db) RangeSelectFromShards(ctx context.Context, filter *sqlplugin.ShardsFilter) ([]sqlplugin.ShardsRow, error) {
+	var rows []sqlplugin.ShardsRow
+	err := mdb.conn.SelectContext(ctx, &rows, rangeGetShardsQry, *filter.ShardIDGreaterThan, *filter.PageSize)
+	return rows, err
+}
+
 // ReadLockShards acquires a read lock on a single row in shards table
 func (mdb *db) ReadLockShards(ctx context.Context, filter *sqlplugin.ShardsFilter) (int, error) {
 	var rangeID int
@@ -64,6 +64,11 @@ task_type = :task_type
 		`WHERE shard_id = ? AND ((domain_id = ? AND name = ? AND task_type > ?) OR (domain_id=? AND name > ?) OR (domain_id > ?)) ` +
 		`ORDER BY domain_id,name,task_type LIMIT ?`
 
+	listTaskListByDomainQry = `SELECT domain_id, range_id, name, task_type, data, data_encoding ` +
+		`FROM task_lists ` +
+		`WHERE shard_id = ? AND domain_id = ? AND ((name = ? AND task_type > ?) OR name > ?) ` +
+		`ORDER BY name,task_type LIMIT ?`
+
 	getTaskListQry = `SELECT domain_id, range_id, name, task_type, data, data_encoding ` +
 		`FROM task_lists ` +
 		`WHERE shard_id = ? AND domain_id = ? AND name = ? AND task_type = ?`
@@ -97,7 +102,13 @@ task_type = :task_type
 		`WHERE NOT EXISTS ( ` +
 		`	SELECT domain_id, name, task_type FROM task_lists AS tl ` +
 		`	WHERE t.domain_id=tl.domain_id and t.task_list_name=tl.name and t.task_type=tl.task_type ` +
-		`) LIMIT ?;`
+		`) ORDER BY domain_id,task_list_name,task_type,task_id LIMIT ?;`
+
+	getOrphanTaskAfterQry = `SELECT task_id, domain_id, task_list_name, task_type FROM tasks AS t ` +
+		`WHERE (domain_id, task_list_name, task_type, task_id) > (?, ?, ?, ?) AND NOT EXISTS ( ` +
+		`	SELECT domain_id, name, task_type FROM task_lists AS tl ` +
+		`	WHERE t.domain_id=tl.domain_id and t.task_list_name=tl.name and t.task_type=tl.task_type ` +
+		`) ORDER BY domain_id,task_list_name,task_type,task_id LIMIT ?;`
 )
 
 // InsertIntoTasks inserts one or more rows into tasks table
@@ -140,8 +151,13 @@ func (mdb *db) GetOrphanTasks(ctx context.Context, filter *sqlplugin.OrphanTasks
 		return nil, fmt.Errorf("missing limit parameter")
 	}
 	var rows []sqlplugin.TaskKeyRow
-
-	err := mdb.conn.SelectContext(ctx, &rows, getOrphanTaskQry, *filter.Limit)
+	var err error
+	if key := filter.ExclusiveMinTaskKey; key != nil {
+		err = mdb.conn.SelectContext(ctx, &rows, getOrphanTaskAfterQry,
+			key.DomainID, key.TaskListName, key.TaskType, key.TaskID, *filter.Limit)
+	} else {
+		err = mdb.conn.SelectContext(ctx, &rows, getOrphanTaskQry, *filter.Limit)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -165,6 +181,8 @@ func (mdb *db) SelectFromTaskLists(ctx context.Context, filter *sqlplugin.TaskLi
 		return mdb.selectFromTaskLists(ctx, filter)
 	case filter.DomainIDGreaterThan != nil && filter.NameGreaterThan != nil && filter.TaskTypeGreaterThan != nil && filter.PageSize != nil:
 		return mdb.rangeSelectFromTaskLists(ctx, filter)
+	case filter.DomainID != nil && filter.NameGreaterThan != nil && filter.TaskTypeGreaterThan != nil && filter.PageSize != nil:
+		return mdb.rangeSelectFromTaskListsByDomain(ctx, filter)
 	default:
 		return nil, fmt.Errorf("invalid set of query filter params")
 	}
@@ -194,6 +212,19 @@ func (mdb *db) rangeSelectFromTaskLists(ctx context.Context, filter *sqlplugin.T
 	return rows, nil
 }
 
+func (mdb *db) rangeSelectFromTaskListsByDomain(ctx context.Context, filter *sqlplugin.TaskListsFilter) ([]sqlplugin.TaskListsRow, error) {
+	var rows []sqlplugin.TaskListsRow
+	err := mdb.conn.SelectContext(ctx, &rows, listTaskListByDomainQry,
+		filter.ShardID, *filter.DomainID, *filter.NameGreaterThan, *filter.TaskTypeGreaterThan, *filter.NameGreaterThan, *filter.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].ShardID = filter.ShardID
+	}
+	return rows, nil
+}
+
 // DeleteFromTaskLists deletes a row from task_lists table
 func (mdb *db) DeleteFromTaskLists(ctx context.Context, filter *sqlplugin.TaskListsFilter) (sql.Result, error) {
 	return mdb.conn.ExecContext(ctx, deleteTaskListQry, filter.ShardID, *filter.DomainID, *filter.Name, *filter.TaskType, *filter.RangeID)
@@ -45,6 +45,9 @@ const (
 
 	getHistoryTreeQuery = `SELECT branch_id, data, data_encoding FROM history_tree WHERE shard_id = $1 AND tree_id = $2 `
 
+	getHistoryTreePageQuery = `SELECT branch_id, data, data_encoding FROM history_tree ` +
+		`WHERE shard_id = $1 AND tree_id = $2 AND branch_id > $3 ORDER BY branch_id LIMIT $4 `
+
 	deleteHistoryTreeQuery = `DELETE FROM history_tree WHERE shard_id = $1 AND tree_id = $2 AND branch_id = $3 `
 
 	getAllHistoryTreeQuery = `SELECT shard_id, tree_id, branch_id, data, data_encoding FROM history_tree WHERE (shard_id = $1 AND tree_id = $2 AND branch_id > $3) OR (shard_id = $1 AND tree_id > $2) OR (shard_id > $1) ORDER BY shard_id, tree_id, branch_id LIMIT $4`
@@ -83,9 +86,14 @@ func (pdb *db) InsertIntoHistoryTree(ctx context.Context, row *sqlplugin.History
 	return pdb.conn.NamedExecContext(ctx, addHistoryTreeQuery, row)
 }
 
-// SelectFromHistoryTree reads one or more rows from history_tree table
+// SelectFromHistoryTree reads one or more rows from history_tree table,
+// if the filter has a page size, a page of rows ordered by branch ID and following the filter branch ID is read
 func (pdb *db) SelectFromHistoryTree(ctx context.Context, filter *sqlplugin.HistoryTreeFilter) ([]sqlplugin.HistoryTreeRow, error) {
 	var rows []sqlplugin.HistoryTreeRow
+	if filter.PageSize != nil {
+		err := pdb.conn.SelectContext(ctx, &rows, getHistoryTreePageQuery, filter.ShardID, filter.TreeID, *filter.BranchID, *filter.PageSize)
+		return rows, err
+	}
 	err := pdb.conn.SelectContext(ctx, &rows, getHistoryTreeQuery, filter.ShardID, filter.TreeID)
 	return rows, err
 }
@@ -43,6 +43,8 @@ const (
 	listExecutionQuery = `SELECT ` + executionsColumns + ` FROM executions
  WHERE shard_id = $1 AND workflow_id > $2 ORDER BY workflow_id LIMIT $3`
 
+	countExecutionQuery = `SELECT COUNT(1) FROM executions WHERE shard_id = $1`
+
 	deleteExecutionQuery = `DELETE FROM executions 
  WHERE shard_id = $1 AND domain_id = $2 AND workflow_id = $3 AND run_id = $4`
 
@@ -192,6 +194,13 @@ func (pdb *db) SelectFromExecutions(ctx context.Context, filter *sqlplugin.Execu
 	return rows, err
 }
 
+// CountFromExecutions returns the number of rows in executions table for a shard
+func (pdb *db) CountFromExecutions(ctx context.Context, filter *sqlplugin.ExecutionsFilter) (int64, error) {
+	var count int64
+	err := pdb.conn.GetContext(ctx, &count, countExecutionQuery, filter.ShardID)
+	return count, err
+}
+
 // DeleteFromExecutions deletes a single row from executions table
 func (pdb *db) DeleteFromExecutions(ctx context.Context, filter *sqlplugin.ExecutionsFilter) (sql.Result, error) {
 	return pdb.conn.ExecContext(ctx, deleteExecutionQuery, filter.ShardID, filter.DomainID, filter.WorkflowID, filter.RunID)
@@ -35,6 +35,10 @@ const (
  shard_id, range_id, data, data_encoding
  FROM shards WHERE shard_id = $1`
 
+	rangeGetShardsQry = `SELECT
+ shard_id, range_id, data, data_encoding
+ FROM shards WHERE shard_id > $1 ORDER BY shard_id LIMIT $2`
+
 	updateShardQry = `UPDATE shards 
  SET range_id = $1, data = $2, data_encoding = $3 
  WHERE shard_id = $4`
@@ -63,6 +67,13 @@ func (pdb *db) SelectFromShards(ctx context.Context, filter *sqlplugin.ShardsFil
 	return &row, err
 }
 
+// RangeSelectFromShards reads a page of rows from shards table
+func (pdb *db) RangeSelectFromShards(ctx context.Context, filter *sqlplugin.ShardsFilter) ([]sqlplugin.ShardsRow, error) {
+	var rows []sqlplugin.ShardsRow
+	err := pdb.conn.SelectContext(ctx, &rows, rangeGetShardsQry, *filter.ShardIDGreaterThan, *filter.PageSize)
+	return rows, err
+}
+
 // ReadLockShards acquires a read lock on a single row in shards table
 func (pdb *db) ReadLockShards(ctx context.Context, filter *sqlplugin.ShardsFilter) (int, error) {
 	var rangeID int
@@ -64,6 +64,11 @@ task_type = :task_type
 		`WHERE shard_id = $1 AND ((domain_id = $2 AND name = $3 AND task_type > $4) OR (domain_id=$2 AND name > $3) OR (domain_id > $2)) ` +
 		`ORDER BY domain_id,name,task_type LIMIT $5`
 
+	listTaskListByDomainQry = `SELECT domain_id, range_id, name, task_type, data, data_encoding ` +
+		`FROM task_lists ` +
+		`WHERE shard_id = $1 AND domain_id = $2 AND ((name = $3 AND task_type > $4) OR name > $3) ` +
+		`ORDER BY name,task_type LIMIT $5`
+
 	getTaskListQry = `SELECT domain_id, range_id, name, task_type, data, data_encoding ` +
 		`FROM task_lists ` +
 		`WHERE shard_id = $1 AND domain_id = $2 AND name = $3 AND task_type = $4`
@@ -98,7 +103,13 @@ task_type = :task_type
 		`WHERE NOT EXISTS ( ` +
 		`	SELECT domain_id, name, task_type FROM task_lists AS tl ` +
 		`	WHERE t.domain_id=tl.domain_id and t.task_list_name=tl.name and t.task_type=tl.task_type ` +
-		`) LIMIT $1;`
+		`) ORDER BY domain_id,task_list_name,task_type,task_id LIMIT $1;`
+
+	getOrphanTaskAfterQry = `SELECT task_id, domain_id, task_list_name, task_type FROM tasks AS t ` +
+		`WHERE (domain_id, task_list_name, task_type, task_id) > ($1, $2, $3, $4) AND NOT EXISTS ( ` +
+		`	SELECT domain_id, name, task_type FROM task_lists AS tl ` +
+		`	WHERE t.domain_id=tl.domain_id and t.task_list_name=tl.name and t.task_type=tl.task_type ` +
+		`) ORDER BY domain_id,task_list_name,task_type,task_id LIMIT $5;`
 )
 
 // InsertIntoTasks inserts one or more rows into tasks table
@@ -141,7 +152,13 @@ func (pdb *db) GetOrphanTasks(ctx context.Context, filter *sqlplugin.OrphanTasks
 		return nil, fmt.Errorf("missing limit parameter")
 	}
 	var rows []sqlplugin.TaskKeyRow
-	err := pdb.conn.SelectContext(ctx, &rows, getOrphanTaskQry, *filter.Limit)
+	var err error
+	if key := filter.ExclusiveMinTaskKey; key != nil {
+		err = pdb.conn.SelectContext(ctx, &rows, getOrphanTaskAfterQry,
+			key.DomainID, key.TaskListName, key.TaskType, key.TaskID, *filter.Limit)
+	} else {
+		err = pdb.conn.SelectContext(ctx, &rows, getOrphanTaskQry, *filter.Limit)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -165,6 +182,8 @@ func (pdb *db) SelectFromTaskLists(ctx context.Context, filter *sqlplugin.TaskLi
 		return pdb.selectFromTaskLists(ctx, filter)
 	case filter.DomainIDGreaterThan != nil && filter.NameGreaterThan != nil && filter.TaskTypeGreaterThan != nil && filter.PageSize != nil:
 		return pdb.rangeSelectFromTaskLists(ctx, filter)
+	case filter.DomainID != nil && filter.NameGreaterThan != nil && filter.TaskTypeGreaterThan != nil && filter.PageSize != nil:
+		return pdb.rangeSelectFromTaskListsByDomain(ctx, filter)
 	default:
 		return nil, fmt.Errorf("invalid set of query filter params")
 	}
@@ -194,6 +213,19 @@ func (pdb *db) rangeSelectFromTaskLists(ctx context.Context, filter *sqlplugin.T
 	return rows, nil
 }
 
+func (pdb *db) rangeSelectFromTaskListsByDomain(ctx context.Context, filter *sqlplugin.TaskListsFilter) ([]sqlplugin.TaskListsRow, error) {
+	var rows []sqlplugin.TaskListsRow
+	err := pdb.conn.SelectContext(ctx, &rows, listTaskListByDomainQry,
+		filter.ShardID, *filter.DomainID, *filter.NameGreaterThan, *filter.TaskTypeGreaterThan, *filter.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].ShardID = filter.ShardID
+	}
+	return rows, nil
+}
+
 // DeleteFromTaskLists deletes a row from task_lists table
 func (pdb *db) DeleteFromTaskLists(ctx context.Context, filter *sqlplugin.TaskListsFilter) (sql.Result, error) {
 	return pdb.conn.ExecContext(ctx, deleteTaskListQry, filter.ShardID, *filter.DomainID, *filter.Name, *filter.TaskType, *filter.RangeID)
@@ -21,11 +21,22 @@
 
 package persistence
 
+import (
+	"fmt"
+	"sort"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/types"
+)
+
 type (
 	// statsComputer is to computing struct sizes after serialization
 	statsComputer struct{}
 )
 
+// sizeEstimateSerializer is used to measure events the same way they are encoded when persisted
+var sizeEstimateSerializer = NewPayloadSerializer()
+
 func (sc *statsComputer) computeMutableStateStats(req *InternalGetWorkflowExecutionResponse) *MutableStateStats {
 	executionInfoSize := computeExecutionInfoSize(req.State.ExecutionInfo)
 
@@ -213,3 +224,281 @@ func computeSignalInfoSize(si *SignalInfo) int {
 
 	return size
 }
+
+// Sub returns the per-field difference between the stats and other, a nil other is treated as empty stats
+func (s *MutableStateStats) Sub(other *MutableStateStats) *MutableStateStats {
+	if other == nil {
+		other = &MutableStateStats{}
+	}
+	return &MutableStateStats{
+		MutableStateSize: s.MutableStateSize - other.MutableStateSize,
+
+		ExecutionInfoSize:  s.ExecutionInfoSize - other.ExecutionInfoSize,
+		ActivityInfoSize:   s.ActivityInfoSize - other.ActivityInfoSize,
+		TimerInfoSize:      s.TimerInfoSize - other.TimerInfoSize,
+		ChildInfoSize:      s.ChildInfoSize - other.ChildInfoSize,
+		SignalInfoSize:     s.SignalInfoSize - other.SignalInfoSize,
+		BufferedEventsSize: s.BufferedEventsSize - other.BufferedEventsSize,
+
+		ActivityInfoCount:      s.ActivityInfoCount - other.ActivityInfoCount,
+		TimerInfoCount:         s.TimerInfoCount - other.TimerInfoCount,
+		ChildInfoCount:         s.ChildInfoCount - other.ChildInfoCount,
+		SignalInfoCount:        s.SignalInfoCount - other.SignalInfoCount,
+		RequestCancelInfoCount: s.RequestCancelInfoCount - other.RequestCancelInfoCount,
+		BufferedEventsCount:    s.BufferedEventsCount - other.BufferedEventsCount,
+	}
+}
+
+// TopContributors returns the names of the n largest size fields of the breakdown, largest first.
+// Ties keep the field declaration order, MutableStateSize is the total and is never reported
+func (s *MutableStateStats) TopContributors(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	contributors := []struct {
+		name string
+		size int
+	}{
+		{"ExecutionInfoSize", s.ExecutionInfoSize},
+		{"ActivityInfoSize", s.ActivityInfoSize},
+		{"TimerInfoSize", s.TimerInfoSize},
+		{"ChildInfoSize", s.ChildInfoSize},
+		{"SignalInfoSize", s.SignalInfoSize},
+		{"BufferedEventsSize", s.BufferedEventsSize},
+	}
+	sort.SliceStable(contributors, func(i, j int) bool {
+		return contributors[i].size > contributors[j].size
+	})
+
+	if n > len(contributors) {
+		n = len(contributors)
+	}
+	names := make([]string, 0, n)
+	for _, c := range contributors[:n] {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// EstimatedSize returns an estimate of the number of bytes the mutation will write once serialized.
+// It is not byte exact, but it grows with the same payloads that count towards the transaction size limit,
+// so callers can use it to decide whether to flush buffered events separately.
+func (m *WorkflowMutation) EstimatedSize() int {
+	size := estimateExecutionInfoSize(m.ExecutionInfo)
+	for _, ai := range m.UpsertActivityInfos {
+		size += estimateActivityInfoSize(ai)
+	}
+	for _, ti := range m.UpsertTimerInfos {
+		size += computeTimerInfoSize(ti)
+	}
+	for _, ci := range m.UpsertChildExecutionInfos {
+		size += estimateChildInfoSize(ci)
+	}
+	for _, rci := range m.UpsertRequestCancelInfos {
+		size += len(rci.CancelRequestID)
+	}
+	for _, si := range m.UpsertSignalInfos {
+		size += computeSignalInfoSize(si)
+	}
+	for _, id := range m.UpsertSignalRequestedIDs {
+		size += len(id)
+	}
+	size += estimateEventsSize(m.NewBufferedEvents)
+
+	return size
+}
+
+// EstimatedSize returns an estimate of the number of bytes the snapshot will write once serialized.
+// See WorkflowMutation.EstimatedSize for details.
+func (s *WorkflowSnapshot) EstimatedSize() int {
+	size := estimateExecutionInfoSize(s.ExecutionInfo)
+	for _, ai := range s.ActivityInfos {
+		size += estimateActivityInfoSize(ai)
+	}
+	for _, ti := range s.TimerInfos {
+		size += computeTimerInfoSize(ti)
+	}
+	for _, ci := range s.ChildExecutionInfos {
+		size += estimateChildInfoSize(ci)
+	}
+	for _, rci := range s.RequestCancelInfos {
+		size += len(rci.CancelRequestID)
+	}
+	for _, si := range s.SignalInfos {
+		size += computeSignalInfoSize(si)
+	}
+	for _, id := range s.SignalRequestedIDs {
+		size += len(id)
+	}
+
+	return size
+}
+
+// SplitBySize splits a snapshot which is estimated to be larger than maxBytes into a primary snapshot to create
+// the workflow with, and follow-up mutations to apply in order with UpdateWorkflowExecution, each of them estimated
+// to be within maxBytes. The activity, timer, child execution, request cancel and signal infos are deferred to the
+// follow-ups, the execution info is not, so a TransactionSizeLimitError is returned if it does not fit on its own
+// or alongside any single deferred info. Every follow-up is conditioned on the NextEventID of the snapshot.
+//
+// The tasks and the checksum of the snapshot are only valid for the complete mutable state, so they are carried by
+// the last follow-up rather than by the primary snapshot. Keeping the decision task in the primary snapshot would
+// dispatch a decision against a mutable state which is still missing its infos. As a result the workflow created
+// with the primary snapshot has no transfer, timer or replication task, including its decision task and timeouts,
+// until the last follow-up is applied: callers must apply every follow-up, and retry the remaining ones or delete
+// the workflow if one of them fails, otherwise the workflow never makes progress. A snapshot within maxBytes is
+// returned as it is.
+func (s *WorkflowSnapshot) SplitBySize(maxBytes int) (WorkflowSnapshot, []WorkflowMutation, error) {
+	if s.EstimatedSize() <= maxBytes {
+		return *s, nil, nil
+	}
+
+	primary := WorkflowSnapshot{
+		ExecutionInfo:    s.ExecutionInfo,
+		ExecutionStats:   s.ExecutionStats,
+		VersionHistories: s.VersionHistories,
+		Condition:        s.Condition,
+	}
+	baseSize := primary.EstimatedSize()
+	if baseSize > maxBytes {
+		return WorkflowSnapshot{}, nil, &TransactionSizeLimitError{
+			Msg: fmt.Sprintf("workflow snapshot can not be split, its execution info alone is estimated to %v bytes, limit: %v", baseSize, maxBytes),
+		}
+	}
+
+	var followups []WorkflowMutation
+	followupSize := 0
+	deferInfo := func(size int, apply func(*WorkflowMutation)) error {
+		if baseSize+size > maxBytes {
+			return &TransactionSizeLimitError{
+				Msg: fmt.Sprintf("workflow snapshot can not be split, an info of %v bytes does not fit alongside the execution info of %v bytes, limit: %v", size, baseSize, maxBytes),
+			}
+		}
+		if len(followups) == 0 || followupSize+size > maxBytes {
+			followups = append(followups, WorkflowMutation{
+				ExecutionInfo:    s.ExecutionInfo,
+				ExecutionStats:   s.ExecutionStats,
+				VersionHistories: s.VersionHistories,
+				Condition:        s.ExecutionInfo.NextEventID,
+			})
+			followupSize = baseSize
+		}
+		apply(&followups[len(followups)-1])
+		followupSize += size
+		return nil
+	}
+
+	for _, ai := range s.ActivityInfos {
+		ai := ai
+		if err := deferInfo(estimateActivityInfoSize(ai), func(m *WorkflowMutation) {
+			m.UpsertActivityInfos = append(m.UpsertActivityInfos, ai)
+		}); err != nil {
+			return WorkflowSnapshot{}, nil, err
+		}
+	}
+	for _, ti := range s.TimerInfos {
+		ti := ti
+		if err := deferInfo(computeTimerInfoSize(ti), func(m *WorkflowMutation) {
+			m.UpsertTimerInfos = append(m.UpsertTimerInfos, ti)
+		}); err != nil {
+			return WorkflowSnapshot{}, nil, err
+		}
+	}
+	for _, ci := range s.ChildExecutionInfos {
+		ci := ci
+		if err := deferInfo(estimateChildInfoSize(ci), func(m *WorkflowMutation) {
+			m.UpsertChildExecutionInfos = append(m.UpsertChildExecutionInfos, ci)
+		}); err != nil {
+			return WorkflowSnapshot{}, nil, err
+		}
+	}
+	for _, rci := range s.RequestCancelInfos {
+		rci := rci
+		if err := deferInfo(len(rci.CancelRequestID), func(m *WorkflowMutation) {
+			m.UpsertRequestCancelInfos = append(m.UpsertRequestCancelInfos, rci)
+		}); err != nil {
+			return WorkflowSnapshot{}, nil, err
+		}
+	}
+	for _, si := range s.SignalInfos {
+		si := si
+		if err := deferInfo(computeSignalInfoSize(si), func(m *WorkflowMutation) {
+			m.UpsertSignalInfos = append(m.UpsertSignalInfos, si)
+		}); err != nil {
+			return WorkflowSnapshot{}, nil, err
+		}
+	}
+	for _, id := range s.SignalRequestedIDs {
+		id := id
+		if err := deferInfo(len(id), func(m *WorkflowMutation) {
+			m.UpsertSignalRequestedIDs = append(m.UpsertSignalRequestedIDs, id)
+		}); err != nil {
+			return WorkflowSnapshot{}, nil, err
+		}
+	}
+
+	last := &followups[len(followups)-1]
+	last.TransferTasks = s.TransferTasks
+	last.ReplicationTasks = s.ReplicationTasks
+	last.TimerTasks = s.TimerTasks
+	last.Checksum = s.Checksum
+	return primary, followups, nil
+}
+
+func estimateExecutionInfoSize(executionInfo *WorkflowExecutionInfo) int {
+	if executionInfo == nil {
+		return 0
+	}
+
+	size := len(executionInfo.WorkflowID)
+	size += len(executionInfo.TaskList)
+	size += len(executionInfo.WorkflowTypeName)
+	size += len(executionInfo.ParentWorkflowID)
+	size += len(executionInfo.ExecutionContext)
+	size += estimateEventSize(executionInfo.CompletionEvent)
+	for key, value := range executionInfo.Memo {
+		size += len(key) + len(value)
+	}
+	for key, value := range executionInfo.SearchAttributes {
+		size += len(key) + len(value)
+	}
+
+	return size
+}
+
+func estimateActivityInfoSize(ai *ActivityInfo) int {
+	size := len(ai.ActivityID)
+	size += estimateEventSize(ai.ScheduledEvent)
+	size += estimateEventSize(ai.StartedEvent)
+	size += len(ai.Details)
+	size += len(ai.LastFailureDetails)
+
+	return size
+}
+
+func estimateChildInfoSize(ci *ChildExecutionInfo) int {
+	size := estimateEventSize(ci.InitiatedEvent)
+	size += estimateEventSize(ci.StartedEvent)
+
+	return size
+}
+
+func estimateEventSize(event *types.HistoryEvent) int {
+	// a failure to serialize will also fail the actual write, so it is fine to not account for the event here
+	blob, err := sizeEstimateSerializer.SerializeEvent(event, common.EncodingTypeThriftRW)
+	if err != nil || blob == nil {
+		return 0
+	}
+	return len(blob.Data)
+}
+
+func estimateEventsSize(events []*types.HistoryEvent) int {
+	if len(events) == 0 {
+		return 0
+	}
+	blob, err := sizeEstimateSerializer.SerializeBatchEvents(events, common.EncodingTypeThriftRW)
+	if err != nil || blob == nil {
+		return 0
+	}
+	return len(blob.Data)
+}
@@ -28,6 +28,8 @@ import (
 
 	workflow "github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/checksum"
+	"github.com/uber/cadence/common/types"
 )
 
 type (
@@ -85,3 +87,165 @@ func (s *statsComputerSuite) TestStatsWithStartedEvent() {
 	stats := s.sc.computeMutableStateUpdateStats(ms)
 	s.Equal(stats.ExecutionInfoSize, expectedSize)
 }
+
+func (s *statsComputerSuite) TestWorkflowMutationEstimatedSize() {
+	mutation := &WorkflowMutation{
+		ExecutionInfo: &WorkflowExecutionInfo{
+			WorkflowID:       "test-workflow-id",
+			TaskList:         "test-tasklist",
+			WorkflowTypeName: "test-workflow-type-name",
+			SearchAttributes: map[string][]byte{"CustomKeywordField": []byte(`"keyword"`)},
+		},
+	}
+	baseSize := mutation.EstimatedSize()
+	s.True(baseSize >= len("test-workflow-id")+len("test-tasklist")+len("test-workflow-type-name")+len("CustomKeywordField"))
+
+	var bufferedEvents []*types.HistoryEvent
+	for i := int64(0); i < 100; i++ {
+		bufferedEvents = append(bufferedEvents, &types.HistoryEvent{
+			EventID:   common.BufferedEventID,
+			EventType: types.EventTypeWorkflowExecutionSignaled.Ptr(),
+			WorkflowExecutionSignaledEventAttributes: &types.WorkflowExecutionSignaledEventAttributes{
+				SignalName: "test-signal",
+				Input:      make([]byte, 1024),
+			},
+		})
+	}
+	mutation.NewBufferedEvents = bufferedEvents
+	s.True(mutation.EstimatedSize() >= baseSize+100*1024)
+
+	mutation.NewBufferedEvents = bufferedEvents[:1]
+	smallSize := mutation.EstimatedSize()
+	s.True(smallSize > baseSize)
+	s.True(smallSize < baseSize+100*1024)
+}
+
+func (s *statsComputerSuite) TestWorkflowSnapshotEstimatedSize() {
+	snapshot := &WorkflowSnapshot{
+		ExecutionInfo: &WorkflowExecutionInfo{
+			WorkflowID: "test-workflow-id",
+		},
+	}
+	baseSize := snapshot.EstimatedSize()
+	s.Equal(len("test-workflow-id"), baseSize)
+
+	snapshot.ActivityInfos = []*ActivityInfo{{
+		ActivityID: "test-activity-id",
+		ScheduledEvent: &types.HistoryEvent{
+			EventID:   5,
+			EventType: types.EventTypeActivityTaskScheduled.Ptr(),
+			ActivityTaskScheduledEventAttributes: &types.ActivityTaskScheduledEventAttributes{
+				ActivityID: "test-activity-id",
+				Input:      make([]byte, 1024),
+			},
+		},
+	}}
+	s.True(snapshot.EstimatedSize() >= baseSize+len("test-activity-id")+1024)
+}
+
+func (s *statsComputerSuite) TestWorkflowSnapshotSplitBySize() {
+	newActivityInfo := func(id int64) *ActivityInfo {
+		return &ActivityInfo{
+			ScheduleID: id,
+			ActivityID: "test-activity-id",
+			Details:    make([]byte, 1024),
+		}
+	}
+	snapshot := &WorkflowSnapshot{
+		ExecutionInfo: &WorkflowExecutionInfo{
+			WorkflowID:       "test-workflow-id",
+			NextEventID:      3,
+			SearchAttributes: map[string][]byte{"CustomKeywordField": make([]byte, 512)},
+		},
+		SignalRequestedIDs: []string{"test-signal-requested-id"},
+		TransferTasks:      []Task{&DecisionTask{}},
+		TimerTasks:         []Task{&UserTimerTask{}},
+		Checksum:           checksum.Checksum{Flavor: checksum.FlavorIEEECRC32OverThriftBinary, Value: []byte("crc")},
+	}
+	for i := int64(0); i < 10; i++ {
+		snapshot.ActivityInfos = append(snapshot.ActivityInfos, newActivityInfo(i))
+	}
+	baseSize := estimateExecutionInfoSize(snapshot.ExecutionInfo)
+	maxBytes := baseSize + 3*1024
+
+	primary, followups, err := snapshot.SplitBySize(maxBytes)
+	s.NoError(err)
+	s.True(primary.EstimatedSize() <= maxBytes)
+	s.Equal(snapshot.ExecutionInfo, primary.ExecutionInfo)
+	s.Empty(primary.ActivityInfos)
+	s.Empty(primary.SignalRequestedIDs)
+	s.Empty(primary.TransferTasks)
+	s.Empty(primary.TimerTasks)
+	s.Empty(primary.Checksum.Value)
+
+	s.True(len(followups) > 1)
+	var activityInfos []*ActivityInfo
+	var signalRequestedIDs []string
+	for i, followup := range followups {
+		s.True(followup.EstimatedSize() <= maxBytes)
+		s.Equal(int64(3), followup.Condition)
+		activityInfos = append(activityInfos, followup.UpsertActivityInfos...)
+		signalRequestedIDs = append(signalRequestedIDs, followup.UpsertSignalRequestedIDs...)
+		if i < len(followups)-1 {
+			s.Empty(followup.TransferTasks)
+			s.Empty(followup.Checksum.Value)
+		}
+	}
+	s.Equal(snapshot.ActivityInfos, activityInfos)
+	s.Equal(snapshot.SignalRequestedIDs, signalRequestedIDs)
+	last := followups[len(followups)-1]
+	s.Equal(snapshot.TransferTasks, last.TransferTasks)
+	s.Equal(snapshot.TimerTasks, last.TimerTasks)
+	s.Equal(snapshot.Checksum, last.Checksum)
+
+	primary, followups, err = snapshot.SplitBySize(snapshot.EstimatedSize())
+	s.NoError(err)
+	s.Equal(*snapshot, primary)
+	s.Empty(followups)
+
+	_, _, err = snapshot.SplitBySize(baseSize - 1)
+	s.IsType(&TransactionSizeLimitError{}, err)
+	_, _, err = snapshot.SplitBySize(baseSize + 512)
+	s.IsType(&TransactionSizeLimitError{}, err)
+}
+
+func (s *statsComputerSuite) TestMutableStateStatsSub() {
+	current := &MutableStateStats{
+		MutableStateSize:    300,
+		ExecutionInfoSize:   100,
+		ActivityInfoSize:    150,
+		BufferedEventsSize:  50,
+		ActivityInfoCount:   3,
+		BufferedEventsCount: 2,
+	}
+	previous := &MutableStateStats{
+		MutableStateSize:  160,
+		ExecutionInfoSize: 100,
+		ActivityInfoSize:  60,
+		ActivityInfoCount: 1,
+	}
+
+	s.Equal(&MutableStateStats{
+		MutableStateSize:    140,
+		ActivityInfoSize:    90,
+		BufferedEventsSize:  50,
+		ActivityInfoCount:   2,
+		BufferedEventsCount: 2,
+	}, current.Sub(previous))
+	s.Equal(current, current.Sub(nil))
+}
+
+func (s *statsComputerSuite) TestMutableStateStatsTopContributors() {
+	stats := &MutableStateStats{
+		MutableStateSize:   1000,
+		ExecutionInfoSize:  100,
+		ActivityInfoSize:   400,
+		TimerInfoSize:      100,
+		BufferedEventsSize: 400,
+	}
+
+	s.Nil(stats.TopContributors(0))
+	s.Equal([]string{"ActivityInfoSize"}, stats.TopContributors(1))
+	s.Equal([]string{"ActivityInfoSize", "BufferedEventsSize", "ExecutionInfoSize"}, stats.TopContributors(3))
+	s.Len(stats.TopContributors(10), 6)
+}
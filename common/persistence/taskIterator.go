@@ -0,0 +1,83 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"context"
+)
+
+type (
+	taskIterator struct {
+		taskManager TaskManager
+		request     GetTasksRequest
+		tasks       []*TaskInfo
+		exhausted   bool
+	}
+)
+
+var _ TaskIterator = (*taskIterator)(nil)
+
+// NewTaskIterator returns a TaskIterator that reads batches from GetTasks only when the tasks already
+// fetched have all been returned, the read level of the request is moved past the last task of every batch.
+// The request passed in is not modified.
+func NewTaskIterator(
+	taskManager TaskManager,
+	request *GetTasksRequest,
+) TaskIterator {
+	iter := &taskIterator{
+		taskManager: taskManager,
+		request:     *request,
+	}
+	if request.MaxReadLevel != nil {
+		maxReadLevel := *request.MaxReadLevel
+		iter.request.MaxReadLevel = &maxReadLevel
+	}
+	return iter
+}
+
+func (i *taskIterator) Next(
+	ctx context.Context,
+) (*TaskInfo, error) {
+	if len(i.tasks) == 0 {
+		if i.exhausted {
+			return nil, nil
+		}
+		response, err := i.taskManager.GetTasks(ctx, &i.request)
+		if err != nil {
+			return nil, err
+		}
+		i.tasks = response.Tasks
+		// a short batch means there is nothing left below the max read level
+		i.exhausted = len(response.Tasks) < i.request.BatchSize
+		if len(i.tasks) == 0 {
+			i.exhausted = true
+			return nil, nil
+		}
+		i.request.ReadLevel = i.tasks[len(i.tasks)-1].TaskID
+		if i.request.MaxReadLevel != nil && i.request.ReadLevel >= *i.request.MaxReadLevel {
+			i.exhausted = true
+		}
+	}
+
+	task := i.tasks[0]
+	i.tasks = i.tasks[1:]
+	return task, nil
+}
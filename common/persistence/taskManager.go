@@ -24,12 +24,16 @@ package persistence
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/uber/cadence/common"
 )
 
 type (
 	taskManager struct {
+		taskManagerCompositeOperations
+
 		persistence TaskStore
 	}
 )
@@ -40,9 +44,11 @@ var _ TaskManager = (*taskManager)(nil)
 func NewTaskManager(
 	persistence TaskStore,
 ) TaskManager {
-	return &taskManager{
+	m := &taskManager{
 		persistence: persistence,
 	}
+	m.taskManagerCompositeOperations = taskManagerCompositeOperations{manager: m}
+	return m
 }
 
 func (t *taskManager) GetName() string {
@@ -53,6 +59,11 @@ func (t *taskManager) Close() {
 	t.persistence.Close()
 }
 
+// CloseWithContext closes the task store within the deadline of ctx
+func (t *taskManager) CloseWithContext(ctx context.Context) error {
+	return CloseWithContext(ctx, t.persistence)
+}
+
 func (t *taskManager) LeaseTaskList(ctx context.Context, request *LeaseTaskListRequest) (*LeaseTaskListResponse, error) {
 	return t.persistence.LeaseTaskList(ctx, request)
 }
@@ -62,7 +73,26 @@ func (t *taskManager) UpdateTaskList(ctx context.Context, request *UpdateTaskLis
 }
 
 func (t *taskManager) ListTaskList(ctx context.Context, request *ListTaskListRequest) (*ListTaskListResponse, error) {
-	return t.persistence.ListTaskList(ctx, request)
+	response, err := t.persistence.ListTaskList(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if request.ExcludeExpired {
+		// filtering happens after the page is read, so the page token returned by the store is still valid
+		now := time.Now()
+		items := make([]TaskListInfo, 0, len(response.Items))
+		for _, item := range response.Items {
+			if !item.IsExpired(now) {
+				items = append(items, item)
+			}
+		}
+		response.Items = items
+	}
+	return response, nil
+}
+
+func (t *taskManager) ListTaskListByDomain(ctx context.Context, request *ListTaskListByDomainRequest) (*ListTaskListResponse, error) {
+	return t.persistence.ListTaskListByDomain(ctx, request)
 }
 
 func (t *taskManager) DeleteTaskList(ctx context.Context, request *DeleteTaskListRequest) error {
@@ -75,14 +105,37 @@ func (t *taskManager) CreateTasks(ctx context.Context, request *CreateTasksReque
 		internalCreateTasks = append(internalCreateTasks, t.toInternalCreateTaskInfo(task))
 	}
 	internalRequest := &InternalCreateTasksRequest{
-		TaskListInfo: request.TaskListInfo,
-		Tasks:        internalCreateTasks,
+		TaskListInfo:   request.TaskListInfo,
+		Tasks:          internalCreateTasks,
+		IdempotencyKey: createTasksIdempotencyKey(request),
 	}
 	_, err := t.persistence.CreateTasks(ctx, internalRequest)
 	if err != nil {
 		return nil, err
 	}
-	return &CreateTasksResponse{}, err
+	var maxTaskID int64
+	for _, task := range request.Tasks {
+		if task.TaskID > maxTaskID {
+			maxTaskID = task.TaskID
+		}
+	}
+	return &CreateTasksResponse{MaxTaskID: maxTaskID}, nil
+}
+
+// createTasksIdempotencyKey returns the key used to dedup a CreateTasks batch,
+// falling back to the task keys when the request does not carry its own
+func createTasksIdempotencyKey(request *CreateTasksRequest) string {
+	if request.IdempotencyKey != "" || len(request.Tasks) == 0 {
+		return request.IdempotencyKey
+	}
+	keys := make([]string, 0, len(request.Tasks))
+	for _, task := range request.Tasks {
+		if task.IdempotencyKey == "" {
+			return ""
+		}
+		keys = append(keys, task.IdempotencyKey)
+	}
+	return strings.Join(keys, ",")
 }
 
 func (t *taskManager) GetTasks(ctx context.Context, request *GetTasksRequest) (*GetTasksResponse, error) {
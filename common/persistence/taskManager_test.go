@@ -0,0 +1,153 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common"
+)
+
+func newTestTaskManager(t *testing.T) (*MockTaskStore, TaskManager) {
+	controller := gomock.NewController(t)
+	t.Cleanup(controller.Finish)
+	store := NewMockTaskStore(controller)
+	return store, NewTaskManager(store)
+}
+
+func TestListTaskListExcludeExpired(t *testing.T) {
+	now := time.Now()
+	store, manager := newTestTaskManager(t)
+	store.EXPECT().ListTaskList(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *ListTaskListRequest) (*ListTaskListResponse, error) {
+			return &ListTaskListResponse{
+				Items: []TaskListInfo{
+					{Name: "normal"},
+					{Name: "sticky-expired", Kind: TaskListKindSticky, Expiry: now.Add(-time.Minute)},
+					{Name: "sticky", Kind: TaskListKindSticky, Expiry: now.Add(time.Hour)},
+				},
+				NextPageToken: []byte("next"),
+			}, nil
+		},
+	).Times(2)
+
+	response, err := manager.ListTaskList(context.Background(), &ListTaskListRequest{PageSize: 3})
+	require.NoError(t, err)
+	assert.Len(t, response.Items, 3)
+
+	response, err = manager.ListTaskList(context.Background(), &ListTaskListRequest{PageSize: 3, ExcludeExpired: true})
+	require.NoError(t, err)
+	require.Len(t, response.Items, 2)
+	assert.Equal(t, "normal", response.Items[0].Name)
+	assert.Equal(t, "sticky", response.Items[1].Name)
+	assert.Equal(t, []byte("next"), response.NextPageToken)
+}
+
+func TestCreateTasksMaxTaskID(t *testing.T) {
+	store, manager := newTestTaskManager(t)
+	var taskIDs []int64
+	store.EXPECT().CreateTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *InternalCreateTasksRequest) (*CreateTasksResponse, error) {
+			for _, task := range request.Tasks {
+				taskIDs = append(taskIDs, task.TaskID)
+			}
+			return &CreateTasksResponse{}, nil
+		},
+	).Times(1)
+
+	response, err := manager.CreateTasks(context.Background(), &CreateTasksRequest{
+		TaskListInfo: &TaskListInfo{},
+		Tasks: []*CreateTaskInfo{
+			{TaskID: 5, Data: &TaskInfo{}},
+			{TaskID: 7, Data: &TaskInfo{}},
+			{TaskID: 6, Data: &TaskInfo{}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), response.MaxTaskID)
+	assert.Equal(t, []int64{5, 7, 6}, taskIDs)
+}
+
+func TestCreateTasksIdempotencyKey(t *testing.T) {
+	assert.Equal(t, "", createTasksIdempotencyKey(&CreateTasksRequest{}))
+	assert.Equal(t, "batch", createTasksIdempotencyKey(&CreateTasksRequest{
+		IdempotencyKey: "batch",
+		Tasks:          []*CreateTaskInfo{{IdempotencyKey: "a"}},
+	}))
+	assert.Equal(t, "a,b", createTasksIdempotencyKey(&CreateTasksRequest{
+		Tasks: []*CreateTaskInfo{{IdempotencyKey: "a"}, {IdempotencyKey: "b"}},
+	}))
+	assert.Equal(t, "", createTasksIdempotencyKey(&CreateTasksRequest{
+		Tasks: []*CreateTaskInfo{{IdempotencyKey: "a"}, {}},
+	}))
+}
+
+func TestGetTasksIterator(t *testing.T) {
+	store, manager := newTestTaskManager(t)
+	request := &GetTasksRequest{
+		ReadLevel:    3,
+		MaxReadLevel: common.Int64Ptr(9),
+		BatchSize:    2,
+	}
+	// the mock fails the test if the iterator reads before Next is called
+	iter := manager.GetTasksIterator(request)
+
+	expectRead := func(readLevel int64, taskIDs ...int64) *gomock.Call {
+		return store.EXPECT().GetTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *GetTasksRequest) (*InternalGetTasksResponse, error) {
+				assert.Equal(t, readLevel, request.ReadLevel)
+				assert.Equal(t, common.Int64Ptr(9), request.MaxReadLevel)
+				response := &InternalGetTasksResponse{}
+				for _, taskID := range taskIDs {
+					response.Tasks = append(response.Tasks, &InternalTaskInfo{TaskID: taskID})
+				}
+				return response, nil
+			},
+		).Times(1)
+	}
+	// the second batch reaches the max read level, no further read is needed
+	gomock.InOrder(
+		expectRead(3, 5, 6),
+		expectRead(6, 8, 9),
+	)
+
+	var taskIDs []int64
+	for {
+		task, err := iter.Next(context.Background())
+		require.NoError(t, err)
+		if task == nil {
+			break
+		}
+		taskIDs = append(taskIDs, task.TaskID)
+	}
+	assert.Equal(t, []int64{5, 6, 8, 9}, taskIDs)
+	assert.Equal(t, int64(3), request.ReadLevel)
+
+	task, err := iter.Next(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, task)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"context"
+)
+
+type (
+	timerTaskIterator struct {
+		executionManager ExecutionManager
+		request          GetTimerIndexTasksRequest
+		timers           []*TimerTaskInfo
+		exhausted        bool
+	}
+)
+
+var _ TimerTaskIterator = (*timerTaskIterator)(nil)
+
+// NewTimerTaskIterator returns a TimerTaskIterator that reads pages from GetTimerIndexTasks
+// only when the timers already fetched have all been returned. The request passed in is not modified.
+func NewTimerTaskIterator(
+	executionManager ExecutionManager,
+	request *GetTimerIndexTasksRequest,
+) TimerTaskIterator {
+	return &timerTaskIterator{
+		executionManager: executionManager,
+		request:          *request,
+	}
+}
+
+func (i *timerTaskIterator) Next(
+	ctx context.Context,
+) (*TimerTaskInfo, error) {
+	// a filtered page can be empty while more pages remain, keep reading until a timer shows up
+	for len(i.timers) == 0 {
+		if i.exhausted {
+			return nil, nil
+		}
+		response, err := i.executionManager.GetTimerIndexTasks(ctx, &i.request)
+		if err != nil {
+			return nil, err
+		}
+		i.timers = response.Timers
+		i.request.NextPageToken = response.NextPageToken
+		i.exhausted = len(response.NextPageToken) == 0
+	}
+
+	timer := i.timers[0]
+	i.timers = i.timers[1:]
+	return timer, nil
+}
@@ -539,6 +539,24 @@ func (h *VersionHistories) FindLCAVersionHistoryIndexAndItem(
 	return versionHistoryIndex, versionHistoryItem, nil
 }
 
+// FindLCAItem finds the lowest common ancestor version history item
+// between the incoming version history and all local version histories
+func (h *VersionHistories) FindLCAItem(
+	incomingHistory *VersionHistory,
+) (*VersionHistoryItem, error) {
+
+	_, item, err := h.FindLCAVersionHistoryIndexAndItem(incomingHistory)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, &types.BadRequestError{
+			Message: "version histories is empty. No joint point found.",
+		}
+	}
+	return item, nil
+}
+
 // FindFirstVersionHistoryIndexByItem find the first version history index which
 // contains the given version history item
 func (h *VersionHistories) FindFirstVersionHistoryIndexByItem(
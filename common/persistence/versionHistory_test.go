@@ -383,6 +383,83 @@ func (s *versionHistorySuite) TestFindLCAItem_Error_NoLCA() {
 	s.Error(err)
 }
 
+func (s *versionHistorySuite) TestFindLCAItem_Table() {
+	items := []*VersionHistoryItem{
+		{EventID: 3, Version: 0},
+		{EventID: 5, Version: 4},
+		{EventID: 7, Version: 6},
+	}
+	testCases := []struct {
+		name         string
+		localItems   []*VersionHistoryItem
+		remoteItems  []*VersionHistoryItem
+		expectedItem *VersionHistoryItem
+		expectedErr  bool
+	}{
+		{
+			name:        "both empty",
+			expectedErr: true,
+		},
+		{
+			name:        "local empty",
+			remoteItems: items,
+			expectedErr: true,
+		},
+		{
+			name:        "remote empty",
+			localItems:  items,
+			expectedErr: true,
+		},
+		{
+			name:         "identical",
+			localItems:   items,
+			remoteItems:  items,
+			expectedItem: NewVersionHistoryItem(7, 6),
+		},
+		{
+			name:       "diverging",
+			localItems: items,
+			remoteItems: []*VersionHistoryItem{
+				{EventID: 3, Version: 0},
+				{EventID: 4, Version: 4},
+				{EventID: 10, Version: 8},
+			},
+			expectedItem: NewVersionHistoryItem(4, 4),
+		},
+		{
+			name:       "remote is prefix",
+			localItems: items,
+			remoteItems: []*VersionHistoryItem{
+				{EventID: 3, Version: 0},
+				{EventID: 5, Version: 4},
+			},
+			expectedItem: NewVersionHistoryItem(5, 4),
+		},
+		{
+			name:       "no common prefix",
+			localItems: items,
+			remoteItems: []*VersionHistoryItem{
+				{EventID: 3, Version: 1},
+				{EventID: 8, Version: 3},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		localVersionHistory := NewVersionHistory([]byte("local branch token"), tc.localItems)
+		remoteVersionHistory := NewVersionHistory([]byte("remote branch token"), tc.remoteItems)
+
+		item, err := localVersionHistory.FindLCAItem(remoteVersionHistory)
+		if tc.expectedErr {
+			s.Error(err, tc.name)
+			continue
+		}
+		s.NoError(err, tc.name)
+		s.Equal(tc.expectedItem, item, tc.name)
+	}
+}
+
 func (s *versionHistorySuite) TestGetFirstItem_Success() {
 	BranchToken := []byte("some random branch token")
 	item := NewVersionHistoryItem(3, 0)
@@ -633,6 +710,80 @@ func (s *versionHistoriesSuite) TestFindLCAVersionHistoryIndexAndItem_SameEventI
 	s.Equal(NewVersionHistoryItem(7, 6), item)
 }
 
+func (s *versionHistoriesSuite) TestFindLCAItem() {
+	versionHistory1 := NewVersionHistory([]byte("branch token 1"), []*VersionHistoryItem{
+		{EventID: 3, Version: 0},
+		{EventID: 5, Version: 4},
+		{EventID: 7, Version: 6},
+		{EventID: 9, Version: 10},
+	})
+	versionHistory2 := NewVersionHistory([]byte("branch token 2"), []*VersionHistoryItem{
+		{EventID: 3, Version: 0},
+		{EventID: 5, Version: 4},
+		{EventID: 6, Version: 6},
+		{EventID: 11, Version: 12},
+	})
+	histories := NewVersionHistories(versionHistory1)
+	_, _, err := histories.AddVersionHistory(versionHistory2)
+	s.Nil(err)
+
+	testCases := []struct {
+		name         string
+		histories    *VersionHistories
+		incoming     *VersionHistory
+		expectedItem *VersionHistoryItem
+		expectedErr  bool
+	}{
+		{
+			name:        "empty histories",
+			histories:   &VersionHistories{},
+			incoming:    versionHistory1,
+			expectedErr: true,
+		},
+		{
+			name:        "empty incoming history",
+			histories:   histories,
+			incoming:    NewVersionHistory([]byte("branch token incoming"), nil),
+			expectedErr: true,
+		},
+		{
+			name:         "identical",
+			histories:    histories,
+			incoming:     versionHistory2.Duplicate(),
+			expectedItem: NewVersionHistoryItem(11, 12),
+		},
+		{
+			name:      "diverging",
+			histories: histories,
+			incoming: NewVersionHistory([]byte("branch token incoming"), []*VersionHistoryItem{
+				{EventID: 3, Version: 0},
+				{EventID: 5, Version: 4},
+				{EventID: 8, Version: 6},
+				{EventID: 11, Version: 100},
+			}),
+			expectedItem: NewVersionHistoryItem(7, 6),
+		},
+		{
+			name:      "no common prefix",
+			histories: histories,
+			incoming: NewVersionHistory([]byte("branch token incoming"), []*VersionHistoryItem{
+				{EventID: 3, Version: 1},
+			}),
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		item, err := tc.histories.FindLCAItem(tc.incoming)
+		if tc.expectedErr {
+			s.Error(err, tc.name)
+			continue
+		}
+		s.NoError(err, tc.name)
+		s.Equal(tc.expectedItem, item, tc.name)
+	}
+}
+
 func (s *versionHistoriesSuite) TestFindFirstVersionHistoryIndexByItem() {
 	versionHistory1 := NewVersionHistory([]byte("branch token 1"), []*VersionHistoryItem{
 		{EventID: 3, Version: 0},
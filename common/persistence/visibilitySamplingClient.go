@@ -306,6 +306,11 @@ func (p *visibilitySamplingClient) Close() {
 	p.persistence.Close()
 }
 
+// CloseWithContext closes the wrapped persistence, closing is not sampled
+func (p *visibilitySamplingClient) CloseWithContext(ctx context.Context) error {
+	return p.persistence.CloseWithContext(ctx)
+}
+
 func (p *visibilitySamplingClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -57,6 +57,11 @@ func (v *visibilityManagerImpl) Close() {
 	v.persistence.Close()
 }
 
+// CloseWithContext closes the visibility store within the deadline of ctx
+func (v *visibilityManagerImpl) CloseWithContext(ctx context.Context) error {
+	return CloseWithContext(ctx, v.persistence)
+}
+
 func (v *visibilityManagerImpl) GetName() string {
 	return v.persistence.GetName()
 }
@@ -64,6 +64,20 @@ func (v *visibilityManagerWrapper) Close() {
 	}
 }
 
+// CloseWithContext closes both the database and the elasticsearch visibility managers, the first error is returned
+func (v *visibilityManagerWrapper) CloseWithContext(ctx context.Context) error {
+	var err error
+	if v.visibilityManager != nil {
+		err = v.visibilityManager.CloseWithContext(ctx)
+	}
+	if v.esVisibilityManager != nil {
+		if esErr := v.esVisibilityManager.CloseWithContext(ctx); err == nil {
+			err = esErr
+		}
+	}
+	return err
+}
+
 func (v *visibilityManagerWrapper) GetName() string {
 	return "visibilityManagerWrapper"
 }
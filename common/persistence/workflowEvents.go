@@ -0,0 +1,53 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"fmt"
+)
+
+// Validate checks that the events are non-empty and that their IDs are contiguous and strictly increasing,
+// an InvalidPersistenceRequestError is returned otherwise
+func (e *WorkflowEvents) Validate() error {
+	if len(e.Events) == 0 {
+		return &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("workflow events for workflow %v, run %v are empty", e.WorkflowID, e.RunID),
+		}
+	}
+
+	for i, event := range e.Events {
+		if event == nil {
+			return &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("workflow events for workflow %v, run %v contain a nil event at index %v", e.WorkflowID, e.RunID, i),
+			}
+		}
+		if i == 0 {
+			continue
+		}
+		if expectedEventID := e.Events[i-1].EventID + 1; event.EventID != expectedEventID {
+			return &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("workflow events for workflow %v, run %v are not contiguous, expected event ID %v at index %v, got %v",
+					e.WorkflowID, e.RunID, expectedEventID, i, event.EventID),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func TestWorkflowEventsValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		eventIDs []int64
+		valid    bool
+	}{
+		{name: "single event", eventIDs: []int64{5}, valid: true},
+		{name: "contiguous", eventIDs: []int64{5, 6, 7}, valid: true},
+		{name: "empty", eventIDs: nil, valid: false},
+		{name: "gap", eventIDs: []int64{5, 6, 8}, valid: false},
+		{name: "duplicate", eventIDs: []int64{5, 6, 6, 7}, valid: false},
+		{name: "reversed", eventIDs: []int64{7, 6, 5}, valid: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			workflowEvents := &WorkflowEvents{
+				DomainID:   "domain",
+				WorkflowID: "workflow",
+				RunID:      "run",
+			}
+			for _, eventID := range tc.eventIDs {
+				workflowEvents.Events = append(workflowEvents.Events, &types.HistoryEvent{EventID: eventID})
+			}
+
+			err := workflowEvents.Validate()
+			if tc.valid {
+				assert.NoError(t, err)
+				return
+			}
+			assert.IsType(t, &InvalidPersistenceRequestError{}, err)
+		})
+	}
+}
+
+func TestWorkflowEventsValidateNilEvent(t *testing.T) {
+	workflowEvents := &WorkflowEvents{
+		Events: []*types.HistoryEvent{{EventID: 1}, nil},
+	}
+	assert.IsType(t, &InvalidPersistenceRequestError{}, workflowEvents.Validate())
+}
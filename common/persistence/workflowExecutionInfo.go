@@ -22,6 +22,7 @@ package persistence
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/uber/cadence/common/types"
 )
@@ -41,6 +42,64 @@ func (e *WorkflowExecutionInfo) SetLastFirstEventID(id int64) {
 	e.LastFirstEventID = id
 }
 
+// GetDecisionScheduledTimestamp returns the schedule time of the pending decision, or the zero time if there is none
+func (e *WorkflowExecutionInfo) GetDecisionScheduledTimestamp() time.Time {
+	return unixNanoToTime(e.DecisionScheduledTimestamp)
+}
+
+// GetDecisionOriginalScheduledTimestamp returns the schedule time of the first attempt of the pending decision,
+// or the zero time if there is none
+func (e *WorkflowExecutionInfo) GetDecisionOriginalScheduledTimestamp() time.Time {
+	return unixNanoToTime(e.DecisionOriginalScheduledTimestamp)
+}
+
+// GetDecisionStartedTimestamp returns the start time of the pending decision, or the zero time if it is not started
+func (e *WorkflowExecutionInfo) GetDecisionStartedTimestamp() time.Time {
+	return unixNanoToTime(e.DecisionStartedTimestamp)
+}
+
+// IsRunning returns true if the workflow is created or running, i.e. it has not been closed yet
+func (e *WorkflowExecutionInfo) IsRunning() bool {
+	return e.State == WorkflowStateCreated || e.State == WorkflowStateRunning
+}
+
+// IsClosed returns true if the workflow is completed, its close status tells how it was closed
+func (e *WorkflowExecutionInfo) IsClosed() bool {
+	return e.State == WorkflowStateCompleted
+}
+
+// IsZombie returns true if the workflow is a zombie, i.e. it is not the current run of the workflow ID
+// and is only kept to be able to apply replicated events
+func (e *WorkflowExecutionInfo) IsZombie() bool {
+	return e.State == WorkflowStateZombie
+}
+
+// CloseStatusString returns a readable representation of the close status, e.g. COMPLETED,
+// or NONE if the workflow is not closed
+func (e *WorkflowExecutionInfo) CloseStatusString() string {
+	if e.CloseStatus == WorkflowCloseStatusNone {
+		return "NONE"
+	}
+	if _, ok := validWorkflowCloseStatuses[e.CloseStatus]; !ok {
+		return fmt.Sprintf("WorkflowCloseStatus(%d)", e.CloseStatus)
+	}
+	return ToInternalWorkflowExecutionCloseStatus(e.CloseStatus).String()
+}
+
+// Sanitized returns a shallow copy of the execution info that is safe to log, fields which may
+// carry customer data (completion event, memo, search attributes and execution context) are dropped
+func (e *WorkflowExecutionInfo) Sanitized() *WorkflowExecutionInfo {
+	if e == nil {
+		return nil
+	}
+	sanitized := *e
+	sanitized.CompletionEvent = nil
+	sanitized.Memo = nil
+	sanitized.SearchAttributes = nil
+	sanitized.ExecutionContext = nil
+	return &sanitized
+}
+
 // UpdateWorkflowStateCloseStatus update the workflow state
 func (e *WorkflowExecutionInfo) UpdateWorkflowStateCloseStatus(
 	state int,
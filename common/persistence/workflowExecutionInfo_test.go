@@ -0,0 +1,94 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func TestWorkflowExecutionInfoSanitized(t *testing.T) {
+	info := &WorkflowExecutionInfo{
+		DomainID:         "domain",
+		WorkflowID:       "workflow",
+		RunID:            "run",
+		NextEventID:      10,
+		State:            WorkflowStateCompleted,
+		CompletionEvent:  &types.HistoryEvent{EventID: 9},
+		Memo:             map[string][]byte{"key": []byte("memo")},
+		SearchAttributes: map[string][]byte{"key": []byte("attr")},
+		ExecutionContext: []byte("context"),
+	}
+
+	sanitized := info.Sanitized()
+	assert.Nil(t, sanitized.CompletionEvent)
+	assert.Nil(t, sanitized.Memo)
+	assert.Nil(t, sanitized.SearchAttributes)
+	assert.Nil(t, sanitized.ExecutionContext)
+	assert.Equal(t, "domain", sanitized.DomainID)
+	assert.Equal(t, "workflow", sanitized.WorkflowID)
+	assert.Equal(t, "run", sanitized.RunID)
+	assert.Equal(t, int64(10), sanitized.NextEventID)
+	assert.Equal(t, WorkflowStateCompleted, sanitized.State)
+
+	// the original is left untouched
+	assert.NotNil(t, info.CompletionEvent)
+	assert.NotNil(t, info.Memo)
+	assert.NotNil(t, info.SearchAttributes)
+	assert.NotNil(t, info.ExecutionContext)
+
+	var nilInfo *WorkflowExecutionInfo
+	assert.Nil(t, nilInfo.Sanitized())
+}
+
+func TestWorkflowExecutionInfoState(t *testing.T) {
+	testCases := []struct {
+		state     int
+		isRunning bool
+		isClosed  bool
+		isZombie  bool
+	}{
+		{state: WorkflowStateCreated, isRunning: true},
+		{state: WorkflowStateRunning, isRunning: true},
+		{state: WorkflowStateCompleted, isClosed: true},
+		{state: WorkflowStateZombie, isZombie: true},
+		{state: WorkflowStateVoid},
+		{state: WorkflowStateCorrupted},
+	}
+
+	for _, tc := range testCases {
+		info := &WorkflowExecutionInfo{State: tc.state}
+		assert.Equal(t, tc.isRunning, info.IsRunning(), "state %v", tc.state)
+		assert.Equal(t, tc.isClosed, info.IsClosed(), "state %v", tc.state)
+		assert.Equal(t, tc.isZombie, info.IsZombie(), "state %v", tc.state)
+	}
+}
+
+func TestWorkflowExecutionInfoCloseStatusString(t *testing.T) {
+	assert.Equal(t, "NONE", (&WorkflowExecutionInfo{CloseStatus: WorkflowCloseStatusNone}).CloseStatusString())
+	assert.Equal(t, "COMPLETED", (&WorkflowExecutionInfo{CloseStatus: WorkflowCloseStatusCompleted}).CloseStatusString())
+	assert.Equal(t, "CONTINUED_AS_NEW", (&WorkflowExecutionInfo{CloseStatus: WorkflowCloseStatusContinuedAsNew}).CloseStatusString())
+	assert.Equal(t, "TIMED_OUT", (&WorkflowExecutionInfo{CloseStatus: WorkflowCloseStatusTimedOut}).CloseStatusString())
+	assert.Equal(t, "WorkflowCloseStatus(100)", (&WorkflowExecutionInfo{CloseStatus: 100}).CloseStatusString())
+}
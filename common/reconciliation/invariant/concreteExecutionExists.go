@@ -164,7 +164,7 @@ func (c *concreteExecutionExists) validateCurrentRunID(
 	})
 	if err != nil {
 		switch err.(type) {
-		case *types.EntityNotExistsError:
+		case *types.EntityNotExistsError, *persistence.WorkflowExecutionNotExistsError:
 			return nil, &CheckResult{
 				CheckResultType: CheckResultTypeHealthy,
 				InvariantName:   c.Name(),
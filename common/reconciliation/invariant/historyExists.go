@@ -162,10 +162,8 @@ func ExecutionStillExists(
 	if err == nil {
 		return true, nil
 	}
-	switch err.(type) {
-	case *types.EntityNotExistsError:
+	if persistence.IsNotExistsError(err) {
 		return false, nil
-	default:
-		return false, err
 	}
+	return false, err
 }
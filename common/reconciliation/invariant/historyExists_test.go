@@ -71,7 +71,7 @@ func (s *HistoryExistsSuite) TestCheck() {
 			expectedResourcePopulated: false,
 		},
 		{
-			getExecErr:     &types.EntityNotExistsError{},
+			getExecErr:     &persistence.WorkflowExecutionNotExistsError{},
 			getHistoryResp: &persistence.ReadHistoryBranchResponse{},
 			expectedResult: CheckResult{
 				CheckResultType: CheckResultTypeHealthy,
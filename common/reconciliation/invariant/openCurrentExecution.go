@@ -89,7 +89,7 @@ func (o *openCurrentExecution) Check(
 	}
 	if currentExecErr != nil {
 		switch currentExecErr.(type) {
-		case *types.EntityNotExistsError:
+		case *types.EntityNotExistsError, *persistence.WorkflowExecutionNotExistsError:
 			return CheckResult{
 				CheckResultType: CheckResultTypeCorrupted,
 				InvariantName:   o.Name(),
@@ -158,7 +158,7 @@ func ExecutionStillOpen(
 	resp, err := pr.GetWorkflowExecution(ctx, req)
 	if err != nil {
 		switch err.(type) {
-		case *types.EntityNotExistsError:
+		case *types.EntityNotExistsError, *persistence.WorkflowExecutionNotExistsError:
 			return false, nil
 		default:
 			return false, err
@@ -73,19 +73,17 @@ func (h *TimerInvalid) Check(
 	resp, err := h.pr.GetWorkflowExecution(ctx, req)
 
 	if err != nil {
-		switch err.(type) {
-		case *types.EntityNotExistsError:
+		if persistence.IsNotExistsError(err) {
 			return CheckResult{
 				CheckResultType: CheckResultTypeCorrupted,
 				InvariantName:   h.Name(),
 				Info:            "timer scheduled for non existing workflow",
 			}
-		default:
-			return CheckResult{
-				CheckResultType: CheckResultTypeFailed,
-				InvariantName:   h.Name(),
-				Info:            "failed to get workflow for timer",
-			}
+		}
+		return CheckResult{
+			CheckResultType: CheckResultTypeFailed,
+			InvariantName:   h.Name(),
+			Info:            "failed to get workflow for timer",
 		}
 	}
 
@@ -35,7 +35,6 @@ import (
 	"github.com/uber/cadence/common/mocks"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/reconciliation/entity"
-	"github.com/uber/cadence/common/types"
 )
 
 type TimerInvalidTest struct {
@@ -98,7 +97,7 @@ func (ts *TimerInvalidTest) TestCheck() {
 				InfoDetails:     "",
 			},
 			getExecResp: nil,
-			getExecErr:  &types.EntityNotExistsError{},
+			getExecErr:  &persistence.WorkflowExecutionNotExistsError{},
 			entity:      &entity.Timer{},
 		},
 		{
@@ -21,6 +21,7 @@
 package resource
 
 import (
+	"context"
 	"math/rand"
 	"os"
 	"sync/atomic"
@@ -133,6 +134,9 @@ type (
 
 var _ Resource = (*Impl)(nil)
 
+// persistenceCloseTimeout bounds how long stopping a service waits for the persistence clients to close
+const persistenceCloseTimeout = 10 * time.Second
+
 // New create a new resource containing common dependencies
 func New(
 	params *service.BootstrapParams,
@@ -411,8 +415,15 @@ func (h *Impl) Stop() {
 		h.logger.WithTags(tag.Error(err)).Error("failed to stop dispatcher")
 	}
 	h.runtimeMetricsReporter.Stop()
-	h.persistenceBean.Close()
-	h.visibilityMgr.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), persistenceCloseTimeout)
+	defer cancel()
+	if err := h.persistenceBean.CloseWithContext(ctx); err != nil {
+		h.logger.WithTags(tag.Error(err)).Error("failed to close persistence")
+	}
+	if err := persistence.CloseWithContext(ctx, h.visibilityMgr); err != nil {
+		h.logger.WithTags(tag.Error(err)).Error("failed to close visibility manager")
+	}
 }
 
 // GetServiceName return service name
@@ -5198,6 +5198,26 @@ func ToFailureDetails(failure *apiv1.Failure) []byte {
 	return failure.Details
 }
 
+func FromFailureType(t *types.Failure) *apiv1.Failure {
+	if t == nil {
+		return nil
+	}
+	return &apiv1.Failure{
+		Reason:  t.Reason,
+		Details: t.Details,
+	}
+}
+
+func ToFailureType(t *apiv1.Failure) *types.Failure {
+	if t == nil {
+		return nil
+	}
+	return &types.Failure{
+		Reason:  t.Reason,
+		Details: t.Details,
+	}
+}
+
 func FromHistoryEvent(e *types.HistoryEvent) *apiv1.HistoryEvent {
 	if e == nil {
 		return nil
@@ -910,6 +910,17 @@ func TestFailure(t *testing.T) {
 	assert.Equal(t, testdata.FailureReason, *ToFailureReason(failure))
 	assert.Equal(t, testdata.FailureDetails, ToFailureDetails(failure))
 }
+func TestFailureType(t *testing.T) {
+	for _, item := range []*types.Failure{
+		nil,
+		{},
+		{Reason: testdata.FailureReason, Details: testdata.FailureDetails},
+	} {
+		assert.Equal(t, item, ToFailureType(FromFailureType(item)))
+	}
+	assert.Equal(t, FromFailure(&testdata.FailureReason, testdata.FailureDetails),
+		FromFailureType(&types.Failure{Reason: testdata.FailureReason, Details: testdata.FailureDetails}))
+}
 func TestHistoryEvent(t *testing.T) {
 	for _, item := range []*types.HistoryEvent{
 		nil,
@@ -1046,7 +1046,7 @@ func (c *contextImpl) getWorkflowExecutionWithRetry(
 	switch err.(type) {
 	case nil:
 		return resp, nil
-	case *types.EntityNotExistsError:
+	case *types.EntityNotExistsError, *persistence.WorkflowExecutionNotExistsError:
 		// it is possible that workflow does not exists
 		return nil, err
 	default:
@@ -1242,10 +1242,12 @@ func (c *contextImpl) isPersistenceTimeoutError(
 	case *types.WorkflowExecutionAlreadyStartedError,
 		*persistence.WorkflowExecutionAlreadyStartedError,
 		*persistence.CurrentWorkflowConditionFailedError,
+		*persistence.CurrentWorkflowStillRunningError,
 		*persistence.ConditionFailedError,
 		*types.ServiceBusyError,
 		*types.LimitExceededError,
-		*persistence.ShardOwnershipLostError:
+		*persistence.ShardOwnershipLostError,
+		*persistence.ShardRangeIDMismatchError:
 		return false
 	case *persistence.TimeoutError:
 		return true
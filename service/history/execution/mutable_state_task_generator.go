@@ -218,13 +218,16 @@ func (r *mutableStateTaskGeneratorImpl) GenerateDelayedDecisionTasks(
 		}
 	}
 
-	r.mutableState.AddTimerTasks(&persistence.WorkflowBackoffTimerTask{
-		// TaskID is set by shard
-		// TODO EventID seems not used at all
-		VisibilityTimestamp: executionTimestamp,
-		TimeoutType:         firstDecisionDelayType,
-		Version:             startVersion,
-	})
+	// TaskID is set by shard
+	backoffTask, err := persistence.NewWorkflowBackoffTimerTask(
+		executionTimestamp,
+		firstDecisionDelayType,
+		startVersion,
+	)
+	if err != nil {
+		return err
+	}
+	r.mutableState.AddTimerTasks(backoffTask)
 
 	return nil
 }
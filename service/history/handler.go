@@ -1836,6 +1836,14 @@ func (h *handlerImpl) NotifyFailoverMarkers(
 	return nil
 }
 
+func (h *handlerImpl) createShardOwnershipLostError(shardID int) error {
+	info, err := h.GetHistoryServiceResolver().Lookup(strconv.Itoa(shardID))
+	if err == nil {
+		return shard.CreateShardOwnershipLostError(h.GetHostInfo().GetAddress(), info.GetAddress())
+	}
+	return shard.CreateShardOwnershipLostError(h.GetHostInfo().GetAddress(), "")
+}
+
 // convertError is a helper method to convert ShardOwnershipLostError from persistence layer returned by various
 // HistoryEngine API calls to ShardOwnershipLost error return by HistoryService for client to be redirected to the
 // correct shard.
@@ -1843,20 +1851,25 @@ func (h *handlerImpl) convertError(err error) error {
 	switch err.(type) {
 	case *persistence.ShardOwnershipLostError:
 		shardID := err.(*persistence.ShardOwnershipLostError).ShardID
-		info, err := h.GetHistoryServiceResolver().Lookup(strconv.Itoa(shardID))
-		if err == nil {
-			return shard.CreateShardOwnershipLostError(h.GetHostInfo().GetAddress(), info.GetAddress())
-		}
-		return shard.CreateShardOwnershipLostError(h.GetHostInfo().GetAddress(), "")
+		return h.createShardOwnershipLostError(shardID)
+	case *persistence.ShardRangeIDMismatchError:
+		shardID := err.(*persistence.ShardRangeIDMismatchError).ShardID
+		return h.createShardOwnershipLostError(shardID)
 	case *persistence.WorkflowExecutionAlreadyStartedError:
 		err := err.(*persistence.WorkflowExecutionAlreadyStartedError)
 		return &types.InternalServiceError{Message: err.Msg}
 	case *persistence.CurrentWorkflowConditionFailedError:
 		err := err.(*persistence.CurrentWorkflowConditionFailedError)
 		return &types.InternalServiceError{Message: err.Msg}
+	case *persistence.CurrentWorkflowStillRunningError:
+		err := err.(*persistence.CurrentWorkflowStillRunningError)
+		return &types.InternalServiceError{Message: err.Msg}
 	case *persistence.TransactionSizeLimitError:
 		err := err.(*persistence.TransactionSizeLimitError)
 		return &types.BadRequestError{Message: err.Msg}
+	case *persistence.WorkflowExecutionNotExistsError:
+		err := err.(*persistence.WorkflowExecutionNotExistsError)
+		return &types.EntityNotExistsError{Message: err.Msg}
 	}
 
 	return err
@@ -906,7 +906,7 @@ func (e *historyEngineImpl) PollMutableState(
 
 func (e *historyEngineImpl) updateEntityNotExistsErrorOnPassiveCluster(err error, domainID string) error {
 	switch err.(type) {
-	case *types.EntityNotExistsError:
+	case *types.EntityNotExistsError, *persistence.WorkflowExecutionNotExistsError:
 		domainCache, domainCacheErr := e.shard.GetDomainCache().GetDomainByID(domainID)
 		if domainCacheErr != nil {
 			return err // if could not access domain cache simply return original error
@@ -2215,7 +2215,7 @@ func (e *historyEngineImpl) SignalWithStartWorkflowExecution(
 			// workflow not exist, will create workflow then signal
 			mutableState, err1 := wfContext.LoadWorkflowExecution(ctx)
 			if err1 != nil {
-				if _, ok := err1.(*types.EntityNotExistsError); ok {
+				if persistence.IsNotExistsError(err1) {
 					break
 				}
 				return nil, err1
@@ -2279,7 +2279,7 @@ func (e *historyEngineImpl) SignalWithStartWorkflowExecution(
 			return nil, workflow.ErrMaxAttemptsExceeded
 		}
 	} else {
-		if _, ok := err0.(*types.EntityNotExistsError); !ok {
+		if !persistence.IsNotExistsError(err0) {
 			return nil, err0
 		}
 		// workflow not exist, will create workflow then signal
@@ -101,7 +101,7 @@ func (r *activityReplicatorImpl) SyncActivity(
 
 	mutableState, err := context.LoadWorkflowExecution(ctx)
 	if err != nil {
-		if _, ok := err.(*types.EntityNotExistsError); !ok {
+		if !persistence.IsNotExistsError(err) {
 			return err
 		}
 
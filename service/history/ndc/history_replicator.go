@@ -270,7 +270,7 @@ func (r *historyReplicatorImpl) applyEvents(
 			}
 			return r.applyNonStartEventsToNoneCurrentBranch(ctx, context, mutableState, branchIndex, releaseFn, task)
 
-		case *types.EntityNotExistsError:
+		case *types.EntityNotExistsError, *persistence.WorkflowExecutionNotExistsError:
 			// mutable state not created, check if is workflow reset
 			mutableState, err := r.applyNonStartEventsMissingMutableState(ctx, context, task)
 			if err != nil {
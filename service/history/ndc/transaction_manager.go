@@ -400,7 +400,7 @@ func (r *transactionManagerImpl) checkWorkflowExists(
 	switch err.(type) {
 	case nil:
 		return true, nil
-	case *types.EntityNotExistsError:
+	case *types.EntityNotExistsError, *persistence.WorkflowExecutionNotExistsError:
 		return false, nil
 	default:
 		return false, err
@@ -424,7 +424,7 @@ func (r *transactionManagerImpl) getCurrentWorkflowRunID(
 	switch err.(type) {
 	case nil:
 		return resp.RunID, nil
-	case *types.EntityNotExistsError:
+	case *types.EntityNotExistsError, *persistence.WorkflowExecutionNotExistsError:
 		return "", nil
 	default:
 		return "", err
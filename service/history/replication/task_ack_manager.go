@@ -301,7 +301,7 @@ func (t *taskAckManagerImpl) processReplication(
 		release(nil)
 
 		return action(targetActivityInfo, targetVersionHistory)
-	case *types.EntityNotExistsError:
+	case *types.EntityNotExistsError, *persistence.WorkflowExecutionNotExistsError:
 		return nil, nil
 	default:
 		return nil, err
@@ -598,6 +598,7 @@ func (s *contextImpl) CreateWorkflowExecution(
 	if err != nil {
 		return nil, err
 	}
+	request.WorkflowSizeLimits = s.getWorkflowSizeLimits(domainEntry.GetInfo().Name)
 
 	s.Lock()
 	defer s.Unlock()
@@ -626,11 +627,12 @@ Create_Loop:
 			case *types.WorkflowExecutionAlreadyStartedError,
 				*persistence.WorkflowExecutionAlreadyStartedError,
 				*persistence.CurrentWorkflowConditionFailedError,
+				*persistence.CurrentWorkflowStillRunningError,
 				*types.ServiceBusyError,
 				*persistence.TimeoutError,
 				*types.LimitExceededError:
 				// No special handling required for these errors
-			case *persistence.ShardOwnershipLostError:
+			case *persistence.ShardOwnershipLostError, *persistence.ShardRangeIDMismatchError:
 				{
 					// RangeID might have been renewed by the same host while this update was in flight
 					// Retry the operation if we still have the shard ownership
@@ -680,6 +682,16 @@ func (s *contextImpl) getDefaultEncoding(domainName string) common.EncodingType
 	return common.EncodingType(s.config.EventEncodingType(domainName))
 }
 
+// getWorkflowSizeLimits returns the budgets the execution manager enforces on the workflows written for the domain,
+// they are the limits the frontend applies to the memo, search attributes and signals of incoming requests
+func (s *contextImpl) getWorkflowSizeLimits(domainName string) persistence.WorkflowSizeLimits {
+	return persistence.WorkflowSizeLimits{
+		MaxMemoSize:             s.config.BlobSizeLimitError(domainName),
+		MaxSearchAttributesSize: s.config.SearchAttributesTotalSizeLimit(domainName),
+		MaxSignalInputSize:      s.config.BlobSizeLimitError(domainName),
+	}
+}
+
 func (s *contextImpl) UpdateWorkflowExecution(
 	ctx context.Context,
 	request *persistence.UpdateWorkflowExecutionRequest,
@@ -701,6 +713,7 @@ func (s *contextImpl) UpdateWorkflowExecution(
 		return nil, err
 	}
 	request.Encoding = s.getDefaultEncoding(domainEntry.GetInfo().Name)
+	request.WorkflowSizeLimits = s.getWorkflowSizeLimits(domainEntry.GetInfo().Name)
 
 	s.Lock()
 	defer s.Unlock()
@@ -742,7 +755,7 @@ Update_Loop:
 				*types.ServiceBusyError,
 				*types.LimitExceededError:
 				// No special handling required for these errors
-			case *persistence.ShardOwnershipLostError:
+			case *persistence.ShardOwnershipLostError, *persistence.ShardRangeIDMismatchError:
 				{
 					// RangeID might have been renewed by the same host while this update was in flight
 					// Retry the operation if we still have the shard ownership
@@ -809,6 +822,7 @@ func (s *contextImpl) ConflictResolveWorkflowExecution(
 		return err
 	}
 	request.Encoding = s.getDefaultEncoding(domainEntry.GetInfo().Name)
+	request.WorkflowSizeLimits = s.getWorkflowSizeLimits(domainEntry.GetInfo().Name)
 
 	s.Lock()
 	defer s.Unlock()
@@ -861,7 +875,7 @@ Conflict_Resolve_Loop:
 				*types.ServiceBusyError,
 				*types.LimitExceededError:
 				// No special handling required for these errors
-			case *persistence.ShardOwnershipLostError:
+			case *persistence.ShardOwnershipLostError, *persistence.ShardRangeIDMismatchError:
 				{
 					// RangeID might have been renewed by the same host while this update was in flight
 					// Retry the operation if we still have the shard ownership
@@ -1129,7 +1143,7 @@ func (s *contextImpl) persistShardInfoLocked(
 
 	if err != nil {
 		// Shard is stolen, trigger history engine shutdown
-		if _, ok := err.(*persistence.ShardOwnershipLostError); ok {
+		if persistence.IsShardOwnershipLostError(err) {
 			s.logger.Warn(
 				"Closing shard: updateShardInfoLocked failed due to stolen shard.",
 				tag.ShardID(s.GetShardID()),
@@ -1358,7 +1372,7 @@ Retry_Loop:
 		switch err.(type) {
 		case nil:
 			break Retry_Loop
-		case *persistence.ShardOwnershipLostError:
+		case *persistence.ShardOwnershipLostError, *persistence.ShardRangeIDMismatchError:
 			// do not retry on ShardOwnershipLostError
 			s.logger.Warn(
 				"Closing shard: ReplicateFailoverMarkers failed due to stolen shard.",
@@ -1586,50 +1600,5 @@ func acquireShard(
 }
 
 func copyShardInfo(shardInfo *persistence.ShardInfo) *persistence.ShardInfo {
-	transferFailoverLevels := map[string]persistence.TransferFailoverLevel{}
-	for k, v := range shardInfo.TransferFailoverLevels {
-		transferFailoverLevels[k] = v
-	}
-	timerFailoverLevels := map[string]persistence.TimerFailoverLevel{}
-	for k, v := range shardInfo.TimerFailoverLevels {
-		timerFailoverLevels[k] = v
-	}
-	clusterTransferAckLevel := make(map[string]int64)
-	for k, v := range shardInfo.ClusterTransferAckLevel {
-		clusterTransferAckLevel[k] = v
-	}
-	clusterTimerAckLevel := make(map[string]time.Time)
-	for k, v := range shardInfo.ClusterTimerAckLevel {
-		clusterTimerAckLevel[k] = v
-	}
-	clusterReplicationLevel := make(map[string]int64)
-	for k, v := range shardInfo.ClusterReplicationLevel {
-		clusterReplicationLevel[k] = v
-	}
-	replicationDLQAckLevel := make(map[string]int64)
-	for k, v := range shardInfo.ReplicationDLQAckLevel {
-		replicationDLQAckLevel[k] = v
-	}
-	shardInfoCopy := &persistence.ShardInfo{
-		ShardID:                       shardInfo.ShardID,
-		Owner:                         shardInfo.Owner,
-		RangeID:                       shardInfo.RangeID,
-		StolenSinceRenew:              shardInfo.StolenSinceRenew,
-		ReplicationAckLevel:           shardInfo.ReplicationAckLevel,
-		TransferAckLevel:              shardInfo.TransferAckLevel,
-		TimerAckLevel:                 shardInfo.TimerAckLevel,
-		TransferFailoverLevels:        transferFailoverLevels,
-		TimerFailoverLevels:           timerFailoverLevels,
-		ClusterTransferAckLevel:       clusterTransferAckLevel,
-		ClusterTimerAckLevel:          clusterTimerAckLevel,
-		TransferProcessingQueueStates: shardInfo.TransferProcessingQueueStates,
-		TimerProcessingQueueStates:    shardInfo.TimerProcessingQueueStates,
-		DomainNotificationVersion:     shardInfo.DomainNotificationVersion,
-		ClusterReplicationLevel:       clusterReplicationLevel,
-		ReplicationDLQAckLevel:        replicationDLQAckLevel,
-		PendingFailoverMarkers:        shardInfo.PendingFailoverMarkers,
-		UpdatedAt:                     shardInfo.UpdatedAt,
-	}
-
-	return shardInfoCopy
+	return shardInfo.Copy()
 }
@@ -34,6 +34,8 @@ import (
 	"github.com/stretchr/testify/suite"
 	"github.com/uber-go/tally"
 
+	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/cluster"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/loggerimpl"
 	"github.com/uber/cadence/common/metrics"
@@ -142,3 +144,45 @@ func (s *contextTestSuite) TestReplicateFailoverMarkersSuccess() {
 	err := s.context.ReplicateFailoverMarkers(context.Background(), markers)
 	s.NoError(err)
 }
+
+func (s *contextTestSuite) TestReplicateFailoverMarkersRangeIDMovedByAnotherHost() {
+	closed := make(chan int, 1)
+	s.context.closeCallback = func(shardID int, _ *historyShardsItem) {
+		closed <- shardID
+	}
+	mismatchErr := &persistence.ShardRangeIDMismatchError{
+		ShardID:         s.context.shardID,
+		ExpectedRangeID: s.context.getRangeID(),
+		ActualRangeID:   s.context.getRangeID() + 1,
+	}
+	s.mockResource.ExecutionMgr.On("CreateFailoverMarkerTasks", mock.Anything, mock.Anything).Once().Return(mismatchErr)
+
+	markers := make([]*persistence.FailoverMarkerTask, 0)
+	err := s.context.ReplicateFailoverMarkers(context.Background(), markers)
+	s.Equal(mismatchErr, err)
+	s.Equal(s.context.shardID, <-closed)
+}
+
+func (s *contextTestSuite) TestUpdateWorkflowExecutionSizeLimits() {
+	domainEntry := cache.NewLocalDomainCacheEntryForTest(
+		&persistence.DomainInfo{ID: "domain-id", Name: "domain"}, &persistence.DomainConfig{Retention: 1}, "", nil,
+	)
+	s.mockResource.DomainCache.EXPECT().GetDomainByID("domain-id").Return(domainEntry, nil).Times(1)
+	s.mockResource.ClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockResource.ExecutionMgr.On("UpdateWorkflowExecution", mock.Anything, mock.MatchedBy(
+		func(request *persistence.UpdateWorkflowExecutionRequest) bool {
+			return request.WorkflowSizeLimits == persistence.WorkflowSizeLimits{
+				MaxMemoSize:             s.context.config.BlobSizeLimitError("domain"),
+				MaxSearchAttributesSize: s.context.config.SearchAttributesTotalSizeLimit("domain"),
+				MaxSignalInputSize:      s.context.config.BlobSizeLimitError("domain"),
+			}
+		},
+	)).Once().Return(&persistence.UpdateWorkflowExecutionResponse{}, nil)
+
+	_, err := s.context.UpdateWorkflowExecution(context.Background(), &persistence.UpdateWorkflowExecutionRequest{
+		UpdateWorkflowMutation: persistence.WorkflowMutation{
+			ExecutionInfo: &persistence.WorkflowExecutionInfo{DomainID: "domain-id", WorkflowID: "workflow"},
+		},
+	})
+	s.NoError(err)
+}
@@ -503,10 +503,11 @@ func (i *historyShardsItem) logInvalidStatus() string {
 	return msg
 }
 
-// IsShardOwnershiptLostError checks if a given error is shard ownership lost error
+// IsShardOwnershiptLostError checks if a given error is shard ownership lost error,
+// a range ID mismatch means the shard has been acquired by another host as well
 func IsShardOwnershiptLostError(err error) bool {
 	switch err.(type) {
-	case *persistence.ShardOwnershipLostError:
+	case *persistence.ShardOwnershipLostError, *persistence.ShardRangeIDMismatchError:
 		return true
 	}
 
@@ -559,6 +559,13 @@ func (s *controllerSuite) TestShardControllerClosed() {
 	workerWG.Wait()
 }
 
+func (s *controllerSuite) TestIsShardOwnershipLostError() {
+	s.True(IsShardOwnershiptLostError(&persistence.ShardOwnershipLostError{ShardID: 1}))
+	s.True(IsShardOwnershiptLostError(&persistence.ShardRangeIDMismatchError{ShardID: 1, ExpectedRangeID: 5, ActualRangeID: 6}))
+	s.False(IsShardOwnershiptLostError(&persistence.ConditionFailedError{}))
+	s.False(IsShardOwnershiptLostError(errors.New("some error")))
+}
+
 func (s *controllerSuite) setupMocksForAcquireShard(shardID int, mockEngine *engine.MockEngine, currentRangeID,
 	newRangeID int64) {
 
@@ -236,7 +236,7 @@ func (t *taskImpl) HandleErr(
 
 	t.logEvent("Handling task processing error", err)
 
-	if _, ok := err.(*types.EntityNotExistsError); ok {
+	if persistence.IsNotExistsError(err) {
 		return nil
 	}
 
@@ -291,7 +291,8 @@ func (t *taskImpl) HandleErr(
 
 	t.scope.IncCounter(metrics.TaskFailuresPerDomain)
 
-	if _, ok := err.(*persistence.CurrentWorkflowConditionFailedError); ok {
+	switch err.(type) {
+	case *persistence.CurrentWorkflowConditionFailedError, *persistence.CurrentWorkflowStillRunningError:
 		t.logger.Error("More than 2 workflow are running.", tag.Error(err), tag.LifeCycleProcessingFailed)
 		return nil
 	}
@@ -226,7 +226,7 @@ func loadMutableStateForTimerTask(
 ) (execution.MutableState, error) {
 	msBuilder, err := wfContext.LoadWorkflowExecution(ctx)
 	if err != nil {
-		if _, ok := err.(*types.EntityNotExistsError); ok {
+		if persistence.IsNotExistsError(err) {
 			// this could happen if this is a duplicate processing of the task, and the execution has already completed.
 			return nil, nil
 		}
@@ -279,7 +279,7 @@ func loadMutableStateForTransferTask(
 
 	msBuilder, err := wfContext.LoadWorkflowExecution(ctx)
 	if err != nil {
-		if _, ok := err.(*types.EntityNotExistsError); ok {
+		if persistence.IsNotExistsError(err) {
 			// this could happen if this is a duplicate processing of the task, and the execution has already completed.
 			return nil, nil
 		}
@@ -410,7 +410,7 @@ func (t *transferActiveTaskExecutor) processCancelExecution(
 	if task.DomainID == task.TargetDomainID && task.WorkflowID == task.TargetWorkflowID {
 		// it does not matter if the run ID is a mismatch
 		err = t.requestCancelExternalExecutionFailed(ctx, task, wfContext, targetDomainName, task.TargetWorkflowID, task.TargetRunID)
-		if _, ok := err.(*types.EntityNotExistsError); ok {
+		if persistence.IsNotExistsError(err) {
 			// this could happen if this is a duplicate processing of the task, and the execution has already completed.
 			return nil
 		}
@@ -1033,7 +1033,7 @@ func (t *transferActiveTaskExecutor) requestCancelExternalExecutionCompleted(
 			return err
 		})
 
-	if _, ok := err.(*types.EntityNotExistsError); ok {
+	if persistence.IsNotExistsError(err) {
 		// this could happen if this is a duplicate processing of the task,
 		// or the execution has already completed.
 		return nil
@@ -1073,7 +1073,7 @@ func (t *transferActiveTaskExecutor) signalExternalExecutionCompleted(
 			return err
 		})
 
-	if _, ok := err.(*types.EntityNotExistsError); ok {
+	if persistence.IsNotExistsError(err) {
 		// this could happen if this is a duplicate processing of the task,
 		// or the execution has already completed.
 		return nil
@@ -1113,7 +1113,7 @@ func (t *transferActiveTaskExecutor) requestCancelExternalExecutionFailed(
 			return err
 		})
 
-	if _, ok := err.(*types.EntityNotExistsError); ok {
+	if persistence.IsNotExistsError(err) {
 		// this could happen if this is a duplicate processing of the task,
 		// or the execution has already completed.
 		return nil
@@ -1155,7 +1155,7 @@ func (t *transferActiveTaskExecutor) signalExternalExecutionFailed(
 			return err
 		})
 
-	if _, ok := err.(*types.EntityNotExistsError); ok {
+	if persistence.IsNotExistsError(err) {
 		// this could happen if this is a duplicate processing of the task,
 		// or the execution has already completed.
 		return nil
@@ -368,6 +368,5 @@ func copySearchAttributes(
 }
 
 func isWorkflowNotExistError(err error) bool {
-	_, ok := err.(*types.EntityNotExistsError)
-	return ok
+	return persistence.IsNotExistsError(err)
 }
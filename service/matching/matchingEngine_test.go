@@ -1681,6 +1681,10 @@ func (m *testTaskManager) Close() {
 	return
 }
 
+func (m *testTaskManager) CloseWithContext(ctx context.Context) error {
+	return nil
+}
+
 func (m *testTaskManager) getTaskListManager(id *taskListID) *testTaskListManager {
 	m.Lock()
 	defer m.Unlock()
@@ -1816,6 +1820,13 @@ func (m *testTaskManager) ListTaskList(
 	return nil, fmt.Errorf("unsupported operation")
 }
 
+func (m *testTaskManager) ListTaskListByDomain(
+	_ context.Context,
+	request *persistence.ListTaskListByDomainRequest,
+) (*persistence.ListTaskListResponse, error) {
+	return nil, fmt.Errorf("unsupported operation")
+}
+
 // DeleteTaskList provides a mock function with given fields: ctx, request
 func (m *testTaskManager) DeleteTaskList(
 	_ context.Context,
@@ -1912,6 +1923,12 @@ func (m *testTaskManager) GetTasks(
 	}, nil
 }
 
+func (m *testTaskManager) GetTasksIterator(
+	request *persistence.GetTasksRequest,
+) persistence.TaskIterator {
+	return persistence.NewTaskIterator(m, request)
+}
+
 func (m *testTaskManager) GetOrphanTasks(_ context.Context, request *persistence.GetOrphanTasksRequest) (*persistence.GetOrphanTasksResponse, error) {
 	return &persistence.GetOrphanTasksResponse{}, nil
 }
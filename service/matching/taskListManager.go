@@ -492,10 +492,13 @@ func (c *taskListManagerImpl) executeWithRetry(
 		if _, ok := err.(*persistence.ConditionFailedError); ok {
 			return false
 		}
+		if persistence.IsTaskListNotOwnedError(err) {
+			return false
+		}
 		return persistence.IsTransientError(err)
 	})
 
-	if _, ok := err.(*persistence.ConditionFailedError); ok {
+	if _, ok := err.(*persistence.ConditionFailedError); ok || persistence.IsTaskListNotOwnedError(err) {
 		c.metricScope().IncCounter(metrics.ConditionFailedErrorPerTaskListCounter)
 		c.logger.Debug("Stopping task list due to persistence condition failure.", tag.Error(err))
 		c.Stop()
@@ -176,7 +176,7 @@ writerLoop:
 				switch err.(type) {
 				case nil:
 					// Do nothing
-				case *persistence.ConditionFailedError:
+				case *persistence.ConditionFailedError, *persistence.TaskListNotOwnedError:
 					// Stop and reload task list manager
 					w.tlMgr.Stop()
 				default:
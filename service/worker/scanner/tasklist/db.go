@@ -46,12 +46,13 @@ func (s *Scavenger) completeTasks(info *p.TaskListInfo, taskID int64, limit int)
 	return n, err
 }
 
-func (s *Scavenger) getOrphanTasks(limit int) (*p.GetOrphanTasksResponse, error) {
+func (s *Scavenger) getOrphanTasks(limit int, pageToken []byte) (*p.GetOrphanTasksResponse, error) {
 	var tasks *p.GetOrphanTasksResponse
 	var err error
 	err = s.retryForever(func() error {
 		tasks, err = s.db.GetOrphanTasks(s.ctx, &p.GetOrphanTasksRequest{
-			Limit: limit,
+			Limit:         limit,
+			NextPageToken: pageToken,
 		})
 		return err
 	})
@@ -61,9 +62,15 @@ func (s *Scavenger) getOrphanTasks(limit int) (*p.GetOrphanTasksResponse, error)
 func (s *Scavenger) completeTask(info *p.TaskListInfo, taskid int64) error {
 	var err error
 	err = s.retryForever(func() error {
+		// expired tasks are deleted regardless of which host owns the task list,
+		// so the request is not fenced by the task list RangeID
 		err = s.db.CompleteTask(s.ctx, &p.CompleteTaskRequest{
-			TaskList: info,
-			TaskID:   taskid,
+			TaskList: &p.TaskListInfo{
+				DomainID: info.DomainID,
+				Name:     info.Name,
+				TaskType: info.TaskType,
+			},
+			TaskID: taskid,
 		})
 		return err
 	})
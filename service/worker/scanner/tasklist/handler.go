@@ -140,17 +140,19 @@ func (s *Scavenger) isTaskExpired(t *p.TaskInfo) bool {
 	return t.Expiry.After(epochStartTime) && time.Now().After(t.Expiry)
 }
 
-func (s *Scavenger) completeOrphanTasksHandler() handlerStatus {
+// completeOrphanTasksHandler deletes one page of orphan tasks starting at pageToken,
+// and returns the token to resume from when the handler is deferred
+func (s *Scavenger) completeOrphanTasksHandler(pageToken []byte) (handlerStatus, []byte) {
 	var nDeleted int
 	batchSize := s.getOrphanTasksPageSizeFn()
-	resp, err := s.getOrphanTasks(batchSize)
+	resp, err := s.getOrphanTasks(batchSize, pageToken)
 	if err == p.ErrPersistenceLimitExceeded {
 		s.logger.Info("scavenger.completeOrphanTasksHandler query was ratelimited; will retry")
-		return handlerStatusDefer
+		return handlerStatusDefer, pageToken
 	}
 	if err != nil {
 		s.logger.Error("scavenger.completeOrphanTasksHandler error getting orphan tasks")
-		return handlerStatusErr
+		return handlerStatusErr, pageToken
 	}
 	for _, taskKey := range resp.Tasks {
 		err = s.completeTask(&p.TaskListInfo{
@@ -160,19 +162,19 @@ func (s *Scavenger) completeOrphanTasksHandler() handlerStatus {
 		}, taskKey.TaskID)
 		if err == p.ErrPersistenceLimitExceeded {
 			s.logger.Info("scavenger.completeOrphanTasksHandler query was ratelimited; will retry")
-			return handlerStatusDefer
+			return handlerStatusDefer, pageToken
 		}
 		if err != nil {
 			s.logger.Error("scavenger.completeOrphanTasksHandler error getting orphan tasks")
-			return handlerStatusErr
+			return handlerStatusErr, pageToken
 		}
 		nDeleted++
 		atomic.AddInt64(&s.stats.task.nDeleted, 1)
 		atomic.AddInt64(&s.stats.task.nProcessed, 1)
 	}
 	s.logger.Info("scavenger.completeOrphanTasksHandler deleted.", tag.NumberDeleted(nDeleted))
-	if len(resp.Tasks) < batchSize {
-		return handlerStatusDone
+	if len(resp.Tasks) < batchSize || len(resp.NextPageToken) == 0 {
+		return handlerStatusDone, nil
 	}
-	return handlerStatusDefer
+	return handlerStatusDefer, resp.NextPageToken
 }
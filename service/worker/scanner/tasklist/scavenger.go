@@ -74,7 +74,8 @@ type (
 	// orphanExecutorTask is a runnable task that processes a limited block of
 	// orphans
 	orphanExecutorTask struct {
-		scvg *Scavenger
+		scvg      *Scavenger
+		pageToken []byte
 	}
 )
 
@@ -232,5 +233,7 @@ func (t *executorTask) Run() executor.TaskStatus {
 }
 
 func (t *orphanExecutorTask) Run() executor.TaskStatus {
-	return t.scvg.completeOrphanTasksHandler()
+	status, pageToken := t.scvg.completeOrphanTasksHandler(t.pageToken)
+	t.pageToken = pageToken
+	return status
 }
@@ -447,7 +447,7 @@ func AdminDescribeShard(c *cli.Context) {
 	defer cancel()
 	client, session := connectToCassandra(c)
 	shardStore := cassp.NewShardPersistenceFromSession(client, session, "current-cluster", loggerimpl.NewNopLogger())
-	shardManager := persistence.NewShardManager(shardStore)
+	shardManager := persistence.NewShardManager(shardStore, loggerimpl.NewNopLogger(), nil, nil)
 
 	getShardReq := &persistence.GetShardRequest{ShardID: sid}
 	shard, err := shardManager.GetShard(ctx, getShardReq)
@@ -467,7 +467,7 @@ func AdminSetShardRangeID(c *cli.Context) {
 	defer cancel()
 	client, session := connectToCassandra(c)
 	shardStore := cassp.NewShardPersistenceFromSession(client, session, "current-cluster", loggerimpl.NewNopLogger())
-	shardManager := persistence.NewShardManager(shardStore)
+	shardManager := persistence.NewShardManager(shardStore, loggerimpl.NewNopLogger(), nil, nil)
 
 	getShardResp, err := shardManager.GetShard(ctx, &persistence.GetShardRequest{ShardID: sid})
 	if err != nil {